@@ -0,0 +1,138 @@
+// Command migrate-money moves prices and totals stored as decimal baht into the
+// integer minor-unit *_amount and *_currency columns money.Money maps to, then
+// drops the old columns. It runs in a single transaction and skips columns it
+// has already moved, so it is safe to run again. Run it before the first start
+// of a server that stores money.Money, so auto-migration never sees the old
+// columns.
+//
+//	go run ./cmd/migrate-money .env
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/phetployst/art-toys-store/config"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// moneyColumn is a decimal column From of Table that becomes the money.Money
+// columns To_amount and To_currency.
+type moneyColumn struct {
+	Table string
+	From  string
+	To    string
+}
+
+var moneyColumns = []moneyColumn{
+	{"products", "price", "price"},
+	{"products", "sale_price", "sale_price"},
+	{"product_variants", "price", "price"},
+	{"price_changes", "price", "price"},
+	{"price_changes", "sale_price", "sale_price"},
+	{"scheduled_prices", "price", "price"},
+	{"pre_order_offers", "deposit_amount", "deposit"},
+	{"pre_order_lines", "unit_price", "unit_price"},
+	{"pre_order_lines", "deposit_paid", "deposit_paid"},
+	{"pre_order_lines", "balance_due", "balance_due"},
+	{"cart_items", "price", "price"},
+	{"orders", "total_amount", "total"},
+	{"order_items", "total_price", "total_price"},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("Error: .env path is required")
+	}
+
+	configProvider := config.ConfigProvider{
+		Getter: &config.OsEnvGetter{},
+		Loader: &config.GodotenvLoader{},
+	}
+
+	if err := configProvider.LoadEnvFile(os.Args[1]); err != nil {
+		log.Fatalf("Failed to load .env file from path %s: %v", os.Args[1], err)
+	}
+
+	config, err := configProvider.GetConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(config.Server.DBConnectionString), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	moved := 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, column := range moneyColumns {
+			ok, err := migrateColumn(tx, column)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", column.Table, column.From, err)
+			}
+			if ok {
+				fmt.Printf("moved %s.%s to %s_amount\n", column.Table, column.From, column.To)
+				moved++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to migrate money columns: %v", err)
+	}
+
+	fmt.Printf("%d column(s) moved to minor units\n", moved)
+}
+
+// migrateColumn moves one decimal column into minor units. It reports false
+// when the table has no decimal column by that name, either because the column
+// was moved already or because the table does not exist. Amounts are rounded
+// half away from zero, as the money package rounds, and taken to be in the
+// store's default currency.
+func migrateColumn(tx *gorm.DB, column moneyColumn) (bool, error) {
+	var dataType string
+	if err := tx.Raw(
+		"SELECT data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?",
+		column.Table, column.From,
+	).Scan(&dataType).Error; err != nil {
+		return false, err
+	}
+	if dataType != "numeric" && dataType != "double precision" && dataType != "real" {
+		return false, nil
+	}
+
+	from := column.From
+	amount, currency := column.To+"_amount", column.To+"_currency"
+
+	// orders.total_amount and pre_order_offers.deposit_amount keep their name
+	// as the amount column, so the decimal column is moved out of the way.
+	if from == amount {
+		from += "_decimal"
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE %q RENAME COLUMN %q TO %q", column.Table, column.From, from)).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Exec(fmt.Sprintf("ALTER TABLE %q ADD COLUMN IF NOT EXISTS %q bigint, ADD COLUMN IF NOT EXISTS %q varchar(3)",
+		column.Table, amount, currency)).Error; err != nil {
+		return false, err
+	}
+
+	factor := int64(math.Pow10(money.Exponent(money.DefaultCurrency)))
+	if err := tx.Exec(fmt.Sprintf("UPDATE %q SET %q = round(%q::numeric * ?), %q = ? WHERE %q IS NOT NULL",
+		column.Table, amount, from, currency, from), factor, money.DefaultCurrency).Error; err != nil {
+		return false, err
+	}
+
+	if err := tx.Exec(fmt.Sprintf("ALTER TABLE %q DROP COLUMN %q", column.Table, from)).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,71 @@
+// Command reconcile-stock reports products and variants whose stock column has
+// drifted from the inventory ledger. It exits with status 1 when it finds any,
+// so it can run from cron or CI.
+//
+//	go run ./cmd/reconcile-stock .env
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/phetployst/art-toys-store/config"
+	"github.com/phetployst/art-toys-store/modules/product/adapters"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("Error: .env path is required")
+	}
+
+	configProvider := config.ConfigProvider{
+		Getter: &config.OsEnvGetter{},
+		Loader: &config.GodotenvLoader{},
+	}
+
+	if err := configProvider.LoadEnvFile(os.Args[1]); err != nil {
+		log.Fatalf("Failed to load .env file from path %s: %v", os.Args[1], err)
+	}
+
+	config, err := configProvider.GetConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(config.Server.DBConnectionString), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	inventory := usecase.NewInventoryService(adapters.NewInventoryRepository(db), adapters.NewProductRepository(db))
+
+	report, err := inventory.ReconcileStock()
+	if err != nil {
+		log.Fatalf("Failed to reconcile stock: %v", err)
+	}
+
+	if len(report.Drifts) == 0 {
+		fmt.Printf("stock matches the ledger (checked at %s)\n", report.CheckedAt.Format("2006-01-02 15:04:05"))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PRODUCT\tVARIANT\tSTOCK\tLEDGER\tDRIFT")
+	for _, drift := range report.Drifts {
+		variant := "-"
+		if drift.VariantID != nil {
+			variant = fmt.Sprint(*drift.VariantID)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%+d\n", drift.ProductID, variant, drift.Stock, drift.Ledger, drift.Stock-drift.Ledger)
+	}
+	w.Flush()
+
+	fmt.Printf("%d item(s) drifted from the ledger (checked at %s)\n", len(report.Drifts), report.CheckedAt.Format("2006-01-02 15:04:05"))
+	os.Exit(1)
+}
@@ -30,6 +30,9 @@ type (
 		Environment string
 		Server      Server
 		Jwt         Jwt
+		Storage     Storage
+		Queue       Queue
+		Payment     Payment
 	}
 
 	Server struct {
@@ -43,6 +46,32 @@ type (
 		AccessTokenSecret  string
 		RefreshTokenSecret string
 	}
+
+	// Queue configures the drop waiting room. TokenSecret signs queue tickets
+	// and admission tokens and must differ from the JWT secrets, so a queue
+	// token is never accepted as a login; AdmitInterval is how many seconds
+	// pass between admitted batches.
+	Queue struct {
+		TokenSecret   string
+		AdmitInterval int
+	}
+
+	// Payment configures the payment provider's callbacks. WebhookSecret
+	// verifies the signature on every payment confirmation.
+	Payment struct {
+		WebhookSecret string
+	}
+
+	Storage struct {
+		Driver        string
+		LocalDir      string
+		PublicBaseURL string
+		S3Endpoint    string
+		S3Region      string
+		S3Bucket      string
+		S3AccessKey   string
+		S3SecretKey   string
+	}
 )
 
 func (o *OsEnvGetter) Getenv(key string) string {
@@ -100,6 +129,28 @@ func (c *ConfigProvider) GetConfig() (Config, error) {
 		return Config{}, fmt.Errorf("failed to load JWT_REFRESH_SECRET: %w", err)
 	}
 
+	queueTokenSecret, err := c.GetRequiredEnv("QUEUE_TOKEN_SECRET")
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to load QUEUE_TOKEN_SECRET: %w", err)
+	}
+
+	if queueTokenSecret == accessTokenSecret || queueTokenSecret == refreshTokenSecret {
+		return Config{}, fmt.Errorf("QUEUE_TOKEN_SECRET must differ from the JWT secrets")
+	}
+
+	paymentWebhookSecret, err := c.GetRequiredEnv("PAYMENT_WEBHOOK_SECRET")
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to load PAYMENT_WEBHOOK_SECRET: %w", err)
+	}
+
+	// Local files are served by the app itself; other drivers fall back to
+	// their own public URL unless one is configured.
+	storageDriver := c.GetStringEnv("STORAGE_DRIVER", "local")
+	publicBaseURL := ""
+	if storageDriver == "local" {
+		publicBaseURL = "/uploads"
+	}
+
 	return Config{
 		Environment: c.GetStringEnv("ENVIRONMENT", "local"),
 		Server: Server{
@@ -112,5 +163,22 @@ func (c *ConfigProvider) GetConfig() (Config, error) {
 			AccessTokenSecret:  accessTokenSecret,
 			RefreshTokenSecret: refreshTokenSecret,
 		},
+		Storage: Storage{
+			Driver:        storageDriver,
+			LocalDir:      c.GetStringEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			PublicBaseURL: c.GetStringEnv("STORAGE_PUBLIC_URL", publicBaseURL),
+			S3Endpoint:    c.GetStringEnv("S3_ENDPOINT", ""),
+			S3Region:      c.GetStringEnv("S3_REGION", ""),
+			S3Bucket:      c.GetStringEnv("S3_BUCKET", ""),
+			S3AccessKey:   c.GetStringEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   c.GetStringEnv("S3_SECRET_KEY", ""),
+		},
+		Queue: Queue{
+			TokenSecret:   queueTokenSecret,
+			AdmitInterval: c.GetIntEnv("QUEUE_ADMIT_INTERVAL", 5),
+		},
+		Payment: Payment{
+			WebhookSecret: paymentWebhookSecret,
+		},
 	}, nil
 }
@@ -140,13 +140,16 @@ func TestLoadEnvFile(t *testing.T) {
 func TestGetConfig(t *testing.T) {
 	t.Run("get env given keys exist", func(t *testing.T) {
 		envGetter := StubEnvGetter{
-			"ENVIRONMENT":          "local",
-			"SERVICE_NAME":         "auth",
-			"HOSTNAME":             "localhost",
-			"PORT":                 "5000",
-			"DB_CONNECTION_STRING": "db://localhost:5432",
-			"JWT_ACCESS_SECRET":    "access-secret",
-			"JWT_REFRESH_SECRET":   "refresh-secret",
+			"ENVIRONMENT":            "local",
+			"SERVICE_NAME":           "auth",
+			"HOSTNAME":               "localhost",
+			"PORT":                   "5000",
+			"DB_CONNECTION_STRING":   "db://localhost:5432",
+			"JWT_ACCESS_SECRET":      "access-secret",
+			"JWT_REFRESH_SECRET":     "refresh-secret",
+			"QUEUE_TOKEN_SECRET":     "queue-secret",
+			"PAYMENT_WEBHOOK_SECRET": "webhook-secret",
+			"QUEUE_ADMIT_INTERVAL":   "10",
 		}
 		configProvider := ConfigProvider{Getter: envGetter}
 		config, err := configProvider.GetConfig()
@@ -164,6 +167,18 @@ func TestGetConfig(t *testing.T) {
 				AccessTokenSecret:  "access-secret",
 				RefreshTokenSecret: "refresh-secret",
 			},
+			Storage: Storage{
+				Driver:        "local",
+				LocalDir:      "./uploads",
+				PublicBaseURL: "/uploads",
+			},
+			Queue: Queue{
+				TokenSecret:   "queue-secret",
+				AdmitInterval: 10,
+			},
+			Payment: Payment{
+				WebhookSecret: "webhook-secret",
+			},
 		}
 
 		assert.NoError(t, err)
@@ -175,8 +190,10 @@ func TestGetConfig(t *testing.T) {
 
 	t.Run("get default value when keys do not exist", func(t *testing.T) {
 		envGetter := StubEnvGetter{
-			"JWT_ACCESS_SECRET":  "access-secret",
-			"JWT_REFRESH_SECRET": "refresh-secret",
+			"JWT_ACCESS_SECRET":      "access-secret",
+			"JWT_REFRESH_SECRET":     "refresh-secret",
+			"QUEUE_TOKEN_SECRET":     "queue-secret",
+			"PAYMENT_WEBHOOK_SECRET": "webhook-secret",
 		}
 		configProvider := ConfigProvider{Getter: envGetter}
 		config, err := configProvider.GetConfig()
@@ -194,6 +211,18 @@ func TestGetConfig(t *testing.T) {
 				AccessTokenSecret:  "access-secret",
 				RefreshTokenSecret: "refresh-secret",
 			},
+			Storage: Storage{
+				Driver:        "local",
+				LocalDir:      "./uploads",
+				PublicBaseURL: "/uploads",
+			},
+			Queue: Queue{
+				TokenSecret:   "queue-secret",
+				AdmitInterval: 5,
+			},
+			Payment: Payment{
+				WebhookSecret: "webhook-secret",
+			},
 		}
 
 		assert.NoError(t, err)
@@ -203,6 +232,58 @@ func TestGetConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("get no default public url given s3 storage", func(t *testing.T) {
+		envGetter := StubEnvGetter{
+			"JWT_ACCESS_SECRET":      "access-secret",
+			"JWT_REFRESH_SECRET":     "refresh-secret",
+			"QUEUE_TOKEN_SECRET":     "queue-secret",
+			"PAYMENT_WEBHOOK_SECRET": "webhook-secret",
+			"STORAGE_DRIVER":         "s3",
+			"S3_BUCKET":              "art-toys",
+		}
+		configProvider := ConfigProvider{Getter: envGetter}
+		config, err := configProvider.GetConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "s3", config.Storage.Driver)
+		assert.Equal(t, "", config.Storage.PublicBaseURL)
+	})
+
+	t.Run("get error given queue token secret do not exist", func(t *testing.T) {
+		envGetter := StubEnvGetter{
+			"JWT_ACCESS_SECRET":  "access-secret",
+			"JWT_REFRESH_SECRET": "refresh-secret",
+		}
+		configProvider := ConfigProvider{Getter: envGetter}
+		_, err := configProvider.GetConfig()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("get error given queue token secret reuses the access secret", func(t *testing.T) {
+		envGetter := StubEnvGetter{
+			"JWT_ACCESS_SECRET":  "access-secret",
+			"JWT_REFRESH_SECRET": "refresh-secret",
+			"QUEUE_TOKEN_SECRET": "access-secret",
+		}
+		configProvider := ConfigProvider{Getter: envGetter}
+		_, err := configProvider.GetConfig()
+
+		assert.EqualError(t, err, "QUEUE_TOKEN_SECRET must differ from the JWT secrets")
+	})
+
+	t.Run("get error given payment webhook secret do not exist", func(t *testing.T) {
+		envGetter := StubEnvGetter{
+			"JWT_ACCESS_SECRET":  "access-secret",
+			"JWT_REFRESH_SECRET": "refresh-secret",
+			"QUEUE_TOKEN_SECRET": "queue-secret",
+		}
+		configProvider := ConfigProvider{Getter: envGetter}
+		_, err := configProvider.GetConfig()
+
+		assert.Error(t, err)
+	})
+
 	t.Run("get error given JWT secret do not exist", func(t *testing.T) {
 		envGetter := StubEnvGetter{}
 		configProvider := ConfigProvider{Getter: envGetter}
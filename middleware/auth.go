@@ -18,6 +18,7 @@ const (
 
 type ConfigProvider interface {
 	GetJwtSecret() string
+	GetPaymentWebhookSecret() string
 }
 
 type ConfigWrapper struct {
@@ -28,6 +29,10 @@ func (cw *ConfigWrapper) GetJwtSecret() string {
 	return cw.Jwt.AccessTokenSecret
 }
 
+func (cw *ConfigWrapper) GetPaymentWebhookSecret() string {
+	return cw.Payment.WebhookSecret
+}
+
 type middlewareHandler struct {
 	config ConfigProvider
 }
@@ -12,12 +12,18 @@ import (
 )
 
 type MockConfigProvider struct {
-	JwtSecret string
+	JwtSecret            string
+	PaymentWebhookSecret string
 }
 
 func (m *MockConfigProvider) GetJwtSecret() string {
 	return m.JwtSecret
 }
+
+func (m *MockConfigProvider) GetPaymentWebhookSecret() string {
+	return m.PaymentWebhookSecret
+}
+
 func TestJwtMiddleWare(t *testing.T) {
 	t.Run("should pass when token is valid", func(t *testing.T) {
 		e := echo.New()
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+)
+
+// PaymentSignatureMiddleware turns away payment callbacks whose body is not
+// signed with the payment webhook secret. The body is put back so the handler
+// can bind it.
+func (m *middlewareHandler) PaymentSignatureMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		}
+
+		if !payment.Verify([]byte(m.config.GetPaymentWebhookSecret()), body, c.Request().Header.Get(payment.SignatureHeader)) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid payment signature")
+		}
+
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		return next(c)
+	}
+}
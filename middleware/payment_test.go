@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentSignatureMiddleware(t *testing.T) {
+	body := `{"reference_id":"a1b2","amount":{"amount":129000,"currency":"THB"}}`
+
+	t.Run("should pass signed callback with its body", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(payment.SignatureHeader, payment.Sign([]byte("webhook-secret"), []byte(body)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := NewMiddlewareHandler(&MockConfigProvider{PaymentWebhookSecret: "webhook-secret"})
+
+		middlewareFunc := handler.PaymentSignatureMiddleware(func(c echo.Context) error {
+			got, _ := io.ReadAll(c.Request().Body)
+			return c.String(http.StatusOK, string(got))
+		})
+		err := middlewareFunc(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("should return unauthorized when signature is wrong", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(payment.SignatureHeader, payment.Sign([]byte("other-secret"), []byte(body)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := NewMiddlewareHandler(&MockConfigProvider{PaymentWebhookSecret: "webhook-secret"})
+
+		middlewareFunc := handler.PaymentSignatureMiddleware(func(c echo.Context) error {
+			return c.String(http.StatusOK, "OK")
+		})
+		err := middlewareFunc(c)
+
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+	})
+}
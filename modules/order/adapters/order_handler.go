@@ -1,8 +1,22 @@
 package adapters
 
 import (
+	"log"
+	"net/http"
+
 	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/order/entities"
 	"github.com/phetployst/art-toys-store/modules/order/usecase"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+const (
+	ContextUserIDKey = "userID"
+	// AdmissionTokenHeader carries waiting room admission tokens, the same
+	// header the product service reads. It may be repeated, once for each
+	// product behind a waiting room.
+	AdmissionTokenHeader = "X-Admission-Token"
 )
 
 type httpOrderHandler struct {
@@ -13,9 +27,135 @@ func NewOrderHandler(usecase usecase.OrderUsecase) *httpOrderHandler {
 	return &httpOrderHandler{usecase}
 }
 
-func (h *httpOrderHandler) AddItemToCart(c echo.Context) {
-	// 	รับ item
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+func (h *httpOrderHandler) AddItemToCart(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	addItemRequest := new(entities.AddItemRequest)
+	if err := request.ContextWrapper(c).Bind(addItemRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	addItemRequest.AdmissionTokens = c.Request().Header.Values(AdmissionTokenHeader)
+
+	cart, err := h.usecase.AddItemToCart(userID, addItemRequest)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, cart)
+}
+
+func (h *httpOrderHandler) Checkout(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	checkoutRequest := new(entities.CheckoutRequest)
+	if err := request.ContextWrapper(c).Bind(checkoutRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	checkoutRequest.AdmissionTokens = c.Request().Header.Values(AdmissionTokenHeader)
+
+	order, err := h.usecase.Checkout(userID, checkoutRequest)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	if order.Status == entities.OrderInReview {
+		return c.JSON(http.StatusAccepted, order)
+	}
+
+	return c.JSON(http.StatusCreated, order)
+}
+
+// ConfirmPayment takes the payment provider's confirmation for a checkout
+// order. It must sit behind the payment signature middleware.
+func (h *httpOrderHandler) ConfirmPayment(c echo.Context) error {
+	confirmation := new(payment.Confirmation)
+	if err := request.ContextWrapper(c).Bind(confirmation); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	order, err := h.usecase.ConfirmPayment(confirmation)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+func (h *httpOrderHandler) CancelOrder(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	order, err := h.usecase.CancelOrder(userID, c.Param("reference"))
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
 
-	//
+// ApproveOrder lets an order held for review through to payment. It must sit
+// behind the admin middleware.
+func (h *httpOrderHandler) ApproveOrder(c echo.Context) error {
+	reviewerID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || reviewerID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	order, err := h.usecase.ApproveOrder(c.Param("reference"), reviewerID)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// RejectOrder turns away an order held for review. It must sit behind the
+// admin middleware.
+func (h *httpOrderHandler) RejectOrder(c echo.Context) error {
+	reviewerID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || reviewerID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	order, err := h.usecase.RejectOrder(c.Param("reference"), reviewerID)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
 
+func orderError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "cart not found", "product not found", "variant not found", "case not found", "currency not supported",
+		"coupon not found", "order not found", "order review not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "cart is empty", "invalid currency", "invalid coupon code", "coupon not active", "coupon expired",
+		"coupon not applicable", "minimum spend not met", "coupons cannot be combined", "product is not a blind box", "product is not a blind box case", "variant required",
+		"shipping address required", "payment instrument required":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "sign in required":
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: err.Error()})
+	case "order rejected", "admission required":
+		return c.JSON(http.StatusForbidden, ErrorResponse{Message: err.Error()})
+	case "blind box sold out", "insufficient stock", "product is not on sale", "purchase limit exceeded",
+		"coupon usage limit reached", "order is not pending", "payment deadline has passed", "payment amount does not match",
+		"reservation not found", "order is not in review", "order review already settled":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
 }
@@ -0,0 +1,437 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAddItemToCart(t *testing.T) {
+	t.Run("add item to cart successfully", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("AddItemToCart", uint(7), &entities.AddItemRequest{ProductID: 1, VariantID: 3, Quantity: 2}).Return(&entities.Cart{
+			Model:    gorm.Model{ID: 4},
+			UserID:   7,
+			Status:   "active",
+			CartItem: []entities.CartItem{{CartID: 4, ProductID: 1, Quantity: 2, Price: money.New(62000, "THB")}},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"variant_id":3,"quantity":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.AddItemToCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"price":{"amount":62000,"currency":"THB"}`)
+	})
+
+	t.Run("add item to cart given insufficient stock", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("AddItemToCart", uint(7), mock.Anything).Return((*entities.Cart)(nil), errors.New("insufficient stock"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"quantity":20}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.AddItemToCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"insufficient stock"}`, response.Body.String())
+	})
+
+	t.Run("add item to cart without a user", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"quantity":1}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.AddItemToCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "AddItemToCart", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCheckout(t *testing.T) {
+	t.Run("checkout successfully", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Checkout", uint(7), &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42"}).Return(&entities.Order{
+			Model:           gorm.Model{ID: 9},
+			UserID:          7,
+			Status:          "pending",
+			ShippingAddress: "1 Sukhumvit Rd",
+			TotalAmount:     money.New(45000, "THB"),
+			OrderItems: []entities.OrderItem{
+				{OrderID: 9, ProductID: 20, Quantity: 1, TotalPrice: money.New(45000, "THB"), BlindBox: &entities.BlindBoxDraw{FigureID: 10, FigureName: "Labubu Sea Salt", ClientSeed: "order-42", Nonce: 7}},
+			},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"shipping_address":"1 Sukhumvit Rd","client_seed":"order-42"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Checkout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"blind_box":{"figure_id":10,"figure_name":"Labubu Sea Salt"`)
+	})
+
+	t.Run("checkout without a user", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"shipping_address":"1 Sukhumvit Rd","client_seed":"order-42"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.Checkout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "Checkout", mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout passes on the admission tokens", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Checkout", uint(7), &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", AdmissionTokens: []string{"token-for-1", "token-for-20"}}).
+			Return((*entities.Order)(nil), errors.New("admission required"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"shipping_address":"1 Sukhumvit Rd","client_seed":"order-42"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Add(AdmissionTokenHeader, "token-for-1")
+		request.Header.Add(AdmissionTokenHeader, "token-for-20")
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Checkout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.JSONEq(t, `{"message":"admission required"}`, response.Body.String())
+	})
+
+	t.Run("checkout an order held for review", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Checkout", uint(7), mock.Anything).Return(&entities.Order{UserID: 7, ReferenceID: "a1b2", Status: entities.OrderInReview}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"shipping_address":"1 Sukhumvit Rd","client_seed":"order-42"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Checkout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"review"`)
+	})
+
+	t.Run("checkout given sold out blind box", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Checkout", uint(7), mock.Anything).Return((*entities.Order)(nil), errors.New("blind box sold out"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"shipping_address":"1 Sukhumvit Rd","client_seed":"order-42"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Checkout(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"blind box sold out"}`, response.Body.String())
+	})
+}
+
+func TestConfirmPayment(t *testing.T) {
+	t.Run("confirm payment successfully", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ConfirmPayment", &payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB"), PaymentFingerprint: "fp_123"}).
+			Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: "paid", TotalAmount: money.New(129000, "THB")}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"a1b2","amount":{"amount":129000,"currency":"THB"},"payment_fingerprint":"fp_123"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmPayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"paid"`)
+	})
+
+	t.Run("confirm payment given missing reference", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":{"amount":129000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmPayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "ConfirmPayment", mock.Anything)
+	})
+
+	t.Run("confirm payment after the deadline", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ConfirmPayment", mock.Anything).Return((*entities.Order)(nil), errors.New("payment deadline has passed"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"a1b2","amount":{"amount":129000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmPayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"payment deadline has passed"}`, response.Body.String())
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	t.Run("cancel order successfully", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CancelOrder", uint(12), "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: "cancelled"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("reference")
+		c.SetParamValues("a1b2")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.CancelOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"cancelled"`)
+	})
+
+	t.Run("cancel an order that is not pending", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CancelOrder", uint(12), "a1b2").Return((*entities.Order)(nil), errors.New("order is not pending"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("reference")
+		c.SetParamValues("a1b2")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.CancelOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestApproveOrder(t *testing.T) {
+	t.Run("approve order successfully", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ApproveOrder", "a1b2", uint(1)).Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: "pending"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("reference")
+		c.SetParamValues("a1b2")
+		c.Set(ContextUserIDKey, uint(1))
+
+		err := handler.ApproveOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"pending"`)
+	})
+
+	t.Run("approve an order that is not in review", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ApproveOrder", "a1b2", uint(1)).Return((*entities.Order)(nil), errors.New("order is not in review"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("reference")
+		c.SetParamValues("a1b2")
+		c.Set(ContextUserIDKey, uint(1))
+
+		err := handler.ApproveOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestRejectOrder(t *testing.T) {
+	t.Run("reject order without a reviewer", func(t *testing.T) {
+		mockService := new(MockOrderUsecase)
+		handler := &httpOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("reference")
+		c.SetParamValues("a1b2")
+
+		err := handler.RejectOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "RejectOrder", mock.Anything, mock.Anything)
+	})
+}
+
+type MockOrderUsecase struct {
+	mock.Mock
+}
+
+func (m *MockOrderUsecase) ApproveOrder(referenceID string, reviewerID uint) (*entities.Order, error) {
+	args := m.Called(referenceID, reviewerID)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderUsecase) RejectOrder(referenceID string, reviewerID uint) (*entities.Order, error) {
+	args := m.Called(referenceID, reviewerID)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderUsecase) AddItemToCart(userID uint, request *entities.AddItemRequest) (*entities.Cart, error) {
+	args := m.Called(userID, request)
+	return args.Get(0).(*entities.Cart), args.Error(1)
+}
+
+func (m *MockOrderUsecase) PlaceOrder(userID uint, items []entities.OrderItem) (*entities.Order, error) {
+	args := m.Called(userID, items)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderUsecase) SettleOrder(referenceID string, amount money.Money) (*entities.Order, error) {
+	args := m.Called(referenceID, amount)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderUsecase) CancelOrder(userID uint, referenceID string) (*entities.Order, error) {
+	args := m.Called(userID, referenceID)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderUsecase) ConfirmPayment(confirmation *payment.Confirmation) (*entities.Order, error) {
+	args := m.Called(confirmation)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderUsecase) CancelExpiredOrders() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOrderUsecase) Checkout(userID uint, request *entities.CheckoutRequest) (*entities.Order, error) {
+	args := m.Called(userID, request)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
@@ -2,9 +2,11 @@ package adapters
 
 import (
 	"errors"
+	"time"
 
 	"github.com/phetployst/art-toys-store/modules/order/entities"
 	"github.com/phetployst/art-toys-store/modules/order/usecase"
+	"github.com/phetployst/art-toys-store/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -16,7 +18,7 @@ func NewOrdertRepository(db *gorm.DB) usecase.OrderRepository {
 	return &gormOrderRepository{db}
 }
 
-func (r *gormOrderRepository) InsertItemToCart(userID uint, productID uint, quantity int, price float64) error {
+func (r *gormOrderRepository) InsertItemToCart(userID uint, productID uint, variantID *uint, quantity int, price money.Money) error {
 	var cart entities.Cart
 
 	// ค้นหาว่าผู้ใช้มีตะกร้าที่ active หรือไม่
@@ -29,12 +31,18 @@ func (r *gormOrderRepository) InsertItemToCart(userID uint, productID uint, quan
 
 	var cartItem entities.CartItem
 	// ตรวจสอบว่าสินค้านี้อยู่ในตะกร้าอยู่แล้วหรือไม่
-	if err := r.db.Where("cart_id = ? AND product_id = ?", cart.ID, productID).First(&cartItem).Error; err != nil {
+	// แต่ละ variant ของสินค้าเดียวกันถือเป็นคนละรายการในตะกร้า
+	if err := r.db.Where(map[string]interface{}{
+		"cart_id":    cart.ID,
+		"product_id": productID,
+		"variant_id": variantID,
+	}).First(&cartItem).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// ถ้าไม่พบให้เพิ่มสินค้าใหม่ในตะกร้า
 			cartItem = entities.CartItem{
 				CartID:    cart.ID,
 				ProductID: productID,
+				VariantID: variantID,
 				Quantity:  quantity,
 				Price:     price,
 			}
@@ -43,7 +51,122 @@ func (r *gormOrderRepository) InsertItemToCart(userID uint, productID uint, quan
 		return err
 	}
 
-	// ถ้าพบสินค้าอยู่แล้วให้เพิ่มจำนวน
-	cartItem.Quantity += quantity
-	return r.db.Save(&cartItem).Error
+	// ถ้าพบสินค้าอยู่แล้วให้เพิ่มจำนวน และเก็บราคาล่าสุดไว้
+	return r.db.Model(&entities.CartItem{}).Where(map[string]interface{}{
+		"cart_id":    cart.ID,
+		"product_id": productID,
+		"variant_id": variantID,
+	}).Updates(map[string]interface{}{
+		"quantity":       gorm.Expr("quantity + ?", quantity),
+		"price_amount":   price.Amount,
+		"price_currency": price.Currency,
+	}).Error
+}
+
+func (r *gormOrderRepository) GetActiveCart(userID uint) (*entities.Cart, error) {
+	cart := &entities.Cart{}
+
+	if err := r.db.Preload("CartItem").Where("user_id = ? AND status = ?", userID, "active").First(cart).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cart not found")
+		}
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// InsertOrder saves the order with its items and closes the cart it was
+// placed from, so the next item added starts a new cart.
+func (r *gormOrderRepository) InsertOrder(order *entities.Order, cartID uint) (*entities.Order, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.Cart{}).Where("id = ?", cartID).Update("status", "completed").Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreateOrder saves an order placed outside the cart, with its items.
+func (r *gormOrderRepository) CreateOrder(order *entities.Order) (*entities.Order, error) {
+	if err := r.db.Create(order).Error; err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *gormOrderRepository) GetOrderByReference(referenceID string) (*entities.Order, error) {
+	order := &entities.Order{}
+
+	if err := r.db.Preload("OrderItems").Where("reference_id = ?", referenceID).First(order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetExpiredOrders lists up to limit pending orders whose payment deadline
+// passed before now, oldest first.
+func (r *gormOrderRepository) GetExpiredOrders(now time.Time, limit int) ([]entities.Order, error) {
+	var orders []entities.Order
+
+	if err := r.db.Where("status = ? AND expires_at <= ?", entities.OrderPending, now).
+		Order("expires_at, id").
+		Limit(limit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// ReopenOrder moves the order referenced by referenceID out of review to
+// pending, to be paid by expiresAt. It reports false when the order was not
+// in review.
+func (r *gormOrderRepository) ReopenOrder(referenceID string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&entities.Order{}).
+		Where("reference_id = ? AND status = ?", referenceID, entities.OrderInReview).
+		Updates(map[string]any{"status": entities.OrderPending, "expires_at": expiresAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CountOrdersSince counts the orders the user placed since since, whatever
+// became of them.
+func (r *gormOrderRepository) CountOrdersSince(userID uint, since time.Time) (int64, error) {
+	var orders int64
+
+	if err := r.db.Model(&entities.Order{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&orders).Error; err != nil {
+		return 0, err
+	}
+
+	return orders, nil
+}
+
+// UpdateOrderStatus moves the order referenced by referenceID from one status
+// to another. It reports false when the order was not in status from.
+func (r *gormOrderRepository) UpdateOrderStatus(referenceID, from, to string) (bool, error) {
+	result := r.db.Model(&entities.Order{}).
+		Where("reference_id = ? AND status = ?", referenceID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
 }
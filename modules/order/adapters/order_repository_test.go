@@ -0,0 +1,254 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getActiveCartQuery        = `SELECT * FROM "carts" WHERE (user_id = $1 AND status = $2) AND "carts"."deleted_at" IS NULL ORDER BY "carts"."id" LIMIT $3`
+	getCartItemsQuery         = `SELECT * FROM "cart_items" WHERE "cart_items"."cart_id" = $1`
+	insertOrderQuery          = `INSERT INTO "orders" ("created_at","updated_at","deleted_at","user_id","reference_id","total_amount","total_currency","status","shipping_address","quote_currency","quote_rate","quote_quoted_at","discount_amount","discount_currency","expires_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`
+	insertOrderItemsQuery     = `INSERT INTO "order_items" ("created_at","updated_at","deleted_at","order_id","product_id","variant_id","quantity","total_price_amount","total_price_currency","savings_amount","savings_currency","promotions","discount_amount","discount_currency","draw_figure_id","draw_figure_name","draw_is_secret","draw_seed_id","draw_seed_hash","draw_client_seed","draw_nonce","draw_roll","draw_pool","blind_box_case") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24) ON CONFLICT ("id") DO UPDATE SET "order_id"="excluded"."order_id" RETURNING "id"`
+	insertOrderDiscountsQuery = `INSERT INTO "order_discounts" ("created_at","updated_at","deleted_at","order_id","code","type","amount_amount","amount_currency") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT ("id") DO UPDATE SET "order_id"="excluded"."order_id" RETURNING "id"`
+	findActiveCartQuery       = `SELECT * FROM "carts" WHERE (user_id = $1 AND status = $2) AND ("carts"."user_id" = $3 AND "carts"."status" = $4) AND "carts"."deleted_at" IS NULL ORDER BY "carts"."id" LIMIT $5`
+	findCartItemQuery         = `SELECT * FROM "cart_items" WHERE "cart_id" = $1 AND "product_id" = $2 AND "variant_id" = $3 ORDER BY "cart_items"."cart_id" LIMIT $4`
+	addCartItemQuery          = `UPDATE "cart_items" SET "price_amount"=$1,"price_currency"=$2,"quantity"=quantity + $3 WHERE "cart_id" = $4 AND "product_id" = $5 AND "variant_id" = $6`
+	completeCartQuery         = `UPDATE "carts" SET "status"=$1,"updated_at"=$2 WHERE id = $3 AND "carts"."deleted_at" IS NULL`
+	getExpiredOrdersQuery     = `SELECT * FROM "orders" WHERE (status = $1 AND expires_at <= $2) AND "orders"."deleted_at" IS NULL ORDER BY expires_at, id LIMIT $3`
+	reopenOrderQuery          = `UPDATE "orders" SET "expires_at"=$1,"status"=$2,"updated_at"=$3 WHERE (reference_id = $4 AND status = $5) AND "orders"."deleted_at" IS NULL`
+	countOrdersSinceQuery     = `SELECT count(*) FROM "orders" WHERE (user_id = $1 AND created_at >= $2) AND "orders"."deleted_at" IS NULL`
+	updateOrderStatusQuery    = `UPDATE "orders" SET "status"=$1,"updated_at"=$2 WHERE (reference_id = $3 AND status = $4) AND "orders"."deleted_at" IS NULL`
+)
+
+func TestInsertItemToCart_gormRepo(t *testing.T) {
+	t.Run("add units to an item already in the cart", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		variantID := uint(3)
+
+		mock.ExpectQuery(findActiveCartQuery).
+			WithArgs(7, "active", 7, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(4, 7, "active"))
+		mock.ExpectQuery(findCartItemQuery).
+			WithArgs(4, 1, 3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"cart_id", "product_id", "variant_id", "quantity", "price_amount", "price_currency"}).AddRow(4, 1, 3, 1, 59000, "THB"))
+		mock.ExpectBegin()
+		mock.ExpectExec(addCartItemQuery).
+			WithArgs(62000, "THB", 2, 4, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.InsertItemToCart(7, 1, &variantID, 2, money.New(62000, "THB"))
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetActiveCart_gormRepo(t *testing.T) {
+	t.Run("get active cart with its items", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		mock.ExpectQuery(getActiveCartQuery).
+			WithArgs(7, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(4, 7, "active"))
+		mock.ExpectQuery(getCartItemsQuery).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"cart_id", "product_id", "quantity", "price_amount", "price_currency"}).AddRow(4, 20, 2, 45000, "THB"))
+
+		got, err := repo.GetActiveCart(7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), got.ID)
+		assert.Equal(t, []entities.CartItem{{CartID: 4, ProductID: 20, Quantity: 2, Price: money.New(45000, "THB")}}, got.CartItem)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get active cart given none", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		mock.ExpectQuery(getActiveCartQuery).
+			WithArgs(7, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.GetActiveCart(7)
+
+		assert.EqualError(t, err, "cart not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInsertOrder_gormRepo(t *testing.T) {
+	t.Run("insert order and complete the cart", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		order := &entities.Order{
+			UserID:          7,
+			ReferenceID:     "a1b2",
+			Status:          entities.OrderPending,
+			ShippingAddress: "1 Sukhumvit Rd",
+			TotalAmount:     money.New(40500, "THB"),
+			Discount:        money.New(4500, "THB"),
+			Discounts:       []entities.OrderDiscount{{Code: "WELCOME", Type: "percentage", Amount: money.New(4500, "THB")}},
+			OrderItems: []entities.OrderItem{
+				{ProductID: 20, Quantity: 1, TotalPrice: money.New(40500, "THB"), Discount: money.New(4500, "THB"), BlindBox: &entities.BlindBoxDraw{FigureID: 10, FigureName: "Labubu Sea Salt", ClientSeed: "order-42", Nonce: 7}},
+			},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertOrderQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectQuery(insertOrderItemsQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(15))
+		mock.ExpectQuery(insertOrderDiscountsQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 9, "WELCOME", "percentage", 4500, "THB").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(completeCartQuery).
+			WithArgs("completed", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.InsertOrder(order, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(9), got.ID)
+		assert.Equal(t, uint(9), got.OrderItems[0].OrderID)
+		assert.Equal(t, uint(9), got.Discounts[0].OrderID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetExpiredOrders_gormRepo(t *testing.T) {
+	t.Run("list pending orders past their deadline", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(getExpiredOrdersQuery).
+			WithArgs("pending", now, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "reference_id", "status"}).AddRow(9, 12, "a1b2", "pending"))
+
+		got, err := repo.GetExpiredOrders(now, 100)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "a1b2", got[0].ReferenceID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReopenOrder_gormRepo(t *testing.T) {
+	t.Run("reopen an approved order with a new deadline", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		expiresAt := time.Date(2026, 10, 1, 10, 30, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(reopenOrderQuery).
+			WithArgs(expiresAt, "pending", sqlmock.AnyArg(), "a1b2", "review").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.ReopenOrder("a1b2", expiresAt)
+
+		assert.NoError(t, err)
+		assert.True(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCountOrdersSince_gormRepo(t *testing.T) {
+	t.Run("count the user's recent orders", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		since := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(countOrdersSinceQuery).
+			WithArgs(12, since).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		got, err := repo.CountOrdersSince(12, since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateOrderStatus_gormRepo(t *testing.T) {
+	t.Run("move a pending order to paid", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateOrderStatusQuery).
+			WithArgs("paid", sqlmock.AnyArg(), "a1b2", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.UpdateOrderStatus("a1b2", entities.OrderPending, entities.OrderPaid)
+
+		assert.NoError(t, err)
+		assert.True(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("move an order that is no longer pending", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewOrdertRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateOrderStatusQuery).
+			WithArgs("cancelled", sqlmock.AnyArg(), "a1b2", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		got, err := repo.UpdateOrderStatus("a1b2", entities.OrderPending, entities.OrderCancelled)
+
+		assert.NoError(t, err)
+		assert.False(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
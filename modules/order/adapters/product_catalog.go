@@ -0,0 +1,223 @@
+package adapters
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	"github.com/phetployst/art-toys-store/modules/order/usecase"
+	productEntities "github.com/phetployst/art-toys-store/modules/product/entities"
+	productUsecase "github.com/phetployst/art-toys-store/modules/product/usecase"
+)
+
+// productCatalog serves the order module's Catalog from the product service's
+// usecases.
+type productCatalog struct {
+	products     productUsecase.ProductUsecase
+	reservations productUsecase.ReservationUsecase
+	blindBoxes   productUsecase.BlindBoxUsecase
+	drops        productUsecase.DropUsecase
+	currencies   productUsecase.CurrencyUsecase
+	promotions   productUsecase.PromotionUsecase
+	queue        productUsecase.QueueUsecase
+	risk         productUsecase.RiskUsecase
+}
+
+func NewProductCatalog(products productUsecase.ProductUsecase, reservations productUsecase.ReservationUsecase, blindBoxes productUsecase.BlindBoxUsecase, drops productUsecase.DropUsecase, currencies productUsecase.CurrencyUsecase, promotions productUsecase.PromotionUsecase, queue productUsecase.QueueUsecase, risk productUsecase.RiskUsecase) usecase.Catalog {
+	return &productCatalog{products, reservations, blindBoxes, drops, currencies, promotions, queue, risk}
+}
+
+func (c *productCatalog) GetItem(productID uint, variantID *uint) (*entities.CatalogItem, error) {
+	item, err := c.products.GetSellableItem(productID, valueOf(variantID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.CatalogItem{
+		Price:    item.Price,
+		BlindBox: item.Type == productEntities.ProductTypeBlindBox || item.Type == productEntities.ProductTypeBlindBoxCase,
+	}, nil
+}
+
+// Reserve holds the units under the user's cart holder, the same one the
+// product service's reservation endpoints act on for that user.
+func (c *productCatalog) Reserve(userID, productID uint, variantID *uint, quantity int) error {
+	_, err := c.reservations.Reserve(&productEntities.ReservationRequest{
+		UserID:    userID,
+		HolderID:  productEntities.CartHolderID(userID),
+		ProductID: productID,
+		VariantID: valueOf(variantID),
+		Quantity:  quantity,
+	})
+
+	return err
+}
+
+func (c *productCatalog) HoldOrder(userID uint, referenceID string, items []entities.CartItem, expiresAt time.Time) error {
+	request := &productEntities.OrderHoldRequest{UserID: userID, ReferenceID: referenceID, ExpiresAt: expiresAt}
+	for _, item := range items {
+		request.Items = append(request.Items, productEntities.OrderHoldItem{ProductID: item.ProductID, VariantID: valueOf(item.VariantID), Quantity: item.Quantity})
+	}
+
+	_, err := c.reservations.HoldOrder(request)
+	return err
+}
+
+func (c *productCatalog) ReleaseReservations(referenceID string) error {
+	return c.reservations.ReleaseReservations(referenceID)
+}
+
+func (c *productCatalog) ConvertReservations(referenceID, shippingAddress, paymentFingerprint string) error {
+	_, err := c.reservations.ConvertReservations(&productEntities.ReservationConvertRequest{
+		ReferenceID:        referenceID,
+		ShippingAddress:    shippingAddress,
+		PaymentFingerprint: paymentFingerprint,
+	})
+
+	return err
+}
+
+func (c *productCatalog) ScreenOrder(screening *entities.OrderScreening) (bool, error) {
+	review, err := c.risk.ScreenOrder(&productEntities.OrderScreenRequest{
+		UserID:         screening.UserID,
+		ReferenceID:    screening.ReferenceID,
+		Quantity:       screening.Quantity,
+		OrdersLastHour: screening.OrdersLastHour,
+		OrdersLastDay:  screening.OrdersLastDay,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return review != nil, nil
+}
+
+func (c *productCatalog) ApproveReview(referenceID string, reviewerID uint, expiresAt time.Time) error {
+	_, err := c.risk.ApproveOrderReview(referenceID, reviewerID, expiresAt)
+	return err
+}
+
+func (c *productCatalog) RejectReview(referenceID string, reviewerID uint) error {
+	_, err := c.risk.RejectOrderReview(referenceID, reviewerID)
+	return err
+}
+
+func (c *productCatalog) CheckAdmission(userID, productID uint, token string) error {
+	return c.queue.CheckAdmission(strconv.FormatUint(uint64(productID), 10), userID, token)
+}
+
+func (c *productCatalog) CheckPurchase(userID, productID uint, quantity int) error {
+	return c.drops.CheckPurchase(userID, productID, quantity)
+}
+
+func (c *productCatalog) DrawBlindBoxes(items []entities.CartItem, clientSeed, referenceID string) (*entities.BlindBoxDraws, error) {
+	request := &productEntities.BlindBoxOrderDrawRequest{ClientSeed: clientSeed, ReferenceID: referenceID}
+	for _, item := range items {
+		request.Items = append(request.Items, productEntities.BlindBoxOrderItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	result, err := c.blindBoxes.DrawOrder(request)
+	if err != nil {
+		return nil, err
+	}
+
+	draws := &entities.BlindBoxDraws{
+		Boxes: map[uint][]entities.BlindBoxDraw{},
+		Cases: map[uint][]entities.BlindBoxCaseDraw{},
+	}
+
+	for _, box := range result.Boxes {
+		draws.Boxes[box.BoxID] = append(draws.Boxes[box.BoxID], entities.BlindBoxDraw{
+			FigureID:   box.FigureID,
+			FigureName: box.FigureName,
+			IsSecret:   box.IsSecret,
+			SeedID:     box.SeedID,
+			SeedHash:   box.SeedHash,
+			ClientSeed: box.ClientSeed,
+			Nonce:      box.Nonce,
+			Roll:       box.Roll,
+			Pool:       box.Pool,
+		})
+	}
+
+	for _, opened := range result.Cases {
+		slots := make([]entities.BlindBoxCaseSlot, 0, len(opened.Figures))
+		for _, figure := range opened.Figures {
+			slots = append(slots, entities.BlindBoxCaseSlot{
+				FigureID:   figure.FigureID,
+				FigureName: figure.FigureName,
+				IsSecret:   figure.IsSecret,
+			})
+		}
+
+		draws.Cases[opened.CaseID] = append(draws.Cases[opened.CaseID], entities.BlindBoxCaseDraw{
+			Figures:    slots,
+			SeedID:     opened.SeedID,
+			SeedHash:   opened.SeedHash,
+			ClientSeed: opened.ClientSeed,
+			Nonce:      opened.Nonce,
+			Rolls:      opened.Rolls,
+			Pool:       opened.Pool,
+		})
+	}
+
+	return draws, nil
+}
+
+func (c *productCatalog) RedeemCoupons(userID uint, referenceID string, codes []string, items []entities.CartItem) (*entities.PricedCart, error) {
+	request := &productEntities.CouponRedeemRequest{
+		CartPricingRequest: productEntities.CartPricingRequest{UserID: userID, Codes: codes},
+		ReferenceID:        referenceID,
+	}
+	for _, item := range items {
+		request.Items = append(request.Items, productEntities.CartLine{ProductID: item.ProductID, VariantID: valueOf(item.VariantID), Quantity: item.Quantity})
+	}
+
+	pricing, err := c.promotions.RedeemCoupons(request)
+	if err != nil {
+		return nil, err
+	}
+
+	priced := &entities.PricedCart{Discount: pricing.Discount, Total: pricing.Total}
+	for _, line := range pricing.Items {
+		item := entities.PricedItem{Savings: line.Savings, Discount: line.Discount, Total: line.Total}
+		for _, promotion := range line.Promotions {
+			item.Promotions = append(item.Promotions, entities.ItemPromotion{PromotionID: promotion.PromotionID, Name: promotion.Name, Amount: promotion.Amount})
+		}
+		priced.Items = append(priced.Items, item)
+	}
+	for _, applied := range pricing.Discounts {
+		priced.Discounts = append(priced.Discounts, entities.OrderDiscount{Code: applied.Code, Type: applied.Type, Amount: applied.Amount})
+	}
+
+	return priced, nil
+}
+
+func (c *productCatalog) ReturnDraws(referenceID string) error {
+	return c.blindBoxes.ReturnDraws(referenceID)
+}
+
+func (c *productCatalog) ReleaseCoupons(referenceID string) error {
+	return c.promotions.ReleaseCoupons(referenceID)
+}
+
+func (c *productCatalog) Quote(currency string) (*entities.RateQuote, error) {
+	quote, err := c.currencies.Quote(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if quote.Currency == quote.SettlementCurrency {
+		return nil, nil
+	}
+
+	return &entities.RateQuote{Currency: quote.Currency, Rate: quote.Rate, QuotedAt: quote.QuotedAt}, nil
+}
+
+func valueOf(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+
+	return *id
+}
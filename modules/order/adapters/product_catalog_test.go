@@ -0,0 +1,586 @@
+package adapters
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	productEntities "github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetItem(t *testing.T) {
+	t.Run("get item marks blind boxes", func(t *testing.T) {
+		mockProducts := new(MockProductUsecase)
+		catalog := &productCatalog{products: mockProducts}
+
+		mockProducts.On("GetSellableItem", uint(20), uint(0)).Return(&productEntities.SellableItem{ProductID: 20, Type: productEntities.ProductTypeBlindBox, Price: money.New(45000, "THB")}, nil)
+
+		got, err := catalog.GetItem(20, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.CatalogItem{Price: money.New(45000, "THB"), BlindBox: true}, got)
+	})
+
+	t.Run("get item given product off sale", func(t *testing.T) {
+		mockProducts := new(MockProductUsecase)
+		catalog := &productCatalog{products: mockProducts}
+
+		variantID := uint(3)
+		mockProducts.On("GetSellableItem", uint(1), uint(3)).Return((*productEntities.SellableItem)(nil), errors.New("product is not on sale"))
+
+		_, err := catalog.GetItem(1, &variantID)
+
+		assert.EqualError(t, err, "product is not on sale")
+	})
+}
+
+func TestReserve(t *testing.T) {
+	t.Run("reserve under the user's cart holder", func(t *testing.T) {
+		mockReservations := new(MockReservationUsecase)
+		catalog := &productCatalog{reservations: mockReservations}
+
+		variantID := uint(3)
+		mockReservations.On("Reserve", &productEntities.ReservationRequest{UserID: 7, HolderID: "user:7", ProductID: 1, VariantID: 3, Quantity: 2}).
+			Return(&productEntities.StockReservation{HolderID: "user:7", ProductID: 1, Quantity: 2}, nil)
+
+		err := catalog.Reserve(7, 1, &variantID, 2)
+
+		assert.NoError(t, err)
+		mockReservations.AssertExpectations(t)
+	})
+}
+
+func TestHoldOrder(t *testing.T) {
+	t.Run("hold the cart's items for the order", func(t *testing.T) {
+		mockReservations := new(MockReservationUsecase)
+		catalog := &productCatalog{reservations: mockReservations}
+
+		variantID := uint(3)
+		expiresAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+		items := []entities.CartItem{
+			{CartID: 4, ProductID: 1, VariantID: &variantID, Quantity: 2, Price: money.New(129000, "THB")},
+			{CartID: 4, ProductID: 20, Quantity: 1, Price: money.New(45000, "THB")},
+		}
+
+		mockReservations.On("HoldOrder", &productEntities.OrderHoldRequest{
+			UserID:      7,
+			ReferenceID: "a1b2",
+			Items:       []productEntities.OrderHoldItem{{ProductID: 1, VariantID: 3, Quantity: 2}, {ProductID: 20, Quantity: 1}},
+			ExpiresAt:   expiresAt,
+		}).Return([]productEntities.StockReservation{{HolderID: "a1b2", ProductID: 1, Quantity: 2}}, nil)
+
+		err := catalog.HoldOrder(7, "a1b2", items, expiresAt)
+
+		assert.NoError(t, err)
+		mockReservations.AssertExpectations(t)
+	})
+}
+
+func TestConvertReservations(t *testing.T) {
+	t.Run("convert the order's reservations with its address and instrument", func(t *testing.T) {
+		mockReservations := new(MockReservationUsecase)
+		catalog := &productCatalog{reservations: mockReservations}
+
+		mockReservations.On("ConvertReservations", &productEntities.ReservationConvertRequest{ReferenceID: "a1b2", ShippingAddress: "1 Sukhumvit Rd", PaymentFingerprint: "fp_123"}).
+			Return(([]productEntities.StockReservation)(nil), errors.New("purchase limit exceeded"))
+
+		err := catalog.ConvertReservations("a1b2", "1 Sukhumvit Rd", "fp_123")
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+	})
+}
+
+func TestScreenOrder(t *testing.T) {
+	t.Run("screen order reports a hold for review", func(t *testing.T) {
+		mockRisk := new(MockRiskUsecase)
+		catalog := &productCatalog{risk: mockRisk}
+
+		mockRisk.On("ScreenOrder", &productEntities.OrderScreenRequest{UserID: 7, ReferenceID: "a1b2", Quantity: 3, OrdersLastHour: 1, OrdersLastDay: 2}).
+			Return(&productEntities.OrderReview{ReferenceID: "a1b2", Status: "pending"}, nil)
+
+		held, err := catalog.ScreenOrder(&entities.OrderScreening{UserID: 7, ReferenceID: "a1b2", Quantity: 3, OrdersLastHour: 1, OrdersLastDay: 2})
+
+		assert.NoError(t, err)
+		assert.True(t, held)
+	})
+
+	t.Run("screen order the rules let through", func(t *testing.T) {
+		mockRisk := new(MockRiskUsecase)
+		catalog := &productCatalog{risk: mockRisk}
+
+		mockRisk.On("ScreenOrder", mock.Anything).Return((*productEntities.OrderReview)(nil), nil)
+
+		held, err := catalog.ScreenOrder(&entities.OrderScreening{UserID: 7, ReferenceID: "a1b2", Quantity: 1})
+
+		assert.NoError(t, err)
+		assert.False(t, held)
+	})
+}
+
+func TestCheckAdmission(t *testing.T) {
+	t.Run("check admission to the product's waiting room", func(t *testing.T) {
+		mockQueue := new(MockQueueUsecase)
+		catalog := &productCatalog{queue: mockQueue}
+
+		mockQueue.On("CheckAdmission", "5", uint(7), "admission-token").Return(errors.New("admission required"))
+
+		err := catalog.CheckAdmission(7, 5, "admission-token")
+
+		assert.EqualError(t, err, "admission required")
+	})
+}
+
+func TestCheckPurchase(t *testing.T) {
+	t.Run("check purchase against the drop rules", func(t *testing.T) {
+		mockDrops := new(MockDropUsecase)
+		catalog := &productCatalog{drops: mockDrops}
+
+		mockDrops.On("CheckPurchase", uint(7), uint(20), 3).Return(errors.New("purchase limit exceeded"))
+
+		err := catalog.CheckPurchase(7, 20, 3)
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+	})
+}
+
+func TestDrawBlindBoxes(t *testing.T) {
+	t.Run("draw blind boxes and cases keyed by product", func(t *testing.T) {
+		mockBlindBoxes := new(MockBlindBoxUsecase)
+		catalog := &productCatalog{blindBoxes: mockBlindBoxes}
+
+		items := []entities.CartItem{
+			{CartID: 4, ProductID: 20, Quantity: 2, Price: money.New(45000, "THB")},
+			{CartID: 4, ProductID: 30, Quantity: 1, Price: money.New(540000, "THB")},
+		}
+
+		mockBlindBoxes.On("DrawOrder", &productEntities.BlindBoxOrderDrawRequest{
+			ClientSeed:  "order-42",
+			ReferenceID: "a1b2",
+			Items:       []productEntities.BlindBoxOrderItem{{ProductID: 20, Quantity: 2}, {ProductID: 30, Quantity: 1}},
+		}).Return(&productEntities.BlindBoxOrderDraw{
+			Boxes: []productEntities.BlindBoxDraw{
+				{BoxID: 20, FigureID: 10, FigureName: "Labubu Sea Salt", SeedID: 1, SeedHash: "hash", ClientSeed: "order-42", Nonce: 7, Roll: 12, Pool: "10:75,12:25"},
+				{BoxID: 20, FigureID: 12, FigureName: "Chestnut Cocoa", IsSecret: true, SeedID: 1, SeedHash: "hash", ClientSeed: "order-42", Nonce: 8, Roll: 96, Pool: "10:75,12:25"},
+			},
+			Cases: []productEntities.BlindBoxCaseDraw{
+				{CaseID: 30, BoxID: 20, Figures: []productEntities.BlindBoxCaseSlot{{FigureID: 10, FigureName: "Labubu Sea Salt"}}, SeedID: 1, SeedHash: "hash", ClientSeed: "order-42", Nonce: 9, Rolls: 1, Pool: "10:75,12:25"},
+			},
+		}, nil)
+
+		got, err := catalog.DrawBlindBoxes(items, "order-42", "a1b2")
+
+		want := &entities.BlindBoxDraws{
+			Boxes: map[uint][]entities.BlindBoxDraw{20: {
+				{FigureID: 10, FigureName: "Labubu Sea Salt", SeedID: 1, SeedHash: "hash", ClientSeed: "order-42", Nonce: 7, Roll: 12, Pool: "10:75,12:25"},
+				{FigureID: 12, FigureName: "Chestnut Cocoa", IsSecret: true, SeedID: 1, SeedHash: "hash", ClientSeed: "order-42", Nonce: 8, Roll: 96, Pool: "10:75,12:25"},
+			}},
+			Cases: map[uint][]entities.BlindBoxCaseDraw{30: {
+				{Figures: []entities.BlindBoxCaseSlot{{FigureID: 10, FigureName: "Labubu Sea Salt"}}, SeedID: 1, SeedHash: "hash", ClientSeed: "order-42", Nonce: 9, Rolls: 1, Pool: "10:75,12:25"},
+			}},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestRedeemCoupons(t *testing.T) {
+	t.Run("redeem coupons for the order's cart", func(t *testing.T) {
+		mockPromotions := new(MockPromotionUsecase)
+		catalog := &productCatalog{promotions: mockPromotions}
+
+		variantID := uint(3)
+		items := []entities.CartItem{
+			{CartID: 4, ProductID: 1, VariantID: &variantID, Quantity: 2, Price: money.New(129000, "THB")},
+		}
+
+		mockPromotions.On("RedeemCoupons", &productEntities.CouponRedeemRequest{
+			CartPricingRequest: productEntities.CartPricingRequest{UserID: 7, Codes: []string{"WELCOME"}, Items: []productEntities.CartLine{{ProductID: 1, VariantID: 3, Quantity: 2}}},
+			ReferenceID:        "a1b2",
+		}).Return(&productEntities.CartPricing{
+			Items: []productEntities.PricedCartLine{{
+				ProductID:  1,
+				VariantID:  3,
+				Quantity:   2,
+				UnitPrice:  money.New(129000, "THB"),
+				Promotions: []productEntities.LinePromotion{{PromotionID: 2, Name: "Labubu bundle", Amount: money.New(10000, "THB")}},
+				Savings:    money.New(10000, "THB"),
+				Discount:   money.New(12900, "THB"),
+				Total:      money.New(235100, "THB"),
+			}},
+			Discounts: []productEntities.AppliedCoupon{{CouponID: 5, Code: "WELCOME", Type: "percentage", Amount: money.New(12900, "THB")}},
+			Discount:  money.New(12900, "THB"),
+			Total:     money.New(235100, "THB"),
+		}, nil)
+
+		got, err := catalog.RedeemCoupons(7, "a1b2", []string{"WELCOME"}, items)
+
+		want := &entities.PricedCart{
+			Items: []entities.PricedItem{{
+				Promotions: []entities.ItemPromotion{{PromotionID: 2, Name: "Labubu bundle", Amount: money.New(10000, "THB")}},
+				Savings:    money.New(10000, "THB"),
+				Discount:   money.New(12900, "THB"),
+				Total:      money.New(235100, "THB"),
+			}},
+			Discount:  money.New(12900, "THB"),
+			Discounts: []entities.OrderDiscount{{Code: "WELCOME", Type: "percentage", Amount: money.New(12900, "THB")}},
+			Total:     money.New(235100, "THB"),
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestQuote(t *testing.T) {
+	t.Run("quote a foreign currency", func(t *testing.T) {
+		mockCurrencies := new(MockCurrencyUsecase)
+		catalog := &productCatalog{currencies: mockCurrencies}
+
+		quotedAt := time.Date(2027, 3, 1, 9, 0, 0, 0, time.UTC)
+		mockCurrencies.On("Quote", "usd").Return(&productEntities.RateQuote{SettlementCurrency: "THB", Currency: "USD", Rate: 27350, QuotedAt: quotedAt}, nil)
+
+		got, err := catalog.Quote("usd")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.RateQuote{Currency: "USD", Rate: 27350, QuotedAt: quotedAt}, got)
+	})
+
+	t.Run("quote the settlement currency", func(t *testing.T) {
+		mockCurrencies := new(MockCurrencyUsecase)
+		catalog := &productCatalog{currencies: mockCurrencies}
+
+		mockCurrencies.On("Quote", "THB").Return(&productEntities.RateQuote{SettlementCurrency: "THB", Currency: "THB", Rate: money.RateScale}, nil)
+
+		got, err := catalog.Quote("THB")
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+type MockBlindBoxUsecase struct {
+	mock.Mock
+}
+
+func (m *MockBlindBoxUsecase) AddFigure(boxID string, figure *productEntities.BlindBoxFigure) (*productEntities.BlindBoxFigureResponse, error) {
+	args := m.Called(boxID, figure)
+	return args.Get(0).(*productEntities.BlindBoxFigureResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) GetFigures(boxID string) ([]productEntities.BlindBoxFigureResponse, error) {
+	args := m.Called(boxID)
+	return args.Get(0).([]productEntities.BlindBoxFigureResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) UpdateFigure(boxID, figureID string, update *productEntities.BlindBoxFigureUpdate) (*productEntities.BlindBoxFigureResponse, error) {
+	args := m.Called(boxID, figureID, update)
+	return args.Get(0).(*productEntities.BlindBoxFigureResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) DeleteFigure(boxID, figureID string) error {
+	args := m.Called(boxID, figureID)
+	return args.Error(0)
+}
+
+func (m *MockBlindBoxUsecase) DrawOrder(request *productEntities.BlindBoxOrderDrawRequest) (*productEntities.BlindBoxOrderDraw, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.BlindBoxOrderDraw), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) ReturnDraws(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
+
+func (m *MockBlindBoxUsecase) GetSeeds(boxID string) ([]productEntities.BlindBoxSeedResponse, error) {
+	args := m.Called(boxID)
+	return args.Get(0).([]productEntities.BlindBoxSeedResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) RotateSeed(boxID string) (*productEntities.BlindBoxSeedResponse, error) {
+	args := m.Called(boxID)
+	return args.Get(0).(*productEntities.BlindBoxSeedResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) SetCase(caseID string, blindBoxCase *productEntities.BlindBoxCase) (*productEntities.BlindBoxCaseResponse, error) {
+	args := m.Called(caseID, blindBoxCase)
+	return args.Get(0).(*productEntities.BlindBoxCaseResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) GetCase(caseID string) (*productEntities.BlindBoxCaseResponse, error) {
+	args := m.Called(caseID)
+	return args.Get(0).(*productEntities.BlindBoxCaseResponse), args.Error(1)
+}
+
+type MockProductUsecase struct {
+	mock.Mock
+}
+
+func (m *MockProductUsecase) CreateNewProduct(product *productEntities.Product) (*productEntities.ProductResponse, error) {
+	args := m.Called(product)
+	return args.Get(0).(*productEntities.ProductResponse), args.Error(1)
+}
+
+func (m *MockProductUsecase) GetAllProducts() ([]productEntities.ProductResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]productEntities.ProductResponse), args.Error(1)
+}
+
+func (m *MockProductUsecase) GetProductById(id string) (*productEntities.ProductResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*productEntities.ProductResponse), args.Error(1)
+}
+
+func (m *MockProductUsecase) GetSellableItem(productID, variantID uint) (*productEntities.SellableItem, error) {
+	args := m.Called(productID, variantID)
+	return args.Get(0).(*productEntities.SellableItem), args.Error(1)
+}
+
+func (m *MockProductUsecase) UpdateProduct(product *productEntities.Product, id string) (*productEntities.ProductResponse, error) {
+	args := m.Called(product, id)
+	return args.Get(0).(*productEntities.ProductResponse), args.Error(1)
+}
+
+func (m *MockProductUsecase) DeductStock(id string, count *productEntities.CountProduct) (*productEntities.CountProduct, error) {
+	args := m.Called(id, count)
+	return args.Get(0).(*productEntities.CountProduct), args.Error(1)
+}
+
+func (m *MockProductUsecase) ListProducts(filter *productEntities.ProductFilter) ([]productEntities.ProductResponse, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]productEntities.ProductResponse), args.Error(1)
+}
+
+type MockReservationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockReservationUsecase) Reserve(request *productEntities.ReservationRequest) (*productEntities.StockReservation, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) GetReservations(holderID string) ([]productEntities.StockReservation, error) {
+	args := m.Called(holderID)
+	return args.Get(0).([]productEntities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ExtendReservations(holderID string) ([]productEntities.StockReservation, error) {
+	args := m.Called(holderID)
+	return args.Get(0).([]productEntities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ReleaseReservations(holderID string) error {
+	args := m.Called(holderID)
+	return args.Error(0)
+}
+
+func (m *MockReservationUsecase) HoldOrder(request *productEntities.OrderHoldRequest) ([]productEntities.StockReservation, error) {
+	args := m.Called(request)
+	return args.Get(0).([]productEntities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ConvertReservations(request *productEntities.ReservationConvertRequest) ([]productEntities.StockReservation, error) {
+	args := m.Called(request)
+	return args.Get(0).([]productEntities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ReleaseExpired() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type MockDropUsecase struct {
+	mock.Mock
+}
+
+func (m *MockDropUsecase) ScheduleDrop(id string, request *productEntities.DropRequest) (*productEntities.DropSummary, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(*productEntities.DropSummary), args.Error(1)
+}
+
+func (m *MockDropUsecase) CancelDrop(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDropUsecase) SyncDrops() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDropUsecase) CheckPurchase(userID, productID uint, quantity int) error {
+	args := m.Called(userID, productID, quantity)
+	return args.Error(0)
+}
+
+type MockQueueUsecase struct {
+	mock.Mock
+}
+
+func (m *MockQueueUsecase) ConfigureWaitingRoom(productID string, request *productEntities.WaitingRoomRequest) (*productEntities.WaitingRoom, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*productEntities.WaitingRoom), args.Error(1)
+}
+
+func (m *MockQueueUsecase) JoinQueue(productID string, userID uint) (*productEntities.QueueStatus, error) {
+	args := m.Called(productID, userID)
+	return args.Get(0).(*productEntities.QueueStatus), args.Error(1)
+}
+
+func (m *MockQueueUsecase) GetQueueStatus(productID string, token string) (*productEntities.QueueStatus, error) {
+	args := m.Called(productID, token)
+	return args.Get(0).(*productEntities.QueueStatus), args.Error(1)
+}
+
+func (m *MockQueueUsecase) AdmitNext() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueUsecase) CheckAdmission(productID string, userID uint, token string) error {
+	args := m.Called(productID, userID, token)
+	return args.Error(0)
+}
+
+type MockRiskUsecase struct {
+	mock.Mock
+}
+
+func (m *MockRiskUsecase) SetPurchaseLimits(productID string, request *productEntities.PurchaseLimitsRequest) (*productEntities.PurchaseLimits, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*productEntities.PurchaseLimits), args.Error(1)
+}
+
+func (m *MockRiskUsecase) CreateRiskRule(rule *productEntities.RiskRule) (*productEntities.RiskRule, error) {
+	args := m.Called(rule)
+	return args.Get(0).(*productEntities.RiskRule), args.Error(1)
+}
+
+func (m *MockRiskUsecase) GetRiskRules() ([]productEntities.RiskRule, error) {
+	args := m.Called()
+	return args.Get(0).([]productEntities.RiskRule), args.Error(1)
+}
+
+func (m *MockRiskUsecase) DeleteRiskRule(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRiskUsecase) GetOrderReviews(filter *productEntities.OrderReviewFilter) ([]productEntities.OrderReview, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]productEntities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskUsecase) ScreenOrder(request *productEntities.OrderScreenRequest) (*productEntities.OrderReview, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskUsecase) ApproveOrderReview(referenceID string, reviewerID uint, expiresAt time.Time) (*productEntities.OrderReview, error) {
+	args := m.Called(referenceID, reviewerID, expiresAt)
+	return args.Get(0).(*productEntities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskUsecase) RejectOrderReview(referenceID string, reviewerID uint) (*productEntities.OrderReview, error) {
+	args := m.Called(referenceID, reviewerID)
+	return args.Get(0).(*productEntities.OrderReview), args.Error(1)
+}
+
+type MockCurrencyUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyUsecase) SetRate(currency string, actorID uint, request *productEntities.ExchangeRateRequest) (*productEntities.ExchangeRate, error) {
+	args := m.Called(currency, actorID, request)
+	return args.Get(0).(*productEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) ImportRates(actorID uint, data []byte) (int, error) {
+	args := m.Called(actorID, data)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) GetRates() ([]productEntities.ExchangeRate, error) {
+	args := m.Called()
+	return args.Get(0).([]productEntities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) DeleteRate(currency string) error {
+	args := m.Called(currency)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyUsecase) Quote(currency string) (*productEntities.RateQuote, error) {
+	args := m.Called(currency)
+	return args.Get(0).(*productEntities.RateQuote), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) LocalizeProducts(currency string, products ...*productEntities.ProductResponse) error {
+	args := m.Called(currency, products)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyUsecase) LocalizeCart(currency string, pricing *productEntities.CartPricing) error {
+	args := m.Called(currency, pricing)
+	return args.Error(0)
+}
+
+type MockPromotionUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPromotionUsecase) CreateCoupon(request *productEntities.CouponRequest) (*productEntities.Coupon, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) GetCoupons() ([]productEntities.Coupon, error) {
+	args := m.Called()
+	return args.Get(0).([]productEntities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) UpdateCoupon(id string, request *productEntities.CouponRequest) (*productEntities.Coupon, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(*productEntities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) DeleteCoupon(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionUsecase) CreateCartPromotion(request *productEntities.CartPromotionRequest) (*productEntities.CartPromotion, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.CartPromotion), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) GetCartPromotions() ([]productEntities.CartPromotion, error) {
+	args := m.Called()
+	return args.Get(0).([]productEntities.CartPromotion), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) DeleteCartPromotion(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionUsecase) PriceCart(request *productEntities.CartPricingRequest) (*productEntities.CartPricing, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.CartPricing), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) RedeemCoupons(request *productEntities.CouponRedeemRequest) (*productEntities.CartPricing, error) {
+	args := m.Called(request)
+	return args.Get(0).(*productEntities.CartPricing), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) ReleaseCoupons(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
@@ -1,9 +1,21 @@
 package entities
 
 import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
 	"gorm.io/gorm"
 )
 
+const (
+	OrderPending   = "pending"
+	OrderPaid      = "paid"
+	OrderCancelled = "cancelled"
+	// OrderInReview orders were held back by the risk rules at checkout and
+	// take no payment until an admin approves them.
+	OrderInReview = "review"
+)
+
 type (
 	Cart struct {
 		gorm.Model
@@ -13,28 +25,109 @@ type (
 	}
 
 	CartItem struct {
-		CartID    uint    `gorm:"not null" json:"cart_id"`
-		ProductID uint    `gorm:"not null" json:"product_id"`
-		Quantity  int     `gorm:"not null" json:"quantity" validate:"gte=1"`
-		Price     float64 `gorm:"not null" json:"price"` // Snapshot of product price at the time of adding to cart
-	}
-
-	// Order struct {
-	// 	gorm.Model
-	// 	UserID uint `json:"user_id" gorm:"not null"`
-	// 	// OrderItems      []OrderItem `json:"order_items" gorm:"foreignkey:OrderID"`
-	// 	TotalAmount     float64 `json:"total_amount"`
-	// 	Status          string  `json:"status" gorm:"default:'pending'"`
-	// 	ShippingAddress string  `json:"shipping_address" gorm:"type:text;not null"`
-	// }
-
-	// OrderItem struct {
-	// 	gorm.Model
-	// 	OrderID   uint `json:"order_id" gorm:"not null"`
-	// 	ProductID uint `json:"product_id" gorm:"not null"`
-	// 	// ProductName string  `json:"product_name"`
-	// 	// Price       float64 `json:"price"`
-	// 	Quantity   int     `json:"quantity"`
-	// 	TotalPrice float64 `json:"total_price"`
-	// }
+		CartID    uint        `gorm:"not null" json:"cart_id"`
+		ProductID uint        `gorm:"not null" json:"product_id"`
+		VariantID *uint       `gorm:"index" json:"variant_id,omitempty"` // Set when the product is sold in variants
+		Quantity  int         `gorm:"not null" json:"quantity" validate:"gte=1"`
+		Price     money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"` // Snapshot of product (or variant) price at the time of adding to cart
+	}
+
+	Order struct {
+		gorm.Model
+		UserID uint `json:"user_id" gorm:"not null"`
+		// ReferenceID identifies the order to the product service's ledger
+		// and to payment callbacks.
+		ReferenceID string      `json:"reference_id" gorm:"type:varchar(64);uniqueIndex"`
+		OrderItems  []OrderItem `json:"order_items" gorm:"foreignkey:OrderID"`
+		// TotalAmount is what was charged, always in the settlement currency.
+		TotalAmount     money.Money `json:"total_amount" gorm:"embedded;embeddedPrefix:total_"`
+		Status          string      `json:"status" gorm:"default:'pending'"`
+		ShippingAddress string      `json:"shipping_address" gorm:"type:text;not null"`
+		// Quote is the exchange rate the customer saw prices at, kept as it
+		// was at checkout. Orders placed in the settlement currency have none.
+		Quote *RateQuote `json:"quote,omitempty" gorm:"embedded;embeddedPrefix:quote_"`
+		// Discount is what the order's coupons took off, coupon by coupon in
+		// Discounts. TotalAmount already has it taken off.
+		Discount  money.Money     `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+		Discounts []OrderDiscount `json:"discounts,omitempty" gorm:"foreignkey:OrderID"`
+		// ExpiresAt is the payment deadline of an order placed at checkout.
+		// The stock held for it is released and the order cancelled once it
+		// passes unpaid.
+		ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	}
+
+	OrderDiscount struct {
+		gorm.Model
+		OrderID uint        `json:"order_id" gorm:"not null;index"`
+		Code    string      `json:"code" gorm:"type:varchar(40);not null"`
+		Type    string      `json:"type" gorm:"type:varchar(20);not null"`
+		Amount  money.Money `json:"amount" gorm:"embedded;embeddedPrefix:amount_"`
+	}
+
+	RateQuote struct {
+		Currency string     `json:"currency" gorm:"type:varchar(3)"`
+		Rate     money.Rate `json:"rate"`
+		QuotedAt time.Time  `json:"quoted_at"`
+	}
+
+	OrderItem struct {
+		gorm.Model
+		OrderID    uint        `json:"order_id" gorm:"not null"`
+		ProductID  uint        `json:"product_id" gorm:"not null"`
+		VariantID  *uint       `json:"variant_id,omitempty"`
+		Quantity   int         `json:"quantity"`
+		TotalPrice money.Money `json:"total_price" gorm:"embedded;embeddedPrefix:total_price_"`
+		// Savings is what automatic promotions took off the item, promotion
+		// by promotion in Promotions, and Discount its share of the order's
+		// coupon discounts. TotalPrice is what is left to pay after both.
+		Savings    money.Money     `json:"savings" gorm:"embedded;embeddedPrefix:savings_"`
+		Promotions []ItemPromotion `json:"promotions,omitempty" gorm:"type:jsonb;serializer:json"`
+		Discount   money.Money     `json:"discount" gorm:"embedded;embeddedPrefix:discount_"`
+		// BlindBox is set when the product is a blind box. Each box is drawn on
+		// its own, so blind box items always have a quantity of 1.
+		BlindBox *BlindBoxDraw `json:"blind_box,omitempty" gorm:"embedded;embeddedPrefix:draw_"`
+		// BlindBoxCase is set when the product is a full case; the whole
+		// assortment is kept as one JSON document.
+		BlindBoxCase *BlindBoxCaseDraw `json:"blind_box_case,omitempty" gorm:"type:jsonb;serializer:json"`
+	}
+
+	// ItemPromotion is what one automatic promotion took off an order item,
+	// kept as it was at checkout.
+	ItemPromotion struct {
+		PromotionID uint        `json:"promotion_id"`
+		Name        string      `json:"name"`
+		Amount      money.Money `json:"amount"`
+	}
+
+	// BlindBoxDraw records which figure a blind box resolved to and the inputs
+	// needed to verify the draw once the product service reveals the seed.
+	BlindBoxDraw struct {
+		FigureID   uint   `json:"figure_id"`
+		FigureName string `json:"figure_name" gorm:"type:varchar(100)"`
+		IsSecret   bool   `json:"is_secret"`
+		SeedID     uint   `json:"seed_id"`
+		SeedHash   string `json:"seed_hash" gorm:"type:varchar(64)"`
+		ClientSeed string `json:"client_seed" gorm:"type:varchar(64)"`
+		Nonce      uint64 `json:"nonce"`
+		Roll       uint64 `json:"roll"`
+		Pool       string `json:"pool" gorm:"type:text"`
+	}
+
+	// BlindBoxCaseDraw records the figures a full case resolved to. Nonce is
+	// the first nonce used and Rolls how many followed from it.
+	BlindBoxCaseDraw struct {
+		Figures    []BlindBoxCaseSlot `json:"figures"`
+		SeedID     uint               `json:"seed_id"`
+		SeedHash   string             `json:"seed_hash"`
+		ClientSeed string             `json:"client_seed"`
+		Nonce      uint64             `json:"nonce"`
+		Rolls      uint64             `json:"rolls"`
+		Pool       string             `json:"pool"`
+	}
+
+	BlindBoxCaseSlot struct {
+		FigureID   uint   `json:"figure_id"`
+		FigureName string `json:"figure_name"`
+		IsSecret   bool   `json:"is_secret"`
+	}
 )
@@ -1 +1,75 @@
 package entities
+
+import "github.com/phetployst/art-toys-store/pkg/money"
+
+type (
+	// AddItemRequest adds units of a product, or of one of its variants, to
+	// the user's active cart. AdmissionTokens are the waiting room admission
+	// tokens sent with the request, taken from its headers.
+	AddItemRequest struct {
+		ProductID       uint     `json:"product_id" validate:"required,gt=0"`
+		VariantID       uint     `json:"variant_id,omitempty"`
+		Quantity        int      `json:"quantity" validate:"required,gte=1,lte=100"`
+		AdmissionTokens []string `json:"-"`
+	}
+
+	// CatalogItem is what the product service sells a cart line for now.
+	// BlindBox is set for blind boxes and cases, which are drawn at checkout
+	// rather than reserved.
+	CatalogItem struct {
+		Price    money.Money
+		BlindBox bool
+	}
+
+	// CheckoutRequest turns the user's active cart into a pending order.
+	// ClientSeed is mixed into every blind box draw on the order. Currency is
+	// the currency the customer was shown prices in, if not the settlement
+	// currency. Codes are the coupon codes the customer entered.
+	// AdmissionTokens are taken from the request's headers, one for each
+	// product in the cart behind a waiting room.
+	CheckoutRequest struct {
+		ShippingAddress string   `json:"shipping_address" validate:"required,max=500"`
+		ClientSeed      string   `json:"client_seed" validate:"required,max=64"`
+		Currency        string   `json:"currency,omitempty" validate:"omitempty,len=3"`
+		Codes           []string `json:"codes,omitempty" validate:"max=5,dive,required,max=40"`
+		AdmissionTokens []string `json:"-"`
+	}
+
+	// OrderScreening is what the risk rules are run against when an order is
+	// checked out: its units and the orders the user placed in the last hour
+	// and day before it.
+	OrderScreening struct {
+		UserID         uint
+		ReferenceID    string
+		Quantity       int
+		OrdersLastHour int
+		OrdersLastDay  int
+	}
+
+	// PricedCart is what the product service charges for a cart, with its
+	// promotions and coupons taken off. Items are in the cart's order.
+	PricedCart struct {
+		Items     []PricedItem
+		Discount  money.Money
+		Discounts []OrderDiscount
+		Total     money.Money
+	}
+
+	// PricedItem is what a cart line is charged. Savings is what automatic
+	// promotions took off it, promotion by promotion in Promotions, and
+	// Discount its share of the coupon discounts; Total is what is left to
+	// pay after both.
+	PricedItem struct {
+		Promotions []ItemPromotion
+		Savings    money.Money
+		Discount   money.Money
+		Total      money.Money
+	}
+
+	// BlindBoxDraws holds what the product service drew for an order's blind
+	// box and case items, one draw per unit, keyed by product ID.
+	BlindBoxDraws struct {
+		Boxes map[uint][]BlindBoxDraw
+		Cases map[uint][]BlindBoxCaseDraw
+	}
+)
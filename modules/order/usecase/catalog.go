@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+)
+
+// Catalog is what the order module needs from the product service.
+type Catalog interface {
+	// GetItem looks up what one unit of a product, or of one of its
+	// variants, sells for now.
+	GetItem(productID uint, variantID *uint) (*entities.CatalogItem, error)
+	// Reserve sets how many units of an item the user's cart holds.
+	Reserve(userID, productID uint, variantID *uint, quantity int) error
+	// HoldOrder moves the user's cart reservations for items onto the order
+	// referenced by referenceID until expiresAt, checking stock and purchase
+	// limits again. Blind boxes and cases are skipped.
+	HoldOrder(userID uint, referenceID string, items []entities.CartItem, expiresAt time.Time) error
+	// ReleaseReservations puts the stock held for the order referenced by
+	// referenceID back on sale. Stock already released or sold is skipped.
+	ReleaseReservations(referenceID string) error
+	// ConvertReservations sells the stock held for the order referenced by
+	// referenceID once it is paid. The order's shipping address and the
+	// instrument that paid count towards the purchase limits.
+	ConvertReservations(referenceID, shippingAddress, paymentFingerprint string) error
+	// ScreenOrder runs an order being checked out past the risk rules. It
+	// reports whether they held the order for review, with the stock held
+	// for it set aside; an order they reject fails with "order rejected".
+	ScreenOrder(screening *entities.OrderScreening) (bool, error)
+	// ApproveReview settles the review of the order referenced by
+	// referenceID as approved and holds its stock again until expiresAt.
+	ApproveReview(referenceID string, reviewerID uint, expiresAt time.Time) error
+	// RejectReview settles the review of the order referenced by
+	// referenceID as rejected and puts its stock back on sale.
+	RejectReview(referenceID string, reviewerID uint) error
+	// CheckAdmission checks that token admits the user to a product behind
+	// an active waiting room. Products without one let everyone through.
+	CheckAdmission(userID, productID uint, token string) error
+	// CheckPurchase checks that the user's cart may hold quantity units of a
+	// product that is not reserved, under its drop rules.
+	CheckPurchase(userID, productID uint, quantity int) error
+	// DrawBlindBoxes draws every blind box and case unit among items for the
+	// order referenced by referenceID. Items that are neither have no entry.
+	DrawBlindBoxes(items []entities.CartItem, clientSeed, referenceID string) (*entities.BlindBoxDraws, error)
+	// ReturnDraws puts back into their pools the blind boxes and cases drawn
+	// for the order referenced by referenceID. Draws already returned are
+	// skipped.
+	ReturnDraws(referenceID string) error
+	// RedeemCoupons prices items for the order referenced by referenceID and
+	// takes a use of each of the coupons codes name for it.
+	RedeemCoupons(userID uint, referenceID string, codes []string, items []entities.CartItem) (*entities.PricedCart, error)
+	// ReleaseCoupons gives back the coupon uses taken for the order
+	// referenced by referenceID.
+	ReleaseCoupons(referenceID string) error
+	// Quote is the exchange rate prices are shown at in currency right now.
+	// The settlement currency has no quote.
+	Quote(currency string) (*entities.RateQuote, error)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// OrderSweeper cancels checkout orders left unpaid past their payment
+// deadline in the background, so the stock they hold goes back on sale.
+type OrderSweeper struct {
+	usecase  OrderUsecase
+	interval time.Duration
+}
+
+func NewOrderSweeper(usecase OrderUsecase, interval time.Duration) *OrderSweeper {
+	return &OrderSweeper{usecase, interval}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (s *OrderSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+func (s *OrderSweeper) sweep() {
+	cancelled, err := s.usecase.CancelExpiredOrders()
+	if err != nil {
+		log.Printf("failed to cancel expired orders: %v", err)
+	}
+
+	if cancelled > 0 {
+		log.Printf("cancelled %d expired orders", cancelled)
+	}
+}
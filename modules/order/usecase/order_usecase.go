@@ -1,20 +1,600 @@
 package usecase
 
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+)
+
+const (
+	// paymentWindow is how long a checkout order holds its stock while it
+	// waits to be paid.
+	paymentWindow     = 30 * time.Minute
+	expiredOrderBatch = 100
+)
+
 type OrderUsecase interface {
+	AddItemToCart(userID uint, request *entities.AddItemRequest) (*entities.Cart, error)
+	Checkout(userID uint, request *entities.CheckoutRequest) (*entities.Order, error)
+	PlaceOrder(userID uint, items []entities.OrderItem) (*entities.Order, error)
+	SettleOrder(referenceID string, amount money.Money) (*entities.Order, error)
+	CancelOrder(userID uint, referenceID string) (*entities.Order, error)
+	ConfirmPayment(confirmation *payment.Confirmation) (*entities.Order, error)
+	CancelExpiredOrders() (int, error)
+	ApproveOrder(referenceID string, reviewerID uint) (*entities.Order, error)
+	RejectOrder(referenceID string, reviewerID uint) (*entities.Order, error)
 }
 
 type OrderService struct {
-	repo OrderRepository
+	repo    OrderRepository
+	catalog Catalog
+}
+
+func NewOrderService(repo OrderRepository, catalog Catalog) OrderUsecase {
+	return &OrderService{repo, catalog}
+}
+
+// AddItemToCart adds units of a product to the user's active cart at the price
+// it sells for now. A product behind an active waiting room is only added for
+// a user it admitted. The cart's units of the item are held with a stock
+// reservation before the line is written; blind boxes and cases are drawn
+// from their pools at checkout, so they are not reserved, only checked
+// against their drop rules.
+func (s *OrderService) AddItemToCart(userID uint, request *entities.AddItemRequest) (*entities.Cart, error) {
+	if err := s.checkAdmission(userID, request.ProductID, request.AdmissionTokens); err != nil {
+		return nil, err
+	}
+
+	var variantID *uint
+	if request.VariantID != 0 {
+		variantID = &request.VariantID
+	}
+
+	item, err := s.catalog.GetItem(request.ProductID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := s.cartQuantity(userID, request.ProductID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.BlindBox {
+		err = s.catalog.CheckPurchase(userID, request.ProductID, held+request.Quantity)
+	} else {
+		err = s.catalog.Reserve(userID, request.ProductID, variantID, held+request.Quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.InsertItemToCart(userID, request.ProductID, variantID, request.Quantity, item.Price); err != nil {
+		if !item.BlindBox {
+			s.catalog.Reserve(userID, request.ProductID, variantID, held)
+		}
+		return nil, errors.New("database error")
+	}
+
+	cart, err := s.repo.GetActiveCart(userID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return cart, nil
+}
+
+// checkAdmission lets the user through to a product when it has no active
+// waiting room or one of tokens admits them to it. Each token is only valid
+// for the product it was issued for.
+func (s *OrderService) checkAdmission(userID, productID uint, tokens []string) error {
+	if len(tokens) == 0 {
+		tokens = []string{""}
+	}
+
+	var err error
+	for _, token := range tokens {
+		if err = s.catalog.CheckAdmission(userID, productID, token); err == nil || err.Error() != "admission required" {
+			return err
+		}
+	}
+
+	return err
+}
+
+// cartQuantity is how many units of an item the user's active cart has.
+func (s *OrderService) cartQuantity(userID, productID uint, variantID *uint) (int, error) {
+	cart, err := s.repo.GetActiveCart(userID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			return 0, nil
+		}
+		return 0, errors.New("database error")
+	}
+
+	quantity := 0
+	for _, item := range cart.CartItem {
+		if item.ProductID == productID && sameVariant(item.VariantID, variantID) {
+			quantity += item.Quantity
+		}
+	}
+
+	return quantity, nil
+}
+
+// Checkout places the user's active cart as a pending order. Every product in
+// the cart behind an active waiting room needs the user's admission to it
+// again, as admissions expire while carts do not. The cart's
+// reservations are moved onto the order until its payment deadline, with
+// stock checked again, so the order only sells what it holds. Blind boxes and
+// cases are drawn here, so each unit becomes an order item of its own
+// carrying its draw. The exchange rate of the currency the customer shopped in
+// is kept on the order as it was quoted now.
+//
+// The cart is priced again with its coupons first, which takes a use of each
+// coupon for the order, so a coupon's last use goes to the first checkout to
+// start rather than the first to pay. The stock held, the uses and the blind
+// boxes drawn for the order are given back if the order is not placed.
+func (s *OrderService) Checkout(userID uint, request *entities.CheckoutRequest) (*entities.Order, error) {
+	cart, err := s.repo.GetActiveCart(userID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	if len(cart.CartItem) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	admitted := make(map[uint]bool, len(cart.CartItem))
+	for _, item := range cart.CartItem {
+		if admitted[item.ProductID] {
+			continue
+		}
+		if err := s.checkAdmission(userID, item.ProductID, request.AdmissionTokens); err != nil {
+			return nil, err
+		}
+		admitted[item.ProductID] = true
+	}
+
+	var quote *entities.RateQuote
+	if request.Currency != "" {
+		if quote, err = s.catalog.Quote(request.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	referenceID, err := newReferenceID()
+	if err != nil {
+		return nil, errors.New("failed to create order reference")
+	}
+
+	expiresAt := time.Now().Add(paymentWindow)
+	if err := s.catalog.HoldOrder(userID, referenceID, cart.CartItem, expiresAt); err != nil {
+		return nil, err
+	}
+
+	held, err := s.screenOrder(userID, referenceID, cart.CartItem)
+	if err != nil {
+		s.abandonOrder(referenceID)
+		return nil, err
+	}
+
+	pricing, err := s.catalog.RedeemCoupons(userID, referenceID, request.Codes, cart.CartItem)
+	if err != nil {
+		s.abandonOrder(referenceID)
+		return nil, err
+	}
+
+	draws, err := s.catalog.DrawBlindBoxes(cart.CartItem, request.ClientSeed, referenceID)
+	if err != nil {
+		s.abandonOrder(referenceID)
+		return nil, err
+	}
+
+	order := &entities.Order{
+		UserID:          userID,
+		ReferenceID:     referenceID,
+		TotalAmount:     pricing.Total,
+		Status:          entities.OrderPending,
+		ShippingAddress: request.ShippingAddress,
+		Quote:           quote,
+		Discount:        pricing.Discount,
+		Discounts:       pricing.Discounts,
+		ExpiresAt:       &expiresAt,
+	}
+	if held {
+		order.Status, order.ExpiresAt = entities.OrderInReview, nil
+	}
+
+	for i, item := range cart.CartItem {
+		priced := pricing.Items[i]
+		boxes, cases := draws.Boxes[item.ProductID], draws.Cases[item.ProductID]
+		switch {
+		case len(boxes) > 0:
+			units := splitPricedItem(priced, len(boxes))
+			for i := range boxes {
+				order.OrderItems = append(order.OrderItems, entities.OrderItem{
+					ProductID:  item.ProductID,
+					Quantity:   1,
+					TotalPrice: units[i].Total,
+					Savings:    units[i].Savings,
+					Promotions: units[i].Promotions,
+					Discount:   units[i].Discount,
+					BlindBox:   &boxes[i],
+				})
+			}
+		case len(cases) > 0:
+			units := splitPricedItem(priced, len(cases))
+			for i := range cases {
+				order.OrderItems = append(order.OrderItems, entities.OrderItem{
+					ProductID:    item.ProductID,
+					Quantity:     1,
+					TotalPrice:   units[i].Total,
+					Savings:      units[i].Savings,
+					Promotions:   units[i].Promotions,
+					Discount:     units[i].Discount,
+					BlindBoxCase: &cases[i],
+				})
+			}
+		default:
+			order.OrderItems = append(order.OrderItems, entities.OrderItem{
+				ProductID:  item.ProductID,
+				VariantID:  item.VariantID,
+				Quantity:   item.Quantity,
+				TotalPrice: priced.Total,
+				Savings:    priced.Savings,
+				Promotions: priced.Promotions,
+				Discount:   priced.Discount,
+			})
+		}
+	}
+
+	newOrder, err := s.repo.InsertOrder(order, cart.ID)
+	if err != nil {
+		s.abandonOrder(referenceID)
+		return nil, errors.New("database error")
+	}
+
+	return newOrder, nil
 }
 
-func NewOrderService(repo OrderRepository) OrderUsecase {
-	return &OrderService{repo}
+// screenOrder runs the order referenced by referenceID past the risk rules
+// with the user's recent orders, and reports whether it was held for review.
+func (s *OrderService) screenOrder(userID uint, referenceID string, items []entities.CartItem) (bool, error) {
+	screening := &entities.OrderScreening{UserID: userID, ReferenceID: referenceID}
+	for _, item := range items {
+		screening.Quantity += item.Quantity
+	}
+
+	now := time.Now()
+	lastHour, err := s.repo.CountOrdersSince(userID, now.Add(-time.Hour))
+	if err != nil {
+		return false, errors.New("database error")
+	}
+
+	lastDay, err := s.repo.CountOrdersSince(userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return false, errors.New("database error")
+	}
+
+	screening.OrdersLastHour, screening.OrdersLastDay = int(lastHour), int(lastDay)
+	return s.catalog.ScreenOrder(screening)
+}
+
+// PlaceOrder opens a pending order for items another service has already
+// priced and set aside, such as a raffle win, without going through the cart.
+func (s *OrderService) PlaceOrder(userID uint, items []entities.OrderItem) (*entities.Order, error) {
+	if len(items) == 0 {
+		return nil, errors.New("order has no items")
+	}
+
+	referenceID, err := newReferenceID()
+	if err != nil {
+		return nil, errors.New("failed to create order reference")
+	}
+
+	total := money.New(0, items[0].TotalPrice.Currency)
+	for _, item := range items {
+		if total, err = total.Add(item.TotalPrice); err != nil {
+			return nil, errors.New("currency mismatch")
+		}
+	}
+
+	order, err := s.repo.CreateOrder(&entities.Order{
+		UserID:      userID,
+		ReferenceID: referenceID,
+		OrderItems:  items,
+		TotalAmount: total,
+		Status:      entities.OrderPending,
+	})
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return order, nil
+}
+
+// SettleOrder marks the order referenced by referenceID as paid once amount,
+// which must be the order's total, has been confirmed. A settlement repeated
+// for a paid order changes nothing.
+func (s *OrderService) SettleOrder(referenceID string, amount money.Money) (*entities.Order, error) {
+	order, err := s.getOrder(referenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == entities.OrderPaid {
+		return order, nil
+	}
+	if order.Status != entities.OrderPending {
+		return nil, errors.New("order is not pending")
+	}
+	if amount != order.TotalAmount {
+		return nil, errors.New("payment amount does not match")
+	}
+
+	return s.moveOrder(order, entities.OrderPaid)
 }
 
-func (s *OrderService) AddItemToCart() {
+// ConfirmPayment settles the order the payment provider confirms was paid and
+// sells it the stock held for it. The order's stored shipping address and the
+// instrument that paid count towards the purchase limits. Payment is refused
+// once the order's deadline has passed, as its stock may be back on sale. A
+// confirmation repeated for a paid order changes nothing.
+func (s *OrderService) ConfirmPayment(confirmation *payment.Confirmation) (*entities.Order, error) {
+	order, err := s.getOrder(confirmation.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == entities.OrderPaid {
+		return order, nil
+	}
+	if order.Status != entities.OrderPending {
+		return nil, errors.New("order is not pending")
+	}
+	if order.ExpiresAt != nil && !time.Now().Before(*order.ExpiresAt) {
+		return nil, errors.New("payment deadline has passed")
+	}
+	if confirmation.Amount != order.TotalAmount {
+		return nil, errors.New("payment amount does not match")
+	}
+
+	if holdsStock(order) {
+		if err := s.catalog.ConvertReservations(order.ReferenceID, order.ShippingAddress, confirmation.PaymentFingerprint); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.moveOrder(order, entities.OrderPaid)
+}
+
+// CancelOrder cancels one of the user's orders while it is still pending or in
+// review and gives back its stock, blind box draws and coupon uses. A cancellation repeated for
+// a cancelled order gives back whatever the first failed to.
+func (s *OrderService) CancelOrder(userID uint, referenceID string) (*entities.Order, error) {
+	order, err := s.getOrder(referenceID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errors.New("order not found")
+	}
+
+	if order.Status != entities.OrderCancelled {
+		if order, err = s.moveOrder(order, entities.OrderCancelled); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.releaseOrder(referenceID); err != nil {
+		log.Printf("failed to release order %s: %v", referenceID, err)
+		return nil, errors.New("database error")
+	}
+
+	return order, nil
+}
+
+// CancelExpiredOrders cancels every order left unpaid past its payment
+// deadline, in batches, and returns how many were cancelled. An order that
+// fails is logged and tried again on the next run.
+func (s *OrderService) CancelExpiredOrders() (int, error) {
+	total := 0
+
+	for {
+		orders, err := s.repo.GetExpiredOrders(time.Now(), expiredOrderBatch)
+		if err != nil {
+			return total, errors.New("database error")
+		}
+
+		failed := 0
+		for _, order := range orders {
+			if _, err := s.CancelOrder(order.UserID, order.ReferenceID); err != nil {
+				log.Printf("failed to cancel expired order %s: %v", order.ReferenceID, err)
+				failed++
+				continue
+			}
+			total++
+		}
+
+		// A full batch of failures would be listed again as it is.
+		if len(orders) < expiredOrderBatch || failed == len(orders) {
+			return total, nil
+		}
+	}
+}
+
+// ApproveOrder lets an order held for review through to payment. Its stock is
+// held for it again and it gets a new payment deadline.
+func (s *OrderService) ApproveOrder(referenceID string, reviewerID uint) (*entities.Order, error) {
+	order, err := s.getOrder(referenceID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != entities.OrderInReview {
+		return nil, errors.New("order is not in review")
+	}
+
+	expiresAt := time.Now().Add(paymentWindow)
+	if err := s.catalog.ApproveReview(referenceID, reviewerID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	reopened, err := s.repo.ReopenOrder(referenceID, expiresAt)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if !reopened {
+		return nil, errors.New("order is not in review")
+	}
+
+	order.Status, order.ExpiresAt = entities.OrderPending, &expiresAt
+	return order, nil
+}
+
+// RejectOrder turns away an order held for review. It is cancelled and its
+// stock, blind box draws and coupon uses are given back.
+func (s *OrderService) RejectOrder(referenceID string, reviewerID uint) (*entities.Order, error) {
+	order, err := s.getOrder(referenceID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != entities.OrderInReview {
+		return nil, errors.New("order is not in review")
+	}
+
+	if err := s.catalog.RejectReview(referenceID, reviewerID); err != nil {
+		return nil, err
+	}
+
+	if order, err = s.moveOrder(order, entities.OrderCancelled); err != nil {
+		return nil, err
+	}
+
+	if err := s.releaseOrder(referenceID); err != nil {
+		log.Printf("failed to release order %s: %v", referenceID, err)
+		return nil, errors.New("database error")
+	}
+
+	return order, nil
+}
+
+// releaseOrder gives back what the order referenced by referenceID took from
+// the catalogue: its reserved stock, its blind box draws and its coupon uses.
+// Each is safe to repeat.
+func (s *OrderService) releaseOrder(referenceID string) error {
+	if err := s.catalog.ReleaseReservations(referenceID); err != nil {
+		return err
+	}
+
+	if err := s.catalog.ReturnDraws(referenceID); err != nil {
+		return err
+	}
+
+	return s.catalog.ReleaseCoupons(referenceID)
+}
+
+// abandonOrder releases an order checkout could not place. Failures are only
+// logged, as the checkout has already failed.
+func (s *OrderService) abandonOrder(referenceID string) {
+	if err := s.releaseOrder(referenceID); err != nil {
+		log.Printf("failed to release order %s: %v", referenceID, err)
+	}
+}
+
+func (s *OrderService) getOrder(referenceID string) (*entities.Order, error) {
+	order, err := s.repo.GetOrderByReference(referenceID)
+	if err != nil {
+		if err.Error() == "order not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return order, nil
+}
+
+// moveOrder moves an unpaid order, pending or in review, to status.
+func (s *OrderService) moveOrder(order *entities.Order, status string) (*entities.Order, error) {
+	if order.Status != entities.OrderPending && order.Status != entities.OrderInReview {
+		return nil, errors.New("order is not pending")
+	}
+
+	moved, err := s.repo.UpdateOrderStatus(order.ReferenceID, order.Status, status)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if !moved {
+		return nil, errors.New("order is not pending")
+	}
+
+	order.Status = status
+	return order, nil
+}
+
+// splitPricedItem splits a cart line's amounts, and what each of its
+// promotions took off, evenly across its units, for lines whose units become
+// order items of their own. A unit keeps only the promotions that took
+// something off it.
+func splitPricedItem(item entities.PricedItem, units int) []entities.PricedItem {
+	weights := make([]int64, units)
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	totals, savings, discounts := item.Total.Allocate(weights...), item.Savings.Allocate(weights...), item.Discount.Allocate(weights...)
+
+	split := make([]entities.PricedItem, units)
+	for i := range split {
+		split[i] = entities.PricedItem{Savings: savings[i], Discount: discounts[i], Total: totals[i]}
+	}
+
+	for _, promotion := range item.Promotions {
+		for i, amount := range promotion.Amount.Allocate(weights...) {
+			if amount.IsPositive() {
+				split[i].Promotions = append(split[i].Promotions, entities.ItemPromotion{PromotionID: promotion.PromotionID, Name: promotion.Name, Amount: amount})
+			}
+		}
+	}
+
+	return split
+}
+
+// newReferenceID returns a random reference for a new order.
+func newReferenceID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// holdsStock reports whether any of the order's items is sold from reserved
+// stock rather than drawn from a blind box pool.
+func holdsStock(order *entities.Order) bool {
+	for _, item := range order.OrderItems {
+		if item.BlindBox == nil && item.BlindBoxCase == nil {
+			return true
+		}
+	}
+
+	return false
+}
 
-	// ตรวจสอบว่าสินค้ามีอยู่จริงและมีสต็อกเพียงพอหรือไม่
-	// get by id product service
+func sameVariant(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
 
-	// เพิ่มสินค้าลงในตระกร้า
+	return *a == *b
 }
@@ -0,0 +1,1070 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func activeCart() *entities.Cart {
+	return &entities.Cart{
+		Model:  gorm.Model{ID: 4},
+		UserID: 7,
+		Status: "active",
+		CartItem: []entities.CartItem{
+			{CartID: 4, ProductID: 1, Quantity: 2, Price: money.New(129000, "THB")},
+			{CartID: 4, ProductID: 20, Quantity: 2, Price: money.New(45000, "THB")},
+			{CartID: 4, ProductID: 30, Quantity: 1, Price: money.New(540000, "THB")},
+		},
+	}
+}
+
+// firstCall is the first call made to a mock's method.
+func firstCall(m *mock.Mock, method string) mock.Call {
+	for _, call := range m.Calls {
+		if call.Method == method {
+			return call
+		}
+	}
+	panic("no call to " + method)
+}
+
+func TestAddItemToCart(t *testing.T) {
+	t.Run("add item reserves the cart's units and snapshots the price", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		variantID := uint(3)
+		cart := &entities.Cart{Model: gorm.Model{ID: 4}, UserID: 7, Status: "active", CartItem: []entities.CartItem{
+			{CartID: 4, ProductID: 1, VariantID: &variantID, Quantity: 1, Price: money.New(62000, "THB")},
+			{CartID: 4, ProductID: 1, Quantity: 5, Price: money.New(59000, "THB")},
+		}}
+
+		mockCatalog.On("GetItem", uint(1), &variantID).Return(&entities.CatalogItem{Price: money.New(62000, "THB")}, nil)
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("Reserve", uint(7), uint(1), &variantID, 3).Return(nil)
+		mockRepo.On("InsertItemToCart", uint(7), uint(1), &variantID, 2, money.New(62000, "THB")).Return(nil)
+
+		got, err := orderService.AddItemToCart(7, &entities.AddItemRequest{ProductID: 1, VariantID: 3, Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, cart, got)
+		mockCatalog.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("add blind box checks its drop rules instead of reserving", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := &entities.Cart{Model: gorm.Model{ID: 4}, UserID: 7, Status: "active", CartItem: []entities.CartItem{{CartID: 4, ProductID: 20, Quantity: 2, Price: money.New(45000, "THB")}}}
+
+		mockCatalog.On("GetItem", uint(20), (*uint)(nil)).Return(&entities.CatalogItem{Price: money.New(45000, "THB"), BlindBox: true}, nil)
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("CheckPurchase", uint(7), uint(20), 4).Return(nil)
+		mockRepo.On("InsertItemToCart", uint(7), uint(20), (*uint)(nil), 2, money.New(45000, "THB")).Return(nil)
+
+		got, err := orderService.AddItemToCart(7, &entities.AddItemRequest{ProductID: 20, Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, cart, got)
+		mockCatalog.AssertExpectations(t)
+		mockCatalog.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("add blind box given purchase limit exceeded", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		mockCatalog.On("GetItem", uint(20), (*uint)(nil)).Return(&entities.CatalogItem{Price: money.New(45000, "THB"), BlindBox: true}, nil)
+		mockRepo.On("GetActiveCart", uint(7)).Return((*entities.Cart)(nil), errors.New("cart not found"))
+		mockCatalog.On("CheckPurchase", uint(7), uint(20), 3).Return(errors.New("purchase limit exceeded"))
+
+		_, err := orderService.AddItemToCart(7, &entities.AddItemRequest{ProductID: 20, Quantity: 3})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+		mockRepo.AssertNotCalled(t, "InsertItemToCart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("add item given insufficient stock", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		mockCatalog.On("GetItem", uint(1), (*uint)(nil)).Return(&entities.CatalogItem{Price: money.New(59000, "THB")}, nil)
+		mockRepo.On("GetActiveCart", uint(7)).Return((*entities.Cart)(nil), errors.New("cart not found"))
+		mockCatalog.On("Reserve", uint(7), uint(1), (*uint)(nil), 20).Return(errors.New("insufficient stock"))
+
+		_, err := orderService.AddItemToCart(7, &entities.AddItemRequest{ProductID: 1, Quantity: 20})
+
+		assert.EqualError(t, err, "insufficient stock")
+		mockRepo.AssertNotCalled(t, "InsertItemToCart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("add item given database error releases the new hold", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		mockCatalog.On("GetItem", uint(1), (*uint)(nil)).Return(&entities.CatalogItem{Price: money.New(59000, "THB")}, nil)
+		mockRepo.On("GetActiveCart", uint(7)).Return((*entities.Cart)(nil), errors.New("cart not found"))
+		mockCatalog.On("Reserve", uint(7), uint(1), (*uint)(nil), 2).Return(nil)
+		mockRepo.On("InsertItemToCart", uint(7), uint(1), (*uint)(nil), 2, money.New(59000, "THB")).Return(errors.New("database error"))
+		mockCatalog.On("Reserve", uint(7), uint(1), (*uint)(nil), 0).Return(nil)
+
+		_, err := orderService.AddItemToCart(7, &entities.AddItemRequest{ProductID: 1, Quantity: 2})
+
+		assert.EqualError(t, err, "database error")
+		mockCatalog.AssertExpectations(t)
+	})
+
+	t.Run("add a drop without admission", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockCatalog.On("CheckAdmission", uint(7), uint(5), "token-for-1").Return(errors.New("admission required"))
+		mockCatalog.On("CheckAdmission", uint(7), uint(5), "stale-token").Return(errors.New("admission required"))
+
+		_, err := orderService.AddItemToCart(7, &entities.AddItemRequest{ProductID: 5, Quantity: 1, AdmissionTokens: []string{"token-for-1", "stale-token"}})
+
+		assert.EqualError(t, err, "admission required")
+		mockCatalog.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "InsertItemToCart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCheckout(t *testing.T) {
+	t.Run("checkout draws each blind box and case onto its own item with its share of the discount", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := activeCart()
+		draws := &entities.BlindBoxDraws{
+			Boxes: map[uint][]entities.BlindBoxDraw{20: {
+				{FigureID: 10, FigureName: "Labubu Sea Salt", SeedID: 1, ClientSeed: "order-42", Nonce: 7},
+				{FigureID: 12, FigureName: "Chestnut Cocoa", IsSecret: true, SeedID: 1, ClientSeed: "order-42", Nonce: 8},
+			}},
+			Cases: map[uint][]entities.BlindBoxCaseDraw{30: {
+				{Figures: []entities.BlindBoxCaseSlot{{FigureID: 10, FigureName: "Labubu Sea Salt"}, {FigureID: 11, FigureName: "Lychee Berry"}}, SeedID: 1, ClientSeed: "order-42", Nonce: 9, Rolls: 2},
+			}},
+		}
+
+		discounts := []entities.OrderDiscount{{Code: "WELCOME", Type: "percentage", Amount: money.New(71401, "THB")}}
+		pricing := &entities.PricedCart{
+			Items: []entities.PricedItem{
+				{Discount: money.New(12900, "THB"), Total: money.New(245100, "THB")},
+				{Discount: money.New(4501, "THB"), Total: money.New(85499, "THB")},
+				{Discount: money.New(54000, "THB"), Total: money.New(486000, "THB")},
+			},
+			Discount:  money.New(71401, "THB"),
+			Discounts: discounts,
+			Total:     money.New(816599, "THB"),
+		}
+
+		want := &entities.Order{
+			UserID:          7,
+			Status:          entities.OrderPending,
+			ShippingAddress: "1 Sukhumvit Rd",
+			TotalAmount:     money.New(816599, "THB"),
+			Discount:        money.New(71401, "THB"),
+			Discounts:       discounts,
+			OrderItems: []entities.OrderItem{
+				{ProductID: 1, Quantity: 2, TotalPrice: money.New(245100, "THB"), Discount: money.New(12900, "THB")},
+				{ProductID: 20, Quantity: 1, TotalPrice: money.New(42750, "THB"), Discount: money.New(2251, "THB"), BlindBox: &draws.Boxes[20][0]},
+				{ProductID: 20, Quantity: 1, TotalPrice: money.New(42749, "THB"), Discount: money.New(2250, "THB"), BlindBox: &draws.Boxes[20][1]},
+				{ProductID: 30, Quantity: 1, TotalPrice: money.New(486000, "THB"), Discount: money.New(54000, "THB"), BlindBoxCase: &draws.Cases[30][0]},
+			},
+		}
+
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string{"WELCOME"}, cart.CartItem).Return(pricing, nil)
+		mockCatalog.On("DrawBlindBoxes", cart.CartItem, "order-42", mock.AnythingOfType("string")).Return(draws, nil)
+		mockRepo.On("InsertOrder", mock.Anything, uint(4)).Return(want, nil)
+
+		got, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Codes: []string{"WELCOME"}})
+
+		inserted := firstCall(&mockRepo.Mock, "InsertOrder").Arguments.Get(0).(*entities.Order)
+		referenceID := firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1)
+		expiresAt := firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.Get(3).(time.Time)
+		assert.Equal(t, referenceID, firstCall(&mockCatalog.Mock, "RedeemCoupons").Arguments.String(1))
+		assert.Equal(t, referenceID, firstCall(&mockCatalog.Mock, "DrawBlindBoxes").Arguments.String(2))
+		assert.NoError(t, err)
+		assert.Len(t, referenceID, 32)
+		assert.Equal(t, referenceID, inserted.ReferenceID)
+		assert.WithinDuration(t, time.Now().Add(paymentWindow), expiresAt, time.Minute)
+		want.ReferenceID = referenceID
+		want.ExpiresAt = &expiresAt
+		assert.Equal(t, want, inserted)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("checkout keeps what each promotion took off each item", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := &entities.Cart{Model: gorm.Model{ID: 4}, UserID: 7, Status: "active", CartItem: []entities.CartItem{
+			{CartID: 4, ProductID: 1, Quantity: 2, Price: money.New(129000, "THB")},
+			{CartID: 4, ProductID: 20, Quantity: 2, Price: money.New(45000, "THB")},
+		}}
+		draws := &entities.BlindBoxDraws{Boxes: map[uint][]entities.BlindBoxDraw{20: {
+			{FigureID: 10, FigureName: "Labubu Sea Salt", SeedID: 1, ClientSeed: "order-42", Nonce: 7},
+			{FigureID: 12, FigureName: "Chestnut Cocoa", SeedID: 1, ClientSeed: "order-42", Nonce: 8},
+		}}}
+		pricing := &entities.PricedCart{
+			Items: []entities.PricedItem{
+				{Promotions: []entities.ItemPromotion{{PromotionID: 2, Name: "Labubu bundle", Amount: money.New(10000, "THB")}}, Savings: money.New(10000, "THB"), Total: money.New(248000, "THB")},
+				{Promotions: []entities.ItemPromotion{{PromotionID: 1, Name: "Buy 1 get 1", Amount: money.New(45000, "THB")}}, Savings: money.New(45000, "THB"), Total: money.New(45000, "THB")},
+			},
+			Total: money.New(293000, "THB"),
+		}
+
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string(nil), cart.CartItem).Return(pricing, nil)
+		mockCatalog.On("DrawBlindBoxes", cart.CartItem, "order-42", mock.AnythingOfType("string")).Return(draws, nil)
+		mockRepo.On("InsertOrder", mock.Anything, uint(4)).Return(&entities.Order{}, nil)
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42"})
+
+		want := []entities.OrderItem{
+			{ProductID: 1, Quantity: 2, TotalPrice: money.New(248000, "THB"), Savings: money.New(10000, "THB"),
+				Promotions: []entities.ItemPromotion{{PromotionID: 2, Name: "Labubu bundle", Amount: money.New(10000, "THB")}}},
+			{ProductID: 20, Quantity: 1, TotalPrice: money.New(22500, "THB"), Savings: money.New(22500, "THB"),
+				Promotions: []entities.ItemPromotion{{PromotionID: 1, Name: "Buy 1 get 1", Amount: money.New(22500, "THB")}}, BlindBox: &draws.Boxes[20][0]},
+			{ProductID: 20, Quantity: 1, TotalPrice: money.New(22500, "THB"), Savings: money.New(22500, "THB"),
+				Promotions: []entities.ItemPromotion{{PromotionID: 1, Name: "Buy 1 get 1", Amount: money.New(22500, "THB")}}, BlindBox: &draws.Boxes[20][1]},
+		}
+
+		inserted := firstCall(&mockRepo.Mock, "InsertOrder").Arguments.Get(0).(*entities.Order)
+		assert.NoError(t, err)
+		assert.Equal(t, want, inserted.OrderItems)
+		assert.Equal(t, money.New(293000, "THB"), inserted.TotalAmount)
+	})
+
+	t.Run("checkout keeps the quote of the customer's currency", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := &entities.Cart{Model: gorm.Model{ID: 4}, UserID: 7, Status: "active", CartItem: []entities.CartItem{
+			{CartID: 4, ProductID: 1, Quantity: 2, Price: money.New(129000, "THB")},
+		}}
+		quote := &entities.RateQuote{Currency: "USD", Rate: 27350, QuotedAt: time.Now()}
+
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("Quote", "USD").Return(quote, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string(nil), cart.CartItem).
+			Return(&entities.PricedCart{Items: []entities.PricedItem{{Total: money.New(258000, "THB")}}, Total: money.New(258000, "THB")}, nil)
+		mockCatalog.On("DrawBlindBoxes", cart.CartItem, "order-42", mock.AnythingOfType("string")).Return(&entities.BlindBoxDraws{}, nil)
+		mockRepo.On("InsertOrder", mock.MatchedBy(func(o *entities.Order) bool {
+			return o.Quote == quote && o.TotalAmount == money.New(258000, "THB")
+		}), uint(4)).Return(&entities.Order{Quote: quote}, nil)
+
+		got, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Currency: "USD"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, quote, got.Quote)
+	})
+
+	t.Run("checkout given unsupported currency", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		mockRepo.On("GetActiveCart", uint(7)).Return(activeCart(), nil)
+		mockCatalog.On("Quote", "EUR").Return((*entities.RateQuote)(nil), errors.New("currency not supported"))
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Currency: "EUR"})
+
+		assert.EqualError(t, err, "currency not supported")
+		mockCatalog.AssertNotCalled(t, "DrawBlindBoxes", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout given empty cart", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		mockRepo.On("GetActiveCart", uint(7)).Return(&entities.Cart{Model: gorm.Model{ID: 4}, UserID: 7, Status: "active"}, nil)
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42"})
+
+		assert.EqualError(t, err, "cart is empty")
+		mockCatalog.AssertNotCalled(t, "DrawBlindBoxes", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout given sold out blind box", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := activeCart()
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string{"WELCOME"}, cart.CartItem).Return(&entities.PricedCart{}, nil)
+		mockCatalog.On("DrawBlindBoxes", cart.CartItem, "order-42", mock.AnythingOfType("string")).Return((*entities.BlindBoxDraws)(nil), errors.New("blind box sold out"))
+		mockCatalog.On("ReleaseReservations", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReturnDraws", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReleaseCoupons", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Codes: []string{"WELCOME"}})
+
+		assert.EqualError(t, err, "blind box sold out")
+		referenceID := firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1)
+		mockCatalog.AssertCalled(t, "ReleaseReservations", referenceID)
+		mockCatalog.AssertCalled(t, "ReleaseCoupons", referenceID)
+		mockRepo.AssertNotCalled(t, "InsertOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout given last use of a coupon taken releases the held stock", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := activeCart()
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string{"WELCOME"}, cart.CartItem).Return((*entities.PricedCart)(nil), errors.New("coupon usage limit reached"))
+		mockCatalog.On("ReleaseReservations", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReturnDraws", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReleaseCoupons", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Codes: []string{"WELCOME"}})
+
+		assert.EqualError(t, err, "coupon usage limit reached")
+		mockCatalog.AssertNotCalled(t, "DrawBlindBoxes", mock.Anything, mock.Anything, mock.Anything)
+		mockCatalog.AssertCalled(t, "ReleaseReservations", firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1))
+	})
+
+	t.Run("checkout given stock sold since the cart was filled", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := activeCart()
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(errors.New("insufficient stock"))
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42"})
+
+		assert.EqualError(t, err, "insufficient stock")
+		mockCatalog.AssertNotCalled(t, "RedeemCoupons", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "InsertOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout given the order cannot be saved gives the stock, draws and coupons back", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := &entities.Cart{Model: gorm.Model{ID: 4}, UserID: 7, Status: "active", CartItem: []entities.CartItem{
+			{CartID: 4, ProductID: 1, Quantity: 2, Price: money.New(129000, "THB")},
+		}}
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string{"WELCOME"}, cart.CartItem).
+			Return(&entities.PricedCart{Items: []entities.PricedItem{{Total: money.New(245100, "THB")}}, Total: money.New(245100, "THB")}, nil)
+		mockCatalog.On("DrawBlindBoxes", cart.CartItem, "order-42", mock.AnythingOfType("string")).Return(&entities.BlindBoxDraws{}, nil)
+		mockRepo.On("InsertOrder", mock.Anything, uint(4)).Return((*entities.Order)(nil), errors.New("connection refused"))
+		mockCatalog.On("ReleaseReservations", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReturnDraws", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReleaseCoupons", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Codes: []string{"WELCOME"}})
+
+		assert.EqualError(t, err, "database error")
+		mockCatalog.AssertCalled(t, "ReleaseReservations", firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1))
+		mockCatalog.AssertCalled(t, "ReturnDraws", firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1))
+		mockCatalog.AssertCalled(t, "ReleaseCoupons", firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1))
+	})
+
+	t.Run("checkout a drop needs an admission for each product behind a waiting room", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		tokens := []string{"token-for-1", "token-for-20"}
+		mockRepo.On("GetActiveCart", uint(7)).Return(activeCart(), nil)
+		mockCatalog.On("CheckAdmission", uint(7), uint(1), "token-for-1").Return(nil)
+		mockCatalog.On("CheckAdmission", uint(7), uint(20), "token-for-1").Return(errors.New("admission required"))
+		mockCatalog.On("CheckAdmission", uint(7), uint(20), "token-for-20").Return(errors.New("admission required"))
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", AdmissionTokens: tokens})
+
+		assert.EqualError(t, err, "admission required")
+		mockCatalog.AssertNotCalled(t, "HoldOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "InsertOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout holds an order the risk rules flag for review", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := activeCart()
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(4), nil).Once()
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(true, nil)
+		mockCatalog.On("RedeemCoupons", uint(7), mock.AnythingOfType("string"), []string(nil), cart.CartItem).
+			Return(&entities.PricedCart{Items: make([]entities.PricedItem, 3)}, nil)
+		mockCatalog.On("DrawBlindBoxes", cart.CartItem, "order-42", mock.AnythingOfType("string")).Return(&entities.BlindBoxDraws{}, nil)
+		mockRepo.On("InsertOrder", mock.Anything, uint(4)).Return(&entities.Order{Status: entities.OrderInReview}, nil)
+
+		got, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42"})
+
+		referenceID := firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1)
+		screening := firstCall(&mockCatalog.Mock, "ScreenOrder").Arguments.Get(0).(*entities.OrderScreening)
+		inserted := firstCall(&mockRepo.Mock, "InsertOrder").Arguments.Get(0).(*entities.Order)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderInReview, got.Status)
+		assert.Equal(t, &entities.OrderScreening{UserID: 7, ReferenceID: referenceID, Quantity: 5, OrdersLastHour: 1, OrdersLastDay: 4}, screening)
+		assert.Equal(t, entities.OrderInReview, inserted.Status)
+		assert.Nil(t, inserted.ExpiresAt)
+	})
+
+	t.Run("checkout given an order the risk rules reject", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		cart := activeCart()
+		mockRepo.On("GetActiveCart", uint(7)).Return(cart, nil)
+		mockCatalog.On("HoldOrder", uint(7), mock.AnythingOfType("string"), cart.CartItem, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("CountOrdersSince", uint(7), mock.AnythingOfType("time.Time")).Return(int64(6), nil)
+		mockCatalog.On("ScreenOrder", mock.Anything).Return(false, errors.New("order rejected"))
+		mockCatalog.On("ReleaseReservations", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReturnDraws", mock.AnythingOfType("string")).Return(nil)
+		mockCatalog.On("ReleaseCoupons", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42", Codes: []string{"WELCOME"}})
+
+		assert.EqualError(t, err, "order rejected")
+		mockCatalog.AssertCalled(t, "ReleaseReservations", firstCall(&mockCatalog.Mock, "HoldOrder").Arguments.String(1))
+		mockCatalog.AssertNotCalled(t, "RedeemCoupons", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "InsertOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("checkout given database error", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+		mockCatalog.On("CheckAdmission", uint(7), mock.Anything, "").Return(nil)
+
+		mockRepo.On("GetActiveCart", uint(7)).Return((*entities.Cart)(nil), errors.New("connection refused"))
+
+		_, err := orderService.Checkout(7, &entities.CheckoutRequest{ShippingAddress: "1 Sukhumvit Rd", ClientSeed: "order-42"})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestPlaceOrder(t *testing.T) {
+	t.Run("place an order for priced items", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		items := []entities.OrderItem{{ProductID: 1, Quantity: 1, TotalPrice: money.New(129000, "THB")}}
+
+		mockRepo.On("CreateOrder", mock.MatchedBy(func(order *entities.Order) bool {
+			return order.UserID == 12 && order.ReferenceID != "" && order.Status == entities.OrderPending && order.TotalAmount == money.New(129000, "THB")
+		})).Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", TotalAmount: money.New(129000, "THB")}, nil)
+
+		got, err := orderService.PlaceOrder(12, items)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "a1b2", got.ReferenceID)
+	})
+
+	t.Run("place an order with no items", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		_, err := orderService.PlaceOrder(12, nil)
+
+		assert.EqualError(t, err, "order has no items")
+		mockRepo.AssertNotCalled(t, "CreateOrder", mock.Anything)
+	})
+}
+
+func TestSettleOrder(t *testing.T) {
+	t.Run("settle a pending order", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{ReferenceID: "a1b2", Status: entities.OrderPending, TotalAmount: money.New(129000, "THB")}, nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderPaid).Return(true, nil)
+
+		got, err := orderService.SettleOrder("a1b2", money.New(129000, "THB"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderPaid, got.Status)
+	})
+
+	t.Run("settle an order for the wrong amount", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{ReferenceID: "a1b2", Status: entities.OrderPending, TotalAmount: money.New(129000, "THB")}, nil)
+
+		_, err := orderService.SettleOrder("a1b2", money.New(100, "THB"))
+
+		assert.EqualError(t, err, "payment amount does not match")
+		mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("settle an order held for review", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{ReferenceID: "a1b2", Status: entities.OrderInReview, TotalAmount: money.New(129000, "THB")}, nil)
+
+		_, err := orderService.SettleOrder("a1b2", money.New(129000, "THB"))
+
+		assert.EqualError(t, err, "order is not pending")
+		mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("settle an order cancelled in the meantime", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{ReferenceID: "a1b2", Status: entities.OrderPending, TotalAmount: money.New(129000, "THB")}, nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderPaid).Return(false, nil)
+
+		_, err := orderService.SettleOrder("a1b2", money.New(129000, "THB"))
+
+		assert.EqualError(t, err, "order is not pending")
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	t.Run("cancel a pending order and give back its stock, draws and coupons", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderPending}, nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderCancelled).Return(true, nil)
+		mockCatalog.On("ReleaseReservations", "a1b2").Return(nil)
+		mockCatalog.On("ReturnDraws", "a1b2").Return(nil)
+		mockCatalog.On("ReleaseCoupons", "a1b2").Return(nil)
+
+		got, err := orderService.CancelOrder(12, "a1b2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderCancelled, got.Status)
+		mockCatalog.AssertExpectations(t)
+	})
+
+	t.Run("cancel an order held for review", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderInReview}, nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderInReview, entities.OrderCancelled).Return(true, nil)
+		mockCatalog.On("ReleaseReservations", "a1b2").Return(nil)
+		mockCatalog.On("ReturnDraws", "a1b2").Return(nil)
+		mockCatalog.On("ReleaseCoupons", "a1b2").Return(nil)
+
+		got, err := orderService.CancelOrder(12, "a1b2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderCancelled, got.Status)
+		mockCatalog.AssertExpectations(t)
+	})
+
+	t.Run("cancel again after the draws failed to return", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderCancelled}, nil)
+		mockCatalog.On("ReleaseReservations", "a1b2").Return(nil)
+		mockCatalog.On("ReturnDraws", "a1b2").Return(nil)
+		mockCatalog.On("ReleaseCoupons", "a1b2").Return(nil)
+
+		got, err := orderService.CancelOrder(12, "a1b2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderCancelled, got.Status)
+		mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+		mockCatalog.AssertExpectations(t)
+	})
+
+	t.Run("cancel given the draws fail to return", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderPending}, nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderCancelled).Return(true, nil)
+		mockCatalog.On("ReleaseReservations", "a1b2").Return(nil)
+		mockCatalog.On("ReturnDraws", "a1b2").Return(errors.New("database error"))
+
+		_, err := orderService.CancelOrder(12, "a1b2")
+
+		assert.EqualError(t, err, "database error")
+		mockCatalog.AssertNotCalled(t, "ReleaseCoupons", mock.Anything)
+	})
+
+	t.Run("cancel another user's order", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 13, ReferenceID: "a1b2", Status: entities.OrderPending}, nil)
+
+		_, err := orderService.CancelOrder(12, "a1b2")
+
+		assert.EqualError(t, err, "order not found")
+		mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cancel a paid order", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderPaid}, nil)
+
+		_, err := orderService.CancelOrder(12, "a1b2")
+
+		assert.EqualError(t, err, "order is not pending")
+	})
+}
+
+func TestConfirmPayment(t *testing.T) {
+	pendingOrder := func() *entities.Order {
+		expiresAt := time.Now().Add(10 * time.Minute)
+		return &entities.Order{
+			UserID:          12,
+			ReferenceID:     "a1b2",
+			Status:          entities.OrderPending,
+			ShippingAddress: "1 Sukhumvit Rd",
+			TotalAmount:     money.New(129000, "THB"),
+			OrderItems:      []entities.OrderItem{{ProductID: 1, Quantity: 1, TotalPrice: money.New(129000, "THB")}},
+			ExpiresAt:       &expiresAt,
+		}
+	}
+
+	t.Run("confirm payment sells the held stock to the order's address and instrument", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(pendingOrder(), nil)
+		mockCatalog.On("ConvertReservations", "a1b2", "1 Sukhumvit Rd", "fp_123").Return(nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderPaid).Return(true, nil)
+
+		got, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB"), PaymentFingerprint: "fp_123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderPaid, got.Status)
+		mockCatalog.AssertExpectations(t)
+	})
+
+	t.Run("confirm payment for an order of blind boxes only", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		order := pendingOrder()
+		order.OrderItems = []entities.OrderItem{{ProductID: 20, Quantity: 1, TotalPrice: money.New(129000, "THB"), BlindBox: &entities.BlindBoxDraw{FigureID: 10}}}
+		mockRepo.On("GetOrderByReference", "a1b2").Return(order, nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderPaid).Return(true, nil)
+
+		_, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.NoError(t, err)
+		mockCatalog.AssertNotCalled(t, "ConvertReservations", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm payment after the deadline", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		order := pendingOrder()
+		expiredAt := time.Now().Add(-time.Minute)
+		order.ExpiresAt = &expiredAt
+		mockRepo.On("GetOrderByReference", "a1b2").Return(order, nil)
+
+		_, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.EqualError(t, err, "payment deadline has passed")
+		mockCatalog.AssertNotCalled(t, "ConvertReservations", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm payment for the wrong amount", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(pendingOrder(), nil)
+
+		_, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(100, "THB")})
+
+		assert.EqualError(t, err, "payment amount does not match")
+		mockCatalog.AssertNotCalled(t, "ConvertReservations", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm payment given the stock cannot be sold", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(pendingOrder(), nil)
+		mockCatalog.On("ConvertReservations", "a1b2", "1 Sukhumvit Rd", "").Return(errors.New("purchase limit exceeded"))
+
+		_, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+		mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm payment repeated for a paid order", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		order := pendingOrder()
+		order.Status = entities.OrderPaid
+		mockRepo.On("GetOrderByReference", "a1b2").Return(order, nil)
+
+		got, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderPaid, got.Status)
+		mockCatalog.AssertNotCalled(t, "ConvertReservations", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("confirm payment for a cancelled order", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		order := pendingOrder()
+		order.Status = entities.OrderCancelled
+		mockRepo.On("GetOrderByReference", "a1b2").Return(order, nil)
+
+		_, err := orderService.ConfirmPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.EqualError(t, err, "order is not pending")
+	})
+}
+
+func TestApproveOrder(t *testing.T) {
+	t.Run("approve an order held for review and give it a new payment deadline", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderInReview}, nil)
+		mockCatalog.On("ApproveReview", "a1b2", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("ReopenOrder", "a1b2", mock.AnythingOfType("time.Time")).Return(true, nil)
+
+		got, err := orderService.ApproveOrder("a1b2", 1)
+
+		expiresAt := firstCall(&mockCatalog.Mock, "ApproveReview").Arguments.Get(2).(time.Time)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderPending, got.Status)
+		assert.Equal(t, &expiresAt, got.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(paymentWindow), expiresAt, time.Minute)
+		mockRepo.AssertCalled(t, "ReopenOrder", "a1b2", expiresAt)
+	})
+
+	t.Run("approve an order that is not in review", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderPending}, nil)
+
+		_, err := orderService.ApproveOrder("a1b2", 1)
+
+		assert.EqualError(t, err, "order is not in review")
+		mockCatalog.AssertNotCalled(t, "ApproveReview", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("approve an order cancelled in the meantime", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderInReview}, nil)
+		mockCatalog.On("ApproveReview", "a1b2", uint(1), mock.Anything).Return(nil)
+		mockRepo.On("ReopenOrder", "a1b2", mock.Anything).Return(false, nil)
+
+		_, err := orderService.ApproveOrder("a1b2", 1)
+
+		assert.EqualError(t, err, "order is not in review")
+	})
+}
+
+func TestRejectOrder(t *testing.T) {
+	t.Run("reject an order held for review and give back its stock, draws and coupons", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderInReview}, nil)
+		mockCatalog.On("RejectReview", "a1b2", uint(1)).Return(nil)
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderInReview, entities.OrderCancelled).Return(true, nil)
+		mockCatalog.On("ReleaseReservations", "a1b2").Return(nil)
+		mockCatalog.On("ReturnDraws", "a1b2").Return(nil)
+		mockCatalog.On("ReleaseCoupons", "a1b2").Return(nil)
+
+		got, err := orderService.RejectOrder("a1b2", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.OrderCancelled, got.Status)
+		mockCatalog.AssertExpectations(t)
+	})
+
+	t.Run("reject an order whose review was already settled", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderInReview}, nil)
+		mockCatalog.On("RejectReview", "a1b2", uint(1)).Return(errors.New("order review already settled"))
+
+		_, err := orderService.RejectOrder("a1b2", 1)
+
+		assert.EqualError(t, err, "order review already settled")
+		mockRepo.AssertNotCalled(t, "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelExpiredOrders(t *testing.T) {
+	t.Run("cancel expired orders and carry on past one that fails", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		mockCatalog := new(MockCatalog)
+		orderService := OrderService{repo: mockRepo, catalog: mockCatalog}
+
+		mockRepo.On("GetExpiredOrders", mock.Anything, expiredOrderBatch).Return([]entities.Order{
+			{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderPending},
+			{UserID: 13, ReferenceID: "c3d4", Status: entities.OrderPending},
+		}, nil)
+		mockRepo.On("GetOrderByReference", "a1b2").Return(&entities.Order{UserID: 12, ReferenceID: "a1b2", Status: entities.OrderPending}, nil)
+		mockRepo.On("GetOrderByReference", "c3d4").Return((*entities.Order)(nil), errors.New("connection reset"))
+		mockRepo.On("UpdateOrderStatus", "a1b2", entities.OrderPending, entities.OrderCancelled).Return(true, nil)
+		mockCatalog.On("ReleaseReservations", "a1b2").Return(nil)
+		mockCatalog.On("ReturnDraws", "a1b2").Return(nil)
+		mockCatalog.On("ReleaseCoupons", "a1b2").Return(nil)
+
+		got, err := orderService.CancelExpiredOrders()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+		mockRepo.AssertNumberOfCalls(t, "GetExpiredOrders", 1)
+	})
+
+	t.Run("cancel expired orders given database error", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		orderService := OrderService{repo: mockRepo}
+
+		mockRepo.On("GetExpiredOrders", mock.Anything, expiredOrderBatch).Return(([]entities.Order)(nil), errors.New("connection reset"))
+
+		_, err := orderService.CancelExpiredOrders()
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestOrderSweeper(t *testing.T) {
+	t.Run("sweep on every tick until cancelled", func(t *testing.T) {
+		mockRepo := new(MockOrderRepository)
+		sweeper := NewOrderSweeper(&OrderService{repo: mockRepo}, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockRepo.On("GetExpiredOrders", mock.Anything, expiredOrderBatch).Return([]entities.Order{}, nil).Run(func(mock.Arguments) {
+			cancel()
+		})
+
+		done := make(chan struct{})
+		go func() {
+			sweeper.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("sweeper did not stop after cancel")
+		}
+		mockRepo.AssertCalled(t, "GetExpiredOrders", mock.Anything, expiredOrderBatch)
+	})
+}
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) InsertItemToCart(userID uint, productID uint, variantID *uint, quantity int, price money.Money) error {
+	args := m.Called(userID, productID, variantID, quantity, price)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetActiveCart(userID uint) (*entities.Cart, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*entities.Cart), args.Error(1)
+}
+
+func (m *MockOrderRepository) InsertOrder(order *entities.Order, cartID uint) (*entities.Order, error) {
+	args := m.Called(order, cartID)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) CreateOrder(order *entities.Order) (*entities.Order, error) {
+	args := m.Called(order)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetOrderByReference(referenceID string) (*entities.Order, error) {
+	args := m.Called(referenceID)
+	return args.Get(0).(*entities.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateOrderStatus(referenceID, from, to string) (bool, error) {
+	args := m.Called(referenceID, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetExpiredOrders(now time.Time, limit int) ([]entities.Order, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]entities.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) ReopenOrder(referenceID string, expiresAt time.Time) (bool, error) {
+	args := m.Called(referenceID, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRepository) CountOrdersSince(userID uint, since time.Time) (int64, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockCatalog struct {
+	mock.Mock
+}
+
+func (m *MockCatalog) GetItem(productID uint, variantID *uint) (*entities.CatalogItem, error) {
+	args := m.Called(productID, variantID)
+	return args.Get(0).(*entities.CatalogItem), args.Error(1)
+}
+
+func (m *MockCatalog) Reserve(userID, productID uint, variantID *uint, quantity int) error {
+	args := m.Called(userID, productID, variantID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) ScreenOrder(screening *entities.OrderScreening) (bool, error) {
+	args := m.Called(screening)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCatalog) ApproveReview(referenceID string, reviewerID uint, expiresAt time.Time) error {
+	args := m.Called(referenceID, reviewerID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) RejectReview(referenceID string, reviewerID uint) error {
+	args := m.Called(referenceID, reviewerID)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) CheckAdmission(userID, productID uint, token string) error {
+	args := m.Called(userID, productID, token)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) CheckPurchase(userID, productID uint, quantity int) error {
+	args := m.Called(userID, productID, quantity)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) DrawBlindBoxes(items []entities.CartItem, clientSeed, referenceID string) (*entities.BlindBoxDraws, error) {
+	args := m.Called(items, clientSeed, referenceID)
+	return args.Get(0).(*entities.BlindBoxDraws), args.Error(1)
+}
+
+func (m *MockCatalog) Quote(currency string) (*entities.RateQuote, error) {
+	args := m.Called(currency)
+	return args.Get(0).(*entities.RateQuote), args.Error(1)
+}
+
+func (m *MockCatalog) RedeemCoupons(userID uint, referenceID string, codes []string, items []entities.CartItem) (*entities.PricedCart, error) {
+	args := m.Called(userID, referenceID, codes, items)
+	return args.Get(0).(*entities.PricedCart), args.Error(1)
+}
+
+func (m *MockCatalog) HoldOrder(userID uint, referenceID string, items []entities.CartItem, expiresAt time.Time) error {
+	args := m.Called(userID, referenceID, items, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) ReleaseReservations(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) ConvertReservations(referenceID, shippingAddress, paymentFingerprint string) error {
+	args := m.Called(referenceID, shippingAddress, paymentFingerprint)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) ReturnDraws(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
+
+func (m *MockCatalog) ReleaseCoupons(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
@@ -1,5 +1,20 @@
 package usecase
 
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/order/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
 type OrderRepository interface {
-	InsertItemToCart(userID uint, productID uint, quantity int, price float64) error
+	InsertItemToCart(userID uint, productID uint, variantID *uint, quantity int, price money.Money) error
+	GetActiveCart(userID uint) (*entities.Cart, error)
+	InsertOrder(order *entities.Order, cartID uint) (*entities.Order, error)
+	CreateOrder(order *entities.Order) (*entities.Order, error)
+	GetOrderByReference(referenceID string) (*entities.Order, error)
+	UpdateOrderStatus(referenceID, from, to string) (bool, error)
+	GetExpiredOrders(now time.Time, limit int) ([]entities.Order, error)
+	ReopenOrder(referenceID string, expiresAt time.Time) (bool, error)
+	CountOrdersSince(userID uint, since time.Time) (int64, error)
 }
@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpBlindBoxHandler struct {
+	usecase usecase.BlindBoxUsecase
+}
+
+func NewBlindBoxHandler(usecase usecase.BlindBoxUsecase) *httpBlindBoxHandler {
+	return &httpBlindBoxHandler{usecase}
+}
+
+func (h *httpBlindBoxHandler) AddFigure(c echo.Context) error {
+	figure := new(entities.BlindBoxFigure)
+
+	if err := request.ContextWrapper(c).Bind(figure); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newFigure, err := h.usecase.AddFigure(c.Param("id"), figure)
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newFigure)
+}
+
+func (h *httpBlindBoxHandler) GetFigures(c echo.Context) error {
+	figures, err := h.usecase.GetFigures(c.Param("id"))
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, figures)
+}
+
+func (h *httpBlindBoxHandler) UpdateFigure(c echo.Context) error {
+	update := new(entities.BlindBoxFigureUpdate)
+
+	if err := request.ContextWrapper(c).Bind(update); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	figure, err := h.usecase.UpdateFigure(c.Param("id"), c.Param("figure_id"), update)
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, figure)
+}
+
+func (h *httpBlindBoxHandler) DeleteFigure(c echo.Context) error {
+	if err := h.usecase.DeleteFigure(c.Param("id"), c.Param("figure_id")); err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpBlindBoxHandler) GetSeeds(c echo.Context) error {
+	seeds, err := h.usecase.GetSeeds(c.Param("id"))
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, seeds)
+}
+
+func (h *httpBlindBoxHandler) RotateSeed(c echo.Context) error {
+	revealed, err := h.usecase.RotateSeed(c.Param("id"))
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, revealed)
+}
+
+func (h *httpBlindBoxHandler) SetCase(c echo.Context) error {
+	blindBoxCase := new(entities.BlindBoxCase)
+
+	if err := request.ContextWrapper(c).Bind(blindBoxCase); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	savedCase, err := h.usecase.SetCase(c.Param("id"), blindBoxCase)
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, savedCase)
+}
+
+func (h *httpBlindBoxHandler) GetCase(c echo.Context) error {
+	blindBoxCase, err := h.usecase.GetCase(c.Param("id"))
+	if err != nil {
+		return blindBoxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, blindBoxCase)
+}
+
+func blindBoxError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "figure not found", "seed not found", "case not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "product is not a blind box", "product is not a blind box case", "figure is not part of the series", "blind box is not part of the series":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "blind box sold out":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
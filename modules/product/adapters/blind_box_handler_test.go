@@ -0,0 +1,116 @@
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddFigure(t *testing.T) {
+	t.Run("add figure successfully", func(t *testing.T) {
+		mockService := new(MockBlindBoxUsecase)
+		handler := &httpBlindBoxHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("AddFigure", "20", mock.AnythingOfType("*entities.BlindBoxFigure")).Return(&entities.BlindBoxFigureResponse{
+			ID: 3, FigureID: 10, Name: "Labubu Sea Salt", Weight: 60, InStock: true,
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"figure_id":10,"weight":60,"stock":30}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("20")
+
+		err := handler.AddFigure(c)
+
+		expectedJSON := `{"id":3,"figure_id":10,"name":"Labubu Sea Salt","image_url":"","weight":60,"odds":0,"is_secret":false,"in_stock":true}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("add figure given zero weight", func(t *testing.T) {
+		mockService := new(MockBlindBoxUsecase)
+		handler := &httpBlindBoxHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"figure_id":10,"weight":0}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("20")
+
+		err := handler.AddFigure(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "AddFigure", mock.Anything, mock.Anything)
+	})
+}
+
+type MockBlindBoxUsecase struct {
+	mock.Mock
+}
+
+func (m *MockBlindBoxUsecase) AddFigure(boxID string, figure *entities.BlindBoxFigure) (*entities.BlindBoxFigureResponse, error) {
+	args := m.Called(boxID, figure)
+	return args.Get(0).(*entities.BlindBoxFigureResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) GetFigures(boxID string) ([]entities.BlindBoxFigureResponse, error) {
+	args := m.Called(boxID)
+	return args.Get(0).([]entities.BlindBoxFigureResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) UpdateFigure(boxID, figureID string, update *entities.BlindBoxFigureUpdate) (*entities.BlindBoxFigureResponse, error) {
+	args := m.Called(boxID, figureID, update)
+	return args.Get(0).(*entities.BlindBoxFigureResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) DeleteFigure(boxID, figureID string) error {
+	args := m.Called(boxID, figureID)
+	return args.Error(0)
+}
+
+func (m *MockBlindBoxUsecase) DrawOrder(request *entities.BlindBoxOrderDrawRequest) (*entities.BlindBoxOrderDraw, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.BlindBoxOrderDraw), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) ReturnDraws(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
+
+func (m *MockBlindBoxUsecase) GetSeeds(boxID string) ([]entities.BlindBoxSeedResponse, error) {
+	args := m.Called(boxID)
+	return args.Get(0).([]entities.BlindBoxSeedResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) RotateSeed(boxID string) (*entities.BlindBoxSeedResponse, error) {
+	args := m.Called(boxID)
+	return args.Get(0).(*entities.BlindBoxSeedResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) SetCase(caseID string, blindBoxCase *entities.BlindBoxCase) (*entities.BlindBoxCaseResponse, error) {
+	args := m.Called(caseID, blindBoxCase)
+	return args.Get(0).(*entities.BlindBoxCaseResponse), args.Error(1)
+}
+
+func (m *MockBlindBoxUsecase) GetCase(caseID string) (*entities.BlindBoxCaseResponse, error) {
+	args := m.Called(caseID)
+	return args.Get(0).(*entities.BlindBoxCaseResponse), args.Error(1)
+}
@@ -0,0 +1,609 @@
+package adapters
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/draw"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormBlindBoxRepository struct {
+	db *gorm.DB
+}
+
+func NewBlindBoxRepository(db *gorm.DB) usecase.BlindBoxRepository {
+	return &gormBlindBoxRepository{db}
+}
+
+func (r *gormBlindBoxRepository) InsertFigure(figure *entities.BlindBoxFigure) (*entities.BlindBoxFigure, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Figure").Create(figure).Error; err != nil {
+			return err
+		}
+
+		return syncBlindBoxStock(tx, figure.BoxID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return figure, nil
+}
+
+func (r *gormBlindBoxRepository) GetFigures(boxID uint) ([]entities.BlindBoxFigure, error) {
+	var figures []entities.BlindBoxFigure
+
+	if err := r.db.Preload("Figure").Where("box_id = ?", boxID).Order("id").Find(&figures).Error; err != nil {
+		return nil, err
+	}
+
+	return figures, nil
+}
+
+func (r *gormBlindBoxRepository) GetFigureById(boxID uint, figureID string) (*entities.BlindBoxFigure, error) {
+	figure := new(entities.BlindBoxFigure)
+
+	if err := r.db.Preload("Figure").Where("id = ? AND box_id = ?", figureID, boxID).First(figure).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("figure not found")
+		}
+		return nil, err
+	}
+
+	return figure, nil
+}
+
+func (r *gormBlindBoxRepository) UpdateFigure(figure *entities.BlindBoxFigure) (*entities.BlindBoxFigure, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(figure).
+			Select("weight", "is_secret", "stock").
+			Updates(figure).Error; err != nil {
+			return err
+		}
+
+		return syncBlindBoxStock(tx, figure.BoxID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return figure, nil
+}
+
+func (r *gormBlindBoxRepository) DeleteFigure(figure *entities.BlindBoxFigure) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(figure).Error; err != nil {
+			return err
+		}
+
+		return syncBlindBoxStock(tx, figure.BoxID)
+	})
+}
+
+func (r *gormBlindBoxRepository) InsertSeed(seed *entities.BlindBoxSeed) (*entities.BlindBoxSeed, error) {
+	if result := r.db.Create(seed); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return seed, nil
+}
+
+func (r *gormBlindBoxRepository) GetSeeds(boxID uint) ([]entities.BlindBoxSeed, error) {
+	var seeds []entities.BlindBoxSeed
+
+	if err := r.db.Where("box_id = ?", boxID).Order("id DESC").Find(&seeds).Error; err != nil {
+		return nil, err
+	}
+
+	return seeds, nil
+}
+
+// RotateSeed reveals the active seed and puts next in its place. The revealed
+// seed is returned so the draws made with it can be verified.
+func (r *gormBlindBoxRepository) RotateSeed(boxID uint, next *entities.BlindBoxSeed) (*entities.BlindBoxSeed, error) {
+	revealed := new(entities.BlindBoxSeed)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("box_id = ? AND revealed_at IS NULL", boxID).
+			First(revealed).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("seed not found")
+			}
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(revealed).Update("revealed_at", now).Error; err != nil {
+			return err
+		}
+		revealed.RevealedAt = &now
+
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revealed, nil
+}
+
+// DrawOrder draws one box per entry in boxIDs and opens one case per entry
+// in cases in a single transaction, so an order's blind boxes are either all
+// drawn or none are. Draws run in box ID order so that two orders sharing a
+// series always lock its pool, and then its case products, the same way.
+func (r *gormBlindBoxRepository) DrawOrder(boxIDs []uint, cases []entities.BlindBoxCase, clientSeed, referenceID string) (*entities.BlindBoxOrderDraw, error) {
+	boxIDs = slices.Sorted(slices.Values(boxIDs))
+	cases = slices.SortedStableFunc(slices.Values(cases), func(a, b entities.BlindBoxCase) int {
+		return cmp.Compare(a.BoxID, b.BoxID)
+	})
+
+	result := &entities.BlindBoxOrderDraw{Boxes: []entities.BlindBoxDraw{}, Cases: []entities.BlindBoxCaseDraw{}}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for len(boxIDs) > 0 || len(cases) > 0 {
+			if len(cases) == 0 || (len(boxIDs) > 0 && boxIDs[0] <= cases[0].BoxID) {
+				box, err := drawFigure(tx, boxIDs[0], clientSeed, referenceID)
+				if err != nil {
+					return err
+				}
+				result.Boxes = append(result.Boxes, *box)
+				boxIDs = boxIDs[1:]
+				continue
+			}
+
+			opened, err := drawCase(tx, &cases[0], clientSeed, referenceID)
+			if err != nil {
+				return err
+			}
+			result.Cases = append(result.Cases, *opened)
+			cases = cases[1:]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReturnDraws puts back what the draws for the order referenced by
+// referenceID took: each figure goes back into its pool and each case is
+// returned through the ledger. Takes already returned are skipped, so it can
+// be repeated. Boxes are handled in box ID order, each with its figures locked
+// before its case products, the way draws lock them.
+func (r *gormBlindBoxRepository) ReturnDraws(referenceID string, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var takes []entities.BlindBoxTake
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("reference_id = ? AND returned_at IS NULL", referenceID).
+			Order("box_id, id").
+			Find(&takes).Error; err != nil {
+			return err
+		}
+
+		if len(takes) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(takes))
+		for start := 0; start < len(takes); {
+			end := start
+			for end < len(takes) && takes[end].BoxID == takes[start].BoxID {
+				ids = append(ids, takes[end].ID)
+				end++
+			}
+
+			if err := returnTakes(tx, takes[start].BoxID, takes[start:end], referenceID); err != nil {
+				return err
+			}
+			start = end
+		}
+
+		return tx.Model(&entities.BlindBoxTake{}).Where("id IN ?", ids).Update("returned_at", now).Error
+	})
+}
+
+func (r *gormBlindBoxRepository) SaveCase(blindBoxCase *entities.BlindBoxCase) (*entities.BlindBoxCase, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"box_id", "size", "updated_at"}),
+		}).Create(blindBoxCase).Error; err != nil {
+			return err
+		}
+
+		return syncBlindBoxStock(tx, blindBoxCase.BoxID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blindBoxCase, nil
+}
+
+func (r *gormBlindBoxRepository) GetCase(productID uint) (*entities.BlindBoxCase, error) {
+	blindBoxCase := new(entities.BlindBoxCase)
+
+	if err := r.db.Where("product_id = ?", productID).First(blindBoxCase).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("case not found")
+		}
+		return nil, err
+	}
+
+	return blindBoxCase, nil
+}
+
+// drawCase opens a whole case under its series' pool lock: either every slot
+// is taken from the pool or none is. The case product is sold one unit
+// through the ledger; syncBlindBoxStock then records whatever else the pool
+// change did to the number of full cases.
+func drawCase(tx *gorm.DB, blindBoxCase *entities.BlindBoxCase, clientSeed, referenceID string) (*entities.BlindBoxCaseDraw, error) {
+	seed, figures, err := lockDrawPool(tx, blindBoxCase.BoxID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, caseProducts, err := lockCaseProducts(tx, blindBoxCase.BoxID)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(caseProducts, func(product *entities.Product) bool { return product.ID == blindBoxCase.ProductID })
+	if i < 0 {
+		return nil, errors.New("case not found")
+	}
+
+	caseProduct := caseProducts[i]
+	if caseProduct.Stock <= 0 {
+		return nil, errors.New("blind box sold out")
+	}
+
+	var regulars, secrets []draw.Entry
+	byID := map[uint]entities.BlindBoxFigure{}
+	for _, figure := range figures {
+		byID[figure.ID] = figure
+		if figure.IsSecret {
+			secrets = append(secrets, draw.Entry{ID: figure.ID, Weight: figure.Weight})
+		} else {
+			regulars = append(regulars, draw.Entry{ID: figure.ID, Weight: figure.Weight})
+		}
+	}
+
+	nonce := seed.Nonce
+	picks, rolls, err := draw.Assort(regulars, secrets, blindBoxCase.Size, seed.Seed, clientSeed, nonce)
+	if err != nil {
+		return nil, errors.New("blind box sold out")
+	}
+
+	pickedIDs := make([]uint, 0, len(picks))
+	productIDs := make([]uint, 0, len(picks))
+	for _, pick := range picks {
+		pickedIDs = append(pickedIDs, pick.ID)
+		productIDs = append(productIDs, byID[pick.ID].FigureID)
+	}
+
+	// Picks never repeat a figure, so one decrement per row takes the
+	// whole case out of the pool.
+	if err := tx.Model(&entities.BlindBoxFigure{}).
+		Where("id IN ?", pickedIDs).
+		Update("stock", gorm.Expr("stock - 1")).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(seed).Update("nonce", nonce+rolls).Error; err != nil {
+		return nil, err
+	}
+
+	if err := applyStockMovement(tx, caseProduct, &entities.StockMovement{
+		Kind:        entities.StockMovementSale,
+		Quantity:    -1,
+		ReferenceID: referenceID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(&entities.BlindBoxTake{
+		ReferenceID: referenceID,
+		BoxID:       blindBoxCase.BoxID,
+		CaseID:      &blindBoxCase.ProductID,
+		FigureIDs:   pickedIDs,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := syncBlindBoxStock(tx, blindBoxCase.BoxID); err != nil {
+		return nil, err
+	}
+
+	var figureProducts []entities.Product
+	if err := tx.Select("id", "name").Where("id IN ?", productIDs).Find(&figureProducts).Error; err != nil {
+		return nil, err
+	}
+
+	names := map[uint]string{}
+	for _, product := range figureProducts {
+		names[product.ID] = product.Name
+	}
+
+	slots := make([]entities.BlindBoxCaseSlot, 0, len(picks))
+	for _, pick := range picks {
+		figure := byID[pick.ID]
+		slots = append(slots, entities.BlindBoxCaseSlot{
+			FigureID:   figure.FigureID,
+			FigureName: names[figure.FigureID],
+			IsSecret:   figure.IsSecret,
+		})
+	}
+
+	return &entities.BlindBoxCaseDraw{
+		CaseID:     blindBoxCase.ProductID,
+		BoxID:      blindBoxCase.BoxID,
+		Figures:    slots,
+		SeedID:     seed.ID,
+		SeedHash:   seed.SeedHash,
+		ClientSeed: clientSeed,
+		Nonce:      nonce,
+		Rolls:      rolls,
+		Pool:       drawPool(figures),
+	}, nil
+}
+
+// drawFigure takes one box out of the pool. The pool is locked again for
+// every box, so drawing the same box twice in a transaction sees the first
+// draw's stock and nonce.
+func drawFigure(tx *gorm.DB, boxID uint, clientSeed, referenceID string) (*entities.BlindBoxDraw, error) {
+	seed, figures, err := lockDrawPool(tx, boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]draw.Entry, 0, len(figures))
+	for _, figure := range figures {
+		entries = append(entries, draw.Entry{ID: figure.ID, Weight: figure.Weight})
+	}
+
+	nonce := seed.Nonce
+	entry, roll, err := draw.Draw(entries, seed.Seed, clientSeed, nonce)
+	if err != nil {
+		return nil, errors.New("blind box sold out")
+	}
+
+	var drawn entities.BlindBoxFigure
+	for _, figure := range figures {
+		if figure.ID == entry.ID {
+			drawn = figure
+			break
+		}
+	}
+
+	if err := tx.Model(&drawn).Update("stock", gorm.Expr("stock - 1")).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(seed).Update("nonce", nonce+1).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(&entities.BlindBoxTake{
+		ReferenceID: referenceID,
+		BoxID:       boxID,
+		FigureIDs:   []uint{drawn.ID},
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := syncBlindBoxStock(tx, boxID); err != nil {
+		return nil, err
+	}
+
+	figureProduct := new(entities.Product)
+	if err := tx.Select("id", "name").First(figureProduct, drawn.FigureID).Error; err != nil {
+		return nil, err
+	}
+
+	return &entities.BlindBoxDraw{
+		BoxID:      boxID,
+		FigureID:   drawn.FigureID,
+		FigureName: figureProduct.Name,
+		IsSecret:   drawn.IsSecret,
+		SeedID:     seed.ID,
+		SeedHash:   seed.SeedHash,
+		ClientSeed: clientSeed,
+		Nonce:      nonce,
+		Roll:       roll,
+		Pool:       drawPool(figures),
+	}, nil
+}
+
+// returnTakes puts one box's takes back into its pool.
+func returnTakes(tx *gorm.DB, boxID uint, takes []entities.BlindBoxTake, referenceID string) error {
+	units := map[uint]int{}
+	cases := map[uint]int{}
+	for _, take := range takes {
+		for _, id := range take.FigureIDs {
+			units[id]++
+		}
+		if take.CaseID != nil {
+			cases[*take.CaseID]++
+		}
+	}
+
+	var figures []entities.BlindBoxFigure
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", slices.Sorted(maps.Keys(units))).
+		Order("id").
+		Find(&figures).Error; err != nil {
+		return err
+	}
+
+	for _, figure := range figures {
+		if err := tx.Model(&figure).Update("stock", gorm.Expr("stock + ?", units[figure.ID])).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(cases) > 0 {
+		_, caseProducts, err := lockCaseProducts(tx, boxID)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range caseProducts {
+			if cases[product.ID] == 0 {
+				continue
+			}
+
+			if err := applyStockMovement(tx, product, &entities.StockMovement{
+				Kind:        entities.StockMovementReturn,
+				Quantity:    cases[product.ID],
+				ReferenceID: referenceID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return syncBlindBoxStock(tx, boxID)
+}
+
+// lockDrawPool locks the box's active seed and the figures that can still be
+// drawn, so concurrent draws neither reuse a nonce nor take the last unit of
+// a figure twice.
+func lockDrawPool(tx *gorm.DB, boxID uint) (*entities.BlindBoxSeed, []entities.BlindBoxFigure, error) {
+	seed := new(entities.BlindBoxSeed)
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("box_id = ? AND revealed_at IS NULL", boxID).
+		First(seed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("blind box sold out")
+		}
+		return nil, nil, err
+	}
+
+	var figures []entities.BlindBoxFigure
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("box_id = ? AND stock > 0", boxID).
+		Order("id").
+		Find(&figures).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return seed, figures, nil
+}
+
+// drawPool records the figure_id:weight pairs a draw was made from.
+func drawPool(figures []entities.BlindBoxFigure) string {
+	pool := make([]string, 0, len(figures))
+	for _, figure := range figures {
+		pool = append(pool, strconv.FormatUint(uint64(figure.FigureID), 10)+":"+strconv.Itoa(figure.Weight))
+	}
+
+	return strings.Join(pool, ",")
+}
+
+// syncBlindBoxStock keeps each case product's stock equal to the full cases
+// the box's pool can still fill, and the box product's stock equal to the
+// units left in it, so listings and sold-out handling work as for any other
+// product. Case stock is moved through the ledger as an adjustment.
+func syncBlindBoxStock(tx *gorm.DB, boxID uint) error {
+	cases, caseProducts, err := lockCaseProducts(tx, boxID)
+	if err != nil {
+		return err
+	}
+
+	if len(cases) > 0 {
+		var stocks []int
+		if err := tx.Model(&entities.BlindBoxFigure{}).
+			Where("box_id = ? AND is_secret = ? AND stock > 0", boxID, false).
+			Pluck("stock", &stocks).Error; err != nil {
+			return err
+		}
+
+		for i := range cases {
+			full := fullCases(stocks, cases[i].Size)
+			if full == caseProducts[i].Stock {
+				continue
+			}
+
+			if err := applyStockMovement(tx, caseProducts[i], &entities.StockMovement{
+				Kind:     entities.StockMovementAdjustment,
+				Quantity: full - caseProducts[i].Stock,
+				Note:     "blind box pool changed",
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	var total int64
+	if err := tx.Model(&entities.BlindBoxFigure{}).
+		Where("box_id = ?", boxID).
+		Select("COALESCE(SUM(stock), 0)").
+		Scan(&total).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entities.Product{}).
+		Where("id = ?", boxID).
+		Update("stock", total).Error
+}
+
+// lockCaseProducts locks the case products filled from a box's pool in
+// product order. Every path that moves case stock locks them this way, after
+// the pool or the figure it changes and before the box product, so two of
+// them can never wait on each other.
+func lockCaseProducts(tx *gorm.DB, boxID uint) ([]entities.BlindBoxCase, []*entities.Product, error) {
+	var cases []entities.BlindBoxCase
+	if err := tx.Where("box_id = ?", boxID).Order("product_id").Find(&cases).Error; err != nil {
+		return nil, nil, err
+	}
+
+	products := make([]*entities.Product, len(cases))
+	for i := range cases {
+		product, err := lockProduct(tx, cases[i].ProductID)
+		if err != nil {
+			return nil, nil, err
+		}
+		products[i] = product
+	}
+
+	return cases, products, nil
+}
+
+// fullCases is the largest k for which k cases of size different regular
+// figures can be filled, i.e. each figure contributes at most k units.
+func fullCases(stocks []int, size int) int {
+	if size <= 0 {
+		return 0
+	}
+
+	var total int
+	for _, stock := range stocks {
+		total += stock
+	}
+
+	for k := total / size; k > 0; k-- {
+		var usable int
+		for _, stock := range stocks {
+			usable += min(stock, k)
+		}
+		if usable >= k*size {
+			return k
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,407 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/draw"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getActiveSeedForUpdateQuery = `SELECT * FROM "blind_box_seeds" WHERE (box_id = $1 AND revealed_at IS NULL) AND "blind_box_seeds"."deleted_at" IS NULL ORDER BY "blind_box_seeds"."id" LIMIT $2 FOR UPDATE`
+	getDrawPoolForUpdateQuery   = `SELECT * FROM "blind_box_figures" WHERE (box_id = $1 AND stock > 0) AND "blind_box_figures"."deleted_at" IS NULL ORDER BY id FOR UPDATE`
+	decrementFigureStockQuery   = `UPDATE "blind_box_figures" SET "stock"=stock - 1,"updated_at"=$1 WHERE "blind_box_figures"."deleted_at" IS NULL AND "id" = $2`
+	advanceSeedNonceQuery       = `UPDATE "blind_box_seeds" SET "nonce"=$1,"updated_at"=$2 WHERE "blind_box_seeds"."deleted_at" IS NULL AND "id" = $3`
+	sumBlindBoxStockQuery       = `SELECT COALESCE(SUM(stock), 0) FROM "blind_box_figures" WHERE box_id = $1 AND "blind_box_figures"."deleted_at" IS NULL`
+	getCasesForBoxQuery         = `SELECT * FROM "blind_box_cases" WHERE box_id = $1 AND "blind_box_cases"."deleted_at" IS NULL ORDER BY product_id`
+	getRegularStocksQuery       = `SELECT "stock" FROM "blind_box_figures" WHERE (box_id = $1 AND is_secret = $2 AND stock > 0) AND "blind_box_figures"."deleted_at" IS NULL`
+	decrementCaseFiguresQuery   = `UPDATE "blind_box_figures" SET "stock"=stock - 1,"updated_at"=$1 WHERE id IN ($2,$3) AND "blind_box_figures"."deleted_at" IS NULL`
+	getCaseFigureNamesQuery     = `SELECT "id","name" FROM "products" WHERE id IN ($1,$2) AND "products"."deleted_at" IS NULL`
+	insertBlindBoxTakeQuery     = `INSERT INTO "blind_box_takes" ("created_at","reference_id","box_id","case_id","figure_ids","returned_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`
+	lockTakesQuery              = `SELECT * FROM "blind_box_takes" WHERE reference_id = $1 AND returned_at IS NULL ORDER BY box_id, id FOR UPDATE`
+	lockTakenFiguresQuery       = `SELECT * FROM "blind_box_figures" WHERE id IN ($1,$2) AND "blind_box_figures"."deleted_at" IS NULL ORDER BY id FOR UPDATE`
+	returnFigureStockQuery      = `UPDATE "blind_box_figures" SET "stock"=stock + $1,"updated_at"=$2 WHERE "blind_box_figures"."deleted_at" IS NULL AND "id" = $3`
+	markTakesReturnedQuery      = `UPDATE "blind_box_takes" SET "returned_at"=$1 WHERE id IN ($2,$3)`
+	getFigureNameQuery          = `SELECT "id","name" FROM "products" WHERE "products"."id" = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`
+)
+
+func TestDrawOrder_gormRepo(t *testing.T) {
+	t.Run("draw figure and take it from the pool", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		seed := "6f1c2a"
+		entries := []draw.Entry{{ID: 1, Weight: 75}, {ID: 3, Weight: 25}}
+		entry, roll, _ := draw.Draw(entries, seed, "order-42", 7)
+		figureIDs := map[uint]uint{1: 10, 3: 12}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getActiveSeedForUpdateQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "seed", "seed_hash", "nonce"}).AddRow(1, 20, seed, draw.HashSeed(seed), 7))
+		mock.ExpectQuery(getDrawPoolForUpdateQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "figure_id", "weight", "is_secret", "stock"}).
+				AddRow(1, 20, 10, 75, false, 12).
+				AddRow(3, 20, 12, 25, true, 1))
+		mock.ExpectExec(decrementFigureStockQuery).
+			WithArgs(sqlmock.AnyArg(), entry.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(advanceSeedNonceQuery).
+			WithArgs(8, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertBlindBoxTakeQuery).
+			WithArgs(sqlmock.AnyArg(), "a1b2", 20, nil, sqlmock.AnyArg(), nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(sumBlindBoxStockQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(12))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(12, sqlmock.AnyArg(), 20).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getFigureNameQuery).
+			WithArgs(figureIDs[entry.ID], 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(figureIDs[entry.ID], "Drawn figure"))
+		mock.ExpectCommit()
+
+		got, err := repo.DrawOrder([]uint{20}, nil, "order-42", "a1b2")
+
+		want := &entities.BlindBoxOrderDraw{Boxes: []entities.BlindBoxDraw{{
+			BoxID:      20,
+			FigureID:   figureIDs[entry.ID],
+			FigureName: "Drawn figure",
+			IsSecret:   entry.ID == 3,
+			SeedID:     1,
+			SeedHash:   draw.HashSeed(seed),
+			ClientSeed: "order-42",
+			Nonce:      7,
+			Roll:       roll,
+			Pool:       "10:75,12:25",
+		}}, Cases: []entities.BlindBoxCaseDraw{}}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		verified, err := draw.Verify(entries, seed, got.Boxes[0].SeedHash, got.Boxes[0].ClientSeed, got.Boxes[0].Nonce)
+		assert.NoError(t, err)
+		assert.Equal(t, entry, verified)
+	})
+
+	t.Run("draw given no active seed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getActiveSeedForUpdateQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.DrawOrder([]uint{20}, nil, "order-42", "a1b2")
+
+		assert.EqualError(t, err, "blind box sold out")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("draw given empty pool", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getActiveSeedForUpdateQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "seed", "seed_hash", "nonce"}).AddRow(1, 20, "6f1c2a", "hash", 7))
+		mock.ExpectQuery(getDrawPoolForUpdateQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.DrawOrder([]uint{20}, nil, "order-42", "a1b2")
+
+		assert.EqualError(t, err, "blind box sold out")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDrawOrder_gormRepo_cases(t *testing.T) {
+	t.Run("draw case and sell it through the ledger", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		seed := "6f1c2a"
+		config := entities.BlindBoxCase{ProductID: 30, BoxID: 20, Size: 2}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getActiveSeedForUpdateQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "seed", "seed_hash", "nonce"}).AddRow(1, 20, seed, draw.HashSeed(seed), 7))
+		mock.ExpectQuery(getDrawPoolForUpdateQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "figure_id", "weight", "is_secret", "stock"}).
+				AddRow(1, 20, 10, 50, false, 3).
+				AddRow(2, 20, 11, 50, false, 1))
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "box_id", "size"}).AddRow(1, 30, 20, 2))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(30, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(30, 1))
+		mock.ExpectExec(decrementCaseFiguresQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(advanceSeedNonceQuery).
+			WithArgs(9, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(30).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(0, sqlmock.AnyArg(), 30).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fulfilProductSaleQuery).
+			WithArgs(30).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 30, nil, nil, "sale", -1, 0, nil, "a1b2", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(41))
+		mock.ExpectQuery(insertBlindBoxTakeQuery).
+			WithArgs(sqlmock.AnyArg(), "a1b2", 20, 30, sqlmock.AnyArg(), nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "box_id", "size"}).AddRow(1, 30, 20, 2))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(30, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(30, 0))
+		mock.ExpectQuery(getRegularStocksQuery).
+			WithArgs(20, false).
+			WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(2))
+		mock.ExpectQuery(sumBlindBoxStockQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(2))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(2, sqlmock.AnyArg(), 20).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getCaseFigureNamesQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(10, "Sea Salt").AddRow(11, "Lychee Berry"))
+		mock.ExpectCommit()
+
+		got, err := repo.DrawOrder(nil, []entities.BlindBoxCase{config}, "order-42", "a1b2")
+
+		assert.NoError(t, err)
+		assert.Empty(t, got.Boxes)
+		assert.Len(t, got.Cases, 1)
+		assert.ElementsMatch(t, []entities.BlindBoxCaseSlot{{FigureID: 10, FigureName: "Sea Salt"}, {FigureID: 11, FigureName: "Lychee Berry"}}, got.Cases[0].Figures)
+		assert.Equal(t, uint(30), got.Cases[0].CaseID)
+		assert.Equal(t, uint64(7), got.Cases[0].Nonce)
+		assert.Equal(t, uint64(2), got.Cases[0].Rolls)
+		assert.Equal(t, "10:50,11:50", got.Cases[0].Pool)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("sync adjusts case stock the pool can no longer fill", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "box_id", "size"}).AddRow(1, 30, 20, 2))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(30, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(30, 2))
+		mock.ExpectQuery(getRegularStocksQuery).
+			WithArgs(20, false).
+			WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(3).AddRow(1))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(30).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(1, sqlmock.AnyArg(), 30).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 30, nil, nil, "adjustment", -1, 1, nil, "", "blind box pool changed").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+		mock.ExpectQuery(sumBlindBoxStockQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(4))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(4, sqlmock.AnyArg(), 20).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := gormDB.Transaction(func(tx *gorm.DB) error {
+			return syncBlindBoxStock(tx, 20)
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("draw case given sold out case product", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getActiveSeedForUpdateQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "seed", "seed_hash", "nonce"}).AddRow(1, 20, "6f1c2a", "hash", 7))
+		mock.ExpectQuery(getDrawPoolForUpdateQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "figure_id", "weight", "is_secret", "stock"}).
+				AddRow(1, 20, 10, 50, false, 3).
+				AddRow(2, 20, 11, 50, false, 1))
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "box_id", "size"}).AddRow(1, 30, 20, 2))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(30, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(30, 0))
+		mock.ExpectRollback()
+
+		_, err := repo.DrawOrder(nil, []entities.BlindBoxCase{{ProductID: 30, BoxID: 20, Size: 2}}, "order-42", "a1b2")
+
+		assert.EqualError(t, err, "blind box sold out")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("draw case given fewer regular figures than slots", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getActiveSeedForUpdateQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "seed", "seed_hash", "nonce"}).AddRow(1, 20, "6f1c2a", "hash", 7))
+		mock.ExpectQuery(getDrawPoolForUpdateQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "figure_id", "weight", "is_secret", "stock"}).
+				AddRow(1, 20, 10, 50, false, 3).
+				AddRow(3, 20, 12, 5, true, 1))
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "box_id", "size"}).AddRow(1, 30, 20, 2))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(30, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(30, 1))
+		mock.ExpectRollback()
+
+		_, err := repo.DrawOrder(nil, []entities.BlindBoxCase{{ProductID: 30, BoxID: 20, Size: 2}}, "order-42", "a1b2")
+
+		assert.EqualError(t, err, "blind box sold out")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReturnDraws_gormRepo(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("return drawn figures to the pool", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockTakesQuery).
+			WithArgs("a1b2").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reference_id", "box_id", "figure_ids"}).
+				AddRow(5, "a1b2", 20, "[3]").
+				AddRow(6, "a1b2", 20, "[1]"))
+		mock.ExpectQuery(lockTakenFiguresQuery).
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "box_id", "stock"}).AddRow(1, 20, 11).AddRow(3, 20, 0))
+		mock.ExpectExec(returnFigureStockQuery).
+			WithArgs(1, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(returnFigureStockQuery).
+			WithArgs(1, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getCasesForBoxQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(sumBlindBoxStockQuery).
+			WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(13))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(13, sqlmock.AnyArg(), 20).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(markTakesReturnedQuery).
+			WithArgs(now, 5, 6).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := repo.ReturnDraws("a1b2", now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("return draws already returned", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewBlindBoxRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockTakesQuery).
+			WithArgs("a1b2").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		err := repo.ReturnDraws("a1b2", now)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFullCases(t *testing.T) {
+	tests := []struct {
+		stocks []int
+		size   int
+		want   int
+	}{
+		{[]int{3, 3, 3}, 3, 3},
+		{[]int{5, 1, 1}, 3, 1},
+		{[]int{4, 4, 2, 2}, 3, 4},
+		{[]int{2, 2}, 3, 0},
+		{nil, 3, 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fullCases(tt.stocks, tt.size))
+	}
+}
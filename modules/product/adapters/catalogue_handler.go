@@ -0,0 +1,159 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpCatalogueHandler struct {
+	usecase usecase.CatalogueUsecase
+}
+
+func NewCatalogueHandler(usecase usecase.CatalogueUsecase) *httpCatalogueHandler {
+	return &httpCatalogueHandler{usecase}
+}
+
+func (h *httpCatalogueHandler) CreateArtist(c echo.Context) error {
+	artist := new(entities.Artist)
+
+	if err := request.ContextWrapper(c).Bind(artist); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newArtist, err := h.usecase.CreateArtist(artist)
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newArtist)
+}
+
+func (h *httpCatalogueHandler) GetAllArtists(c echo.Context) error {
+	artists, err := h.usecase.GetAllArtists()
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, artists)
+}
+
+func (h *httpCatalogueHandler) GetArtistById(c echo.Context) error {
+	artist, err := h.usecase.GetArtistById(c.Param("id"))
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, artist)
+}
+
+func (h *httpCatalogueHandler) UpdateArtist(c echo.Context) error {
+	artist := new(entities.Artist)
+
+	if err := request.ContextWrapper(c).Bind(artist); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	artistUpdated, err := h.usecase.UpdateArtist(artist, c.Param("id"))
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, artistUpdated)
+}
+
+func (h *httpCatalogueHandler) DeleteArtist(c echo.Context) error {
+	if err := h.usecase.DeleteArtist(c.Param("id")); err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpCatalogueHandler) GetArtistProducts(c echo.Context) error {
+	products, err := h.usecase.GetArtistProducts(c.Param("id"))
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+func (h *httpCatalogueHandler) CreateBrand(c echo.Context) error {
+	brand := new(entities.Brand)
+
+	if err := request.ContextWrapper(c).Bind(brand); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newBrand, err := h.usecase.CreateBrand(brand)
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newBrand)
+}
+
+func (h *httpCatalogueHandler) GetAllBrands(c echo.Context) error {
+	brands, err := h.usecase.GetAllBrands()
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, brands)
+}
+
+func (h *httpCatalogueHandler) GetBrandById(c echo.Context) error {
+	brand, err := h.usecase.GetBrandById(c.Param("id"))
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, brand)
+}
+
+func (h *httpCatalogueHandler) UpdateBrand(c echo.Context) error {
+	brand := new(entities.Brand)
+
+	if err := request.ContextWrapper(c).Bind(brand); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	brandUpdated, err := h.usecase.UpdateBrand(brand, c.Param("id"))
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, brandUpdated)
+}
+
+func (h *httpCatalogueHandler) DeleteBrand(c echo.Context) error {
+	if err := h.usecase.DeleteBrand(c.Param("id")); err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpCatalogueHandler) GetBrandProducts(c echo.Context) error {
+	products, err := h.usecase.GetBrandProducts(c.Param("id"))
+	if err != nil {
+		return catalogueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+func catalogueError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "artist not found", "brand not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,192 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateArtist(t *testing.T) {
+	t.Run("create artist successfully", func(t *testing.T) {
+		mockService := new(MockCatalogueUsecase)
+		handler := &httpCatalogueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateArtist", mock.AnythingOfType("*entities.Artist")).Return(&entities.ArtistResponse{
+			ID: 1, Name: "Kenny Wong", Slug: "kenny-wong", SocialLinks: entities.SocialLinks{"instagram": "https://instagram.com/kennyswork"},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Kenny Wong","social_links":{"instagram":"https://instagram.com/kennyswork"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateArtist(c)
+
+		expectedJSON := `{"id":1,"name":"Kenny Wong","slug":"kenny-wong","bio":"","avatar_url":"","social_links":{"instagram":"https://instagram.com/kennyswork"}}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("create artist given invalid social link", func(t *testing.T) {
+		mockService := new(MockCatalogueUsecase)
+		handler := &httpCatalogueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Kenny Wong","social_links":{"instagram":"kennyswork"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateArtist(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "CreateArtist", mock.Anything)
+	})
+}
+
+func TestGetArtistProducts(t *testing.T) {
+	t.Run("get artist products successfully", func(t *testing.T) {
+		mockService := new(MockCatalogueUsecase)
+		handler := &httpCatalogueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetArtistProducts", "1").Return([]entities.ProductResponse{
+			{ID: 12, Name: "Molly Classic", Price: money.New(34099, "THB"), Artist: &entities.CreatorSummary{ID: 1, Name: "Kenny Wong", Slug: "kenny-wong"}},
+		}, nil)
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.GetArtistProducts(c)
+
+		expectedJSON := `[{"id":12,"name":"Molly Classic","description":"","price":{"amount":34099,"currency":"THB"},"image_url":"","artist":{"id":1,"name":"Kenny Wong","slug":"kenny-wong"}}]`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("get artist products given artist not found", func(t *testing.T) {
+		mockService := new(MockCatalogueUsecase)
+		handler := &httpCatalogueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetArtistProducts", "8").Return(([]entities.ProductResponse)(nil), errors.New("artist not found"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("8")
+
+		err := handler.GetArtistProducts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestDeleteBrand(t *testing.T) {
+	t.Run("delete brand given database error", func(t *testing.T) {
+		mockService := new(MockCatalogueUsecase)
+		handler := &httpCatalogueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteBrand", "2").Return(errors.New("database error"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("2")
+
+		err := handler.DeleteBrand(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
+type MockCatalogueUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCatalogueUsecase) CreateArtist(artist *entities.Artist) (*entities.ArtistResponse, error) {
+	args := m.Called(artist)
+	return args.Get(0).(*entities.ArtistResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) GetAllArtists() ([]entities.ArtistResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.ArtistResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) GetArtistById(id string) (*entities.ArtistResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.ArtistResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) UpdateArtist(artist *entities.Artist, id string) (*entities.ArtistResponse, error) {
+	args := m.Called(artist, id)
+	return args.Get(0).(*entities.ArtistResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) DeleteArtist(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCatalogueUsecase) GetArtistProducts(id string) ([]entities.ProductResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).([]entities.ProductResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) CreateBrand(brand *entities.Brand) (*entities.BrandResponse, error) {
+	args := m.Called(brand)
+	return args.Get(0).(*entities.BrandResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) GetAllBrands() ([]entities.BrandResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.BrandResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) GetBrandById(id string) (*entities.BrandResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.BrandResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) UpdateBrand(brand *entities.Brand, id string) (*entities.BrandResponse, error) {
+	args := m.Called(brand, id)
+	return args.Get(0).(*entities.BrandResponse), args.Error(1)
+}
+
+func (m *MockCatalogueUsecase) DeleteBrand(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCatalogueUsecase) GetBrandProducts(id string) ([]entities.ProductResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).([]entities.ProductResponse), args.Error(1)
+}
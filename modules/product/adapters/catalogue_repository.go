@@ -0,0 +1,125 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+)
+
+type gormCatalogueRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogueRepository(db *gorm.DB) usecase.CatalogueRepository {
+	return &gormCatalogueRepository{db}
+}
+
+func (r *gormCatalogueRepository) InsertArtist(artist *entities.Artist) (*entities.Artist, error) {
+	if result := r.db.Create(artist); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return artist, nil
+}
+
+func (r *gormCatalogueRepository) GetAllArtists() ([]entities.Artist, error) {
+	var artists []entities.Artist
+
+	if err := r.db.Order("name").Find(&artists).Error; err != nil {
+		return nil, err
+	}
+
+	return artists, nil
+}
+
+func (r *gormCatalogueRepository) GetArtistById(id string) (*entities.Artist, error) {
+	artist := new(entities.Artist)
+
+	if err := r.db.First(artist, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("artist not found")
+		}
+		return nil, err
+	}
+
+	return artist, nil
+}
+
+func (r *gormCatalogueRepository) UpdateArtist(artist *entities.Artist, id string) (*entities.Artist, error) {
+	if result := r.db.Model(&entities.Artist{}).
+		Where("id = ?", id).
+		Select("name", "slug", "bio", "avatar_url", "social_links").
+		Updates(artist); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return artist, nil
+}
+
+func (r *gormCatalogueRepository) DeleteArtist(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Product{}).
+			Where("artist_id = ?", id).
+			Update("artist_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entities.Artist{}, id).Error
+	})
+}
+
+func (r *gormCatalogueRepository) InsertBrand(brand *entities.Brand) (*entities.Brand, error) {
+	if result := r.db.Create(brand); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return brand, nil
+}
+
+func (r *gormCatalogueRepository) GetAllBrands() ([]entities.Brand, error) {
+	var brands []entities.Brand
+
+	if err := r.db.Order("name").Find(&brands).Error; err != nil {
+		return nil, err
+	}
+
+	return brands, nil
+}
+
+func (r *gormCatalogueRepository) GetBrandById(id string) (*entities.Brand, error) {
+	brand := new(entities.Brand)
+
+	if err := r.db.First(brand, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("brand not found")
+		}
+		return nil, err
+	}
+
+	return brand, nil
+}
+
+func (r *gormCatalogueRepository) UpdateBrand(brand *entities.Brand, id string) (*entities.Brand, error) {
+	if result := r.db.Model(&entities.Brand{}).
+		Where("id = ?", id).
+		Select("name", "slug", "bio", "avatar_url", "social_links").
+		Updates(brand); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return brand, nil
+}
+
+func (r *gormCatalogueRepository) DeleteBrand(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Product{}).
+			Where("brand_id = ?", id).
+			Update("brand_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entities.Brand{}, id).Error
+	})
+}
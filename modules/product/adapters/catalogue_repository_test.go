@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	insertArtistQuery         = `INSERT INTO "artists" ("created_at","updated_at","deleted_at","name","slug","bio","avatar_url","social_links") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`
+	getArtistByIdQuery        = `SELECT * FROM "artists" WHERE "artists"."id" = $1 AND "artists"."deleted_at" IS NULL ORDER BY "artists"."id" LIMIT $2`
+	detachArtistProductsQuery = `UPDATE "products" SET "artist_id"=$1,"updated_at"=$2 WHERE artist_id = $3 AND "products"."deleted_at" IS NULL`
+	deleteArtistQuery         = `UPDATE "artists" SET "deleted_at"=$1 WHERE "artists"."id" = $2 AND "artists"."deleted_at" IS NULL`
+)
+
+func TestInsertArtist_gormRepo(t *testing.T) {
+	t.Run("insert artist with social links", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewCatalogueRepository(gormDB)
+
+		artist := &entities.Artist{Name: "Kenny Wong", Slug: "kenny-wong", SocialLinks: entities.SocialLinks{"instagram": "https://instagram.com/kennyswork"}}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertArtistQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "Kenny Wong", "kenny-wong", "", "", `{"instagram":"https://instagram.com/kennyswork"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		got, err := repo.InsertArtist(artist)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), got.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetArtistById_gormRepo(t *testing.T) {
+	t.Run("get artist successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewCatalogueRepository(gormDB)
+
+		rows := sqlmock.NewRows([]string{"id", "name", "slug", "social_links"}).
+			AddRow(1, "Kenny Wong", "kenny-wong", []byte(`{"instagram":"https://instagram.com/kennyswork"}`))
+		mock.ExpectQuery(getArtistByIdQuery).WithArgs("1", 1).WillReturnRows(rows)
+
+		got, err := repo.GetArtistById("1")
+
+		want := &entities.Artist{Model: gorm.Model{ID: 1}, Name: "Kenny Wong", Slug: "kenny-wong", SocialLinks: entities.SocialLinks{"instagram": "https://instagram.com/kennyswork"}}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("get artist given artist not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewCatalogueRepository(gormDB)
+
+		mock.ExpectQuery(getArtistByIdQuery).WithArgs("7", 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetArtistById("7")
+
+		assert.EqualError(t, err, "artist not found")
+	})
+}
+
+func TestDeleteArtist_gormRepo(t *testing.T) {
+	t.Run("delete artist and unlink their products", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewCatalogueRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(detachArtistProductsQuery).WithArgs(nil, sqlmock.AnyArg(), "1").WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(deleteArtistQuery).WithArgs(sqlmock.AnyArg(), "1").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteArtist("1")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
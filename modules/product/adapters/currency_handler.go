@@ -0,0 +1,139 @@
+package adapters
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpCurrencyHandler struct {
+	usecase usecase.CurrencyUsecase
+}
+
+func NewCurrencyHandler(usecase usecase.CurrencyUsecase) *httpCurrencyHandler {
+	return &httpCurrencyHandler{usecase}
+}
+
+func (h *httpCurrencyHandler) SetRate(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	rateRequest := new(entities.ExchangeRateRequest)
+	if err := request.ContextWrapper(c).Bind(rateRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	rate, err := h.usecase.SetRate(c.Param("currency"), actorID, rateRequest)
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rate)
+}
+
+// ImportRates reads exchange rates from an uploaded CSV file.
+func (h *httpCurrencyHandler) ImportRates(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("failed to read form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "rate file is required"})
+	}
+
+	if file.Size > usecase.MaxRateFileSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: "rate file too large"})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Printf("failed to open form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "rate file is required"})
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, usecase.MaxRateFileSize+1))
+	if err != nil {
+		log.Printf("failed to read form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "rate file is required"})
+	}
+
+	imported, err := h.usecase.ImportRates(actorID, data)
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, entities.RateImportResponse{Imported: imported})
+}
+
+func (h *httpCurrencyHandler) GetRates(c echo.Context) error {
+	rates, err := h.usecase.GetRates()
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rates)
+}
+
+func (h *httpCurrencyHandler) DeleteRate(c echo.Context) error {
+	if err := h.usecase.DeleteRate(c.Param("currency")); err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetQuote is the rate the cart and checkout show prices at. Checkout keeps
+// the quote it charged under on the order.
+func (h *httpCurrencyHandler) GetQuote(c echo.Context) error {
+	quote, err := h.usecase.Quote(c.Param("currency"))
+	if err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, quote)
+}
+
+// localize adds prices in the currency the customer picked with ?currency=
+// to products. Without one they stay in the settlement currency only.
+func localize(c echo.Context, currency usecase.CurrencyUsecase, products ...*entities.ProductResponse) error {
+	code := c.QueryParam("currency")
+	if code == "" {
+		return nil
+	}
+
+	return currency.LocalizeProducts(code, products...)
+}
+
+// localizeCart shows a priced cart in the currency asked for in the query
+// string, if any.
+func localizeCart(c echo.Context, currency usecase.CurrencyUsecase, pricing *entities.CartPricing) error {
+	code := c.QueryParam("currency")
+	if code == "" {
+		return nil
+	}
+
+	return currency.LocalizeCart(code, pricing)
+}
+
+func currencyError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "currency not supported":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "invalid currency", "invalid exchange rate", "invalid rate file", "settlement currency has no rate":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
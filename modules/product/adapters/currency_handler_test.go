@@ -0,0 +1,307 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetRate(t *testing.T) {
+	t.Run("set rate successfully", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SetRate", "USD", uint(9), &entities.ExchangeRateRequest{Rate: 27350}).
+			Return(&entities.ExchangeRate{Currency: "USD", Rate: 27350}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rate":"0.02735"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("currency")
+		c.SetParamValues("USD")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SetRate(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"rate":"0.027350"`)
+	})
+
+	t.Run("set rate for the settlement currency", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SetRate", "THB", uint(9), mock.Anything).Return((*entities.ExchangeRate)(nil), errors.New("settlement currency has no rate"))
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rate":"1"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("currency")
+		c.SetParamValues("THB")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SetRate(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("set rate given malformed rate", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rate":"0.1234567"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("currency")
+		c.SetParamValues("USD")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SetRate(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "SetRate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("set rate without signing in", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"rate":"0.02735"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("currency")
+		c.SetParamValues("USD")
+
+		err := handler.SetRate(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+}
+
+func TestImportRates(t *testing.T) {
+	t.Run("import rates successfully", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		data := []byte("currency,rate\nUSD,0.027350\n")
+		mockService.On("ImportRates", uint(9), data).Return(1, nil)
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "file", data, nil), response)
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.ImportRates(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"imported":1}`, response.Body.String())
+	})
+
+	t.Run("import rates given too large file", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "file", make([]byte, usecase.MaxRateFileSize+1), nil), response)
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.ImportRates(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+		mockService.AssertNotCalled(t, "ImportRates", mock.Anything, mock.Anything)
+	})
+
+	t.Run("import rates given invalid file", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ImportRates", uint(9), mock.Anything).Return(0, errors.New("invalid rate file"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "file", []byte("USD,abc\n"), nil), response)
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.ImportRates(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDeleteRate(t *testing.T) {
+	t.Run("delete rate given not supported", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteRate", "EUR").Return(errors.New("currency not supported"))
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("currency")
+		c.SetParamValues("EUR")
+
+		err := handler.DeleteRate(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestGetQuote(t *testing.T) {
+	t.Run("get quote successfully", func(t *testing.T) {
+		mockService := new(MockCurrencyUsecase)
+		handler := &httpCurrencyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Quote", "USD").Return(&entities.RateQuote{SettlementCurrency: "THB", Currency: "USD", Rate: 27350}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("currency")
+		c.SetParamValues("USD")
+
+		err := handler.GetQuote(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"settlement_currency":"THB","currency":"USD","rate":"0.027350"`)
+	})
+}
+
+func TestGetProductByIdInCurrency(t *testing.T) {
+	t.Run("get product with display prices", func(t *testing.T) {
+		mockService := new(MockProductUsecase)
+		mockCurrency := new(MockCurrencyUsecase)
+		handler := &httpProductHandler{usecase: mockService, currency: mockCurrency}
+
+		e := echo.New()
+		defer e.Close()
+
+		product := &entities.ProductResponse{ID: 1, Price: money.New(4999, "THB")}
+		mockService.On("GetProductById", "1").Return(product, nil)
+		mockCurrency.On("LocalizeProducts", "USD", []*entities.ProductResponse{product}).Run(func(args mock.Arguments) {
+			product.Display = &entities.DisplayPrice{Price: money.New(137, "USD"), Rate: 27350}
+		}).Return(nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/?currency=USD", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.GetProductById(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"price":{"amount":4999,"currency":"THB"}`)
+		assert.Contains(t, response.Body.String(), `"display":{"price":{"amount":137,"currency":"USD"},"rate":"0.027350"}`)
+	})
+
+	t.Run("get product given currency not supported", func(t *testing.T) {
+		mockService := new(MockProductUsecase)
+		mockCurrency := new(MockCurrencyUsecase)
+		handler := &httpProductHandler{usecase: mockService, currency: mockCurrency}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetProductById", "1").Return(&entities.ProductResponse{ID: 1}, nil)
+		mockCurrency.On("LocalizeProducts", "EUR", mock.Anything).Return(errors.New("currency not supported"))
+
+		request := httptest.NewRequest(http.MethodGet, "/?currency=EUR", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.GetProductById(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockCurrencyUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyUsecase) SetRate(currency string, actorID uint, request *entities.ExchangeRateRequest) (*entities.ExchangeRate, error) {
+	args := m.Called(currency, actorID, request)
+	return args.Get(0).(*entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) ImportRates(actorID uint, data []byte) (int, error) {
+	args := m.Called(actorID, data)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) GetRates() ([]entities.ExchangeRate, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) DeleteRate(currency string) error {
+	args := m.Called(currency)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyUsecase) Quote(currency string) (*entities.RateQuote, error) {
+	args := m.Called(currency)
+	return args.Get(0).(*entities.RateQuote), args.Error(1)
+}
+
+func (m *MockCurrencyUsecase) LocalizeProducts(currency string, products ...*entities.ProductResponse) error {
+	args := m.Called(currency, products)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyUsecase) LocalizeCart(currency string, pricing *entities.CartPricing) error {
+	args := m.Called(currency, pricing)
+	return args.Error(0)
+}
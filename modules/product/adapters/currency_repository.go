@@ -0,0 +1,63 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) usecase.ExchangeRateRepository {
+	return &gormExchangeRateRepository{db}
+}
+
+// SaveRates adds or replaces the rates of their currencies in one statement,
+// so an import applies in full or not at all.
+func (r *gormExchangeRateRepository) SaveRates(rates []entities.ExchangeRate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at", "updated_by"}),
+	}).Create(&rates).Error
+}
+
+func (r *gormExchangeRateRepository) GetRates() ([]entities.ExchangeRate, error) {
+	var rates []entities.ExchangeRate
+
+	if err := r.db.Order("currency").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (r *gormExchangeRateRepository) GetRate(currency string) (*entities.ExchangeRate, error) {
+	rate := new(entities.ExchangeRate)
+
+	if err := r.db.Where("currency = ?", currency).First(rate).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("currency not supported")
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (r *gormExchangeRateRepository) DeleteRate(currency string) error {
+	result := r.db.Where("currency = ?", currency).Delete(&entities.ExchangeRate{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("currency not supported")
+	}
+
+	return nil
+}
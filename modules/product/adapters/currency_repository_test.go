@@ -0,0 +1,83 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	saveRatesQuery  = `INSERT INTO "exchange_rates" ("currency","rate","updated_at","updated_by") VALUES ($1,$2,$3,$4),($5,$6,$7,$8) ON CONFLICT ("currency") DO UPDATE SET "rate"="excluded"."rate","updated_at"="excluded"."updated_at","updated_by"="excluded"."updated_by" RETURNING "id"`
+	getRateQuery    = `SELECT * FROM "exchange_rates" WHERE currency = $1 ORDER BY "exchange_rates"."id" LIMIT $2`
+	deleteRateQuery = `DELETE FROM "exchange_rates" WHERE currency = $1`
+)
+
+func TestSaveRates_gormRepo(t *testing.T) {
+	t.Run("save rates in one statement", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewExchangeRateRepository(gormDB)
+
+		actorID := uint(7)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(saveRatesQuery).
+			WithArgs("USD", 27350, now, 7, "JPY", 4215000, now, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		mock.ExpectCommit()
+
+		err := repo.SaveRates([]entities.ExchangeRate{
+			{Currency: "USD", Rate: 27350, UpdatedAt: now, UpdatedBy: &actorID},
+			{Currency: "JPY", Rate: 4215000, UpdatedAt: now, UpdatedBy: &actorID},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetRate_gormRepo(t *testing.T) {
+	t.Run("get rate given not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewExchangeRateRepository(gormDB)
+
+		mock.ExpectQuery(getRateQuery).
+			WithArgs("EUR", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.GetRate("EUR")
+
+		assert.EqualError(t, err, "currency not supported")
+	})
+}
+
+func TestDeleteRate_gormRepo(t *testing.T) {
+	t.Run("delete rate given not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewExchangeRateRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteRateQuery).
+			WithArgs("EUR").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.DeleteRate("EUR")
+
+		assert.EqualError(t, err, "currency not supported")
+	})
+}
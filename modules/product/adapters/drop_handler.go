@@ -0,0 +1,53 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpDropHandler struct {
+	usecase usecase.DropUsecase
+}
+
+func NewDropHandler(usecase usecase.DropUsecase) *httpDropHandler {
+	return &httpDropHandler{usecase}
+}
+
+func (h *httpDropHandler) ScheduleDrop(c echo.Context) error {
+	dropRequest := new(entities.DropRequest)
+	if err := request.ContextWrapper(c).Bind(dropRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	drop, err := h.usecase.ScheduleDrop(c.Param("id"), dropRequest)
+	if err != nil {
+		return dropError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, drop)
+}
+
+func (h *httpDropHandler) CancelDrop(c echo.Context) error {
+	if err := h.usecase.CancelDrop(c.Param("id")); err != nil {
+		return dropError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func dropError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "drop not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "drop has already ended":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,110 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduleDrop(t *testing.T) {
+	t.Run("schedule drop successfully", func(t *testing.T) {
+		mockService := new(MockDropUsecase)
+		handler := &httpDropHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		releaseAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+		mockService.On("ScheduleDrop", "1", &entities.DropRequest{ReleaseAt: releaseAt, PurchaseLimit: 2}).
+			Return(&entities.DropSummary{Status: "coming_soon", ReleaseAt: releaseAt, PurchaseLimit: 2}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"release_at":"2026-11-01T10:00:00Z","purchase_limit":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ScheduleDrop(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"status":"coming_soon","release_at":"2026-11-01T10:00:00Z","purchase_limit":2}`, response.Body.String())
+	})
+
+	t.Run("schedule drop ending before its release", func(t *testing.T) {
+		mockService := new(MockDropUsecase)
+		handler := &httpDropHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"release_at":"2026-11-01T10:00:00Z","end_at":"2026-11-01T09:00:00Z"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ScheduleDrop(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "ScheduleDrop", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelDrop(t *testing.T) {
+	t.Run("cancel drop given product without one", func(t *testing.T) {
+		mockService := new(MockDropUsecase)
+		handler := &httpDropHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CancelDrop", "1").Return(errors.New("drop not found"))
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.CancelDrop(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockDropUsecase struct {
+	mock.Mock
+}
+
+func (m *MockDropUsecase) ScheduleDrop(productID string, request *entities.DropRequest) (*entities.DropSummary, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*entities.DropSummary), args.Error(1)
+}
+
+func (m *MockDropUsecase) CancelDrop(productID string) error {
+	args := m.Called(productID)
+	return args.Error(0)
+}
+
+func (m *MockDropUsecase) SyncDrops() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDropUsecase) CheckPurchase(userID, productID uint, quantity int) error {
+	args := m.Called(userID, productID, quantity)
+	return args.Error(0)
+}
@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+)
+
+// dropWindowOpen is true for products whose release window is open at the
+// time given (twice) as its arguments.
+const dropWindowOpen = "release_at <= ? AND (release_end_at IS NULL OR release_end_at > ?)"
+
+type gormDropRepository struct {
+	db *gorm.DB
+}
+
+func NewDropRepository(db *gorm.DB) usecase.DropRepository {
+	return &gormDropRepository{db}
+}
+
+func (r *gormDropRepository) UpdateDrop(product *entities.Product) error {
+	result := r.db.Model(&entities.Product{}).Where("id = ?", product.ID).Updates(map[string]any{
+		"release_at":     product.ReleaseAt,
+		"release_end_at": product.ReleaseEndAt,
+		"purchase_limit": product.PurchaseLimit,
+		"off_sale":       product.OffSale,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("product not found")
+	}
+
+	return nil
+}
+
+// UpdateDropWindows puts every drop whose window is open at now on sale, and
+// every other drop off sale, touching only the products that change.
+func (r *gormDropRepository) UpdateDropWindows(now time.Time) (int64, error) {
+	offSale := gorm.Expr("NOT ("+dropWindowOpen+")", now, now)
+
+	result := r.db.Model(&entities.Product{}).
+		Where("release_at IS NOT NULL AND off_sale <> ?", offSale).
+		Update("off_sale", offSale)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// CheckPurchase runs the drop rules against units of a product a user puts in
+// their cart without reserving them, as blind boxes are.
+func (r *gormDropRepository) CheckPurchase(productID, userID uint, quantity int) error {
+	product := &entities.Product{}
+	if err := r.db.First(product, "id = ?", productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("product not found")
+		}
+		return err
+	}
+
+	return checkDropPurchase(r.db, product, &userID, 0, quantity)
+}
+
+// checkDropPurchase refuses to sell or hold more units of a locked product
+// while it is off sale, on pre-order or outside its release window, or beyond
+// its per-user purchase limit. The window is checked as well as OffSale so
+// nothing slips through between scheduler runs. Units the user holds or has
+// bought through other reservations count towards the limit; reservationID,
+// when set, is left out because its quantity is being replaced.
+func checkDropPurchase(tx *gorm.DB, product *entities.Product, userID *uint, reservationID uint, quantity int) error {
+	if product.OffSale || product.PreOrder || dropClosedAt(product, time.Now()) {
+		return errors.New("product is not on sale")
+	}
+
+	if product.PurchaseLimit == 0 {
+		return nil
+	}
+
+	if userID == nil {
+		return errors.New("sign in required")
+	}
+
+	var bought int64
+	if err := tx.Model(&entities.StockReservation{}).
+		Where("user_id = ? AND product_id = ? AND status IN ? AND id <> ?",
+			*userID, product.ID, []string{entities.ReservationActive, entities.ReservationConverted}, reservationID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&bought).Error; err != nil {
+		return errors.New("failed to retrieve reservations")
+	}
+
+	if int(bought)+quantity > product.PurchaseLimit {
+		return errors.New("purchase limit exceeded")
+	}
+
+	return nil
+}
+
+// dropClosedAt is true for a drop whose release window is not open at now.
+func dropClosedAt(product *entities.Product, now time.Time) bool {
+	if product.ReleaseAt == nil {
+		return false
+	}
+
+	return now.Before(*product.ReleaseAt) || (product.ReleaseEndAt != nil && !now.Before(*product.ReleaseEndAt))
+}
@@ -0,0 +1,224 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	updateDropQuery          = `UPDATE "products" SET "off_sale"=$1,"purchase_limit"=$2,"release_at"=$3,"release_end_at"=$4,"updated_at"=$5 WHERE id = $6 AND "products"."deleted_at" IS NULL`
+	updateDropWindowsQuery   = `UPDATE "products" SET "off_sale"=NOT (release_at <= $1 AND (release_end_at IS NULL OR release_end_at > $2)),"updated_at"=$3 WHERE (release_at IS NOT NULL AND off_sale <> NOT (release_at <= $4 AND (release_end_at IS NULL OR release_end_at > $5))) AND "products"."deleted_at" IS NULL`
+	getDropProductQuery      = `SELECT * FROM "products" WHERE id = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`
+	sumUserReservationsQuery = `SELECT COALESCE(SUM(quantity), 0) FROM "stock_reservations" WHERE (user_id = $1 AND product_id = $2 AND status IN ($3,$4) AND id <> $5) AND "stock_reservations"."deleted_at" IS NULL`
+)
+
+func TestUpdateDropWindows_gormRepo(t *testing.T) {
+	t.Run("open and close drops at now", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewDropRepository(gormDB)
+
+		now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateDropWindowsQuery).
+			WithArgs(now, now, sqlmock.AnyArg(), now, now).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		got, err := repo.UpdateDropWindows(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateDrop_gormRepo(t *testing.T) {
+	t.Run("schedule drop on a product", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewDropRepository(gormDB)
+
+		releaseAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateDropQuery).
+			WithArgs(true, 2, releaseAt, nil, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateDrop(&entities.Product{Model: gorm.Model{ID: 1}, ReleaseAt: &releaseAt, PurchaseLimit: 2, OffSale: true})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCheckDropPurchase_gormRepo(t *testing.T) {
+	t.Run("reserve an off-sale drop", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "off_sale"}).AddRow(1, 10, true))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 1})
+
+		assert.EqualError(t, err, "product is not on sale")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve a product on pre-order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "pre_order"}).AddRow(1, 10, true))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 1})
+
+		assert.EqualError(t, err, "product is not on sale")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve past the purchase limit", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		userID := uint(12)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "purchase_limit"}).AddRow(1, 10, 2))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(sumUserReservationsQuery).
+			WithArgs(12, 1, "active", "converted", 0).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", UserID: &userID, ProductID: 1, Quantity: 2})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve a drop whose window has closed before the scheduler ran", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		releaseAt := time.Now().Add(-2 * time.Hour)
+		endAt := time.Now().Add(-time.Minute)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "release_at", "release_end_at"}).AddRow(1, 10, releaseAt, endAt))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 1})
+
+		assert.EqualError(t, err, "product is not on sale")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve a limited drop without signing in", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "purchase_limit"}).AddRow(1, 10, 2))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 1})
+
+		assert.EqualError(t, err, "sign in required")
+	})
+}
+
+func TestCheckPurchase_gormRepo(t *testing.T) {
+	t.Run("check purchase counts the user's units", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewDropRepository(gormDB)
+
+		mock.ExpectQuery(getDropProductQuery).
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "purchase_limit"}).AddRow(20, 10, 3))
+		mock.ExpectQuery(sumUserReservationsQuery).
+			WithArgs(7, 20, "active", "converted", 0).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+
+		err := repo.CheckPurchase(20, 7, 3)
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("check purchase given product not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewDropRepository(gormDB)
+
+		mock.ExpectQuery(getDropProductQuery).
+			WithArgs(20, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		err := repo.CheckPurchase(20, 7, 1)
+
+		assert.EqualError(t, err, "product not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
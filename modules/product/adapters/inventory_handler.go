@@ -0,0 +1,112 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+const (
+	ContextUserIDKey = "userID"
+)
+
+type httpInventoryHandler struct {
+	usecase usecase.InventoryUsecase
+}
+
+func NewInventoryHandler(usecase usecase.InventoryUsecase) *httpInventoryHandler {
+	return &httpInventoryHandler{usecase}
+}
+
+func (h *httpInventoryHandler) Restock(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	restock := new(entities.StockRestockRequest)
+	if err := request.ContextWrapper(c).Bind(restock); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	movement, err := h.usecase.Restock(c.Param("id"), actorID, restock)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, movement)
+}
+
+func (h *httpInventoryHandler) AdjustStock(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	adjustment := new(entities.StockAdjustmentRequest)
+	if err := request.ContextWrapper(c).Bind(adjustment); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	movement, err := h.usecase.AdjustStock(c.Param("id"), actorID, adjustment)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, movement)
+}
+
+func (h *httpInventoryHandler) GetMovements(c echo.Context) error {
+	filter := new(entities.StockMovementFilter)
+	if err := request.ContextWrapper(c).Bind(filter); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	movements, err := h.usecase.GetMovements(c.Param("id"), filter)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, movements)
+}
+
+func (h *httpInventoryHandler) UpdateStockRules(c echo.Context) error {
+	rules := new(entities.StockRulesRequest)
+	if err := request.ContextWrapper(c).Bind(rules); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	updated, err := h.usecase.UpdateStockRules(c.Param("id"), rules)
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *httpInventoryHandler) GetLowStockReport(c echo.Context) error {
+	products, err := h.usecase.GetLowStockReport()
+	if err != nil {
+		return inventoryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+func inventoryError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "variant not found", "warehouse not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "variant required", "blind box stock is set by its figures":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "insufficient stock":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
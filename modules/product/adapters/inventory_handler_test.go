@@ -0,0 +1,316 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRestock(t *testing.T) {
+	t.Run("restock successfully", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		actorID := uint(7)
+		createdAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+		mockService.On("Restock", "1", uint(7), &entities.StockRestockRequest{Quantity: 24, ReferenceID: "PO-88"}).Return(&entities.StockMovement{
+			ID: 5, CreatedAt: createdAt, ProductID: 1, Kind: "restock", Quantity: 24, Balance: 30, ActorID: &actorID, ReferenceID: "PO-88",
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":24,"reference_id":"PO-88"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Restock(c)
+
+		expectedJSON := `{"id":5,"created_at":"2026-10-01T09:00:00Z","product_id":1,"kind":"restock","quantity":24,"balance":30,"actor_id":7,"reference_id":"PO-88"}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("restock given no user in token", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":24}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.Restock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "Restock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("restock given unknown kind", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"kind":"sale","quantity":24}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Restock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "Restock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("restock given blind box", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Restock", "20", uint(7), mock.Anything).Return((*entities.StockMovement)(nil), errors.New("blind box stock is set by its figures"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":24}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("20")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.Restock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"blind box stock is set by its figures"}`, response.Body.String())
+	})
+}
+
+func TestAdjustStock(t *testing.T) {
+	t.Run("adjust stock given no note", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":-2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.AdjustStock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "AdjustStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("adjust stock below zero", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("AdjustStock", "1", uint(7), &entities.StockAdjustmentRequest{Quantity: -40, Note: "damaged in storage"}).
+			Return((*entities.StockMovement)(nil), errors.New("insufficient stock"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":-40,"note":"damaged in storage"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.AdjustStock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"insufficient stock"}`, response.Body.String())
+	})
+}
+
+func TestGetStockMovements(t *testing.T) {
+	t.Run("get movements given filter", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetMovements", "1", &entities.StockMovementFilter{Kind: "sale", Limit: 10}).Return([]entities.StockMovement{
+			{ID: 9, CreatedAt: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), ProductID: 1, Kind: "sale", Quantity: -1, Balance: 29, ReferenceID: "ORD-1001"},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/?kind=sale&limit=10", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.GetMovements(c)
+
+		expectedJSON := `[{"id":9,"created_at":"2026-10-02T00:00:00Z","product_id":1,"kind":"sale","quantity":-1,"balance":29,"reference_id":"ORD-1001"}]`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("get movements given unknown product", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetMovements", "99", mock.Anything).Return(([]entities.StockMovement)(nil), errors.New("product not found"))
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("99")
+
+		err := handler.GetMovements(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestUpdateStockRules(t *testing.T) {
+	t.Run("update stock rules successfully", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		threshold, show := 5, true
+		mockService.On("UpdateStockRules", "1", &entities.StockRulesRequest{LowStockThreshold: &threshold, ShowWhenSoldOut: &show}).
+			Return(&entities.StockRules{ProductID: 1, LowStockThreshold: 5, ShowWhenSoldOut: true}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"low_stock_threshold":5,"show_when_sold_out":true}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.UpdateStockRules(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"product_id":1,"low_stock_threshold":5,"show_when_sold_out":true}`, response.Body.String())
+	})
+
+	t.Run("update stock rules given negative threshold", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"low_stock_threshold":-1}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.UpdateStockRules(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "UpdateStockRules", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetLowStockReport(t *testing.T) {
+	t.Run("get low stock report successfully", func(t *testing.T) {
+		mockService := new(MockInventoryUsecase)
+		handler := &httpInventoryHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetLowStockReport").Return([]entities.LowStockProduct{{ProductID: 4, Name: "Labubu Macaron", Stock: 0, Threshold: 3, Active: true}}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.GetLowStockReport(c)
+
+		expectedJSON := `[{"product_id":4,"name":"Labubu Macaron","stock":0,"threshold":3,"active":true}]`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+}
+
+type MockInventoryUsecase struct {
+	mock.Mock
+}
+
+func (m *MockInventoryUsecase) Restock(productID string, actorID uint, request *entities.StockRestockRequest) (*entities.StockMovement, error) {
+	args := m.Called(productID, actorID, request)
+	return args.Get(0).(*entities.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryUsecase) AdjustStock(productID string, actorID uint, request *entities.StockAdjustmentRequest) (*entities.StockMovement, error) {
+	args := m.Called(productID, actorID, request)
+	return args.Get(0).(*entities.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryUsecase) GetMovements(productID string, filter *entities.StockMovementFilter) ([]entities.StockMovement, error) {
+	args := m.Called(productID, filter)
+	return args.Get(0).([]entities.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryUsecase) ReconcileStock() (*entities.StockReconciliation, error) {
+	args := m.Called()
+	return args.Get(0).(*entities.StockReconciliation), args.Error(1)
+}
+
+func (m *MockInventoryUsecase) UpdateStockRules(productID string, request *entities.StockRulesRequest) (*entities.StockRules, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*entities.StockRules), args.Error(1)
+}
+
+func (m *MockInventoryUsecase) GetLowStockReport() ([]entities.LowStockProduct, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.LowStockProduct), args.Error(1)
+}
@@ -0,0 +1,282 @@
+package adapters
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormInventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) usecase.InventoryRepository {
+	return &gormInventoryRepository{db}
+}
+
+func (r *gormInventoryRepository) RecordMovement(movement *entities.StockMovement) (*entities.StockMovement, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		product, err := lockProduct(tx, movement.ProductID)
+		if err != nil {
+			return err
+		}
+
+		return applyStockMovement(tx, product, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+func (r *gormInventoryRepository) GetMovements(productID uint, filter *entities.StockMovementFilter) ([]entities.StockMovement, error) {
+	var movements []entities.StockMovement
+
+	query := r.db.Where("product_id = ?", productID)
+	if filter.VariantID != 0 {
+		query = query.Where("variant_id = ?", filter.VariantID)
+	}
+	if filter.WarehouseID != 0 {
+		query = query.Where("warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// stockDriftQuery compares every ledgered stock column with the sum of its
+// movements: products without variants, and each variant. Blind boxes and
+// cases are left out because their stock is derived from the figure pool.
+const stockDriftQuery = `SELECT p.id AS product_id, NULL AS variant_id, p.stock AS stock, COALESCE(SUM(m.quantity), 0) AS ledger
+FROM products p
+LEFT JOIN stock_movements m ON m.product_id = p.id AND m.variant_id IS NULL
+WHERE p.deleted_at IS NULL AND p.type = ?
+	AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id AND v.deleted_at IS NULL)
+GROUP BY p.id, p.stock
+HAVING p.stock <> COALESCE(SUM(m.quantity), 0)
+UNION ALL
+SELECT v.product_id, v.id, v.stock, COALESCE(SUM(m.quantity), 0)
+FROM product_variants v
+JOIN products p ON p.id = v.product_id AND p.deleted_at IS NULL
+LEFT JOIN stock_movements m ON m.variant_id = v.id
+WHERE v.deleted_at IS NULL
+GROUP BY v.id, v.product_id, v.stock
+HAVING v.stock <> COALESCE(SUM(m.quantity), 0)
+ORDER BY product_id, variant_id NULLS FIRST`
+
+func (r *gormInventoryRepository) GetStockDrift() ([]entities.StockDrift, error) {
+	drifts := []entities.StockDrift{}
+
+	if err := r.db.Raw(stockDriftQuery, entities.ProductTypeStandard).Scan(&drifts).Error; err != nil {
+		return nil, err
+	}
+
+	return drifts, nil
+}
+
+func (r *gormInventoryRepository) UpdateStockRules(product *entities.Product) error {
+	result := r.db.Model(&entities.Product{}).Where("id = ?", product.ID).Updates(map[string]any{
+		"low_stock_threshold": product.LowStockThreshold,
+		"show_when_sold_out":  product.ShowWhenSoldOut,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("product not found")
+	}
+
+	return nil
+}
+
+func (r *gormInventoryRepository) GetLowStockProducts() ([]entities.LowStockProduct, error) {
+	products := []entities.LowStockProduct{}
+
+	err := r.db.Model(&entities.Product{}).
+		Select("id AS product_id, name, stock, low_stock_threshold AS threshold, active").
+		Where("low_stock_threshold > 0 AND stock <= low_stock_threshold").
+		Order("stock, id").
+		Scan(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *gormInventoryRepository) GetPendingStockAlerts(limit int) ([]entities.StockAlert, error) {
+	var alerts []entities.StockAlert
+
+	if err := r.db.Where("notified_at IS NULL").Order("id").Limit(limit).Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (r *gormInventoryRepository) MarkStockAlertsNotified(ids []uint, notifiedAt time.Time) error {
+	return r.db.Model(&entities.StockAlert{}).Where("id IN ?", ids).Update("notified_at", notifiedAt).Error
+}
+
+func lockProduct(tx *gorm.DB, id any) (*entities.Product, error) {
+	product := &entities.Product{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(product, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("failed to retrieve product")
+	}
+
+	return product, nil
+}
+
+// applyStockMovement moves the stock of a locked product, or of the variant the
+// movement names, by movement.Quantity and appends the movement to the ledger.
+// Products that come in variants only hold stock through them. A sale shipped
+// from several warehouses is recorded as one entry per warehouse, and movement
+// is left holding the last of them.
+func applyStockMovement(tx *gorm.DB, product *entities.Product, movement *entities.StockMovement) error {
+	movement.ProductID = product.ID
+	before := product.Stock
+
+	var balance int
+	var err error
+	if movement.VariantID != nil {
+		balance, err = moveVariantStock(tx, product, *movement.VariantID, movement.Quantity)
+	} else {
+		balance, err = moveProductStock(tx, product, movement.Quantity)
+	}
+	if err != nil {
+		return err
+	}
+
+	entries, err := locateStockMovement(tx, movement)
+	if err != nil {
+		return err
+	}
+
+	variantID, itemBefore := movement.VariantID, balance-movement.Quantity
+	balance -= movement.Quantity
+	for i := range entries {
+		balance += entries[i].Quantity
+		entries[i].Balance = balance
+
+		if err := tx.Create(&entries[i]).Error; err != nil {
+			return errors.New("failed to record stock movement")
+		}
+	}
+	*movement = entries[len(entries)-1]
+
+	if movement.Kind == entities.StockMovementReservation || movement.Kind == entities.StockMovementRelease {
+		return nil
+	}
+
+	if err := raiseStockAlert(tx, product, before); err != nil {
+		return err
+	}
+
+	if variantID != nil && itemBefore <= 0 && balance > 0 {
+		if err := queueBackInStock(tx, product.ID, variantID); err != nil {
+			return err
+		}
+	}
+	if before <= 0 && product.Stock > 0 {
+		return queueBackInStock(tx, product.ID, nil)
+	}
+
+	return nil
+}
+
+// raiseStockAlert records an alert when a product's stock drops from above its
+// low-stock threshold to at or below it. Reservations and releases are left
+// out so that a held cart converting into a sale only alerts once.
+func raiseStockAlert(tx *gorm.DB, product *entities.Product, before int) error {
+	threshold := product.LowStockThreshold
+	if threshold == 0 || before <= threshold || product.Stock > threshold {
+		return nil
+	}
+
+	alert := &entities.StockAlert{ProductID: product.ID, Stock: product.Stock, Threshold: threshold}
+	if err := tx.Create(alert).Error; err != nil {
+		return errors.New("failed to record stock alert")
+	}
+
+	return nil
+}
+
+func moveProductStock(tx *gorm.DB, product *entities.Product, quantity int) (int, error) {
+	var variants int64
+	if err := tx.Model(&entities.ProductVariant{}).Where("product_id = ?", product.ID).Count(&variants).Error; err != nil {
+		return 0, errors.New("failed to retrieve product")
+	}
+	if variants > 0 {
+		return 0, errors.New("variant required")
+	}
+
+	balance := product.Stock + quantity
+	if balance < 0 {
+		return 0, errors.New("insufficient stock")
+	}
+
+	if err := tx.Model(product).Update("stock", balance).Error; err != nil {
+		return 0, errors.New("failed to update product stock")
+	}
+
+	return balance, nil
+}
+
+func moveVariantStock(tx *gorm.DB, product *entities.Product, variantID uint, quantity int) (int, error) {
+	variant := &entities.ProductVariant{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND product_id = ?", variantID, product.ID).
+		First(variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("variant not found")
+		}
+		return 0, errors.New("failed to retrieve product")
+	}
+
+	balance := variant.Stock + quantity
+	if balance < 0 {
+		return 0, errors.New("insufficient stock")
+	}
+
+	if err := tx.Model(variant).Update("stock", balance).Error; err != nil {
+		return 0, errors.New("failed to update product stock")
+	}
+
+	if err := syncProductStock(tx, product.ID); err != nil {
+		return 0, errors.New("failed to update product stock")
+	}
+	product.Stock += quantity
+
+	return balance, nil
+}
+
+// recordInitialStock opens the ledger of a newly created product or variant.
+func recordInitialStock(tx *gorm.DB, productID uint, variantID *uint, stock int) error {
+	if stock == 0 {
+		return nil
+	}
+
+	return tx.Create(&entities.StockMovement{
+		ProductID: productID,
+		VariantID: variantID,
+		Kind:      entities.StockMovementRestock,
+		Quantity:  stock,
+		Balance:   stock,
+		Note:      "initial stock",
+	}).Error
+}
@@ -0,0 +1,319 @@
+package adapters
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getStockMovementsQuery        = `SELECT * FROM "stock_movements" WHERE product_id = $1 AND variant_id = $2 AND kind = $3 ORDER BY id DESC LIMIT $4`
+	getProductSubscriptionsQuery  = `SELECT * FROM "stock_subscriptions" WHERE "product_id" = $1 AND "variant_id" IS NULL ORDER BY id FOR UPDATE`
+	insertStockNotificationsQuery = `INSERT INTO "stock_notifications" ("created_at","product_id","variant_id","user_id","email","sent_at") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12) RETURNING "id"`
+	deleteStockSubscriptionsQuery = `DELETE FROM "stock_subscriptions" WHERE "stock_subscriptions"."id" IN ($1,$2)`
+	insertStockAlertQuery         = `INSERT INTO "stock_alerts" ("created_at","product_id","stock","threshold","notified_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`
+	updateStockRulesQuery         = `UPDATE "products" SET "low_stock_threshold"=$1,"show_when_sold_out"=$2,"updated_at"=$3 WHERE id = $4 AND "products"."deleted_at" IS NULL`
+	getLowStockQuery              = `SELECT id AS product_id, name, stock, low_stock_threshold AS threshold, active FROM "products" WHERE (low_stock_threshold > 0 AND stock <= low_stock_threshold) AND "products"."deleted_at" IS NULL ORDER BY stock, id`
+	stockDriftTestQuery           = `SELECT p.id AS product_id, NULL AS variant_id, p.stock AS stock, COALESCE(SUM(m.quantity), 0) AS ledger FROM products p LEFT JOIN stock_movements m ON m.product_id = p.id AND m.variant_id IS NULL WHERE p.deleted_at IS NULL AND p.type = $1 AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id AND v.deleted_at IS NULL) GROUP BY p.id, p.stock HAVING p.stock <> COALESCE(SUM(m.quantity), 0) UNION ALL SELECT v.product_id, v.id, v.stock, COALESCE(SUM(m.quantity), 0) FROM product_variants v JOIN products p ON p.id = v.product_id AND p.deleted_at IS NULL LEFT JOIN stock_movements m ON m.variant_id = v.id WHERE v.deleted_at IS NULL GROUP BY v.id, v.product_id, v.stock HAVING v.stock <> COALESCE(SUM(m.quantity), 0) ORDER BY product_id, variant_id NULLS FIRST`
+)
+
+func TestRecordMovement_gormRepo(t *testing.T) {
+	t.Run("restock product and append to the ledger", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		actorID := uint(7)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "stock", "active"}).AddRow(1, "Dimoo Starry Night", 0, false))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(24, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "restock", 24, 24, 7, "PO-88", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+		mock.ExpectQuery(getProductSubscriptionsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		got, err := repo.RecordMovement(&entities.StockMovement{ProductID: 1, Kind: "restock", Quantity: 24, ActorID: &actorID, ReferenceID: "PO-88"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), got.ID)
+		assert.Equal(t, 24, got.Balance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("restock from sold out queues subscribers oldest first", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 0))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(6, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "restock", 6, 6, nil, "", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+		mock.ExpectQuery(getProductSubscriptionsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "user_id", "email"}).
+				AddRow(3, 1, nil, 12, "first@example.com").
+				AddRow(7, 1, nil, 40, "second@example.com"))
+		mock.ExpectQuery(insertStockNotificationsQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, 12, "first@example.com", nil, sqlmock.AnyArg(), 1, nil, 40, "second@example.com", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+		mock.ExpectExec(deleteStockSubscriptionsQuery).
+			WithArgs(3, 7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		_, err := repo.RecordMovement(&entities.StockMovement{ProductID: 1, Kind: "restock", Quantity: 6})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("adjust variant stock and resync product total", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		actorID, variantID := uint(7), uint(3)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 9))
+		mock.ExpectQuery(getVariantForUpdateQuery).
+			WithArgs(3, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "stock"}).AddRow(3, 1, 4))
+		mock.ExpectExec(updateVariantStockQuery).
+			WithArgs(3, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(sumVariantStockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(8))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(8, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, 3, nil, "adjustment", -1, 3, 7, "", "chipped paint").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+		mock.ExpectCommit()
+
+		got, err := repo.RecordMovement(&entities.StockMovement{ProductID: 1, VariantID: &variantID, Kind: "adjustment", Quantity: -1, ActorID: &actorID, Note: "chipped paint"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, got.Balance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("adjustment crossing the low-stock threshold raises an alert", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "low_stock_threshold"}).AddRow(1, 8, 5))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(5, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "adjustment", -3, 5, nil, "", "damaged").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery(insertStockAlertQuery).
+			WithArgs(sqlmock.AnyArg(), 1, 5, 5, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		_, err := repo.RecordMovement(&entities.StockMovement{ProductID: 1, Kind: "adjustment", Quantity: -3, Note: "damaged"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("adjust stock below zero", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 2))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		_, err := repo.RecordMovement(&entities.StockMovement{ProductID: 1, Kind: "adjustment", Quantity: -3, Note: "lost"})
+
+		assert.EqualError(t, err, "insufficient stock")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetMovements_gormRepo(t *testing.T) {
+	t.Run("get movements newest first", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectQuery(getStockMovementsQuery).
+			WithArgs(1, 3, "sale", 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "kind", "quantity", "balance", "reference_id"}).
+				AddRow(9, 1, 3, "sale", -1, 2, "ORD-1002").
+				AddRow(8, 1, 3, "sale", -1, 3, "ORD-1001"))
+
+		got, err := repo.GetMovements(1, &entities.StockMovementFilter{VariantID: 3, Kind: "sale", Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, uint(9), got[0].ID)
+		assert.Equal(t, uint(3), *got[0].VariantID)
+	})
+}
+
+func TestGetStockDrift_gormRepo(t *testing.T) {
+	t.Run("report items whose stock disagrees with the ledger", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectQuery(stockDriftTestQuery).
+			WithArgs("standard").
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "variant_id", "stock", "ledger"}).
+				AddRow(4, nil, 12, 10).
+				AddRow(9, 3, 0, 2))
+
+		got, err := repo.GetStockDrift()
+
+		variantID := uint(3)
+		want := []entities.StockDrift{
+			{ProductID: 4, Stock: 12, Ledger: 10},
+			{ProductID: 9, VariantID: &variantID, Stock: 0, Ledger: 2},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("report drift given database error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectQuery(stockDriftTestQuery).
+			WithArgs("standard").
+			WillReturnError(errors.New("connection reset"))
+
+		_, err := repo.GetStockDrift()
+
+		assert.EqualError(t, err, "connection reset")
+	})
+}
+
+func TestUpdateStockRules_gormRepo(t *testing.T) {
+	t.Run("update stock rules and relist a sold-out product", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateStockRulesQuery).
+			WithArgs(5, true, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateStockRules(&entities.Product{Model: gorm.Model{ID: 1}, LowStockThreshold: 5, ShowWhenSoldOut: true})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("update stock rules given unknown product", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateStockRulesQuery).
+			WithArgs(0, false, sqlmock.AnyArg(), 99).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.UpdateStockRules(&entities.Product{Model: gorm.Model{ID: 99}})
+
+		assert.EqualError(t, err, "product not found")
+	})
+}
+
+func TestGetLowStockProducts_gormRepo(t *testing.T) {
+	t.Run("list products at or below their threshold", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		mock.ExpectQuery(getLowStockQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "name", "stock", "threshold", "active"}).
+				AddRow(4, "Labubu Macaron", 0, 3, true).
+				AddRow(1, "Dimoo Starry Night", 2, 5, true))
+
+		got, err := repo.GetLowStockProducts()
+
+		want := []entities.LowStockProduct{
+			{ProductID: 4, Name: "Labubu Macaron", Stock: 0, Threshold: 3, Active: true},
+			{ProductID: 1, Name: "Dimoo Starry Night", Stock: 2, Threshold: 5, Active: true},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
@@ -0,0 +1,36 @@
+package adapters
+
+import (
+	"log"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+)
+
+// logAdminNotifier and logCustomerNotifier write notifications to the service
+// log. They stand in until the store has a mail channel.
+type logAdminNotifier struct{}
+
+func NewLogAdminNotifier() usecase.AdminNotifier {
+	return &logAdminNotifier{}
+}
+
+func (n *logAdminNotifier) NotifyLowStock(alerts []entities.StockAlert) error {
+	for _, alert := range alerts {
+		log.Printf("low stock: product %d has %d left (threshold %d)", alert.ProductID, alert.Stock, alert.Threshold)
+	}
+
+	return nil
+}
+
+type logCustomerNotifier struct{}
+
+func NewLogCustomerNotifier() usecase.CustomerNotifier {
+	return &logCustomerNotifier{}
+}
+
+func (n *logCustomerNotifier) NotifyBackInStock(notification entities.StockNotification) error {
+	log.Printf("back in stock: product %d is available again, notifying %s", notification.ProductID, notification.Email)
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package adapters
+
+import (
+	orderEntities "github.com/phetployst/art-toys-store/modules/order/entities"
+	orderUsecase "github.com/phetployst/art-toys-store/modules/order/usecase"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
+// orderBook serves the product module's Orders from the order service's
+// usecase.
+type orderBook struct {
+	orders orderUsecase.OrderUsecase
+}
+
+func NewOrderBook(orders orderUsecase.OrderUsecase) usecase.Orders {
+	return &orderBook{orders}
+}
+
+func (b *orderBook) PlaceOrder(userID, productID uint, variantID *uint, price money.Money) (*entities.PlacedOrder, error) {
+	order, err := b.orders.PlaceOrder(userID, []orderEntities.OrderItem{{
+		ProductID:  productID,
+		VariantID:  variantID,
+		Quantity:   1,
+		TotalPrice: price,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.PlacedOrder{ID: order.ID, ReferenceID: order.ReferenceID, Total: order.TotalAmount}, nil
+}
+
+func (b *orderBook) SettleOrder(referenceID string, amount money.Money) error {
+	_, err := b.orders.SettleOrder(referenceID, amount)
+	return err
+}
+
+func (b *orderBook) CancelOrder(userID uint, referenceID string) error {
+	_, err := b.orders.CancelOrder(userID, referenceID)
+	return err
+}
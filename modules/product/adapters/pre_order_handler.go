@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpPreOrderHandler struct {
+	usecase usecase.PreOrderUsecase
+}
+
+func NewPreOrderHandler(usecase usecase.PreOrderUsecase) *httpPreOrderHandler {
+	return &httpPreOrderHandler{usecase}
+}
+
+func (h *httpPreOrderHandler) ConfigurePreOrder(c echo.Context) error {
+	offerRequest := new(entities.PreOrderOfferRequest)
+	if err := request.ContextWrapper(c).Bind(offerRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	offer, err := h.usecase.ConfigurePreOrder(c.Param("id"), offerRequest)
+	if err != nil {
+		return preOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, offer)
+}
+
+func (h *httpPreOrderHandler) GetPreOrder(c echo.Context) error {
+	offer, err := h.usecase.GetPreOrder(c.Param("id"))
+	if err != nil {
+		return preOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, offer)
+}
+
+func (h *httpPreOrderHandler) PlacePreOrder(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	preOrderRequest := new(entities.PreOrderRequest)
+	if err := request.ContextWrapper(c).Bind(preOrderRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	line, err := h.usecase.PlacePreOrder(c.Param("id"), userID, preOrderRequest)
+	if err != nil {
+		return preOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, line)
+}
+
+func (h *httpPreOrderHandler) GetPreOrders(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	lines, err := h.usecase.GetPreOrders(userID)
+	if err != nil {
+		return preOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, lines)
+}
+
+func (h *httpPreOrderHandler) ReleasePreOrders(c echo.Context) error {
+	releaseRequest := new(entities.PreOrderReleaseRequest)
+	if err := request.ContextWrapper(c).Bind(releaseRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	lines, err := h.usecase.ReleasePreOrders(c.Param("id"), releaseRequest)
+	if err != nil {
+		return preOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, lines)
+}
+
+// ConfirmPreOrderPayment takes the payment provider's confirmation for a
+// pre-order's deposit or balance. It must sit behind the payment signature
+// middleware.
+func (h *httpPreOrderHandler) ConfirmPreOrderPayment(c echo.Context) error {
+	confirmation := new(payment.Confirmation)
+	if err := request.ContextWrapper(c).Bind(confirmation); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	line, err := h.usecase.SettlePreOrderPayment(confirmation)
+	if err != nil {
+		return preOrderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, line)
+}
+
+func preOrderError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "pre-order not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "product cannot be pre-ordered", "deposit must be below the price", "cap is below pre-orders taken",
+		"unsupported currency":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "pre-orders are closed", "pre-order cap reached", "pre-order has no balance due", "payment deadline has passed",
+		"payment amount does not match":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
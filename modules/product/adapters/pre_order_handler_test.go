@@ -0,0 +1,280 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestConfigurePreOrder(t *testing.T) {
+	t.Run("configure pre-order successfully", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ConfigurePreOrder", "1", mock.MatchedBy(func(r *entities.PreOrderOfferRequest) bool {
+			return r.Cap == 50 && r.Deposit == money.New(2000, "THB")
+		})).Return(&entities.PreOrderOffer{Model: gorm.Model{ID: 3}, ProductID: 1, Cap: 50, Deposit: money.New(2000, "THB"), Open: true}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"expected_ship_date":"2027-03-01T00:00:00Z","deposit":{"amount":2000,"currency":"THB"},"cap":50}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ConfigurePreOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("configure pre-order without a cap", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"expected_ship_date":"2027-03-01T00:00:00Z"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ConfigurePreOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "ConfigurePreOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("configure pre-order given deposit above the price", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ConfigurePreOrder", "1", mock.Anything).Return((*entities.PreOrderOffer)(nil), errors.New("deposit must be below the price"))
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"expected_ship_date":"2027-03-01T00:00:00Z","deposit":{"amount":20000,"currency":"THB"},"cap":50}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ConfigurePreOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestPlacePreOrder(t *testing.T) {
+	t.Run("place pre-order successfully", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("PlacePreOrder", "1", uint(5), &entities.PreOrderRequest{Quantity: 2}).
+			Return(&entities.PreOrderLine{ID: 8, Status: entities.PreOrderAwaitingDeposit, ReferenceID: "a1b2"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(5))
+
+		err := handler.PlacePreOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("place pre-order without signing in", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.PlacePreOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "PlacePreOrder", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("place pre-order once the cap is reached", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("PlacePreOrder", "1", uint(5), mock.Anything).Return((*entities.PreOrderLine)(nil), errors.New("pre-order cap reached"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"quantity":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(5))
+
+		err := handler.PlacePreOrder(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestReleasePreOrders(t *testing.T) {
+	t.Run("release pre-orders successfully", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ReleasePreOrders", "1", &entities.PreOrderReleaseRequest{Limit: 10}).
+			Return([]entities.PreOrderLine{{ID: 8, Status: entities.PreOrderAwaitingBalance}}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"limit":10}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ReleasePreOrders(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("release pre-orders given unexpected error", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ReleasePreOrders", "1", mock.Anything).Return(([]entities.PreOrderLine)(nil), errors.New("database error"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.ReleasePreOrders(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
+func TestConfirmPreOrderPayment(t *testing.T) {
+	t.Run("confirm payment of a deposit", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SettlePreOrderPayment", &payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(4000, "THB")}).
+			Return(&entities.PreOrderLine{ID: 8, Status: entities.PreOrderAwaitingStock}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"a1b2","amount":{"amount":4000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmPreOrderPayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("confirm payment of a balance not yet due", func(t *testing.T) {
+		mockService := new(MockPreOrderUsecase)
+		handler := &httpPreOrderHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SettlePreOrderPayment", mock.Anything).Return((*entities.PreOrderLine)(nil), errors.New("pre-order has no balance due"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"c3d4","amount":{"amount":16000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmPreOrderPayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+type MockPreOrderUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPreOrderUsecase) ConfigurePreOrder(productID string, request *entities.PreOrderOfferRequest) (*entities.PreOrderOffer, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*entities.PreOrderOffer), args.Error(1)
+}
+
+func (m *MockPreOrderUsecase) GetPreOrder(productID string) (*entities.PreOrderOffer, error) {
+	args := m.Called(productID)
+	return args.Get(0).(*entities.PreOrderOffer), args.Error(1)
+}
+
+func (m *MockPreOrderUsecase) PlacePreOrder(productID string, userID uint, request *entities.PreOrderRequest) (*entities.PreOrderLine, error) {
+	args := m.Called(productID, userID, request)
+	return args.Get(0).(*entities.PreOrderLine), args.Error(1)
+}
+
+func (m *MockPreOrderUsecase) GetPreOrders(userID uint) ([]entities.PreOrderLine, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entities.PreOrderLine), args.Error(1)
+}
+
+func (m *MockPreOrderUsecase) ReleasePreOrders(productID string, request *entities.PreOrderReleaseRequest) ([]entities.PreOrderLine, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).([]entities.PreOrderLine), args.Error(1)
+}
+
+func (m *MockPreOrderUsecase) SettlePreOrderPayment(confirmation *payment.Confirmation) (*entities.PreOrderLine, error) {
+	args := m.Called(confirmation)
+	return args.Get(0).(*entities.PreOrderLine), args.Error(1)
+}
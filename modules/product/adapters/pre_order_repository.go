@@ -0,0 +1,306 @@
+package adapters
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormPreOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPreOrderRepository(db *gorm.DB) usecase.PreOrderRepository {
+	return &gormPreOrderRepository{db}
+}
+
+// SaveOffer saves the offer and takes the product off regular sale, or puts it
+// back, to match.
+func (r *gormPreOrderRepository) SaveOffer(offer *entities.PreOrderOffer) (*entities.PreOrderOffer, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(offer).Error; err != nil {
+			return err
+		}
+
+		return syncPreOrderMode(tx, offer, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+func (r *gormPreOrderRepository) GetOffer(productID uint) (*entities.PreOrderOffer, error) {
+	offer := &entities.PreOrderOffer{}
+
+	if err := r.db.Where("product_id = ?", productID).First(offer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pre-order not found")
+		}
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// InsertLine takes the line's units out of the offer's cap and issues the
+// reference its deposit is paid under. Units of lines whose deposit is overdue
+// go back into the cap first. Pre-orders never touch stock; the units are sold
+// to the line when it is released.
+func (r *gormPreOrderRepository) InsertLine(line *entities.PreOrderLine) (*entities.PreOrderLine, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		offer := &entities.PreOrderOffer{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("product_id = ?", line.ProductID).First(offer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("pre-order not found")
+			}
+			return err
+		}
+
+		if err := lapsePreOrderDeposits(tx, offer, time.Now()); err != nil {
+			return err
+		}
+
+		if !offer.Open {
+			return errors.New("pre-orders are closed")
+		}
+		if offer.Taken+line.Quantity > offer.Cap {
+			return errors.New("pre-order cap reached")
+		}
+
+		referenceID, err := newOrderReference()
+		if err != nil {
+			return err
+		}
+
+		line.OfferID = offer.ID
+		line.ReferenceID = referenceID
+		if err := tx.Create(line).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(offer).Update("taken", offer.Taken+line.Quantity).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+func (r *gormPreOrderRepository) GetLinesByUser(userID uint) ([]entities.PreOrderLine, error) {
+	var lines []entities.PreOrderLine
+
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// ReleaseLines sells arrived stock to the product's waiting lines, oldest
+// first, and stops at the first line stock cannot cover so later lines never
+// jump the queue. Lines with a balance left are issued the reference it is
+// paid under. A limit of 0 releases as many lines as stock allows. The product
+// goes back on regular sale once its offer is closed and no line waits.
+func (r *gormPreOrderRepository) ReleaseLines(productID uint, limit int, releasedAt time.Time) ([]entities.PreOrderLine, error) {
+	var released []entities.PreOrderLine
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		product, err := lockProduct(tx, productID)
+		if err != nil {
+			return err
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND status = ?", productID, entities.PreOrderAwaitingStock).
+			Order("id")
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+
+		var lines []entities.PreOrderLine
+		if err := query.Find(&lines).Error; err != nil {
+			return err
+		}
+
+		for i := range lines {
+			if lines[i].Quantity > product.Stock {
+				break
+			}
+
+			if err := applyStockMovement(tx, product, &entities.StockMovement{
+				Kind:        entities.StockMovementSale,
+				Quantity:    -lines[i].Quantity,
+				ReferenceID: lines[i].ReferenceID,
+			}); err != nil {
+				return err
+			}
+
+			updates := map[string]any{"status": entities.PreOrderPaid, "released_at": releasedAt}
+			if lines[i].BalanceDue.IsPositive() {
+				balanceReferenceID, err := newOrderReference()
+				if err != nil {
+					return err
+				}
+
+				updates["status"] = entities.PreOrderAwaitingBalance
+				updates["balance_reference_id"] = balanceReferenceID
+				lines[i].BalanceReferenceID = balanceReferenceID
+			}
+
+			if err := tx.Model(&lines[i]).Updates(updates).Error; err != nil {
+				return err
+			}
+
+			lines[i].Status = updates["status"].(string)
+			lines[i].ReleasedAt = &releasedAt
+			released = append(released, lines[i])
+		}
+
+		offer := &entities.PreOrderOffer{}
+		if err := tx.Where("product_id = ?", productID).First(offer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return syncPreOrderMode(tx, offer, releasedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+// PayLine records the deposit or balance confirmed paid for amount under
+// referenceID, which must be what the line owes under it. A deposit is
+// refused once the line's payment window has passed. A confirmation repeated
+// for a line already past that step changes nothing.
+func (r *gormPreOrderRepository) PayLine(referenceID string, amount money.Money, now time.Time) (*entities.PreOrderLine, error) {
+	line := &entities.PreOrderLine{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("offer_id").
+			Where("reference_id = ? OR balance_reference_id = ?", referenceID, referenceID).
+			First(line).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("pre-order not found")
+			}
+			return err
+		}
+
+		// The offer is locked first, as InsertLine does, so a deposit cannot
+		// be confirmed while its line is being lapsed.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entities.PreOrderOffer{}, line.OfferID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("offer_id = ? AND (reference_id = ? OR balance_reference_id = ?)", line.OfferID, referenceID, referenceID).
+			First(line).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("pre-order not found")
+			}
+			return err
+		}
+
+		due, next := line.BalanceDue, entities.PreOrderPaid
+		if line.ReferenceID == referenceID {
+			if line.Status == entities.PreOrderLapsed {
+				return errors.New("payment deadline has passed")
+			}
+			if line.Status != entities.PreOrderAwaitingDeposit {
+				return nil
+			}
+			if line.PaymentDueAt == nil || !now.Before(*line.PaymentDueAt) {
+				return errors.New("payment deadline has passed")
+			}
+			due, next = line.DepositPaid, entities.PreOrderAwaitingStock
+		} else {
+			if line.Status == entities.PreOrderPaid {
+				return nil
+			}
+			if line.Status != entities.PreOrderAwaitingBalance {
+				return errors.New("pre-order has no balance due")
+			}
+		}
+
+		if amount != due {
+			return errors.New("payment amount does not match")
+		}
+
+		line.Status = next
+		return tx.Model(line).Update("status", next).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+// lapsePreOrderDeposits lapses the lines of a locked offer whose deposit was
+// not paid by their deadline and gives their units back to the cap.
+func lapsePreOrderDeposits(tx *gorm.DB, offer *entities.PreOrderOffer, now time.Time) error {
+	var lapsed []entities.PreOrderLine
+	if err := tx.Where("offer_id = ? AND status = ? AND payment_due_at <= ?", offer.ID, entities.PreOrderAwaitingDeposit, now).
+		Find(&lapsed).Error; err != nil {
+		return err
+	}
+
+	if len(lapsed) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(lapsed))
+	for i := range lapsed {
+		ids[i] = lapsed[i].ID
+		offer.Taken -= lapsed[i].Quantity
+	}
+
+	if err := tx.Model(&entities.PreOrderLine{}).Where("id IN ?", ids).Update("status", entities.PreOrderLapsed).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(offer).Update("taken", offer.Taken).Error
+}
+
+// syncPreOrderMode keeps the offer's product off regular sale while the offer
+// is open or has lines waiting for stock, or for a deposit still in time, so
+// arrived units go to the pre-orders first.
+func syncPreOrderMode(tx *gorm.DB, offer *entities.PreOrderOffer, now time.Time) error {
+	preOrder := offer.Open
+	if !preOrder {
+		var waiting int64
+		if err := tx.Model(&entities.PreOrderLine{}).
+			Where("offer_id = ? AND (status = ? OR (status = ? AND payment_due_at > ?))",
+				offer.ID, entities.PreOrderAwaitingStock, entities.PreOrderAwaitingDeposit, now).
+			Count(&waiting).Error; err != nil {
+			return err
+		}
+		preOrder = waiting > 0
+	}
+
+	return tx.Model(&entities.Product{}).Where("id = ?", offer.ProductID).Update("pre_order", preOrder).Error
+}
+
+// newOrderReference returns a random reference for a new order.
+func newOrderReference() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
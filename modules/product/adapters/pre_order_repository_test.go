@@ -0,0 +1,320 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	lockPreOrderOfferQuery   = `SELECT * FROM "pre_order_offers" WHERE product_id = $1 AND "pre_order_offers"."deleted_at" IS NULL ORDER BY "pre_order_offers"."id" LIMIT $2 FOR UPDATE`
+	insertPreOrderLineQuery  = `INSERT INTO "pre_order_lines" ("created_at","updated_at","offer_id","product_id","user_id","quantity","unit_price_amount","unit_price_currency","deposit_paid_amount","deposit_paid_currency","balance_due_amount","balance_due_currency","reference_id","balance_reference_id","status","payment_due_at","released_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17) RETURNING "id"`
+	getLapsedDepositsQuery   = `SELECT * FROM "pre_order_lines" WHERE offer_id = $1 AND status = $2 AND payment_due_at <= $3`
+	lapsePreOrderLinesQuery  = `UPDATE "pre_order_lines" SET "status"=$1,"updated_at"=$2 WHERE id IN ($3)`
+	updatePreOrderTakenQuery = `UPDATE "pre_order_offers" SET "taken"=$1,"updated_at"=$2 WHERE "pre_order_offers"."deleted_at" IS NULL AND "id" = $3`
+	getWaitingPreOrdersQuery = `SELECT * FROM "pre_order_lines" WHERE product_id = $1 AND status = $2 ORDER BY id FOR UPDATE`
+	releasePreOrderLineQuery = `UPDATE "pre_order_lines" SET "balance_reference_id"=$1,"released_at"=$2,"status"=$3,"updated_at"=$4 WHERE "id" = $5`
+	getPreOrderOfferQuery    = `SELECT * FROM "pre_order_offers" WHERE product_id = $1 AND "pre_order_offers"."deleted_at" IS NULL ORDER BY "pre_order_offers"."id" LIMIT $2`
+	setPreOrderModeQuery     = `UPDATE "products" SET "pre_order"=$1,"updated_at"=$2 WHERE id = $3 AND "products"."deleted_at" IS NULL`
+	getLineOfferQuery        = `SELECT "offer_id" FROM "pre_order_lines" WHERE reference_id = $1 OR balance_reference_id = $2 ORDER BY "pre_order_lines"."id" LIMIT $3`
+	lockOfferByIDQuery       = `SELECT * FROM "pre_order_offers" WHERE "pre_order_offers"."id" = $1 AND "pre_order_offers"."deleted_at" IS NULL ORDER BY "pre_order_offers"."id" LIMIT $2 FOR UPDATE`
+	lockPaidLineQuery        = `SELECT * FROM "pre_order_lines" WHERE offer_id = $1 AND (reference_id = $2 OR balance_reference_id = $3) ORDER BY "pre_order_lines"."id" LIMIT $4 FOR UPDATE`
+	payPreOrderLineQuery     = `UPDATE "pre_order_lines" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`
+)
+
+func TestInsertLine_gormRepo(t *testing.T) {
+	line := func() *entities.PreOrderLine {
+		return &entities.PreOrderLine{
+			ProductID:   1,
+			UserID:      5,
+			Quantity:    2,
+			UnitPrice:   money.New(10000, "THB"),
+			DepositPaid: money.New(4000, "THB"),
+			BalanceDue:  money.New(16000, "THB"),
+			Status:      entities.PreOrderAwaitingDeposit,
+		}
+	}
+
+	t.Run("place pre-order within the cap", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockPreOrderOfferQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "cap", "taken", "open"}).AddRow(3, 1, 10, 8, true))
+		mock.ExpectQuery(getLapsedDepositsQuery).
+			WithArgs(3, "awaiting_deposit", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(insertPreOrderLineQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 1, 5, 2, 10000, "THB", 4000, "THB", 16000, "THB", sqlmock.AnyArg(), "", "awaiting_deposit", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+		mock.ExpectExec(updatePreOrderTakenQuery).
+			WithArgs(10, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.InsertLine(line())
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(8), got.ID)
+		assert.Equal(t, uint(3), got.OfferID)
+		assert.Len(t, got.ReferenceID, 32)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("place pre-order in the cap of a lapsed deposit", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockPreOrderOfferQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "cap", "taken", "open"}).AddRow(3, 1, 10, 10, true))
+		mock.ExpectQuery(getLapsedDepositsQuery).
+			WithArgs(3, "awaiting_deposit", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "quantity"}).AddRow(6, 2))
+		mock.ExpectExec(lapsePreOrderLinesQuery).
+			WithArgs("lapsed", sqlmock.AnyArg(), 6).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(updatePreOrderTakenQuery).
+			WithArgs(8, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertPreOrderLineQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 1, 5, 2, 10000, "THB", 4000, "THB", 16000, "THB", sqlmock.AnyArg(), "", "awaiting_deposit", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+		mock.ExpectExec(updatePreOrderTakenQuery).
+			WithArgs(10, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, err := repo.InsertLine(line())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("place pre-order past the cap", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockPreOrderOfferQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "cap", "taken", "open"}).AddRow(3, 1, 10, 9, true))
+		mock.ExpectQuery(getLapsedDepositsQuery).
+			WithArgs(3, "awaiting_deposit", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.InsertLine(line())
+
+		assert.EqualError(t, err, "pre-order cap reached")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("place pre-order once closed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockPreOrderOfferQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "cap", "taken", "open"}).AddRow(3, 1, 10, 0, false))
+		mock.ExpectQuery(getLapsedDepositsQuery).
+			WithArgs(3, "awaiting_deposit", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.InsertLine(line())
+
+		assert.EqualError(t, err, "pre-orders are closed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseLines_gormRepo(t *testing.T) {
+	releasedAt := time.Date(2027, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("release oldest lines until stock runs short", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency", "stock"}).AddRow(1, 10000, "THB", 3))
+		mock.ExpectQuery(getWaitingPreOrdersQuery).
+			WithArgs(1, "awaiting_stock").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "quantity", "balance_due_amount", "balance_due_currency", "reference_id", "status"}).
+				AddRow(8, 1, 2, 16000, "THB", "order-1", "awaiting_stock").
+				AddRow(9, 1, 2, 0, "THB", "order-2", "awaiting_stock").
+				AddRow(10, 1, 1, 0, "THB", "order-3", "awaiting_stock"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(1, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(fulfilProductSaleQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "sale", -2, 1, nil, "order-1", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+		mock.ExpectExec(releasePreOrderLineQuery).
+			WithArgs(sqlmock.AnyArg(), releasedAt, "awaiting_balance", sqlmock.AnyArg(), 8).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getPreOrderOfferQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "open"}).AddRow(3, 1, true))
+		mock.ExpectExec(setPreOrderModeQuery).
+			WithArgs(true, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.ReleaseLines(1, 0, releasedAt)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, entities.PreOrderAwaitingBalance, got[0].Status)
+		assert.Len(t, got[0].BalanceReferenceID, 32)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPayLine_gormRepo(t *testing.T) {
+	now := time.Date(2027, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("pay the deposit of a new line", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getLineOfferQuery).
+			WithArgs("a1b2", "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"offer_id"}).AddRow(3))
+		mock.ExpectQuery(lockOfferByIDQuery).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}).AddRow(3, 1))
+		mock.ExpectQuery(lockPaidLineQuery).
+			WithArgs(3, "a1b2", "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "offer_id", "deposit_paid_amount", "deposit_paid_currency", "reference_id", "status", "payment_due_at"}).
+				AddRow(8, 3, 4000, "THB", "a1b2", "awaiting_deposit", now.Add(time.Minute)))
+		mock.ExpectExec(payPreOrderLineQuery).
+			WithArgs("awaiting_stock", sqlmock.AnyArg(), 8).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.PayLine("a1b2", money.New(4000, "THB"), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.PreOrderAwaitingStock, got.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pay the deposit for the wrong amount", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getLineOfferQuery).
+			WithArgs("a1b2", "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"offer_id"}).AddRow(3))
+		mock.ExpectQuery(lockOfferByIDQuery).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}).AddRow(3, 1))
+		mock.ExpectQuery(lockPaidLineQuery).
+			WithArgs(3, "a1b2", "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "offer_id", "deposit_paid_amount", "deposit_paid_currency", "reference_id", "status", "payment_due_at"}).
+				AddRow(8, 3, 4000, "THB", "a1b2", "awaiting_deposit", now.Add(time.Minute)))
+		mock.ExpectRollback()
+
+		_, err := repo.PayLine("a1b2", money.New(100, "THB"), now)
+
+		assert.EqualError(t, err, "payment amount does not match")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pay the deposit after the deadline", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getLineOfferQuery).
+			WithArgs("a1b2", "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"offer_id"}).AddRow(3))
+		mock.ExpectQuery(lockOfferByIDQuery).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}).AddRow(3, 1))
+		mock.ExpectQuery(lockPaidLineQuery).
+			WithArgs(3, "a1b2", "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "offer_id", "reference_id", "status"}).AddRow(8, 3, "a1b2", "lapsed"))
+		mock.ExpectRollback()
+
+		_, err := repo.PayLine("a1b2", money.New(4000, "THB"), now)
+
+		assert.EqualError(t, err, "payment deadline has passed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pay the balance of a released line", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPreOrderRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getLineOfferQuery).
+			WithArgs("c3d4", "c3d4", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"offer_id"}).AddRow(3))
+		mock.ExpectQuery(lockOfferByIDQuery).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}).AddRow(3, 1))
+		mock.ExpectQuery(lockPaidLineQuery).
+			WithArgs(3, "c3d4", "c3d4", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "offer_id", "balance_due_amount", "balance_due_currency", "reference_id", "balance_reference_id", "status"}).
+				AddRow(8, 3, 16000, "THB", "a1b2", "c3d4", "awaiting_balance"))
+		mock.ExpectExec(payPreOrderLineQuery).
+			WithArgs("paid", sqlmock.AnyArg(), 8).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.PayLine("c3d4", money.New(16000, "THB"), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.PreOrderPaid, got.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
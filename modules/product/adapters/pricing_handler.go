@@ -0,0 +1,111 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpPricingHandler struct {
+	usecase usecase.PricingUsecase
+}
+
+func NewPricingHandler(usecase usecase.PricingUsecase) *httpPricingHandler {
+	return &httpPricingHandler{usecase}
+}
+
+func (h *httpPricingHandler) SetSale(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	saleRequest := new(entities.SaleRequest)
+	if err := request.ContextWrapper(c).Bind(saleRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	product, err := h.usecase.SetSale(c.Param("id"), actorID, saleRequest)
+	if err != nil {
+		return pricingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+func (h *httpPricingHandler) EndSale(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	if err := h.usecase.EndSale(c.Param("id"), actorID); err != nil {
+		return pricingError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpPricingHandler) GetPriceHistory(c echo.Context) error {
+	history, err := h.usecase.GetPriceHistory(c.Param("id"))
+	if err != nil {
+		return pricingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+func (h *httpPricingHandler) SchedulePrice(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	scheduleRequest := new(entities.ScheduledPriceRequest)
+	if err := request.ContextWrapper(c).Bind(scheduleRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	scheduled, err := h.usecase.SchedulePrice(c.Param("id"), actorID, scheduleRequest)
+	if err != nil {
+		return pricingError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, scheduled)
+}
+
+func (h *httpPricingHandler) GetScheduledPrices(c echo.Context) error {
+	scheduled, err := h.usecase.GetScheduledPrices(c.Param("id"))
+	if err != nil {
+		return pricingError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, scheduled)
+}
+
+func (h *httpPricingHandler) CancelScheduledPrice(c echo.Context) error {
+	if err := h.usecase.CancelScheduledPrice(c.Param("id"), c.Param("schedule_id")); err != nil {
+		return pricingError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func pricingError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "sale not found", "scheduled price not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "sale price must be below the price", "sale has already ended", "sale must end after it starts",
+		"price change must be in the future", "unsupported currency", "price must be positive":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "product with variants cannot go on sale":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
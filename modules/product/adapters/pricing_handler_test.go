@@ -0,0 +1,223 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSetSale(t *testing.T) {
+	t.Run("set sale successfully", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		listPrice := money.New(10000, "THB")
+		mockService.On("SetSale", "1", uint(9), &entities.SaleRequest{Price: money.New(7500, "THB")}).
+			Return(&entities.ProductResponse{ID: 1, Price: money.New(7500, "THB"), CompareAtPrice: &listPrice}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"price":{"amount":7500,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SetSale(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"compare_at_price":{"amount":10000,"currency":"THB"}`)
+	})
+
+	t.Run("set sale at the list price", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SetSale", "1", uint(9), mock.Anything).Return((*entities.ProductResponse)(nil), errors.New("sale price must be below the price"))
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"price":{"amount":10000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SetSale(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("set sale without signing in", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"price":{"amount":7500,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.SetSale(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "SetSale", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEndSale(t *testing.T) {
+	t.Run("end sale of a product not on sale", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("EndSale", "1", uint(9)).Return(errors.New("sale not found"))
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.EndSale(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestSchedulePrice(t *testing.T) {
+	t.Run("schedule price successfully", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SchedulePrice", "1", uint(9), mock.MatchedBy(func(r *entities.ScheduledPriceRequest) bool {
+			return r.Price == money.New(12000, "THB")
+		})).Return(&entities.ScheduledPrice{Model: gorm.Model{ID: 4}, ProductID: 1, Price: money.New(12000, "THB")}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"price":{"amount":12000,"currency":"THB"},"effective_at":"2027-01-01T00:00:00Z"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SchedulePrice(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("schedule price without a time", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"price":{"amount":12000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(9))
+
+		err := handler.SchedulePrice(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "SchedulePrice", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelScheduledPrice(t *testing.T) {
+	t.Run("cancel scheduled price successfully", func(t *testing.T) {
+		mockService := new(MockPricingUsecase)
+		handler := &httpPricingHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CancelScheduledPrice", "1", "4").Return(nil)
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id", "schedule_id")
+		c.SetParamValues("1", "4")
+
+		err := handler.CancelScheduledPrice(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+type MockPricingUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPricingUsecase) SetSale(productID string, actorID uint, request *entities.SaleRequest) (*entities.ProductResponse, error) {
+	args := m.Called(productID, actorID, request)
+	return args.Get(0).(*entities.ProductResponse), args.Error(1)
+}
+
+func (m *MockPricingUsecase) EndSale(productID string, actorID uint) error {
+	args := m.Called(productID, actorID)
+	return args.Error(0)
+}
+
+func (m *MockPricingUsecase) GetPriceHistory(productID string) ([]entities.PriceChange, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.PriceChange), args.Error(1)
+}
+
+func (m *MockPricingUsecase) SchedulePrice(productID string, actorID uint, request *entities.ScheduledPriceRequest) (*entities.ScheduledPrice, error) {
+	args := m.Called(productID, actorID, request)
+	return args.Get(0).(*entities.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockPricingUsecase) GetScheduledPrices(productID string) ([]entities.ScheduledPrice, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockPricingUsecase) CancelScheduledPrice(productID, id string) error {
+	args := m.Called(productID, id)
+	return args.Error(0)
+}
+
+func (m *MockPricingUsecase) ApplyDuePrices() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
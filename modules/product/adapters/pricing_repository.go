@@ -0,0 +1,169 @@
+package adapters
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormPricingRepository struct {
+	db *gorm.DB
+}
+
+func NewPricingRepository(db *gorm.DB) usecase.PricingRepository {
+	return &gormPricingRepository{db}
+}
+
+// UpdateSale writes the product's sale price and window and records them in
+// its price history.
+func (r *gormPricingRepository) UpdateSale(product *entities.Product, actorID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		sale := map[string]any{
+			"sale_price_amount":   nil,
+			"sale_price_currency": nil,
+			"sale_starts_at":      product.SaleStartsAt,
+			"sale_ends_at":        product.SaleEndsAt,
+		}
+		if product.SalePrice != nil {
+			sale["sale_price_amount"] = product.SalePrice.Amount
+			sale["sale_price_currency"] = product.SalePrice.Currency
+		}
+
+		result := tx.Model(&entities.Product{}).Where("id = ?", product.ID).Updates(sale)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("product not found")
+		}
+
+		return recordPrice(tx, product, entities.PriceSourceSale, &actorID)
+	})
+}
+
+func (r *gormPricingRepository) GetPriceHistory(productID uint) ([]entities.PriceChange, error) {
+	var history []entities.PriceChange
+
+	if err := r.db.Where("product_id = ?", productID).Order("id DESC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (r *gormPricingRepository) InsertScheduledPrice(scheduled *entities.ScheduledPrice) (*entities.ScheduledPrice, error) {
+	if err := r.db.Create(scheduled).Error; err != nil {
+		return nil, err
+	}
+
+	return scheduled, nil
+}
+
+// GetScheduledPrices lists the product's price changes still to be applied,
+// soonest first.
+func (r *gormPricingRepository) GetScheduledPrices(productID uint) ([]entities.ScheduledPrice, error) {
+	var scheduled []entities.ScheduledPrice
+
+	if err := r.db.Where("product_id = ? AND applied_at IS NULL", productID).
+		Order("effective_at, id").
+		Find(&scheduled).Error; err != nil {
+		return nil, err
+	}
+
+	return scheduled, nil
+}
+
+// DeleteScheduledPrice cancels a price change that has not been applied yet.
+func (r *gormPricingRepository) DeleteScheduledPrice(productID uint, id string) error {
+	result := r.db.Where("product_id = ? AND applied_at IS NULL", productID).Delete(&entities.ScheduledPrice{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("scheduled price not found")
+	}
+
+	return nil
+}
+
+// GetDueScheduledPrices lists unapplied price changes whose time has come,
+// in the order they were due.
+func (r *gormPricingRepository) GetDueScheduledPrices(now time.Time, limit int) ([]entities.ScheduledPrice, error) {
+	var scheduled []entities.ScheduledPrice
+
+	if err := r.db.Where("applied_at IS NULL AND effective_at <= ?", now).
+		Order("effective_at, id").
+		Limit(limit).
+		Find(&scheduled).Error; err != nil {
+		return nil, err
+	}
+
+	return scheduled, nil
+}
+
+// ApplyScheduledPrice sets the product's list price to the scheduled one and
+// records it in the price history. It reports false when the change was
+// already applied or cancelled.
+func (r *gormPricingRepository) ApplyScheduledPrice(id uint, now time.Time) (bool, error) {
+	applied := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		scheduled := &entities.ScheduledPrice{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("applied_at IS NULL").
+			First(scheduled, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		product, err := lockProduct(tx, scheduled.ProductID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(product).Updates(map[string]any{
+			"price_amount":   scheduled.Price.Amount,
+			"price_currency": scheduled.Price.Currency,
+		}).Error; err != nil {
+			return err
+		}
+
+		product.Price = scheduled.Price
+		if err := recordPrice(tx, product, entities.PriceSourceSchedule, scheduled.ActorID); err != nil {
+			return err
+		}
+
+		applied = true
+		return tx.Model(scheduled).Update("applied_at", now).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
+
+// recordPrice appends a snapshot of the product's pricing to its history.
+func recordPrice(tx *gorm.DB, product *entities.Product, source string, actorID *uint) error {
+	if err := tx.Create(&entities.PriceChange{
+		ProductID:    product.ID,
+		Price:        product.Price,
+		SalePrice:    product.SalePrice,
+		SaleStartsAt: product.SaleStartsAt,
+		SaleEndsAt:   product.SaleEndsAt,
+		Source:       source,
+		ActorID:      actorID,
+	}).Error; err != nil {
+		return errors.New("failed to record price history")
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	updateSaleQuery           = `UPDATE "products" SET "sale_ends_at"=$1,"sale_price_amount"=$2,"sale_price_currency"=$3,"sale_starts_at"=$4,"updated_at"=$5 WHERE id = $6 AND "products"."deleted_at" IS NULL`
+	deleteScheduledPriceQuery = `UPDATE "scheduled_prices" SET "deleted_at"=$1 WHERE (product_id = $2 AND applied_at IS NULL) AND id = $3 AND "scheduled_prices"."deleted_at" IS NULL`
+	lockScheduledPriceQuery   = `SELECT * FROM "scheduled_prices" WHERE applied_at IS NULL AND "scheduled_prices"."id" = $1 AND "scheduled_prices"."deleted_at" IS NULL ORDER BY "scheduled_prices"."id" LIMIT $2 FOR UPDATE`
+	updateListPriceQuery      = `UPDATE "products" SET "price_amount"=$1,"price_currency"=$2,"updated_at"=$3 WHERE "products"."deleted_at" IS NULL AND "id" = $4`
+	markPriceAppliedQuery     = `UPDATE "scheduled_prices" SET "applied_at"=$1,"updated_at"=$2 WHERE "scheduled_prices"."deleted_at" IS NULL AND "id" = $3`
+)
+
+func TestUpdateSale_gormRepo(t *testing.T) {
+	t.Run("put product on sale", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPricingRepository(gormDB)
+
+		salePrice := money.New(7500, "THB")
+		endsAt := time.Date(2026, 11, 3, 10, 0, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateSaleQuery).
+			WithArgs(&endsAt, salePrice.Amount, salePrice.Currency, nil, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertPriceChangeQuery).
+			WithArgs(sqlmock.AnyArg(), 1, 10000, "THB", salePrice.Amount, salePrice.Currency, nil, &endsAt, "sale", 9).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectCommit()
+
+		err := repo.UpdateSale(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB"), SalePrice: &salePrice, SaleEndsAt: &endsAt}, 9)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("put missing product on sale", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPricingRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateSaleQuery).
+			WithArgs(nil, nil, nil, nil, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.UpdateSale(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, 9)
+
+		assert.EqualError(t, err, "product not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteScheduledPrice_gormRepo(t *testing.T) {
+	t.Run("cancel a price change already applied", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPricingRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteScheduledPriceQuery).
+			WithArgs(sqlmock.AnyArg(), 1, "4").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.DeleteScheduledPrice(1, "4")
+
+		assert.EqualError(t, err, "scheduled price not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestApplyScheduledPrice_gormRepo(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("apply a due price change", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPricingRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockScheduledPriceQuery).
+			WithArgs(4, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "price_amount", "price_currency", "actor_id"}).AddRow(4, 1, 12000, "THB", 9))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency"}).AddRow(1, 10000, "THB"))
+		mock.ExpectExec(updateListPriceQuery).
+			WithArgs(12000, "THB", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertPriceChangeQuery).
+			WithArgs(sqlmock.AnyArg(), 1, 12000, "THB", nil, nil, nil, nil, "schedule", 9).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(markPriceAppliedQuery).
+			WithArgs(now, sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		applied, err := repo.ApplyScheduledPrice(4, now)
+
+		assert.NoError(t, err)
+		assert.True(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("apply a price change already applied", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPricingRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockScheduledPriceQuery).
+			WithArgs(4, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		applied, err := repo.ApplyScheduledPrice(4, now)
+
+		assert.NoError(t, err)
+		assert.False(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -11,11 +11,12 @@ import (
 )
 
 type httpProductHandler struct {
-	usecase usecase.ProductUsecase
+	usecase  usecase.ProductUsecase
+	currency usecase.CurrencyUsecase
 }
 
-func NewProductHandler(usecase usecase.ProductUsecase) *httpProductHandler {
-	return &httpProductHandler{usecase}
+func NewProductHandler(usecase usecase.ProductUsecase, currency usecase.CurrencyUsecase) *httpProductHandler {
+	return &httpProductHandler{usecase, currency}
 }
 
 type ErrorResponse struct {
@@ -51,6 +52,10 @@ func (h *httpProductHandler) CreateNewProduct(c echo.Context) error {
 
 	newProduct, err := h.usecase.CreateNewProduct(product)
 	if err != nil {
+		switch err.Error() {
+		case "blind box requires a series", "unsupported currency", "price must be positive":
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		}
 		log.Printf("failed to create new user: %v", err)
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 	}
@@ -60,11 +65,40 @@ func (h *httpProductHandler) CreateNewProduct(c echo.Context) error {
 }
 
 func (h *httpProductHandler) GetAllProducts(c echo.Context) error {
+	for _, param := range []string{"category_id", "series_id", "artist_id", "brand_id"} {
+		if c.QueryParam(param) != "" {
+			return h.listProducts(c)
+		}
+	}
+
 	products, err := h.usecase.GetAllProducts()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 	}
 
+	if err := h.localizeAll(c, products); err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, products)
+}
+
+func (h *httpProductHandler) listProducts(c echo.Context) error {
+	filter := new(entities.ProductFilter)
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, filter); err != nil {
+		log.Printf("failed to bind query %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "invalid filter"})
+	}
+
+	products, err := h.usecase.ListProducts(filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+
+	if err := h.localizeAll(c, products); err != nil {
+		return currencyError(c, err)
+	}
+
 	return c.JSON(http.StatusOK, products)
 }
 
@@ -76,6 +110,10 @@ func (h *httpProductHandler) GetProductById(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 	}
 
+	if err := localize(c, h.currency, products); err != nil {
+		return currencyError(c, err)
+	}
+
 	return c.JSON(http.StatusOK, products)
 }
 
@@ -98,6 +136,10 @@ func (h *httpProductHandler) UpdateProduct(c echo.Context) error {
 
 	productUpdate, err := h.usecase.UpdateProduct(product, id)
 	if err != nil {
+		switch err.Error() {
+		case "unsupported currency", "price must be positive":
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		}
 		log.Printf("failed to create new user: %v", err)
 		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 	}
@@ -125,10 +167,12 @@ func (h *httpProductHandler) DeductStock(c echo.Context) error {
 	newStock, err := h.usecase.DeductStock(id, count)
 	if err != nil {
 		switch err.Error() {
-		case "product not found":
-			return c.JSON(http.StatusNotFound, ErrorResponse{Message: "product not found"})
-		case "insufficient stock":
-			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "insufficient stock"})
+		case "product not found", "variant not found":
+			return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+		case "insufficient stock", "variant required":
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case "product is not on sale":
+			return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
 		default:
 			return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
 		}
@@ -137,21 +181,11 @@ func (h *httpProductHandler) DeductStock(c echo.Context) error {
 	return c.JSON(http.StatusOK, newStock)
 }
 
-func (h *httpProductHandler) SearchProducts(c echo.Context) error {
-	keyword := c.QueryParam("keyword")
-	if keyword == "" {
-		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "keyword is required"})
+func (h *httpProductHandler) localizeAll(c echo.Context, products []entities.ProductResponse) error {
+	responses := make([]*entities.ProductResponse, len(products))
+	for i := range products {
+		responses[i] = &products[i]
 	}
 
-	products, err := h.usecase.SearchProducts(keyword)
-	if err != nil {
-		switch err.Error() {
-		case "product not found":
-			return c.JSON(http.StatusNotFound, ErrorResponse{Message: "product not found"})
-		default:
-			return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
-		}
-	}
-
-	return c.JSON(http.StatusOK, products)
+	return localize(c, h.currency, responses...)
 }
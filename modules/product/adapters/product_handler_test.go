@@ -9,6 +9,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -21,9 +22,9 @@ func TestCreateNewProduct(t *testing.T) {
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("CreateNewProduct", mock.AnythingOfType("*entities.Product")).Return(&entities.ProductResponse{ID: uint(30), Name: "Customizable Art Toy", Description: "A fully customizable art toy.", Price: 20.0, ImageURL: "https://example.com/images/dimoo-starry-night.jpg"}, nil)
+		mockService.On("CreateNewProduct", mock.AnythingOfType("*entities.Product")).Return(&entities.ProductResponse{ID: uint(30), Name: "Customizable Art Toy", Description: "A fully customizable art toy.", Price: money.New(2000, "THB"), ImageURL: "https://example.com/images/dimoo-starry-night.jpg"}, nil)
 
-		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": 49.99, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
+		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": {"amount": 4999, "currency": "THB"}, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
 		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
@@ -31,7 +32,7 @@ func TestCreateNewProduct(t *testing.T) {
 
 		err := handler.CreateNewProduct(c)
 
-		expectedJSON := `{"id": 30, "name": "Customizable Art Toy", "description": "A fully customizable art toy.", "price": 20.0, "image_url": "https://example.com/images/dimoo-starry-night.jpg"}`
+		expectedJSON := `{"id": 30, "name": "Customizable Art Toy", "description": "A fully customizable art toy.", "price": {"amount": 2000, "currency": "THB"}, "image_url": "https://example.com/images/dimoo-starry-night.jpg"}`
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusCreated, response.Code)
 		assert.JSONEq(t, expectedJSON, response.Body.String())
@@ -66,7 +67,7 @@ func TestCreateNewProduct(t *testing.T) {
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("CreateNewProduct", mock.AnythingOfType("*entities.Product")).Return((*entities.ProductResponse)(nil), nil)
+		mockService.On("CreateNewProduct", mock.AnythingOfType("*entities.Product")).Return((*entities.ProductResponse)(nil), errors.New("price must be positive"))
 
 		body := `{"name": "Customizable Art Toy"}`
 		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
@@ -89,7 +90,7 @@ func TestCreateNewProduct(t *testing.T) {
 
 		mockService.On("CreateNewProduct", mock.AnythingOfType("*entities.Product")).Return((*entities.ProductResponse)(nil), errors.New("internal server error"))
 
-		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": 49.99, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
+		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": {"amount": 4999, "currency": "THB"}, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
 		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
@@ -111,8 +112,8 @@ func TestGetAllProducts(t *testing.T) {
 		defer e.Close()
 
 		products := []entities.ProductResponse{
-			{ID: uint(13), Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, ImageURL: "https://example.com/images/dimoo-starry-night.jpg"},
-			{ID: uint(14), Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"},
+			{ID: uint(13), Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: money.New(4999, "THB"), ImageURL: "https://example.com/images/dimoo-starry-night.jpg"},
+			{ID: uint(14), Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: money.New(4499, "THB"), ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"},
 		}
 
 		mockService.On("GetAllProducts").Return(products, nil)
@@ -123,8 +124,8 @@ func TestGetAllProducts(t *testing.T) {
 
 		err := handler.GetAllProducts(c)
 
-		expectedJSON := `[{"id":13,"description":"Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.","image_url":"https://example.com/images/dimoo-starry-night.jpg","name":"Dimoo Starry Night","price":49.99},
-  			{"id":14,"description":"A magical art toy figure from Pucky, with a whimsical forest fairy design.","image_url":"https://example.com/images/pucky-forest-fairy.jpg","name":"Pucky Forest Fairy","price":44.99}]`
+		expectedJSON := `[{"id":13,"description":"Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.","image_url":"https://example.com/images/dimoo-starry-night.jpg","name":"Dimoo Starry Night","price":{"amount":4999,"currency":"THB"}},
+  			{"id":14,"description":"A magical art toy figure from Pucky, with a whimsical forest fairy design.","image_url":"https://example.com/images/pucky-forest-fairy.jpg","name":"Pucky Forest Fairy","price":{"amount":4499,"currency":"THB"}}]`
 
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -160,7 +161,7 @@ func TestGetProductById(t *testing.T) {
 		e := echo.New()
 		defer e.Close()
 
-		product := &entities.ProductResponse{ID: uint(12), Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"}
+		product := &entities.ProductResponse{ID: uint(12), Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: money.New(4499, "THB"), ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"}
 		mockService.On("GetProductById", "12").Return(product, nil)
 
 		request := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -171,7 +172,7 @@ func TestGetProductById(t *testing.T) {
 
 		err := handler.GetProductById(c)
 
-		expectedJSON := `{"id":12,"description":"A magical art toy figure from Pucky, with a whimsical forest fairy design.","image_url":"https://example.com/images/pucky-forest-fairy.jpg","name":"Pucky Forest Fairy","price":44.99}`
+		expectedJSON := `{"id":12,"description":"A magical art toy figure from Pucky, with a whimsical forest fairy design.","image_url":"https://example.com/images/pucky-forest-fairy.jpg","name":"Pucky Forest Fairy","price":{"amount":4499,"currency":"THB"}}`
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusOK, response.Code)
 		assert.JSONEq(t, expectedJSON, response.Body.String())
@@ -207,9 +208,9 @@ func TestUpdateProduct(t *testing.T) {
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "30").Return(&entities.ProductResponse{ID: uint(30), Name: "Customizable Art Toy", Description: "A fully customizable art toy.", Price: 20.0, ImageURL: "https://example.com/images/dimoo-starry-night.jpg"}, nil)
+		mockService.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "30").Return(&entities.ProductResponse{ID: uint(30), Name: "Customizable Art Toy", Description: "A fully customizable art toy.", Price: money.New(2000, "THB"), ImageURL: "https://example.com/images/dimoo-starry-night.jpg"}, nil)
 
-		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": 49.99, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
+		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": {"amount": 4999, "currency": "THB"}, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
 		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
@@ -219,7 +220,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		err := handler.UpdateProduct(c)
 
-		expectedJSON := `{"id": 30, "name": "Customizable Art Toy", "description": "A fully customizable art toy.", "price": 20.0, "image_url": "https://example.com/images/dimoo-starry-night.jpg"}`
+		expectedJSON := `{"id": 30, "name": "Customizable Art Toy", "description": "A fully customizable art toy.", "price": {"amount": 2000, "currency": "THB"}, "image_url": "https://example.com/images/dimoo-starry-night.jpg"}`
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusCreated, response.Code)
 		assert.JSONEq(t, expectedJSON, response.Body.String())
@@ -256,7 +257,7 @@ func TestUpdateProduct(t *testing.T) {
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "19").Return((*entities.ProductResponse)(nil), nil)
+		mockService.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "19").Return((*entities.ProductResponse)(nil), errors.New("price must be positive"))
 
 		body := `{"name": "Customizable Art Toy"}`
 		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
@@ -281,7 +282,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		mockService.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "20").Return((*entities.ProductResponse)(nil), errors.New("internal server error"))
 
-		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": 49.99, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
+		body := `{"name": "Dimoo Starry Night", "description": "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", "price": {"amount": 4999, "currency": "THB"}, "stock": 25, "image_url": "https://example.com/images/dimoo-starry-night.jpg", "active": true}`
 		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
@@ -320,14 +321,16 @@ func TestDeductStock(t *testing.T) {
 		assert.JSONEq(t, `{"count": 18}`, response.Body.String())
 	})
 
-	t.Run("deduct stock given error during binding", func(t *testing.T) {
+	t.Run("deduct variant stock successfully", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{count: -1}`))
+		mockService.On("DeductStock", "31", &entities.CountProduct{VariantID: 4, Count: 1}).Return(&entities.CountProduct{VariantID: 4, Count: 2}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"variant_id": 4, "count": 1}`))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
 		c := e.NewContext(request, response)
@@ -337,18 +340,20 @@ func TestDeductStock(t *testing.T) {
 		err := handler.DeductStock(c)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.JSONEq(t, `{"message":"invalid request data"}`, response.Body.String())
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"variant_id": 4, "count": 2}`, response.Body.String())
 	})
 
-	t.Run("deduct stock given invalid input data", func(t *testing.T) {
+	t.Run("deduct stock given variant required", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 0}`))
+		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 1}).Return((*entities.CountProduct)(nil), errors.New("variant required"))
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 1}`))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
 		c := e.NewContext(request, response)
@@ -359,19 +364,17 @@ func TestDeductStock(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.JSONEq(t, `{"message":"request data validation failed"}`, response.Body.String())
+		assert.JSONEq(t, `{"message": "variant required"}`, response.Body.String())
 	})
 
-	t.Run("deduct stock given product not found", func(t *testing.T) {
+	t.Run("deduct stock given error during binding", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 2}).Return((*entities.CountProduct)(nil), errors.New("product not found"))
-
-		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 2}`))
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{count: -1}`))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
 		c := e.NewContext(request, response)
@@ -381,20 +384,18 @@ func TestDeductStock(t *testing.T) {
 		err := handler.DeductStock(c)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, response.Code)
-		assert.JSONEq(t, `{"message":"product not found"}`, response.Body.String())
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"invalid request data"}`, response.Body.String())
 	})
 
-	t.Run("deduct stock given insufficient stock", func(t *testing.T) {
+	t.Run("deduct stock given invalid input data", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 2}).Return((*entities.CountProduct)(nil), errors.New("insufficient stock"))
-
-		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 2}`))
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 0}`))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
 		c := e.NewContext(request, response)
@@ -405,17 +406,17 @@ func TestDeductStock(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.JSONEq(t, `{"message":"insufficient stock"}`, response.Body.String())
+		assert.JSONEq(t, `{"message":"request data validation failed"}`, response.Body.String())
 	})
 
-	t.Run("deduct stock given internal server error", func(t *testing.T) {
+	t.Run("deduct stock given product not found", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 2}).Return((*entities.CountProduct)(nil), errors.New("internal server error"))
+		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 2}).Return((*entities.CountProduct)(nil), errors.New("product not found"))
 
 		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 2}`))
 		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -427,98 +428,54 @@ func TestDeductStock(t *testing.T) {
 		err := handler.DeductStock(c)
 
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, response.Code)
-		assert.JSONEq(t, `{"message":"internal server error"}`, response.Body.String())
-	})
-}
-
-func TestSearchProduct(t *testing.T) {
-	t.Run("search product successfully", func(t *testing.T) {
-		mockService := new(MockProductUsecase)
-		handler := &httpProductHandler{usecase: mockService}
-
-		e := echo.New()
-		defer e.Close()
-
-		products := []entities.ProductResponse{
-			{ID: uint(13), Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, ImageURL: "https://example.com/images/dimoo-starry-night.jpg"},
-			{ID: uint(14), Name: "Pucky Forest Fairy Dimoo", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"},
-		}
-
-		mockService.On("SearchProducts", "Dimoo").Return(products, nil)
-
-		request := httptest.NewRequest(http.MethodGet, "/search?keyword=Dimoo", nil)
-		response := httptest.NewRecorder()
-		c := e.NewContext(request, response)
-
-		err := handler.SearchProducts(c)
-
-		expectedJSON := `[{"id":13,"description":"Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.","image_url":"https://example.com/images/dimoo-starry-night.jpg","name":"Dimoo Starry Night","price":49.99},
-  			{"id":14,"description":"A magical art toy figure from Pucky, with a whimsical forest fairy design.","image_url":"https://example.com/images/pucky-forest-fairy.jpg","name":"Pucky Forest Fairy Dimoo","price":44.99}]`
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusOK, response.Code)
-		assert.JSONEq(t, expectedJSON, response.Body.String())
-
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.JSONEq(t, `{"message":"product not found"}`, response.Body.String())
 	})
 
-	t.Run("search product given empty keyword", func(t *testing.T) {
+	t.Run("deduct stock given insufficient stock", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("SearchProducts", "Dimoo").Return(([]entities.ProductResponse)(nil), errors.New("database error"))
+		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 2}).Return((*entities.CountProduct)(nil), errors.New("insufficient stock"))
 
-		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
 		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("31")
 
-		err := handler.SearchProducts(c)
+		err := handler.DeductStock(c)
 
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, response.Code)
-		assert.JSONEq(t, `{"message":"keyword is required"}`, response.Body.String())
-	})
-
-	t.Run("search product given product not found", func(t *testing.T) {
-		mockService := new(MockProductUsecase)
-		handler := &httpProductHandler{usecase: mockService}
-
-		e := echo.New()
-		defer e.Close()
-
-		mockService.On("SearchProducts", "Dimoo").Return(([]entities.ProductResponse)(nil), errors.New("product not found"))
-
-		request := httptest.NewRequest(http.MethodGet, "/search?keyword=Dimoo", nil)
-		response := httptest.NewRecorder()
-		c := e.NewContext(request, response)
-
-		err := handler.SearchProducts(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusNotFound, response.Code)
-		assert.JSONEq(t, `{"message":"product not found"}`, response.Body.String())
+		assert.JSONEq(t, `{"message":"insufficient stock"}`, response.Body.String())
 	})
 
-	t.Run("search product given error", func(t *testing.T) {
+	t.Run("deduct stock given internal server error", func(t *testing.T) {
 		mockService := new(MockProductUsecase)
 		handler := &httpProductHandler{usecase: mockService}
 
 		e := echo.New()
 		defer e.Close()
 
-		mockService.On("SearchProducts", "Dimoo").Return(([]entities.ProductResponse)(nil), errors.New("database error"))
+		mockService.On("DeductStock", "31", &entities.CountProduct{Count: 2}).Return((*entities.CountProduct)(nil), errors.New("internal server error"))
 
-		request := httptest.NewRequest(http.MethodGet, "/search?keyword=Dimoo", nil)
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"count": 2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		response := httptest.NewRecorder()
 		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("31")
 
-		err := handler.SearchProducts(c)
+		err := handler.DeductStock(c)
 
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusInternalServerError, response.Code)
+		assert.JSONEq(t, `{"message":"internal server error"}`, response.Body.String())
 	})
 }
 
@@ -541,6 +498,11 @@ func (m *MockProductUsecase) GetProductById(id string) (*entities.ProductRespons
 	return args.Get(0).(*entities.ProductResponse), args.Error(1)
 }
 
+func (m *MockProductUsecase) GetSellableItem(productID, variantID uint) (*entities.SellableItem, error) {
+	args := m.Called(productID, variantID)
+	return args.Get(0).(*entities.SellableItem), args.Error(1)
+}
+
 func (m *MockProductUsecase) UpdateProduct(product *entities.Product, id string) (*entities.ProductResponse, error) {
 	args := m.Called(product, id)
 	return args.Get(0).(*entities.ProductResponse), args.Error(1)
@@ -551,7 +513,7 @@ func (m *MockProductUsecase) DeductStock(id string, count *entities.CountProduct
 	return args.Get(0).(*entities.CountProduct), args.Error(1)
 }
 
-func (m *MockProductUsecase) SearchProducts(keyword string) ([]entities.ProductResponse, error) {
-	args := m.Called(keyword)
+func (m *MockProductUsecase) ListProducts(filter *entities.ProductFilter) ([]entities.ProductResponse, error) {
+	args := m.Called(filter)
 	return args.Get(0).([]entities.ProductResponse), args.Error(1)
 }
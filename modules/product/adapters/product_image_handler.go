@@ -0,0 +1,138 @@
+package adapters
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+)
+
+type httpProductImageHandler struct {
+	usecase usecase.ProductImageUsecase
+}
+
+func NewProductImageHandler(usecase usecase.ProductImageUsecase) *httpProductImageHandler {
+	return &httpProductImageHandler{usecase}
+}
+
+func (h *httpProductImageHandler) UploadProductImage(c echo.Context) error {
+	id := c.Param("id")
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		log.Printf("failed to read form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "image file is required"})
+	}
+
+	if file.Size > usecase.MaxProductImageSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: "image too large"})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Printf("failed to open form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "image file is required"})
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, usecase.MaxProductImageSize+1))
+	if err != nil {
+		log.Printf("failed to read form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "image file is required"})
+	}
+
+	isPrimary, _ := strconv.ParseBool(c.FormValue("is_primary"))
+
+	productImage, err := h.usecase.UploadProductImage(id, data, c.FormValue("alt_text"), isPrimary)
+	if err != nil {
+		return productImageError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, productImage)
+}
+
+func (h *httpProductImageHandler) GetProductImages(c echo.Context) error {
+	images, err := h.usecase.GetProductImages(c.Param("id"))
+	if err != nil {
+		return productImageError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, images)
+}
+
+func (h *httpProductImageHandler) UpdateProductImage(c echo.Context) error {
+	update := new(entities.ProductImageUpdate)
+
+	validator := validator.New()
+	c.Echo().Validator = &CustomValidator{validator: validator}
+
+	if err := c.Bind(&update); err != nil {
+		log.Printf("failed to bind input %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "invalid request data"})
+	}
+
+	if err := c.Validate(update); err != nil {
+		log.Printf("failed to validate input %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "request data validation failed"})
+	}
+
+	productImage, err := h.usecase.UpdateProductImage(c.Param("id"), c.Param("image_id"), update)
+	if err != nil {
+		return productImageError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, productImage)
+}
+
+func (h *httpProductImageHandler) ReorderProductImages(c echo.Context) error {
+	order := new(entities.ProductImageOrder)
+
+	validator := validator.New()
+	c.Echo().Validator = &CustomValidator{validator: validator}
+
+	if err := c.Bind(&order); err != nil {
+		log.Printf("failed to bind input %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "invalid request data"})
+	}
+
+	if err := c.Validate(order); err != nil {
+		log.Printf("failed to validate input %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "request data validation failed"})
+	}
+
+	images, err := h.usecase.ReorderProductImages(c.Param("id"), order)
+	if err != nil {
+		return productImageError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, images)
+}
+
+func (h *httpProductImageHandler) DeleteProductImage(c echo.Context) error {
+	if err := h.usecase.DeleteProductImage(c.Param("id"), c.Param("image_id")); err != nil {
+		return productImageError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func productImageError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "image not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "image too large", "image dimensions too large":
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: err.Error()})
+	case "unsupported image type":
+		return c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Message: err.Error()})
+	case "empty image", "invalid image":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,279 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newImageUploadRequest(t *testing.T, field string, data []byte, fields map[string]string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "front.png")
+	assert.NoError(t, err)
+	part.Write(data)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/", &body)
+	request.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	return request
+}
+
+func TestUploadProductImage(t *testing.T) {
+	t.Run("upload product image successfully", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("UploadProductImage", "12", []byte("image-bytes"), "Front view", true).Return(&entities.ProductImageResponse{
+			ID: 3, URL: "/uploads/large.jpg", MediumURL: "/uploads/medium.jpg", ThumbnailURL: "/uploads/thumb.jpg", AltText: "Front view", IsPrimary: true,
+		}, nil)
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "image", []byte("image-bytes"), map[string]string{"alt_text": "Front view", "is_primary": "true"}), response)
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+
+		err := handler.UploadProductImage(c)
+
+		expectedJSON := `{"id":3,"url":"/uploads/large.jpg","medium_url":"/uploads/medium.jpg","thumbnail_url":"/uploads/thumb.jpg","alt_text":"Front view","sort_order":0,"is_primary":true}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("upload product image given missing file", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "photo", []byte("image-bytes"), nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+
+		err := handler.UploadProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("upload product image given product not found", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("UploadProductImage", "99", []byte("image-bytes"), "", false).Return((*entities.ProductImageResponse)(nil), errors.New("product not found"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "image", []byte("image-bytes"), nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("99")
+
+		err := handler.UploadProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.JSONEq(t, `{"message":"product not found"}`, response.Body.String())
+	})
+
+	t.Run("upload product image given unsupported type", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("UploadProductImage", "12", []byte("text"), "", false).Return((*entities.ProductImageResponse)(nil), errors.New("unsupported image type"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newImageUploadRequest(t, "image", []byte("text"), nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+
+		err := handler.UploadProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+	})
+}
+
+func TestUpdateProductImage(t *testing.T) {
+	t.Run("update product image successfully", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("UpdateProductImage", "12", "3", mock.AnythingOfType("*entities.ProductImageUpdate")).Return(&entities.ProductImageResponse{ID: 3, AltText: "Side view"}, nil)
+
+		request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"alt_text":"Side view"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id", "image_id")
+		c.SetParamValues("12", "3")
+
+		err := handler.UpdateProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		update := mockService.Calls[0].Arguments.Get(2).(*entities.ProductImageUpdate)
+		assert.Equal(t, "Side view", *update.AltText)
+		assert.Nil(t, update.IsPrimary)
+	})
+
+	t.Run("update product image given invalid sort order", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"sort_order":-1}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.UpdateProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestReorderProductImages(t *testing.T) {
+	t.Run("reorder product images successfully", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ReorderProductImages", "12", &entities.ProductImageOrder{ImageIDs: []uint{4, 3}}).Return([]entities.ProductImageResponse{
+			{ID: 4, SortOrder: 0}, {ID: 3, SortOrder: 1, IsPrimary: true},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"image_ids":[4,3]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+
+		err := handler.ReorderProductImages(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("reorder product images given empty list", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"image_ids":[]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ReorderProductImages(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	t.Run("delete product image successfully", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteProductImage", "12", "3").Return(nil)
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), response)
+		c.SetParamNames("id", "image_id")
+		c.SetParamValues("12", "3")
+
+		err := handler.DeleteProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("delete product image given image not found", func(t *testing.T) {
+		mockService := new(MockProductImageUsecase)
+		handler := &httpProductImageHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteProductImage", "12", "30").Return(errors.New("image not found"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), response)
+		c.SetParamNames("id", "image_id")
+		c.SetParamValues("12", "30")
+
+		err := handler.DeleteProductImage(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockProductImageUsecase struct {
+	mock.Mock
+}
+
+func (m *MockProductImageUsecase) UploadProductImage(productID string, data []byte, altText string, isPrimary bool) (*entities.ProductImageResponse, error) {
+	args := m.Called(productID, data, altText, isPrimary)
+	return args.Get(0).(*entities.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductImageUsecase) GetProductImages(productID string) ([]entities.ProductImageResponse, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductImageUsecase) UpdateProductImage(productID, imageID string, update *entities.ProductImageUpdate) (*entities.ProductImageResponse, error) {
+	args := m.Called(productID, imageID, update)
+	return args.Get(0).(*entities.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductImageUsecase) ReorderProductImages(productID string, order *entities.ProductImageOrder) ([]entities.ProductImageResponse, error) {
+	args := m.Called(productID, order)
+	return args.Get(0).([]entities.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductImageUsecase) DeleteProductImage(productID, imageID string) error {
+	args := m.Called(productID, imageID)
+	return args.Error(0)
+}
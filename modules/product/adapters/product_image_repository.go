@@ -0,0 +1,146 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+)
+
+type gormProductImageRepository struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepository(db *gorm.DB) usecase.ProductImageRepository {
+	return &gormProductImageRepository{db}
+}
+
+func orderProductImages(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, id")
+}
+
+func (r *gormProductImageRepository) InsertProductImage(image *entities.ProductImage) (*entities.ProductImage, error) {
+	if result := r.db.Create(image); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return image, nil
+}
+
+func (r *gormProductImageRepository) GetProductImages(productID uint) ([]entities.ProductImage, error) {
+	var images []entities.ProductImage
+
+	if err := r.db.Where("product_id = ?", productID).Order("sort_order, id").Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (r *gormProductImageRepository) GetProductImageById(productID uint, imageID string) (*entities.ProductImage, error) {
+	image := new(entities.ProductImage)
+
+	if err := r.db.Where("id = ? AND product_id = ?", imageID, productID).First(image).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+
+	return image, nil
+}
+
+func (r *gormProductImageRepository) UpdateProductImage(image *entities.ProductImage) (*entities.ProductImage, error) {
+	if result := r.db.Model(image).
+		Select("alt_text", "sort_order").
+		Updates(image); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return image, nil
+}
+
+// SetPrimaryProductImage marks a single image as primary and mirrors its URL
+// into products.image_url so listings keep working off a single column.
+func (r *gormProductImageRepository) SetPrimaryProductImage(productID uint, imageID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		image := new(entities.ProductImage)
+		if err := tx.Where("id = ? AND product_id = ?", imageID, productID).First(image).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("image not found")
+			}
+			return err
+		}
+
+		if err := tx.Model(&entities.ProductImage{}).
+			Where("product_id = ? AND id <> ?", productID, imageID).
+			Update("is_primary", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(image).Update("is_primary", true).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.Product{}).
+			Where("id = ?", productID).
+			Update("image_url", image.URL).Error
+	})
+}
+
+func (r *gormProductImageRepository) ReorderProductImages(productID uint, imageIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entities.ProductImage{}).
+			Where("product_id = ? AND id IN ?", productID, imageIDs).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if int(count) != len(imageIDs) {
+			return errors.New("image not found")
+		}
+
+		for position, imageID := range imageIDs {
+			if err := tx.Model(&entities.ProductImage{}).
+				Where("id = ? AND product_id = ?", imageID, productID).
+				Update("sort_order", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *gormProductImageRepository) DeleteProductImage(image *entities.ProductImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(image).Error; err != nil {
+			return err
+		}
+
+		if !image.IsPrimary {
+			return nil
+		}
+
+		next := new(entities.ProductImage)
+		err := tx.Where("product_id = ?", image.ProductID).Order("sort_order, id").First(next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Model(&entities.Product{}).
+				Where("id = ?", image.ProductID).
+				Update("image_url", "").Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(next).Update("is_primary", true).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.Product{}).
+			Where("id = ?", image.ProductID).
+			Update("image_url", next.URL).Error
+	})
+}
@@ -0,0 +1,181 @@
+package adapters
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getProductImagesQuery      = `SELECT * FROM "product_images" WHERE product_id = $1 AND "product_images"."deleted_at" IS NULL ORDER BY sort_order, id`
+	getProductImageByIdQuery   = `SELECT * FROM "product_images" WHERE (id = $1 AND product_id = $2) AND "product_images"."deleted_at" IS NULL ORDER BY "product_images"."id" LIMIT $3`
+	unsetPrimaryImagesQuery    = `UPDATE "product_images" SET "is_primary"=$1,"updated_at"=$2 WHERE (product_id = $3 AND id <> $4) AND "product_images"."deleted_at" IS NULL`
+	setPrimaryImageQuery       = `UPDATE "product_images" SET "is_primary"=$1,"updated_at"=$2 WHERE "product_images"."deleted_at" IS NULL AND "id" = $3`
+	updateProductImageURLQuery = `UPDATE "products" SET "image_url"=$1,"updated_at"=$2 WHERE id = $3 AND "products"."deleted_at" IS NULL`
+	countProductImagesQuery    = `SELECT count(*) FROM "product_images" WHERE (product_id = $1 AND id IN ($2,$3)) AND "product_images"."deleted_at" IS NULL`
+	updateImageSortOrderQuery  = `UPDATE "product_images" SET "sort_order"=$1,"updated_at"=$2 WHERE (id = $3 AND product_id = $4) AND "product_images"."deleted_at" IS NULL`
+	deleteProductImageQuery    = `UPDATE "product_images" SET "deleted_at"=$1 WHERE "product_images"."id" = $2 AND "product_images"."deleted_at" IS NULL`
+	getFirstProductImageQuery  = `SELECT * FROM "product_images" WHERE product_id = $1 AND "product_images"."deleted_at" IS NULL ORDER BY sort_order, id,"product_images"."id" LIMIT $2`
+)
+
+func TestGetProductImages_gormRepo(t *testing.T) {
+	t.Run("get product images successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		rows := sqlmock.NewRows([]string{"id", "product_id", "url", "alt_text", "sort_order", "is_primary"}).
+			AddRow(3, 12, "https://cdn.example.com/front.jpg", "Front", 0, true).
+			AddRow(4, 12, "https://cdn.example.com/back.jpg", "Back", 1, false)
+
+		mock.ExpectQuery(getProductImagesQuery).WithArgs(12).WillReturnRows(rows)
+
+		got, err := repo.GetProductImages(12)
+
+		want := []entities.ProductImage{
+			{Model: gorm.Model{ID: 3}, ProductID: 12, URL: "https://cdn.example.com/front.jpg", AltText: "Front", SortOrder: 0, IsPrimary: true},
+			{Model: gorm.Model{ID: 4}, ProductID: 12, URL: "https://cdn.example.com/back.jpg", AltText: "Back", SortOrder: 1},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestGetProductImageById_gormRepo(t *testing.T) {
+	t.Run("get product image given image not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectQuery(getProductImageByIdQuery).WithArgs("9", 12, 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetProductImageById(12, "9")
+
+		assert.EqualError(t, err, "image not found")
+	})
+}
+
+func TestSetPrimaryProductImage_gormRepo(t *testing.T) {
+	t.Run("set primary image successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductImageByIdQuery).WithArgs(4, 12, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}).AddRow(4, 12, "https://cdn.example.com/back.jpg"))
+		mock.ExpectExec(unsetPrimaryImagesQuery).WithArgs(false, sqlmock.AnyArg(), 12, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(setPrimaryImageQuery).WithArgs(true, sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(updateProductImageURLQuery).WithArgs("https://cdn.example.com/back.jpg", sqlmock.AnyArg(), 12).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.SetPrimaryProductImage(12, 4)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("set primary image given image of another product", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductImageByIdQuery).WithArgs(4, 13, 1).WillReturnError(gorm.ErrRecordNotFound)
+		mock.ExpectRollback()
+
+		err := repo.SetPrimaryProductImage(13, 4)
+
+		assert.EqualError(t, err, "image not found")
+	})
+}
+
+func TestReorderProductImages_gormRepo(t *testing.T) {
+	t.Run("reorder images successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(countProductImagesQuery).WithArgs(12, 4, 3).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectExec(updateImageSortOrderQuery).WithArgs(0, sqlmock.AnyArg(), 4, 12).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(updateImageSortOrderQuery).WithArgs(1, sqlmock.AnyArg(), 3, 12).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.ReorderProductImages(12, []uint{4, 3})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reorder images given unknown image", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(countProductImagesQuery).WithArgs(12, 4, 99).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		err := repo.ReorderProductImages(12, []uint{4, 99})
+
+		assert.EqualError(t, err, "image not found")
+	})
+}
+
+func TestDeleteProductImage_gormRepo(t *testing.T) {
+	t.Run("delete primary image promotes next image", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteProductImageQuery).WithArgs(sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getFirstProductImageQuery).WithArgs(12, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}).AddRow(4, 12, "https://cdn.example.com/back.jpg"))
+		mock.ExpectExec(setPrimaryImageQuery).WithArgs(true, sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(updateProductImageURLQuery).WithArgs("https://cdn.example.com/back.jpg", sqlmock.AnyArg(), 12).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteProductImage(&entities.ProductImage{Model: gorm.Model{ID: 3}, ProductID: 12, IsPrimary: true})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete image given database error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductImageRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteProductImageQuery).WithArgs(sqlmock.AnyArg(), 3).WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := repo.DeleteProductImage(&entities.ProductImage{Model: gorm.Model{ID: 3}, ProductID: 12})
+
+		assert.Error(t, err)
+	})
+}
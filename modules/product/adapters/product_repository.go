@@ -6,7 +6,6 @@ import (
 	"github.com/phetployst/art-toys-store/modules/product/entities"
 	"github.com/phetployst/art-toys-store/modules/product/usecase"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type gormProductRepository struct {
@@ -17,9 +16,17 @@ func NewProductRepository(db *gorm.DB) usecase.ProductRepository {
 	return &gormProductRepository{db}
 }
 
+// InsertProduct opens the product's ledger with its initial stock.
 func (r *gormProductRepository) InsertProduct(product *entities.Product) (*entities.Product, error) {
-	if result := r.db.Create(&product); result.Error != nil {
-		return nil, result.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+
+		return recordInitialStock(tx, product.ID, nil, product.Stock)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return product, nil
@@ -28,7 +35,7 @@ func (r *gormProductRepository) InsertProduct(product *entities.Product) (*entit
 func (r *gormProductRepository) GetAllProduct() ([]entities.Product, error) {
 	var products []entities.Product
 
-	result := r.db.Find(&products)
+	result := r.db.Scopes(preloadProductDetails).Find(&products)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -39,70 +46,135 @@ func (r *gormProductRepository) GetAllProduct() ([]entities.Product, error) {
 func (r *gormProductRepository) GetProductById(id string) (*entities.Product, error) {
 	product := new(entities.Product)
 
-	if err := r.db.First(&product, id).Error; err != nil {
+	if err := r.db.Scopes(preloadProductDetails).First(&product, id).Error; err != nil {
 		return nil, err
 	}
 
 	return product, nil
 }
 
+// UpdateProduct writes the product's details and, when its price changes,
+// records the new price in its history.
 func (r *gormProductRepository) UpdateProduct(product *entities.Product, id string) (*entities.Product, error) {
-	if result := r.db.Model(&entities.Product{}).
-		Where("id = ?", id).
-		Updates(product); result.Error != nil {
-		return nil, result.Error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		current, err := lockProduct(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// Stock only changes through the inventory ledger.
+		if err := tx.Model(&entities.Product{}).
+			Where("id = ?", id).
+			Omit("stock").
+			Updates(product).Error; err != nil {
+			return err
+		}
+
+		if product.Price.IsZero() || product.Price == current.Price {
+			return nil
+		}
+
+		current.Price = product.Price
+		return recordPrice(tx, current, entities.PriceSourceUpdate, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
+
 	return product, nil
 }
 
-func (r *gormProductRepository) UpdateStock(id string, count int) (int, error) {
-	var newStock int
+// UpdateStock records the sale of count units of a product. Products that
+// come in variants must name the variant; its stock is reduced and the product
+// total is re-derived from the variants.
+func (r *gormProductRepository) UpdateStock(id string, variantID uint, count int, referenceID string) (int, error) {
+	movement := &entities.StockMovement{
+		Kind:        entities.StockMovementSale,
+		Quantity:    -count,
+		ReferenceID: referenceID,
+	}
+	if variantID != 0 {
+		movement.VariantID = &variantID
+	}
 
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		product := &entities.Product{}
-		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(product, "id = ?", id).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("product not found")
-			}
-			return errors.New("failed to retrieve product")
+		product, err := lockProduct(tx, id)
+		if err != nil {
+			return err
 		}
 
-		if product.Stock < count {
-			return errors.New("insufficient stock")
+		if product.OffSale || product.PreOrder {
+			return errors.New("product is not on sale")
 		}
 
-		newStock = product.Stock - count
-
-		updates := map[string]interface{}{
-			"stock":  newStock,
-			"active": newStock > 0,
-		}
-		if err := tx.Model(product).Updates(updates).Error; err != nil {
-			return errors.New("failed to update product stock")
-		}
-
-		return nil
+		return applyStockMovement(tx, product, movement)
 	})
 
 	if err != nil {
 		return 0, err
 	}
 
-	return newStock, nil
+	return movement.Balance, nil
+}
+
+// syncProductStock sets the product total to the sum of its variants.
+func syncProductStock(tx *gorm.DB, productID uint) error {
+	var total int64
+	if err := tx.Model(&entities.ProductVariant{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(SUM(stock), 0)").
+		Scan(&total).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entities.Product{}).
+		Where("id = ?", productID).
+		Update("stock", total).Error
 }
 
-func (r *gormProductRepository) SearchProducts(keyword string) ([]entities.Product, error) {
+// listedProducts keeps the products shoppers browse: those an admin has left
+// active that are in stock or set to show as sold out. Whether a product is
+// listed is worked out as it is read, never stored, so a restocked product is
+// listed again the moment its stock comes back.
+const listedProducts = "products.active AND (products.stock > 0 OR products.show_when_sold_out)"
+
+// categoryTreeQuery selects the product IDs linked to any of the given
+// categories or to their descendants.
+const categoryTreeQuery = `products.id IN (
+	SELECT pc.product_id FROM product_categories pc WHERE pc.category_id IN (
+		WITH RECURSIVE category_tree AS (
+			SELECT id FROM categories WHERE id IN ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+		)
+		SELECT id FROM category_tree
+	)
+)`
+
+func (r *gormProductRepository) GetProductsByFilter(filter *entities.ProductFilter) ([]entities.Product, error) {
 	var products []entities.Product
 
-	if err := r.db.Where("(name ILIKE ? OR description ILIKE ?) AND active = ?",
-		"%"+keyword+"%", "%"+keyword+"%", true).
-		Find(&products).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
-		}
+	query := r.db.Scopes(preloadProductDetails).Where(listedProducts)
+	if filter.CategoryID != 0 {
+		query = query.Where(categoryTreeQuery, []uint{filter.CategoryID})
+	}
+	if filter.SeriesID != 0 {
+		query = query.Where("series_id = ?", filter.SeriesID)
+	}
+	if filter.ArtistID != 0 {
+		query = query.Where("artist_id = ?", filter.ArtistID)
+	}
+	if filter.BrandID != 0 {
+		query = query.Where("brand_id = ?", filter.BrandID)
+	}
 
+	if err := query.Find(&products).Error; err != nil {
 		return nil, err
 	}
 
 	return products, nil
 }
+
+func preloadProductDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Images", orderProductImages).Preload("Categories").Preload("Artist").Preload("Brand").Preload("Variants", orderProductVariants)
+}
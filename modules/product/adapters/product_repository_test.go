@@ -1,28 +1,68 @@
 package adapters
 
 import (
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 const (
-	insertProductQuery       = `INSERT INTO "products" ("created_at","updated_at","deleted_at","name","description","price","stock","image_url","active") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`
-	getAllProductQuery       = `SELECT * FROM "products" WHERE "products"."deleted_at" IS NULL`
-	getProductByIdQuery      = `SELECT * FROM "products" WHERE "products"."id" = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`
-	updateProductQuery       = `UPDATE "products" SET "updated_at"=$1,"name"=$2,"description"=$3,"price"=$4,"stock"=$5,"image_url"=$6,"active"=$7 WHERE id = $8 AND "products"."deleted_at" IS NULL`
-	getProductforUpdateQuery = `SELECT * FROM "products" WHERE id = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2 FOR UPDATE`
-	updateStockProductQuery  = `UPDATE "products" SET "active"=$1,"stock"=$2,"updated_at"=$3 WHERE "products"."deleted_at" IS NULL AND "id" = $4`
-	searchProductsQuery      = `SELECT * FROM "products" WHERE ((name ILIKE $1 OR description ILIKE $2) AND active = $3) AND "products"."deleted_at" IS NULL`
+	insertProductQuery        = `INSERT INTO "products" ("created_at","updated_at","deleted_at","name","description","price_amount","price_currency","stock","image_url","active","low_stock_threshold","show_when_sold_out","sale_price_amount","sale_price_currency","type","series_id","artist_id","brand_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18) RETURNING "id"`
+	getAllProductQuery        = `SELECT * FROM "products" WHERE "products"."deleted_at" IS NULL`
+	getProductByIdQuery       = `SELECT * FROM "products" WHERE "products"."id" = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2`
+	updateProductQuery        = `UPDATE "products" SET "updated_at"=$1,"name"=$2,"description"=$3,"price_amount"=$4,"price_currency"=$5,"image_url"=$6,"active"=$7 WHERE id = $8 AND "products"."deleted_at" IS NULL`
+	getProductforUpdateQuery  = `SELECT * FROM "products" WHERE id = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2 FOR UPDATE`
+	updateStockProductQuery   = `UPDATE "products" SET "stock"=$1,"updated_at"=$2 WHERE "products"."deleted_at" IS NULL AND "id" = $3`
+	countProductVariantsQuery = `SELECT count(*) FROM "product_variants" WHERE product_id = $1 AND "product_variants"."deleted_at" IS NULL`
+	getVariantForUpdateQuery  = `SELECT * FROM "product_variants" WHERE (id = $1 AND product_id = $2) AND "product_variants"."deleted_at" IS NULL ORDER BY "product_variants"."id" LIMIT $3 FOR UPDATE`
+	updateVariantStockQuery   = `UPDATE "product_variants" SET "stock"=$1,"updated_at"=$2 WHERE "product_variants"."deleted_at" IS NULL AND "id" = $3`
+	sumVariantStockQuery      = `SELECT COALESCE(SUM(stock), 0) FROM "product_variants" WHERE product_id = $1 AND "product_variants"."deleted_at" IS NULL`
+	syncProductStockQuery     = `UPDATE "products" SET "stock"=$1,"updated_at"=$2 WHERE id = $3 AND "products"."deleted_at" IS NULL`
+	fulfilProductSaleQuery    = `SELECT "warehouse_stocks"."id","warehouse_stocks"."updated_at","warehouse_stocks"."warehouse_id","warehouse_stocks"."product_id","warehouse_stocks"."variant_id","warehouse_stocks"."stock" FROM "warehouse_stocks" JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.active AND warehouses.deleted_at IS NULL WHERE (warehouse_stocks.product_id = $1 AND warehouse_stocks.variant_id IS NULL) AND warehouse_stocks.stock > 0 ORDER BY warehouses.priority, warehouses.id FOR UPDATE OF "warehouse_stocks"`
+	fulfilVariantSaleQuery    = `SELECT "warehouse_stocks"."id","warehouse_stocks"."updated_at","warehouse_stocks"."warehouse_id","warehouse_stocks"."product_id","warehouse_stocks"."variant_id","warehouse_stocks"."stock" FROM "warehouse_stocks" JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.active AND warehouses.deleted_at IS NULL WHERE (warehouse_stocks.product_id = $1 AND warehouse_stocks.variant_id = $2) AND warehouse_stocks.stock > 0 ORDER BY warehouses.priority, warehouses.id FOR UPDATE OF "warehouse_stocks"`
+	insertPriceChangeQuery    = `INSERT INTO "price_changes" ("created_at","product_id","price_amount","price_currency","sale_price_amount","sale_price_currency","sale_starts_at","sale_ends_at","source","actor_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`
+	insertStockMovementQuery  = `INSERT INTO "stock_movements" ("created_at","product_id","variant_id","warehouse_id","kind","quantity","balance","actor_id","reference_id","note") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`
 )
 
+// expectProductDetails registers the association queries issued by
+// preloadProductDetails for the given product IDs.
+func expectProductDetails(mock sqlmock.Sqlmock, imageRows *sqlmock.Rows, productIDs ...driver.Value) {
+	placeholders := make([]string, len(productIDs))
+	for i := range productIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	condition := "= " + placeholders[0]
+	if len(productIDs) > 1 {
+		condition = "IN (" + strings.Join(placeholders, ",") + ")"
+	}
+
+	if imageRows == nil {
+		imageRows = sqlmock.NewRows([]string{"id", "product_id", "url"})
+	}
+
+	mock.ExpectQuery(`SELECT * FROM "product_categories" WHERE "product_categories"."product_id" ` + condition).
+		WithArgs(productIDs...).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "category_id"}))
+	mock.ExpectQuery(`SELECT * FROM "product_images" WHERE "product_images"."product_id" ` + condition + ` AND "product_images"."deleted_at" IS NULL ORDER BY sort_order, id`).
+		WithArgs(productIDs...).
+		WillReturnRows(imageRows)
+	mock.ExpectQuery(`SELECT * FROM "product_variants" WHERE "product_variants"."product_id" ` + condition + ` AND "product_variants"."deleted_at" IS NULL ORDER BY sort_order, id`).
+		WithArgs(productIDs...).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "sku"}))
+}
+
 func TestInsertProduct_gormRepo(t *testing.T) {
 	t.Run("insert new product successfully", func(t *testing.T) {
 		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
@@ -33,14 +73,17 @@ func TestInsertProduct_gormRepo(t *testing.T) {
 
 		newProduct := &entities.Product{
 			Name: "Molly Classic", Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price: 340.99, Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
+			Price: money.New(34099, "THB"), Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
 		}
 
 		mock.ExpectBegin()
 		row := sqlmock.NewRows([]string{"id"}).AddRow(1)
 		mock.ExpectQuery(insertProductQuery).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), newProduct.Name, newProduct.Description, newProduct.Price, newProduct.Stock, newProduct.ImageURL, newProduct.Active).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), newProduct.Name, newProduct.Description, newProduct.Price.Amount, newProduct.Price.Currency, newProduct.Stock, newProduct.ImageURL, newProduct.Active, 0, false, nil, nil, "standard", nil, nil, nil).
 			WillReturnRows(row)
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "restock", 30, 30, nil, "", "initial stock").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 		mock.ExpectCommit()
 
 		got, err := repo.InsertProduct(newProduct)
@@ -49,10 +92,11 @@ func TestInsertProduct_gormRepo(t *testing.T) {
 			Model:       gorm.Model{ID: 1, CreatedAt: got.CreatedAt, UpdatedAt: got.UpdatedAt, DeletedAt: got.DeletedAt},
 			Name:        "Molly Classic",
 			Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price:       340.99,
+			Price:       money.New(34099, "THB"),
 			Stock:       30,
 			ImageURL:    "https://example.com/images/molly-classic.jpg",
 			Active:      true,
+			Type:        "standard",
 		}
 
 		assert.NoError(t, err)
@@ -71,11 +115,11 @@ func TestInsertProduct_gormRepo(t *testing.T) {
 
 		newProduct := &entities.Product{
 			Name: "Molly Classic", Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price: 340.99, Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
+			Price: money.New(34099, "THB"), Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
 		}
 
 		mock.ExpectQuery(insertProductQuery).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), newProduct.Name, newProduct.Description, newProduct.Price, newProduct.Stock, newProduct.ImageURL, newProduct.Active).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), newProduct.Name, newProduct.Description, newProduct.Price.Amount, newProduct.Price.Currency, newProduct.Stock, newProduct.ImageURL, newProduct.Active, 0, false, nil, nil, "standard", nil, nil, nil).
 			WillReturnError(gorm.ErrRecordNotFound)
 
 		got, err := repo.InsertProduct(newProduct)
@@ -94,19 +138,24 @@ func TestGetAllProducts_gormRepo(t *testing.T) {
 		repo := NewProductRepository(gormDB)
 
 		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "image_url", "active",
-		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 49.99, 25, "https://example.com/images/dimoo-starry-night.jpg", true).
-			AddRow(2, "Pucky Forest Fairy", "A magical art toy figure from Pucky, with a whimsical forest fairy design.", 44.99, 40, "https://example.com/images/pucky-forest-fairy.jpg", true)
+			"id", "name", "description", "price_amount", "price_currency", "stock", "image_url", "active",
+		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 4999, "THB", 25, "https://example.com/images/dimoo-starry-night.jpg", true).
+			AddRow(2, "Pucky Forest Fairy", "A magical art toy figure from Pucky, with a whimsical forest fairy design.", 4499, "THB", 40, "https://example.com/images/pucky-forest-fairy.jpg", true)
 
 		mock.ExpectQuery(getAllProductQuery).WillReturnRows(rows)
+		expectProductDetails(mock, sqlmock.NewRows([]string{"id", "product_id", "url", "alt_text", "sort_order", "is_primary"}).
+			AddRow(7, 1, "https://example.com/images/dimoo-starry-night.jpg", "Dimoo front", 0, true), 1, 2)
 
 		got, err := repo.GetAllProduct()
 
 		assert.NoError(t, err)
 
 		want := []entities.Product{
-			{Model: gorm.Model{ID: 1}, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, Stock: 25, ImageURL: "https://example.com/images/dimoo-starry-night.jpg", Active: true},
-			{Model: gorm.Model{ID: 2}, Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true},
+			{Model: gorm.Model{ID: 1}, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: money.New(4999, "THB"), Stock: 25, ImageURL: "https://example.com/images/dimoo-starry-night.jpg", Active: true,
+				Images:     []entities.ProductImage{{Model: gorm.Model{ID: 7}, ProductID: 1, URL: "https://example.com/images/dimoo-starry-night.jpg", AltText: "Dimoo front", IsPrimary: true}},
+				Categories: []entities.Category{}, Variants: []entities.ProductVariant{}},
+			{Model: gorm.Model{ID: 2}, Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: money.New(4499, "THB"), Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true,
+				Images: []entities.ProductImage{}, Categories: []entities.Category{}, Variants: []entities.ProductVariant{}},
 		}
 
 		if !reflect.DeepEqual(got, want) {
@@ -140,12 +189,13 @@ func TestGetProductById_gormRepo(t *testing.T) {
 		repo := NewProductRepository(gormDB)
 
 		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "image_url", "active",
-		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 49.99, 25, "https://example.com/images/dimoo-starry-night.jpg", true)
+			"id", "name", "description", "price_amount", "price_currency", "stock", "image_url", "active",
+		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 4999, "THB", 25, "https://example.com/images/dimoo-starry-night.jpg", true)
 
 		mock.ExpectQuery(getProductByIdQuery).
 			WithArgs("1", 1).
 			WillReturnRows(rows)
+		expectProductDetails(mock, nil, 1)
 
 		got, err := repo.GetProductById("1")
 
@@ -155,10 +205,13 @@ func TestGetProductById_gormRepo(t *testing.T) {
 			Model:       gorm.Model{ID: 1, CreatedAt: time.Time{}, UpdatedAt: time.Time{}},
 			Name:        "Dimoo Starry Night",
 			Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.",
-			Price:       49.99,
+			Price:       money.New(4999, "THB"),
 			Stock:       25,
 			ImageURL:    "https://example.com/images/dimoo-starry-night.jpg",
 			Active:      true,
+			Images:      []entities.ProductImage{},
+			Categories:  []entities.Category{},
+			Variants:    []entities.ProductVariant{},
 		}
 
 		if !reflect.DeepEqual(got, want) {
@@ -192,15 +245,16 @@ func TestUpdateProduct_gormRepo(t *testing.T) {
 		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
 		repo := NewProductRepository(gormDB)
 
-		updateInput := &entities.Product{Name: "Updated Product Name", Description: "Updated Description", Price: 99.99, Stock: 50,
+		updateInput := &entities.Product{Name: "Updated Product Name", Description: "Updated Description", Price: money.New(9999, "THB"), Stock: 50,
 			ImageURL: "https://example.com/images/updated-product.jpg", Active: true}
 
 		mock.ExpectBegin()
-
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("12", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency"}).AddRow(12, 9999, "THB"))
 		mock.ExpectExec(updateProductQuery).
-			WithArgs(sqlmock.AnyArg(), updateInput.Name, updateInput.Description, updateInput.Price, updateInput.Stock, updateInput.ImageURL, updateInput.Active, "12").
+			WithArgs(sqlmock.AnyArg(), updateInput.Name, updateInput.Description, updateInput.Price.Amount, updateInput.Price.Currency, updateInput.ImageURL, updateInput.Active, "12").
 			WillReturnResult(sqlmock.NewResult(0, 1))
-
 		mock.ExpectCommit()
 
 		got, err := repo.UpdateProduct(updateInput, "12")
@@ -210,7 +264,35 @@ func TestUpdateProduct_gormRepo(t *testing.T) {
 		if !reflect.DeepEqual(got, updateInput) {
 			t.Errorf("got %v but want %v", got, updateInput)
 		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("record price change in history", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductRepository(gormDB)
+
+		updateInput := &entities.Product{Name: "Updated Product Name", Description: "Updated Description", Price: money.New(8999, "THB"),
+			ImageURL: "https://example.com/images/updated-product.jpg", Active: true}
 
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("12", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency"}).AddRow(12, 9999, "THB"))
+		mock.ExpectExec(updateProductQuery).
+			WithArgs(sqlmock.AnyArg(), updateInput.Name, updateInput.Description, updateInput.Price.Amount, updateInput.Price.Currency, updateInput.ImageURL, updateInput.Active, "12").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertPriceChangeQuery).
+			WithArgs(sqlmock.AnyArg(), 12, 8999, "THB", nil, nil, nil, nil, "update", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		_, err := repo.UpdateProduct(updateInput, "12")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("database error during query", func(t *testing.T) {
@@ -220,17 +302,23 @@ func TestUpdateProduct_gormRepo(t *testing.T) {
 		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
 		repo := NewProductRepository(gormDB)
 
-		updateInput := &entities.Product{Name: "Updated Product Name", Description: "Updated Description", Price: 99.99, Stock: 50,
+		updateInput := &entities.Product{Name: "Updated Product Name", Description: "Updated Description", Price: money.New(9999, "THB"), Stock: 50,
 			ImageURL: "https://example.com/images/updated-product.jpg", Active: true}
 
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("20", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price_amount", "price_currency"}).AddRow(20, 9999, "THB"))
 		mock.ExpectExec(updateProductQuery).
-			WithArgs(sqlmock.AnyArg(), updateInput.Name, updateInput.Description, updateInput.Price, updateInput.Stock, updateInput.ImageURL, updateInput.Active, "20").
+			WithArgs(sqlmock.AnyArg(), updateInput.Name, updateInput.Description, updateInput.Price.Amount, updateInput.Price.Currency, updateInput.ImageURL, updateInput.Active, "20").
 			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
 
 		updatedProfile, err := repo.UpdateProduct(updateInput, "20")
 
 		assert.Error(t, err)
 		assert.Nil(t, updatedProfile)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -243,23 +331,33 @@ func TestUpdateStockProduct_gornRepo(t *testing.T) {
 		repo := NewProductRepository(gormDB)
 
 		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "image_url", "active",
-		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 49.99, 20, "https://example.com/images/dimoo-starry-night.jpg", true)
+			"id", "name", "description", "price_amount", "price_currency", "stock", "image_url", "active",
+		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 4999, "THB", 20, "https://example.com/images/dimoo-starry-night.jpg", true)
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(getProductforUpdateQuery).
 			WithArgs("1", 1).
 			WillReturnRows(rows)
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
 		mock.ExpectExec(updateStockProductQuery).
-			WithArgs(true, 18, sqlmock.AnyArg(), 1).
+			WithArgs(18, sqlmock.AnyArg(), 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(fulfilProductSaleQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "sale", -2, 18, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
 		mock.ExpectCommit()
 
-		newStock, err := repo.UpdateStock("1", 2)
+		newStock, err := repo.UpdateStock("1", 0, 2, "ORD-1001")
 
 		assert.NoError(t, err)
 		assert.Equal(t, 18, newStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("product not found", func(t *testing.T) {
@@ -275,7 +373,7 @@ func TestUpdateStockProduct_gornRepo(t *testing.T) {
 			WillReturnError(gorm.ErrRecordNotFound)
 		mock.ExpectRollback()
 
-		_, err := repo.UpdateStock("1", 2)
+		_, err := repo.UpdateStock("1", 0, 2, "")
 
 		assert.Error(t, err)
 		assert.Equal(t, "product not found", err.Error())
@@ -294,7 +392,7 @@ func TestUpdateStockProduct_gornRepo(t *testing.T) {
 			WillReturnError(errors.New("database error"))
 		mock.ExpectRollback()
 
-		_, err := repo.UpdateStock("1", 2)
+		_, err := repo.UpdateStock("1", 0, 2, "")
 
 		assert.Error(t, err)
 		assert.Equal(t, "failed to retrieve product", err.Error())
@@ -308,16 +406,19 @@ func TestUpdateStockProduct_gornRepo(t *testing.T) {
 		repo := NewProductRepository(gormDB)
 
 		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "image_url", "active",
-		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 49.99, 20, "https://example.com/images/dimoo-starry-night.jpg", true)
+			"id", "name", "description", "price_amount", "price_currency", "stock", "image_url", "active",
+		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 4999, "THB", 20, "https://example.com/images/dimoo-starry-night.jpg", true)
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(getProductforUpdateQuery).
 			WithArgs("1", 1).
 			WillReturnRows(rows)
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 		mock.ExpectRollback()
 
-		_, err := repo.UpdateStock("1", 25)
+		_, err := repo.UpdateStock("1", 0, 25, "")
 
 		assert.Error(t, err)
 		assert.Equal(t, "insufficient stock", err.Error())
@@ -331,81 +432,105 @@ func TestUpdateStockProduct_gornRepo(t *testing.T) {
 		repo := NewProductRepository(gormDB)
 
 		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "image_url", "active",
-		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 49.99, 20, "https://example.com/images/dimoo-starry-night.jpg", true)
+			"id", "name", "description", "price_amount", "price_currency", "stock", "image_url", "active",
+		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 4999, "THB", 20, "https://example.com/images/dimoo-starry-night.jpg", true)
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(getProductforUpdateQuery).
 			WithArgs("1", 1).
 			WillReturnRows(rows)
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 		mock.ExpectExec(updateStockProductQuery).
-			WithArgs(18, true, 1).
+			WithArgs(18, sqlmock.AnyArg(), 1).
 			WillReturnError(errors.New("update failed"))
 		mock.ExpectRollback()
 
-		_, err := repo.UpdateStock("1", 2)
+		_, err := repo.UpdateStock("1", 0, 2, "")
 
 		assert.Error(t, err)
 		assert.Equal(t, "failed to update product stock", err.Error())
 	})
 }
-func TestSearchProduct_gormRepo(t *testing.T) {
-	t.Run("search product successfully", func(t *testing.T) {
+func TestUpdateVariantStock_gormRepo(t *testing.T) {
+	t.Run("reduce variant stock and resync product total", func(t *testing.T) {
 		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		defer db.Close()
 
 		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
 		repo := NewProductRepository(gormDB)
 
-		rows := sqlmock.NewRows([]string{
-			"id", "name", "description", "price", "stock", "image_url", "active",
-		}).AddRow(1, "Dimoo Starry Night", "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", 49.99, 25, "https://example.com/images/dimoo-starry-night.jpg", true).
-			AddRow(2, "Pucky Forest Fairy Dimoo", "A magical art toy figure from Pucky, with a whimsical forest fairy design.", 44.99, 40, "https://example.com/images/pucky-forest-fairy.jpg", true)
-
-		mock.ExpectQuery(searchProductsQuery).WillReturnRows(rows)
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "stock", "active"}).AddRow(1, "Labubu Big Into Energy", 9, true))
+		mock.ExpectQuery(getVariantForUpdateQuery).
+			WithArgs(3, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "sku", "stock"}).AddRow(3, 1, "LBB-BIE-GLOW", 4))
+		mock.ExpectExec(updateVariantStockQuery).
+			WithArgs(2, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(sumVariantStockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(7))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(7, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fulfilVariantSaleQuery).
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, 3, nil, "sale", -2, 2, nil, "", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(41))
+		mock.ExpectCommit()
 
-		got, err := repo.SearchProducts("Dimoo")
+		newStock, err := repo.UpdateStock("1", 3, 2, "")
 
 		assert.NoError(t, err)
-
-		want := []entities.Product{
-			{Model: gorm.Model{ID: 1}, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, Stock: 25, ImageURL: "https://example.com/images/dimoo-starry-night.jpg", Active: true},
-			{Model: gorm.Model{ID: 2}, Name: "Pucky Forest Fairy Dimoo", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true},
-		}
-
-		if !reflect.DeepEqual(got, want) {
-			t.Errorf("got %v but want %v", got, want)
-		}
+		assert.Equal(t, 2, newStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("search product given product not found", func(t *testing.T) {
+	t.Run("reduce stock given product with variants and no variant", func(t *testing.T) {
 		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		defer db.Close()
 
 		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
 		repo := NewProductRepository(gormDB)
 
-		mock.ExpectQuery(searchProductsQuery).
-			WillReturnError(gorm.ErrRecordNotFound)
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 9))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectRollback()
 
-		_, err := repo.SearchProducts("dimond")
+		_, err := repo.UpdateStock("1", 0, 2, "")
 
-		assert.Error(t, err)
-		assert.Equal(t, "product not found", err.Error())
+		assert.EqualError(t, err, "variant required")
 	})
 
-	t.Run("search product given database error", func(t *testing.T) {
+	t.Run("reduce stock given insufficient variant stock", func(t *testing.T) {
 		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		defer db.Close()
 
 		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
 		repo := NewProductRepository(gormDB)
 
-		mock.ExpectQuery(searchProductsQuery).
-			WillReturnError(errors.New("database error"))
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 9))
+		mock.ExpectQuery(getVariantForUpdateQuery).
+			WithArgs(5, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "sku", "stock"}).AddRow(5, 1, "LBB-BIE-CHASE", 1))
+		mock.ExpectRollback()
 
-		_, err := repo.SearchProducts("dimond")
+		_, err := repo.UpdateStock("1", 5, 2, "")
 
-		assert.Error(t, err)
+		assert.EqualError(t, err, "insufficient stock")
 	})
 }
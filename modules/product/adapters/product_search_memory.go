@@ -0,0 +1,427 @@
+package adapters
+
+import (
+	"html"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/trigram"
+)
+
+const (
+	memoryNameWeight        = 1.0
+	memoryDescriptionWeight = 0.4
+	memorySnippetWords      = 30
+)
+
+// memoryProductSearchRepository searches a fixed catalogue in process. It
+// follows the same rules as the Postgres search (every term must prefix a
+// word, name matches outrank description matches, categories include their
+// subcategories, prices are what products sell for now, pg_trgm thresholds
+// for suggestions) so it can stand in for it in tests and local runs without
+// a database.
+type memoryProductSearchRepository struct {
+	products   []entities.Product
+	categories map[uint]entities.Category
+	series     []entities.Series
+	artists    []entities.Artist
+}
+
+func NewMemoryProductSearchRepository(products []entities.Product, categories []entities.Category, series []entities.Series, artists []entities.Artist) usecase.ProductSearchRepository {
+	byID := make(map[uint]entities.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	return &memoryProductSearchRepository{products, byID, series, artists}
+}
+
+func (r *memoryProductSearchRepository) SearchProducts(query *entities.SearchQuery) ([]entities.SearchHit, int64, error) {
+	hits := []entities.SearchHit{}
+	now := time.Now()
+
+	for _, product := range r.products {
+		if !memoryListed(product) {
+			continue
+		}
+
+		rank, ok := memoryRank(product, query.Terms)
+		if !ok || !r.matchesFilters(product, &query.Filters, "", now) {
+			continue
+		}
+
+		hits = append(hits, entities.SearchHit{
+			Product: product,
+			Rank:    rank,
+			Snippet: memorySnippet(product.Description, query.Terms),
+		})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Product.ID < hits[j].Product.ID
+	})
+
+	total := int64(len(hits))
+
+	start := min(query.Offset, len(hits))
+	end := len(hits)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, len(hits))
+	}
+
+	return hits[start:end], total, nil
+}
+
+func (r *memoryProductSearchRepository) SearchFacets(query *entities.SearchQuery) (*entities.SearchFacets, error) {
+	categories := newMemoryFacet()
+	artists := newMemoryFacet()
+	prices := newMemoryFacet()
+	availability := newMemoryFacet()
+	editions := newMemoryFacet()
+	now := time.Now()
+
+	for _, product := range r.products {
+		if !memoryListed(product) {
+			continue
+		}
+		if _, ok := memoryRank(product, query.Terms); !ok {
+			continue
+		}
+
+		if r.matchesFilters(product, &query.Filters, facetCategory, now) {
+			for _, id := range r.productCategories(product) {
+				categories.add(strconv.FormatUint(uint64(id), 10), r.categories[id].Name)
+			}
+		}
+
+		if r.matchesFilters(product, &query.Filters, facetArtist, now) && product.Artist != nil {
+			artists.add(strconv.FormatUint(uint64(product.Artist.ID), 10), product.Artist.Name)
+		}
+
+		if r.matchesFilters(product, &query.Filters, facetPrice, now) {
+			for _, key := range memoryPriceBuckets(product, now) {
+				prices.add(key, "")
+			}
+		}
+
+		if r.matchesFilters(product, &query.Filters, facetAvailability, now) {
+			availability.add(memoryAvailability(product), "")
+		}
+
+		if r.matchesFilters(product, &query.Filters, facetEdition, now) {
+			for _, edition := range memoryEditions(product) {
+				editions.add(edition, "")
+			}
+		}
+	}
+
+	return &entities.SearchFacets{
+		Categories:   categories.counts(),
+		Artists:      artists.counts(),
+		Prices:       prices.counts(),
+		Availability: availability.counts(),
+		Editions:     editions.counts(),
+	}, nil
+}
+
+func (r *memoryProductSearchRepository) Autocomplete(query string, limit int) ([]entities.Suggestion, error) {
+	suggestions := []entities.Suggestion{}
+
+	add := func(kind string, id uint, name string) {
+		if score := trigram.WordSimilarity(query, name); score >= trigram.WordSimilarityThreshold {
+			suggestions = append(suggestions, entities.Suggestion{Kind: kind, ID: id, Name: name, Score: score})
+		}
+	}
+
+	for _, product := range r.products {
+		if memoryListed(product) {
+			add(entities.SuggestionKindProduct, product.ID, product.Name)
+		}
+	}
+	for _, series := range r.series {
+		if !series.DeletedAt.Valid {
+			add(entities.SuggestionKindSeries, series.ID, series.Name)
+		}
+	}
+	for _, artist := range r.artists {
+		if !artist.DeletedAt.Valid {
+			add(entities.SuggestionKindArtist, artist.ID, artist.Name)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
+
+func (r *memoryProductSearchRepository) ClosestWord(term string) (string, error) {
+	var names []string
+	for _, product := range r.products {
+		if memoryListed(product) {
+			names = append(names, product.Name)
+		}
+	}
+	for _, series := range r.series {
+		if !series.DeletedAt.Valid {
+			names = append(names, series.Name)
+		}
+	}
+	for _, artist := range r.artists {
+		if !artist.DeletedAt.Valid {
+			names = append(names, artist.Name)
+		}
+	}
+
+	var closest string
+	var best float64
+	for _, name := range names {
+		for _, word := range memoryWords(name) {
+			score := trigram.Similarity(word, term)
+			if score < trigram.SimilarityThreshold {
+				continue
+			}
+			if score > best || (score == best && word < closest) {
+				closest, best = word, score
+			}
+		}
+	}
+
+	return closest, nil
+}
+
+func (r *memoryProductSearchRepository) matchesFilters(product entities.Product, filters *entities.SearchFilters, skip string, now time.Time) bool {
+	if len(filters.CategoryIDs) > 0 && skip != facetCategory {
+		if !slices.ContainsFunc(r.productCategories(product), func(id uint) bool {
+			return slices.Contains(filters.CategoryIDs, id)
+		}) {
+			return false
+		}
+	}
+
+	if len(filters.ArtistIDs) > 0 && skip != facetArtist {
+		if product.ArtistID == nil || !slices.Contains(filters.ArtistIDs, *product.ArtistID) {
+			return false
+		}
+	}
+
+	if len(filters.PriceBuckets) > 0 && skip != facetPrice {
+		if !slices.ContainsFunc(memoryPriceBuckets(product, now), func(key string) bool {
+			return slices.Contains(filters.PriceBuckets, key)
+		}) {
+			return false
+		}
+	}
+
+	if filters.Availability != "" && skip != facetAvailability {
+		if memoryAvailability(product) != filters.Availability {
+			return false
+		}
+	}
+
+	if len(filters.Editions) > 0 && skip != facetEdition {
+		if !slices.ContainsFunc(memoryEditions(product), func(edition string) bool {
+			return slices.Contains(filters.Editions, edition)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// productCategories lists the product's categories and all their ancestors,
+// each once, the categories it is found under and counts towards.
+func (r *memoryProductSearchRepository) productCategories(product entities.Product) []uint {
+	var ids []uint
+	for _, category := range product.Categories {
+		for id := &category.ID; id != nil; id = r.categories[*id].ParentID {
+			if _, ok := r.categories[*id]; !ok || slices.Contains(ids, *id) {
+				break
+			}
+			ids = append(ids, *id)
+		}
+	}
+
+	return ids
+}
+
+// memoryListed mirrors listedProducts.
+func memoryListed(product entities.Product) bool {
+	return product.Active && !product.DeletedAt.Valid && (product.Stock > 0 || product.ShowWhenSoldOut)
+}
+
+// memoryPriceBuckets lists the buckets holding any price the product sells for
+// at now: its variants' prices, or its sale price while a sale cheaper than
+// the list price runs, otherwise its list price.
+func memoryPriceBuckets(product entities.Product, now time.Time) []string {
+	low, high := product.Price.Amount, product.Price.Amount
+	if sale := product.SalePrice; sale != nil && sale.Amount < low &&
+		(product.SaleStartsAt == nil || !now.Before(*product.SaleStartsAt)) &&
+		(product.SaleEndsAt == nil || now.Before(*product.SaleEndsAt)) {
+		low, high = sale.Amount, sale.Amount
+	}
+
+	first := true
+	for _, variant := range product.Variants {
+		if variant.DeletedAt.Valid {
+			continue
+		}
+		if first || variant.Price.Amount < low {
+			low = variant.Price.Amount
+		}
+		if first || variant.Price.Amount > high {
+			high = variant.Price.Amount
+		}
+		first = false
+	}
+
+	var keys []string
+	for _, bucket := range entities.PriceBuckets {
+		if (bucket.Max == 0 || low < bucket.Max) && high >= bucket.Min {
+			keys = append(keys, bucket.Key)
+		}
+	}
+
+	return keys
+}
+
+func memoryEditions(product entities.Product) []string {
+	var editions []string
+	for _, variant := range product.Variants {
+		if !variant.DeletedAt.Valid && !slices.Contains(editions, variant.Edition) {
+			editions = append(editions, variant.Edition)
+		}
+	}
+
+	return editions
+}
+
+func memoryAvailability(product entities.Product) string {
+	if product.Stock > 0 {
+		return entities.AvailabilityInStock
+	}
+
+	return entities.AvailabilitySoldOut
+}
+
+// memoryFacet tallies facet values and reports them like the Postgres
+// aggregates: highest count first, then by label or value.
+type memoryFacet struct {
+	order  []string
+	labels map[string]string
+	totals map[string]int64
+}
+
+func newMemoryFacet() *memoryFacet {
+	return &memoryFacet{labels: map[string]string{}, totals: map[string]int64{}}
+}
+
+func (f *memoryFacet) add(value, label string) {
+	if _, ok := f.totals[value]; !ok {
+		f.order = append(f.order, value)
+		f.labels[value] = label
+	}
+	f.totals[value]++
+}
+
+func (f *memoryFacet) counts() []entities.FacetCount {
+	counts := []entities.FacetCount{}
+	for _, value := range f.order {
+		counts = append(counts, entities.FacetCount{Value: value, Label: f.labels[value], Count: f.totals[value]})
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].Label != counts[j].Label {
+			return counts[i].Label < counts[j].Label
+		}
+		return counts[i].Value < counts[j].Value
+	})
+
+	return counts
+}
+
+// memoryRank scores a product the way ts_rank_cd with normalisation 32 does
+// in spirit: a weighted match count scaled into [0, 1).
+func memoryRank(product entities.Product, terms []string) (float64, bool) {
+	nameWords := memoryWords(product.Name)
+	descriptionWords := memoryWords(product.Description)
+
+	var score float64
+	for _, term := range terms {
+		switch {
+		case memoryMatches(nameWords, term):
+			score += memoryNameWeight
+		case memoryMatches(descriptionWords, term):
+			score += memoryDescriptionWeight
+		default:
+			return 0, false
+		}
+	}
+
+	return score / (score + 1), true
+}
+
+// memorySnippet escapes the description for HTML and marks the matched words,
+// as the Postgres snippet does.
+func memorySnippet(description string, terms []string) string {
+	words := strings.Fields(description)
+	if len(words) == 0 {
+		return ""
+	}
+
+	first := -1
+	marked := make([]string, len(words))
+	for i, word := range words {
+		marked[i] = html.EscapeString(word)
+		if memoryMatches(memoryWords(word), terms...) {
+			marked[i] = "<mark>" + marked[i] + "</mark>"
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+
+	start := max(first-memorySnippetWords/3, 0)
+	end := min(start+memorySnippetWords, len(marked))
+
+	return strings.Join(marked[start:end], " ")
+}
+
+func memoryWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	})
+}
+
+func memoryMatches(words []string, terms ...string) bool {
+	for _, word := range words {
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,344 @@
+package adapters
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+)
+
+// searchHeadlineOptions marks matched words in the description snippet.
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2"
+
+// escapedDescription is the description escaped for HTML the way
+// html.EscapeString does it. ts_headline copies markup in the text through as
+// it is, so the snippet is cut from the escaped text and only the <mark> tags
+// it adds are left as markup.
+const escapedDescription = `replace(replace(replace(replace(replace(coalesce(description, ''),
+	'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+
+// autocompleteQuery ranks product, series and artist names by how closely the
+// typed text matches a word in them, so "labubo" still finds "Labubu". The
+// <% operator and the name indexes come from the pg_trgm extension, which
+// CreateSearchExtensions installs.
+const autocompleteQuery = `SELECT kind, id, name, score FROM (
+	SELECT 'product' AS kind, id, name, word_similarity(@query, name) AS score
+	FROM products WHERE deleted_at IS NULL AND ` + listedProducts + ` AND @query <% name
+	UNION ALL
+	SELECT 'series', id, name, word_similarity(@query, name)
+	FROM series WHERE deleted_at IS NULL AND @query <% name
+	UNION ALL
+	SELECT 'artist', id, name, word_similarity(@query, name)
+	FROM artists WHERE deleted_at IS NULL AND @query <% name
+) suggestions
+ORDER BY score DESC, name
+LIMIT @limit`
+
+// closestWordQuery finds the catalogue word most similar to a search term.
+const closestWordQuery = `SELECT word FROM (
+	SELECT lower(regexp_split_to_table(name, '[[:space:][:punct:]]+')) AS word FROM products WHERE deleted_at IS NULL AND ` + listedProducts + `
+	UNION
+	SELECT lower(regexp_split_to_table(name, '[[:space:][:punct:]]+')) FROM series WHERE deleted_at IS NULL
+	UNION
+	SELECT lower(regexp_split_to_table(name, '[[:space:][:punct:]]+')) FROM artists WHERE deleted_at IS NULL
+) words
+WHERE word % @term
+ORDER BY similarity(word, @term) DESC, word
+LIMIT 1`
+
+// categoryFacetQuery counts the matching products under each category. A
+// product linked to a subcategory also counts towards every ancestor, the
+// same way the category filter matches descendants.
+const categoryFacetQuery = `WITH RECURSIVE category_ancestors AS (
+	SELECT id AS category_id, id AS ancestor_id, parent_id FROM categories WHERE deleted_at IS NULL
+	UNION ALL
+	SELECT a.category_id, c.id, c.parent_id FROM category_ancestors a
+	JOIN categories c ON c.id = a.parent_id AND c.deleted_at IS NULL
+)
+SELECT categories.id AS value, categories.name AS label, count(DISTINCT product_categories.product_id) AS count
+FROM product_categories
+JOIN category_ancestors ON category_ancestors.category_id = product_categories.category_id
+JOIN categories ON categories.id = category_ancestors.ancestor_id
+WHERE product_categories.product_id IN (?)
+GROUP BY categories.id, categories.name
+ORDER BY count DESC, categories.name`
+
+// CreateSearchExtensions installs the pg_trgm extension behind the name
+// indexes and the similarity operators search uses. It must run before the
+// catalogue tables are auto-migrated, as their gin_trgm_ops indexes cannot be
+// built without it.
+func CreateSearchExtensions(db *gorm.DB) error {
+	return db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+}
+
+type gormProductSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewProductSearchRepository(db *gorm.DB) usecase.ProductSearchRepository {
+	return &gormProductSearchRepository{db}
+}
+
+type searchRow struct {
+	ID      uint
+	Rank    float64
+	Snippet string
+}
+
+// SearchProducts matches against the generated search_vector column. Ranking
+// and snippets are computed for the requested page only; the products
+// themselves are then loaded with their usual details.
+func (r *gormProductSearchRepository) SearchProducts(query *entities.SearchQuery) ([]entities.SearchHit, int64, error) {
+	tsquery := toTSQuery(query.Terms)
+	now := time.Now()
+
+	var total int64
+	if err := r.db.Model(&entities.Product{}).
+		Scopes(matchSearchVector(tsquery), searchFilters(&query.Filters, "", now)).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return []entities.SearchHit{}, 0, nil
+	}
+
+	var rows []searchRow
+	if err := r.db.Model(&entities.Product{}).
+		Select("id, ts_rank_cd(search_vector, to_tsquery('english', ?), 32) AS rank, "+
+			"ts_headline('english', "+escapedDescription+", to_tsquery('english', ?), ?) AS snippet",
+			tsquery, tsquery, searchHeadlineOptions).
+		Scopes(matchSearchVector(tsquery), searchFilters(&query.Filters, "", now)).
+		Order("rank DESC, id").
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if len(rows) == 0 {
+		return []entities.SearchHit{}, total, nil
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+
+	var products []entities.Product
+	if err := r.db.Scopes(preloadProductDetails).Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[uint]entities.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	hits := make([]entities.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		product, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, entities.SearchHit{Product: product, Rank: row.Rank, Snippet: row.Snippet})
+	}
+
+	return hits, total, nil
+}
+
+// SearchFacets counts matches per facet value. Each facet runs as its own
+// aggregate with every other selected filter applied.
+func (r *gormProductSearchRepository) SearchFacets(query *entities.SearchQuery) (*entities.SearchFacets, error) {
+	tsquery := toTSQuery(query.Terms)
+	now := time.Now()
+	matches := func(skip string) *gorm.DB {
+		return r.db.Model(&entities.Product{}).Scopes(matchSearchVector(tsquery), searchFilters(&query.Filters, skip, now))
+	}
+
+	facets := &entities.SearchFacets{}
+
+	if err := r.db.Raw(categoryFacetQuery, matches(facetCategory).Select("products.id")).
+		Scan(&facets.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	if err := matches(facetArtist).
+		Select("artists.id AS value, artists.name AS label, count(*) AS count").
+		Joins("JOIN artists ON artists.id = products.artist_id AND artists.deleted_at IS NULL").
+		Group("artists.id, artists.name").
+		Order("count DESC, artists.name").
+		Scan(&facets.Artists).Error; err != nil {
+		return nil, err
+	}
+
+	buckets, bucketArgs := priceBucketsTable(entities.PriceBuckets)
+	overlaps, overlapArgs := overlapsPriceBucket(now)
+	if err := matches(facetPrice).
+		Select("price_buckets.key AS value, count(*) AS count").
+		Joins("JOIN "+buckets+" ON "+overlaps, append(bucketArgs, overlapArgs...)...).
+		Group("price_buckets.key").
+		Scan(&facets.Prices).Error; err != nil {
+		return nil, err
+	}
+
+	if err := matches(facetAvailability).
+		Select("CASE WHEN products.stock > 0 THEN ? ELSE ? END AS value, count(*) AS count", entities.AvailabilityInStock, entities.AvailabilitySoldOut).
+		Group("value").
+		Order("value").
+		Scan(&facets.Availability).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Table("product_variants").
+		Select("product_variants.edition AS value, count(DISTINCT product_variants.product_id) AS count").
+		Where("product_variants.deleted_at IS NULL AND product_variants.product_id IN (?)", matches(facetEdition).Select("products.id")).
+		Group("product_variants.edition").
+		Order("count DESC, product_variants.edition").
+		Scan(&facets.Editions).Error; err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (r *gormProductSearchRepository) Autocomplete(query string, limit int) ([]entities.Suggestion, error) {
+	var suggestions []entities.Suggestion
+
+	if err := r.db.Raw(autocompleteQuery, map[string]interface{}{"query": query, "limit": limit}).
+		Scan(&suggestions).Error; err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+func (r *gormProductSearchRepository) ClosestWord(term string) (string, error) {
+	var words []string
+
+	if err := r.db.Raw(closestWordQuery, map[string]interface{}{"term": term}).
+		Scan(&words).Error; err != nil {
+		return "", err
+	}
+
+	if len(words) == 0 {
+		return "", nil
+	}
+
+	return words[0], nil
+}
+
+func matchSearchVector(tsquery string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("products.search_vector @@ to_tsquery('english', ?) AND "+listedProducts, tsquery)
+	}
+}
+
+const (
+	facetCategory     = "category"
+	facetArtist       = "artist"
+	facetPrice        = "price"
+	facetAvailability = "availability"
+	facetEdition      = "edition"
+)
+
+// searchFilters applies the selected facet filters, leaving out the one named
+// by skip. Prices are matched as they stand at now.
+func searchFilters(filters *entities.SearchFilters, skip string, now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(filters.CategoryIDs) > 0 && skip != facetCategory {
+			db = db.Where(categoryTreeQuery, filters.CategoryIDs)
+		}
+
+		if len(filters.ArtistIDs) > 0 && skip != facetArtist {
+			db = db.Where("products.artist_id IN ?", filters.ArtistIDs)
+		}
+
+		if len(filters.PriceBuckets) > 0 && skip != facetPrice {
+			var selected []entities.PriceBucket
+			for _, bucket := range entities.PriceBuckets {
+				if slices.Contains(filters.PriceBuckets, bucket.Key) {
+					selected = append(selected, bucket)
+				}
+			}
+			buckets, bucketArgs := priceBucketsTable(selected)
+			overlaps, overlapArgs := overlapsPriceBucket(now)
+			db = db.Where("EXISTS (SELECT 1 FROM "+buckets+" WHERE "+overlaps+")", append(bucketArgs, overlapArgs...)...)
+		}
+
+		if filters.Availability != "" && skip != facetAvailability {
+			if filters.Availability == entities.AvailabilityInStock {
+				db = db.Where("products.stock > 0")
+			} else {
+				db = db.Where("products.stock = 0")
+			}
+		}
+
+		if len(filters.Editions) > 0 && skip != facetEdition {
+			db = db.Where("products.id IN (SELECT product_id FROM product_variants WHERE edition IN ? AND deleted_at IS NULL)", filters.Editions)
+		}
+
+		return db
+	}
+}
+
+// salePriceCase is a product's own price at the time bound to both
+// placeholders: its sale price while a sale cheaper than the list price runs,
+// otherwise its list price.
+const salePriceCase = `CASE WHEN products.sale_price_amount < products.price_amount
+	AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= ?)
+	AND (products.sale_ends_at IS NULL OR products.sale_ends_at > ?)
+	THEN products.sale_price_amount ELSE products.price_amount END`
+
+// effectivePriceBound is the lowest (bound "min") or highest ("max") price a
+// product sells for at now. A product with variants sells at its variants'
+// prices and is never on sale; any other product sells at salePriceCase.
+func effectivePriceBound(bound string, now time.Time) (string, []interface{}) {
+	sql := fmt.Sprintf("COALESCE((SELECT %s(product_variants.price_amount) FROM product_variants "+
+		"WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL), %s)", bound, salePriceCase)
+
+	return sql, []interface{}{now, now}
+}
+
+// priceBucketsTable lists buckets as a price_buckets table of key, min_amount
+// and max_amount, with a null max_amount for the open top bucket.
+func priceBucketsTable(buckets []entities.PriceBucket) (string, []interface{}) {
+	rows := make([]string, 0, len(buckets))
+	var args []interface{}
+	for _, bucket := range buckets {
+		rows = append(rows, "(?::varchar, ?::bigint, ?::bigint)")
+		var maxAmount interface{}
+		if bucket.Max > 0 {
+			maxAmount = bucket.Max
+		}
+		args = append(args, bucket.Key, bucket.Min, maxAmount)
+	}
+
+	return "(VALUES " + strings.Join(rows, ", ") + ") AS price_buckets(key, min_amount, max_amount)", args
+}
+
+// overlapsPriceBucket matches a product to a price_buckets row when any price
+// it sells for at now falls in the bucket, so a product whose variants span
+// two buckets is found under both.
+func overlapsPriceBucket(now time.Time) (string, []interface{}) {
+	low, lowArgs := effectivePriceBound("min", now)
+	high, highArgs := effectivePriceBound("max", now)
+
+	return "(price_buckets.max_amount IS NULL OR " + low + " < price_buckets.max_amount) AND " + high + " >= price_buckets.min_amount",
+		append(lowArgs, highArgs...)
+}
+
+// toTSQuery ANDs the terms together, each as a prefix. Terms only hold
+// letters and digits, so they cannot inject tsquery operators.
+func toTSQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term+":*")
+	}
+
+	return strings.Join(parts, " & ")
+}
@@ -0,0 +1,436 @@
+package adapters
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	countSearchMatchesQuery      = `SELECT count(*) FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $1) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND "products"."deleted_at" IS NULL`
+	rankSearchMatchesQuery       = `SELECT id, ts_rank_cd(search_vector, to_tsquery('english', $1), 32) AS rank, ts_headline('english', replace(replace(replace(replace(replace(coalesce(description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'), to_tsquery('english', $2), $3) AS snippet FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $4) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND "products"."deleted_at" IS NULL ORDER BY rank DESC, id LIMIT $5 OFFSET $6`
+	getSearchProductsQuery       = `SELECT * FROM "products" WHERE id IN ($1,$2) AND "products"."deleted_at" IS NULL`
+	autocompleteNamesQuery       = `SELECT kind, id, name, score FROM ( SELECT 'product' AS kind, id, name, word_similarity($1, name) AS score FROM products WHERE deleted_at IS NULL AND products.active AND (products.stock > 0 OR products.show_when_sold_out) AND $2 <% name UNION ALL SELECT 'series', id, name, word_similarity($3, name) FROM series WHERE deleted_at IS NULL AND $4 <% name UNION ALL SELECT 'artist', id, name, word_similarity($5, name) FROM artists WHERE deleted_at IS NULL AND $6 <% name ) suggestions ORDER BY score DESC, name LIMIT $7`
+	searchCategoryFacetQuery     = `WITH RECURSIVE category_ancestors AS ( SELECT id AS category_id, id AS ancestor_id, parent_id FROM categories WHERE deleted_at IS NULL UNION ALL SELECT a.category_id, c.id, c.parent_id FROM category_ancestors a JOIN categories c ON c.id = a.parent_id AND c.deleted_at IS NULL ) SELECT categories.id AS value, categories.name AS label, count(DISTINCT product_categories.product_id) AS count FROM product_categories JOIN category_ancestors ON category_ancestors.category_id = product_categories.category_id JOIN categories ON categories.id = category_ancestors.ancestor_id WHERE product_categories.product_id IN (SELECT products.id FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $1) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND products.stock > 0 AND "products"."deleted_at" IS NULL) GROUP BY categories.id, categories.name ORDER BY count DESC, categories.name`
+	searchArtistFacetQuery       = `SELECT artists.id AS value, artists.name AS label, count(*) AS count FROM "products" JOIN artists ON artists.id = products.artist_id AND artists.deleted_at IS NULL WHERE (products.search_vector @@ to_tsquery('english', $1) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND (products.id IN ( SELECT pc.product_id FROM product_categories pc WHERE pc.category_id IN ( WITH RECURSIVE category_tree AS ( SELECT id FROM categories WHERE id IN ($2) AND deleted_at IS NULL UNION ALL SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL ) SELECT id FROM category_tree ) )) AND products.stock > 0 AND "products"."deleted_at" IS NULL GROUP BY artists.id, artists.name ORDER BY count DESC, artists.name`
+	searchPriceFacetQuery        = `SELECT price_buckets.key AS value, count(*) AS count FROM "products" JOIN (VALUES ($1::varchar, $2::bigint, $3::bigint), ($4::varchar, $5::bigint, $6::bigint), ($7::varchar, $8::bigint, $9::bigint), ($10::varchar, $11::bigint, $12::bigint), ($13::varchar, $14::bigint, $15::bigint)) AS price_buckets(key, min_amount, max_amount) ON (price_buckets.max_amount IS NULL OR COALESCE((SELECT min(product_variants.price_amount) FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL), CASE WHEN products.sale_price_amount < products.price_amount AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= $16) AND (products.sale_ends_at IS NULL OR products.sale_ends_at > $17) THEN products.sale_price_amount ELSE products.price_amount END) < price_buckets.max_amount) AND COALESCE((SELECT max(product_variants.price_amount) FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL), CASE WHEN products.sale_price_amount < products.price_amount AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= $18) AND (products.sale_ends_at IS NULL OR products.sale_ends_at > $19) THEN products.sale_price_amount ELSE products.price_amount END) >= price_buckets.min_amount WHERE (products.search_vector @@ to_tsquery('english', $20) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND (products.id IN ( SELECT pc.product_id FROM product_categories pc WHERE pc.category_id IN ( WITH RECURSIVE category_tree AS ( SELECT id FROM categories WHERE id IN ($21) AND deleted_at IS NULL UNION ALL SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL ) SELECT id FROM category_tree ) )) AND products.stock > 0 AND "products"."deleted_at" IS NULL GROUP BY "price_buckets"."key"`
+	searchAvailabilityFacetQuery = `SELECT CASE WHEN products.stock > 0 THEN $1 ELSE $2 END AS value, count(*) AS count FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $3) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND (products.id IN ( SELECT pc.product_id FROM product_categories pc WHERE pc.category_id IN ( WITH RECURSIVE category_tree AS ( SELECT id FROM categories WHERE id IN ($4) AND deleted_at IS NULL UNION ALL SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL ) SELECT id FROM category_tree ) )) AND "products"."deleted_at" IS NULL GROUP BY "value" ORDER BY value`
+	searchEditionFacetQuery      = `SELECT product_variants.edition AS value, count(DISTINCT product_variants.product_id) AS count FROM "product_variants" WHERE product_variants.deleted_at IS NULL AND product_variants.product_id IN (SELECT products.id FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $1) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND (products.id IN ( SELECT pc.product_id FROM product_categories pc WHERE pc.category_id IN ( WITH RECURSIVE category_tree AS ( SELECT id FROM categories WHERE id IN ($2) AND deleted_at IS NULL UNION ALL SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL ) SELECT id FROM category_tree ) )) AND products.stock > 0 AND "products"."deleted_at" IS NULL) GROUP BY "product_variants"."edition" ORDER BY count DESC, product_variants.edition`
+	searchFiltersQuery           = `SELECT count(*) FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $1) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND (products.id IN ( SELECT pc.product_id FROM product_categories pc WHERE pc.category_id IN ( WITH RECURSIVE category_tree AS ( SELECT id FROM categories WHERE id IN ($2) AND deleted_at IS NULL UNION ALL SELECT c.id FROM categories c JOIN category_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL ) SELECT id FROM category_tree ) )) AND products.artist_id IN ($3,$4) AND (EXISTS (SELECT 1 FROM (VALUES ($5::varchar, $6::bigint, $7::bigint), ($8::varchar, $9::bigint, $10::bigint)) AS price_buckets(key, min_amount, max_amount) WHERE (price_buckets.max_amount IS NULL OR COALESCE((SELECT min(product_variants.price_amount) FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL), CASE WHEN products.sale_price_amount < products.price_amount AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= $11) AND (products.sale_ends_at IS NULL OR products.sale_ends_at > $12) THEN products.sale_price_amount ELSE products.price_amount END) < price_buckets.max_amount) AND COALESCE((SELECT max(product_variants.price_amount) FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL), CASE WHEN products.sale_price_amount < products.price_amount AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= $13) AND (products.sale_ends_at IS NULL OR products.sale_ends_at > $14) THEN products.sale_price_amount ELSE products.price_amount END) >= price_buckets.min_amount)) AND (products.id IN (SELECT product_id FROM product_variants WHERE edition IN ($15) AND deleted_at IS NULL)) AND "products"."deleted_at" IS NULL`
+	closestCatalogueWordQuery    = `SELECT word FROM ( SELECT lower(regexp_split_to_table(name, '[[:space:][:punct:]]+')) AS word FROM products WHERE deleted_at IS NULL AND products.active AND (products.stock > 0 OR products.show_when_sold_out) UNION SELECT lower(regexp_split_to_table(name, '[[:space:][:punct:]]+')) FROM series WHERE deleted_at IS NULL UNION SELECT lower(regexp_split_to_table(name, '[[:space:][:punct:]]+')) FROM artists WHERE deleted_at IS NULL ) words WHERE word % $1 ORDER BY similarity(word, $2) DESC, word LIMIT 1`
+)
+
+func TestCreateSearchExtensions(t *testing.T) {
+	t.Run("create pg_trgm if it is missing", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+
+		mock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := CreateSearchExtensions(gormDB)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("create pg_trgm error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+
+		mock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).
+			WillReturnError(errors.New("permission denied to create extension"))
+
+		err := CreateSearchExtensions(gormDB)
+
+		assert.EqualError(t, err, "permission denied to create extension")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchProducts_gormRepo(t *testing.T) {
+	t.Run("search products page in rank order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(countSearchMatchesQuery).
+			WithArgs("dimoo:* & star:*").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+		mock.ExpectQuery(rankSearchMatchesQuery).
+			WithArgs("dimoo:* & star:*", "dimoo:* & star:*", searchHeadlineOptions, "dimoo:* & star:*", 2, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "rank", "snippet"}).
+				AddRow(2, 0.6, "<mark>Dimoo</mark> <mark>Starry</mark> Night").
+				AddRow(1, 0.3, "A <mark>Dimoo</mark> under the <mark>stars</mark>"))
+		mock.ExpectQuery(getSearchProductsQuery).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price_amount", "price_currency", "active"}).
+				AddRow(1, "Dimoo Stargazer", 4499, "THB", true).
+				AddRow(2, "Dimoo Starry Night", 4999, "THB", true))
+		expectProductDetails(mock, nil, 1, 2)
+
+		got, total, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"dimoo", "star"}, Limit: 2, Offset: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "Dimoo Starry Night", got[0].Product.Name)
+		assert.Equal(t, 0.6, got[0].Rank)
+		assert.Equal(t, "<mark>Dimoo</mark> <mark>Starry</mark> Night", got[0].Snippet)
+		assert.Equal(t, "Dimoo Stargazer", got[1].Product.Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("search products given no match", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(countSearchMatchesQuery).
+			WithArgs("dimond:*").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		got, total, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"dimond"}, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Equal(t, []entities.SearchHit{}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("search products given database error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(countSearchMatchesQuery).
+			WillReturnError(errors.New("database error"))
+
+		_, _, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"dimond"}, Limit: 20})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchFacets_gormRepo(t *testing.T) {
+	t.Run("count each facet without its own filter", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(searchCategoryFacetQuery).
+			WithArgs("dimoo:*").
+			WillReturnRows(sqlmock.NewRows([]string{"value", "label", "count"}).AddRow("3", "Figures", 4).AddRow("4", "Plush", 1))
+		mock.ExpectQuery(searchArtistFacetQuery).
+			WithArgs("dimoo:*", 3).
+			WillReturnRows(sqlmock.NewRows([]string{"value", "label", "count"}).AddRow("4", "Ayan", 3))
+		mock.ExpectQuery(searchPriceFacetQuery).
+			WithArgs("under-50", 0, 5000, "50-100", 5000, 10000, "100-250", 10000, 25000, "250-500", 25000, 50000, "500-plus", 50000, nil,
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "dimoo:*", 3).
+			WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).AddRow("50-100", 4))
+		mock.ExpectQuery(searchAvailabilityFacetQuery).
+			WithArgs("in_stock", "sold_out", "dimoo:*", 3).
+			WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).AddRow("in_stock", 4).AddRow("sold_out", 2))
+		mock.ExpectQuery(searchEditionFacetQuery).
+			WithArgs("dimoo:*", 3).
+			WillReturnRows(sqlmock.NewRows([]string{"value", "count"}).AddRow("regular", 3).AddRow("chase", 1))
+
+		got, err := repo.SearchFacets(&entities.SearchQuery{
+			Terms:   []string{"dimoo"},
+			Filters: entities.SearchFilters{CategoryIDs: []uint{3}, Availability: entities.AvailabilityInStock},
+		})
+
+		want := &entities.SearchFacets{
+			Categories:   []entities.FacetCount{{Value: "3", Label: "Figures", Count: 4}, {Value: "4", Label: "Plush", Count: 1}},
+			Artists:      []entities.FacetCount{{Value: "4", Label: "Ayan", Count: 3}},
+			Prices:       []entities.FacetCount{{Value: "50-100", Count: 4}},
+			Availability: []entities.FacetCount{{Value: "in_stock", Count: 4}, {Value: "sold_out", Count: 2}},
+			Editions:     []entities.FacetCount{{Value: "regular", Count: 3}, {Value: "chase", Count: 1}},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSearchFilters_gormRepo(t *testing.T) {
+	t.Run("price buckets are ORed together", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(searchFiltersQuery).
+			WithArgs("dimoo:*", 3, 4, 5, "50-100", 5000, 10000, "500-plus", 50000, nil, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "chase").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		_, total, err := repo.SearchProducts(&entities.SearchQuery{
+			Terms: []string{"dimoo"},
+			Filters: entities.SearchFilters{
+				CategoryIDs:  []uint{3},
+				ArtistIDs:    []uint{4, 5},
+				PriceBuckets: []string{"500-plus", "50-100"},
+				Editions:     []string{"chase"},
+			},
+			Limit: 20,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("sold out filter keeps only products without stock", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(`SELECT count(*) FROM "products" WHERE (products.search_vector @@ to_tsquery('english', $1) AND products.active AND (products.stock > 0 OR products.show_when_sold_out)) AND products.stock = 0 AND "products"."deleted_at" IS NULL`).
+			WithArgs("night:*").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		_, total, err := repo.SearchProducts(&entities.SearchQuery{
+			Terms:   []string{"night"},
+			Filters: entities.SearchFilters{Availability: entities.AvailabilitySoldOut},
+			Limit:   20,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAutocomplete_gormRepo(t *testing.T) {
+	t.Run("autocomplete across products, series and artists", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(autocompleteNamesQuery).
+			WithArgs("labubo", "labubo", "labubo", "labubo", "labubo", "labubo", 8).
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "id", "name", "score"}).
+				AddRow("series", 5, "Labubu The Monsters", 0.71).
+				AddRow("product", 12, "Labubu Sea Salt Coconut", 0.71))
+
+		got, err := repo.Autocomplete("labubo", 8)
+
+		want := []entities.Suggestion{
+			{Kind: "series", ID: 5, Name: "Labubu The Monsters", Score: 0.71},
+			{Kind: "product", ID: 12, Name: "Labubu Sea Salt Coconut", Score: 0.71},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestClosestWord_gormRepo(t *testing.T) {
+	t.Run("closest word found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(closestCatalogueWordQuery).
+			WithArgs("labubo", "labubo").
+			WillReturnRows(sqlmock.NewRows([]string{"word"}).AddRow("labubu"))
+
+		got, err := repo.ClosestWord("labubo")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "labubu", got)
+	})
+
+	t.Run("nothing close enough", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductSearchRepository(gormDB)
+
+		mock.ExpectQuery(closestCatalogueWordQuery).
+			WithArgs("xyz", "xyz").
+			WillReturnRows(sqlmock.NewRows([]string{"word"}))
+
+		got, err := repo.ClosestWord("xyz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
+
+func TestSearchProducts_memoryRepo(t *testing.T) {
+	products := []entities.Product{
+		{Model: gorm.Model{ID: 1}, Name: "Skullpanda The Sound", Description: "A skull inspired figure listening to the sound of the night.", Stock: 4, Active: true},
+		{Model: gorm.Model{ID: 2}, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy & artistic design.", Stock: 2, Active: true},
+		{Model: gorm.Model{ID: 3}, Name: "Hirono Night Walk", Description: "Hirono on a late walk.", Stock: 0, ShowWhenSoldOut: true, Active: true},
+		{Model: gorm.Model{ID: 4}, Name: "Dimoo Night Retired", Stock: 5, Active: false},
+		{Model: gorm.Model{ID: 5}, Name: "Labubu Night Out", Stock: 0, Active: true},
+	}
+	repo := NewMemoryProductSearchRepository(products, nil, nil, nil)
+
+	t.Run("name matches rank above description matches", func(t *testing.T) {
+		got, total, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"night"}, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, uint(2), got[0].Product.ID)
+		assert.Equal(t, uint(3), got[1].Product.ID)
+		assert.Equal(t, uint(1), got[2].Product.ID)
+		assert.Greater(t, got[1].Rank, got[2].Rank)
+	})
+
+	t.Run("every term must match as a prefix", func(t *testing.T) {
+		got, total, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"dim", "star"}, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, uint(2), got[0].Product.ID)
+		assert.Equal(t, "<mark>Dimoo</mark> inspired by Van Gogh&#39;s <mark>&#39;Starry</mark> Night,&#39; featuring a dreamy &amp; artistic design.", got[0].Snippet)
+	})
+
+	t.Run("snippet escapes markup in the description", func(t *testing.T) {
+		repo := NewMemoryProductSearchRepository([]entities.Product{
+			{Model: gorm.Model{ID: 6}, Name: "Crybaby", Description: `Crybaby <img src=x onerror="alert(1)"> tears`, Stock: 1, Active: true},
+		}, nil, nil, nil)
+
+		got, _, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"tears"}, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Crybaby &lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>tears</mark>", got[0].Snippet)
+	})
+
+	t.Run("pages through results", func(t *testing.T) {
+		got, total, err := repo.SearchProducts(&entities.SearchQuery{Terms: []string{"night"}, Limit: 2, Offset: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, got, 1)
+		assert.Equal(t, uint(1), got[0].Product.ID)
+	})
+}
+
+func TestSearchFacets_memoryRepo(t *testing.T) {
+	toys := entities.Category{Model: gorm.Model{ID: 2}, Name: "Toys"}
+	figures := entities.Category{Model: gorm.Model{ID: 3}, Name: "Figures", ParentID: &toys.ID}
+	plush := entities.Category{Model: gorm.Model{ID: 4}, Name: "Plush", ParentID: &toys.ID}
+	ayan := &entities.Artist{Model: gorm.Model{ID: 7}, Name: "Ayan"}
+	saleEnds := time.Now().Add(time.Hour)
+	products := []entities.Product{
+		{Model: gorm.Model{ID: 1}, Name: "Dimoo Starry Night", Price: money.New(6000, "THB"), SalePrice: &money.Money{Amount: 4500, Currency: "THB"}, SaleEndsAt: &saleEnds,
+			Stock: 3, Active: true, Categories: []entities.Category{figures}, Artist: ayan},
+		{Model: gorm.Model{ID: 2}, Name: "Dimoo Night Plush", Price: money.New(8000, "THB"), Stock: 0, ShowWhenSoldOut: true, Active: true, Categories: []entities.Category{plush}, Artist: ayan},
+		{Model: gorm.Model{ID: 3}, Name: "Dimoo Night Figure", Price: money.New(12000, "THB"), Stock: 9, Active: true, Categories: []entities.Category{figures},
+			Variants: []entities.ProductVariant{
+				{Model: gorm.Model{ID: 31}, Edition: "regular", Price: money.New(12000, "THB")},
+				{Model: gorm.Model{ID: 32}, Edition: "chase", Price: money.New(30000, "THB")},
+			}},
+	}
+	repo := NewMemoryProductSearchRepository(products, []entities.Category{toys, figures, plush}, nil, nil)
+
+	t.Run("each facet ignores its own filter", func(t *testing.T) {
+		query := &entities.SearchQuery{
+			Terms:   []string{"dimoo"},
+			Filters: entities.SearchFilters{CategoryIDs: []uint{3}, Availability: entities.AvailabilityInStock},
+		}
+
+		got, err := repo.SearchFacets(query)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.FacetCount{{Value: "3", Label: "Figures", Count: 2}, {Value: "2", Label: "Toys", Count: 2}}, got.Categories)
+		assert.Equal(t, []entities.FacetCount{{Value: "7", Label: "Ayan", Count: 1}}, got.Artists)
+		assert.Equal(t, []entities.FacetCount{{Value: "100-250", Count: 1}, {Value: "250-500", Count: 1}, {Value: "under-50", Count: 1}}, got.Prices)
+		assert.Equal(t, []entities.FacetCount{{Value: "in_stock", Count: 2}}, got.Availability)
+		assert.Equal(t, []entities.FacetCount{{Value: "chase", Count: 1}, {Value: "regular", Count: 1}}, got.Editions)
+
+		hits, total, err := repo.SearchProducts(&entities.SearchQuery{Terms: query.Terms, Filters: query.Filters, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, hits, 2)
+	})
+
+	t.Run("price filter matches sale and variant prices", func(t *testing.T) {
+		hits, total, err := repo.SearchProducts(&entities.SearchQuery{
+			Terms:   []string{"dimoo"},
+			Filters: entities.SearchFilters{PriceBuckets: []string{"under-50", "250-500"}},
+			Limit:   20,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []uint{1, 3}, []uint{hits[0].Product.ID, hits[1].Product.ID})
+	})
+
+	t.Run("sold out products count towards availability", func(t *testing.T) {
+		got, err := repo.SearchFacets(&entities.SearchQuery{
+			Terms:   []string{"night"},
+			Filters: entities.SearchFilters{Availability: entities.AvailabilityInStock},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.FacetCount{{Value: "in_stock", Count: 2}, {Value: "sold_out", Count: 1}}, got.Availability)
+		assert.Equal(t, []entities.FacetCount{{Value: "3", Label: "Figures", Count: 2}, {Value: "2", Label: "Toys", Count: 2}}, got.Categories)
+	})
+}
+
+func TestSuggestions_memoryRepo(t *testing.T) {
+	products := []entities.Product{
+		{Model: gorm.Model{ID: 12}, Name: "Labubu Sea Salt Coconut", Stock: 3, Active: true},
+		{Model: gorm.Model{ID: 13}, Name: "Skullpanda The Sound", Stock: 3, Active: true},
+	}
+	series := []entities.Series{{Model: gorm.Model{ID: 5}, Name: "Labubu The Monsters"}}
+	artists := []entities.Artist{{Model: gorm.Model{ID: 4}, Name: "Kasing Lung"}}
+	repo := NewMemoryProductSearchRepository(products, nil, series, artists)
+
+	t.Run("autocomplete tolerates a typo", func(t *testing.T) {
+		got, err := repo.Autocomplete("labubo", 8)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "Labubu Sea Salt Coconut", got[0].Name)
+		assert.Equal(t, entities.SuggestionKindSeries, got[1].Kind)
+	})
+
+	t.Run("autocomplete matches a partial word", func(t *testing.T) {
+		got, err := repo.Autocomplete("kasi", 8)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.Suggestion{{Kind: entities.SuggestionKindArtist, ID: 4, Name: "Kasing Lung", Score: got[0].Score}}, got)
+	})
+
+	t.Run("closest word corrects a typo", func(t *testing.T) {
+		got, err := repo.ClosestWord("skulpanda")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "skullpanda", got)
+	})
+}
@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpProductVariantHandler struct {
+	usecase usecase.ProductVariantUsecase
+}
+
+func NewProductVariantHandler(usecase usecase.ProductVariantUsecase) *httpProductVariantHandler {
+	return &httpProductVariantHandler{usecase}
+}
+
+func (h *httpProductVariantHandler) CreateVariant(c echo.Context) error {
+	variant := new(entities.ProductVariant)
+
+	if err := request.ContextWrapper(c).Bind(variant); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newVariant, err := h.usecase.CreateVariant(c.Param("id"), variant)
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newVariant)
+}
+
+func (h *httpProductVariantHandler) GetVariants(c echo.Context) error {
+	variants, err := h.usecase.GetVariants(c.Param("id"))
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, variants)
+}
+
+func (h *httpProductVariantHandler) UpdateVariant(c echo.Context) error {
+	variant := new(entities.ProductVariant)
+
+	if err := request.ContextWrapper(c).Bind(variant); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	variantUpdated, err := h.usecase.UpdateVariant(c.Param("id"), c.Param("variant_id"), variant)
+	if err != nil {
+		return productVariantError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, variantUpdated)
+}
+
+func (h *httpProductVariantHandler) DeleteVariant(c echo.Context) error {
+	if err := h.usecase.DeleteVariant(c.Param("id"), c.Param("variant_id")); err != nil {
+		return productVariantError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func productVariantError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "variant not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "unsupported currency", "price must be positive":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "sku already exists", "product is on sale":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,132 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateVariant(t *testing.T) {
+	t.Run("create variant successfully", func(t *testing.T) {
+		mockService := new(MockProductVariantUsecase)
+		handler := &httpProductVariantHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateVariant", "1", mock.AnythingOfType("*entities.ProductVariant")).Return(&entities.ProductVariantResponse{
+			ID: 3, SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Edition: entities.EditionGlow, Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(59000, "THB"), InStock: true,
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"sku":"LBB-BIE-GLOW","name":"Glow in the dark","edition":"glow","attributes":{"finish":"glow"},"price":{"amount":59000,"currency":"THB"},"stock":4}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.CreateVariant(c)
+
+		expectedJSON := `{"id":3,"sku":"LBB-BIE-GLOW","name":"Glow in the dark","edition":"glow","attributes":{"finish":"glow"},"price":{"amount":59000,"currency":"THB"},"in_stock":true}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("create variant given missing sku", func(t *testing.T) {
+		mockService := new(MockProductVariantUsecase)
+		handler := &httpProductVariantHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Glow in the dark","price":{"amount":59000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.CreateVariant(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "CreateVariant", mock.Anything, mock.Anything)
+	})
+
+	t.Run("create variant given duplicate sku", func(t *testing.T) {
+		mockService := new(MockProductVariantUsecase)
+		handler := &httpProductVariantHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateVariant", "1", mock.AnythingOfType("*entities.ProductVariant")).Return((*entities.ProductVariantResponse)(nil), errors.New("sku already exists"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"sku":"LBB-BIE-GLOW","name":"Glow in the dark","price":{"amount":59000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.CreateVariant(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestDeleteVariant(t *testing.T) {
+	t.Run("delete variant given variant not found", func(t *testing.T) {
+		mockService := new(MockProductVariantUsecase)
+		handler := &httpProductVariantHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteVariant", "1", "30").Return(errors.New("variant not found"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), response)
+		c.SetParamNames("id", "variant_id")
+		c.SetParamValues("1", "30")
+
+		err := handler.DeleteVariant(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockProductVariantUsecase struct {
+	mock.Mock
+}
+
+func (m *MockProductVariantUsecase) CreateVariant(productID string, variant *entities.ProductVariant) (*entities.ProductVariantResponse, error) {
+	args := m.Called(productID, variant)
+	return args.Get(0).(*entities.ProductVariantResponse), args.Error(1)
+}
+
+func (m *MockProductVariantUsecase) GetVariants(productID string) ([]entities.ProductVariantResponse, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.ProductVariantResponse), args.Error(1)
+}
+
+func (m *MockProductVariantUsecase) UpdateVariant(productID, variantID string, variant *entities.ProductVariant) (*entities.ProductVariantResponse, error) {
+	args := m.Called(productID, variantID, variant)
+	return args.Get(0).(*entities.ProductVariantResponse), args.Error(1)
+}
+
+func (m *MockProductVariantUsecase) DeleteVariant(productID, variantID string) error {
+	args := m.Called(productID, variantID)
+	return args.Error(0)
+}
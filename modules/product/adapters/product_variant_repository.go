@@ -0,0 +1,98 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+)
+
+type gormProductVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) usecase.ProductVariantRepository {
+	return &gormProductVariantRepository{db}
+}
+
+func orderProductVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("sort_order, id")
+}
+
+func (r *gormProductVariantRepository) InsertVariant(variant *entities.ProductVariant) (*entities.ProductVariant, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(variant).Error; err != nil {
+			return err
+		}
+
+		if err := recordInitialStock(tx, variant.ProductID, &variant.ID, variant.Stock); err != nil {
+			return err
+		}
+
+		return syncProductStock(tx, variant.ProductID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (r *gormProductVariantRepository) GetVariants(productID uint) ([]entities.ProductVariant, error) {
+	var variants []entities.ProductVariant
+
+	if err := r.db.Where("product_id = ?", productID).Scopes(orderProductVariants).Find(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (r *gormProductVariantRepository) GetVariantById(productID uint, variantID string) (*entities.ProductVariant, error) {
+	variant := new(entities.ProductVariant)
+
+	if err := r.db.Where("id = ? AND product_id = ?", variantID, productID).First(variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("variant not found")
+		}
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (r *gormProductVariantRepository) GetVariantBySKU(sku string) (*entities.ProductVariant, error) {
+	variant := new(entities.ProductVariant)
+
+	if err := r.db.Where("sku = ?", sku).First(variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("variant not found")
+		}
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant leaves stock alone; it only changes through the inventory
+// ledger.
+func (r *gormProductVariantRepository) UpdateVariant(variant *entities.ProductVariant) (*entities.ProductVariant, error) {
+	if err := r.db.Model(variant).
+		Select("sku", "name", "edition", "attributes", "price_amount", "price_currency", "sort_order").
+		Updates(variant).Error; err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (r *gormProductVariantRepository) DeleteVariant(variant *entities.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(variant).Error; err != nil {
+			return err
+		}
+
+		return syncProductStock(tx, variant.ProductID)
+	})
+}
@@ -0,0 +1,142 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	insertVariantQuery   = `INSERT INTO "product_variants" ("created_at","updated_at","deleted_at","product_id","sku","name","edition","attributes","price_amount","price_currency","stock","sort_order") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING "id"`
+	getVariantBySKUQuery = `SELECT * FROM "product_variants" WHERE sku = $1 AND "product_variants"."deleted_at" IS NULL ORDER BY "product_variants"."id" LIMIT $2`
+	deleteVariantQuery   = `UPDATE "product_variants" SET "deleted_at"=$1 WHERE "product_variants"."id" = $2 AND "product_variants"."deleted_at" IS NULL`
+	getVariantsQuery     = `SELECT * FROM "product_variants" WHERE product_id = $1 AND "product_variants"."deleted_at" IS NULL ORDER BY sort_order, id`
+	updateVariantQuery   = `UPDATE "product_variants" SET "updated_at"=$1,"sku"=$2,"name"=$3,"edition"=$4,"attributes"=$5,"price_amount"=$6,"price_currency"=$7,"sort_order"=$8 WHERE "product_variants"."deleted_at" IS NULL AND "id" = $9`
+)
+
+func TestInsertVariant_gormRepo(t *testing.T) {
+	t.Run("insert variant and resync product stock", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductVariantRepository(gormDB)
+
+		variant := &entities.ProductVariant{ProductID: 1, SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Edition: entities.EditionGlow, Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(59000, "THB"), Stock: 4}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertVariantQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 1, "LBB-BIE-GLOW", "Glow in the dark", "glow", `{"finish":"glow"}`, 59000, "THB", 4, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, 3, nil, "restock", 4, 4, nil, "", "initial stock").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectQuery(sumVariantStockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(10))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.InsertVariant(variant)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), got.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateVariant_gormRepo(t *testing.T) {
+	t.Run("update variant leaves stock to the ledger", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductVariantRepository(gormDB)
+
+		variant := &entities.ProductVariant{Model: gorm.Model{ID: 3}, ProductID: 1, SKU: "LBB-BIE-GLOW", Name: "Glow", Edition: entities.EditionGlow, Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(62000, "THB"), Stock: 4}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateVariantQuery).
+			WithArgs(sqlmock.AnyArg(), "LBB-BIE-GLOW", "Glow", "glow", `{"finish":"glow"}`, 62000, "THB", 0, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, err := repo.UpdateVariant(variant)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetVariants_gormRepo(t *testing.T) {
+	t.Run("get variants in display order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductVariantRepository(gormDB)
+
+		rows := sqlmock.NewRows([]string{"id", "product_id", "sku", "name", "attributes", "price_amount", "price_currency", "stock"}).
+			AddRow(2, 1, "LBB-BIE-REG", "Regular", []byte(`{}`), 39000, "THB", 6).
+			AddRow(3, 1, "LBB-BIE-GLOW", "Glow in the dark", []byte(`{"finish":"glow"}`), 59000, "THB", 4)
+		mock.ExpectQuery(getVariantsQuery).WithArgs(1).WillReturnRows(rows)
+
+		got, err := repo.GetVariants(1)
+
+		want := []entities.ProductVariant{
+			{Model: gorm.Model{ID: 2}, ProductID: 1, SKU: "LBB-BIE-REG", Name: "Regular", Attributes: entities.VariantAttributes{}, Price: money.New(39000, "THB"), Stock: 6},
+			{Model: gorm.Model{ID: 3}, ProductID: 1, SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(59000, "THB"), Stock: 4},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestGetVariantBySKU_gormRepo(t *testing.T) {
+	t.Run("get variant given unknown sku", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductVariantRepository(gormDB)
+
+		mock.ExpectQuery(getVariantBySKUQuery).WithArgs("NOPE", 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetVariantBySKU("NOPE")
+
+		assert.EqualError(t, err, "variant not found")
+	})
+}
+
+func TestDeleteVariant_gormRepo(t *testing.T) {
+	t.Run("delete last variant marks product sold out", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductVariantRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteVariantQuery).WithArgs(sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(sumVariantStockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(0))
+		mock.ExpectExec(syncProductStockQuery).
+			WithArgs(0, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteVariant(&entities.ProductVariant{Model: gorm.Model{ID: 3}, ProductID: 1})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
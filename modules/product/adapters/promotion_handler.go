@@ -0,0 +1,149 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpPromotionHandler struct {
+	usecase  usecase.PromotionUsecase
+	currency usecase.CurrencyUsecase
+}
+
+func NewPromotionHandler(usecase usecase.PromotionUsecase, currency usecase.CurrencyUsecase) *httpPromotionHandler {
+	return &httpPromotionHandler{usecase, currency}
+}
+
+func (h *httpPromotionHandler) CreateCoupon(c echo.Context) error {
+	couponRequest := new(entities.CouponRequest)
+	if err := request.ContextWrapper(c).Bind(couponRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	coupon, err := h.usecase.CreateCoupon(couponRequest)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, coupon)
+}
+
+func (h *httpPromotionHandler) GetCoupons(c echo.Context) error {
+	coupons, err := h.usecase.GetCoupons()
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, coupons)
+}
+
+func (h *httpPromotionHandler) UpdateCoupon(c echo.Context) error {
+	couponRequest := new(entities.CouponRequest)
+	if err := request.ContextWrapper(c).Bind(couponRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	coupon, err := h.usecase.UpdateCoupon(c.Param("id"), couponRequest)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, coupon)
+}
+
+func (h *httpPromotionHandler) DeleteCoupon(c echo.Context) error {
+	if err := h.usecase.DeleteCoupon(c.Param("id")); err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpPromotionHandler) CreateCartPromotion(c echo.Context) error {
+	promotionRequest := new(entities.CartPromotionRequest)
+	if err := request.ContextWrapper(c).Bind(promotionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	promotion, err := h.usecase.CreateCartPromotion(promotionRequest)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, promotion)
+}
+
+func (h *httpPromotionHandler) GetCartPromotions(c echo.Context) error {
+	promotions, err := h.usecase.GetCartPromotions()
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, promotions)
+}
+
+func (h *httpPromotionHandler) DeleteCartPromotion(c echo.Context) error {
+	if err := h.usecase.DeleteCartPromotion(c.Param("id")); err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpPromotionHandler) PriceCart(c echo.Context) error {
+	pricingRequest := new(entities.CartPricingRequest)
+	if err := request.ContextWrapper(c).Bind(pricingRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	pricingRequest.UserID, _ = c.Get(ContextUserIDKey).(uint)
+
+	pricing, err := h.usecase.PriceCart(pricingRequest)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	if err := localizeCart(c, h.currency, pricing); err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, pricing)
+}
+
+func (h *httpPromotionHandler) RedeemCoupons(c echo.Context) error {
+	redeemRequest := new(entities.CouponRedeemRequest)
+	if err := request.ContextWrapper(c).Bind(redeemRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	redeemRequest.UserID, _ = c.Get(ContextUserIDKey).(uint)
+
+	pricing, err := h.usecase.RedeemCoupons(redeemRequest)
+	if err != nil {
+		return promotionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, pricing)
+}
+
+func promotionError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "coupon not found", "promotion not found", "product not found", "variant not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "invalid coupon code", "invalid discount", "unsupported currency", "coupon must end after it starts",
+		"invalid promotion", "price must be positive", "promotion must end after it starts",
+		"variant required", "coupon not active", "coupon expired", "coupon not applicable",
+		"minimum spend not met", "coupons cannot be combined":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "sign in required":
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: err.Error()})
+	case "coupon code already exists", "coupon usage limit reached", "product is not on sale":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
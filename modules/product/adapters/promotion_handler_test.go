@@ -0,0 +1,338 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateCoupon(t *testing.T) {
+	t.Run("create coupon successfully", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateCoupon", &entities.CouponRequest{Code: "SUMMER-10", Type: "percentage", PercentOff: 10, Stackable: true}).
+			Return(&entities.Coupon{Model: gorm.Model{ID: 1}, Code: "SUMMER-10", Type: "percentage", PercentOff: 10, Stackable: true, Active: true}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"SUMMER-10","type":"percentage","percent_off":10,"stackable":true}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCoupon(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"code":"SUMMER-10","description":"","type":"percentage","percent_off":10`)
+	})
+
+	t.Run("create coupon given unknown type", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"SUMMER-10","type":"buy_one_get_one"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCoupon(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "CreateCoupon", mock.Anything)
+	})
+
+	t.Run("create coupon given code in use", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateCoupon", mock.Anything).Return((*entities.Coupon)(nil), errors.New("coupon code already exists"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"SUMMER-10","type":"free_shipping"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCoupon(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestPriceCart(t *testing.T) {
+	t.Run("price cart for a signed-in user", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("PriceCart", mock.MatchedBy(func(r *entities.CartPricingRequest) bool {
+			return r.UserID == 12 && r.Codes[0] == "WELCOME" && r.Items[0].ProductID == 1
+		})).Return(&entities.CartPricing{
+			Promotions: []entities.AppliedPromotion{{PromotionID: 1, Name: "Buy 3 get 1", Type: "buy_x_get_y", Amount: money.New(39000, "THB")}},
+			Total:      money.New(47500, "THB"),
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"codes":["WELCOME"],"items":[{"product_id":1,"quantity":1}]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.PriceCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"total":{"amount":47500,"currency":"THB"}`)
+		assert.Contains(t, response.Body.String(), `"promotions":[{"promotion_id":1,"name":"Buy 3 get 1","type":"buy_x_get_y","amount":{"amount":39000,"currency":"THB"}}]`)
+	})
+
+	t.Run("price cart in the customer's currency", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		mockCurrency := new(MockCurrencyUsecase)
+		handler := &httpPromotionHandler{usecase: mockService, currency: mockCurrency}
+
+		e := echo.New()
+		defer e.Close()
+
+		pricing := &entities.CartPricing{Total: money.New(47500, "THB")}
+		mockService.On("PriceCart", mock.Anything).Return(pricing, nil)
+		mockCurrency.On("LocalizeCart", "USD", pricing).Run(func(args mock.Arguments) {
+			pricing.Display = &entities.CartDisplay{Total: money.New(1299, "USD"), Rate: 27350}
+		}).Return(nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/?currency=USD", strings.NewReader(`{"items":[{"product_id":1,"quantity":1}]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.PriceCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"total":{"amount":1299,"currency":"USD"},"rate":"0.027350"`)
+	})
+
+	t.Run("price cart given empty cart", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"codes":["WELCOME"],"items":[]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.PriceCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("price cart given minimum spend not met", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("PriceCart", mock.Anything).Return((*entities.CartPricing)(nil), errors.New("minimum spend not met"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"codes":["BIG"],"items":[{"product_id":1,"quantity":1}]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.PriceCart(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "minimum spend not met")
+	})
+}
+
+func TestRedeemCoupons(t *testing.T) {
+	t.Run("redeem coupons given usage limit reached", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("RedeemCoupons", mock.MatchedBy(func(r *entities.CouponRedeemRequest) bool {
+			return r.ReferenceID == "order-42" && r.Codes[0] == "SHIP"
+		})).Return((*entities.CartPricing)(nil), errors.New("coupon usage limit reached"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"order-42","codes":["SHIP"],"items":[{"product_id":1,"quantity":1}]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.RedeemCoupons(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("redeem coupons without reference", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"codes":["SHIP"],"items":[{"product_id":1,"quantity":1}]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.RedeemCoupons(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "RedeemCoupons", mock.Anything)
+	})
+}
+
+func TestCreateCartPromotion(t *testing.T) {
+	t.Run("create buy x get y promotion successfully", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateCartPromotion", &entities.CartPromotionRequest{Name: "Buy 3 get 1", Type: "buy_x_get_y", BuyQuantity: 3, FreeQuantity: 1, ProductType: "blind_box"}).
+			Return(&entities.CartPromotion{Model: gorm.Model{ID: 1}, Name: "Buy 3 get 1", Type: "buy_x_get_y", BuyQuantity: 3, FreeQuantity: 1, ProductType: "blind_box", Active: true}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Buy 3 get 1","type":"buy_x_get_y","buy_quantity":3,"free_quantity":1,"product_type":"blind_box"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCartPromotion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"buy_quantity":3,"free_quantity":1,"product_type":"blind_box"`)
+	})
+
+	t.Run("create bundle given invalid products", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateCartPromotion", mock.Anything).Return((*entities.CartPromotion)(nil), errors.New("invalid promotion"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Pair","type":"bundle","bundle_product_ids":[1,1],"bundle_price":{"amount":80000}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCartPromotion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestDeleteCartPromotion(t *testing.T) {
+	t.Run("delete promotion given not found", func(t *testing.T) {
+		mockService := new(MockPromotionUsecase)
+		handler := &httpPromotionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteCartPromotion", "9").Return(errors.New("promotion not found"))
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("9")
+
+		err := handler.DeleteCartPromotion(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockPromotionUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPromotionUsecase) CreateCoupon(request *entities.CouponRequest) (*entities.Coupon, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) GetCoupons() ([]entities.Coupon, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) UpdateCoupon(id string, request *entities.CouponRequest) (*entities.Coupon, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) DeleteCoupon(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionUsecase) PriceCart(request *entities.CartPricingRequest) (*entities.CartPricing, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.CartPricing), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) RedeemCoupons(request *entities.CouponRedeemRequest) (*entities.CartPricing, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.CartPricing), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) CreateCartPromotion(request *entities.CartPromotionRequest) (*entities.CartPromotion, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.CartPromotion), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) GetCartPromotions() ([]entities.CartPromotion, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.CartPromotion), args.Error(1)
+}
+
+func (m *MockPromotionUsecase) DeleteCartPromotion(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionUsecase) ReleaseCoupons(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
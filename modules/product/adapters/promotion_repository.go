@@ -0,0 +1,233 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormPromotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) usecase.PromotionRepository {
+	return &gormPromotionRepository{db}
+}
+
+func (r *gormPromotionRepository) InsertCoupon(coupon *entities.Coupon) (*entities.Coupon, error) {
+	if err := r.db.Create(coupon).Error; err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (r *gormPromotionRepository) GetCoupons() ([]entities.Coupon, error) {
+	var coupons []entities.Coupon
+
+	if err := r.db.Order("id").Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+
+	return coupons, nil
+}
+
+func (r *gormPromotionRepository) GetCouponById(id string) (*entities.Coupon, error) {
+	coupon := new(entities.Coupon)
+
+	if err := r.db.First(coupon, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("coupon not found")
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (r *gormPromotionRepository) GetCouponByCode(code string) (*entities.Coupon, error) {
+	coupon := new(entities.Coupon)
+
+	if err := r.db.Where("code = ?", code).First(coupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("coupon not found")
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// UpdateCoupon writes the coupon's terms. Redemptions is left to
+// RedeemCoupons.
+func (r *gormPromotionRepository) UpdateCoupon(coupon *entities.Coupon) (*entities.Coupon, error) {
+	if err := r.db.Model(coupon).
+		Select("code", "description", "type", "percent_off", "amount_off_amount", "amount_off_currency",
+			"min_spend_amount", "min_spend_currency", "product_ids", "category_ids", "artist_ids",
+			"usage_limit", "per_user_limit", "starts_at", "ends_at", "stackable", "active").
+		Updates(coupon).Error; err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (r *gormPromotionRepository) DeleteCoupon(id string) error {
+	result := r.db.Delete(&entities.Coupon{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("coupon not found")
+	}
+
+	return nil
+}
+
+func (r *gormPromotionRepository) CountRedemptions(couponID, userID uint) (int64, error) {
+	var count int64
+
+	if err := r.db.Model(&entities.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", couponID, userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *gormPromotionRepository) InsertCartPromotion(promotion *entities.CartPromotion) (*entities.CartPromotion, error) {
+	if err := r.db.Create(promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// GetCartPromotions returns promotions in the order they are applied.
+func (r *gormPromotionRepository) GetCartPromotions(activeOnly bool) ([]entities.CartPromotion, error) {
+	var promotions []entities.CartPromotion
+
+	query := r.db.Order("priority, id")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	if err := query.Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+func (r *gormPromotionRepository) DeleteCartPromotion(id string) error {
+	result := r.db.Delete(&entities.CartPromotion{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("promotion not found")
+	}
+
+	return nil
+}
+
+// ReleaseCoupons deletes an order's coupon redemptions and gives their uses
+// back. Each coupon is locked first, as RedeemCoupons locks it.
+func (r *gormPromotionRepository) ReleaseCoupons(referenceID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var redemptions []entities.CouponRedemption
+		if err := tx.Where("reference_id = ?", referenceID).Find(&redemptions).Error; err != nil {
+			return err
+		}
+
+		for _, redemption := range redemptions {
+			coupon := new(entities.Coupon)
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Unscoped().First(coupon, "id = ?", redemption.CouponID).Error; err != nil {
+				return err
+			}
+
+			result := tx.Unscoped().Where("coupon_id = ? AND reference_id = ?", coupon.ID, referenceID).Delete(&entities.CouponRedemption{})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			if err := tx.Model(coupon).Unscoped().
+				Update("redemptions", gorm.Expr("redemptions - 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RedeemCoupons records an order's use of its coupons in one transaction.
+// Each coupon is locked while its usage limits are checked, and a coupon the
+// order has already redeemed is skipped.
+func (r *gormPromotionRepository) RedeemCoupons(redemptions []entities.CouponRedemption) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range redemptions {
+			redemption := &redemptions[i]
+
+			coupon := new(entities.Coupon)
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(coupon, "id = ?", redemption.CouponID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("coupon not found")
+				}
+				return errors.New("failed to retrieve coupon")
+			}
+
+			var redeemed int64
+			if err := tx.Model(&entities.CouponRedemption{}).
+				Where("coupon_id = ? AND reference_id = ?", coupon.ID, redemption.ReferenceID).
+				Count(&redeemed).Error; err != nil {
+				return errors.New("failed to retrieve redemptions")
+			}
+
+			if redeemed > 0 {
+				continue
+			}
+
+			if coupon.UsageLimit > 0 && coupon.Redemptions >= coupon.UsageLimit {
+				return errors.New("coupon usage limit reached")
+			}
+
+			if coupon.PerUserLimit > 0 {
+				if redemption.UserID == nil {
+					return errors.New("sign in required")
+				}
+
+				var used int64
+				if err := tx.Model(&entities.CouponRedemption{}).
+					Where("coupon_id = ? AND user_id = ?", coupon.ID, *redemption.UserID).
+					Count(&used).Error; err != nil {
+					return errors.New("failed to retrieve redemptions")
+				}
+
+				if used >= int64(coupon.PerUserLimit) {
+					return errors.New("coupon usage limit reached")
+				}
+			}
+
+			if err := tx.Create(redemption).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(coupon).
+				Update("redemptions", gorm.Expr("redemptions + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,200 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getCouponByCodeQuery         = `SELECT * FROM "coupons" WHERE code = $1 AND "coupons"."deleted_at" IS NULL ORDER BY "coupons"."id" LIMIT $2`
+	lockCouponQuery              = `SELECT * FROM "coupons" WHERE id = $1 AND "coupons"."deleted_at" IS NULL ORDER BY "coupons"."id" LIMIT $2 FOR UPDATE`
+	countOrderRedemptionsQuery   = `SELECT count(*) FROM "coupon_redemptions" WHERE (coupon_id = $1 AND reference_id = $2) AND "coupon_redemptions"."deleted_at" IS NULL`
+	countUserRedemptionsQuery    = `SELECT count(*) FROM "coupon_redemptions" WHERE (coupon_id = $1 AND user_id = $2) AND "coupon_redemptions"."deleted_at" IS NULL`
+	insertRedemptionQuery        = `INSERT INTO "coupon_redemptions" ("created_at","updated_at","deleted_at","coupon_id","reference_id","user_id","discount_amount","discount_currency") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`
+	getActiveCartPromotionsQuery = `SELECT * FROM "cart_promotions" WHERE active = $1 AND "cart_promotions"."deleted_at" IS NULL ORDER BY priority, id`
+	deleteCartPromotionQuery     = `UPDATE "cart_promotions" SET "deleted_at"=$1 WHERE id = $2 AND "cart_promotions"."deleted_at" IS NULL`
+	getOrderRedemptionsQuery     = `SELECT * FROM "coupon_redemptions" WHERE reference_id = $1 AND "coupon_redemptions"."deleted_at" IS NULL`
+	lockReleasedCouponQuery      = `SELECT * FROM "coupons" WHERE id = $1 ORDER BY "coupons"."id" LIMIT $2 FOR UPDATE`
+	deleteRedemptionQuery        = `DELETE FROM "coupon_redemptions" WHERE coupon_id = $1 AND reference_id = $2`
+	decrementRedemptionsQuery    = `UPDATE "coupons" SET "redemptions"=redemptions - 1,"updated_at"=$1 WHERE "id" = $2`
+	incrementRedemptionsQuery    = `UPDATE "coupons" SET "redemptions"=redemptions + 1,"updated_at"=$1 WHERE "coupons"."deleted_at" IS NULL AND "id" = $2`
+)
+
+func TestGetCouponByCode_gormRepo(t *testing.T) {
+	t.Run("get coupon by code given not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectQuery(getCouponByCodeQuery).
+			WithArgs("SUMMER-10", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.GetCouponByCode("SUMMER-10")
+
+		assert.EqualError(t, err, "coupon not found")
+	})
+}
+
+func TestReleaseCoupons_gormRepo(t *testing.T) {
+	t.Run("release an order's coupons", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getOrderRedemptionsQuery).
+			WithArgs("order-42").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "coupon_id", "reference_id"}).AddRow(1, 5, "order-42"))
+		mock.ExpectQuery(lockReleasedCouponQuery).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "redemptions"}).AddRow(5, 3))
+		mock.ExpectExec(deleteRedemptionQuery).
+			WithArgs(5, "order-42").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(decrementRedemptionsQuery).
+			WithArgs(sqlmock.AnyArg(), 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.ReleaseCoupons("order-42")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedeemCoupons_gormRepo(t *testing.T) {
+	userID := uint(12)
+	redemption := func() []entities.CouponRedemption {
+		return []entities.CouponRedemption{{CouponID: 5, ReferenceID: "order-42", UserID: &userID, Discount: money.New(2500, "THB")}}
+	}
+
+	t.Run("redeem coupon successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockCouponQuery).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "usage_limit", "per_user_limit", "redemptions"}).AddRow(5, 100, 1, 99))
+		mock.ExpectQuery(countOrderRedemptionsQuery).
+			WithArgs(5, "order-42").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(countUserRedemptionsQuery).
+			WithArgs(5, 12).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(insertRedemptionQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 5, "order-42", 12, 2500, "THB").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(incrementRedemptionsQuery).
+			WithArgs(sqlmock.AnyArg(), 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.RedeemCoupons(redemption())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("redeem coupon already redeemed by the order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockCouponQuery).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "usage_limit", "redemptions"}).AddRow(5, 100, 100))
+		mock.ExpectQuery(countOrderRedemptionsQuery).
+			WithArgs(5, "order-42").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectCommit()
+
+		err := repo.RedeemCoupons(redemption())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("redeem coupon given usage limit reached", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockCouponQuery).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "usage_limit", "redemptions"}).AddRow(5, 100, 100))
+		mock.ExpectQuery(countOrderRedemptionsQuery).
+			WithArgs(5, "order-42").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		err := repo.RedeemCoupons(redemption())
+
+		assert.EqualError(t, err, "coupon usage limit reached")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetCartPromotions_gormRepo(t *testing.T) {
+	t.Run("get active promotions in priority order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectQuery(getActiveCartPromotionsQuery).
+			WithArgs(true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "type", "priority", "bundle_product_ids", "bundle_price_amount", "bundle_price_currency"}).
+				AddRow(2, "Starter set", "bundle", 1, "[1,3]", 80000, "THB").
+				AddRow(1, "Buy 3 get 1", "buy_x_get_y", 2, nil, nil, nil))
+
+		got, err := repo.GetCartPromotions(true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{1, 3}, got[0].BundleProductIDs)
+		assert.Equal(t, money.New(80000, "THB"), *got[0].BundlePrice)
+		assert.Nil(t, got[1].BundlePrice)
+	})
+}
+
+func TestDeleteCartPromotion_gormRepo(t *testing.T) {
+	t.Run("delete promotion given not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewPromotionRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteCartPromotionQuery).
+			WithArgs(sqlmock.AnyArg(), "9").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.DeleteCartPromotion("9")
+
+		assert.EqualError(t, err, "promotion not found")
+	})
+}
@@ -0,0 +1,144 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+// AdmissionTokenHeader carries the admission token on reservation, cart and
+// checkout requests for products behind a waiting room.
+const AdmissionTokenHeader = "X-Admission-Token"
+
+const queueStreamInterval = 2 * time.Second
+
+type httpQueueHandler struct {
+	usecase        usecase.QueueUsecase
+	streamInterval time.Duration
+}
+
+func NewQueueHandler(usecase usecase.QueueUsecase) *httpQueueHandler {
+	return &httpQueueHandler{usecase, queueStreamInterval}
+}
+
+func (h *httpQueueHandler) ConfigureWaitingRoom(c echo.Context) error {
+	roomRequest := new(entities.WaitingRoomRequest)
+	if err := request.ContextWrapper(c).Bind(roomRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	room, err := h.usecase.ConfigureWaitingRoom(c.Param("id"), roomRequest)
+	if err != nil {
+		return queueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, room)
+}
+
+func (h *httpQueueHandler) JoinQueue(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	status, err := h.usecase.JoinQueue(c.Param("id"), userID)
+	if err != nil {
+		return queueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+func (h *httpQueueHandler) GetQueueStatus(c echo.Context) error {
+	status, err := h.usecase.GetQueueStatus(c.Param("id"), c.QueryParam("token"))
+	if err != nil {
+		return queueError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// StreamQueueStatus sends the ticket's queue status as server-sent events
+// until it is admitted or the client goes away.
+func (h *httpQueueHandler) StreamQueueStatus(c echo.Context) error {
+	productID, token := c.Param("id"), c.QueryParam("token")
+
+	status, err := h.usecase.GetQueueStatus(productID, token)
+	if err != nil {
+		return queueError(c, err)
+	}
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, "text/event-stream")
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set(echo.HeaderConnection, "keep-alive")
+	response.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(h.streamInterval)
+	defer ticker.Stop()
+
+	for {
+		data, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(response, "event: status\ndata: %s\n\n", data); err != nil {
+			return nil
+		}
+		response.Flush()
+
+		if status.Status == entities.QueueTicketAdmitted {
+			return nil
+		}
+
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if status, err = h.usecase.GetQueueStatus(productID, token); err != nil {
+			log.Printf("failed to stream queue status: %v", err)
+			return nil
+		}
+	}
+}
+
+// RequireAdmission guards routes for the product named by the :id path
+// parameter, turning away requests without a valid admission token while the
+// product has an active waiting room. Routes that name their products in the
+// body, such as reservations, check admission themselves.
+func (h *httpQueueHandler) RequireAdmission(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _ := c.Get(ContextUserIDKey).(uint)
+
+		if err := h.usecase.CheckAdmission(c.Param("id"), userID, c.Request().Header.Get(AdmissionTokenHeader)); err != nil {
+			return queueError(c, err)
+		}
+
+		return next(c)
+	}
+}
+
+func queueError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "waiting room not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "invalid queue ticket":
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: err.Error()})
+	case "admission required":
+		return c.JSON(http.StatusForbidden, ErrorResponse{Message: err.Error()})
+	case "waiting room is closed":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
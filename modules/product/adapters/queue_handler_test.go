@@ -0,0 +1,192 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJoinQueue(t *testing.T) {
+	t.Run("join queue successfully", func(t *testing.T) {
+		mockService := new(MockQueueUsecase)
+		handler := &httpQueueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("JoinQueue", "1", uint(12)).Return(&entities.QueueStatus{TicketID: 40, Status: "waiting", Position: 40, Token: "ticket-token"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.JoinQueue(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"ticket_id":40,"status":"waiting","position":40,"token":"ticket-token"}`, response.Body.String())
+	})
+
+	t.Run("join queue given no waiting room", func(t *testing.T) {
+		mockService := new(MockQueueUsecase)
+		handler := &httpQueueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("JoinQueue", "1", uint(12)).Return((*entities.QueueStatus)(nil), errors.New("waiting room not found"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.JoinQueue(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestStreamQueueStatus(t *testing.T) {
+	t.Run("stream status until admitted", func(t *testing.T) {
+		mockService := new(MockQueueUsecase)
+		handler := &httpQueueHandler{usecase: mockService, streamInterval: time.Millisecond}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetQueueStatus", "1", "ticket-token").Return(&entities.QueueStatus{TicketID: 40, Status: "waiting", Position: 2, Token: "ticket-token"}, nil).Once()
+		mockService.On("GetQueueStatus", "1", "ticket-token").Return(&entities.QueueStatus{TicketID: 40, Status: "admitted", Token: "ticket-token", AdmissionToken: "admission-token"}, nil).Once()
+
+		request := httptest.NewRequest(http.MethodGet, "/?token=ticket-token", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.StreamQueueStatus(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "text/event-stream", response.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, 2, strings.Count(response.Body.String(), "event: status\n"))
+		assert.Contains(t, response.Body.String(), `"admission_token":"admission-token"`)
+	})
+
+	t.Run("stream given invalid ticket", func(t *testing.T) {
+		mockService := new(MockQueueUsecase)
+		handler := &httpQueueHandler{usecase: mockService, streamInterval: time.Millisecond}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("GetQueueStatus", "1", "forged").Return((*entities.QueueStatus)(nil), errors.New("invalid queue ticket"))
+
+		request := httptest.NewRequest(http.MethodGet, "/?token=forged", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.StreamQueueStatus(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+}
+
+func TestRequireAdmission(t *testing.T) {
+	t.Run("let admitted checkout through", func(t *testing.T) {
+		mockService := new(MockQueueUsecase)
+		handler := &httpQueueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CheckAdmission", "1", uint(12), "admission-token").Return(nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", nil)
+		request.Header.Set(AdmissionTokenHeader, "admission-token")
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.RequireAdmission(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("turn away checkout without admission", func(t *testing.T) {
+		mockService := new(MockQueueUsecase)
+		handler := &httpQueueHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CheckAdmission", "1", uint(12), "").Return(errors.New("admission required"))
+
+		request := httptest.NewRequest(http.MethodPut, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(12))
+
+		called := false
+		err := handler.RequireAdmission(func(c echo.Context) error {
+			called = true
+			return nil
+		})(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		assert.False(t, called)
+	})
+}
+
+type MockQueueUsecase struct {
+	mock.Mock
+}
+
+func (m *MockQueueUsecase) ConfigureWaitingRoom(productID string, request *entities.WaitingRoomRequest) (*entities.WaitingRoom, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*entities.WaitingRoom), args.Error(1)
+}
+
+func (m *MockQueueUsecase) JoinQueue(productID string, userID uint) (*entities.QueueStatus, error) {
+	args := m.Called(productID, userID)
+	return args.Get(0).(*entities.QueueStatus), args.Error(1)
+}
+
+func (m *MockQueueUsecase) GetQueueStatus(productID string, token string) (*entities.QueueStatus, error) {
+	args := m.Called(productID, token)
+	return args.Get(0).(*entities.QueueStatus), args.Error(1)
+}
+
+func (m *MockQueueUsecase) AdmitNext() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueUsecase) CheckAdmission(productID string, userID uint, token string) error {
+	args := m.Called(productID, userID, token)
+	return args.Error(0)
+}
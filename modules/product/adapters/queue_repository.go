@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormQueueRepository struct {
+	db *gorm.DB
+}
+
+func NewQueueRepository(db *gorm.DB) usecase.QueueRepository {
+	return &gormQueueRepository{db}
+}
+
+func (r *gormQueueRepository) SaveWaitingRoom(room *entities.WaitingRoom) (*entities.WaitingRoom, error) {
+	if err := r.db.Save(room).Error; err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+func (r *gormQueueRepository) GetWaitingRoom(productID uint) (*entities.WaitingRoom, error) {
+	room := &entities.WaitingRoom{}
+
+	if err := r.db.Where("product_id = ?", productID).First(room).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("waiting room not found")
+		}
+		return nil, err
+	}
+
+	return room, nil
+}
+
+func (r *gormQueueRepository) GetActiveWaitingRooms() ([]entities.WaitingRoom, error) {
+	var rooms []entities.WaitingRoom
+
+	if err := r.db.Where("active = ?", true).Order("id").Find(&rooms).Error; err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+// JoinQueue issues the user a ticket at the back of the queue. A user still
+// waiting gets the ticket they hold, so rejoining never loses their place; a
+// ticket that was already admitted, whether or not its admission has expired,
+// is replaced by a new one at the back. Admission tokens are checked by their
+// signature, so one already handed out stays usable until it expires.
+func (r *gormQueueRepository) JoinQueue(productID, userID uint) (*entities.QueueTicket, error) {
+	ticket := &entities.QueueTicket{ProductID: productID, UserID: userID, Status: entities.QueueTicketWaiting}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).Create(ticket).Error; err != nil {
+			return err
+		}
+
+		if ticket.ID != 0 {
+			return nil
+		}
+
+		existing := &entities.QueueTicket{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND user_id = ?", productID, userID).
+			First(existing).Error; err != nil {
+			return err
+		}
+
+		if existing.Status == entities.QueueTicketWaiting {
+			ticket = existing
+			return nil
+		}
+
+		if err := tx.Delete(existing).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(ticket).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+func (r *gormQueueRepository) GetTicket(id uint) (*entities.QueueTicket, error) {
+	ticket := &entities.QueueTicket{}
+
+	if err := r.db.First(ticket, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("queue ticket not found")
+		}
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+// CountAhead counts the tickets still waiting in front of ticket.
+func (r *gormQueueRepository) CountAhead(ticket *entities.QueueTicket) (int64, error) {
+	var ahead int64
+
+	if err := r.db.Model(&entities.QueueTicket{}).
+		Where("product_id = ? AND status = ? AND id < ?", ticket.ProductID, entities.QueueTicketWaiting, ticket.ID).
+		Count(&ahead).Error; err != nil {
+		return 0, err
+	}
+
+	return ahead, nil
+}
+
+// AdmitTickets admits the next limit waiting tickets of a room, oldest first.
+func (r *gormQueueRepository) AdmitTickets(productID uint, limit int, admittedAt, expiresAt time.Time) (int64, error) {
+	next := r.db.Model(&entities.QueueTicket{}).
+		Select("id").
+		Where("product_id = ? AND status = ?", productID, entities.QueueTicketWaiting).
+		Order("id").
+		Limit(limit)
+
+	result := r.db.Model(&entities.QueueTicket{}).
+		Where("id IN (?)", next).
+		Updates(map[string]any{
+			"status":      entities.QueueTicketAdmitted,
+			"admitted_at": admittedAt,
+			"expires_at":  expiresAt,
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,119 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	insertQueueTicketQuery  = `INSERT INTO "queue_tickets" ("created_at","updated_at","product_id","user_id","status","admitted_at","expires_at") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("product_id","user_id") DO NOTHING RETURNING "id"`
+	getUserTicketQuery      = `SELECT * FROM "queue_tickets" WHERE product_id = $1 AND user_id = $2 ORDER BY "queue_tickets"."id" LIMIT $3 FOR UPDATE`
+	reissueQueueTicketQuery = `INSERT INTO "queue_tickets" ("created_at","updated_at","product_id","user_id","status","admitted_at","expires_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`
+	deleteQueueTicketQuery  = `DELETE FROM "queue_tickets" WHERE "queue_tickets"."id" = $1`
+	admitTicketsQuery       = `UPDATE "queue_tickets" SET "admitted_at"=$1,"expires_at"=$2,"status"=$3,"updated_at"=$4 WHERE id IN (SELECT "id" FROM "queue_tickets" WHERE product_id = $5 AND status = $6 ORDER BY id LIMIT $7)`
+)
+
+func TestJoinQueue_gormRepo(t *testing.T) {
+	t.Run("join queue for the first time", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewQueueRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertQueueTicketQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 12, "waiting", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+		mock.ExpectCommit()
+
+		got, err := repo.JoinQueue(1, 12)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(40), got.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejoin while waiting keeps the existing ticket", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewQueueRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertQueueTicketQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 12, "waiting", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(getUserTicketQuery).
+			WithArgs(1, 12, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "user_id", "status"}).AddRow(7, 1, 12, "waiting"))
+		mock.ExpectCommit()
+
+		got, err := repo.JoinQueue(1, 12)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), got.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rejoin after admission replaces the ticket", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewQueueRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertQueueTicketQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 12, "waiting", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(getUserTicketQuery).
+			WithArgs(1, 12, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "user_id", "status"}).AddRow(7, 1, 12, "admitted"))
+		mock.ExpectExec(deleteQueueTicketQuery).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(reissueQueueTicketQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 12, "waiting", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(41))
+		mock.ExpectCommit()
+
+		got, err := repo.JoinQueue(1, 12)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(41), got.ID)
+		assert.Equal(t, "waiting", got.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAdmitTickets_gormRepo(t *testing.T) {
+	t.Run("admit the oldest waiting tickets", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewQueueRepository(gormDB)
+
+		admittedAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+		expiresAt := admittedAt.Add(10 * time.Minute)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(admitTicketsQuery).
+			WithArgs(admittedAt, expiresAt, "admitted", sqlmock.AnyArg(), 1, "waiting", 50).
+			WillReturnResult(sqlmock.NewResult(0, 50))
+		mock.ExpectCommit()
+
+		got, err := repo.AdmitTickets(1, 50, admittedAt, expiresAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(50), got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
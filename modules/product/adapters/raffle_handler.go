@@ -0,0 +1,104 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpRaffleHandler struct {
+	usecase usecase.RaffleUsecase
+}
+
+func NewRaffleHandler(usecase usecase.RaffleUsecase) *httpRaffleHandler {
+	return &httpRaffleHandler{usecase}
+}
+
+func (h *httpRaffleHandler) CreateRaffle(c echo.Context) error {
+	raffleRequest := new(entities.RaffleRequest)
+	if err := request.ContextWrapper(c).Bind(raffleRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	raffle, err := h.usecase.CreateRaffle(c.Param("id"), raffleRequest)
+	if err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, raffle)
+}
+
+func (h *httpRaffleHandler) GetRaffle(c echo.Context) error {
+	raffle, err := h.usecase.GetRaffle(c.Param("id"))
+	if err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, raffle)
+}
+
+func (h *httpRaffleHandler) EnterRaffle(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	entry, err := h.usecase.EnterRaffle(c.Param("id"), userID)
+	if err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *httpRaffleHandler) GetRaffleEntry(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	entry, err := h.usecase.GetRaffleEntry(c.Param("id"), userID)
+	if err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, entry)
+}
+
+// ConfirmRafflePayment takes the payment provider's confirmation for a raffle
+// win's order. It must sit behind the payment signature middleware.
+func (h *httpRaffleHandler) ConfirmRafflePayment(c echo.Context) error {
+	confirmation := new(payment.Confirmation)
+	if err := request.ContextWrapper(c).Bind(confirmation); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	entry, err := h.usecase.SettleRafflePayment(confirmation)
+	if err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, entry)
+}
+
+func raffleError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "variant not found", "raffle not found", "raffle entry not found", "user not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "variant required", "blind box stock is set by its figures", "raffle entry has already closed", "unsupported currency":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "email not verified":
+		return c.JSON(http.StatusForbidden, ErrorResponse{Message: err.Error()})
+	case "insufficient stock", "raffle is not open for entries", "already entered", "raffle entry is not a win",
+		"payment deadline has passed", "payment amount does not match":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
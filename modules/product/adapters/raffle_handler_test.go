@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEnterRaffle(t *testing.T) {
+	t.Run("enter raffle successfully", func(t *testing.T) {
+		mockService := new(MockRaffleUsecase)
+		handler := &httpRaffleHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("EnterRaffle", "7", uint(12)).Return(&entities.RaffleEntry{ID: 30, RaffleID: 7, UserID: 12, Status: "entered"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.EnterRaffle(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("enter raffle without signing in", func(t *testing.T) {
+		mockService := new(MockRaffleUsecase)
+		handler := &httpRaffleHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+
+		err := handler.EnterRaffle(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "EnterRaffle", mock.Anything, mock.Anything)
+	})
+
+	t.Run("enter raffle twice", func(t *testing.T) {
+		mockService := new(MockRaffleUsecase)
+		handler := &httpRaffleHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("EnterRaffle", "7", uint(12)).Return((*entities.RaffleEntry)(nil), errors.New("already entered"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.EnterRaffle(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"already entered"}`, response.Body.String())
+	})
+
+	t.Run("enter raffle without a verified email", func(t *testing.T) {
+		mockService := new(MockRaffleUsecase)
+		handler := &httpRaffleHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("EnterRaffle", "7", uint(12)).Return((*entities.RaffleEntry)(nil), errors.New("email not verified"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.EnterRaffle(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
+
+func TestConfirmRafflePayment(t *testing.T) {
+	t.Run("confirm payment for a win", func(t *testing.T) {
+		mockService := new(MockRaffleUsecase)
+		handler := &httpRaffleHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SettleRafflePayment", &payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")}).
+			Return(&entities.RaffleEntry{ID: 40, RaffleID: 7, UserID: 12, Status: "paid", ReferenceID: "a1b2"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"a1b2","amount":{"amount":129000,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmRafflePayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("confirm payment for the wrong amount", func(t *testing.T) {
+		mockService := new(MockRaffleUsecase)
+		handler := &httpRaffleHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SettleRafflePayment", mock.Anything).Return((*entities.RaffleEntry)(nil), errors.New("payment amount does not match"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"reference_id":"a1b2","amount":{"amount":100,"currency":"THB"}}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.ConfirmRafflePayment(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"payment amount does not match"}`, response.Body.String())
+	})
+}
+
+type MockRaffleUsecase struct {
+	mock.Mock
+}
+
+func (m *MockRaffleUsecase) CreateRaffle(productID string, request *entities.RaffleRequest) (*entities.Raffle, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*entities.Raffle), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) GetRaffle(id string) (*entities.Raffle, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Raffle), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) EnterRaffle(id string, userID uint) (*entities.RaffleEntry, error) {
+	args := m.Called(id, userID)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) GetRaffleEntry(id string, userID uint) (*entities.RaffleEntry, error) {
+	args := m.Called(id, userID)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) SettleRafflePayment(confirmation *payment.Confirmation) (*entities.RaffleEntry, error) {
+	args := m.Called(confirmation)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) DrawClosedRaffles() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) PlaceWinOrders() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRaffleUsecase) PassOnLapsedWins() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,399 @@
+package adapters
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/draw"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRaffleRepository struct {
+	db *gorm.DB
+}
+
+func NewRaffleRepository(db *gorm.DB) usecase.RaffleRepository {
+	return &gormRaffleRepository{db}
+}
+
+// InsertRaffle creates the raffle and takes its units out of stock, so they
+// can only be sold to its winners.
+func (r *gormRaffleRepository) InsertRaffle(raffle *entities.Raffle) (*entities.Raffle, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		product, err := lockProduct(tx, raffle.ProductID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(raffle).Error; err != nil {
+			return err
+		}
+
+		return applyStockMovement(tx, product, &entities.StockMovement{
+			VariantID:   raffle.VariantID,
+			Kind:        entities.StockMovementReservation,
+			Quantity:    -raffle.Quantity,
+			ReferenceID: raffleReference(raffle.ID),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return raffle, nil
+}
+
+func (r *gormRaffleRepository) GetRaffle(id uint) (*entities.Raffle, error) {
+	raffle := &entities.Raffle{}
+
+	if err := r.db.First(raffle, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("raffle not found")
+		}
+		return nil, err
+	}
+
+	return raffle, nil
+}
+
+// InsertEntry enters the user into the raffle; a user gets one entry.
+func (r *gormRaffleRepository) InsertEntry(entry *entities.RaffleEntry) (*entities.RaffleEntry, error) {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "raffle_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(entry).Error; err != nil {
+		return nil, err
+	}
+
+	if entry.ID == 0 {
+		return nil, errors.New("already entered")
+	}
+
+	return entry, nil
+}
+
+func (r *gormRaffleRepository) GetEntry(raffleID, userID uint) (*entities.RaffleEntry, error) {
+	entry := &entities.RaffleEntry{}
+
+	if err := r.db.Where("raffle_id = ? AND user_id = ?", raffleID, userID).First(entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("raffle entry not found")
+		}
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetRafflesToDraw lists open raffles whose entry window closed before now.
+func (r *gormRaffleRepository) GetRafflesToDraw(now time.Time, limit int) ([]entities.Raffle, error) {
+	var raffles []entities.Raffle
+
+	if err := r.db.Where("status = ? AND entry_closes_at <= ?", entities.RaffleOpen, now).
+		Order("id").
+		Limit(limit).
+		Find(&raffles).Error; err != nil {
+		return nil, err
+	}
+
+	return raffles, nil
+}
+
+// DrawRaffle ranks every entry of an open raffle. The first Quantity ranks
+// win and the rest are waitlisted; a win's order is placed afterwards, through
+// AttachOrder. Units left over for want of entries go back into stock. It
+// reports false when the raffle was already drawn.
+func (r *gormRaffleRepository) DrawRaffle(id uint, now time.Time) (bool, error) {
+	drawn := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		raffle, err := lockRaffle(tx, id)
+		if err != nil {
+			return err
+		}
+		if raffle.Status != entities.RaffleOpen {
+			return nil
+		}
+
+		var entries []entities.RaffleEntry
+		if err := tx.Where("raffle_id = ?", raffle.ID).Order("id").Find(&entries).Error; err != nil {
+			return err
+		}
+
+		ranked, clientSeed := rankRaffleEntries(entries, raffle.Seed)
+
+		for i, entry := range ranked {
+			status := entities.RaffleEntryWaitlisted
+			if i < raffle.Quantity {
+				status = entities.RaffleEntryWon
+			}
+
+			if err := tx.Model(&entities.RaffleEntry{}).Where("id = ?", entry.ID).Updates(map[string]any{
+				"rank":   i + 1,
+				"status": status,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if unsold := raffle.Quantity - len(entries); unsold > 0 {
+			if err := releaseRaffleUnits(tx, raffle, unsold); err != nil {
+				return err
+			}
+		}
+
+		drawn = true
+		return tx.Model(raffle).Updates(map[string]any{
+			"status":      entities.RaffleDrawn,
+			"client_seed": clientSeed,
+			"drawn_at":    now,
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return drawn, nil
+}
+
+// GetUnorderedWins lists wins whose order has not been placed yet.
+func (r *gormRaffleRepository) GetUnorderedWins(limit int) ([]entities.RaffleEntry, error) {
+	var entries []entities.RaffleEntry
+
+	if err := r.db.Where("status = ? AND order_id IS NULL", entities.RaffleEntryWon).
+		Order("raffle_id, id").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// AttachOrder gives a win the order placed for it, to pay through until
+// dueAt. It reports false when the win has an order already or is no longer
+// a win.
+func (r *gormRaffleRepository) AttachOrder(entryID uint, order *entities.PlacedOrder, dueAt time.Time) (bool, error) {
+	result := r.db.Model(&entities.RaffleEntry{}).
+		Where("id = ? AND status = ? AND order_id IS NULL", entryID, entities.RaffleEntryWon).
+		Updates(map[string]any{
+			"order_id":       order.ID,
+			"reference_id":   order.ReferenceID,
+			"payment_due_at": dueAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetLapsedWins lists wins whose payment deadline passed before now.
+func (r *gormRaffleRepository) GetLapsedWins(now time.Time, limit int) ([]entities.RaffleEntry, error) {
+	var entries []entities.RaffleEntry
+
+	if err := r.db.Where("status = ? AND payment_due_at <= ?", entities.RaffleEntryWon, now).
+		Order("raffle_id, id").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// PassOnWin lapses an unpaid win and hands its unit to the best-ranked
+// waitlisted entry, whose order is placed afterwards through AttachOrder, or
+// puts it back into stock once the waitlist is used up. It returns the lapsed
+// entry, so its order can be cancelled, or nil when the win was paid or passed
+// on in the meantime.
+func (r *gormRaffleRepository) PassOnWin(entryID uint, now time.Time) (*entities.RaffleEntry, error) {
+	var lapsed *entities.RaffleEntry
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		entry := &entities.RaffleEntry{}
+		if err := tx.Select("raffle_id").First(entry, entryID).Error; err != nil {
+			return err
+		}
+
+		// The raffle is locked first so that two lapsed wins are never handed
+		// to the same waitlisted entry.
+		raffle, err := lockRaffle(tx, entry.RaffleID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ? AND payment_due_at <= ?", entryID, entities.RaffleEntryWon, now).
+			First(entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(entry).Update("status", entities.RaffleEntryLapsed).Error; err != nil {
+			return err
+		}
+		lapsed = entry
+
+		next := &entities.RaffleEntry{}
+		if err := tx.Where("raffle_id = ? AND status = ?", raffle.ID, entities.RaffleEntryWaitlisted).
+			Order("rank").
+			First(next).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return releaseRaffleUnits(tx, raffle, 1)
+			}
+			return err
+		}
+
+		return tx.Model(next).Update("status", entities.RaffleEntryWon).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lapsed, nil
+}
+
+// PayEntry sells a winner their unit once the payment for the order
+// referenced by referenceID is confirmed for amount, which must be the
+// raffle's price, the total of the order placed for the win. The hold is released and sold in one transaction, like a
+// converted reservation, so the ledger shows the sale against the order.
+// Raffle units are promised to their winners, so this ignores whether the
+// product is otherwise on sale. A confirmation repeated for a paid entry
+// changes nothing.
+func (r *gormRaffleRepository) PayEntry(referenceID string, amount money.Money, now time.Time) (*entities.RaffleEntry, error) {
+	entry := &entities.RaffleEntry{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("raffle_id").Where("reference_id = ?", referenceID).First(entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("raffle entry not found")
+			}
+			return err
+		}
+
+		raffle, err := lockRaffle(tx, entry.RaffleID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("raffle_id = ? AND reference_id = ?", raffle.ID, referenceID).
+			First(entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("raffle entry not found")
+			}
+			return err
+		}
+
+		if entry.Status == entities.RaffleEntryPaid {
+			return nil
+		}
+		if entry.Status != entities.RaffleEntryWon {
+			return errors.New("raffle entry is not a win")
+		}
+		if entry.OrderID == nil || !now.Before(*entry.PaymentDueAt) {
+			return errors.New("payment deadline has passed")
+		}
+
+		if amount != raffle.Price {
+			return errors.New("payment amount does not match")
+		}
+
+		product, err := lockProduct(tx, raffle.ProductID)
+		if err != nil {
+			return err
+		}
+
+		release := &entities.StockMovement{
+			VariantID:   raffle.VariantID,
+			Kind:        entities.StockMovementRelease,
+			Quantity:    1,
+			ReferenceID: raffleReference(raffle.ID),
+		}
+		if err := applyStockMovement(tx, product, release); err != nil {
+			return err
+		}
+
+		sale := &entities.StockMovement{
+			VariantID:   raffle.VariantID,
+			Kind:        entities.StockMovementSale,
+			Quantity:    -1,
+			ReferenceID: referenceID,
+		}
+		if err := applyStockMovement(tx, product, sale); err != nil {
+			return err
+		}
+
+		entry.Status = entities.RaffleEntryPaid
+		return tx.Model(entry).Update("status", entry.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func lockRaffle(tx *gorm.DB, id uint) (*entities.Raffle, error) {
+	raffle := &entities.Raffle{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(raffle, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("raffle not found")
+		}
+		return nil, err
+	}
+
+	return raffle, nil
+}
+
+// releaseRaffleUnits puts quantity of a raffle's held units back into stock.
+func releaseRaffleUnits(tx *gorm.DB, raffle *entities.Raffle, quantity int) error {
+	product, err := lockProduct(tx, raffle.ProductID)
+	if err != nil {
+		return err
+	}
+
+	return applyStockMovement(tx, product, &entities.StockMovement{
+		VariantID:   raffle.VariantID,
+		Kind:        entities.StockMovementRelease,
+		Quantity:    quantity,
+		ReferenceID: raffleReference(raffle.ID),
+	})
+}
+
+// rankRaffleEntries puts entries in a random order with a shuffle seeded by
+// the raffle's seed. The client seed is the hash of the entry IDs in ID order,
+// so anyone holding the entry list and the revealed seed can repeat the draw.
+func rankRaffleEntries(entries []entities.RaffleEntry, seed string) ([]entities.RaffleEntry, string) {
+	if len(entries) == 0 {
+		return nil, ""
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, strconv.FormatUint(uint64(entry.ID), 10))
+	}
+
+	clientSeed := draw.HashSeed(strings.Join(ids, ","))
+
+	ranked := slices.Clone(entries)
+	draw.Shuffle(len(ranked), seed, clientSeed, 0, func(i, j int) {
+		ranked[i], ranked[j] = ranked[j], ranked[i]
+	})
+
+	return ranked, clientSeed
+}
+
+func raffleReference(id uint) string {
+	return "raffle:" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,240 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	insertRaffleEntryQuery = `INSERT INTO "raffle_entries" ("created_at","updated_at","raffle_id","user_id","status","rank","payment_due_at","order_id","reference_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) ON CONFLICT ("raffle_id","user_id") DO NOTHING RETURNING "id"`
+	lockRaffleQuery        = `SELECT * FROM "raffles" WHERE "raffles"."id" = $1 AND "raffles"."deleted_at" IS NULL ORDER BY "raffles"."id" LIMIT $2 FOR UPDATE`
+	lockUserEntryQuery     = `SELECT * FROM "raffle_entries" WHERE raffle_id = $1 AND user_id = $2 ORDER BY "raffle_entries"."id" LIMIT $3 FOR UPDATE`
+	getEntryRaffleQuery    = `SELECT "raffle_id" FROM "raffle_entries" WHERE reference_id = $1 ORDER BY "raffle_entries"."id" LIMIT $2`
+	lockPaidEntryQuery     = `SELECT * FROM "raffle_entries" WHERE raffle_id = $1 AND reference_id = $2 ORDER BY "raffle_entries"."id" LIMIT $3 FOR UPDATE`
+	attachRaffleOrderQuery = `UPDATE "raffle_entries" SET "order_id"=$1,"payment_due_at"=$2,"reference_id"=$3,"updated_at"=$4 WHERE id = $5 AND status = $6 AND order_id IS NULL`
+)
+
+func TestRankRaffleEntries(t *testing.T) {
+	entries := []entities.RaffleEntry{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+
+	t.Run("rank every entry once", func(t *testing.T) {
+		ranked, clientSeed := rankRaffleEntries(entries, "server-seed")
+
+		assert.NotEmpty(t, clientSeed)
+		assert.ElementsMatch(t, entries, ranked)
+	})
+
+	t.Run("rank the same way given the same seed", func(t *testing.T) {
+		first, _ := rankRaffleEntries(entries, "server-seed")
+		second, _ := rankRaffleEntries(entries, "server-seed")
+
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("rank no entries", func(t *testing.T) {
+		ranked, clientSeed := rankRaffleEntries(nil, "server-seed")
+
+		assert.Empty(t, ranked)
+		assert.Empty(t, clientSeed)
+	})
+}
+
+func TestInsertEntry_gormRepo(t *testing.T) {
+	t.Run("enter raffle", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertRaffleEntryQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7, 12, "entered", 0, nil, nil, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(30))
+		mock.ExpectCommit()
+
+		got, err := repo.InsertEntry(&entities.RaffleEntry{RaffleID: 7, UserID: 12, Status: entities.RaffleEntryEntered})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(30), got.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("enter raffle twice", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertRaffleEntryQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7, 12, "entered", 0, nil, nil, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		_, err := repo.InsertEntry(&entities.RaffleEntry{RaffleID: 7, UserID: 12, Status: entities.RaffleEntryEntered})
+
+		assert.EqualError(t, err, "already entered")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPayEntry_gormRepo(t *testing.T) {
+	now := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("pay after the deadline", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getEntryRaffleQuery).
+			WithArgs("a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"raffle_id"}).AddRow(7))
+		mock.ExpectQuery(lockRaffleQuery).
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "status"}).AddRow(7, 1, "drawn"))
+		mock.ExpectQuery(lockPaidEntryQuery).
+			WithArgs(7, "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "raffle_id", "user_id", "status", "payment_due_at", "order_id"}).
+				AddRow(30, 7, 12, "won", now.Add(-time.Minute), 40))
+		mock.ExpectRollback()
+
+		_, err := repo.PayEntry("a1b2", money.New(129000, "THB"), now)
+
+		assert.EqualError(t, err, "payment deadline has passed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pay for an entry that did not win", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getEntryRaffleQuery).
+			WithArgs("a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"raffle_id"}).AddRow(7))
+		mock.ExpectQuery(lockRaffleQuery).
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "status"}).AddRow(7, 1, "drawn"))
+		mock.ExpectQuery(lockPaidEntryQuery).
+			WithArgs(7, "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "raffle_id", "user_id", "status"}).AddRow(30, 7, 12, "lapsed"))
+		mock.ExpectRollback()
+
+		_, err := repo.PayEntry("a1b2", money.New(129000, "THB"), now)
+
+		assert.EqualError(t, err, "raffle entry is not a win")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pay the wrong amount", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getEntryRaffleQuery).
+			WithArgs("a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"raffle_id"}).AddRow(7))
+		mock.ExpectQuery(lockRaffleQuery).
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "status", "price_amount", "price_currency"}).AddRow(7, 1, "drawn", 129000, "THB"))
+		mock.ExpectQuery(lockPaidEntryQuery).
+			WithArgs(7, "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "raffle_id", "user_id", "status", "payment_due_at", "order_id"}).
+				AddRow(30, 7, 12, "won", now.Add(time.Hour), 40))
+		mock.ExpectRollback()
+
+		_, err := repo.PayEntry("a1b2", money.New(100, "THB"), now)
+
+		assert.EqualError(t, err, "payment amount does not match")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("pay an entry that is already paid", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getEntryRaffleQuery).
+			WithArgs("a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"raffle_id"}).AddRow(7))
+		mock.ExpectQuery(lockRaffleQuery).
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "status"}).AddRow(7, 1, "drawn"))
+		mock.ExpectQuery(lockPaidEntryQuery).
+			WithArgs(7, "a1b2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "raffle_id", "user_id", "status", "order_id"}).AddRow(30, 7, 12, "paid", 40))
+		mock.ExpectCommit()
+
+		got, err := repo.PayEntry("a1b2", money.New(129000, "THB"), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.RaffleEntryPaid, got.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAttachOrder_gormRepo(t *testing.T) {
+	dueAt := time.Date(2026, 11, 1, 11, 0, 0, 0, time.UTC)
+	order := &entities.PlacedOrder{ID: 40, ReferenceID: "a1b2", Total: money.New(129000, "THB")}
+
+	t.Run("attach an order to a win", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(attachRaffleOrderQuery).
+			WithArgs(40, dueAt, "a1b2", sqlmock.AnyArg(), 30, "won").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.AttachOrder(30, order, dueAt)
+
+		assert.NoError(t, err)
+		assert.True(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("attach an order to a win passed on meanwhile", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRaffleRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(attachRaffleOrderQuery).
+			WithArgs(40, dueAt, "a1b2", sqlmock.AnyArg(), 30, "won").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		got, err := repo.AttachOrder(30, order, dueAt)
+
+		assert.NoError(t, err)
+		assert.False(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpReservationHandler struct {
+	usecase usecase.ReservationUsecase
+	queue   usecase.QueueUsecase
+}
+
+func NewReservationHandler(usecase usecase.ReservationUsecase, queue usecase.QueueUsecase) *httpReservationHandler {
+	return &httpReservationHandler{usecase, queue}
+}
+
+// Reserve holds units of the product named in the body. While the product has
+// an active waiting room, only a user it admitted may reserve it.
+func (h *httpReservationHandler) Reserve(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	reservationRequest := new(entities.ReservationRequest)
+	if err := request.ContextWrapper(c).Bind(reservationRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+	reservationRequest.UserID = userID
+	reservationRequest.HolderID = entities.CartHolderID(userID)
+
+	productID := strconv.FormatUint(uint64(reservationRequest.ProductID), 10)
+	if err := h.queue.CheckAdmission(productID, userID, c.Request().Header.Get(AdmissionTokenHeader)); err != nil {
+		return reservationError(c, err)
+	}
+
+	reservation, err := h.usecase.Reserve(reservationRequest)
+	if err != nil {
+		return reservationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reservation)
+}
+
+func (h *httpReservationHandler) GetReservations(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	reservations, err := h.usecase.GetReservations(entities.CartHolderID(userID))
+	if err != nil {
+		return reservationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reservations)
+}
+
+func (h *httpReservationHandler) ExtendReservations(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	reservations, err := h.usecase.ExtendReservations(entities.CartHolderID(userID))
+	if err != nil {
+		return reservationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reservations)
+}
+
+func (h *httpReservationHandler) ReleaseReservations(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	if err := h.usecase.ReleaseReservations(entities.CartHolderID(userID)); err != nil {
+		return reservationError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func reservationError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "variant not found", "reservation not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "variant required", "blind box stock is set by its figures":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "sign in required":
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: err.Error()})
+	case "admission required":
+		return c.JSON(http.StatusForbidden, ErrorResponse{Message: err.Error()})
+	case "insufficient stock", "product is not on sale", "purchase limit exceeded":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
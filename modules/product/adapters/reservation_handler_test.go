@@ -0,0 +1,249 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestReserve(t *testing.T) {
+	t.Run("reserve successfully", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		mockQueue := new(MockQueueUsecase)
+		handler := &httpReservationHandler{usecase: mockService, queue: mockQueue}
+
+		e := echo.New()
+		defer e.Close()
+
+		createdAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		expiresAt := createdAt.Add(15 * time.Minute)
+
+		mockQueue.On("CheckAdmission", "1", uint(12), "").Return(nil)
+		mockService.On("Reserve", &entities.ReservationRequest{UserID: 12, HolderID: "user:12", ProductID: 1, Quantity: 2}).Return(&entities.StockReservation{
+			Model: gorm.Model{ID: 4, CreatedAt: createdAt, UpdatedAt: createdAt}, HolderID: "user:12", ProductID: 1, Quantity: 2, Status: "active", ExpiresAt: expiresAt,
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"quantity":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Reserve(c)
+
+		expectedJSON := `{"ID":4,"CreatedAt":"2026-10-01T09:00:00Z","UpdatedAt":"2026-10-01T09:00:00Z","DeletedAt":null,"holder_id":"user:12","product_id":1,"quantity":2,"status":"active","expires_at":"2026-10-01T09:15:00Z"}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("reserve ignores a holder sent by the client", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		mockQueue := new(MockQueueUsecase)
+		handler := &httpReservationHandler{usecase: mockService, queue: mockQueue}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockQueue.On("CheckAdmission", "1", uint(12), "").Return(nil)
+		mockService.On("Reserve", &entities.ReservationRequest{UserID: 12, HolderID: "user:12", ProductID: 1, Quantity: 2}).
+			Return(&entities.StockReservation{HolderID: "user:12", ProductID: 1, Quantity: 2, Status: "active"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"holder_id":"user:7","product_id":1,"quantity":2}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Reserve(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("reserve given insufficient stock", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		mockQueue := new(MockQueueUsecase)
+		handler := &httpReservationHandler{usecase: mockService, queue: mockQueue}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockQueue.On("CheckAdmission", "1", uint(12), "").Return(nil)
+		mockService.On("Reserve", mock.Anything).Return((*entities.StockReservation)(nil), errors.New("insufficient stock"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"quantity":20}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Reserve(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"insufficient stock"}`, response.Body.String())
+	})
+}
+
+func TestReserveDrop(t *testing.T) {
+	t.Run("reserve passes the signed-in user", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		mockQueue := new(MockQueueUsecase)
+		handler := &httpReservationHandler{usecase: mockService, queue: mockQueue}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockQueue.On("CheckAdmission", "1", uint(12), "").Return(nil)
+		mockService.On("Reserve", &entities.ReservationRequest{UserID: 12, HolderID: "user:12", ProductID: 1, Quantity: 3}).
+			Return((*entities.StockReservation)(nil), errors.New("purchase limit exceeded"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"quantity":3}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Reserve(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("reserve without signing in", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		handler := &httpReservationHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"quantity":1}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.Reserve(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		mockService.AssertNotCalled(t, "Reserve", mock.Anything)
+	})
+
+	t.Run("reserve a drop without admission", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		mockQueue := new(MockQueueUsecase)
+		handler := &httpReservationHandler{usecase: mockService, queue: mockQueue}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockQueue.On("CheckAdmission", "5", uint(12), "stale-token").Return(errors.New("admission required"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":5,"quantity":1}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Set(AdmissionTokenHeader, "stale-token")
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Reserve(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+		mockService.AssertNotCalled(t, "Reserve", mock.Anything)
+	})
+}
+
+func TestExtendReservations(t *testing.T) {
+	t.Run("extend given no active reservations", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		handler := &httpReservationHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ExtendReservations", "user:12").Return(([]entities.StockReservation)(nil), errors.New("reservation not found"))
+
+		request := httptest.NewRequest(http.MethodPut, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.ExtendReservations(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestReleaseReservations(t *testing.T) {
+	t.Run("release reservations successfully", func(t *testing.T) {
+		mockService := new(MockReservationUsecase)
+		handler := &httpReservationHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("ReleaseReservations", "user:12").Return(nil)
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.ReleaseReservations(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, response.Code)
+	})
+}
+
+type MockReservationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockReservationUsecase) Reserve(request *entities.ReservationRequest) (*entities.StockReservation, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) GetReservations(holderID string) ([]entities.StockReservation, error) {
+	args := m.Called(holderID)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ExtendReservations(holderID string) ([]entities.StockReservation, error) {
+	args := m.Called(holderID)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ReleaseReservations(holderID string) error {
+	args := m.Called(holderID)
+	return args.Error(0)
+}
+
+func (m *MockReservationUsecase) HoldOrder(request *entities.OrderHoldRequest) ([]entities.StockReservation, error) {
+	args := m.Called(request)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ConvertReservations(request *entities.ReservationConvertRequest) ([]entities.StockReservation, error) {
+	args := m.Called(request)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationUsecase) ReleaseExpired() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
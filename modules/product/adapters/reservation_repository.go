@@ -0,0 +1,363 @@
+package adapters
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormReservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) usecase.ReservationRepository {
+	return &gormReservationRepository{db}
+}
+
+// SaveReservation sets the holder's reservation for an item to
+// reservation.Quantity, taking the difference out of stock or putting it back,
+// and moves its expiry to reservation.ExpiresAt. A quantity of 0 releases it.
+func (r *gormReservationRepository) SaveReservation(reservation *entities.StockReservation) (*entities.StockReservation, error) {
+	saved := &entities.StockReservation{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		product, err := lockProduct(tx, reservation.ProductID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(map[string]interface{}{
+				"holder_id":  reservation.HolderID,
+				"product_id": reservation.ProductID,
+				"variant_id": reservation.VariantID,
+				"status":     entities.ReservationActive,
+			}).
+			First(saved).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if reservation.Quantity == 0 {
+				return errors.New("reservation not found")
+			}
+			saved = &entities.StockReservation{
+				HolderID:  reservation.HolderID,
+				UserID:    reservation.UserID,
+				ProductID: reservation.ProductID,
+				VariantID: reservation.VariantID,
+				Status:    entities.ReservationActive,
+			}
+		}
+
+		return resizeReservation(tx, product, saved, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// HoldOrder moves the cart's reservations for an order's items onto the order,
+// keyed by its reference, and sets each to the quantity and expiry the order
+// holds it for. Units the cart's reservation lost to expiry are reserved
+// again, so stock and the drop and cart limits are checked once more before
+// the order is placed. Either every item is held or none is.
+func (r *gormReservationRepository) HoldOrder(cartHolderID string, reservations []entities.StockReservation) ([]entities.StockReservation, error) {
+	held := make([]entities.StockReservation, 0, len(reservations))
+
+	// Products are locked in ID order, as holderReservations lists them.
+	sorted := append([]entities.StockReservation(nil), reservations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ProductID < sorted[j].ProductID })
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range sorted {
+			reservation := &sorted[i]
+
+			product, err := lockProduct(tx, reservation.ProductID)
+			if err != nil {
+				return err
+			}
+
+			saved := &entities.StockReservation{}
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where(map[string]interface{}{
+					"holder_id":  cartHolderID,
+					"product_id": reservation.ProductID,
+					"variant_id": reservation.VariantID,
+					"status":     entities.ReservationActive,
+				}).
+				First(saved).Error; err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				saved = &entities.StockReservation{
+					UserID:    reservation.UserID,
+					ProductID: reservation.ProductID,
+					VariantID: reservation.VariantID,
+					Status:    entities.ReservationActive,
+				}
+			}
+			saved.HolderID = reservation.HolderID
+
+			if err := resizeReservation(tx, product, saved, reservation); err != nil {
+				return err
+			}
+			held = append(held, *saved)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return held, nil
+}
+
+func (r *gormReservationRepository) GetReservations(holderID string) ([]entities.StockReservation, error) {
+	var reservations []entities.StockReservation
+
+	if err := r.db.Where("holder_id = ? AND status = ?", holderID, entities.ReservationActive).
+		Order("id").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+func (r *gormReservationRepository) ExtendReservations(holderID string, expiresAt time.Time) (int64, error) {
+	result := r.db.Model(&entities.StockReservation{}).
+		Where("holder_id = ? AND status = ?", holderID, entities.ReservationActive).
+		Update("expires_at", expiresAt)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ReleaseReservations puts the holder's stock back on sale. An order cancelled
+// while held for review withdraws its review, and the stock set aside for it
+// is released with the rest.
+func (r *gormReservationRepository) ReleaseReservations(holderID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.OrderReview{}).
+			Where("reference_id = ? AND status = ?", holderID, entities.OrderReviewPending).
+			Update("status", entities.OrderReviewWithdrawn).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entities.StockReservation{}).
+			Where("holder_id = ? AND status = ?", holderID, entities.ReservationHeld).
+			Update("status", entities.ReservationActive).Error; err != nil {
+			return err
+		}
+
+		reservations, err := holderReservations(tx, holderID)
+		if err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			if _, err := settleReservation(tx, &reservations[i], entities.ReservationReleased, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ConvertReservations turns the holder's reservations into sales once the
+// order is paid. Each one is released and sold in the same transaction so the
+// ledger shows the sale against the order.
+func (r *gormReservationRepository) ConvertReservations(holderID string, checkout *entities.Checkout) ([]entities.StockReservation, error) {
+	converted := []entities.StockReservation{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		reservations, err := holderReservations(tx, holderID)
+		if err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			settled, err := settleReservation(tx, &reservations[i], entities.ReservationConverted, checkout)
+			if err != nil {
+				return err
+			}
+			if settled {
+				converted = append(converted, reservations[i])
+			}
+		}
+
+		if len(converted) == 0 {
+			return errors.New("reservation not found")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converted, nil
+}
+
+// ReleaseExpiredReservations releases up to limit reservations that expired
+// before now, each in its own transaction so one failure does not hold back
+// the rest. It returns how many were released.
+func (r *gormReservationRepository) ReleaseExpiredReservations(now time.Time, limit int) (int, error) {
+	var expired []entities.StockReservation
+
+	if err := r.db.Where("status = ? AND expires_at <= ?", entities.ReservationActive, now).
+		Order("product_id, id").
+		Limit(limit).
+		Find(&expired).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range expired {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			settled, err := settleReservation(tx, &expired[i], entities.ReservationExpired, nil)
+			if settled {
+				released++
+			}
+			return err
+		})
+		if err != nil {
+			return released, err
+		}
+	}
+
+	return released, nil
+}
+
+// resizeReservation sets a locked product's reservation saved to the quantity
+// and expiry of reservation, taking the difference out of stock or putting it
+// back under reservation's holder. The drop and cart limits are checked when
+// the quantity grows. A quantity of 0 releases it.
+func resizeReservation(tx *gorm.DB, product *entities.Product, saved, reservation *entities.StockReservation) error {
+	if saved.UserID == nil {
+		saved.UserID = reservation.UserID
+	}
+	if reservation.AddressKey != "" {
+		saved.AddressKey = reservation.AddressKey
+	}
+
+	if reservation.Quantity > saved.Quantity {
+		if err := checkDropPurchase(tx, product, saved.UserID, saved.ID, reservation.Quantity); err != nil {
+			return err
+		}
+
+		if err := checkCartLimits(tx, product, saved, reservation.Quantity); err != nil {
+			return err
+		}
+	}
+
+	if delta := reservation.Quantity - saved.Quantity; delta != 0 {
+		movement := &entities.StockMovement{
+			VariantID:   reservation.VariantID,
+			Kind:        entities.StockMovementReservation,
+			Quantity:    -delta,
+			ReferenceID: reservation.HolderID,
+		}
+		if delta < 0 {
+			movement.Kind = entities.StockMovementRelease
+		}
+
+		if err := applyStockMovement(tx, product, movement); err != nil {
+			return err
+		}
+	}
+
+	saved.Quantity = reservation.Quantity
+	saved.ExpiresAt = reservation.ExpiresAt
+	if saved.Quantity == 0 {
+		saved.Status = entities.ReservationReleased
+	}
+
+	return tx.Save(saved).Error
+}
+
+// holderReservations lists a holder's active reservations in product order,
+// the order their products are locked in, so that two transactions settling
+// overlapping items cannot deadlock.
+func holderReservations(tx *gorm.DB, holderID string) ([]entities.StockReservation, error) {
+	var reservations []entities.StockReservation
+
+	err := tx.Where("holder_id = ? AND status = ?", holderID, entities.ReservationActive).
+		Order("product_id, id").
+		Find(&reservations).Error
+
+	return reservations, err
+}
+
+// settleReservation puts a reservation's stock back and closes it with status;
+// converted reservations are sold to checkout again straight away. It reports
+// false when the reservation was already settled by someone else.
+func settleReservation(tx *gorm.DB, reservation *entities.StockReservation, status string, checkout *entities.Checkout) (bool, error) {
+	product, err := lockProduct(tx, reservation.ProductID)
+	if err != nil {
+		return false, err
+	}
+
+	locked := &entities.StockReservation{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status = ?", reservation.ID, entities.ReservationActive).
+		First(locked).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	*reservation = *locked
+
+	release := &entities.StockMovement{
+		VariantID:   reservation.VariantID,
+		Kind:        entities.StockMovementRelease,
+		Quantity:    reservation.Quantity,
+		ReferenceID: reservation.HolderID,
+	}
+	if err := applyStockMovement(tx, product, release); err != nil {
+		return false, err
+	}
+
+	updates := map[string]any{"status": status}
+	if status == entities.ReservationConverted {
+		if product.OffSale || product.PreOrder {
+			return false, errors.New("product is not on sale")
+		}
+
+		if err := checkCheckoutLimits(tx, product, reservation, checkout); err != nil {
+			return false, err
+		}
+
+		sale := &entities.StockMovement{
+			VariantID:   reservation.VariantID,
+			Kind:        entities.StockMovementSale,
+			Quantity:    -reservation.Quantity,
+			ReferenceID: checkout.ReferenceID,
+		}
+		if err := applyStockMovement(tx, product, sale); err != nil {
+			return false, err
+		}
+
+		updates["reference_id"] = checkout.ReferenceID
+		updates["address_key"] = checkout.AddressKey
+		updates["instrument_key"] = checkout.InstrumentKey
+	}
+
+	if err := tx.Model(reservation).Updates(updates).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,468 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getActiveReservationQuery    = `SELECT * FROM "stock_reservations" WHERE ("holder_id" = $1 AND "product_id" = $2 AND "status" = $3 AND "variant_id" IS NULL) AND "stock_reservations"."deleted_at" IS NULL ORDER BY "stock_reservations"."id" LIMIT $4 FOR UPDATE`
+	insertReservationQuery       = `INSERT INTO "stock_reservations" ("created_at","updated_at","deleted_at","holder_id","user_id","product_id","variant_id","quantity","status","expires_at","reference_id","address_key","instrument_key","review_id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id"`
+	saveReservationQuery         = `UPDATE "stock_reservations" SET "created_at"=$1,"updated_at"=$2,"deleted_at"=$3,"holder_id"=$4,"user_id"=$5,"product_id"=$6,"variant_id"=$7,"quantity"=$8,"status"=$9,"expires_at"=$10,"reference_id"=$11,"address_key"=$12,"instrument_key"=$13,"review_id"=$14 WHERE "stock_reservations"."deleted_at" IS NULL AND "id" = $15`
+	getHolderReservationsQuery   = `SELECT * FROM "stock_reservations" WHERE (holder_id = $1 AND status = $2) AND "stock_reservations"."deleted_at" IS NULL ORDER BY product_id, id`
+	getReservationForUpdateQuery = `SELECT * FROM "stock_reservations" WHERE (id = $1 AND status = $2) AND "stock_reservations"."deleted_at" IS NULL ORDER BY "stock_reservations"."id" LIMIT $3 FOR UPDATE`
+	updateReservationStatusQuery = `UPDATE "stock_reservations" SET "status"=$1,"updated_at"=$2 WHERE "stock_reservations"."deleted_at" IS NULL AND "id" = $3`
+	convertReservationQuery      = `UPDATE "stock_reservations" SET "address_key"=$1,"instrument_key"=$2,"reference_id"=$3,"status"=$4,"updated_at"=$5 WHERE "stock_reservations"."deleted_at" IS NULL AND "id" = $6`
+	sumAddressSalesQuery         = `SELECT COALESCE(SUM(quantity), 0) FROM "stock_reservations" WHERE (product_id = $1 AND status = $2 AND address_key = $3) AND "stock_reservations"."deleted_at" IS NULL`
+	sumAddressTakenQuery         = `SELECT COALESCE(SUM(quantity), 0) FROM "stock_reservations" WHERE (product_id = $1 AND address_key = $2 AND status IN ($3,$4,$5) AND id <> $6) AND "stock_reservations"."deleted_at" IS NULL`
+	extendReservationsQuery      = `UPDATE "stock_reservations" SET "expires_at"=$1,"updated_at"=$2 WHERE (holder_id = $3 AND status = $4) AND "stock_reservations"."deleted_at" IS NULL`
+	withdrawOrderReviewQuery     = `UPDATE "order_reviews" SET "status"=$1,"updated_at"=$2 WHERE (reference_id = $3 AND status = $4) AND "order_reviews"."deleted_at" IS NULL`
+	unholdReservationsQuery      = `UPDATE "stock_reservations" SET "status"=$1,"updated_at"=$2 WHERE (holder_id = $3 AND status = $4) AND "stock_reservations"."deleted_at" IS NULL`
+	getExpiredReservationsQuery  = `SELECT * FROM "stock_reservations" WHERE (status = $1 AND expires_at <= $2) AND "stock_reservations"."deleted_at" IS NULL ORDER BY product_id, id LIMIT $3`
+)
+
+func TestSaveReservation_gormRepo(t *testing.T) {
+	t.Run("reserve new item and take it out of stock", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		expiresAt := time.Date(2026, 10, 1, 9, 15, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 10, true))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(8, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "reservation", -2, 8, nil, "cart-abc", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+		mock.ExpectQuery(insertReservationQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "cart-abc", nil, 1, nil, 2, "active", expiresAt, "", "", "", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectCommit()
+
+		got, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 2, ExpiresAt: expiresAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), got.ID)
+		assert.Equal(t, 2, got.Quantity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("lower reserved quantity and put the difference back", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		createdAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		expiresAt := time.Date(2026, 10, 1, 9, 20, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 8, true))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "holder_id", "product_id", "quantity", "status"}).
+				AddRow(4, createdAt, "cart-abc", 1, 3, "active"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "release", 2, 10, nil, "cart-abc", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectExec(saveReservationQuery).
+			WithArgs(createdAt, sqlmock.AnyArg(), nil, "cart-abc", nil, 1, nil, 1, "active", expiresAt, "", "", "", nil, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 1, ExpiresAt: expiresAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got.Quantity)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("release item that is not reserved", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 8))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 0})
+
+		assert.EqualError(t, err, "reservation not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve more than is in stock", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 1))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("cart-abc", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "cart-abc", ProductID: 1, Quantity: 2})
+
+		assert.EqualError(t, err, "insufficient stock")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve more than the address limit leaves", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "address_purchase_limit"}).AddRow(1, 10, 2))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("user:12", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(sumAddressTakenQuery).
+			WithArgs(1, "addr-key", "active", "held", "converted", 0).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveReservation(&entities.StockReservation{HolderID: "user:12", ProductID: 1, Quantity: 2, AddressKey: "addr-key"})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHoldOrder_gormRepo(t *testing.T) {
+	t.Run("move cart reservation onto the order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		createdAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		expiresAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 8, true))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("user:12", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "holder_id", "product_id", "quantity", "status"}).
+				AddRow(4, createdAt, "user:12", 1, 2, "active"))
+		mock.ExpectExec(saveReservationQuery).
+			WithArgs(createdAt, sqlmock.AnyArg(), nil, "ORD-1001", nil, 1, nil, 2, "active", expiresAt, "", "", "", nil, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.HoldOrder("user:12", []entities.StockReservation{{HolderID: "ORD-1001", ProductID: 1, Quantity: 2, ExpiresAt: expiresAt}})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "ORD-1001", got[0].HolderID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reserve again given the cart reservation expired and stock ran out", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 1))
+		mock.ExpectQuery(getActiveReservationQuery).
+			WithArgs("user:12", 1, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		_, err := repo.HoldOrder("user:12", []entities.StockReservation{{HolderID: "ORD-1001", ProductID: 1, Quantity: 2}})
+
+		assert.EqualError(t, err, "insufficient stock")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExtendReservations_gormRepo(t *testing.T) {
+	t.Run("extend holder's active reservations", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		expiresAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(extendReservationsQuery).
+			WithArgs(expiresAt, sqlmock.AnyArg(), "cart-abc", "active").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		got, err := repo.ExtendReservations("cart-abc", expiresAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), got)
+	})
+}
+
+func TestConvertReservations_gormRepo(t *testing.T) {
+	t.Run("convert reservation into a sale against the order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getHolderReservationsQuery).
+			WithArgs("ORD-1001", "active").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 8, true))
+		mock.ExpectQuery(getReservationForUpdateQuery).
+			WithArgs(4, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "release", 2, 10, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(8, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fulfilProductSaleQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "sale", -2, 8, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(13))
+		mock.ExpectExec(convertReservationQuery).
+			WithArgs("", "", "ORD-1001", "converted", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.ConvertReservations("ORD-1001", &entities.Checkout{ReferenceID: "ORD-1001"})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, "converted", got[0].Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("convert beyond the per-address limit", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getHolderReservationsQuery).
+			WithArgs("ORD-1001", "active").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active", "address_purchase_limit"}).AddRow(1, 8, true, 2))
+		mock.ExpectQuery(getReservationForUpdateQuery).
+			WithArgs(4, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "release", 2, 10, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectQuery(sumAddressSalesQuery).
+			WithArgs(1, "converted", "addr-key").
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+		mock.ExpectRollback()
+
+		_, err := repo.ConvertReservations("ORD-1001", &entities.Checkout{ReferenceID: "ORD-1001", AddressKey: "addr-key"})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("convert given no active reservations", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getHolderReservationsQuery).
+			WithArgs("ORD-1001", "active").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.ConvertReservations("ORD-1001", &entities.Checkout{ReferenceID: "ORD-1001"})
+
+		assert.EqualError(t, err, "reservation not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseReservations_gormRepo(t *testing.T) {
+	t.Run("release an order held for review and withdraw the review", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(withdrawOrderReviewQuery).
+			WithArgs("withdrawn", sqlmock.AnyArg(), "ORD-1001", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(unholdReservationsQuery).
+			WithArgs("active", sqlmock.AnyArg(), "ORD-1001", "held").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getHolderReservationsQuery).
+			WithArgs("ORD-1001", "active").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 8, true))
+		mock.ExpectQuery(getReservationForUpdateQuery).
+			WithArgs(4, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "release", 2, 10, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectExec(updateReservationStatusQuery).
+			WithArgs("released", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.ReleaseReservations("ORD-1001")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReleaseExpiredReservations_gormRepo(t *testing.T) {
+	t.Run("release expired reservation skipping one already settled", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewReservationRepository(gormDB)
+
+		now := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+
+		mock.ExpectQuery(getExpiredReservationsQuery).
+			WithArgs("active", now, 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).
+				AddRow(4, "cart-abc", 1, 2, "active").
+				AddRow(5, "cart-xyz", 1, 1, "active"))
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 0, false))
+		mock.ExpectQuery(getReservationForUpdateQuery).
+			WithArgs(4, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "cart-abc", 1, 2, "active"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(2, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "release", 2, 2, nil, "cart-abc", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(14))
+		mock.ExpectExec(updateReservationStatusQuery).
+			WithArgs("expired", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 2, true))
+		mock.ExpectQuery(getReservationForUpdateQuery).
+			WithArgs(5, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+
+		got, err := repo.ReleaseExpiredReservations(now, 100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpRiskHandler struct {
+	usecase usecase.RiskUsecase
+}
+
+func NewRiskHandler(usecase usecase.RiskUsecase) *httpRiskHandler {
+	return &httpRiskHandler{usecase}
+}
+
+func (h *httpRiskHandler) SetPurchaseLimits(c echo.Context) error {
+	limitsRequest := new(entities.PurchaseLimitsRequest)
+	if err := request.ContextWrapper(c).Bind(limitsRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	limits, err := h.usecase.SetPurchaseLimits(c.Param("id"), limitsRequest)
+	if err != nil {
+		return riskError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, limits)
+}
+
+func (h *httpRiskHandler) CreateRiskRule(c echo.Context) error {
+	rule := new(entities.RiskRule)
+	if err := request.ContextWrapper(c).Bind(rule); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	created, err := h.usecase.CreateRiskRule(rule)
+	if err != nil {
+		return riskError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *httpRiskHandler) GetRiskRules(c echo.Context) error {
+	rules, err := h.usecase.GetRiskRules()
+	if err != nil {
+		return riskError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+func (h *httpRiskHandler) DeleteRiskRule(c echo.Context) error {
+	if err := h.usecase.DeleteRiskRule(c.Param("id")); err != nil {
+		return riskError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpRiskHandler) GetOrderReviews(c echo.Context) error {
+	filter := new(entities.OrderReviewFilter)
+	if err := request.ContextWrapper(c).Bind(filter); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	reviews, err := h.usecase.GetOrderReviews(filter)
+	if err != nil {
+		return riskError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reviews)
+}
+
+func riskError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "risk rule not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,101 @@
+package adapters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateRiskRule(t *testing.T) {
+	t.Run("create risk rule successfully", func(t *testing.T) {
+		mockService := new(MockRiskUsecase)
+		handler := &httpRiskHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		rule := &entities.RiskRule{Name: "new account", Signal: "account_age_hours", Operator: "lt", Threshold: 24, Action: "hold"}
+		mockService.On("CreateRiskRule", rule).Return(&entities.RiskRule{Model: gorm.Model{ID: 1}, Name: "new account", Signal: "account_age_hours", Operator: "lt", Threshold: 24, Action: "hold", Active: true}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"new account","signal":"account_age_hours","operator":"lt","threshold":24,"action":"hold"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateRiskRule(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("create risk rule given unknown signal", func(t *testing.T) {
+		mockService := new(MockRiskUsecase)
+		handler := &httpRiskHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"shoe size","signal":"shoe_size","operator":"gt","threshold":40,"action":"hold"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateRiskRule(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "CreateRiskRule", mock.Anything)
+	})
+}
+
+type MockRiskUsecase struct {
+	mock.Mock
+}
+
+func (m *MockRiskUsecase) SetPurchaseLimits(productID string, request *entities.PurchaseLimitsRequest) (*entities.PurchaseLimits, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(*entities.PurchaseLimits), args.Error(1)
+}
+
+func (m *MockRiskUsecase) CreateRiskRule(rule *entities.RiskRule) (*entities.RiskRule, error) {
+	args := m.Called(rule)
+	return args.Get(0).(*entities.RiskRule), args.Error(1)
+}
+
+func (m *MockRiskUsecase) GetRiskRules() ([]entities.RiskRule, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.RiskRule), args.Error(1)
+}
+
+func (m *MockRiskUsecase) DeleteRiskRule(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRiskUsecase) GetOrderReviews(filter *entities.OrderReviewFilter) ([]entities.OrderReview, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskUsecase) ScreenOrder(request *entities.OrderScreenRequest) (*entities.OrderReview, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskUsecase) ApproveOrderReview(referenceID string, reviewerID uint, expiresAt time.Time) (*entities.OrderReview, error) {
+	args := m.Called(referenceID, reviewerID, expiresAt)
+	return args.Get(0).(*entities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskUsecase) RejectOrderReview(referenceID string, reviewerID uint) (*entities.OrderReview, error) {
+	args := m.Called(referenceID, reviewerID)
+	return args.Get(0).(*entities.OrderReview), args.Error(1)
+}
@@ -0,0 +1,241 @@
+package adapters
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormRiskRepository struct {
+	db *gorm.DB
+}
+
+func NewRiskRepository(db *gorm.DB) usecase.RiskRepository {
+	return &gormRiskRepository{db}
+}
+
+func (r *gormRiskRepository) UpdatePurchaseLimits(product *entities.Product) error {
+	result := r.db.Model(&entities.Product{}).Where("id = ?", product.ID).Updates(map[string]any{
+		"purchase_limit":            product.PurchaseLimit,
+		"address_purchase_limit":    product.AddressPurchaseLimit,
+		"instrument_purchase_limit": product.InstrumentPurchaseLimit,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("product not found")
+	}
+
+	return nil
+}
+
+func (r *gormRiskRepository) InsertRiskRule(rule *entities.RiskRule) (*entities.RiskRule, error) {
+	if err := r.db.Create(rule).Error; err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (r *gormRiskRepository) GetRiskRules(activeOnly bool) ([]entities.RiskRule, error) {
+	var rules []entities.RiskRule
+
+	query := r.db.Order("id")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *gormRiskRepository) DeleteRiskRule(id string) error {
+	result := r.db.Delete(&entities.RiskRule{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("risk rule not found")
+	}
+
+	return nil
+}
+
+func (r *gormRiskRepository) GetOrderReviews(status string) ([]entities.OrderReview, error) {
+	var reviews []entities.OrderReview
+
+	query := r.db.Order("id")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// HoldOrderReview files the review and sets the stock reserved for its order
+// aside. Held reservations neither expire nor sell until it is settled. An
+// order of blind boxes alone has none to set aside.
+func (r *gormRiskRepository) HoldOrderReview(review *entities.OrderReview) (*entities.OrderReview, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.StockReservation{}).
+			Where("holder_id = ? AND status = ?", review.ReferenceID, entities.ReservationActive).
+			Updates(map[string]any{"status": entities.ReservationHeld, "review_id": review.ID}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// ApproveOrderReview puts the stock set aside for a pending review back on
+// hold for its order until expiresAt, when it expires as any other would.
+func (r *gormRiskRepository) ApproveOrderReview(referenceID string, reviewerID uint, reviewedAt, expiresAt time.Time) (*entities.OrderReview, error) {
+	return r.settleOrderReview(referenceID, reviewerID, reviewedAt, entities.OrderReviewApproved, &expiresAt)
+}
+
+// RejectOrderReview puts the stock set aside for a pending review back on sale.
+func (r *gormRiskRepository) RejectOrderReview(referenceID string, reviewerID uint, reviewedAt time.Time) (*entities.OrderReview, error) {
+	return r.settleOrderReview(referenceID, reviewerID, reviewedAt, entities.OrderReviewRejected, nil)
+}
+
+// settleOrderReview closes the pending review of the order referenced by
+// referenceID with status. The stock set aside for it is held again until
+// expiresAt, or released when there is none.
+func (r *gormRiskRepository) settleOrderReview(referenceID string, reviewerID uint, reviewedAt time.Time, status string, expiresAt *time.Time) (*entities.OrderReview, error) {
+	review := &entities.OrderReview{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(review, "reference_id = ?", referenceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("order review not found")
+			}
+			return err
+		}
+
+		if review.Status != entities.OrderReviewPending {
+			return errors.New("order review already settled")
+		}
+
+		var held []entities.StockReservation
+		if err := tx.Where("review_id = ? AND status = ?", review.ID, entities.ReservationHeld).
+			Order("product_id, id").
+			Find(&held).Error; err != nil {
+			return err
+		}
+
+		for i := range held {
+			if expiresAt != nil {
+				if err := tx.Model(&held[i]).Updates(map[string]any{"status": entities.ReservationActive, "expires_at": *expiresAt}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Model(&held[i]).Update("status", entities.ReservationActive).Error; err != nil {
+				return err
+			}
+
+			if _, err := settleReservation(tx, &held[i], entities.ReservationReleased, nil); err != nil {
+				return err
+			}
+		}
+
+		review.Status = status
+		review.ReviewedBy = &reviewerID
+		review.ReviewedAt = &reviewedAt
+		return tx.Model(review).Updates(map[string]any{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": reviewedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// checkCheckoutLimits refuses to sell a locked product's reservation beyond
+// its per-address or per-payment-instrument limit. Units already sold to the
+// same address or instrument count towards it, whichever account bought them.
+func checkCheckoutLimits(tx *gorm.DB, product *entities.Product, reservation *entities.StockReservation, checkout *entities.Checkout) error {
+	limits := []struct {
+		column  string
+		key     string
+		limit   int
+		missing string
+	}{
+		{"address_key", checkout.AddressKey, product.AddressPurchaseLimit, "shipping address required"},
+		{"instrument_key", checkout.InstrumentKey, product.InstrumentPurchaseLimit, "payment instrument required"},
+	}
+
+	for _, l := range limits {
+		if l.limit == 0 {
+			continue
+		}
+
+		if l.key == "" {
+			return errors.New(l.missing)
+		}
+
+		var sold int64
+		if err := tx.Model(&entities.StockReservation{}).
+			Where("product_id = ? AND status = ? AND "+l.column+" = ?", product.ID, entities.ReservationConverted, l.key).
+			Select("COALESCE(SUM(quantity), 0)").
+			Scan(&sold).Error; err != nil {
+			return errors.New("failed to retrieve reservations")
+		}
+
+		if int(sold)+reservation.Quantity > l.limit {
+			return errors.New("purchase limit exceeded")
+		}
+	}
+
+	return nil
+}
+
+// checkCartLimits refuses to hold more of a locked product in a cart than its
+// per-address limit leaves for the reservation's address. Units sold to the
+// address or set aside in any cart for it count towards the limit. The
+// payment instrument is not known before checkout, so its limit waits until
+// then, as does the address limit for a user without a profile address.
+func checkCartLimits(tx *gorm.DB, product *entities.Product, reservation *entities.StockReservation, quantity int) error {
+	if product.AddressPurchaseLimit == 0 || reservation.AddressKey == "" {
+		return nil
+	}
+
+	var taken int64
+	if err := tx.Model(&entities.StockReservation{}).
+		Where("product_id = ? AND address_key = ? AND status IN ? AND id <> ?", product.ID, reservation.AddressKey,
+			[]string{entities.ReservationActive, entities.ReservationHeld, entities.ReservationConverted}, reservation.ID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&taken).Error; err != nil {
+		return errors.New("failed to retrieve reservations")
+	}
+
+	if int(taken)+quantity > product.AddressPurchaseLimit {
+		return errors.New("purchase limit exceeded")
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package adapters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	holdOrderReviewQuery   = `UPDATE "stock_reservations" SET "review_id"=$1,"status"=$2,"updated_at"=$3 WHERE (holder_id = $4 AND status = $5) AND "stock_reservations"."deleted_at" IS NULL`
+	getReviewHeldQuery     = `SELECT * FROM "stock_reservations" WHERE (review_id = $1 AND status = $2) AND "stock_reservations"."deleted_at" IS NULL ORDER BY product_id, id`
+	insertOrderReviewQuery = `INSERT INTO "order_reviews" ("created_at","updated_at","deleted_at","reference_id","user_id","reasons","status","reviewed_by","reviewed_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`
+	lockOrderReviewQuery   = `SELECT * FROM "order_reviews" WHERE reference_id = $1 AND "order_reviews"."deleted_at" IS NULL ORDER BY "order_reviews"."id" LIMIT $2 FOR UPDATE`
+	settleOrderReviewQuery = `UPDATE "order_reviews" SET "reviewed_at"=$1,"reviewed_by"=$2,"status"=$3,"updated_at"=$4 WHERE "order_reviews"."deleted_at" IS NULL AND "id" = $5`
+	reholdReservationQuery = `UPDATE "stock_reservations" SET "expires_at"=$1,"status"=$2,"updated_at"=$3 WHERE "stock_reservations"."deleted_at" IS NULL AND "id" = $4`
+)
+
+func TestHoldOrderReview_gormRepo(t *testing.T) {
+	t.Run("file review and set the order's stock aside", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRiskRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertOrderReviewQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "ORD-1001", nil, "new account", "pending", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(holdOrderReviewQuery).
+			WithArgs(3, "held", sqlmock.AnyArg(), "ORD-1001", "active").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		got, err := repo.HoldOrderReview(&entities.OrderReview{ReferenceID: "ORD-1001", Reasons: "new account", Status: "pending"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), got.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("file review for an order of blind boxes alone", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRiskRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(insertOrderReviewQuery).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "ORD-1001", nil, "", "pending", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec(holdOrderReviewQuery).
+			WithArgs(3, "held", sqlmock.AnyArg(), "ORD-1001", "active").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		_, err := repo.HoldOrderReview(&entities.OrderReview{ReferenceID: "ORD-1001", Status: "pending"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestApproveOrderReview_gormRepo(t *testing.T) {
+	reviewedAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := reviewedAt.Add(30 * time.Minute)
+
+	t.Run("approve review and hold the stock for payment again", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRiskRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrderReviewQuery).
+			WithArgs("ORD-1001", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reference_id", "status"}).AddRow(3, "ORD-1001", "pending"))
+		mock.ExpectQuery(getReviewHeldQuery).
+			WithArgs(3, "held").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "held"))
+		mock.ExpectExec(reholdReservationQuery).
+			WithArgs(expiresAt, "active", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(settleOrderReviewQuery).
+			WithArgs(reviewedAt, 1, "approved", sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.ApproveOrderReview("ORD-1001", 1, reviewedAt, expiresAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "approved", got.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("approve review of an unknown order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRiskRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrderReviewQuery).
+			WithArgs("ORD-1001", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := repo.ApproveOrderReview("ORD-1001", 1, reviewedAt, expiresAt)
+
+		assert.EqualError(t, err, "order review not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRejectOrderReview_gormRepo(t *testing.T) {
+	reviewedAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("reject review and put held stock back", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRiskRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrderReviewQuery).
+			WithArgs("ORD-1001", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reference_id", "status"}).AddRow(3, "ORD-1001", "pending"))
+		mock.ExpectQuery(getReviewHeldQuery).
+			WithArgs(3, "held").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "held"))
+		mock.ExpectExec(updateReservationStatusQuery).
+			WithArgs("active", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 8, true))
+		mock.ExpectQuery(getReservationForUpdateQuery).
+			WithArgs(4, "active", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "holder_id", "product_id", "quantity", "status"}).AddRow(4, "ORD-1001", 1, 2, "active"))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "release", 2, 10, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectExec(updateReservationStatusQuery).
+			WithArgs("released", sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(settleOrderReviewQuery).
+			WithArgs(reviewedAt, 1, "rejected", sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		got, err := repo.RejectOrderReview("ORD-1001", 1, reviewedAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "rejected", got.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("reject review that was already settled", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewRiskRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrderReviewQuery).
+			WithArgs("ORD-1001", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reference_id", "status"}).AddRow(3, "ORD-1001", "approved"))
+		mock.ExpectRollback()
+
+		_, err := repo.RejectOrderReview("ORD-1001", 1, reviewedAt)
+
+		assert.EqualError(t, err, "order review already settled")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpSearchHandler struct {
+	usecase  usecase.SearchUsecase
+	currency usecase.CurrencyUsecase
+}
+
+func NewSearchHandler(usecase usecase.SearchUsecase, currency usecase.CurrencyUsecase) *httpSearchHandler {
+	return &httpSearchHandler{usecase, currency}
+}
+
+func (h *httpSearchHandler) SearchProducts(c echo.Context) error {
+	searchRequest := new(entities.SearchRequest)
+
+	if err := request.ContextWrapper(c).Bind(searchRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	results, err := h.usecase.SearchProducts(searchRequest)
+	if err != nil {
+		switch err.Error() {
+		case "keyword is required", "invalid price range":
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		default:
+			log.Printf("unexpected error: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+	}
+
+	products := make([]*entities.ProductResponse, len(results.Results))
+	for i := range results.Results {
+		products[i] = &results.Results[i].ProductResponse
+	}
+
+	if err := localize(c, h.currency, products...); err != nil {
+		return currencyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
+
+func (h *httpSearchHandler) Autocomplete(c echo.Context) error {
+	autocompleteRequest := new(entities.AutocompleteRequest)
+
+	if err := request.ContextWrapper(c).Bind(autocompleteRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	suggestions, err := h.usecase.Autocomplete(autocompleteRequest)
+	if err != nil {
+		switch err.Error() {
+		case "query is required":
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		default:
+			log.Printf("unexpected error: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, suggestions)
+}
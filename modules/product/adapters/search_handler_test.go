@@ -0,0 +1,211 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchProducts(t *testing.T) {
+	t.Run("search products successfully", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SearchProducts", &entities.SearchRequest{Keyword: "Dimoo star", Page: 2, PageSize: 1, CategoryIDs: []uint{3, 4}, Availability: "in_stock"}).Return(&entities.SearchResponse{
+			Results: []entities.SearchResult{
+				{
+					ProductResponse: entities.ProductResponse{ID: 13, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night'.", Price: money.New(4999, "THB")},
+					Rank:            0.5,
+					Snippet:         "<mark>Dimoo</mark> inspired by Van Gogh's '<mark>Starry</mark> Night'.",
+				},
+			},
+			Facets: entities.SearchFacets{
+				Categories:   []entities.FacetCount{{Value: "3", Label: "Figures", Count: 2, Selected: true}, {Value: "4", Label: "Plush", Selected: true}},
+				Artists:      []entities.FacetCount{},
+				Prices:       []entities.FacetCount{{Value: "50-100", Label: "50 to 100", Count: 2}},
+				Availability: []entities.FacetCount{{Value: "in_stock", Label: "In stock", Count: 2, Selected: true}},
+				Editions:     []entities.FacetCount{{Value: "regular", Label: "Regular", Count: 2}},
+			},
+			Page: 2, PageSize: 1, Total: 2,
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/search?keyword=Dimoo+star&page=2&page_size=1&category_id=3&category_id=4&availability=in_stock", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.SearchProducts(c)
+
+		expectedJSON := `{"results":[{"id":13,"name":"Dimoo Starry Night","description":"Dimoo inspired by Van Gogh's 'Starry Night'.","price":{"amount":4999,"currency":"THB"},"image_url":"",
+			"rank":0.5,"snippet":"<mark>Dimoo</mark> inspired by Van Gogh's '<mark>Starry</mark> Night'."}],
+			"facets":{"categories":[{"value":"3","label":"Figures","count":2,"selected":true},{"value":"4","label":"Plush","count":0,"selected":true}],"artists":[],
+			"prices":[{"value":"50-100","label":"50 to 100","count":2,"selected":false}],"availability":[{"value":"in_stock","label":"In stock","count":2,"selected":true}],
+			"editions":[{"value":"regular","label":"Regular","count":2,"selected":false}]},
+			"page":2,"page_size":1,"total":2}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("search products given empty keyword", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SearchProducts", &entities.SearchRequest{}).Return((*entities.SearchResponse)(nil), errors.New("keyword is required"))
+
+		request := httptest.NewRequest(http.MethodGet, "/search", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.SearchProducts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"keyword is required"}`, response.Body.String())
+	})
+
+	t.Run("search products given page size over limit", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodGet, "/search?keyword=dimoo&page_size=500", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.SearchProducts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "SearchProducts", mock.Anything)
+	})
+
+	t.Run("search products given unknown edition", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodGet, "/search?keyword=dimoo&edition=limited", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.SearchProducts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "SearchProducts", mock.Anything)
+	})
+
+	t.Run("search products given unknown price range", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SearchProducts", mock.Anything).Return((*entities.SearchResponse)(nil), errors.New("invalid price range"))
+
+		request := httptest.NewRequest(http.MethodGet, "/search?keyword=dimoo&price=cheap", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.SearchProducts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"invalid price range"}`, response.Body.String())
+	})
+
+	t.Run("search products given error", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SearchProducts", mock.Anything).Return((*entities.SearchResponse)(nil), errors.New("database error"))
+
+		request := httptest.NewRequest(http.MethodGet, "/search?keyword=dimoo", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.SearchProducts(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
+
+func TestAutocomplete(t *testing.T) {
+	t.Run("autocomplete successfully", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Autocomplete", &entities.AutocompleteRequest{Query: "labubo"}).Return([]entities.SuggestionResponse{
+			{Kind: "series", ID: 5, Name: "Labubu The Monsters"},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodGet, "/search/autocomplete?q=labubo", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.Autocomplete(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `[{"kind":"series","id":5,"name":"Labubu The Monsters"}]`, response.Body.String())
+	})
+
+	t.Run("autocomplete given empty query", func(t *testing.T) {
+		mockService := new(MockSearchUsecase)
+		handler := &httpSearchHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Autocomplete", &entities.AutocompleteRequest{}).Return(([]entities.SuggestionResponse)(nil), errors.New("query is required"))
+
+		request := httptest.NewRequest(http.MethodGet, "/search/autocomplete", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.Autocomplete(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"query is required"}`, response.Body.String())
+	})
+}
+
+type MockSearchUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSearchUsecase) SearchProducts(request *entities.SearchRequest) (*entities.SearchResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.SearchResponse), args.Error(1)
+}
+
+func (m *MockSearchUsecase) Autocomplete(request *entities.AutocompleteRequest) ([]entities.SuggestionResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).([]entities.SuggestionResponse), args.Error(1)
+}
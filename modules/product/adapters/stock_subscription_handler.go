@@ -0,0 +1,79 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpStockSubscriptionHandler struct {
+	usecase usecase.StockSubscriptionUsecase
+}
+
+func NewStockSubscriptionHandler(usecase usecase.StockSubscriptionUsecase) *httpStockSubscriptionHandler {
+	return &httpStockSubscriptionHandler{usecase}
+}
+
+func (h *httpStockSubscriptionHandler) Subscribe(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	subscriptionRequest := new(entities.StockSubscriptionRequest)
+	if err := request.ContextWrapper(c).Bind(subscriptionRequest); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	subscription, err := h.usecase.Subscribe(userID, subscriptionRequest)
+	if err != nil {
+		return stockSubscriptionError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *httpStockSubscriptionHandler) GetSubscriptions(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	subscriptions, err := h.usecase.GetSubscriptions(userID)
+	if err != nil {
+		return stockSubscriptionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *httpStockSubscriptionHandler) Unsubscribe(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	if err := h.usecase.Unsubscribe(userID, c.Param("id")); err != nil {
+		return stockSubscriptionError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func stockSubscriptionError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "product not found", "variant not found", "subscription not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "blind box stock is set by its figures":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "product is in stock":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,140 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Run("subscribe successfully", func(t *testing.T) {
+		mockService := new(MockStockSubscriptionUsecase)
+		handler := &httpStockSubscriptionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Subscribe", uint(12), &entities.StockSubscriptionRequest{ProductID: 1, Email: "fan@example.com"}).
+			Return(&entities.StockSubscription{ID: 5, ProductID: 1, UserID: 12, Email: "fan@example.com"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"email":"fan@example.com"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Subscribe(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("subscribe given invalid email", func(t *testing.T) {
+		mockService := new(MockStockSubscriptionUsecase)
+		handler := &httpStockSubscriptionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"email":"not-an-email"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Subscribe(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+	})
+
+	t.Run("subscribe given product in stock", func(t *testing.T) {
+		mockService := new(MockStockSubscriptionUsecase)
+		handler := &httpStockSubscriptionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Subscribe", uint(12), mock.Anything).Return((*entities.StockSubscription)(nil), errors.New("product is in stock"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"email":"fan@example.com"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Subscribe(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("subscribe without a user", func(t *testing.T) {
+		mockService := new(MockStockSubscriptionUsecase)
+		handler := &httpStockSubscriptionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"product_id":1,"email":"fan@example.com"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.Subscribe(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+	})
+}
+
+func TestUnsubscribe(t *testing.T) {
+	t.Run("unsubscribe given unknown subscription", func(t *testing.T) {
+		mockService := new(MockStockSubscriptionUsecase)
+		handler := &httpStockSubscriptionHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("Unsubscribe", uint(12), "5").Return(errors.New("subscription not found"))
+
+		request := httptest.NewRequest(http.MethodDelete, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("5")
+		c.Set(ContextUserIDKey, uint(12))
+
+		err := handler.Unsubscribe(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockStockSubscriptionUsecase struct {
+	mock.Mock
+}
+
+func (m *MockStockSubscriptionUsecase) Subscribe(userID uint, request *entities.StockSubscriptionRequest) (*entities.StockSubscription, error) {
+	args := m.Called(userID, request)
+	return args.Get(0).(*entities.StockSubscription), args.Error(1)
+}
+
+func (m *MockStockSubscriptionUsecase) GetSubscriptions(userID uint) ([]entities.StockSubscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entities.StockSubscription), args.Error(1)
+}
+
+func (m *MockStockSubscriptionUsecase) Unsubscribe(userID uint, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,116 @@
+package adapters
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormStockSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewStockSubscriptionRepository(db *gorm.DB) usecase.StockSubscriptionRepository {
+	return &gormStockSubscriptionRepository{db}
+}
+
+func (r *gormStockSubscriptionRepository) InsertSubscription(subscription *entities.StockSubscription) (*entities.StockSubscription, error) {
+	if err := r.db.Create(subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *gormStockSubscriptionRepository) GetSubscription(userID, productID uint, variantID *uint) (*entities.StockSubscription, error) {
+	subscription := &entities.StockSubscription{}
+
+	if err := r.db.Where(map[string]any{"user_id": userID, "product_id": productID, "variant_id": variantID}).First(subscription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("subscription not found")
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *gormStockSubscriptionRepository) GetSubscriptionsByUser(userID uint) ([]entities.StockSubscription, error) {
+	subscriptions := []entities.StockSubscription{}
+
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *gormStockSubscriptionRepository) DeleteSubscription(userID uint, id string) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entities.StockSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("subscription not found")
+	}
+
+	return nil
+}
+
+func (r *gormStockSubscriptionRepository) GetPendingNotifications(limit int) ([]entities.StockNotification, error) {
+	var notifications []entities.StockNotification
+
+	if err := r.db.Where("sent_at IS NULL").Order("id").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *gormStockSubscriptionRepository) MarkNotificationsSent(ids []uint, sentAt time.Time) error {
+	return r.db.Model(&entities.StockNotification{}).Where("id IN ?", ids).Update("sent_at", sentAt).Error
+}
+
+// queueBackInStock turns every subscription for an item that has just come
+// back into stock into a queued notification, oldest subscription first, and
+// removes the subscriptions. A nil variantID means product-level subscriptions.
+func queueBackInStock(tx *gorm.DB, productID uint, variantID *uint) error {
+	var subscriptions []entities.StockSubscription
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(map[string]any{"product_id": productID, "variant_id": variantID}).
+		Order("id").
+		Find(&subscriptions).Error; err != nil {
+		return errors.New("failed to retrieve stock subscriptions")
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	notifications := make([]entities.StockNotification, len(subscriptions))
+	ids := make([]uint, len(subscriptions))
+	for i, subscription := range subscriptions {
+		notifications[i] = entities.StockNotification{
+			ProductID: subscription.ProductID,
+			VariantID: subscription.VariantID,
+			UserID:    subscription.UserID,
+			Email:     subscription.Email,
+		}
+		ids[i] = subscription.ID
+	}
+
+	if err := tx.Create(&notifications).Error; err != nil {
+		return errors.New("failed to queue stock notifications")
+	}
+
+	if err := tx.Delete(&entities.StockSubscription{}, ids).Error; err != nil {
+		return errors.New("failed to remove stock subscriptions")
+	}
+
+	return nil
+}
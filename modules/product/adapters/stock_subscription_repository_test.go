@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getStockSubscriptionQuery    = `SELECT * FROM "stock_subscriptions" WHERE "product_id" = $1 AND "user_id" = $2 AND "variant_id" = $3 ORDER BY "stock_subscriptions"."id" LIMIT $4`
+	deleteStockSubscriptionQuery = `DELETE FROM "stock_subscriptions" WHERE id = $1 AND user_id = $2`
+	getPendingNotificationsQuery = `SELECT * FROM "stock_notifications" WHERE sent_at IS NULL ORDER BY id LIMIT $1`
+)
+
+func TestGetSubscription_gormRepo(t *testing.T) {
+	t.Run("get variant subscription", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewStockSubscriptionRepository(gormDB)
+
+		variantID := uint(3)
+
+		mock.ExpectQuery(getStockSubscriptionQuery).
+			WithArgs(1, 12, 3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "variant_id", "user_id", "email"}).AddRow(5, 1, 3, 12, "fan@example.com"))
+
+		got, err := repo.GetSubscription(12, 1, &variantID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), got.ID)
+	})
+
+	t.Run("get subscription given none", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewStockSubscriptionRepository(gormDB)
+
+		variantID := uint(3)
+
+		mock.ExpectQuery(getStockSubscriptionQuery).
+			WithArgs(1, 12, 3, 1).
+			WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetSubscription(12, 1, &variantID)
+
+		assert.EqualError(t, err, "subscription not found")
+	})
+}
+
+func TestDeleteSubscription_gormRepo(t *testing.T) {
+	t.Run("delete another user's subscription", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewStockSubscriptionRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(deleteStockSubscriptionQuery).
+			WithArgs("5", 40).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.DeleteSubscription(40, "5")
+
+		assert.EqualError(t, err, "subscription not found")
+	})
+}
+
+func TestGetPendingNotifications_gormRepo(t *testing.T) {
+	t.Run("get pending notifications in queue order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewStockSubscriptionRepository(gormDB)
+
+		mock.ExpectQuery(getPendingNotificationsQuery).
+			WithArgs(100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "user_id", "email"}).
+				AddRow(1, 1, 12, "first@example.com").
+				AddRow(2, 1, 40, "second@example.com"))
+
+		got, err := repo.GetPendingNotifications(100)
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, "first@example.com", got[0].Email)
+	})
+}
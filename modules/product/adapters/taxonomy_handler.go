@@ -0,0 +1,160 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpTaxonomyHandler struct {
+	usecase usecase.TaxonomyUsecase
+}
+
+func NewTaxonomyHandler(usecase usecase.TaxonomyUsecase) *httpTaxonomyHandler {
+	return &httpTaxonomyHandler{usecase}
+}
+
+func (h *httpTaxonomyHandler) CreateCategory(c echo.Context) error {
+	category := new(entities.Category)
+
+	if err := request.ContextWrapper(c).Bind(category); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newCategory, err := h.usecase.CreateCategory(category)
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newCategory)
+}
+
+func (h *httpTaxonomyHandler) GetCategoryTree(c echo.Context) error {
+	categories, err := h.usecase.GetCategoryTree()
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, categories)
+}
+
+func (h *httpTaxonomyHandler) GetCategoryById(c echo.Context) error {
+	category, err := h.usecase.GetCategoryById(c.Param("id"))
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, category)
+}
+
+func (h *httpTaxonomyHandler) UpdateCategory(c echo.Context) error {
+	category := new(entities.Category)
+
+	if err := request.ContextWrapper(c).Bind(category); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	categoryUpdated, err := h.usecase.UpdateCategory(category, c.Param("id"))
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, categoryUpdated)
+}
+
+func (h *httpTaxonomyHandler) DeleteCategory(c echo.Context) error {
+	if err := h.usecase.DeleteCategory(c.Param("id")); err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpTaxonomyHandler) CreateSeries(c echo.Context) error {
+	series := new(entities.Series)
+
+	if err := request.ContextWrapper(c).Bind(series); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newSeries, err := h.usecase.CreateSeries(series)
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newSeries)
+}
+
+func (h *httpTaxonomyHandler) GetAllSeries(c echo.Context) error {
+	series, err := h.usecase.GetAllSeries()
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, series)
+}
+
+func (h *httpTaxonomyHandler) GetSeriesById(c echo.Context) error {
+	series, err := h.usecase.GetSeriesById(c.Param("id"))
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, series)
+}
+
+func (h *httpTaxonomyHandler) UpdateSeries(c echo.Context) error {
+	series := new(entities.Series)
+
+	if err := request.ContextWrapper(c).Bind(series); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	seriesUpdated, err := h.usecase.UpdateSeries(series, c.Param("id"))
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, seriesUpdated)
+}
+
+func (h *httpTaxonomyHandler) DeleteSeries(c echo.Context) error {
+	if err := h.usecase.DeleteSeries(c.Param("id")); err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *httpTaxonomyHandler) SetProductCategories(c echo.Context) error {
+	categories := new(entities.ProductCategories)
+
+	if err := request.ContextWrapper(c).Bind(categories); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	product, err := h.usecase.SetProductCategories(c.Param("id"), categories)
+	if err != nil {
+		return taxonomyError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, product)
+}
+
+func taxonomyError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "category not found", "series not found", "product not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "parent category not found", "category cannot be moved under itself":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "category has subcategories":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
@@ -0,0 +1,224 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateCategory(t *testing.T) {
+	t.Run("create category successfully", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateCategory", mock.AnythingOfType("*entities.Category")).Return(&entities.CategoryResponse{ID: 1, Name: "Pop Mart", Slug: "pop-mart"}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Pop Mart"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCategory(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"id":1,"name":"Pop Mart","slug":"pop-mart","description":"","parent_id":null}`, response.Body.String())
+	})
+
+	t.Run("create category given invalid name", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":""}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCategory(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "CreateCategory", mock.Anything)
+	})
+
+	t.Run("create category given missing parent", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateCategory", mock.AnythingOfType("*entities.Category")).Return((*entities.CategoryResponse)(nil), errors.New("parent category not found"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Labubu","parent_id":9}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateCategory(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"parent category not found"}`, response.Body.String())
+	})
+}
+
+func TestDeleteCategory(t *testing.T) {
+	t.Run("delete category given subcategories", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteCategory", "1").Return(errors.New("category has subcategories"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		err := handler.DeleteCategory(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("delete category given category not found", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("DeleteCategory", "30").Return(errors.New("category not found"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), response)
+		c.SetParamNames("id")
+		c.SetParamValues("30")
+
+		err := handler.DeleteCategory(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestSetProductCategories(t *testing.T) {
+	t.Run("set product categories successfully", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("SetProductCategories", "12", &entities.ProductCategories{CategoryIDs: []uint{2, 3}}).Return(&entities.ProductResponse{
+			ID: 12, Name: "Molly Classic", Categories: []entities.CategorySummary{{ID: 2, Name: "Pop Mart", Slug: "pop-mart"}, {ID: 3, Name: "Molly", Slug: "molly"}},
+		}, nil)
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"category_ids":[2,3]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+
+		err := handler.SetProductCategories(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("set product categories given invalid category id", func(t *testing.T) {
+		mockService := new(MockTaxonomyUsecase)
+		handler := &httpTaxonomyHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"category_ids":[0]}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("12")
+
+		err := handler.SetProductCategories(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+type MockTaxonomyUsecase struct {
+	mock.Mock
+}
+
+func (m *MockTaxonomyUsecase) CreateCategory(category *entities.Category) (*entities.CategoryResponse, error) {
+	args := m.Called(category)
+	return args.Get(0).(*entities.CategoryResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) GetCategoryTree() ([]entities.CategoryResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.CategoryResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) GetCategoryById(id string) (*entities.CategoryResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.CategoryResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) UpdateCategory(category *entities.Category, id string) (*entities.CategoryResponse, error) {
+	args := m.Called(category, id)
+	return args.Get(0).(*entities.CategoryResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) DeleteCategory(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaxonomyUsecase) CreateSeries(series *entities.Series) (*entities.SeriesResponse, error) {
+	args := m.Called(series)
+	return args.Get(0).(*entities.SeriesResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) GetAllSeries() ([]entities.SeriesResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.SeriesResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) GetSeriesById(id string) (*entities.SeriesResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.SeriesResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) UpdateSeries(series *entities.Series, id string) (*entities.SeriesResponse, error) {
+	args := m.Called(series, id)
+	return args.Get(0).(*entities.SeriesResponse), args.Error(1)
+}
+
+func (m *MockTaxonomyUsecase) DeleteSeries(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaxonomyUsecase) SetProductCategories(productID string, categories *entities.ProductCategories) (*entities.ProductResponse, error) {
+	args := m.Called(productID, categories)
+	return args.Get(0).(*entities.ProductResponse), args.Error(1)
+}
@@ -0,0 +1,155 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+)
+
+type gormTaxonomyRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxonomyRepository(db *gorm.DB) usecase.TaxonomyRepository {
+	return &gormTaxonomyRepository{db}
+}
+
+func (r *gormTaxonomyRepository) InsertCategory(category *entities.Category) (*entities.Category, error) {
+	if result := r.db.Create(category); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return category, nil
+}
+
+func (r *gormTaxonomyRepository) GetAllCategories() ([]entities.Category, error) {
+	var categories []entities.Category
+
+	if err := r.db.Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *gormTaxonomyRepository) GetCategoryById(id string) (*entities.Category, error) {
+	category := new(entities.Category)
+
+	if err := r.db.First(category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (r *gormTaxonomyRepository) UpdateCategory(category *entities.Category, id string) (*entities.Category, error) {
+	if result := r.db.Model(&entities.Category{}).
+		Where("id = ?", id).
+		Select("name", "slug", "description", "parent_id").
+		Updates(category); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return category, nil
+}
+
+func (r *gormTaxonomyRepository) DeleteCategory(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM product_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entities.Category{}, id).Error
+	})
+}
+
+func (r *gormTaxonomyRepository) CountChildCategories(id uint) (int64, error) {
+	var count int64
+
+	if err := r.db.Model(&entities.Category{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *gormTaxonomyRepository) InsertSeries(series *entities.Series) (*entities.Series, error) {
+	if result := r.db.Create(series); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return series, nil
+}
+
+func (r *gormTaxonomyRepository) GetAllSeries() ([]entities.Series, error) {
+	var series []entities.Series
+
+	if err := r.db.Order("name").Find(&series).Error; err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (r *gormTaxonomyRepository) GetSeriesById(id string) (*entities.Series, error) {
+	series := new(entities.Series)
+
+	if err := r.db.Preload("Products", listedProducts).First(series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (r *gormTaxonomyRepository) UpdateSeries(series *entities.Series, id string) (*entities.Series, error) {
+	if result := r.db.Model(&entities.Series{}).
+		Where("id = ?", id).
+		Select("name", "slug", "description").
+		Updates(series); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return series, nil
+}
+
+func (r *gormTaxonomyRepository) DeleteSeries(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Product{}).
+			Where("series_id = ?", id).
+			Update("series_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entities.Series{}, id).Error
+	})
+}
+
+func (r *gormTaxonomyRepository) ReplaceProductCategories(productID uint, categoryIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var categories []entities.Category
+		if len(categoryIDs) > 0 {
+			if err := tx.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+				return err
+			}
+
+			if len(categories) != len(categoryIDs) {
+				return errors.New("category not found")
+			}
+		}
+
+		product := &entities.Product{Model: gorm.Model{ID: productID}}
+		if len(categories) == 0 {
+			return tx.Model(product).Association("Categories").Clear()
+		}
+
+		return tx.Model(product).Association("Categories").Replace(categories)
+	})
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getCategoryByIdQuery       = `SELECT * FROM "categories" WHERE "categories"."id" = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2`
+	countChildCategoriesQuery  = `SELECT count(*) FROM "categories" WHERE parent_id = $1 AND "categories"."deleted_at" IS NULL`
+	findCategoriesByIdsQuery   = `SELECT * FROM "categories" WHERE id IN ($1,$2) AND "categories"."deleted_at" IS NULL`
+	detachProductCategoryQuery = `DELETE FROM product_categories WHERE category_id = $1`
+	deleteCategoryQuery        = `UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`
+	detachSeriesProductsQuery  = `UPDATE "products" SET "series_id"=$1,"updated_at"=$2 WHERE series_id = $3 AND "products"."deleted_at" IS NULL`
+	deleteSeriesQuery          = `UPDATE "series" SET "deleted_at"=$1 WHERE "series"."id" = $2 AND "series"."deleted_at" IS NULL`
+)
+
+func TestGetCategoryById_gormRepo(t *testing.T) {
+	t.Run("get category successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewTaxonomyRepository(gormDB)
+
+		rows := sqlmock.NewRows([]string{"id", "name", "slug", "parent_id"}).AddRow(2, "Pop Mart", "pop-mart", 1)
+		mock.ExpectQuery(getCategoryByIdQuery).WithArgs("2", 1).WillReturnRows(rows)
+
+		got, err := repo.GetCategoryById("2")
+
+		parentID := uint(1)
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.Category{Model: gorm.Model{ID: 2}, Name: "Pop Mart", Slug: "pop-mart", ParentID: &parentID}, got)
+	})
+
+	t.Run("get category given category not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewTaxonomyRepository(gormDB)
+
+		mock.ExpectQuery(getCategoryByIdQuery).WithArgs("30", 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		_, err := repo.GetCategoryById("30")
+
+		assert.EqualError(t, err, "category not found")
+	})
+}
+
+func TestCountChildCategories_gormRepo(t *testing.T) {
+	t.Run("count child categories successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewTaxonomyRepository(gormDB)
+
+		mock.ExpectQuery(countChildCategoriesQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		got, err := repo.CountChildCategories(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), got)
+	})
+}
+
+func TestDeleteCategory_gormRepo(t *testing.T) {
+	t.Run("delete category and detach its products", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewTaxonomyRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(detachProductCategoryQuery).WithArgs("3").WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec(deleteCategoryQuery).WithArgs(sqlmock.AnyArg(), "3").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteCategory("3")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteSeries_gormRepo(t *testing.T) {
+	t.Run("delete series and unlink its products", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewTaxonomyRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(detachSeriesProductsQuery).WithArgs(nil, sqlmock.AnyArg(), "5").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteSeriesQuery).WithArgs(sqlmock.AnyArg(), "5").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteSeries("5")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReplaceProductCategories_gormRepo(t *testing.T) {
+	t.Run("replace product categories given unknown category", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewTaxonomyRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(findCategoriesByIdsQuery).WithArgs(2, 40).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug"}).AddRow(2, "Pop Mart", "pop-mart"))
+		mock.ExpectRollback()
+
+		err := repo.ReplaceProductCategories(12, []uint{2, 40})
+
+		assert.EqualError(t, err, "category not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
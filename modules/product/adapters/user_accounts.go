@@ -0,0 +1,31 @@
+package adapters
+
+import (
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	userUsecase "github.com/phetployst/art-toys-store/modules/user/usecase"
+)
+
+// userAccounts serves the product module's Accounts from the user service's
+// usecase.
+type userAccounts struct {
+	users userUsecase.UserUsecase
+}
+
+func NewUserAccounts(users userUsecase.UserUsecase) usecase.Accounts {
+	return &userAccounts{users}
+}
+
+func (a *userAccounts) GetAccount(userID uint) (*entities.Account, error) {
+	status, err := a.users.GetAccountStatus(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Account{
+		UserID:          status.UserID,
+		CreatedAt:       status.CreatedAt,
+		EmailVerified:   status.EmailVerified,
+		ShippingAddress: status.ShippingAddress,
+	}, nil
+}
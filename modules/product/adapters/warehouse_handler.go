@@ -0,0 +1,107 @@
+package adapters
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"github.com/phetployst/art-toys-store/pkg/request"
+)
+
+type httpWarehouseHandler struct {
+	usecase usecase.WarehouseUsecase
+}
+
+func NewWarehouseHandler(usecase usecase.WarehouseUsecase) *httpWarehouseHandler {
+	return &httpWarehouseHandler{usecase}
+}
+
+func (h *httpWarehouseHandler) CreateWarehouse(c echo.Context) error {
+	warehouse := new(entities.WarehouseRequest)
+	if err := request.ContextWrapper(c).Bind(warehouse); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	newWarehouse, err := h.usecase.CreateWarehouse(warehouse)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newWarehouse)
+}
+
+func (h *httpWarehouseHandler) GetAllWarehouses(c echo.Context) error {
+	warehouses, err := h.usecase.GetAllWarehouses()
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, warehouses)
+}
+
+func (h *httpWarehouseHandler) GetWarehouseById(c echo.Context) error {
+	warehouse, err := h.usecase.GetWarehouseById(c.Param("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, warehouse)
+}
+
+func (h *httpWarehouseHandler) UpdateWarehouse(c echo.Context) error {
+	warehouse := new(entities.WarehouseRequest)
+	if err := request.ContextWrapper(c).Bind(warehouse); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	warehouseUpdated, err := h.usecase.UpdateWarehouse(warehouse, c.Param("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, warehouseUpdated)
+}
+
+func (h *httpWarehouseHandler) GetStockLevels(c echo.Context) error {
+	levels, err := h.usecase.GetStockLevels(c.Param("id"))
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, levels)
+}
+
+func (h *httpWarehouseHandler) TransferStock(c echo.Context) error {
+	actorID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || actorID == 0 {
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{Message: "invalid user ID in token"})
+	}
+
+	transfer := new(entities.StockTransferRequest)
+	if err := request.ContextWrapper(c).Bind(transfer); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	}
+
+	movements, err := h.usecase.TransferStock(c.Param("id"), actorID, transfer)
+	if err != nil {
+		return warehouseError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, movements)
+}
+
+func warehouseError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "warehouse not found", "product not found", "variant not found":
+		return c.JSON(http.StatusNotFound, ErrorResponse{Message: err.Error()})
+	case "variant required", "blind box stock is set by its figures":
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+	case "warehouse code already exists", "insufficient stock":
+		return c.JSON(http.StatusConflict, ErrorResponse{Message: err.Error()})
+	default:
+		log.Printf("unexpected error: %v", err)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Message: "internal server error"})
+	}
+}
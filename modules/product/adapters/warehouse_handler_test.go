@@ -0,0 +1,164 @@
+package adapters
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWarehouse(t *testing.T) {
+	t.Run("create warehouse successfully", func(t *testing.T) {
+		mockService := new(MockWarehouseUsecase)
+		handler := &httpWarehouseHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateWarehouse", &entities.WarehouseRequest{Code: "BKK", Name: "Bangkok warehouse", Priority: 1}).
+			Return(&entities.WarehouseResponse{ID: 1, Code: "BKK", Name: "Bangkok warehouse", Priority: 1, Active: true}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"BKK","name":"Bangkok warehouse","priority":1}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateWarehouse(c)
+
+		expectedJSON := `{"id":1,"code":"BKK","name":"Bangkok warehouse","address":"","priority":1,"active":true}`
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, expectedJSON, response.Body.String())
+	})
+
+	t.Run("create warehouse given duplicate code", func(t *testing.T) {
+		mockService := new(MockWarehouseUsecase)
+		handler := &httpWarehouseHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("CreateWarehouse", mock.Anything).Return((*entities.WarehouseResponse)(nil), errors.New("warehouse code already exists"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"BKK","name":"Bangkok warehouse"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.CreateWarehouse(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestTransferStock(t *testing.T) {
+	t.Run("transfer stock successfully", func(t *testing.T) {
+		mockService := new(MockWarehouseUsecase)
+		handler := &httpWarehouseHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("TransferStock", "1", uint(7), &entities.StockTransferRequest{FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4}).
+			Return([]entities.StockMovement{{ID: 50, Kind: "transfer", Quantity: -4}, {ID: 51, Kind: "transfer", Quantity: 4}}, nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"from_warehouse_id":1,"to_warehouse_id":2,"quantity":4}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.TransferStock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+
+	t.Run("transfer to the same warehouse", func(t *testing.T) {
+		mockService := new(MockWarehouseUsecase)
+		handler := &httpWarehouseHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"from_warehouse_id":1,"to_warehouse_id":1,"quantity":4}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.TransferStock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "TransferStock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer given unknown warehouse", func(t *testing.T) {
+		mockService := new(MockWarehouseUsecase)
+		handler := &httpWarehouseHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("TransferStock", "1", uint(7), mock.Anything).Return(([]entities.StockMovement)(nil), errors.New("warehouse not found"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"from_warehouse_id":1,"to_warehouse_id":9,"quantity":4}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set(ContextUserIDKey, uint(7))
+
+		err := handler.TransferStock(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+type MockWarehouseUsecase struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseUsecase) CreateWarehouse(request *entities.WarehouseRequest) (*entities.WarehouseResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(*entities.WarehouseResponse), args.Error(1)
+}
+
+func (m *MockWarehouseUsecase) GetAllWarehouses() ([]entities.WarehouseResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.WarehouseResponse), args.Error(1)
+}
+
+func (m *MockWarehouseUsecase) GetWarehouseById(id string) (*entities.WarehouseResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.WarehouseResponse), args.Error(1)
+}
+
+func (m *MockWarehouseUsecase) UpdateWarehouse(request *entities.WarehouseRequest, id string) (*entities.WarehouseResponse, error) {
+	args := m.Called(request, id)
+	return args.Get(0).(*entities.WarehouseResponse), args.Error(1)
+}
+
+func (m *MockWarehouseUsecase) GetStockLevels(productID string) (*entities.ProductStockLevels, error) {
+	args := m.Called(productID)
+	return args.Get(0).(*entities.ProductStockLevels), args.Error(1)
+}
+
+func (m *MockWarehouseUsecase) TransferStock(productID string, actorID uint, request *entities.StockTransferRequest) ([]entities.StockMovement, error) {
+	args := m.Called(productID, actorID, request)
+	return args.Get(0).([]entities.StockMovement), args.Error(1)
+}
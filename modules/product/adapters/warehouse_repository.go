@@ -0,0 +1,281 @@
+package adapters
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/modules/product/usecase"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormWarehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) usecase.WarehouseRepository {
+	return &gormWarehouseRepository{db}
+}
+
+func (r *gormWarehouseRepository) InsertWarehouse(warehouse *entities.Warehouse) (*entities.Warehouse, error) {
+	if result := r.db.Create(warehouse); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return warehouse, nil
+}
+
+func (r *gormWarehouseRepository) GetAllWarehouses() ([]entities.Warehouse, error) {
+	var warehouses []entities.Warehouse
+
+	if err := r.db.Order("priority, id").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+
+	return warehouses, nil
+}
+
+func (r *gormWarehouseRepository) GetWarehouseById(id string) (*entities.Warehouse, error) {
+	warehouse := new(entities.Warehouse)
+
+	if err := r.db.First(warehouse, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("warehouse not found")
+		}
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (r *gormWarehouseRepository) GetWarehouseByCode(code string) (*entities.Warehouse, error) {
+	warehouse := new(entities.Warehouse)
+
+	if err := r.db.Where("code = ?", code).First(warehouse).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("warehouse not found")
+		}
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+func (r *gormWarehouseRepository) UpdateWarehouse(warehouse *entities.Warehouse) (*entities.Warehouse, error) {
+	if result := r.db.Model(warehouse).
+		Select("code", "name", "address", "priority", "active").
+		Updates(warehouse); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return warehouse, nil
+}
+
+func (r *gormWarehouseRepository) GetStockLevels(productID uint) ([]entities.WarehouseStockLevel, error) {
+	levels := []entities.WarehouseStockLevel{}
+
+	if err := r.db.Model(&entities.WarehouseStock{}).
+		Select("warehouse_stocks.warehouse_id, warehouses.code, warehouses.name, warehouse_stocks.variant_id, warehouse_stocks.stock").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.deleted_at IS NULL").
+		Where("warehouse_stocks.product_id = ?", productID).
+		Order("warehouses.priority, warehouses.id, warehouse_stocks.variant_id NULLS FIRST").
+		Scan(&levels).Error; err != nil {
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// TransferStock moves units between two warehouses and records both legs in
+// the ledger. The item's aggregate stock is unchanged, so both carry the same
+// balance.
+func (r *gormWarehouseRepository) TransferStock(transfer *entities.StockTransfer) ([]entities.StockMovement, error) {
+	movements := []entities.StockMovement{}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		product, err := lockProduct(tx, transfer.ProductID)
+		if err != nil {
+			return err
+		}
+
+		balance, err := itemStock(tx, product, transfer.VariantID)
+		if err != nil {
+			return err
+		}
+
+		legs := []entities.StockAllocation{
+			{WarehouseID: transfer.FromWarehouseID, Quantity: -transfer.Quantity},
+			{WarehouseID: transfer.ToWarehouseID, Quantity: transfer.Quantity},
+		}
+		for _, leg := range legs {
+			if err := moveWarehouseStock(tx, leg.WarehouseID, product.ID, transfer.VariantID, leg.Quantity); err != nil {
+				return err
+			}
+
+			warehouseID := leg.WarehouseID
+			movement := entities.StockMovement{
+				ProductID:   product.ID,
+				VariantID:   transfer.VariantID,
+				WarehouseID: &warehouseID,
+				Kind:        entities.StockMovementTransfer,
+				Quantity:    leg.Quantity,
+				Balance:     balance,
+				ActorID:     transfer.ActorID,
+				ReferenceID: transfer.ReferenceID,
+				Note:        transfer.Note,
+			}
+			if err := tx.Create(&movement).Error; err != nil {
+				return errors.New("failed to record stock movement")
+			}
+
+			movements = append(movements, movement)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// locateStockMovement applies a movement to the warehouse it names and returns
+// the ledger entries to record for it. Sales that name no warehouse are
+// fulfilled by the allocation rule and may span several; whatever the
+// warehouses cannot cover comes out of unassigned stock. Reservations only hold
+// stock and never touch a warehouse.
+func locateStockMovement(tx *gorm.DB, movement *entities.StockMovement) ([]entities.StockMovement, error) {
+	switch {
+	case movement.Kind == entities.StockMovementReservation || movement.Kind == entities.StockMovementRelease:
+		return []entities.StockMovement{*movement}, nil
+	case movement.WarehouseID != nil:
+		if err := moveWarehouseStock(tx, *movement.WarehouseID, movement.ProductID, movement.VariantID, movement.Quantity); err != nil {
+			return nil, err
+		}
+		return []entities.StockMovement{*movement}, nil
+	case movement.Kind == entities.StockMovementSale:
+		return fulfilSale(tx, movement)
+	default:
+		return []entities.StockMovement{*movement}, nil
+	}
+}
+
+func fulfilSale(tx *gorm.DB, movement *entities.StockMovement) ([]entities.StockMovement, error) {
+	var levels []entities.WarehouseStock
+	if err := whereWarehouseItem(tx, movement.ProductID, movement.VariantID).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "warehouse_stocks"}}).
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.active AND warehouses.deleted_at IS NULL").
+		Where("warehouse_stocks.stock > 0").
+		Order("warehouses.priority, warehouses.id").
+		Find(&levels).Error; err != nil {
+		return nil, errors.New("failed to retrieve warehouse stock")
+	}
+
+	byWarehouse := make(map[uint]*entities.WarehouseStock, len(levels))
+	for i := range levels {
+		byWarehouse[levels[i].WarehouseID] = &levels[i]
+	}
+
+	entries := []entities.StockMovement{}
+	remaining := -movement.Quantity
+	for _, allocation := range usecase.AllocateStock(levels, remaining) {
+		level := byWarehouse[allocation.WarehouseID]
+		if err := tx.Model(level).Update("stock", level.Stock-allocation.Quantity).Error; err != nil {
+			return nil, errors.New("failed to update warehouse stock")
+		}
+
+		entry := *movement
+		entry.WarehouseID = &level.WarehouseID
+		entry.Quantity = -allocation.Quantity
+		entries = append(entries, entry)
+		remaining -= allocation.Quantity
+	}
+
+	if remaining > 0 {
+		entry := *movement
+		entry.Quantity = -remaining
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// moveWarehouseStock moves a warehouse's stock of an item by quantity, opening
+// its stock level the first time stock arrives there.
+func moveWarehouseStock(tx *gorm.DB, warehouseID, productID uint, variantID *uint, quantity int) error {
+	level := &entities.WarehouseStock{}
+	if err := whereWarehouseItem(tx, productID, variantID).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("warehouse_stocks.warehouse_id = ?", warehouseID).
+		First(level).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to retrieve warehouse stock")
+		}
+
+		var warehouses int64
+		if err := tx.Model(&entities.Warehouse{}).Where("id = ?", warehouseID).Count(&warehouses).Error; err != nil {
+			return errors.New("failed to retrieve warehouse stock")
+		}
+		if warehouses == 0 {
+			return errors.New("warehouse not found")
+		}
+
+		level = &entities.WarehouseStock{WarehouseID: warehouseID, ProductID: productID, VariantID: variantID}
+	}
+
+	stock := level.Stock + quantity
+	if stock < 0 {
+		return errors.New("insufficient stock")
+	}
+
+	if level.ID == 0 {
+		level.Stock = stock
+		if err := tx.Create(level).Error; err != nil {
+			return errors.New("failed to update warehouse stock")
+		}
+		return nil
+	}
+
+	if err := tx.Model(level).Update("stock", stock).Error; err != nil {
+		return errors.New("failed to update warehouse stock")
+	}
+
+	return nil
+}
+
+// itemStock returns the aggregate stock of a locked product, or of one of its
+// variants, locking the variant too.
+func itemStock(tx *gorm.DB, product *entities.Product, variantID *uint) (int, error) {
+	if variantID == nil {
+		var variants int64
+		if err := tx.Model(&entities.ProductVariant{}).Where("product_id = ?", product.ID).Count(&variants).Error; err != nil {
+			return 0, errors.New("failed to retrieve product")
+		}
+		if variants > 0 {
+			return 0, errors.New("variant required")
+		}
+
+		return product.Stock, nil
+	}
+
+	variant := &entities.ProductVariant{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND product_id = ?", *variantID, product.ID).
+		First(variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("variant not found")
+		}
+		return 0, errors.New("failed to retrieve product")
+	}
+
+	return variant.Stock, nil
+}
+
+func whereWarehouseItem(tx *gorm.DB, productID uint, variantID *uint) *gorm.DB {
+	if variantID == nil {
+		return tx.Where("warehouse_stocks.product_id = ? AND warehouse_stocks.variant_id IS NULL", productID)
+	}
+
+	return tx.Where("warehouse_stocks.product_id = ? AND warehouse_stocks.variant_id = ?", productID, *variantID)
+}
@@ -0,0 +1,222 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	getWarehouseByCodeQuery    = `SELECT * FROM "warehouses" WHERE code = $1 AND "warehouses"."deleted_at" IS NULL ORDER BY "warehouses"."id" LIMIT $2`
+	getProductStockLevelsQuery = `SELECT warehouse_stocks.warehouse_id, warehouses.code, warehouses.name, warehouse_stocks.variant_id, warehouse_stocks.stock FROM "warehouse_stocks" JOIN warehouses ON warehouses.id = warehouse_stocks.warehouse_id AND warehouses.deleted_at IS NULL WHERE warehouse_stocks.product_id = $1 ORDER BY warehouses.priority, warehouses.id, warehouse_stocks.variant_id NULLS FIRST`
+	getWarehouseStockQuery     = `SELECT * FROM "warehouse_stocks" WHERE (warehouse_stocks.product_id = $1 AND warehouse_stocks.variant_id IS NULL) AND warehouse_stocks.warehouse_id = $2 ORDER BY "warehouse_stocks"."id" LIMIT $3 FOR UPDATE`
+	countWarehouseQuery        = `SELECT count(*) FROM "warehouses" WHERE id = $1 AND "warehouses"."deleted_at" IS NULL`
+	insertWarehouseStockQuery  = `INSERT INTO "warehouse_stocks" ("updated_at","warehouse_id","product_id","variant_id","stock") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`
+	updateWarehouseStockQuery  = `UPDATE "warehouse_stocks" SET "stock"=$1,"updated_at"=$2 WHERE "id" = $3`
+)
+
+func TestGetWarehouseByCode_gormRepo(t *testing.T) {
+	t.Run("get warehouse by code given not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewWarehouseRepository(gormDB)
+
+		mock.ExpectQuery(getWarehouseByCodeQuery).
+			WithArgs("BKK", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.GetWarehouseByCode("BKK")
+
+		assert.EqualError(t, err, "warehouse not found")
+	})
+}
+
+func TestGetStockLevels_gormRepo(t *testing.T) {
+	t.Run("get stock levels in priority order", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewWarehouseRepository(gormDB)
+
+		mock.ExpectQuery(getProductStockLevelsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "code", "name", "variant_id", "stock"}).
+				AddRow(1, "BKK", "Bangkok warehouse", nil, 12).
+				AddRow(2, "POP-SIAM", "Siam pop-up", nil, 3))
+
+		got, err := repo.GetStockLevels(1)
+
+		want := []entities.WarehouseStockLevel{
+			{WarehouseID: 1, Code: "BKK", Name: "Bangkok warehouse", Stock: 12},
+			{WarehouseID: 2, Code: "POP-SIAM", Name: "Siam pop-up", Stock: 3},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestTransferStock_gormRepo(t *testing.T) {
+	t.Run("transfer stock to a warehouse that has none yet", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewWarehouseRepository(gormDB)
+
+		actorID := uint(7)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 15))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(getWarehouseStockQuery).
+			WithArgs(1, 1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "stock"}).AddRow(10, 1, 1, 12))
+		mock.ExpectExec(updateWarehouseStockQuery).
+			WithArgs(8, sqlmock.AnyArg(), 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, 1, "transfer", -4, 15, 7, "TR-1", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+		mock.ExpectQuery(getWarehouseStockQuery).
+			WithArgs(1, 2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(countWarehouseQuery).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(insertWarehouseStockQuery).
+			WithArgs(sqlmock.AnyArg(), 2, 1, nil, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, 2, "transfer", 4, 15, 7, "TR-1", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(51))
+		mock.ExpectCommit()
+
+		got, err := repo.TransferStock(&entities.StockTransfer{ProductID: 1, FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4, ActorID: &actorID, ReferenceID: "TR-1"})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, uint(1), *got[0].WarehouseID)
+		assert.Equal(t, uint(2), *got[1].WarehouseID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("transfer more than the source warehouse holds", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewWarehouseRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(1, 15))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(getWarehouseStockQuery).
+			WithArgs(1, 2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "stock"}).AddRow(11, 2, 1, 3))
+		mock.ExpectRollback()
+
+		_, err := repo.TransferStock(&entities.StockTransfer{ProductID: 1, FromWarehouseID: 2, ToWarehouseID: 1, Quantity: 4})
+
+		assert.EqualError(t, err, "insufficient stock")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFulfilSale_gormRepo(t *testing.T) {
+	t.Run("split a sale across warehouses when none can ship it whole", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewProductRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs("1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 6, true))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(1, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(fulfilProductSaleQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "product_id", "stock"}).
+				AddRow(10, 1, 1, 3).
+				AddRow(11, 2, 1, 1))
+		mock.ExpectExec(updateWarehouseStockQuery).
+			WithArgs(0, sqlmock.AnyArg(), 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(updateWarehouseStockQuery).
+			WithArgs(0, sqlmock.AnyArg(), 11).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, 1, "sale", -3, 3, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(60))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, 2, "sale", -1, 2, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(61))
+		mock.ExpectQuery(insertStockMovementQuery).
+			WithArgs(sqlmock.AnyArg(), 1, nil, nil, "sale", -1, 1, nil, "ORD-1001", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(62))
+		mock.ExpectCommit()
+
+		newStock, err := repo.UpdateStock("1", 0, 5, "ORD-1001")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, newStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRecordWarehouseMovement_gormRepo(t *testing.T) {
+	t.Run("restock into an unknown warehouse", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewInventoryRepository(gormDB)
+
+		warehouseID := uint(9)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(getProductforUpdateQuery).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock", "active"}).AddRow(1, 6, true))
+		mock.ExpectQuery(countProductVariantsQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(updateStockProductQuery).
+			WithArgs(10, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(getWarehouseStockQuery).
+			WithArgs(1, 9, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery(countWarehouseQuery).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectRollback()
+
+		_, err := repo.RecordMovement(&entities.StockMovement{ProductID: 1, WarehouseID: &warehouseID, Kind: "restock", Quantity: 4})
+
+		assert.EqualError(t, err, "warehouse not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ProductTypeStandard = "standard"
+	ProductTypeBlindBox = "blind_box"
+	// ProductTypeBlindBoxCase is a sealed case of boxes sold as one unit.
+	ProductTypeBlindBoxCase = "blind_box_case"
+)
+
+type (
+	// BlindBoxFigure is one entry in a blind box's pool: a figure from the box's
+	// series, the weight it is drawn with and how many are left to draw.
+	BlindBoxFigure struct {
+		gorm.Model
+		BoxID    uint     `gorm:"not null;index" json:"box_id" validate:"-"`
+		FigureID uint     `gorm:"not null" json:"figure_id" validate:"required,gt=0"`
+		Figure   *Product `gorm:"foreignKey:FigureID" json:"figure,omitempty" validate:"-"`
+		Weight   int      `gorm:"type:int;not null" json:"weight" validate:"required,gt=0"`
+		IsSecret bool     `gorm:"type:boolean;not null;default:false" json:"is_secret"`
+		Stock    int      `gorm:"type:int;not null;default:0" json:"stock" validate:"gte=0"`
+	}
+
+	// BlindBoxSeed is a server seed committed to by publishing its hash. Draws
+	// use it with an increasing nonce until it is rotated and revealed.
+	BlindBoxSeed struct {
+		gorm.Model
+		BoxID      uint       `gorm:"not null;index" json:"box_id"`
+		Seed       string     `gorm:"type:varchar(64);not null" json:"-"`
+		SeedHash   string     `gorm:"type:varchar(64);not null" json:"seed_hash"`
+		Nonce      uint64     `gorm:"not null;default:0" json:"nonce"`
+		RevealedAt *time.Time `json:"revealed_at"`
+	}
+
+	// BlindBoxDraw is the outcome of a draw together with everything needed to
+	// reproduce it once the seed is revealed.
+	BlindBoxDraw struct {
+		BoxID      uint   `json:"box_id"`
+		FigureID   uint   `json:"figure_id"`
+		FigureName string `json:"figure_name"`
+		IsSecret   bool   `json:"is_secret"`
+		SeedID     uint   `json:"seed_id"`
+		SeedHash   string `json:"seed_hash"`
+		ClientSeed string `json:"client_seed"`
+		Nonce      uint64 `json:"nonce"`
+		Roll       uint64 `json:"roll"`
+		Pool       string `json:"pool"` // figure_id:weight pairs that were eligible, in draw order
+	}
+
+	// BlindBoxTake records what one draw took out of a box's pool for an
+	// order: a unit of each figure in FigureIDs, which are pool entry IDs, and
+	// for a case the case itself. Takes are put back when the order is not
+	// placed or is cancelled, and ReturnedAt set.
+	BlindBoxTake struct {
+		ID          uint       `gorm:"primaryKey" json:"id"`
+		CreatedAt   time.Time  `json:"created_at"`
+		ReferenceID string     `gorm:"type:varchar(64);not null;index" json:"reference_id"`
+		BoxID       uint       `gorm:"not null" json:"box_id"`
+		CaseID      *uint      `json:"case_id,omitempty"`
+		FigureIDs   []uint     `gorm:"type:jsonb;serializer:json" json:"figure_ids"`
+		ReturnedAt  *time.Time `json:"returned_at,omitempty"`
+	}
+
+	// BlindBoxCase makes a case product draw size boxes at once from a blind
+	// box's pool, with no regular figure repeated.
+	BlindBoxCase struct {
+		gorm.Model
+		ProductID uint `gorm:"not null;uniqueIndex" json:"product_id" validate:"-"`
+		BoxID     uint `gorm:"not null;index" json:"box_id" validate:"required,gt=0"`
+		Size      int  `gorm:"type:int;not null" json:"size" validate:"required,gt=0,lte=48"`
+	}
+
+	// BlindBoxCaseDraw is the outcome of opening a case. Nonce is the first nonce
+	// used and Rolls how many were consumed, starting from it.
+	BlindBoxCaseDraw struct {
+		CaseID     uint               `json:"case_id"`
+		BoxID      uint               `json:"box_id"`
+		Figures    []BlindBoxCaseSlot `json:"figures"`
+		SeedID     uint               `json:"seed_id"`
+		SeedHash   string             `json:"seed_hash"`
+		ClientSeed string             `json:"client_seed"`
+		Nonce      uint64             `json:"nonce"`
+		Rolls      uint64             `json:"rolls"`
+		Pool       string             `json:"pool"`
+	}
+
+	BlindBoxCaseSlot struct {
+		FigureID   uint   `json:"figure_id"`
+		FigureName string `json:"figure_name"`
+		IsSecret   bool   `json:"is_secret"`
+	}
+)
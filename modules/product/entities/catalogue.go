@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"database/sql/driver"
+
+	"gorm.io/gorm"
+)
+
+type (
+	Artist struct {
+		gorm.Model
+		Name        string      `gorm:"type:varchar(100);not null;index:idx_artists_name_trgm,type:gin,class:gin_trgm_ops" json:"name" validate:"required,min=2,max=100"`
+		Slug        string      `gorm:"type:varchar(120);uniqueIndex;not null" json:"slug" validate:"omitempty,max=120"`
+		Bio         string      `gorm:"type:text" json:"bio" validate:"max=2000"`
+		AvatarURL   string      `gorm:"type:text" json:"avatar_url" validate:"omitempty,url"`
+		SocialLinks SocialLinks `gorm:"type:jsonb" json:"social_links" validate:"dive,keys,min=1,max=30,endkeys,url"`
+	}
+
+	Brand struct {
+		gorm.Model
+		Name        string      `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
+		Slug        string      `gorm:"type:varchar(120);uniqueIndex;not null" json:"slug" validate:"omitempty,max=120"`
+		Bio         string      `gorm:"type:text" json:"bio" validate:"max=2000"`
+		AvatarURL   string      `gorm:"type:text" json:"avatar_url" validate:"omitempty,url"`
+		SocialLinks SocialLinks `gorm:"type:jsonb" json:"social_links" validate:"dive,keys,min=1,max=30,endkeys,url"`
+	}
+
+	// SocialLinks maps a network name (instagram, x, website, ...) to a profile URL
+	// and is stored as a JSON object.
+	SocialLinks map[string]string
+)
+
+func (l SocialLinks) Value() (driver.Value, error) {
+	return stringMapValue(l)
+}
+
+func (l *SocialLinks) Scan(value any) error {
+	return scanStringMap(value, (*map[string]string)(l))
+}
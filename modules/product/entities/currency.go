@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
+type (
+	// ExchangeRate turns prices in the settlement currency into Currency for
+	// display. Rate is how many units of Currency one unit of the settlement
+	// currency buys. Orders are always charged in the settlement currency.
+	ExchangeRate struct {
+		ID        uint       `gorm:"primaryKey" json:"-"`
+		Currency  string     `gorm:"type:varchar(3);uniqueIndex;not null" json:"currency"`
+		Rate      money.Rate `gorm:"not null" json:"rate"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		UpdatedBy *uint      `json:"updated_by,omitempty"`
+	}
+
+	// RateQuote is the rate a customer is shown prices at. Checkout stores it
+	// on the order so the order reads back in the currency it was quoted in.
+	RateQuote struct {
+		SettlementCurrency string     `json:"settlement_currency"`
+		Currency           string     `json:"currency"`
+		Rate               money.Rate `json:"rate"`
+		QuotedAt           time.Time  `json:"quoted_at"`
+	}
+)
@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StockMovementSale        = "sale"
+	StockMovementRestock     = "restock"
+	StockMovementReturn      = "return"
+	StockMovementAdjustment  = "adjustment"
+	StockMovementReservation = "reservation"
+	StockMovementRelease     = "release"
+	StockMovementTransfer    = "transfer"
+)
+
+const (
+	ReservationActive    = "active"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+	ReservationConverted = "converted"
+	// ReservationHeld reservations belong to an order held for review.
+	ReservationHeld = "held"
+)
+
+type (
+	// StockMovement is one entry in the append-only inventory ledger. Quantity
+	// is signed (sales and reservations take stock away) and Balance is the
+	// item's stock right after the movement, so an item's stock always equals
+	// the sum of its movements. WarehouseID names the location the units left
+	// or arrived at; reservations only hold stock and have none. Movements are
+	// never updated or deleted.
+	StockMovement struct {
+		ID          uint      `gorm:"primaryKey" json:"id"`
+		CreatedAt   time.Time `gorm:"index" json:"created_at"`
+		ProductID   uint      `gorm:"not null;index:idx_stock_movements_item" json:"product_id"`
+		VariantID   *uint     `gorm:"index:idx_stock_movements_item" json:"variant_id,omitempty"`
+		WarehouseID *uint     `gorm:"index" json:"warehouse_id,omitempty"`
+		Kind        string    `gorm:"type:varchar(20);not null" json:"kind"`
+		Quantity    int       `gorm:"type:int;not null" json:"quantity"`
+		Balance     int       `gorm:"type:int;not null" json:"balance"`
+		ActorID     *uint     `json:"actor_id,omitempty"`
+		ReferenceID string    `gorm:"type:varchar(100)" json:"reference_id,omitempty"`
+		Note        string    `gorm:"type:varchar(255)" json:"note,omitempty"`
+	}
+
+	// StockReservation holds stock for a cart or checkout until it expires. The
+	// held quantity is taken out of stock through a reservation movement, so
+	// everything that reads stock already sees it as unavailable. A holder has
+	// at most one active reservation per item.
+	StockReservation struct {
+		gorm.Model
+		HolderID  string    `gorm:"type:varchar(64);not null;index" json:"holder_id"`
+		UserID    *uint     `gorm:"index" json:"user_id,omitempty"`
+		ProductID uint      `gorm:"not null;index" json:"product_id"`
+		VariantID *uint     `json:"variant_id,omitempty"`
+		Quantity  int       `gorm:"type:int;not null" json:"quantity"`
+		Status    string    `gorm:"type:varchar(20);not null;default:'active';index:idx_stock_reservations_expiry,priority:1" json:"status"`
+		ExpiresAt time.Time `gorm:"not null;index:idx_stock_reservations_expiry,priority:2" json:"expires_at"`
+		// Converted reservations record the order they were sold in and who
+		// it was shipped to and paid by, for the checkout purchase limits.
+		// Until then AddressKey is the key of the user's profile address, so
+		// the per-address limit already applies in the cart.
+		ReferenceID   string `gorm:"type:varchar(100)" json:"reference_id,omitempty"`
+		AddressKey    string `gorm:"type:varchar(64);index" json:"-"`
+		InstrumentKey string `gorm:"type:varchar(64);index" json:"-"`
+		// ReviewID is the order review a held reservation is set aside for.
+		ReviewID *uint `gorm:"index" json:"review_id,omitempty"`
+	}
+
+	// StockAlert is raised when a product's stock drops to its low-stock
+	// threshold. It is written in the same transaction as the stock change and
+	// stays pending until admins have been notified.
+	StockAlert struct {
+		ID         uint       `gorm:"primaryKey" json:"id"`
+		CreatedAt  time.Time  `json:"created_at"`
+		ProductID  uint       `gorm:"not null;index" json:"product_id"`
+		Stock      int        `gorm:"type:int;not null" json:"stock"`
+		Threshold  int        `gorm:"type:int;not null" json:"threshold"`
+		NotifiedAt *time.Time `gorm:"index" json:"notified_at,omitempty"`
+	}
+
+	// StockSubscription asks for a back-in-stock notification for a sold-out
+	// product, or one of its variants. It is removed once the notification has
+	// been queued.
+	StockSubscription struct {
+		ID        uint      `gorm:"primaryKey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		ProductID uint      `gorm:"not null;index:idx_stock_subscriptions_item" json:"product_id"`
+		VariantID *uint     `gorm:"index:idx_stock_subscriptions_item" json:"variant_id,omitempty"`
+		UserID    uint      `gorm:"not null;index" json:"user_id"`
+		Email     string    `gorm:"type:varchar(255);not null" json:"email"`
+	}
+
+	// StockNotification is a queued back-in-stock notification. Notifications
+	// are sent in the order they were queued, which follows the order their
+	// subscriptions were made.
+	StockNotification struct {
+		ID        uint       `gorm:"primaryKey" json:"id"`
+		CreatedAt time.Time  `json:"created_at"`
+		ProductID uint       `gorm:"not null" json:"product_id"`
+		VariantID *uint      `json:"variant_id,omitempty"`
+		UserID    uint       `gorm:"not null" json:"user_id"`
+		Email     string     `gorm:"type:varchar(255);not null" json:"email"`
+		SentAt    *time.Time `gorm:"index" json:"sent_at,omitempty"`
+	}
+
+	// StockDrift is an item whose stock column no longer matches the sum of its
+	// ledger movements.
+	StockDrift struct {
+		ProductID uint  `json:"product_id"`
+		VariantID *uint `json:"variant_id,omitempty"`
+		Stock     int   `json:"stock"`
+		Ledger    int   `json:"ledger"`
+	}
+)
+
+// CartHolderID is the reservation holder for a user's cart. Holders are always
+// derived from the signed-in user, never taken from a request, so nobody can
+// extend or release someone else's reservations.
+func CartHolderID(userID uint) string {
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
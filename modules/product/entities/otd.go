@@ -1,15 +1,662 @@
 package entities
 
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
 type (
+	// ProductResponse is what shoppers see of a product. SoldOut marks a
+	// product that stays listed after selling out; Drop carries the release
+	// window of a scheduled drop for the countdown. Price is what the product
+	// sells for now; while it is on sale CompareAtPrice holds its list price
+	// and SaleEndsAt when the sale ends, if it does. Prices are in the
+	// settlement currency; when the customer picks another, Display carries
+	// them converted for show.
 	ProductResponse struct {
-		ID          uint    `json:"id"`
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		ImageURL    string  `json:"image_url"`
+		ID             uint                     `json:"id"`
+		Name           string                   `json:"name"`
+		Description    string                   `json:"description"`
+		Price          money.Money              `json:"price"`
+		CompareAtPrice *money.Money             `json:"compare_at_price,omitempty"`
+		Display        *DisplayPrice            `json:"display,omitempty"`
+		SaleEndsAt     *time.Time               `json:"sale_ends_at,omitempty"`
+		ImageURL       string                   `json:"image_url"`
+		Type           string                   `json:"type,omitempty"`
+		SoldOut        bool                     `json:"sold_out,omitempty"`
+		Drop           *DropSummary             `json:"drop,omitempty"`
+		SeriesID       *uint                    `json:"series_id,omitempty"`
+		Artist         *CreatorSummary          `json:"artist,omitempty"`
+		Brand          *CreatorSummary          `json:"brand,omitempty"`
+		Images         []ProductImageResponse   `json:"images,omitempty"`
+		Categories     []CategorySummary        `json:"categories,omitempty"`
+		Variants       []ProductVariantResponse `json:"variants,omitempty"`
+	}
+
+	ProductVariantResponse struct {
+		ID         uint              `json:"id"`
+		SKU        string            `json:"sku"`
+		Name       string            `json:"name"`
+		Edition    string            `json:"edition"`
+		Attributes VariantAttributes `json:"attributes"`
+		Price      money.Money       `json:"price"`
+		Display    *DisplayPrice     `json:"display,omitempty"`
+		InStock    bool              `json:"in_stock"`
+	}
+
+	// DisplayPrice is a price converted into the customer's currency at Rate.
+	// It is for show only; the customer is charged the settlement price.
+	DisplayPrice struct {
+		Price          money.Money  `json:"price"`
+		CompareAtPrice *money.Money `json:"compare_at_price,omitempty"`
+		Rate           money.Rate   `json:"rate"`
+	}
+
+	ProductFilter struct {
+		CategoryID uint `query:"category_id"`
+		SeriesID   uint `query:"series_id"`
+		ArtistID   uint `query:"artist_id"`
+		BrandID    uint `query:"brand_id"`
+	}
+
+	SearchRequest struct {
+		Keyword      string   `query:"keyword"`
+		Page         int      `query:"page" validate:"omitempty,gte=1"`
+		PageSize     int      `query:"page_size" validate:"omitempty,gte=1,lte=100"`
+		CategoryIDs  []uint   `query:"category_id" validate:"max=20,dive,gt=0"`
+		ArtistIDs    []uint   `query:"artist_id" validate:"max=20,dive,gt=0"`
+		PriceBuckets []string `query:"price" validate:"max=10,dive,max=20"`
+		Availability string   `query:"availability" validate:"omitempty,oneof=in_stock sold_out"`
+		Editions     []string `query:"edition" validate:"max=3,dive,oneof=regular glow chase"`
+	}
+
+	SearchResponse struct {
+		Results    []SearchResult `json:"results"`
+		Facets     SearchFacets   `json:"facets"`
+		Page       int            `json:"page"`
+		PageSize   int            `json:"page_size"`
+		Total      int64          `json:"total"`
+		DidYouMean string         `json:"did_you_mean,omitempty"`
+	}
+
+	// SearchFacets counts the matching products per facet value. Each facet is
+	// counted with every selected filter applied except its own, so the other
+	// values in it stay selectable.
+	SearchFacets struct {
+		Categories   []FacetCount `json:"categories"`
+		Artists      []FacetCount `json:"artists"`
+		Prices       []FacetCount `json:"prices"`
+		Availability []FacetCount `json:"availability"`
+		Editions     []FacetCount `json:"editions"`
+	}
+
+	FacetCount struct {
+		Value    string `json:"value"`
+		Label    string `json:"label"`
+		Count    int64  `json:"count"`
+		Selected bool   `json:"selected"`
+	}
+
+	SearchResult struct {
+		ProductResponse
+		Rank    float64 `json:"rank"`
+		Snippet string  `json:"snippet,omitempty"`
+	}
+
+	AutocompleteRequest struct {
+		Query string `query:"q"`
+		Limit int    `query:"limit" validate:"omitempty,gte=1,lte=20"`
+	}
+
+	SuggestionResponse struct {
+		Kind string `json:"kind"`
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+
+	CategorySummary struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+
+	CreatorSummary struct {
+		ID        uint   `json:"id"`
+		Name      string `json:"name"`
+		Slug      string `json:"slug"`
+		AvatarURL string `json:"avatar_url,omitempty"`
+	}
+
+	ArtistResponse struct {
+		ID          uint        `json:"id"`
+		Name        string      `json:"name"`
+		Slug        string      `json:"slug"`
+		Bio         string      `json:"bio"`
+		AvatarURL   string      `json:"avatar_url"`
+		SocialLinks SocialLinks `json:"social_links"`
+	}
+
+	BrandResponse struct {
+		ID          uint        `json:"id"`
+		Name        string      `json:"name"`
+		Slug        string      `json:"slug"`
+		Bio         string      `json:"bio"`
+		AvatarURL   string      `json:"avatar_url"`
+		SocialLinks SocialLinks `json:"social_links"`
+	}
+
+	CategoryResponse struct {
+		ID          uint               `json:"id"`
+		Name        string             `json:"name"`
+		Slug        string             `json:"slug"`
+		Description string             `json:"description"`
+		ParentID    *uint              `json:"parent_id"`
+		Children    []CategoryResponse `json:"children,omitempty"`
+	}
+
+	SeriesResponse struct {
+		ID          uint              `json:"id"`
+		Name        string            `json:"name"`
+		Slug        string            `json:"slug"`
+		Description string            `json:"description"`
+		Products    []ProductResponse `json:"products,omitempty"`
+	}
+
+	ProductCategories struct {
+		CategoryIDs []uint `json:"category_ids" validate:"dive,gt=0"`
+	}
+
+	ProductImageResponse struct {
+		ID           uint   `json:"id"`
+		URL          string `json:"url"`
+		MediumURL    string `json:"medium_url"`
+		ThumbnailURL string `json:"thumbnail_url"`
+		AltText      string `json:"alt_text"`
+		SortOrder    int    `json:"sort_order"`
+		IsPrimary    bool   `json:"is_primary"`
+	}
+
+	ProductImageUpdate struct {
+		AltText   *string `json:"alt_text" validate:"omitempty,max=255"`
+		SortOrder *int    `json:"sort_order" validate:"omitempty,gte=0"`
+		IsPrimary *bool   `json:"is_primary"`
+	}
+
+	ProductImageOrder struct {
+		ImageIDs []uint `json:"image_ids" validate:"required,min=1,dive,gt=0"`
+	}
+
+	BlindBoxFigureResponse struct {
+		ID       uint    `json:"id"`
+		FigureID uint    `json:"figure_id"`
+		Name     string  `json:"name"`
+		ImageURL string  `json:"image_url"`
+		Weight   int     `json:"weight"`
+		Odds     float64 `json:"odds"`
+		IsSecret bool    `json:"is_secret"`
+		InStock  bool    `json:"in_stock"`
+	}
+
+	BlindBoxFigureUpdate struct {
+		Weight   *int  `json:"weight" validate:"omitempty,gt=0"`
+		IsSecret *bool `json:"is_secret"`
+		Stock    *int  `json:"stock" validate:"omitempty,gte=0"`
+	}
+
+	// BlindBoxOrderDrawRequest is sent by checkout to draw every blind box and
+	// case on an order. Items that are neither are skipped, so checkout can
+	// pass the whole cart. Case sales are recorded under ReferenceID.
+	BlindBoxOrderDrawRequest struct {
+		ClientSeed  string              `json:"client_seed" validate:"required,max=64"`
+		ReferenceID string              `json:"reference_id" validate:"max=100"`
+		Items       []BlindBoxOrderItem `json:"items"`
+	}
+
+	BlindBoxOrderItem struct {
+		ProductID uint `json:"product_id"`
+		Quantity  int  `json:"quantity"`
+	}
+
+	// BlindBoxOrderDraw holds one draw per blind box and one per case on the
+	// order, each in box ID order.
+	BlindBoxOrderDraw struct {
+		Boxes []BlindBoxDraw     `json:"boxes"`
+		Cases []BlindBoxCaseDraw `json:"cases"`
+	}
+
+	BlindBoxCaseResponse struct {
+		CaseID  uint `json:"case_id"`
+		BoxID   uint `json:"box_id"`
+		Size    int  `json:"size"`
+		InStock bool `json:"in_stock"`
+	}
+
+	BlindBoxSeedResponse struct {
+		ID         uint       `json:"id"`
+		SeedHash   string     `json:"seed_hash"`
+		Seed       string     `json:"seed,omitempty"`
+		Nonce      uint64     `json:"nonce"`
+		RevealedAt *time.Time `json:"revealed_at,omitempty"`
+	}
+
+	// SellableItem is what one unit of a product, or of one of its variants,
+	// sells for now.
+	SellableItem struct {
+		ProductID uint        `json:"product_id"`
+		VariantID uint        `json:"variant_id,omitempty"`
+		Type      string      `json:"type"`
+		Price     money.Money `json:"price"`
+	}
+
+	// Account is what the user service tells the product service about a
+	// signed-in user's account. ShippingAddress is their profile address,
+	// empty when they have none.
+	Account struct {
+		UserID          uint
+		CreatedAt       time.Time
+		EmailVerified   bool
+		ShippingAddress string
+	}
+
+	// PlacedOrder is what the order service tells the product service about
+	// an order it placed on its behalf.
+	PlacedOrder struct {
+		ID          uint
+		ReferenceID string
+		Total       money.Money
 	}
 
 	CountProduct struct {
-		Count int `json:"count" validate:"required,gte=1"`
+		VariantID   uint   `json:"variant_id,omitempty"`
+		Count       int    `json:"count" validate:"required,gte=1"`
+		ReferenceID string `json:"reference_id,omitempty" validate:"max=100"`
+	}
+
+	// StockRestockRequest adds stock that arrived from a supplier or, with kind
+	// return, came back from a customer. Without a warehouse the stock is left
+	// unassigned.
+	StockRestockRequest struct {
+		VariantID   uint   `json:"variant_id,omitempty"`
+		WarehouseID uint   `json:"warehouse_id,omitempty"`
+		Kind        string `json:"kind" validate:"omitempty,oneof=restock return"`
+		Quantity    int    `json:"quantity" validate:"required,gt=0"`
+		ReferenceID string `json:"reference_id" validate:"max=100"`
+		Note        string `json:"note" validate:"max=255"`
+	}
+
+	// StockAdjustmentRequest corrects stock by a signed quantity, e.g. after a
+	// count or for damaged goods. A note explaining it is required.
+	StockAdjustmentRequest struct {
+		VariantID   uint   `json:"variant_id,omitempty"`
+		WarehouseID uint   `json:"warehouse_id,omitempty"`
+		Quantity    int    `json:"quantity" validate:"required"`
+		ReferenceID string `json:"reference_id" validate:"max=100"`
+		Note        string `json:"note" validate:"required,max=255"`
+	}
+
+	WaitingRoomRequest struct {
+		BatchSize        int   `json:"batch_size" validate:"required,gte=1,lte=10000"`
+		AdmissionMinutes int   `json:"admission_minutes" validate:"required,gte=1,lte=120"`
+		Active           *bool `json:"active"`
+	}
+
+	// QueueStatus is a ticket holder's view of the waiting room. Position is
+	// 1 for the next ticket to be admitted; once admitted, AdmissionToken is
+	// what checkout asks for.
+	QueueStatus struct {
+		TicketID           uint       `json:"ticket_id"`
+		Status             string     `json:"status"`
+		Position           int64      `json:"position,omitempty"`
+		Token              string     `json:"token"`
+		AdmissionToken     string     `json:"admission_token,omitempty"`
+		AdmissionExpiresAt *time.Time `json:"admission_expires_at,omitempty"`
+	}
+
+	// RaffleRequest sets up a raffle for a product, or one of its variants.
+	// Winners have PaymentMinutes to pay for their unit.
+	RaffleRequest struct {
+		VariantID      uint      `json:"variant_id,omitempty"`
+		Quantity       int       `json:"quantity" validate:"required,gte=1,lte=10000"`
+		EntryOpensAt   time.Time `json:"entry_opens_at" validate:"required"`
+		EntryClosesAt  time.Time `json:"entry_closes_at" validate:"required,gtfield=EntryOpensAt"`
+		PaymentMinutes int       `json:"payment_minutes" validate:"required,gte=1,lte=10080"`
+	}
+
+	// PreOrderOfferRequest sets up or changes a product's pre-order offer.
+	PreOrderOfferRequest struct {
+		ExpectedShipDate time.Time   `json:"expected_ship_date" validate:"required"`
+		Deposit          money.Money `json:"deposit"`
+		Cap              int         `json:"cap" validate:"required,gte=1,lte=100000"`
+		Open             *bool       `json:"open"`
+	}
+
+	// PreOrderRequest places a pre-order, to be paid for under the reference
+	// of the line it creates.
+	PreOrderRequest struct {
+		Quantity int `json:"quantity" validate:"required,gte=1,lte=100"`
+	}
+
+	// PreOrderReleaseRequest releases up to Limit waiting lines, oldest first;
+	// without a limit, as many as stock allows.
+	PreOrderReleaseRequest struct {
+		Limit int `json:"limit" validate:"omitempty,gte=1,lte=1000"`
+	}
+
+	// DropSummary is a drop's release window. Status is coming_soon before
+	// ReleaseAt, live inside the window and ended after EndAt.
+	DropSummary struct {
+		Status        string     `json:"status"`
+		ReleaseAt     time.Time  `json:"release_at"`
+		EndAt         *time.Time `json:"end_at,omitempty"`
+		PurchaseLimit int        `json:"purchase_limit,omitempty"`
+	}
+
+	DropRequest struct {
+		ReleaseAt     time.Time  `json:"release_at" validate:"required"`
+		EndAt         *time.Time `json:"end_at" validate:"omitempty,gtfield=ReleaseAt"`
+		PurchaseLimit int        `json:"purchase_limit" validate:"gte=0"`
+	}
+
+	// SaleRequest puts a product on sale at Price, from StartsAt (or now)
+	// until EndsAt (or until the sale is ended).
+	SaleRequest struct {
+		Price    money.Money `json:"price"`
+		StartsAt *time.Time  `json:"starts_at"`
+		EndsAt   *time.Time  `json:"ends_at"`
+	}
+
+	ScheduledPriceRequest struct {
+		Price       money.Money `json:"price"`
+		EffectiveAt time.Time   `json:"effective_at" validate:"required"`
+	}
+
+	ExchangeRateRequest struct {
+		Rate money.Rate `json:"rate" validate:"required"`
+	}
+
+	RateImportResponse struct {
+		Imported int `json:"imported"`
+	}
+
+	// ReservationRequest sets how many units of an item the user's cart has
+	// on hold. A quantity of 0 releases the item. UserID is taken from the
+	// token and the holder derived from it; the user counts towards drop
+	// purchase limits.
+	ReservationRequest struct {
+		UserID    uint   `json:"-"`
+		HolderID  string `json:"-"`
+		ProductID uint   `json:"product_id" validate:"required,gt=0"`
+		VariantID uint   `json:"variant_id,omitempty"`
+		Quantity  int    `json:"quantity" validate:"gte=0,lte=100"`
+	}
+
+	// OrderHoldRequest moves the user's cart reservations onto the order
+	// referenced by ReferenceID until ExpiresAt. It is sent by the order
+	// service at checkout, never taken from a request.
+	OrderHoldRequest struct {
+		UserID      uint
+		ReferenceID string
+		Items       []OrderHoldItem
+		ExpiresAt   time.Time
+	}
+
+	OrderHoldItem struct {
+		ProductID uint
+		VariantID uint
+		Quantity  int
+	}
+
+	// OrderScreenRequest is sent by the order service at checkout to run the
+	// order referenced by ReferenceID past the risk rules. Quantity is the
+	// units in the order and the order counts are the user's orders placed
+	// in the last hour and day, before this one.
+	OrderScreenRequest struct {
+		UserID         uint
+		ReferenceID    string
+		Quantity       int
+		OrdersLastHour int
+		OrdersLastDay  int
+	}
+
+	// ReservationConvertRequest is sent by the order service once the payment
+	// for the order referenced by ReferenceID is confirmed. The shipping
+	// address is the one stored on the order and the payment instrument
+	// fingerprint comes from the signed confirmation; both count towards the
+	// product purchase limits.
+	ReservationConvertRequest struct {
+		ReferenceID        string `json:"reference_id" validate:"required,max=100"`
+		ShippingAddress    string `json:"shipping_address" validate:"max=500"`
+		PaymentFingerprint string `json:"payment_fingerprint" validate:"max=255"`
+	}
+
+	// PurchaseLimitsRequest sets how many units of a product one user, one
+	// shipping address and one payment instrument may buy; 0 lifts a limit.
+	// Omitted limits are left as they are.
+	PurchaseLimitsRequest struct {
+		PerUser              *int `json:"per_user" validate:"omitempty,gte=0"`
+		PerAddress           *int `json:"per_address" validate:"omitempty,gte=0"`
+		PerPaymentInstrument *int `json:"per_payment_instrument" validate:"omitempty,gte=0"`
+	}
+
+	PurchaseLimits struct {
+		ProductID            uint `json:"product_id"`
+		PerUser              int  `json:"per_user"`
+		PerAddress           int  `json:"per_address"`
+		PerPaymentInstrument int  `json:"per_payment_instrument"`
+	}
+
+	OrderReviewFilter struct {
+		Status string `query:"status" validate:"omitempty,oneof=pending approved rejected withdrawn"`
+	}
+
+	StockMovementFilter struct {
+		VariantID   uint   `query:"variant_id"`
+		WarehouseID uint   `query:"warehouse_id"`
+		Kind        string `query:"kind" validate:"omitempty,oneof=sale restock return adjustment reservation release transfer"`
+		Limit       int    `query:"limit" validate:"omitempty,gte=1,lte=200"`
+	}
+
+	// StockTransferRequest moves units of an item between two warehouses.
+	StockTransferRequest struct {
+		VariantID       uint   `json:"variant_id,omitempty"`
+		FromWarehouseID uint   `json:"from_warehouse_id" validate:"required,gt=0"`
+		ToWarehouseID   uint   `json:"to_warehouse_id" validate:"required,gt=0,nefield=FromWarehouseID"`
+		Quantity        int    `json:"quantity" validate:"required,gt=0"`
+		ReferenceID     string `json:"reference_id" validate:"max=100"`
+		Note            string `json:"note" validate:"max=255"`
+	}
+
+	WarehouseRequest struct {
+		Code     string `json:"code" validate:"required,max=20"`
+		Name     string `json:"name" validate:"required,max=100"`
+		Address  string `json:"address" validate:"max=255"`
+		Priority int    `json:"priority" validate:"gte=0"`
+		Active   *bool  `json:"active"`
+	}
+
+	WarehouseResponse struct {
+		ID       uint   `json:"id"`
+		Code     string `json:"code"`
+		Name     string `json:"name"`
+		Address  string `json:"address"`
+		Priority int    `json:"priority"`
+		Active   bool   `json:"active"`
+	}
+
+	// ProductStockLevels breaks a product's stock down by warehouse. Available
+	// is the aggregate shoppers see.
+	ProductStockLevels struct {
+		ProductID uint                  `json:"product_id"`
+		Available int                   `json:"available"`
+		Locations []WarehouseStockLevel `json:"locations"`
+	}
+
+	WarehouseStockLevel struct {
+		WarehouseID uint   `json:"warehouse_id"`
+		Code        string `json:"code"`
+		Name        string `json:"name"`
+		VariantID   *uint  `json:"variant_id,omitempty"`
+		Stock       int    `json:"stock"`
+	}
+
+	StockSubscriptionRequest struct {
+		ProductID uint   `json:"product_id" validate:"required,gt=0"`
+		VariantID uint   `json:"variant_id,omitempty"`
+		Email     string `json:"email" validate:"required,email,max=255"`
+	}
+
+	// StockRulesRequest sets a product's low-stock threshold and whether it
+	// stays listed once sold out. Omitted fields are left as they are.
+	StockRulesRequest struct {
+		LowStockThreshold *int  `json:"low_stock_threshold" validate:"omitempty,gte=0"`
+		ShowWhenSoldOut   *bool `json:"show_when_sold_out"`
+	}
+
+	StockRules struct {
+		ProductID         uint `json:"product_id"`
+		LowStockThreshold int  `json:"low_stock_threshold"`
+		ShowWhenSoldOut   bool `json:"show_when_sold_out"`
+	}
+
+	LowStockProduct struct {
+		ProductID uint   `json:"product_id"`
+		Name      string `json:"name"`
+		Stock     int    `json:"stock"`
+		Threshold int    `json:"threshold"`
+		Active    bool   `json:"active"`
+	}
+
+	StockReconciliation struct {
+		CheckedAt time.Time    `json:"checked_at"`
+		Drifts    []StockDrift `json:"drifts"`
+	}
+
+	// CouponRequest creates or replaces a coupon. AmountOff is required by
+	// fixed coupons and PercentOff by percentage ones; the other is ignored.
+	CouponRequest struct {
+		Code         string       `json:"code" validate:"required,min=3,max=40"`
+		Description  string       `json:"description" validate:"max=255"`
+		Type         string       `json:"type" validate:"required,oneof=percentage fixed free_shipping"`
+		PercentOff   int          `json:"percent_off" validate:"gte=0,lte=100"`
+		AmountOff    *money.Money `json:"amount_off"`
+		MinSpend     *money.Money `json:"min_spend"`
+		ProductIDs   []uint       `json:"product_ids" validate:"max=100,dive,gt=0"`
+		CategoryIDs  []uint       `json:"category_ids" validate:"max=100,dive,gt=0"`
+		ArtistIDs    []uint       `json:"artist_ids" validate:"max=100,dive,gt=0"`
+		UsageLimit   int          `json:"usage_limit" validate:"gte=0"`
+		PerUserLimit int          `json:"per_user_limit" validate:"gte=0"`
+		StartsAt     *time.Time   `json:"starts_at"`
+		EndsAt       *time.Time   `json:"ends_at"`
+		Stackable    bool         `json:"stackable"`
+		Active       *bool        `json:"active"`
+	}
+
+	// CartPricingRequest prices a cart with the coupon codes the customer
+	// entered. Shipping is the fee checkout would charge. UserID is taken
+	// from the token, when there is one, and counts towards per-user limits.
+	CartPricingRequest struct {
+		UserID   uint        `json:"-"`
+		Codes    []string    `json:"codes" validate:"max=5,dive,required,max=40"`
+		Items    []CartLine  `json:"items" validate:"required,min=1,max=100,dive"`
+		Shipping money.Money `json:"shipping"`
+	}
+
+	CartLine struct {
+		ProductID uint `json:"product_id" validate:"required,gt=0"`
+		VariantID uint `json:"variant_id,omitempty"`
+		Quantity  int  `json:"quantity" validate:"required,gte=1,lte=100"`
+	}
+
+	// CouponRedeemRequest is sent by checkout as it starts placing the order
+	// referenced by ReferenceID. The cart is priced again and a use of each
+	// coupon is taken for the order; the breakdown returned is what the order
+	// keeps.
+	CouponRedeemRequest struct {
+		CartPricingRequest
+		ReferenceID string `json:"reference_id" validate:"required,max=100"`
+	}
+
+	// CartPricing is a cart priced with its promotions and coupons, every
+	// amount in the settlement currency. Savings is what the automatic
+	// promotions took off and Discount what the coupons did; Total is
+	// Subtotal plus Shipping less both. Display is set when the cart is
+	// shown in another currency.
+	CartPricing struct {
+		Items      []PricedCartLine   `json:"items"`
+		Subtotal   money.Money        `json:"subtotal"`
+		Shipping   money.Money        `json:"shipping"`
+		Promotions []AppliedPromotion `json:"promotions"`
+		Savings    money.Money        `json:"savings"`
+		Discounts  []AppliedCoupon    `json:"discounts"`
+		Discount   money.Money        `json:"discount"`
+		Total      money.Money        `json:"total"`
+		Display    *CartDisplay       `json:"display,omitempty"`
+	}
+
+	// CartDisplay is a cart's amounts converted into the customer's currency
+	// at Rate. Like DisplayPrice it is for show only.
+	CartDisplay struct {
+		Subtotal money.Money `json:"subtotal"`
+		Shipping money.Money `json:"shipping"`
+		Savings  money.Money `json:"savings"`
+		Discount money.Money `json:"discount"`
+		Total    money.Money `json:"total"`
+		Rate     money.Rate  `json:"rate"`
+	}
+
+	// PricedCartLine is a cart line with its share of the promotions and
+	// coupon discounts, so the order can keep what each item was sold for.
+	PricedCartLine struct {
+		ProductID  uint            `json:"product_id"`
+		VariantID  uint            `json:"variant_id,omitempty"`
+		Quantity   int             `json:"quantity"`
+		UnitPrice  money.Money     `json:"unit_price"`
+		Promotions []LinePromotion `json:"promotions,omitempty"`
+		Savings    money.Money     `json:"savings"`
+		Discount   money.Money     `json:"discount"`
+		Total      money.Money     `json:"total"`
+		Display    *DisplayPrice   `json:"display,omitempty"`
+	}
+
+	AppliedPromotion struct {
+		PromotionID uint        `json:"promotion_id"`
+		Name        string      `json:"name"`
+		Type        string      `json:"type"`
+		Amount      money.Money `json:"amount"`
+	}
+
+	// LinePromotion is what one promotion took off one cart line.
+	LinePromotion struct {
+		PromotionID uint        `json:"promotion_id"`
+		Name        string      `json:"name"`
+		Amount      money.Money `json:"amount"`
+	}
+
+	// CartPromotionRequest creates a cart promotion. Buy X get Y promotions
+	// need BuyQuantity and FreeQuantity; bundles need BundleProductIDs and
+	// BundlePrice.
+	CartPromotionRequest struct {
+		Name             string       `json:"name" validate:"required,max=100"`
+		Type             string       `json:"type" validate:"required,oneof=buy_x_get_y bundle"`
+		Priority         int          `json:"priority" validate:"gte=0"`
+		BuyQuantity      int          `json:"buy_quantity" validate:"gte=0,lte=100"`
+		FreeQuantity     int          `json:"free_quantity" validate:"gte=0,lte=100"`
+		ProductType      string       `json:"product_type" validate:"omitempty,oneof=standard blind_box blind_box_case"`
+		ProductIDs       []uint       `json:"product_ids" validate:"max=100,dive,gt=0"`
+		CategoryIDs      []uint       `json:"category_ids" validate:"max=100,dive,gt=0"`
+		ArtistIDs        []uint       `json:"artist_ids" validate:"max=100,dive,gt=0"`
+		BundleProductIDs []uint       `json:"bundle_product_ids" validate:"max=20,dive,gt=0"`
+		BundlePrice      *money.Money `json:"bundle_price"`
+		StartsAt         *time.Time   `json:"starts_at"`
+		EndsAt           *time.Time   `json:"ends_at"`
+		Active           *bool        `json:"active"`
+	}
+
+	AppliedCoupon struct {
+		CouponID uint        `json:"coupon_id"`
+		Code     string      `json:"code"`
+		Type     string      `json:"type"`
+		Amount   money.Money `json:"amount"`
 	}
 )
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+)
+
+const (
+	PreOrderAwaitingDeposit = "awaiting_deposit"
+	PreOrderAwaitingStock   = "awaiting_stock"
+	PreOrderAwaitingBalance = "awaiting_balance"
+	PreOrderPaid            = "paid"
+	PreOrderLapsed          = "lapsed"
+)
+
+type (
+	// PreOrderOffer puts a product up for pre-order ahead of its ship date.
+	// Pre-orders are capped by Cap rather than by stock, which the product
+	// does not have yet. Customers pay Deposit per unit when they
+	// pre-order and the rest once their units arrive; a deposit of 0 means
+	// the full price is paid up front. The product is off regular sale while
+	// the offer is open or lines still wait for stock.
+	PreOrderOffer struct {
+		gorm.Model
+		ProductID        uint        `gorm:"not null;uniqueIndex" json:"product_id"`
+		ExpectedShipDate time.Time   `gorm:"not null" json:"expected_ship_date"`
+		Deposit          money.Money `gorm:"embedded;embeddedPrefix:deposit_" json:"deposit"`
+		Cap              int         `gorm:"type:int;not null" json:"cap"`
+		Taken            int         `gorm:"type:int;not null;default:0" json:"taken"`
+		Open             bool        `gorm:"not null;default:true" json:"open"`
+	}
+
+	// PreOrderLine is one customer's pre-order. It holds its units of the
+	// cap in awaiting_deposit until the payment provider confirms its
+	// deposit under ReferenceID, or lapses at PaymentDueAt. It then waits in
+	// awaiting_stock until an admin releases it against arrived stock, which
+	// sells the units to it. Lines with a balance left then wait in
+	// awaiting_balance until the balance is confirmed under
+	// BalanceReferenceID. Both references are issued by the store.
+	PreOrderLine struct {
+		ID                 uint        `gorm:"primaryKey" json:"id"`
+		CreatedAt          time.Time   `json:"created_at"`
+		UpdatedAt          time.Time   `json:"updated_at"`
+		OfferID            uint        `gorm:"not null;index" json:"offer_id"`
+		ProductID          uint        `gorm:"not null;index:idx_pre_order_lines_queue,priority:1" json:"product_id"`
+		UserID             uint        `gorm:"not null;index" json:"user_id"`
+		Quantity           int         `gorm:"type:int;not null" json:"quantity"`
+		UnitPrice          money.Money `gorm:"embedded;embeddedPrefix:unit_price_" json:"unit_price"`
+		DepositPaid        money.Money `gorm:"embedded;embeddedPrefix:deposit_paid_" json:"deposit_paid"`
+		BalanceDue         money.Money `gorm:"embedded;embeddedPrefix:balance_due_" json:"balance_due"`
+		ReferenceID        string      `gorm:"type:varchar(100);not null;index" json:"reference_id"`
+		BalanceReferenceID string      `gorm:"type:varchar(100);index" json:"balance_reference_id,omitempty"`
+		Status             string      `gorm:"type:varchar(20);not null;default:'awaiting_deposit';index:idx_pre_order_lines_queue,priority:2" json:"status"`
+		PaymentDueAt       *time.Time  `json:"payment_due_at,omitempty"`
+		ReleasedAt         *time.Time  `json:"released_at,omitempty"`
+	}
+)
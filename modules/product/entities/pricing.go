@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+)
+
+const (
+	PriceSourceUpdate   = "update"
+	PriceSourceSale     = "sale"
+	PriceSourceSchedule = "schedule"
+)
+
+type (
+	// PriceChange is a snapshot of a product's pricing taken every time it
+	// changes. Source says what changed it: a product update, a sale being
+	// set or ended, or a scheduled price change.
+	PriceChange struct {
+		ID           uint         `gorm:"primaryKey" json:"id"`
+		CreatedAt    time.Time    `gorm:"index" json:"created_at"`
+		ProductID    uint         `gorm:"not null;index" json:"product_id"`
+		Price        money.Money  `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+		SalePrice    *money.Money `gorm:"embedded;embeddedPrefix:sale_price_" json:"sale_price,omitempty"`
+		SaleStartsAt *time.Time   `json:"sale_starts_at,omitempty"`
+		SaleEndsAt   *time.Time   `json:"sale_ends_at,omitempty"`
+		Source       string       `gorm:"type:varchar(20);not null" json:"source"`
+		ActorID      *uint        `json:"actor_id,omitempty"`
+	}
+
+	// ScheduledPrice changes a product's list price at EffectiveAt. The price
+	// scheduler applies it and stamps AppliedAt.
+	ScheduledPrice struct {
+		gorm.Model
+		ProductID   uint        `gorm:"not null;index" json:"product_id"`
+		Price       money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+		EffectiveAt time.Time   `gorm:"not null;index" json:"effective_at"`
+		AppliedAt   *time.Time  `json:"applied_at,omitempty"`
+		ActorID     *uint       `json:"actor_id,omitempty"`
+	}
+)
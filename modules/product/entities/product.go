@@ -1,17 +1,107 @@
 package entities
 
 import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
 	"gorm.io/gorm"
 )
 
+// Variant editions a figure is commonly released in.
+const (
+	EditionRegular = "regular"
+	EditionGlow    = "glow"
+	EditionChase   = "chase"
+)
+
 type (
 	Product struct {
 		gorm.Model
-		Name        string  `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=3,max=100"`
-		Description string  `gorm:"type:text" json:"description" validate:"max=500"`
-		Price       float64 `gorm:"type:decimal(10,2);not null" json:"price" validate:"required,gt=0"`
-		Stock       int     `gorm:"type:int;not null;default:0" json:"stock" validate:"gte=0"`
-		ImageURL    string  `gorm:"type:text" json:"image_url" validate:"required,url"`
-		Active      bool    `gorm:"type:boolean;default:true" json:"active"`
+		Name        string      `gorm:"type:varchar(100);not null;index:idx_products_name_trgm,type:gin,class:gin_trgm_ops" json:"name" validate:"required,min=3,max=100"`
+		Description string      `gorm:"type:text" json:"description" validate:"max=500"`
+		Price       money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+		Stock       int         `gorm:"type:int;not null;default:0" json:"stock" validate:"gte=0"`
+		ImageURL    string      `gorm:"type:text" json:"image_url" validate:"omitempty,url"`
+		Active      bool        `gorm:"type:boolean;default:true" json:"active"`
+		// LowStockThreshold raises a stock alert when stock drops to it; 0
+		// turns alerts off. ShowWhenSoldOut keeps the product listed, marked
+		// sold out, instead of hiding it once stock runs out.
+		LowStockThreshold int  `gorm:"type:int;not null;default:0" json:"low_stock_threshold" validate:"gte=0"`
+		ShowWhenSoldOut   bool `gorm:"type:boolean;not null;default:false" json:"show_when_sold_out"`
+		// A product with ReleaseAt set is a scheduled drop. The drop scheduler
+		// keeps OffSale true outside the release window, and carts and checkout
+		// refuse it while it is. PurchaseLimit caps how many units one user may
+		// buy; 0 means no limit. Drop fields are only written through the drop
+		// endpoints.
+		ReleaseAt     *time.Time `gorm:"<-:update;index" json:"-" validate:"-"`
+		ReleaseEndAt  *time.Time `gorm:"<-:update" json:"-" validate:"-"`
+		PurchaseLimit int        `gorm:"<-:update;type:int;not null;default:0" json:"-" validate:"-"`
+		OffSale       bool       `gorm:"<-:update;type:boolean;not null;default:false" json:"-" validate:"-"`
+		// AddressPurchaseLimit and InstrumentPurchaseLimit cap the units sold to
+		// one shipping address or one payment instrument, whichever accounts
+		// buy them. They are checked at checkout; 0 means no limit.
+		AddressPurchaseLimit    int `gorm:"<-:update;type:int;not null;default:0" json:"-" validate:"-"`
+		InstrumentPurchaseLimit int `gorm:"<-:update;type:int;not null;default:0" json:"-" validate:"-"`
+		// PreOrder is kept true by the pre-order endpoints while the product's
+		// offer is open or pre-orders wait for stock, and carts and checkout
+		// refuse it meanwhile so arrived units go to the pre-orders first.
+		PreOrder bool `gorm:"<-:update;type:boolean;not null;default:false" json:"-" validate:"-"`
+		// SalePrice replaces Price between SaleStartsAt and SaleEndsAt; either
+		// end may be open. Sale fields are only written through the pricing
+		// endpoints.
+		SalePrice    *money.Money     `gorm:"embedded;embeddedPrefix:sale_price_" json:"-" validate:"-"`
+		SaleStartsAt *time.Time       `gorm:"<-:update" json:"-" validate:"-"`
+		SaleEndsAt   *time.Time       `gorm:"<-:update" json:"-" validate:"-"`
+		Type         string           `gorm:"type:varchar(20);not null;default:'standard'" json:"type" validate:"omitempty,oneof=standard blind_box blind_box_case"`
+		SeriesID     *uint            `gorm:"index" json:"series_id,omitempty"`
+		ArtistID     *uint            `gorm:"index" json:"artist_id,omitempty"`
+		BrandID      *uint            `gorm:"index" json:"brand_id,omitempty"`
+		Artist       *Artist          `json:"artist,omitempty" validate:"-"`
+		Brand        *Brand           `json:"brand,omitempty" validate:"-"`
+		Images       []ProductImage   `gorm:"foreignKey:ProductID" json:"images,omitempty" validate:"-"`
+		Categories   []Category       `gorm:"many2many:product_categories" json:"categories,omitempty" validate:"-"`
+		Variants     []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty" validate:"-"`
+		// SearchVector is generated by Postgres from the name and description
+		// and is never read or written by the application.
+		SearchVector string `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(name, '')), 'A') || setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED;index:idx_products_search_vector,type:gin;->:false;<-:false" json:"-" validate:"-"`
+	}
+
+	// ProductVariant is a purchasable edition of a product (regular, glow in the
+	// dark, chase, ...). When a product has variants its Stock is the sum of
+	// theirs and the product itself stays the unit shown in listings.
+	ProductVariant struct {
+		gorm.Model
+		ProductID  uint              `gorm:"not null;index" json:"product_id" validate:"-"`
+		SKU        string            `gorm:"type:varchar(64);uniqueIndex;not null" json:"sku" validate:"required,max=64"`
+		Name       string            `gorm:"type:varchar(100);not null" json:"name" validate:"required,max=100"`
+		Edition    string            `gorm:"type:varchar(20);not null;default:'regular';index" json:"edition" validate:"omitempty,oneof=regular glow chase"`
+		Attributes VariantAttributes `gorm:"type:jsonb" json:"attributes" validate:"dive,keys,min=1,max=50,endkeys,max=100"`
+		Price      money.Money       `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+		Stock      int               `gorm:"type:int;not null;default:0" json:"stock" validate:"gte=0"`
+		SortOrder  int               `gorm:"type:int;not null;default:0" json:"sort_order" validate:"gte=0"`
+	}
+
+	// VariantAttributes describes what sets a variant apart, e.g. {"finish": "glow"}.
+	VariantAttributes map[string]string
+
+	ProductImage struct {
+		gorm.Model
+		ProductID    uint   `gorm:"not null;index" json:"product_id"`
+		URL          string `gorm:"type:text;not null" json:"url"`
+		MediumURL    string `gorm:"type:text;not null" json:"medium_url"`
+		ThumbnailURL string `gorm:"type:text;not null" json:"thumbnail_url"`
+		StorageKey   string `gorm:"type:text;not null" json:"-"`
+		AltText      string `gorm:"type:varchar(255)" json:"alt_text"`
+		SortOrder    int    `gorm:"type:int;not null;default:0" json:"sort_order"`
+		IsPrimary    bool   `gorm:"type:boolean;not null;default:false" json:"is_primary"`
 	}
 )
+
+func (a VariantAttributes) Value() (driver.Value, error) {
+	return stringMapValue(a)
+}
+
+func (a *VariantAttributes) Scan(value any) error {
+	return scanStringMap(value, (*map[string]string)(a))
+}
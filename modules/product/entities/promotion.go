@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+)
+
+const (
+	CouponPercentage   = "percentage"
+	CouponFixed        = "fixed"
+	CouponFreeShipping = "free_shipping"
+)
+
+const (
+	PromotionBuyXGetY = "buy_x_get_y"
+	PromotionBundle   = "bundle"
+)
+
+type (
+	// Coupon is a promotion code. A percentage coupon takes PercentOff
+	// percent off the eligible items, a fixed one takes AmountOff off them
+	// (never more than they cost) and a free shipping one waives shipping.
+	// Every item is eligible when the coupon names no products, categories
+	// or artists; otherwise an item has to match one of them. MinSpend is
+	// counted on eligible items only.
+	//
+	// UsageLimit caps redemptions in all and PerUserLimit redemptions by one
+	// user; 0 means no limit. Redemptions is only written at checkout. A
+	// coupon that is not Stackable can't be used together with another.
+	Coupon struct {
+		gorm.Model
+		Code         string       `gorm:"type:varchar(40);uniqueIndex;not null" json:"code"`
+		Description  string       `gorm:"type:varchar(255)" json:"description"`
+		Type         string       `gorm:"type:varchar(20);not null" json:"type"`
+		PercentOff   int          `gorm:"type:int;not null;default:0" json:"percent_off,omitempty"`
+		AmountOff    *money.Money `gorm:"embedded;embeddedPrefix:amount_off_" json:"amount_off,omitempty"`
+		MinSpend     *money.Money `gorm:"embedded;embeddedPrefix:min_spend_" json:"min_spend,omitempty"`
+		ProductIDs   []uint       `gorm:"type:jsonb;serializer:json" json:"product_ids,omitempty"`
+		CategoryIDs  []uint       `gorm:"type:jsonb;serializer:json" json:"category_ids,omitempty"`
+		ArtistIDs    []uint       `gorm:"type:jsonb;serializer:json" json:"artist_ids,omitempty"`
+		UsageLimit   int          `gorm:"type:int;not null;default:0" json:"usage_limit"`
+		PerUserLimit int          `gorm:"type:int;not null;default:0" json:"per_user_limit"`
+		Redemptions  int          `gorm:"<-:update;type:int;not null;default:0" json:"redemptions"`
+		StartsAt     *time.Time   `json:"starts_at,omitempty"`
+		EndsAt       *time.Time   `json:"ends_at,omitempty"`
+		Stackable    bool         `gorm:"not null" json:"stackable"`
+		Active       bool         `gorm:"not null" json:"active"`
+	}
+
+	// CouponRedemption is one use of a coupon by a paid order. An order
+	// redeems a coupon once however often checkout retries.
+	CouponRedemption struct {
+		gorm.Model
+		CouponID    uint        `gorm:"not null;uniqueIndex:idx_coupon_redemptions_order" json:"coupon_id"`
+		ReferenceID string      `gorm:"type:varchar(100);not null;uniqueIndex:idx_coupon_redemptions_order" json:"reference_id"`
+		UserID      *uint       `gorm:"index" json:"user_id,omitempty"`
+		Discount    money.Money `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
+	}
+
+	// CartPromotion is a deal applied to every cart it matches, without a
+	// code. A buy X get Y promotion makes FreeQuantity of every BuyQuantity
+	// plus FreeQuantity eligible units free, the cheapest of each group; it
+	// is eligible by product type, products, categories or artists like a
+	// coupon. A bundle sells one unit of each of BundleProductIDs together
+	// at BundlePrice.
+	//
+	// Promotions are applied in ascending Priority, then ID, and a unit sold
+	// under one promotion is not counted by the next. Coupons apply after
+	// promotions, to what is left.
+	CartPromotion struct {
+		gorm.Model
+		Name             string       `gorm:"type:varchar(100);not null" json:"name"`
+		Type             string       `gorm:"type:varchar(20);not null" json:"type"`
+		Priority         int          `gorm:"type:int;not null;default:0" json:"priority"`
+		BuyQuantity      int          `gorm:"type:int;not null;default:0" json:"buy_quantity,omitempty"`
+		FreeQuantity     int          `gorm:"type:int;not null;default:0" json:"free_quantity,omitempty"`
+		ProductType      string       `gorm:"type:varchar(20)" json:"product_type,omitempty"`
+		ProductIDs       []uint       `gorm:"type:jsonb;serializer:json" json:"product_ids,omitempty"`
+		CategoryIDs      []uint       `gorm:"type:jsonb;serializer:json" json:"category_ids,omitempty"`
+		ArtistIDs        []uint       `gorm:"type:jsonb;serializer:json" json:"artist_ids,omitempty"`
+		BundleProductIDs []uint       `gorm:"type:jsonb;serializer:json" json:"bundle_product_ids,omitempty"`
+		BundlePrice      *money.Money `gorm:"embedded;embeddedPrefix:bundle_price_" json:"bundle_price,omitempty"`
+		StartsAt         *time.Time   `json:"starts_at,omitempty"`
+		EndsAt           *time.Time   `json:"ends_at,omitempty"`
+		Active           bool         `gorm:"not null" json:"active"`
+	}
+)
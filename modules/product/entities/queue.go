@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	QueueTicketWaiting  = "waiting"
+	QueueTicketAdmitted = "admitted"
+)
+
+const (
+	QueueTokenTicket    = "queue"
+	QueueTokenAdmission = "admission"
+)
+
+type (
+	// WaitingRoom queues shoppers for a high-demand drop. While it is active,
+	// BatchSize tickets are admitted at a time and checkout for the product
+	// needs an admission token, which stays valid for AdmissionMinutes.
+	WaitingRoom struct {
+		gorm.Model
+		ProductID        uint `gorm:"not null;uniqueIndex" json:"product_id"`
+		BatchSize        int  `gorm:"type:int;not null" json:"batch_size"`
+		AdmissionMinutes int  `gorm:"type:int;not null" json:"admission_minutes"`
+		Active           bool `gorm:"not null" json:"active"`
+	}
+
+	// QueueTicket is one user's place in a waiting room. Tickets are admitted
+	// in the order they were issued; a user holds one ticket per room, which
+	// is replaced when they queue again after being admitted.
+	QueueTicket struct {
+		ID         uint       `gorm:"primaryKey" json:"id"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+		ProductID  uint       `gorm:"not null;uniqueIndex:idx_queue_tickets_user,priority:1;index:idx_queue_tickets_status,priority:1" json:"product_id"`
+		UserID     uint       `gorm:"not null;uniqueIndex:idx_queue_tickets_user,priority:2" json:"user_id"`
+		Status     string     `gorm:"type:varchar(20);not null;default:'waiting';index:idx_queue_tickets_status,priority:2" json:"status"`
+		AdmittedAt *time.Time `json:"admitted_at,omitempty"`
+		ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	}
+
+	// QueueClaims are carried by signed queue tokens. A ticket token lets its
+	// holder poll their place in the queue; an admission token lets them
+	// check out until it expires.
+	QueueClaims struct {
+		TicketID  uint   `json:"ticket_id"`
+		ProductID uint   `json:"product_id"`
+		UserID    uint   `json:"user_id"`
+		Type      string `json:"type"`
+		jwt.RegisteredClaims
+	}
+)
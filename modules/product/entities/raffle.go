@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+)
+
+const (
+	RaffleOpen  = "open"
+	RaffleDrawn = "drawn"
+)
+
+const (
+	RaffleEntryEntered    = "entered"
+	RaffleEntryWon        = "won"
+	RaffleEntryWaitlisted = "waitlisted"
+	RaffleEntryPaid       = "paid"
+	RaffleEntryLapsed     = "lapsed"
+)
+
+type (
+	// Raffle sells Quantity units of a product by lot instead of first come,
+	// first served, at Price as it was when the raffle was set up. The units
+	// are taken out of stock when the raffle is created. Entries are accepted while the entry window is open; once it
+	// closes, every entry is ranked by a draw from Seed, which is committed to
+	// by SeedHash up front and revealed after the draw.
+	Raffle struct {
+		gorm.Model
+		ProductID      uint        `gorm:"not null;index" json:"product_id"`
+		VariantID      *uint       `json:"variant_id,omitempty"`
+		Quantity       int         `gorm:"type:int;not null" json:"quantity"`
+		Price          money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+		EntryOpensAt   time.Time   `gorm:"not null" json:"entry_opens_at"`
+		EntryClosesAt  time.Time   `gorm:"not null;index:idx_raffles_draw,priority:2" json:"entry_closes_at"`
+		PaymentMinutes int         `gorm:"type:int;not null" json:"payment_minutes"`
+		Status         string      `gorm:"type:varchar(20);not null;default:'open';index:idx_raffles_draw,priority:1" json:"status"`
+		Seed           string      `gorm:"type:varchar(64);not null" json:"-"`
+		SeedHash       string      `gorm:"type:varchar(64);not null" json:"seed_hash"`
+		ClientSeed     string      `gorm:"type:varchar(64)" json:"client_seed,omitempty"`
+		DrawnAt        *time.Time  `json:"drawn_at,omitempty"`
+		// RevealedSeed is Seed, filled in for responses once the raffle is drawn.
+		RevealedSeed string `gorm:"-" json:"seed,omitempty"`
+	}
+
+	// RaffleEntry is one user's ticket in a raffle. The draw gives every entry
+	// a Rank; the first Quantity ranks win and the rest are waitlisted in rank
+	// order. A win holds one unit for the user. The order service then places
+	// a pending order, OrderID, for it, to be paid until PaymentDueAt;
+	// ReferenceID is that order's reference, which its payment confirmation
+	// carries. If the win lapses unpaid, the order is cancelled and the unit
+	// passes to the next waitlisted entry.
+	RaffleEntry struct {
+		ID           uint       `gorm:"primaryKey" json:"id"`
+		CreatedAt    time.Time  `json:"created_at"`
+		UpdatedAt    time.Time  `json:"updated_at"`
+		RaffleID     uint       `gorm:"not null;uniqueIndex:idx_raffle_entries_user,priority:1;index:idx_raffle_entries_status,priority:1" json:"raffle_id"`
+		UserID       uint       `gorm:"not null;uniqueIndex:idx_raffle_entries_user,priority:2" json:"user_id"`
+		Status       string     `gorm:"type:varchar(20);not null;default:'entered';index:idx_raffle_entries_status,priority:2" json:"status"`
+		Rank         int        `gorm:"type:int;not null;default:0" json:"rank,omitempty"`
+		PaymentDueAt *time.Time `gorm:"index" json:"payment_due_at,omitempty"`
+		OrderID      *uint      `gorm:"index" json:"order_id,omitempty"`
+		ReferenceID  string     `gorm:"type:varchar(64);index" json:"reference_id,omitempty"`
+	}
+)
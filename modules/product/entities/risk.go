@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Checkout signals risk rules can test. Each is a whole number: account age in
+// hours, 1 or 0 for a verified email, the orders the user checked out in the
+// last hour or day, and the units in the checkout.
+const (
+	RiskSignalAccountAgeHours = "account_age_hours"
+	RiskSignalEmailVerified   = "email_verified"
+	RiskSignalOrdersLastHour  = "orders_last_hour"
+	RiskSignalOrdersLastDay   = "orders_last_day"
+	RiskSignalQuantity        = "quantity"
+)
+
+const (
+	RiskActionHold   = "hold"
+	RiskActionReject = "reject"
+)
+
+const (
+	OrderReviewPending  = "pending"
+	OrderReviewApproved = "approved"
+	OrderReviewRejected = "rejected"
+	// OrderReviewWithdrawn is a review whose order was cancelled before an
+	// admin got to it.
+	OrderReviewWithdrawn = "withdrawn"
+)
+
+type (
+	// RiskRule flags a checkout whose Signal compares to Threshold with
+	// Operator (lt, lte, gt, gte or eq). A flagged checkout is held for
+	// review or rejected outright, depending on Action; reject wins when
+	// rules disagree.
+	RiskRule struct {
+		gorm.Model
+		Name      string `gorm:"type:varchar(100);not null" json:"name" validate:"required,max=100"`
+		Signal    string `gorm:"type:varchar(30);not null" json:"signal" validate:"required,oneof=account_age_hours email_verified orders_last_hour orders_last_day quantity"`
+		Operator  string `gorm:"type:varchar(3);not null" json:"operator" validate:"required,oneof=lt lte gt gte eq"`
+		Threshold int    `gorm:"type:int;not null" json:"threshold"`
+		Action    string `gorm:"type:varchar(10);not null" json:"action" validate:"required,oneof=hold reject"`
+		Active    bool   `gorm:"not null;default:true" json:"active"`
+	}
+
+	// OrderReview is an order the risk rules held back at checkout. The stock
+	// reserved for it is set aside, neither expiring nor selling, until an
+	// admin approves the order, which puts the stock back on hold for its
+	// payment, or rejects it, which puts the stock back on sale.
+	OrderReview struct {
+		gorm.Model
+		ReferenceID string     `gorm:"type:varchar(100);not null;uniqueIndex" json:"reference_id"`
+		UserID      *uint      `gorm:"index" json:"user_id,omitempty"`
+		Reasons     string     `gorm:"type:text;not null" json:"reasons"`
+		Status      string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+		ReviewedBy  *uint      `json:"reviewed_by,omitempty"`
+		ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	}
+
+	// Checkout identifies who a converted reservation was sold to, for the
+	// per-address and per-payment-instrument purchase limits. The keys are
+	// hashes of the normalised address and instrument fingerprint.
+	Checkout struct {
+		ReferenceID   string
+		AddressKey    string
+		InstrumentKey string
+	}
+)
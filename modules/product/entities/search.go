@@ -0,0 +1,69 @@
+package entities
+
+const (
+	SuggestionKindProduct = "product"
+	SuggestionKindSeries  = "series"
+	SuggestionKindArtist  = "artist"
+)
+
+const (
+	AvailabilityInStock = "in_stock"
+	AvailabilitySoldOut = "sold_out"
+)
+
+// PriceBuckets are the price ranges offered as a search facet, contiguous and
+// in ascending order. Bounds are in minor units of the store currency. Max
+// is exclusive; a zero Max leaves the top bucket open.
+var PriceBuckets = []PriceBucket{
+	{Key: "under-50", Label: "Under 50", Max: 5000},
+	{Key: "50-100", Label: "50 to 100", Min: 5000, Max: 10000},
+	{Key: "100-250", Label: "100 to 250", Min: 10000, Max: 25000},
+	{Key: "250-500", Label: "250 to 500", Min: 25000, Max: 50000},
+	{Key: "500-plus", Label: "500 and over", Min: 50000},
+}
+
+type (
+	// SearchQuery is a parsed search: every term must match, each as a word
+	// prefix.
+	SearchQuery struct {
+		Terms   []string
+		Filters SearchFilters
+		Limit   int
+		Offset  int
+	}
+
+	// SearchFilters are the facet values a customer has selected. Values within
+	// one facet are ORed, facets are ANDed. A category also matches its
+	// descendants, and an edition matches products with a variant in it.
+	SearchFilters struct {
+		CategoryIDs  []uint
+		ArtistIDs    []uint
+		PriceBuckets []string
+		Availability string
+		Editions     []string
+	}
+
+	PriceBucket struct {
+		Key   string
+		Label string
+		Min   int64
+		Max   int64
+	}
+
+	// SearchHit is a matching product. Snippet is its description escaped for
+	// HTML and cut around the matches, each wrapped in <mark>.
+	SearchHit struct {
+		Product Product
+		Rank    float64
+		Snippet string
+	}
+
+	// Suggestion is a name that closely matches what the customer has typed so
+	// far, with the trigram word similarity it matched by.
+	Suggestion struct {
+		Kind  string
+		ID    uint
+		Name  string
+		Score float64
+	}
+)
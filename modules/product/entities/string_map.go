@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// stringMapValue and scanStringMap store a map[string]string as a JSON object
+// so small key/value sets can live in a single jsonb column.
+func stringMapValue(m map[string]string) (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func scanStringMap(value any, m *map[string]string) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported json value")
+	}
+
+	return json.Unmarshal(data, m)
+}
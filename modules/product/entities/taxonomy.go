@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"gorm.io/gorm"
+)
+
+type (
+	Category struct {
+		gorm.Model
+		Name        string     `gorm:"type:varchar(100);not null" json:"name" validate:"required,min=2,max=100"`
+		Slug        string     `gorm:"type:varchar(120);uniqueIndex;not null" json:"slug" validate:"omitempty,max=120"`
+		Description string     `gorm:"type:text" json:"description" validate:"max=500"`
+		ParentID    *uint      `gorm:"index" json:"parent_id"`
+		Children    []Category `gorm:"foreignKey:ParentID" json:"children,omitempty" validate:"-"`
+	}
+
+	Series struct {
+		gorm.Model
+		Name        string    `gorm:"type:varchar(100);not null;index:idx_series_name_trgm,type:gin,class:gin_trgm_ops" json:"name" validate:"required,min=2,max=100"`
+		Slug        string    `gorm:"type:varchar(120);uniqueIndex;not null" json:"slug" validate:"omitempty,max=120"`
+		Description string    `gorm:"type:text" json:"description" validate:"max=500"`
+		Products    []Product `gorm:"foreignKey:SeriesID" json:"products,omitempty" validate:"-"`
+	}
+)
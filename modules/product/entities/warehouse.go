@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// Warehouse is a location that holds stock, such as the Bangkok warehouse
+	// or a pop-up store. Order lines are fulfilled from active warehouses in
+	// ascending priority.
+	Warehouse struct {
+		gorm.Model
+		Code     string `gorm:"type:varchar(20);uniqueIndex;not null" json:"code"`
+		Name     string `gorm:"type:varchar(100);not null" json:"name"`
+		Address  string `gorm:"type:varchar(255)" json:"address"`
+		Priority int    `gorm:"type:int;not null" json:"priority"`
+		Active   bool   `gorm:"not null" json:"active"`
+	}
+
+	// WarehouseStock is how many units of an item are on a warehouse's
+	// shelves. The product and variant stock columns stay the aggregate that
+	// shoppers see: every location's stock, plus stock not yet assigned to a
+	// location, less what is held by reservations.
+	WarehouseStock struct {
+		ID          uint      `gorm:"primaryKey" json:"id"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		WarehouseID uint      `gorm:"not null;uniqueIndex:idx_warehouse_stocks_item" json:"warehouse_id"`
+		ProductID   uint      `gorm:"not null;uniqueIndex:idx_warehouse_stocks_item" json:"product_id"`
+		VariantID   *uint     `gorm:"uniqueIndex:idx_warehouse_stocks_item" json:"variant_id,omitempty"`
+		Stock       int       `gorm:"type:int;not null" json:"stock"`
+	}
+
+	// StockTransfer moves units of an item from one warehouse to another. The
+	// aggregate stock does not change.
+	StockTransfer struct {
+		ProductID       uint
+		VariantID       *uint
+		FromWarehouseID uint
+		ToWarehouseID   uint
+		Quantity        int
+		ActorID         *uint
+		ReferenceID     string
+		Note            string
+	}
+
+	// StockAllocation is the part of an order line shipped from one warehouse.
+	StockAllocation struct {
+		WarehouseID uint
+		Quantity    int
+	}
+)
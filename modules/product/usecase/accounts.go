@@ -0,0 +1,9 @@
+package usecase
+
+import "github.com/phetployst/art-toys-store/modules/product/entities"
+
+// Accounts is what the product service needs from the user service.
+type Accounts interface {
+	// GetAccount looks up a user's account, or fails with "user not found".
+	GetAccount(userID uint) (*entities.Account, error)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+)
+
+const stockNotificationBatch = 100
+
+// CustomerNotifier tells customers about the products they are waiting for.
+type CustomerNotifier interface {
+	NotifyBackInStock(notification entities.StockNotification) error
+}
+
+// BackInStockDispatcher sends queued back-in-stock notifications in the
+// order they were queued. It stops at the first failed send so nobody is
+// notified ahead of a customer who subscribed earlier; the rest of the batch
+// is retried on the next run.
+type BackInStockDispatcher struct {
+	repo     StockSubscriptionRepository
+	notifier CustomerNotifier
+	interval time.Duration
+}
+
+func NewBackInStockDispatcher(repo StockSubscriptionRepository, notifier CustomerNotifier, interval time.Duration) *BackInStockDispatcher {
+	return &BackInStockDispatcher{repo, notifier, interval}
+}
+
+// Run dispatches every interval until ctx is cancelled.
+func (d *BackInStockDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Dispatch(); err != nil {
+				log.Printf("failed to dispatch back-in-stock notifications: %v", err)
+			}
+		}
+	}
+}
+
+// Dispatch sends one batch of queued notifications and returns how many were
+// sent.
+func (d *BackInStockDispatcher) Dispatch() (int, error) {
+	notifications, err := d.repo.GetPendingNotifications(stockNotificationBatch)
+	if err != nil {
+		return 0, errors.New("database error")
+	}
+
+	sent := []uint{}
+	var sendErr error
+	for _, notification := range notifications {
+		if sendErr = d.notifier.NotifyBackInStock(notification); sendErr != nil {
+			break
+		}
+		sent = append(sent, notification.ID)
+	}
+
+	if len(sent) > 0 {
+		if err := d.repo.MarkNotificationsSent(sent, time.Now()); err != nil {
+			return 0, errors.New("database error")
+		}
+	}
+
+	return len(sent), sendErr
+}
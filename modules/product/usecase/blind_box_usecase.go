@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/draw"
+	"gorm.io/gorm"
+)
+
+type BlindBoxUsecase interface {
+	AddFigure(boxID string, figure *entities.BlindBoxFigure) (*entities.BlindBoxFigureResponse, error)
+	GetFigures(boxID string) ([]entities.BlindBoxFigureResponse, error)
+	UpdateFigure(boxID, figureID string, update *entities.BlindBoxFigureUpdate) (*entities.BlindBoxFigureResponse, error)
+	DeleteFigure(boxID, figureID string) error
+	DrawOrder(request *entities.BlindBoxOrderDrawRequest) (*entities.BlindBoxOrderDraw, error)
+	ReturnDraws(referenceID string) error
+	GetSeeds(boxID string) ([]entities.BlindBoxSeedResponse, error)
+	RotateSeed(boxID string) (*entities.BlindBoxSeedResponse, error)
+	SetCase(caseID string, blindBoxCase *entities.BlindBoxCase) (*entities.BlindBoxCaseResponse, error)
+	GetCase(caseID string) (*entities.BlindBoxCaseResponse, error)
+}
+
+type BlindBoxService struct {
+	repo        BlindBoxRepository
+	productRepo ProductRepository
+}
+
+func NewBlindBoxService(repo BlindBoxRepository, productRepo ProductRepository) BlindBoxUsecase {
+	return &BlindBoxService{repo, productRepo}
+}
+
+func (s *BlindBoxService) AddFigure(boxID string, figure *entities.BlindBoxFigure) (*entities.BlindBoxFigureResponse, error) {
+	box, err := s.getBox(boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	figureProduct, err := s.productRepo.GetProductById(strconv.FormatUint(uint64(figure.FigureID), 10))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("figure not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	if figureProduct.Type != entities.ProductTypeStandard || figureProduct.SeriesID == nil || *figureProduct.SeriesID != *box.SeriesID {
+		return nil, errors.New("figure is not part of the series")
+	}
+
+	if err := s.ensureSeed(box.ID); err != nil {
+		return nil, err
+	}
+
+	figure.BoxID = box.ID
+
+	newFigure, err := s.repo.InsertFigure(figure)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	newFigure.Figure = figureProduct
+
+	response := newBlindBoxFigureResponse(newFigure, 0)
+	return &response, nil
+}
+
+func (s *BlindBoxService) GetFigures(boxID string) ([]entities.BlindBoxFigureResponse, error) {
+	box, err := s.getBox(boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	figures, err := s.repo.GetFigures(box.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	// Only figures still in the pool can be drawn, so the odds shift as
+	// figures sell out.
+	var total int
+	for _, figure := range figures {
+		if figure.Stock > 0 {
+			total += figure.Weight
+		}
+	}
+
+	figureList := []entities.BlindBoxFigureResponse{}
+	for _, figure := range figures {
+		figureList = append(figureList, newBlindBoxFigureResponse(&figure, total))
+	}
+
+	return figureList, nil
+}
+
+func (s *BlindBoxService) UpdateFigure(boxID, figureID string, update *entities.BlindBoxFigureUpdate) (*entities.BlindBoxFigureResponse, error) {
+	box, err := s.getBox(boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	figure, err := s.getFigure(box.ID, figureID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Weight != nil {
+		figure.Weight = *update.Weight
+	}
+	if update.IsSecret != nil {
+		figure.IsSecret = *update.IsSecret
+	}
+	if update.Stock != nil {
+		figure.Stock = *update.Stock
+	}
+
+	figure, err = s.repo.UpdateFigure(figure)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newBlindBoxFigureResponse(figure, 0)
+	return &response, nil
+}
+
+func (s *BlindBoxService) DeleteFigure(boxID, figureID string) error {
+	box, err := s.getBox(boxID)
+	if err != nil {
+		return err
+	}
+
+	figure, err := s.getFigure(box.ID, figureID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteFigure(figure); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// DrawOrder draws every blind box and opens every case on an order in one go.
+func (s *BlindBoxService) DrawOrder(request *entities.BlindBoxOrderDrawRequest) (*entities.BlindBoxOrderDraw, error) {
+	var boxIDs []uint
+	var cases []entities.BlindBoxCase
+	for _, item := range request.Items {
+		product, err := s.productRepo.GetProductById(strconv.FormatUint(uint64(item.ProductID), 10))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("product not found")
+			}
+			return nil, errors.New("database error")
+		}
+
+		switch product.Type {
+		case entities.ProductTypeBlindBox:
+			if product.SeriesID == nil {
+				return nil, errors.New("product is not a blind box")
+			}
+			for i := 0; i < item.Quantity; i++ {
+				boxIDs = append(boxIDs, product.ID)
+			}
+		case entities.ProductTypeBlindBoxCase:
+			if product.SeriesID == nil {
+				return nil, errors.New("product is not a blind box case")
+			}
+			blindBoxCase, err := s.getCase(product.ID)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i < item.Quantity; i++ {
+				cases = append(cases, *blindBoxCase)
+			}
+		}
+	}
+
+	if len(boxIDs) == 0 && len(cases) == 0 {
+		return &entities.BlindBoxOrderDraw{Boxes: []entities.BlindBoxDraw{}, Cases: []entities.BlindBoxCaseDraw{}}, nil
+	}
+
+	result, err := s.repo.DrawOrder(boxIDs, cases, request.ClientSeed, request.ReferenceID)
+	if err != nil {
+		if err.Error() == "blind box sold out" || err.Error() == "case not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return result, nil
+}
+
+// ReturnDraws puts back into their pools the boxes and cases drawn for an
+// order that was not placed after all, or was cancelled.
+func (s *BlindBoxService) ReturnDraws(referenceID string) error {
+	if err := s.repo.ReturnDraws(referenceID, time.Now()); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *BlindBoxService) GetSeeds(boxID string) ([]entities.BlindBoxSeedResponse, error) {
+	box, err := s.getBox(boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds, err := s.repo.GetSeeds(box.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	seedList := []entities.BlindBoxSeedResponse{}
+	for _, seed := range seeds {
+		seedList = append(seedList, newBlindBoxSeedResponse(&seed))
+	}
+
+	return seedList, nil
+}
+
+func (s *BlindBoxService) RotateSeed(boxID string) (*entities.BlindBoxSeedResponse, error) {
+	box, err := s.getBox(boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := newBlindBoxSeed(box.ID)
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	revealed, err := s.repo.RotateSeed(box.ID, next)
+	if err != nil {
+		if err.Error() == "seed not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	response := newBlindBoxSeedResponse(revealed)
+	return &response, nil
+}
+
+// SetCase points a case product at the blind box it is filled from. The box
+// must belong to the same series as the case.
+func (s *BlindBoxService) SetCase(caseID string, blindBoxCase *entities.BlindBoxCase) (*entities.BlindBoxCaseResponse, error) {
+	caseProduct, err := s.getCaseProduct(caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	box, err := s.getBox(strconv.FormatUint(uint64(blindBoxCase.BoxID), 10))
+	if err != nil {
+		return nil, err
+	}
+
+	if *box.SeriesID != *caseProduct.SeriesID {
+		return nil, errors.New("blind box is not part of the series")
+	}
+
+	blindBoxCase.ProductID = caseProduct.ID
+
+	savedCase, err := s.repo.SaveCase(blindBoxCase)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	// Stock was resynced with the case in place, so read it back.
+	caseProduct, err = s.getCaseProduct(caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newBlindBoxCaseResponse(savedCase, caseProduct)
+	return &response, nil
+}
+
+func (s *BlindBoxService) GetCase(caseID string) (*entities.BlindBoxCaseResponse, error) {
+	caseProduct, err := s.getCaseProduct(caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	blindBoxCase, err := s.getCase(caseProduct.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newBlindBoxCaseResponse(blindBoxCase, caseProduct)
+	return &response, nil
+}
+
+func (s *BlindBoxService) getBox(boxID string) (*entities.Product, error) {
+	box, err := s.productRepo.GetProductById(boxID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	if box.Type != entities.ProductTypeBlindBox || box.SeriesID == nil {
+		return nil, errors.New("product is not a blind box")
+	}
+
+	return box, nil
+}
+
+func (s *BlindBoxService) getCaseProduct(caseID string) (*entities.Product, error) {
+	caseProduct, err := s.productRepo.GetProductById(caseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	if caseProduct.Type != entities.ProductTypeBlindBoxCase || caseProduct.SeriesID == nil {
+		return nil, errors.New("product is not a blind box case")
+	}
+
+	return caseProduct, nil
+}
+
+func (s *BlindBoxService) getCase(productID uint) (*entities.BlindBoxCase, error) {
+	blindBoxCase, err := s.repo.GetCase(productID)
+	if err != nil {
+		if err.Error() == "case not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return blindBoxCase, nil
+}
+
+func (s *BlindBoxService) getFigure(boxID uint, figureID string) (*entities.BlindBoxFigure, error) {
+	figure, err := s.repo.GetFigureById(boxID, figureID)
+	if err != nil {
+		if err.Error() == "figure not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return figure, nil
+}
+
+// ensureSeed commits to a seed for the box before its first draw can happen.
+func (s *BlindBoxService) ensureSeed(boxID uint) error {
+	seeds, err := s.repo.GetSeeds(boxID)
+	if err != nil {
+		return errors.New("database error")
+	}
+
+	for _, seed := range seeds {
+		if seed.RevealedAt == nil {
+			return nil
+		}
+	}
+
+	seed, err := newBlindBoxSeed(boxID)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+
+	if _, err := s.repo.InsertSeed(seed); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func newBlindBoxSeed(boxID uint) (*entities.BlindBoxSeed, error) {
+	seed, err := draw.NewSeed()
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.BlindBoxSeed{BoxID: boxID, Seed: seed, SeedHash: draw.HashSeed(seed)}, nil
+}
+
+func newBlindBoxFigureResponse(figure *entities.BlindBoxFigure, totalWeight int) entities.BlindBoxFigureResponse {
+	response := entities.BlindBoxFigureResponse{
+		ID:       figure.ID,
+		FigureID: figure.FigureID,
+		Weight:   figure.Weight,
+		IsSecret: figure.IsSecret,
+		InStock:  figure.Stock > 0,
+	}
+
+	if figure.Figure != nil {
+		response.Name = figure.Figure.Name
+		response.ImageURL = figure.Figure.ImageURL
+	}
+
+	if totalWeight > 0 && figure.Stock > 0 {
+		response.Odds = float64(figure.Weight) / float64(totalWeight)
+	}
+
+	return response
+}
+
+func newBlindBoxCaseResponse(blindBoxCase *entities.BlindBoxCase, caseProduct *entities.Product) entities.BlindBoxCaseResponse {
+	return entities.BlindBoxCaseResponse{
+		CaseID:  caseProduct.ID,
+		BoxID:   blindBoxCase.BoxID,
+		Size:    blindBoxCase.Size,
+		InStock: caseProduct.Stock > 0,
+	}
+}
+
+// newBlindBoxSeedResponse only exposes the seed itself once it is revealed.
+func newBlindBoxSeedResponse(seed *entities.BlindBoxSeed) entities.BlindBoxSeedResponse {
+	response := entities.BlindBoxSeedResponse{
+		ID:         seed.ID,
+		SeedHash:   seed.SeedHash,
+		Nonce:      seed.Nonce,
+		RevealedAt: seed.RevealedAt,
+	}
+
+	if seed.RevealedAt != nil {
+		response.Seed = seed.Seed
+	}
+
+	return response
+}
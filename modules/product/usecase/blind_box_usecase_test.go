@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func blindBox() *entities.Product {
+	return &entities.Product{Model: gorm.Model{ID: 20}, Name: "Exciting Macaron Blind Box", Type: entities.ProductTypeBlindBox, SeriesID: uintPtr(5)}
+}
+
+func TestAddFigure(t *testing.T) {
+	t.Run("add figure and commit to a first seed", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		figure := &entities.Product{Model: gorm.Model{ID: 10}, Name: "Labubu Sea Salt", Type: entities.ProductTypeStandard, SeriesID: uintPtr(5)}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockProductRepo.On("GetProductById", "10").Return(figure, nil)
+		mockRepo.On("GetSeeds", uint(20)).Return([]entities.BlindBoxSeed{}, nil)
+		mockRepo.On("InsertSeed", mock.MatchedBy(func(seed *entities.BlindBoxSeed) bool {
+			return seed.BoxID == 20 && len(seed.Seed) == 64 && len(seed.SeedHash) == 64
+		})).Return(&entities.BlindBoxSeed{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("InsertFigure", mock.MatchedBy(func(f *entities.BlindBoxFigure) bool {
+			return f.BoxID == 20
+		})).Return(&entities.BlindBoxFigure{Model: gorm.Model{ID: 3}, BoxID: 20, FigureID: 10, Weight: 60, Stock: 30}, nil)
+
+		got, err := blindBoxService.AddFigure("20", &entities.BlindBoxFigure{FigureID: 10, Weight: 60, Stock: 30})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.BlindBoxFigureResponse{ID: 3, FigureID: 10, Name: "Labubu Sea Salt", Weight: 60, InStock: true}, got)
+	})
+
+	t.Run("add figure given figure from another series", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockProductRepo.On("GetProductById", "11").Return(&entities.Product{Model: gorm.Model{ID: 11}, SeriesID: uintPtr(6)}, nil)
+
+		_, err := blindBoxService.AddFigure("20", &entities.BlindBoxFigure{FigureID: 11, Weight: 60})
+
+		assert.EqualError(t, err, "figure is not part of the series")
+		mockRepo.AssertNotCalled(t, "InsertFigure", mock.Anything)
+	})
+
+	t.Run("add figure given standard product", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: entities.ProductTypeStandard}, nil)
+
+		_, err := blindBoxService.AddFigure("1", &entities.BlindBoxFigure{FigureID: 10, Weight: 60})
+
+		assert.EqualError(t, err, "product is not a blind box")
+	})
+}
+
+func TestGetFigures(t *testing.T) {
+	t.Run("odds only count figures still in the pool", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockRepo.On("GetFigures", uint(20)).Return([]entities.BlindBoxFigure{
+			{Model: gorm.Model{ID: 1}, FigureID: 10, Figure: &entities.Product{Name: "Sea Salt"}, Weight: 75, Stock: 12},
+			{Model: gorm.Model{ID: 2}, FigureID: 11, Figure: &entities.Product{Name: "Lychee Berry"}, Weight: 20, Stock: 0},
+			{Model: gorm.Model{ID: 3}, FigureID: 12, Figure: &entities.Product{Name: "Chestnut Cocoa"}, Weight: 25, IsSecret: true, Stock: 1},
+		}, nil)
+
+		got, err := blindBoxService.GetFigures("20")
+
+		want := []entities.BlindBoxFigureResponse{
+			{ID: 1, FigureID: 10, Name: "Sea Salt", Weight: 75, Odds: 0.75, InStock: true},
+			{ID: 2, FigureID: 11, Name: "Lychee Berry", Weight: 20},
+			{ID: 3, FigureID: 12, Name: "Chestnut Cocoa", Weight: 25, Odds: 0.25, IsSecret: true, InStock: true},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestDrawOrder(t *testing.T) {
+	t.Run("draw every blind box and case unit on the order", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		figure := &entities.Product{Model: gorm.Model{ID: 10}, Name: "Labubu Sea Salt", Type: entities.ProductTypeStandard}
+		config := &entities.BlindBoxCase{Model: gorm.Model{ID: 1}, ProductID: 30, BoxID: 20, Size: 2}
+		result := &entities.BlindBoxOrderDraw{
+			Boxes: []entities.BlindBoxDraw{
+				{BoxID: 20, FigureID: 12, FigureName: "Chestnut Cocoa", IsSecret: true, SeedID: 1, ClientSeed: "order-42", Nonce: 7},
+				{BoxID: 20, FigureID: 10, FigureName: "Labubu Sea Salt", SeedID: 1, ClientSeed: "order-42", Nonce: 8},
+			},
+			Cases: []entities.BlindBoxCaseDraw{{
+				CaseID:  30,
+				BoxID:   20,
+				Figures: []entities.BlindBoxCaseSlot{{FigureID: 10, FigureName: "Sea Salt"}, {FigureID: 11, FigureName: "Lychee Berry"}},
+				SeedID:  1, ClientSeed: "order-42", Nonce: 9, Rolls: 3, Pool: "10:75,11:20,12:5",
+			}},
+		}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockProductRepo.On("GetProductById", "10").Return(figure, nil)
+		mockProductRepo.On("GetProductById", "30").Return(blindBoxCase(), nil)
+		mockRepo.On("GetCase", uint(30)).Return(config, nil)
+		mockRepo.On("DrawOrder", []uint{20, 20}, []entities.BlindBoxCase{*config}, "order-42", "a1b2").Return(result, nil)
+
+		got, err := blindBoxService.DrawOrder(&entities.BlindBoxOrderDrawRequest{
+			ClientSeed:  "order-42",
+			ReferenceID: "a1b2",
+			Items:       []entities.BlindBoxOrderItem{{ProductID: 20, Quantity: 2}, {ProductID: 10, Quantity: 1}, {ProductID: 30, Quantity: 1}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, result, got)
+	})
+
+	t.Run("draw order without blind boxes", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "10").Return(&entities.Product{Model: gorm.Model{ID: 10}, Type: entities.ProductTypeStandard}, nil)
+
+		got, err := blindBoxService.DrawOrder(&entities.BlindBoxOrderDrawRequest{
+			ClientSeed: "order-42",
+			Items:      []entities.BlindBoxOrderItem{{ProductID: 10, Quantity: 1}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.BlindBoxOrderDraw{Boxes: []entities.BlindBoxDraw{}, Cases: []entities.BlindBoxCaseDraw{}}, got)
+		mockRepo.AssertNotCalled(t, "DrawOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("draw order given case not configured", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "30").Return(blindBoxCase(), nil)
+		mockRepo.On("GetCase", uint(30)).Return((*entities.BlindBoxCase)(nil), errors.New("case not found"))
+
+		_, err := blindBoxService.DrawOrder(&entities.BlindBoxOrderDrawRequest{
+			ClientSeed: "order-42",
+			Items:      []entities.BlindBoxOrderItem{{ProductID: 30, Quantity: 1}},
+		})
+
+		assert.EqualError(t, err, "case not found")
+	})
+
+	t.Run("draw order given sold out box", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockRepo.On("DrawOrder", []uint{20}, []entities.BlindBoxCase(nil), "order-42", "a1b2").Return((*entities.BlindBoxOrderDraw)(nil), errors.New("blind box sold out"))
+
+		_, err := blindBoxService.DrawOrder(&entities.BlindBoxOrderDrawRequest{
+			ClientSeed:  "order-42",
+			ReferenceID: "a1b2",
+			Items:       []entities.BlindBoxOrderItem{{ProductID: 20, Quantity: 1}},
+		})
+
+		assert.EqualError(t, err, "blind box sold out")
+	})
+}
+
+func TestReturnDraws(t *testing.T) {
+	t.Run("return an order's draws", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo}
+
+		mockRepo.On("ReturnDraws", "a1b2", mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := blindBoxService.ReturnDraws("a1b2")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("return draws given database error", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo}
+
+		mockRepo.On("ReturnDraws", "a1b2", mock.Anything).Return(errors.New("connection reset"))
+
+		err := blindBoxService.ReturnDraws("a1b2")
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestRotateSeed(t *testing.T) {
+	t.Run("rotate seed reveals the previous one", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		revealedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockRepo.On("RotateSeed", uint(20), mock.AnythingOfType("*entities.BlindBoxSeed")).
+			Return(&entities.BlindBoxSeed{Model: gorm.Model{ID: 1}, Seed: "secret", SeedHash: "hash", Nonce: 42, RevealedAt: &revealedAt}, nil)
+
+		got, err := blindBoxService.RotateSeed("20")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.BlindBoxSeedResponse{ID: 1, SeedHash: "hash", Seed: "secret", Nonce: 42, RevealedAt: &revealedAt}, got)
+	})
+
+	t.Run("get seeds hides unrevealed seed", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockRepo.On("GetSeeds", uint(20)).Return([]entities.BlindBoxSeed{{Model: gorm.Model{ID: 2}, Seed: "secret", SeedHash: "hash", Nonce: 3}}, nil)
+
+		got, err := blindBoxService.GetSeeds("20")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.BlindBoxSeedResponse{{ID: 2, SeedHash: "hash", Nonce: 3}}, got)
+	})
+}
+
+func blindBoxCase() *entities.Product {
+	return &entities.Product{Model: gorm.Model{ID: 30}, Name: "Exciting Macaron Full Case", Type: entities.ProductTypeBlindBoxCase, SeriesID: uintPtr(5), Stock: 2}
+}
+
+func TestSetCase(t *testing.T) {
+	t.Run("set case successfully", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "30").Return(blindBoxCase(), nil)
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+		mockRepo.On("SaveCase", &entities.BlindBoxCase{ProductID: 30, BoxID: 20, Size: 6}).
+			Return(&entities.BlindBoxCase{Model: gorm.Model{ID: 1}, ProductID: 30, BoxID: 20, Size: 6}, nil)
+
+		got, err := blindBoxService.SetCase("30", &entities.BlindBoxCase{BoxID: 20, Size: 6})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.BlindBoxCaseResponse{CaseID: 30, BoxID: 20, Size: 6, InStock: true}, got)
+	})
+
+	t.Run("set case given box from another series", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		otherBox := blindBox()
+		otherBox.SeriesID = uintPtr(6)
+
+		mockProductRepo.On("GetProductById", "30").Return(blindBoxCase(), nil)
+		mockProductRepo.On("GetProductById", "20").Return(otherBox, nil)
+
+		_, err := blindBoxService.SetCase("30", &entities.BlindBoxCase{BoxID: 20, Size: 6})
+
+		assert.EqualError(t, err, "blind box is not part of the series")
+		mockRepo.AssertNotCalled(t, "SaveCase", mock.Anything)
+	})
+
+	t.Run("set case given single blind box product", func(t *testing.T) {
+		mockRepo := new(MockBlindBoxRepository)
+		mockProductRepo := new(MockProductRepository)
+		blindBoxService := BlindBoxService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(blindBox(), nil)
+
+		_, err := blindBoxService.SetCase("20", &entities.BlindBoxCase{BoxID: 20, Size: 6})
+
+		assert.EqualError(t, err, "product is not a blind box case")
+	})
+}
+
+type MockBlindBoxRepository struct {
+	mock.Mock
+}
+
+func (m *MockBlindBoxRepository) InsertFigure(figure *entities.BlindBoxFigure) (*entities.BlindBoxFigure, error) {
+	args := m.Called(figure)
+	return args.Get(0).(*entities.BlindBoxFigure), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) GetFigures(boxID uint) ([]entities.BlindBoxFigure, error) {
+	args := m.Called(boxID)
+	return args.Get(0).([]entities.BlindBoxFigure), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) GetFigureById(boxID uint, figureID string) (*entities.BlindBoxFigure, error) {
+	args := m.Called(boxID, figureID)
+	return args.Get(0).(*entities.BlindBoxFigure), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) UpdateFigure(figure *entities.BlindBoxFigure) (*entities.BlindBoxFigure, error) {
+	args := m.Called(figure)
+	return args.Get(0).(*entities.BlindBoxFigure), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) DeleteFigure(figure *entities.BlindBoxFigure) error {
+	args := m.Called(figure)
+	return args.Error(0)
+}
+
+func (m *MockBlindBoxRepository) InsertSeed(seed *entities.BlindBoxSeed) (*entities.BlindBoxSeed, error) {
+	args := m.Called(seed)
+	return args.Get(0).(*entities.BlindBoxSeed), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) GetSeeds(boxID uint) ([]entities.BlindBoxSeed, error) {
+	args := m.Called(boxID)
+	return args.Get(0).([]entities.BlindBoxSeed), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) RotateSeed(boxID uint, next *entities.BlindBoxSeed) (*entities.BlindBoxSeed, error) {
+	args := m.Called(boxID, next)
+	return args.Get(0).(*entities.BlindBoxSeed), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) SaveCase(blindBoxCase *entities.BlindBoxCase) (*entities.BlindBoxCase, error) {
+	args := m.Called(blindBoxCase)
+	return args.Get(0).(*entities.BlindBoxCase), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) GetCase(productID uint) (*entities.BlindBoxCase, error) {
+	args := m.Called(productID)
+	return args.Get(0).(*entities.BlindBoxCase), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) DrawOrder(boxIDs []uint, cases []entities.BlindBoxCase, clientSeed, referenceID string) (*entities.BlindBoxOrderDraw, error) {
+	args := m.Called(boxIDs, cases, clientSeed, referenceID)
+	return args.Get(0).(*entities.BlindBoxOrderDraw), args.Error(1)
+}
+
+func (m *MockBlindBoxRepository) ReturnDraws(referenceID string, now time.Time) error {
+	args := m.Called(referenceID, now)
+	return args.Error(0)
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+)
+
+type CatalogueUsecase interface {
+	CreateArtist(artist *entities.Artist) (*entities.ArtistResponse, error)
+	GetAllArtists() ([]entities.ArtistResponse, error)
+	GetArtistById(id string) (*entities.ArtistResponse, error)
+	UpdateArtist(artist *entities.Artist, id string) (*entities.ArtistResponse, error)
+	DeleteArtist(id string) error
+	GetArtistProducts(id string) ([]entities.ProductResponse, error)
+	CreateBrand(brand *entities.Brand) (*entities.BrandResponse, error)
+	GetAllBrands() ([]entities.BrandResponse, error)
+	GetBrandById(id string) (*entities.BrandResponse, error)
+	UpdateBrand(brand *entities.Brand, id string) (*entities.BrandResponse, error)
+	DeleteBrand(id string) error
+	GetBrandProducts(id string) ([]entities.ProductResponse, error)
+}
+
+type CatalogueService struct {
+	repo        CatalogueRepository
+	productRepo ProductRepository
+}
+
+func NewCatalogueService(repo CatalogueRepository, productRepo ProductRepository) CatalogueUsecase {
+	return &CatalogueService{repo, productRepo}
+}
+
+func (s *CatalogueService) CreateArtist(artist *entities.Artist) (*entities.ArtistResponse, error) {
+	if artist.Slug == "" {
+		artist.Slug = slugify(artist.Name)
+	}
+
+	newArtist, err := s.repo.InsertArtist(artist)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newArtistResponse(newArtist)
+	return &response, nil
+}
+
+func (s *CatalogueService) GetAllArtists() ([]entities.ArtistResponse, error) {
+	artists, err := s.repo.GetAllArtists()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	artistList := []entities.ArtistResponse{}
+	for _, artist := range artists {
+		artistList = append(artistList, newArtistResponse(&artist))
+	}
+
+	return artistList, nil
+}
+
+func (s *CatalogueService) GetArtistById(id string) (*entities.ArtistResponse, error) {
+	artist, err := s.getArtist(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newArtistResponse(artist)
+	return &response, nil
+}
+
+func (s *CatalogueService) UpdateArtist(artist *entities.Artist, id string) (*entities.ArtistResponse, error) {
+	existing, err := s.getArtist(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if artist.Slug == "" {
+		artist.Slug = slugify(artist.Name)
+	}
+
+	artistUpdated, err := s.repo.UpdateArtist(artist, id)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	artistUpdated.ID = existing.ID
+
+	response := newArtistResponse(artistUpdated)
+	return &response, nil
+}
+
+func (s *CatalogueService) DeleteArtist(id string) error {
+	if _, err := s.getArtist(id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteArtist(id); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *CatalogueService) GetArtistProducts(id string) ([]entities.ProductResponse, error) {
+	artist, err := s.getArtist(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listProducts(&entities.ProductFilter{ArtistID: artist.ID})
+}
+
+func (s *CatalogueService) CreateBrand(brand *entities.Brand) (*entities.BrandResponse, error) {
+	if brand.Slug == "" {
+		brand.Slug = slugify(brand.Name)
+	}
+
+	newBrand, err := s.repo.InsertBrand(brand)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newBrandResponse(newBrand)
+	return &response, nil
+}
+
+func (s *CatalogueService) GetAllBrands() ([]entities.BrandResponse, error) {
+	brands, err := s.repo.GetAllBrands()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	brandList := []entities.BrandResponse{}
+	for _, brand := range brands {
+		brandList = append(brandList, newBrandResponse(&brand))
+	}
+
+	return brandList, nil
+}
+
+func (s *CatalogueService) GetBrandById(id string) (*entities.BrandResponse, error) {
+	brand, err := s.getBrand(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newBrandResponse(brand)
+	return &response, nil
+}
+
+func (s *CatalogueService) UpdateBrand(brand *entities.Brand, id string) (*entities.BrandResponse, error) {
+	existing, err := s.getBrand(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if brand.Slug == "" {
+		brand.Slug = slugify(brand.Name)
+	}
+
+	brandUpdated, err := s.repo.UpdateBrand(brand, id)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	brandUpdated.ID = existing.ID
+
+	response := newBrandResponse(brandUpdated)
+	return &response, nil
+}
+
+func (s *CatalogueService) DeleteBrand(id string) error {
+	if _, err := s.getBrand(id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteBrand(id); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *CatalogueService) GetBrandProducts(id string) ([]entities.ProductResponse, error) {
+	brand, err := s.getBrand(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listProducts(&entities.ProductFilter{BrandID: brand.ID})
+}
+
+func (s *CatalogueService) getArtist(id string) (*entities.Artist, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, errors.New("artist not found")
+	}
+
+	artist, err := s.repo.GetArtistById(id)
+	if err != nil {
+		if err.Error() == "artist not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return artist, nil
+}
+
+func (s *CatalogueService) getBrand(id string) (*entities.Brand, error) {
+	if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+		return nil, errors.New("brand not found")
+	}
+
+	brand, err := s.repo.GetBrandById(id)
+	if err != nil {
+		if err.Error() == "brand not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return brand, nil
+}
+
+func (s *CatalogueService) listProducts(filter *entities.ProductFilter) ([]entities.ProductResponse, error) {
+	products, err := s.productRepo.GetProductsByFilter(filter)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	productList := []entities.ProductResponse{}
+	for _, product := range products {
+		productList = append(productList, newProductResponse(&product))
+	}
+
+	return productList, nil
+}
+
+func newArtistResponse(artist *entities.Artist) entities.ArtistResponse {
+	return entities.ArtistResponse{
+		ID:          artist.ID,
+		Name:        artist.Name,
+		Slug:        artist.Slug,
+		Bio:         artist.Bio,
+		AvatarURL:   artist.AvatarURL,
+		SocialLinks: artist.SocialLinks,
+	}
+}
+
+func newBrandResponse(brand *entities.Brand) entities.BrandResponse {
+	return entities.BrandResponse{
+		ID:          brand.ID,
+		Name:        brand.Name,
+		Slug:        brand.Slug,
+		Bio:         brand.Bio,
+		AvatarURL:   brand.AvatarURL,
+		SocialLinks: brand.SocialLinks,
+	}
+}
+
+func newArtistSummary(artist *entities.Artist) *entities.CreatorSummary {
+	if artist == nil {
+		return nil
+	}
+
+	return &entities.CreatorSummary{ID: artist.ID, Name: artist.Name, Slug: artist.Slug, AvatarURL: artist.AvatarURL}
+}
+
+func newBrandSummary(brand *entities.Brand) *entities.CreatorSummary {
+	if brand == nil {
+		return nil
+	}
+
+	return &entities.CreatorSummary{ID: brand.ID, Name: brand.Name, Slug: brand.Slug, AvatarURL: brand.AvatarURL}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateArtist(t *testing.T) {
+	t.Run("create artist with generated slug", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		links := entities.SocialLinks{"instagram": "https://instagram.com/kennyswork"}
+		mockRepo.On("InsertArtist", mock.MatchedBy(func(artist *entities.Artist) bool {
+			return artist.Slug == "kenny-wong"
+		})).Return(&entities.Artist{Model: gorm.Model{ID: 1}, Name: "Kenny Wong", Slug: "kenny-wong", Bio: "Creator of Molly", SocialLinks: links}, nil)
+
+		got, err := catalogueService.CreateArtist(&entities.Artist{Name: "Kenny Wong", Bio: "Creator of Molly", SocialLinks: links})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.ArtistResponse{ID: 1, Name: "Kenny Wong", Slug: "kenny-wong", Bio: "Creator of Molly", SocialLinks: links}, got)
+	})
+
+	t.Run("create artist given database error", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		mockRepo.On("InsertArtist", mock.Anything).Return((*entities.Artist)(nil), errors.New("duplicate key"))
+
+		_, err := catalogueService.CreateArtist(&entities.Artist{Name: "Kenny Wong"})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestGetArtistById(t *testing.T) {
+	t.Run("get artist given artist not found", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		mockRepo.On("GetArtistById", "7").Return((*entities.Artist)(nil), errors.New("artist not found"))
+
+		_, err := catalogueService.GetArtistById("7")
+
+		assert.EqualError(t, err, "artist not found")
+	})
+
+	t.Run("get artist given non numeric id", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		_, err := catalogueService.GetArtistById("kenny")
+
+		assert.EqualError(t, err, "artist not found")
+		mockRepo.AssertNotCalled(t, "GetArtistById", mock.Anything)
+	})
+}
+
+func TestGetArtistProducts(t *testing.T) {
+	t.Run("list all works by artist", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		mockProductRepo := new(MockProductRepository)
+		catalogueService := CatalogueService{repo: mockRepo, productRepo: mockProductRepo}
+
+		artist := &entities.Artist{Model: gorm.Model{ID: 1}, Name: "Kenny Wong", Slug: "kenny-wong"}
+		mockRepo.On("GetArtistById", "1").Return(artist, nil)
+		mockProductRepo.On("GetProductsByFilter", &entities.ProductFilter{ArtistID: 1}).Return([]entities.Product{
+			{Model: gorm.Model{ID: 12}, Name: "Molly Classic", Price: money.New(34099, "THB"), ArtistID: uintPtr(1), Artist: artist},
+		}, nil)
+
+		got, err := catalogueService.GetArtistProducts("1")
+
+		want := []entities.ProductResponse{
+			{ID: 12, Name: "Molly Classic", Price: money.New(34099, "THB"), Artist: &entities.CreatorSummary{ID: 1, Name: "Kenny Wong", Slug: "kenny-wong"}},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("list works given artist not found", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		mockProductRepo := new(MockProductRepository)
+		catalogueService := CatalogueService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockRepo.On("GetArtistById", "8").Return((*entities.Artist)(nil), errors.New("artist not found"))
+
+		_, err := catalogueService.GetArtistProducts("8")
+
+		assert.EqualError(t, err, "artist not found")
+		mockProductRepo.AssertNotCalled(t, "GetProductsByFilter", mock.Anything)
+	})
+}
+
+func TestUpdateBrand(t *testing.T) {
+	t.Run("update brand successfully", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		brand := &entities.Brand{Name: "Pop Mart", AvatarURL: "https://cdn.example.com/popmart.png"}
+		mockRepo.On("GetBrandById", "2").Return(&entities.Brand{Model: gorm.Model{ID: 2}, Name: "PopMart"}, nil)
+		mockRepo.On("UpdateBrand", brand, "2").Return(brand, nil)
+
+		got, err := catalogueService.UpdateBrand(brand, "2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.BrandResponse{ID: 2, Name: "Pop Mart", Slug: "pop-mart", AvatarURL: "https://cdn.example.com/popmart.png"}, got)
+	})
+
+	t.Run("update brand given brand not found", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		mockRepo.On("GetBrandById", "20").Return((*entities.Brand)(nil), errors.New("brand not found"))
+
+		_, err := catalogueService.UpdateBrand(&entities.Brand{Name: "Pop Mart"}, "20")
+
+		assert.EqualError(t, err, "brand not found")
+	})
+}
+
+func TestDeleteBrand(t *testing.T) {
+	t.Run("delete brand successfully", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		mockRepo.On("GetBrandById", "2").Return(&entities.Brand{Model: gorm.Model{ID: 2}}, nil)
+		mockRepo.On("DeleteBrand", "2").Return(nil)
+
+		err := catalogueService.DeleteBrand("2")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete brand given database error", func(t *testing.T) {
+		mockRepo := new(MockCatalogueRepository)
+		catalogueService := CatalogueService{repo: mockRepo}
+
+		mockRepo.On("GetBrandById", "2").Return(&entities.Brand{Model: gorm.Model{ID: 2}}, nil)
+		mockRepo.On("DeleteBrand", "2").Return(errors.New("connection refused"))
+
+		err := catalogueService.DeleteBrand("2")
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+type MockCatalogueRepository struct {
+	mock.Mock
+}
+
+func (m *MockCatalogueRepository) InsertArtist(artist *entities.Artist) (*entities.Artist, error) {
+	args := m.Called(artist)
+	return args.Get(0).(*entities.Artist), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) GetAllArtists() ([]entities.Artist, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Artist), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) GetArtistById(id string) (*entities.Artist, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Artist), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) UpdateArtist(artist *entities.Artist, id string) (*entities.Artist, error) {
+	args := m.Called(artist, id)
+	return args.Get(0).(*entities.Artist), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) DeleteArtist(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCatalogueRepository) InsertBrand(brand *entities.Brand) (*entities.Brand, error) {
+	args := m.Called(brand)
+	return args.Get(0).(*entities.Brand), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) GetAllBrands() ([]entities.Brand, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Brand), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) GetBrandById(id string) (*entities.Brand, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Brand), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) UpdateBrand(brand *entities.Brand, id string) (*entities.Brand, error) {
+	args := m.Called(brand, id)
+	return args.Get(0).(*entities.Brand), args.Error(1)
+}
+
+func (m *MockCatalogueRepository) DeleteBrand(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
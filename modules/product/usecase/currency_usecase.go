@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
+// MaxRateFileSize caps an exchange rate import; a line per currency fits
+// comfortably.
+const MaxRateFileSize = 64 << 10
+
+type CurrencyUsecase interface {
+	SetRate(currency string, actorID uint, request *entities.ExchangeRateRequest) (*entities.ExchangeRate, error)
+	ImportRates(actorID uint, data []byte) (int, error)
+	GetRates() ([]entities.ExchangeRate, error)
+	DeleteRate(currency string) error
+	Quote(currency string) (*entities.RateQuote, error)
+	LocalizeProducts(currency string, products ...*entities.ProductResponse) error
+	LocalizeCart(currency string, pricing *entities.CartPricing) error
+}
+
+type CurrencyService struct {
+	repo ExchangeRateRepository
+}
+
+func NewCurrencyService(repo ExchangeRateRepository) CurrencyUsecase {
+	return &CurrencyService{repo}
+}
+
+func (s *CurrencyService) SetRate(currency string, actorID uint, request *entities.ExchangeRateRequest) (*entities.ExchangeRate, error) {
+	rate, err := newExchangeRate(currency, request.Rate, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveRates([]entities.ExchangeRate{*rate}); err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return rate, nil
+}
+
+// ImportRates sets the rates listed in a CSV file of currency,rate lines, e.g.
+// "USD,0.027350". A header line is skipped. The file is checked in full
+// before any rate is saved.
+func (s *CurrencyService) ImportRates(actorID uint, data []byte) (int, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var rates []entities.ExchangeRate
+	seen := map[string]bool{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, errors.New("invalid rate file")
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		parsed, err := money.ParseRate(record[1])
+		if err != nil {
+			return 0, errors.New("invalid rate file")
+		}
+
+		rate, err := newExchangeRate(record[0], parsed, actorID)
+		if err != nil || seen[rate.Currency] {
+			return 0, errors.New("invalid rate file")
+		}
+
+		seen[rate.Currency] = true
+		rates = append(rates, *rate)
+	}
+
+	if len(rates) == 0 {
+		return 0, errors.New("invalid rate file")
+	}
+
+	if err := s.repo.SaveRates(rates); err != nil {
+		return 0, errors.New("database error")
+	}
+
+	return len(rates), nil
+}
+
+func (s *CurrencyService) GetRates() ([]entities.ExchangeRate, error) {
+	rates, err := s.repo.GetRates()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return rates, nil
+}
+
+func (s *CurrencyService) DeleteRate(currency string) error {
+	code, err := currencyCode(currency)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteRate(code); err != nil {
+		if err.Error() == "currency not supported" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// Quote is the rate prices are shown at in currency right now. The
+// settlement currency quotes at 1.
+func (s *CurrencyService) Quote(currency string) (*entities.RateQuote, error) {
+	code, err := currencyCode(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &entities.RateQuote{
+		SettlementCurrency: money.DefaultCurrency,
+		Currency:           code,
+		Rate:               money.RateScale,
+		QuotedAt:           time.Now(),
+	}
+
+	if code == money.DefaultCurrency {
+		return quote, nil
+	}
+
+	rate, err := s.repo.GetRate(code)
+	if err != nil {
+		if err.Error() == "currency not supported" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	quote.Rate = rate.Rate
+	return quote, nil
+}
+
+// LocalizeProducts adds prices in currency to products, variants included.
+// Products shown in the settlement currency are left as they are.
+func (s *CurrencyService) LocalizeProducts(currency string, products ...*entities.ProductResponse) error {
+	quote, err := s.Quote(currency)
+	if err != nil {
+		return err
+	}
+
+	if quote.Currency == quote.SettlementCurrency {
+		return nil
+	}
+
+	for _, product := range products {
+		product.Display = displayPrice(product.Price, product.CompareAtPrice, quote)
+		for i := range product.Variants {
+			product.Variants[i].Display = displayPrice(product.Variants[i].Price, nil, quote)
+		}
+	}
+
+	return nil
+}
+
+// LocalizeCart adds a priced cart's amounts in currency, each line's unit price
+// included. Carts shown in the settlement currency are left as they are.
+func (s *CurrencyService) LocalizeCart(currency string, pricing *entities.CartPricing) error {
+	quote, err := s.Quote(currency)
+	if err != nil {
+		return err
+	}
+
+	if quote.Currency == quote.SettlementCurrency {
+		return nil
+	}
+
+	pricing.Display = &entities.CartDisplay{
+		Subtotal: pricing.Subtotal.Convert(quote.Currency, quote.Rate),
+		Shipping: pricing.Shipping.Convert(quote.Currency, quote.Rate),
+		Savings:  pricing.Savings.Convert(quote.Currency, quote.Rate),
+		Discount: pricing.Discount.Convert(quote.Currency, quote.Rate),
+		Total:    pricing.Total.Convert(quote.Currency, quote.Rate),
+		Rate:     quote.Rate,
+	}
+	for i := range pricing.Items {
+		pricing.Items[i].Display = displayPrice(pricing.Items[i].UnitPrice, nil, quote)
+	}
+
+	return nil
+}
+
+func displayPrice(price money.Money, compareAtPrice *money.Money, quote *entities.RateQuote) *entities.DisplayPrice {
+	display := &entities.DisplayPrice{
+		Price: price.Convert(quote.Currency, quote.Rate),
+		Rate:  quote.Rate,
+	}
+
+	if compareAtPrice != nil {
+		converted := compareAtPrice.Convert(quote.Currency, quote.Rate)
+		display.CompareAtPrice = &converted
+	}
+
+	return display
+}
+
+func newExchangeRate(currency string, rate money.Rate, actorID uint) (*entities.ExchangeRate, error) {
+	code, err := currencyCode(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if code == money.DefaultCurrency {
+		return nil, errors.New("settlement currency has no rate")
+	}
+
+	if rate <= 0 {
+		return nil, money.ErrInvalidRate
+	}
+
+	return &entities.ExchangeRate{Currency: code, Rate: rate, UpdatedAt: time.Now(), UpdatedBy: &actorID}, nil
+}
+
+// currencyCode normalises a currency code to upper case and checks it has
+// the shape of an ISO 4217 code.
+func currencyCode(currency string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", errors.New("invalid currency")
+	}
+
+	return code, nil
+}
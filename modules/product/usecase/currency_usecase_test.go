@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetRate(t *testing.T) {
+	t.Run("set rate successfully", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("SaveRates", mock.MatchedBy(func(rates []entities.ExchangeRate) bool {
+			return len(rates) == 1 && rates[0].Currency == "USD" && rates[0].Rate == 27350 && *rates[0].UpdatedBy == 7
+		})).Return(nil)
+
+		got, err := currencyService.SetRate("usd", 7, &entities.ExchangeRateRequest{Rate: 27350})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", got.Currency)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rate for the settlement currency", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		_, err := currencyService.SetRate("THB", 7, &entities.ExchangeRateRequest{Rate: money.RateScale})
+
+		assert.EqualError(t, err, "settlement currency has no rate")
+		mockRepo.AssertNotCalled(t, "SaveRates", mock.Anything)
+	})
+
+	t.Run("invalid currency", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		_, err := currencyService.SetRate("US1", 7, &entities.ExchangeRateRequest{Rate: 27350})
+
+		assert.EqualError(t, err, "invalid currency")
+	})
+
+	t.Run("rate not positive", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		_, err := currencyService.SetRate("USD", 7, &entities.ExchangeRateRequest{Rate: -1})
+
+		assert.ErrorIs(t, err, money.ErrInvalidRate)
+	})
+}
+
+func TestImportRates(t *testing.T) {
+	t.Run("import rates with a header", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("SaveRates", mock.MatchedBy(func(rates []entities.ExchangeRate) bool {
+			return len(rates) == 2 &&
+				rates[0].Currency == "USD" && rates[0].Rate == 27350 &&
+				rates[1].Currency == "JPY" && rates[1].Rate == 4215000
+		})).Return(nil)
+
+		got, err := currencyService.ImportRates(7, []byte("currency,rate\nUSD,0.027350\njpy, 4.215\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got)
+	})
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty file", ""},
+		{"header only", "currency,rate\n"},
+		{"malformed rate", "USD,abc\n"},
+		{"missing column", "USD\n"},
+		{"duplicate currency", "USD,0.0273\nusd,0.0274\n"},
+		{"settlement currency", "THB,1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockExchangeRateRepository)
+			currencyService := CurrencyService{repo: mockRepo}
+
+			_, err := currencyService.ImportRates(7, []byte(tt.data))
+
+			assert.EqualError(t, err, "invalid rate file")
+			mockRepo.AssertNotCalled(t, "SaveRates", mock.Anything)
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	t.Run("quote a foreign currency", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("GetRate", "USD").Return(&entities.ExchangeRate{Currency: "USD", Rate: 27350}, nil)
+
+		got, err := currencyService.Quote("usd")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "THB", got.SettlementCurrency)
+		assert.Equal(t, money.Rate(27350), got.Rate)
+	})
+
+	t.Run("quote the settlement currency", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		got, err := currencyService.Quote("THB")
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.Rate(money.RateScale), got.Rate)
+		mockRepo.AssertNotCalled(t, "GetRate", mock.Anything)
+	})
+
+	t.Run("currency not supported", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("GetRate", "EUR").Return((*entities.ExchangeRate)(nil), errors.New("currency not supported"))
+
+		_, err := currencyService.Quote("EUR")
+
+		assert.EqualError(t, err, "currency not supported")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("GetRate", "EUR").Return((*entities.ExchangeRate)(nil), errors.New("connection refused"))
+
+		_, err := currencyService.Quote("EUR")
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestLocalizeCart(t *testing.T) {
+	t.Run("add display amounts", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("GetRate", "USD").Return(&entities.ExchangeRate{Currency: "USD", Rate: 27350}, nil)
+
+		pricing := &entities.CartPricing{
+			Items:    []entities.PricedCartLine{{ProductID: 1, Quantity: 2, UnitPrice: money.New(4999, "THB"), Total: money.New(8998, "THB")}},
+			Subtotal: money.New(9998, "THB"),
+			Shipping: money.New(0, "THB"),
+			Savings:  money.New(0, "THB"),
+			Discount: money.New(1000, "THB"),
+			Total:    money.New(8998, "THB"),
+		}
+
+		err := currencyService.LocalizeCart("USD", pricing)
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(8998, "THB"), pricing.Total)
+		assert.Equal(t, money.New(273, "USD"), pricing.Display.Subtotal)
+		assert.Equal(t, money.New(27, "USD"), pricing.Display.Discount)
+		assert.Equal(t, money.New(246, "USD"), pricing.Display.Total)
+		assert.Equal(t, money.New(137, "USD"), pricing.Items[0].Display.Price)
+	})
+
+	t.Run("settlement currency", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		pricing := &entities.CartPricing{Total: money.New(8998, "THB")}
+
+		err := currencyService.LocalizeCart("THB", pricing)
+
+		assert.NoError(t, err)
+		assert.Nil(t, pricing.Display)
+	})
+}
+
+func TestLocalizeProducts(t *testing.T) {
+	t.Run("add display prices", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		mockRepo.On("GetRate", "USD").Return(&entities.ExchangeRate{Currency: "USD", Rate: 27350}, nil)
+
+		compareAtPrice := money.New(6000, "THB")
+		product := &entities.ProductResponse{
+			Price:          money.New(4999, "THB"),
+			CompareAtPrice: &compareAtPrice,
+			Variants:       []entities.ProductVariantResponse{{Price: money.New(10000, "THB")}},
+		}
+
+		err := currencyService.LocalizeProducts("USD", product)
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(4999, "THB"), product.Price)
+		assert.Equal(t, money.New(137, "USD"), product.Display.Price)
+		assert.Equal(t, money.New(164, "USD"), *product.Display.CompareAtPrice)
+		assert.Equal(t, money.Rate(27350), product.Display.Rate)
+		assert.Equal(t, money.New(274, "USD"), product.Variants[0].Display.Price)
+	})
+
+	t.Run("settlement currency", func(t *testing.T) {
+		mockRepo := new(MockExchangeRateRepository)
+		currencyService := CurrencyService{repo: mockRepo}
+
+		product := &entities.ProductResponse{Price: money.New(4999, "THB")}
+
+		err := currencyService.LocalizeProducts("THB", product)
+
+		assert.NoError(t, err)
+		assert.Nil(t, product.Display)
+	})
+}
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) SaveRates(rates []entities.ExchangeRate) error {
+	args := m.Called(rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateRepository) GetRates() ([]entities.ExchangeRate, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetRate(currency string) (*entities.ExchangeRate, error) {
+	args := m.Called(currency)
+	return args.Get(0).(*entities.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) DeleteRate(currency string) error {
+	args := m.Called(currency)
+	return args.Error(0)
+}
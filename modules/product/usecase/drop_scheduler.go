@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DropScheduler opens and closes drop release windows in the background so
+// limited releases go on sale at their announced time without anyone
+// touching the product.
+type DropScheduler struct {
+	usecase  DropUsecase
+	interval time.Duration
+}
+
+func NewDropScheduler(usecase DropUsecase, interval time.Duration) *DropScheduler {
+	return &DropScheduler{usecase, interval}
+}
+
+// Run syncs drops every interval until ctx is cancelled.
+func (s *DropScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sync()
+		}
+	}
+}
+
+func (s *DropScheduler) sync() {
+	changed, err := s.usecase.SyncDrops()
+	if err != nil {
+		log.Printf("failed to sync drops: %v", err)
+	}
+
+	if changed > 0 {
+		log.Printf("updated %d drops", changed)
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+const (
+	DropComingSoon = "coming_soon"
+	DropLive       = "live"
+	DropEnded      = "ended"
+)
+
+type DropUsecase interface {
+	ScheduleDrop(productID string, request *entities.DropRequest) (*entities.DropSummary, error)
+	CancelDrop(productID string) error
+	SyncDrops() (int64, error)
+	CheckPurchase(userID, productID uint, quantity int) error
+}
+
+type DropService struct {
+	repo        DropRepository
+	productRepo ProductRepository
+}
+
+func NewDropService(repo DropRepository, productRepo ProductRepository) DropUsecase {
+	return &DropService{repo, productRepo}
+}
+
+// ScheduleDrop sets or moves a product's release window. The product is put
+// on or off sale straight away so it never waits for the scheduler's next run.
+func (s *DropService) ScheduleDrop(productID string, request *entities.DropRequest) (*entities.DropSummary, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if request.EndAt != nil && !request.EndAt.After(now) {
+		return nil, errors.New("drop has already ended")
+	}
+
+	product.ReleaseAt = &request.ReleaseAt
+	product.ReleaseEndAt = request.EndAt
+	product.PurchaseLimit = request.PurchaseLimit
+	product.OffSale = newDropSummary(product, now).Status != DropLive
+
+	if err := s.repo.UpdateDrop(product); err != nil {
+		if err.Error() == "product not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return newDropSummary(product, now), nil
+}
+
+// CancelDrop turns a drop back into a regular product that is on sale.
+func (s *DropService) CancelDrop(productID string) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	if product.ReleaseAt == nil {
+		return errors.New("drop not found")
+	}
+
+	product.ReleaseAt = nil
+	product.ReleaseEndAt = nil
+	product.PurchaseLimit = 0
+	product.OffSale = false
+
+	if err := s.repo.UpdateDrop(product); err != nil {
+		if err.Error() == "product not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// SyncDrops puts drops whose window has opened on sale and takes ended ones
+// off sale. It returns how many products changed.
+func (s *DropService) SyncDrops() (int64, error) {
+	changed, err := s.repo.UpdateDropWindows(time.Now())
+	if err != nil {
+		return 0, errors.New("database error")
+	}
+
+	return changed, nil
+}
+
+// CheckPurchase checks that a user may have quantity units of a product in
+// their cart under its drop rules. It is for items that are not reserved,
+// since reserving runs the same checks.
+func (s *DropService) CheckPurchase(userID, productID uint, quantity int) error {
+	if err := s.repo.CheckPurchase(productID, userID, quantity); err != nil {
+		switch err.Error() {
+		case "product not found", "product is not on sale", "purchase limit exceeded":
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *DropService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+func newDropSummary(product *entities.Product, now time.Time) *entities.DropSummary {
+	if product.ReleaseAt == nil {
+		return nil
+	}
+
+	status := DropLive
+	switch {
+	case now.Before(*product.ReleaseAt):
+		status = DropComingSoon
+	case product.ReleaseEndAt != nil && !now.Before(*product.ReleaseEndAt):
+		status = DropEnded
+	}
+
+	return &entities.DropSummary{
+		Status:        status,
+		ReleaseAt:     *product.ReleaseAt,
+		EndAt:         product.ReleaseEndAt,
+		PurchaseLimit: product.PurchaseLimit,
+	}
+}
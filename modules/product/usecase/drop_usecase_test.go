@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestNewDropSummary(t *testing.T) {
+	releaseAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	endAt := releaseAt.Add(2 * time.Hour)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"before release", releaseAt.Add(-time.Minute), DropComingSoon},
+		{"at release", releaseAt, DropLive},
+		{"inside the window", releaseAt.Add(time.Hour), DropLive},
+		{"at the end", endAt, DropEnded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := &entities.Product{ReleaseAt: &releaseAt, ReleaseEndAt: &endAt}
+			assert.Equal(t, tt.want, newDropSummary(product, tt.now).Status)
+		})
+	}
+
+	t.Run("not a drop", func(t *testing.T) {
+		assert.Nil(t, newDropSummary(&entities.Product{}, releaseAt))
+	})
+}
+
+func TestScheduleDrop(t *testing.T) {
+	t.Run("schedule a future drop off sale", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		mockProductRepo := new(MockProductRepository)
+		dropService := DropService{repo: mockRepo, productRepo: mockProductRepo}
+
+		releaseAt := time.Now().Add(24 * time.Hour)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("UpdateDrop", mock.MatchedBy(func(p *entities.Product) bool {
+			return p.ID == 1 && p.OffSale && p.PurchaseLimit == 2 && p.ReleaseAt.Equal(releaseAt)
+		})).Return(nil)
+
+		got, err := dropService.ScheduleDrop("1", &entities.DropRequest{ReleaseAt: releaseAt, PurchaseLimit: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, DropComingSoon, got.Status)
+	})
+
+	t.Run("schedule a drop that has already opened on sale", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		mockProductRepo := new(MockProductRepository)
+		dropService := DropService{repo: mockRepo, productRepo: mockProductRepo}
+
+		releaseAt := time.Now().Add(-time.Minute)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, OffSale: true}, nil)
+		mockRepo.On("UpdateDrop", mock.MatchedBy(func(p *entities.Product) bool { return !p.OffSale })).Return(nil)
+
+		got, err := dropService.ScheduleDrop("1", &entities.DropRequest{ReleaseAt: releaseAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, DropLive, got.Status)
+	})
+
+	t.Run("schedule a drop that has already ended", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		mockProductRepo := new(MockProductRepository)
+		dropService := DropService{repo: mockRepo, productRepo: mockProductRepo}
+
+		endAt := time.Now().Add(-time.Minute)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+
+		_, err := dropService.ScheduleDrop("1", &entities.DropRequest{ReleaseAt: endAt.Add(-time.Hour), EndAt: &endAt})
+
+		assert.EqualError(t, err, "drop has already ended")
+		mockRepo.AssertNotCalled(t, "UpdateDrop", mock.Anything)
+	})
+}
+
+func TestCancelDrop(t *testing.T) {
+	t.Run("cancel drop puts the product back on sale", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		mockProductRepo := new(MockProductRepository)
+		dropService := DropService{repo: mockRepo, productRepo: mockProductRepo}
+
+		releaseAt := time.Now().Add(time.Hour)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, ReleaseAt: &releaseAt, PurchaseLimit: 2, OffSale: true}, nil)
+		mockRepo.On("UpdateDrop", &entities.Product{Model: gorm.Model{ID: 1}}).Return(nil)
+
+		err := dropService.CancelDrop("1")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestCheckPurchase(t *testing.T) {
+	t.Run("check purchase passes drop errors through", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		dropService := DropService{repo: mockRepo}
+
+		mockRepo.On("CheckPurchase", uint(20), uint(7), 3).Return(errors.New("purchase limit exceeded"))
+
+		err := dropService.CheckPurchase(7, 20, 3)
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+	})
+
+	t.Run("check purchase given database error", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		dropService := DropService{repo: mockRepo}
+
+		mockRepo.On("CheckPurchase", uint(20), uint(7), 1).Return(errors.New("failed to retrieve reservations"))
+
+		err := dropService.CheckPurchase(7, 20, 1)
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestDropScheduler(t *testing.T) {
+	t.Run("sync drops on every tick until cancelled", func(t *testing.T) {
+		mockRepo := new(MockDropRepository)
+		scheduler := NewDropScheduler(&DropService{repo: mockRepo}, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockRepo.On("UpdateDropWindows", mock.Anything).Return(int64(1), nil).Run(func(mock.Arguments) {
+			cancel()
+		})
+
+		done := make(chan struct{})
+		go func() {
+			scheduler.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop after cancel")
+		}
+		mockRepo.AssertCalled(t, "UpdateDropWindows", mock.Anything)
+	})
+}
+
+type MockDropRepository struct {
+	mock.Mock
+}
+
+func (m *MockDropRepository) UpdateDrop(product *entities.Product) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
+
+func (m *MockDropRepository) UpdateDropWindows(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDropRepository) CheckPurchase(productID, userID uint, quantity int) error {
+	args := m.Called(productID, userID, quantity)
+	return args.Error(0)
+}
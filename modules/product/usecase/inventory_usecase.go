@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+const defaultStockMovementLimit = 50
+
+type InventoryUsecase interface {
+	Restock(productID string, actorID uint, request *entities.StockRestockRequest) (*entities.StockMovement, error)
+	AdjustStock(productID string, actorID uint, request *entities.StockAdjustmentRequest) (*entities.StockMovement, error)
+	GetMovements(productID string, filter *entities.StockMovementFilter) ([]entities.StockMovement, error)
+	ReconcileStock() (*entities.StockReconciliation, error)
+	UpdateStockRules(productID string, request *entities.StockRulesRequest) (*entities.StockRules, error)
+	GetLowStockReport() ([]entities.LowStockProduct, error)
+}
+
+type InventoryService struct {
+	repo        InventoryRepository
+	productRepo ProductRepository
+}
+
+func NewInventoryService(repo InventoryRepository, productRepo ProductRepository) InventoryUsecase {
+	return &InventoryService{repo, productRepo}
+}
+
+func (s *InventoryService) Restock(productID string, actorID uint, request *entities.StockRestockRequest) (*entities.StockMovement, error) {
+	kind := request.Kind
+	if kind == "" {
+		kind = entities.StockMovementRestock
+	}
+
+	return s.recordMovement(productID, &entities.StockMovement{
+		VariantID:   optionalID(request.VariantID),
+		WarehouseID: optionalID(request.WarehouseID),
+		Kind:        kind,
+		Quantity:    request.Quantity,
+		ActorID:     &actorID,
+		ReferenceID: request.ReferenceID,
+		Note:        request.Note,
+	})
+}
+
+func (s *InventoryService) AdjustStock(productID string, actorID uint, request *entities.StockAdjustmentRequest) (*entities.StockMovement, error) {
+	return s.recordMovement(productID, &entities.StockMovement{
+		VariantID:   optionalID(request.VariantID),
+		WarehouseID: optionalID(request.WarehouseID),
+		Kind:        entities.StockMovementAdjustment,
+		Quantity:    request.Quantity,
+		ActorID:     &actorID,
+		ReferenceID: request.ReferenceID,
+		Note:        request.Note,
+	})
+}
+
+func (s *InventoryService) GetMovements(productID string, filter *entities.StockMovementFilter) ([]entities.StockMovement, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit < 1 {
+		filter.Limit = defaultStockMovementLimit
+	}
+
+	movements, err := s.repo.GetMovements(product.ID, filter)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return movements, nil
+}
+
+// ReconcileStock reports every item whose stock column has drifted from its
+// ledger. It only reads; fixing drift is left to an explicit adjustment.
+func (s *InventoryService) ReconcileStock() (*entities.StockReconciliation, error) {
+	drifts, err := s.repo.GetStockDrift()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return &entities.StockReconciliation{
+		CheckedAt: time.Now(),
+		Drifts:    drifts,
+	}, nil
+}
+
+func (s *InventoryService) UpdateStockRules(productID string, request *entities.StockRulesRequest) (*entities.StockRules, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.LowStockThreshold != nil {
+		product.LowStockThreshold = *request.LowStockThreshold
+	}
+	if request.ShowWhenSoldOut != nil {
+		product.ShowWhenSoldOut = *request.ShowWhenSoldOut
+	}
+
+	if err := s.repo.UpdateStockRules(product); err != nil {
+		if err.Error() == "product not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return &entities.StockRules{
+		ProductID:         product.ID,
+		LowStockThreshold: product.LowStockThreshold,
+		ShowWhenSoldOut:   product.ShowWhenSoldOut,
+	}, nil
+}
+
+// GetLowStockReport lists products at or below their low-stock threshold,
+// lowest stock first.
+func (s *InventoryService) GetLowStockReport() ([]entities.LowStockProduct, error) {
+	products, err := s.repo.GetLowStockProducts()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return products, nil
+}
+
+func (s *InventoryService) recordMovement(productID string, movement *entities.StockMovement) (*entities.StockMovement, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+		return nil, errors.New("blind box stock is set by its figures")
+	}
+
+	movement.ProductID = product.ID
+
+	recorded, err := s.repo.RecordMovement(movement)
+	if err != nil {
+		switch err.Error() {
+		case "product not found", "variant not found", "variant required", "warehouse not found", "insufficient stock":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return recorded, nil
+}
+
+func (s *InventoryService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+// optionalID turns an omitted (zero) ID into nil.
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+
+	return &id
+}
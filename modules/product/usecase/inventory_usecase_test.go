@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRestock(t *testing.T) {
+	t.Run("restock defaults to a restock movement", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		actorID := uint(7)
+		movement := &entities.StockMovement{ProductID: 1, Kind: "restock", Quantity: 24, ActorID: &actorID, ReferenceID: "PO-88"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("RecordMovement", movement).Return(&entities.StockMovement{ID: 5, ProductID: 1, Kind: "restock", Quantity: 24, Balance: 30, ActorID: &actorID, ReferenceID: "PO-88"}, nil)
+
+		got, err := inventoryService.Restock("1", 7, &entities.StockRestockRequest{Quantity: 24, ReferenceID: "PO-88"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30, got.Balance)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("restock a customer return into a variant", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		actorID, variantID := uint(7), uint(3)
+		movement := &entities.StockMovement{ProductID: 1, VariantID: &variantID, Kind: "return", Quantity: 1, ActorID: &actorID, ReferenceID: "ORD-1001"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("RecordMovement", movement).Return(movement, nil)
+
+		_, err := inventoryService.Restock("1", 7, &entities.StockRestockRequest{VariantID: 3, Kind: "return", Quantity: 1, ReferenceID: "ORD-1001"})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("restock given blind box", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(&entities.Product{Model: gorm.Model{ID: 20}, Type: "blind_box"}, nil)
+
+		_, err := inventoryService.Restock("20", 7, &entities.StockRestockRequest{Quantity: 24})
+
+		assert.EqualError(t, err, "blind box stock is set by its figures")
+		mockRepo.AssertNotCalled(t, "RecordMovement", mock.Anything)
+	})
+
+	t.Run("restock given product not found", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := inventoryService.Restock("99", 7, &entities.StockRestockRequest{Quantity: 24})
+
+		assert.EqualError(t, err, "product not found")
+	})
+}
+
+func TestAdjustStock(t *testing.T) {
+	t.Run("adjust stock successfully", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		actorID := uint(7)
+		movement := &entities.StockMovement{ProductID: 1, Kind: "adjustment", Quantity: -2, ActorID: &actorID, Note: "damaged in storage"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("RecordMovement", movement).Return(&entities.StockMovement{ID: 6, ProductID: 1, Kind: "adjustment", Quantity: -2, Balance: 28, ActorID: &actorID, Note: "damaged in storage"}, nil)
+
+		got, err := inventoryService.AdjustStock("1", 7, &entities.StockAdjustmentRequest{Quantity: -2, Note: "damaged in storage"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 28, got.Balance)
+	})
+
+	t.Run("adjust stock below zero", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("RecordMovement", mock.Anything).Return((*entities.StockMovement)(nil), errors.New("insufficient stock"))
+
+		_, err := inventoryService.AdjustStock("1", 7, &entities.StockAdjustmentRequest{Quantity: -40, Note: "count"})
+
+		assert.EqualError(t, err, "insufficient stock")
+	})
+
+	t.Run("adjust stock given database error", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("RecordMovement", mock.Anything).Return((*entities.StockMovement)(nil), errors.New("failed to record stock movement"))
+
+		_, err := inventoryService.AdjustStock("1", 7, &entities.StockAdjustmentRequest{Quantity: 5, Note: "count"})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestGetStockMovements(t *testing.T) {
+	t.Run("get movements with default limit", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetMovements", uint(1), &entities.StockMovementFilter{Limit: 50}).Return([]entities.StockMovement{{ID: 9, ProductID: 1, Kind: "sale", Quantity: -1, Balance: 29}}, nil)
+
+		got, err := inventoryService.GetMovements("1", &entities.StockMovementFilter{})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+}
+
+func TestReconcileStock(t *testing.T) {
+	t.Run("reconcile stock reports drift", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		inventoryService := InventoryService{repo: mockRepo}
+
+		drifts := []entities.StockDrift{{ProductID: 4, Stock: 12, Ledger: 10}}
+		mockRepo.On("GetStockDrift").Return(drifts, nil)
+
+		got, err := inventoryService.ReconcileStock()
+
+		assert.NoError(t, err)
+		assert.Equal(t, drifts, got.Drifts)
+		assert.False(t, got.CheckedAt.IsZero())
+	})
+
+	t.Run("reconcile stock given database error", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		inventoryService := InventoryService{repo: mockRepo}
+
+		mockRepo.On("GetStockDrift").Return(([]entities.StockDrift)(nil), errors.New("connection reset"))
+
+		_, err := inventoryService.ReconcileStock()
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestUpdateStockRules(t *testing.T) {
+	t.Run("update only the rules that were sent", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		threshold := 3
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, LowStockThreshold: 5, ShowWhenSoldOut: true}, nil)
+		mockRepo.On("UpdateStockRules", &entities.Product{Model: gorm.Model{ID: 1}, LowStockThreshold: 3, ShowWhenSoldOut: true}).Return(nil)
+
+		got, err := inventoryService.UpdateStockRules("1", &entities.StockRulesRequest{LowStockThreshold: &threshold})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.StockRules{ProductID: 1, LowStockThreshold: 3, ShowWhenSoldOut: true}, got)
+	})
+
+	t.Run("update stock rules given unknown product", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockProductRepo := new(MockProductRepository)
+		inventoryService := InventoryService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := inventoryService.UpdateStockRules("99", &entities.StockRulesRequest{})
+
+		assert.EqualError(t, err, "product not found")
+		mockRepo.AssertNotCalled(t, "UpdateStockRules", mock.Anything)
+	})
+}
+
+func TestGetLowStockReport(t *testing.T) {
+	t.Run("get low stock report given database error", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		inventoryService := InventoryService{repo: mockRepo}
+
+		mockRepo.On("GetLowStockProducts").Return(([]entities.LowStockProduct)(nil), errors.New("connection reset"))
+
+		_, err := inventoryService.GetLowStockReport()
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestStockAlertDispatcher(t *testing.T) {
+	t.Run("notify admins and mark alerts notified", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockNotifier := new(MockAdminNotifier)
+		dispatcher := NewStockAlertDispatcher(mockRepo, mockNotifier, time.Minute)
+
+		alerts := []entities.StockAlert{{ID: 1, ProductID: 4, Stock: 2, Threshold: 5}, {ID: 2, ProductID: 9, Stock: 0, Threshold: 3}}
+		mockRepo.On("GetPendingStockAlerts", stockAlertBatch).Return(alerts, nil)
+		mockNotifier.On("NotifyLowStock", alerts).Return(nil)
+		mockRepo.On("MarkStockAlertsNotified", []uint{1, 2}, mock.Anything).Return(nil)
+
+		sent, err := dispatcher.Dispatch()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+	})
+
+	t.Run("leave alerts pending when notifying fails", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockNotifier := new(MockAdminNotifier)
+		dispatcher := NewStockAlertDispatcher(mockRepo, mockNotifier, time.Minute)
+
+		alerts := []entities.StockAlert{{ID: 1, ProductID: 4, Stock: 2, Threshold: 5}}
+		mockRepo.On("GetPendingStockAlerts", stockAlertBatch).Return(alerts, nil)
+		mockNotifier.On("NotifyLowStock", alerts).Return(errors.New("smtp unavailable"))
+
+		_, err := dispatcher.Dispatch()
+
+		assert.EqualError(t, err, "smtp unavailable")
+		mockRepo.AssertNotCalled(t, "MarkStockAlertsNotified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("nothing pending", func(t *testing.T) {
+		mockRepo := new(MockInventoryRepository)
+		mockNotifier := new(MockAdminNotifier)
+		dispatcher := NewStockAlertDispatcher(mockRepo, mockNotifier, time.Minute)
+
+		mockRepo.On("GetPendingStockAlerts", stockAlertBatch).Return([]entities.StockAlert{}, nil)
+
+		sent, err := dispatcher.Dispatch()
+
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+		mockNotifier.AssertNotCalled(t, "NotifyLowStock", mock.Anything)
+	})
+}
+
+type MockAdminNotifier struct {
+	mock.Mock
+}
+
+func (m *MockAdminNotifier) NotifyLowStock(alerts []entities.StockAlert) error {
+	args := m.Called(alerts)
+	return args.Error(0)
+}
+
+type MockInventoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) RecordMovement(movement *entities.StockMovement) (*entities.StockMovement, error) {
+	args := m.Called(movement)
+	return args.Get(0).(*entities.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetMovements(productID uint, filter *entities.StockMovementFilter) ([]entities.StockMovement, error) {
+	args := m.Called(productID, filter)
+	return args.Get(0).([]entities.StockMovement), args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetStockDrift() ([]entities.StockDrift, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.StockDrift), args.Error(1)
+}
+
+func (m *MockInventoryRepository) UpdateStockRules(product *entities.Product) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) GetLowStockProducts() ([]entities.LowStockProduct, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.LowStockProduct), args.Error(1)
+}
+
+func (m *MockInventoryRepository) GetPendingStockAlerts(limit int) ([]entities.StockAlert, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]entities.StockAlert), args.Error(1)
+}
+
+func (m *MockInventoryRepository) MarkStockAlertsNotified(ids []uint, notifiedAt time.Time) error {
+	args := m.Called(ids, notifiedAt)
+	return args.Error(0)
+}
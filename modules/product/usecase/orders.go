@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
+// Orders is what the product service needs from the order service.
+type Orders interface {
+	// PlaceOrder opens a pending order for the user for one unit of a
+	// product, or of one of its variants, at price.
+	PlaceOrder(userID, productID uint, variantID *uint, price money.Money) (*entities.PlacedOrder, error)
+	// SettleOrder marks the order referenced by referenceID as paid amount,
+	// or fails with "payment amount does not match" when that is not its
+	// total.
+	SettleOrder(referenceID string, amount money.Money) error
+	// CancelOrder cancels the user's order referenced by referenceID while it
+	// is still pending.
+	CancelOrder(userID uint, referenceID string) error
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"gorm.io/gorm"
+)
+
+type PreOrderUsecase interface {
+	ConfigurePreOrder(productID string, request *entities.PreOrderOfferRequest) (*entities.PreOrderOffer, error)
+	GetPreOrder(productID string) (*entities.PreOrderOffer, error)
+	PlacePreOrder(productID string, userID uint, request *entities.PreOrderRequest) (*entities.PreOrderLine, error)
+	GetPreOrders(userID uint) ([]entities.PreOrderLine, error)
+	ReleasePreOrders(productID string, request *entities.PreOrderReleaseRequest) ([]entities.PreOrderLine, error)
+	SettlePreOrderPayment(confirmation *payment.Confirmation) (*entities.PreOrderLine, error)
+}
+
+// preOrderPaymentWindow is how long a new pre-order holds its place in the
+// cap for its deposit to be paid.
+const preOrderPaymentWindow = 30 * time.Minute
+
+type PreOrderService struct {
+	repo        PreOrderRepository
+	productRepo ProductRepository
+}
+
+func NewPreOrderService(repo PreOrderRepository, productRepo ProductRepository) PreOrderUsecase {
+	return &PreOrderService{repo, productRepo}
+}
+
+// ConfigurePreOrder puts a product up for pre-order, or changes the terms of
+// its offer. The deposit and cap of lines already placed are left as they
+// were, so the cap cannot drop below what has been taken.
+func (s *PreOrderService) ConfigurePreOrder(productID string, request *entities.PreOrderOfferRequest) (*entities.PreOrderOffer, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(product.Variants) > 0 || product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+		return nil, errors.New("product cannot be pre-ordered")
+	}
+
+	deposit := request.Deposit.OrCurrency(money.DefaultCurrency)
+	if deposit.Currency != money.DefaultCurrency {
+		return nil, errors.New("unsupported currency")
+	}
+
+	if !deposit.Less(product.Price) {
+		return nil, errors.New("deposit must be below the price")
+	}
+
+	offer, err := s.repo.GetOffer(product.ID)
+	if err != nil {
+		if err.Error() != "pre-order not found" {
+			return nil, errors.New("database error")
+		}
+		offer = &entities.PreOrderOffer{ProductID: product.ID, Open: true}
+	}
+
+	if request.Cap < offer.Taken {
+		return nil, errors.New("cap is below pre-orders taken")
+	}
+
+	offer.ExpectedShipDate = request.ExpectedShipDate
+	offer.Deposit = deposit
+	offer.Cap = request.Cap
+	if request.Open != nil {
+		offer.Open = *request.Open
+	}
+
+	saved, err := s.repo.SaveOffer(offer)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return saved, nil
+}
+
+func (s *PreOrderService) GetPreOrder(productID string) (*entities.PreOrderOffer, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.repo.GetOffer(product.ID)
+	if err != nil {
+		if err.Error() == "pre-order not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return offer, nil
+}
+
+// PlacePreOrder takes units of the cap for a pre-order whose deposit, or full
+// price when the offer takes no deposit, is due under the line's reference
+// within the payment window. The price, sale price included, is fixed at the
+// time of ordering; a sale that takes it below the deposit is paid in full.
+func (s *PreOrderService) PlacePreOrder(productID string, userID uint, request *entities.PreOrderRequest) (*entities.PreOrderLine, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.repo.GetOffer(product.ID)
+	if err != nil {
+		if err.Error() == "pre-order not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	now := time.Now()
+	price := effectivePrice(product, now)
+	total := price.Mul(request.Quantity)
+	deposit := total
+	if offer.Deposit.IsPositive() && offer.Deposit.Less(price) {
+		deposit = offer.Deposit.Mul(request.Quantity)
+	}
+
+	balance, err := total.Sub(deposit)
+	if err != nil {
+		return nil, err
+	}
+
+	dueAt := now.Add(preOrderPaymentWindow)
+	line, err := s.repo.InsertLine(&entities.PreOrderLine{
+		ProductID:    product.ID,
+		UserID:       userID,
+		Quantity:     request.Quantity,
+		UnitPrice:    price,
+		DepositPaid:  deposit,
+		BalanceDue:   balance,
+		Status:       entities.PreOrderAwaitingDeposit,
+		PaymentDueAt: &dueAt,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "pre-order not found", "pre-orders are closed", "pre-order cap reached":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return line, nil
+}
+
+func (s *PreOrderService) GetPreOrders(userID uint) ([]entities.PreOrderLine, error) {
+	lines, err := s.repo.GetLinesByUser(userID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return lines, nil
+}
+
+// ReleasePreOrders sells arrived stock to waiting pre-orders, oldest first,
+// and returns the lines released. Lines with a balance left are returned with
+// the reference to charge it under.
+func (s *PreOrderService) ReleasePreOrders(productID string, request *entities.PreOrderReleaseRequest) ([]entities.PreOrderLine, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.repo.ReleaseLines(product.ID, request.Limit, time.Now())
+	if err != nil {
+		if err.Error() == "product not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return lines, nil
+}
+
+// SettlePreOrderPayment records a deposit or balance once the payment
+// provider confirms it was paid in full under the reference the line issued
+// for it. A deposit confirmed after the payment window is refused.
+func (s *PreOrderService) SettlePreOrderPayment(confirmation *payment.Confirmation) (*entities.PreOrderLine, error) {
+	line, err := s.repo.PayLine(confirmation.ReferenceID, confirmation.Amount, time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "pre-order not found", "pre-order has no balance due", "payment deadline has passed", "payment amount does not match":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return line, nil
+}
+
+func (s *PreOrderService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
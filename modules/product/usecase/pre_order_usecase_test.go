@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestConfigurePreOrder(t *testing.T) {
+	shipDate := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("open a new pre-order offer", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return((*entities.PreOrderOffer)(nil), errors.New("pre-order not found"))
+		mockRepo.On("SaveOffer", mock.MatchedBy(func(o *entities.PreOrderOffer) bool {
+			return o.ID == 0 && o.ProductID == 1 && o.Open && o.Cap == 50 && o.Deposit == money.New(2000, "THB") && o.ExpectedShipDate.Equal(shipDate)
+		})).Return(&entities.PreOrderOffer{Model: gorm.Model{ID: 3}, ProductID: 1, Cap: 50, Open: true}, nil)
+
+		got, err := preOrderService.ConfigurePreOrder("1", &entities.PreOrderOfferRequest{
+			ExpectedShipDate: shipDate,
+			Deposit:          money.New(2000, "THB"),
+			Cap:              50,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), got.ID)
+	})
+
+	t.Run("close an existing offer", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		closed := false
+		offer := &entities.PreOrderOffer{Model: gorm.Model{ID: 3}, ProductID: 1, Cap: 50, Taken: 10, Open: true}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return(offer, nil)
+		mockRepo.On("SaveOffer", offer).Return(offer, nil)
+
+		got, err := preOrderService.ConfigurePreOrder("1", &entities.PreOrderOfferRequest{
+			ExpectedShipDate: shipDate,
+			Cap:              40,
+			Open:             &closed,
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, got.Open)
+		assert.Equal(t, 40, got.Cap)
+		assert.Equal(t, 10, got.Taken)
+	})
+
+	tests := []struct {
+		name    string
+		product *entities.Product
+		offer   *entities.PreOrderOffer
+		request *entities.PreOrderOfferRequest
+		wantErr string
+	}{
+		{
+			name:    "product with variants",
+			product: &entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB"), Variants: []entities.ProductVariant{{SKU: "A"}}},
+			request: &entities.PreOrderOfferRequest{ExpectedShipDate: shipDate, Cap: 10},
+			wantErr: "product cannot be pre-ordered",
+		},
+		{
+			name:    "blind box",
+			product: &entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB"), Type: entities.ProductTypeBlindBox},
+			request: &entities.PreOrderOfferRequest{ExpectedShipDate: shipDate, Cap: 10},
+			wantErr: "product cannot be pre-ordered",
+		},
+		{
+			name:    "deposit as high as the price",
+			product: &entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")},
+			request: &entities.PreOrderOfferRequest{ExpectedShipDate: shipDate, Deposit: money.New(10000, "THB"), Cap: 10},
+			wantErr: "deposit must be below the price",
+		},
+		{
+			name:    "cap below pre-orders taken",
+			product: &entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")},
+			offer:   &entities.PreOrderOffer{Model: gorm.Model{ID: 3}, ProductID: 1, Cap: 50, Taken: 30},
+			request: &entities.PreOrderOfferRequest{ExpectedShipDate: shipDate, Cap: 20},
+			wantErr: "cap is below pre-orders taken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("configure pre-order given "+tt.name, func(t *testing.T) {
+			mockRepo := new(MockPreOrderRepository)
+			mockProductRepo := new(MockProductRepository)
+			preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+			mockProductRepo.On("GetProductById", "1").Return(tt.product, nil)
+			if tt.offer != nil {
+				mockRepo.On("GetOffer", uint(1)).Return(tt.offer, nil)
+			} else {
+				mockRepo.On("GetOffer", uint(1)).Return((*entities.PreOrderOffer)(nil), errors.New("pre-order not found"))
+			}
+
+			_, err := preOrderService.ConfigurePreOrder("1", tt.request)
+
+			assert.EqualError(t, err, tt.wantErr)
+			mockRepo.AssertNotCalled(t, "SaveOffer", mock.Anything)
+		})
+	}
+
+	t.Run("configure pre-order given product not found", func(t *testing.T) {
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: new(MockPreOrderRepository), productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "9").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := preOrderService.ConfigurePreOrder("9", &entities.PreOrderOfferRequest{ExpectedShipDate: shipDate, Cap: 10})
+
+		assert.EqualError(t, err, "product not found")
+	})
+}
+
+func TestPlacePreOrder(t *testing.T) {
+	t.Run("place pre-order with a deposit", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(9990, "THB")}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return(&entities.PreOrderOffer{ProductID: 1, Deposit: money.New(2000, "THB"), Cap: 50, Open: true}, nil)
+		mockRepo.On("InsertLine", mock.MatchedBy(func(l *entities.PreOrderLine) bool {
+			return l.ProductID == 1 && l.UserID == 5 && l.Quantity == 2 && l.UnitPrice == money.New(9990, "THB") &&
+				l.DepositPaid == money.New(4000, "THB") && l.BalanceDue == money.New(15980, "THB") &&
+				l.Status == entities.PreOrderAwaitingDeposit && l.PaymentDueAt != nil
+		})).Return(&entities.PreOrderLine{ID: 8, Status: entities.PreOrderAwaitingDeposit, ReferenceID: "a1b2"}, nil)
+
+		got, err := preOrderService.PlacePreOrder("1", 5, &entities.PreOrderRequest{Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(8), got.ID)
+	})
+
+	t.Run("place pre-order paid in full", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(5000, "THB")}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return(&entities.PreOrderOffer{ProductID: 1, Cap: 50, Open: true}, nil)
+		mockRepo.On("InsertLine", mock.MatchedBy(func(l *entities.PreOrderLine) bool {
+			return l.DepositPaid == money.New(15000, "THB") && l.BalanceDue == money.New(0, "THB")
+		})).Return(&entities.PreOrderLine{ID: 8}, nil)
+
+		_, err := preOrderService.PlacePreOrder("1", 5, &entities.PreOrderRequest{Quantity: 3})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("place pre-order on sale below the deposit", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		salePrice := money.New(1500, "THB")
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(5000, "THB"), SalePrice: &salePrice}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return(&entities.PreOrderOffer{ProductID: 1, Deposit: money.New(2000, "THB"), Cap: 50, Open: true}, nil)
+		mockRepo.On("InsertLine", mock.MatchedBy(func(l *entities.PreOrderLine) bool {
+			return l.UnitPrice == money.New(1500, "THB") && l.DepositPaid == money.New(3000, "THB") && l.BalanceDue == money.New(0, "THB")
+		})).Return(&entities.PreOrderLine{ID: 8}, nil)
+
+		_, err := preOrderService.PlacePreOrder("1", 5, &entities.PreOrderRequest{Quantity: 2})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("place pre-order given cap reached", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(5000, "THB")}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return(&entities.PreOrderOffer{ProductID: 1, Cap: 1, Taken: 1, Open: true}, nil)
+		mockRepo.On("InsertLine", mock.Anything).Return((*entities.PreOrderLine)(nil), errors.New("pre-order cap reached"))
+
+		_, err := preOrderService.PlacePreOrder("1", 5, &entities.PreOrderRequest{Quantity: 1})
+
+		assert.EqualError(t, err, "pre-order cap reached")
+	})
+
+	t.Run("place pre-order given product not up for pre-order", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(5000, "THB")}, nil)
+		mockRepo.On("GetOffer", uint(1)).Return((*entities.PreOrderOffer)(nil), errors.New("pre-order not found"))
+
+		_, err := preOrderService.PlacePreOrder("1", 5, &entities.PreOrderRequest{Quantity: 1})
+
+		assert.EqualError(t, err, "pre-order not found")
+		mockRepo.AssertNotCalled(t, "InsertLine", mock.Anything)
+	})
+}
+
+func TestReleasePreOrders(t *testing.T) {
+	t.Run("release waiting pre-orders", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		released := []entities.PreOrderLine{{ID: 8, Status: entities.PreOrderAwaitingBalance}}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("ReleaseLines", uint(1), 10, mock.AnythingOfType("time.Time")).Return(released, nil)
+
+		got, err := preOrderService.ReleasePreOrders("1", &entities.PreOrderReleaseRequest{Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, released, got)
+	})
+
+	t.Run("release pre-orders given database error", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		mockProductRepo := new(MockProductRepository)
+		preOrderService := PreOrderService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("ReleaseLines", uint(1), 0, mock.AnythingOfType("time.Time")).Return(([]entities.PreOrderLine)(nil), errors.New("failed to record stock movement"))
+
+		_, err := preOrderService.ReleasePreOrders("1", &entities.PreOrderReleaseRequest{})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestSettlePreOrderPayment(t *testing.T) {
+	t.Run("settle a confirmed deposit", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		preOrderService := PreOrderService{repo: mockRepo}
+
+		mockRepo.On("PayLine", "a1b2", money.New(4000, "THB"), mock.AnythingOfType("time.Time")).
+			Return(&entities.PreOrderLine{ID: 8, Status: entities.PreOrderAwaitingStock}, nil)
+
+		got, err := preOrderService.SettlePreOrderPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(4000, "THB")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.PreOrderAwaitingStock, got.Status)
+	})
+
+	t.Run("settle given no balance due", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		preOrderService := PreOrderService{repo: mockRepo}
+
+		mockRepo.On("PayLine", "c3d4", money.New(16000, "THB"), mock.Anything).Return((*entities.PreOrderLine)(nil), errors.New("pre-order has no balance due"))
+
+		_, err := preOrderService.SettlePreOrderPayment(&payment.Confirmation{ReferenceID: "c3d4", Amount: money.New(16000, "THB")})
+
+		assert.EqualError(t, err, "pre-order has no balance due")
+	})
+
+	t.Run("settle given database error", func(t *testing.T) {
+		mockRepo := new(MockPreOrderRepository)
+		preOrderService := PreOrderService{repo: mockRepo}
+
+		mockRepo.On("PayLine", "a1b2", money.New(4000, "THB"), mock.Anything).Return((*entities.PreOrderLine)(nil), errors.New("connection reset"))
+
+		_, err := preOrderService.SettlePreOrderPayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(4000, "THB")})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+type MockPreOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockPreOrderRepository) SaveOffer(offer *entities.PreOrderOffer) (*entities.PreOrderOffer, error) {
+	args := m.Called(offer)
+	return args.Get(0).(*entities.PreOrderOffer), args.Error(1)
+}
+
+func (m *MockPreOrderRepository) GetOffer(productID uint) (*entities.PreOrderOffer, error) {
+	args := m.Called(productID)
+	return args.Get(0).(*entities.PreOrderOffer), args.Error(1)
+}
+
+func (m *MockPreOrderRepository) InsertLine(line *entities.PreOrderLine) (*entities.PreOrderLine, error) {
+	args := m.Called(line)
+	return args.Get(0).(*entities.PreOrderLine), args.Error(1)
+}
+
+func (m *MockPreOrderRepository) GetLinesByUser(userID uint) ([]entities.PreOrderLine, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entities.PreOrderLine), args.Error(1)
+}
+
+func (m *MockPreOrderRepository) ReleaseLines(productID uint, limit int, releasedAt time.Time) ([]entities.PreOrderLine, error) {
+	args := m.Called(productID, limit, releasedAt)
+	return args.Get(0).([]entities.PreOrderLine), args.Error(1)
+}
+
+func (m *MockPreOrderRepository) PayLine(referenceID string, amount money.Money, now time.Time) (*entities.PreOrderLine, error) {
+	args := m.Called(referenceID, amount, now)
+	return args.Get(0).(*entities.PreOrderLine), args.Error(1)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PriceScheduler applies scheduled price changes in the background so list
+// prices change at their announced time without anyone touching the product.
+type PriceScheduler struct {
+	usecase  PricingUsecase
+	interval time.Duration
+}
+
+func NewPriceScheduler(usecase PricingUsecase, interval time.Duration) *PriceScheduler {
+	return &PriceScheduler{usecase, interval}
+}
+
+// Run applies due price changes every interval until ctx is cancelled.
+func (s *PriceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.apply()
+		}
+	}
+}
+
+func (s *PriceScheduler) apply() {
+	applied, err := s.usecase.ApplyDuePrices()
+	if err != nil {
+		log.Printf("failed to apply scheduled prices: %v", err)
+	}
+
+	if applied > 0 {
+		log.Printf("applied %d scheduled prices", applied)
+	}
+}
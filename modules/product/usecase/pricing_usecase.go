@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+)
+
+const priceBatch = 100
+
+type PricingUsecase interface {
+	SetSale(productID string, actorID uint, request *entities.SaleRequest) (*entities.ProductResponse, error)
+	EndSale(productID string, actorID uint) error
+	GetPriceHistory(productID string) ([]entities.PriceChange, error)
+	SchedulePrice(productID string, actorID uint, request *entities.ScheduledPriceRequest) (*entities.ScheduledPrice, error)
+	GetScheduledPrices(productID string) ([]entities.ScheduledPrice, error)
+	CancelScheduledPrice(productID, id string) error
+	ApplyDuePrices() (int, error)
+}
+
+type PricingService struct {
+	repo        PricingRepository
+	productRepo ProductRepository
+}
+
+func NewPricingService(repo PricingRepository, productRepo ProductRepository) PricingUsecase {
+	return &PricingService{repo, productRepo}
+}
+
+// SetSale puts a product on sale, replacing any sale it already has. Products
+// with variants are priced by their variants and cannot go on sale.
+func (s *PricingService) SetSale(productID string, actorID uint, request *entities.SaleRequest) (*entities.ProductResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(product.Variants) > 0 {
+		return nil, errors.New("product with variants cannot go on sale")
+	}
+
+	salePrice, err := storePrice(request.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	if !salePrice.Less(product.Price) {
+		return nil, errors.New("sale price must be below the price")
+	}
+
+	if request.EndsAt != nil {
+		if !request.EndsAt.After(time.Now()) {
+			return nil, errors.New("sale has already ended")
+		}
+		if request.StartsAt != nil && !request.EndsAt.After(*request.StartsAt) {
+			return nil, errors.New("sale must end after it starts")
+		}
+	}
+
+	product.SalePrice = &salePrice
+	product.SaleStartsAt = request.StartsAt
+	product.SaleEndsAt = request.EndsAt
+
+	if err := s.repo.UpdateSale(product, actorID); err != nil {
+		if err.Error() == "product not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	response := newProductResponse(product)
+	return &response, nil
+}
+
+// EndSale takes a product off sale straight away.
+func (s *PricingService) EndSale(productID string, actorID uint) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	if product.SalePrice == nil {
+		return errors.New("sale not found")
+	}
+
+	product.SalePrice = nil
+	product.SaleStartsAt = nil
+	product.SaleEndsAt = nil
+
+	if err := s.repo.UpdateSale(product, actorID); err != nil {
+		if err.Error() == "product not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *PricingService) GetPriceHistory(productID string) ([]entities.PriceChange, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetPriceHistory(product.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return history, nil
+}
+
+// SchedulePrice changes a product's list price at a time to come. The price
+// scheduler applies it.
+func (s *PricingService) SchedulePrice(productID string, actorID uint, request *entities.ScheduledPriceRequest) (*entities.ScheduledPrice, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := storePrice(request.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.EffectiveAt.After(time.Now()) {
+		return nil, errors.New("price change must be in the future")
+	}
+
+	scheduled, err := s.repo.InsertScheduledPrice(&entities.ScheduledPrice{
+		ProductID:   product.ID,
+		Price:       price,
+		EffectiveAt: request.EffectiveAt,
+		ActorID:     &actorID,
+	})
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return scheduled, nil
+}
+
+func (s *PricingService) GetScheduledPrices(productID string) ([]entities.ScheduledPrice, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled, err := s.repo.GetScheduledPrices(product.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return scheduled, nil
+}
+
+func (s *PricingService) CancelScheduledPrice(productID, id string) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteScheduledPrice(product.ID, id); err != nil {
+		if err.Error() == "scheduled price not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// ApplyDuePrices applies every scheduled price change whose time has come and
+// returns how many were applied.
+func (s *PricingService) ApplyDuePrices() (int, error) {
+	total := 0
+
+	for {
+		now := time.Now()
+
+		scheduled, err := s.repo.GetDueScheduledPrices(now, priceBatch)
+		if err != nil {
+			return total, errors.New("database error")
+		}
+
+		for _, change := range scheduled {
+			applied, err := s.repo.ApplyScheduledPrice(change.ID, now)
+			if err != nil {
+				return total, errors.New("database error")
+			}
+			if applied {
+				total++
+			}
+		}
+
+		if len(scheduled) < priceBatch {
+			return total, nil
+		}
+	}
+}
+
+func (s *PricingService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+// onSale reports whether the product's sale price applies at now. A sale
+// price that is not below the list price, which can happen when the list
+// price is cut during a sale, is ignored, as is a sale on a product priced by
+// its variants.
+func onSale(product *entities.Product, now time.Time) bool {
+	return product.SalePrice != nil && product.SalePrice.Less(product.Price) && len(product.Variants) == 0 &&
+		(product.SaleStartsAt == nil || !now.Before(*product.SaleStartsAt)) &&
+		(product.SaleEndsAt == nil || now.Before(*product.SaleEndsAt))
+}
+
+// effectivePrice is what the product sells for at now.
+func effectivePrice(product *entities.Product, now time.Time) money.Money {
+	if onSale(product, now) {
+		return *product.SalePrice
+	}
+
+	return product.Price
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestEffectivePrice(t *testing.T) {
+	startsAt := time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(48 * time.Hour)
+	salePrice := money.New(8000, "THB")
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want money.Money
+	}{
+		{"before the sale", startsAt.Add(-time.Minute), money.New(10000, "THB")},
+		{"at the start", startsAt, money.New(8000, "THB")},
+		{"during the sale", startsAt.Add(time.Hour), money.New(8000, "THB")},
+		{"at the end", endsAt, money.New(10000, "THB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := &entities.Product{Price: money.New(10000, "THB"), SalePrice: &salePrice, SaleStartsAt: &startsAt, SaleEndsAt: &endsAt}
+			assert.Equal(t, tt.want, effectivePrice(product, tt.now))
+		})
+	}
+
+	t.Run("open-ended sale", func(t *testing.T) {
+		product := &entities.Product{Price: money.New(10000, "THB"), SalePrice: &salePrice}
+		assert.Equal(t, money.New(8000, "THB"), effectivePrice(product, endsAt))
+	})
+
+	t.Run("sale price above a cut list price", func(t *testing.T) {
+		product := &entities.Product{Price: money.New(7000, "THB"), SalePrice: &salePrice}
+		assert.Equal(t, money.New(7000, "THB"), effectivePrice(product, startsAt))
+	})
+}
+
+func TestNewProductResponseOnSale(t *testing.T) {
+	salePrice := money.New(8000, "THB")
+	endsAt := time.Now().Add(time.Hour)
+
+	got := newProductResponse(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB"), SalePrice: &salePrice, SaleEndsAt: &endsAt})
+
+	assert.Equal(t, money.New(8000, "THB"), got.Price)
+	assert.Equal(t, money.New(10000, "THB"), *got.CompareAtPrice)
+	assert.Equal(t, &endsAt, got.SaleEndsAt)
+}
+
+func TestSetSale(t *testing.T) {
+	t.Run("put product on sale", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		endsAt := time.Now().Add(24 * time.Hour)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+		mockRepo.On("UpdateSale", mock.MatchedBy(func(p *entities.Product) bool {
+			return *p.SalePrice == money.New(7500, "THB") && p.SaleStartsAt == nil && p.SaleEndsAt.Equal(endsAt)
+		}), uint(9)).Return(nil)
+
+		got, err := pricingService.SetSale("1", 9, &entities.SaleRequest{Price: money.New(7500, "THB"), EndsAt: &endsAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(7500, "THB"), got.Price)
+		assert.Equal(t, money.New(10000, "THB"), *got.CompareAtPrice)
+	})
+
+	past := time.Now().Add(-time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+	sooner := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		request *entities.SaleRequest
+		wantErr string
+	}{
+		{"sale price at the list price", &entities.SaleRequest{Price: money.New(10000, "THB")}, "sale price must be below the price"},
+		{"sale that has ended", &entities.SaleRequest{Price: money.New(7500, "THB"), EndsAt: &past}, "sale has already ended"},
+		{"sale ending before it starts", &entities.SaleRequest{Price: money.New(7500, "THB"), StartsAt: &later, EndsAt: &sooner}, "sale must end after it starts"},
+	}
+
+	for _, tt := range tests {
+		t.Run("set sale given "+tt.name, func(t *testing.T) {
+			mockRepo := new(MockPricingRepository)
+			mockProductRepo := new(MockProductRepository)
+			pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+			mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+
+			_, err := pricingService.SetSale("1", 9, tt.request)
+
+			assert.EqualError(t, err, tt.wantErr)
+			mockRepo.AssertNotCalled(t, "UpdateSale", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("set sale given a product with variants", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{
+			Model:    gorm.Model{ID: 1},
+			Price:    money.New(10000, "THB"),
+			Variants: []entities.ProductVariant{{Model: gorm.Model{ID: 3}, Price: money.New(59000, "THB")}},
+		}, nil)
+
+		_, err := pricingService.SetSale("1", 9, &entities.SaleRequest{Price: money.New(7500, "THB")})
+
+		assert.EqualError(t, err, "product with variants cannot go on sale")
+		mockRepo.AssertNotCalled(t, "UpdateSale", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEndSale(t *testing.T) {
+	t.Run("end sale", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		salePrice := money.New(7500, "THB")
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB"), SalePrice: &salePrice}, nil)
+		mockRepo.On("UpdateSale", mock.MatchedBy(func(p *entities.Product) bool {
+			return p.SalePrice == nil && p.SaleStartsAt == nil && p.SaleEndsAt == nil
+		}), uint(9)).Return(nil)
+
+		err := pricingService.EndSale("1", 9)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("end sale of a product not on sale", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+
+		err := pricingService.EndSale("1", 9)
+
+		assert.EqualError(t, err, "sale not found")
+		mockRepo.AssertNotCalled(t, "UpdateSale", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSchedulePrice(t *testing.T) {
+	t.Run("schedule a price change", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		effectiveAt := time.Now().Add(24 * time.Hour)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+		mockRepo.On("InsertScheduledPrice", mock.MatchedBy(func(s *entities.ScheduledPrice) bool {
+			return s.ProductID == 1 && s.Price == money.New(12000, "THB") && s.EffectiveAt.Equal(effectiveAt) && *s.ActorID == 9
+		})).Return(&entities.ScheduledPrice{Model: gorm.Model{ID: 4}, ProductID: 1, Price: money.New(12000, "THB")}, nil)
+
+		got, err := pricingService.SchedulePrice("1", 9, &entities.ScheduledPriceRequest{Price: money.New(12000, "THB"), EffectiveAt: effectiveAt})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), got.ID)
+	})
+
+	t.Run("schedule a price change in the past", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB")}, nil)
+
+		_, err := pricingService.SchedulePrice("1", 9, &entities.ScheduledPriceRequest{Price: money.New(12000, "THB"), EffectiveAt: time.Now().Add(-time.Minute)})
+
+		assert.EqualError(t, err, "price change must be in the future")
+		mockRepo.AssertNotCalled(t, "InsertScheduledPrice", mock.Anything)
+	})
+}
+
+func TestCancelScheduledPrice(t *testing.T) {
+	t.Run("cancel a price change already applied", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		mockProductRepo := new(MockProductRepository)
+		pricingService := PricingService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("DeleteScheduledPrice", uint(1), "4").Return(errors.New("scheduled price not found"))
+
+		err := pricingService.CancelScheduledPrice("1", "4")
+
+		assert.EqualError(t, err, "scheduled price not found")
+	})
+}
+
+func TestApplyDuePrices(t *testing.T) {
+	t.Run("apply due price changes", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		pricingService := PricingService{repo: mockRepo}
+
+		mockRepo.On("GetDueScheduledPrices", mock.AnythingOfType("time.Time"), priceBatch).
+			Return([]entities.ScheduledPrice{{Model: gorm.Model{ID: 4}}, {Model: gorm.Model{ID: 5}}}, nil)
+		mockRepo.On("ApplyScheduledPrice", uint(4), mock.AnythingOfType("time.Time")).Return(true, nil)
+		mockRepo.On("ApplyScheduledPrice", uint(5), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		applied, err := pricingService.ApplyDuePrices()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, applied)
+	})
+
+	t.Run("apply due price changes given database error", func(t *testing.T) {
+		mockRepo := new(MockPricingRepository)
+		pricingService := PricingService{repo: mockRepo}
+
+		mockRepo.On("GetDueScheduledPrices", mock.AnythingOfType("time.Time"), priceBatch).
+			Return(([]entities.ScheduledPrice)(nil), errors.New("connection refused"))
+
+		_, err := pricingService.ApplyDuePrices()
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+type MockPricingRepository struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepository) UpdateSale(product *entities.Product, actorID uint) error {
+	args := m.Called(product, actorID)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepository) GetPriceHistory(productID uint) ([]entities.PriceChange, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.PriceChange), args.Error(1)
+}
+
+func (m *MockPricingRepository) InsertScheduledPrice(scheduled *entities.ScheduledPrice) (*entities.ScheduledPrice, error) {
+	args := m.Called(scheduled)
+	return args.Get(0).(*entities.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockPricingRepository) GetScheduledPrices(productID uint) ([]entities.ScheduledPrice, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeleteScheduledPrice(productID uint, id string) error {
+	args := m.Called(productID, id)
+	return args.Error(0)
+}
+
+func (m *MockPricingRepository) GetDueScheduledPrices(now time.Time, limit int) ([]entities.ScheduledPrice, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]entities.ScheduledPrice), args.Error(1)
+}
+
+func (m *MockPricingRepository) ApplyScheduledPrice(id uint, now time.Time) (bool, error) {
+	args := m.Called(id, now)
+	return args.Bool(0), args.Error(1)
+}
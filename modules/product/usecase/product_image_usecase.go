@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/imaging"
+	"github.com/phetployst/art-toys-store/pkg/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxProductImageSize = 10 << 20
+
+	productImageLargeEdge  = 1600
+	productImageMediumEdge = 800
+	productImageThumbSize  = 200
+)
+
+type ProductImageUsecase interface {
+	UploadProductImage(productID string, data []byte, altText string, isPrimary bool) (*entities.ProductImageResponse, error)
+	GetProductImages(productID string) ([]entities.ProductImageResponse, error)
+	UpdateProductImage(productID, imageID string, update *entities.ProductImageUpdate) (*entities.ProductImageResponse, error)
+	ReorderProductImages(productID string, order *entities.ProductImageOrder) ([]entities.ProductImageResponse, error)
+	DeleteProductImage(productID, imageID string) error
+}
+
+type ProductImageService struct {
+	repo        ProductImageRepository
+	productRepo ProductRepository
+	storage     storage.Storage
+}
+
+func NewProductImageService(repo ProductImageRepository, productRepo ProductRepository, storage storage.Storage) ProductImageUsecase {
+	return &ProductImageService{repo, productRepo, storage}
+}
+
+func (s *ProductImageService) UploadProductImage(productID string, data []byte, altText string, isPrimary bool) (*entities.ProductImageResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := imaging.Decode(data, MaxProductImageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("products/%d/%d", product.ID, time.Now().UnixNano())
+	variants := []struct {
+		name string
+		img  image.Image
+	}{
+		{"large", imaging.Fit(img, productImageLargeEdge)},
+		{"medium", imaging.Fit(img, productImageMediumEdge)},
+		{"thumb", imaging.SquareThumbnail(img, productImageThumbSize)},
+	}
+
+	urls := make(map[string]string, len(variants))
+	for _, variant := range variants {
+		encoded, err := imaging.EncodeJPEG(variant.img)
+		if err != nil {
+			s.deleteFiles(prefix)
+			return nil, errors.New("internal server error")
+		}
+
+		url, err := s.storage.Put(prefix+"/"+variant.name+".jpg", "image/jpeg", encoded)
+		if err != nil {
+			s.deleteFiles(prefix)
+			return nil, errors.New("internal server error")
+		}
+		urls[variant.name] = url
+	}
+
+	existing, err := s.repo.GetProductImages(product.ID)
+	if err != nil {
+		s.deleteFiles(prefix)
+		return nil, errors.New("database error")
+	}
+
+	newImage, err := s.repo.InsertProductImage(&entities.ProductImage{
+		ProductID:    product.ID,
+		URL:          urls["large"],
+		MediumURL:    urls["medium"],
+		ThumbnailURL: urls["thumb"],
+		StorageKey:   prefix,
+		AltText:      altText,
+		SortOrder:    len(existing),
+	})
+	if err != nil {
+		s.deleteFiles(prefix)
+		return nil, errors.New("database error")
+	}
+
+	if isPrimary || len(existing) == 0 {
+		if err := s.repo.SetPrimaryProductImage(product.ID, newImage.ID); err != nil {
+			return nil, errors.New("database error")
+		}
+		newImage.IsPrimary = true
+	}
+
+	response := newProductImageResponse(newImage)
+	return &response, nil
+}
+
+func (s *ProductImageService) GetProductImages(productID string) ([]entities.ProductImageResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listImages(product.ID)
+}
+
+func (s *ProductImageService) UpdateProductImage(productID, imageID string, update *entities.ProductImageUpdate) (*entities.ProductImageResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	productImage, err := s.repo.GetProductImageById(product.ID, imageID)
+	if err != nil {
+		if err.Error() == "image not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	if update.AltText != nil {
+		productImage.AltText = *update.AltText
+	}
+	if update.SortOrder != nil {
+		productImage.SortOrder = *update.SortOrder
+	}
+
+	productImage, err = s.repo.UpdateProductImage(productImage)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	if update.IsPrimary != nil && *update.IsPrimary && !productImage.IsPrimary {
+		if err := s.repo.SetPrimaryProductImage(product.ID, productImage.ID); err != nil {
+			return nil, errors.New("database error")
+		}
+		productImage.IsPrimary = true
+	}
+
+	response := newProductImageResponse(productImage)
+	return &response, nil
+}
+
+func (s *ProductImageService) ReorderProductImages(productID string, order *entities.ProductImageOrder) ([]entities.ProductImageResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderProductImages(product.ID, order.ImageIDs); err != nil {
+		if err.Error() == "image not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return s.listImages(product.ID)
+}
+
+func (s *ProductImageService) DeleteProductImage(productID, imageID string) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	productImage, err := s.repo.GetProductImageById(product.ID, imageID)
+	if err != nil {
+		if err.Error() == "image not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	if err := s.repo.DeleteProductImage(productImage); err != nil {
+		return errors.New("database error")
+	}
+
+	s.deleteFiles(productImage.StorageKey)
+
+	return nil
+}
+
+// deleteFiles removes an image's files. No record points at them any more,
+// so a leftover file is only wasted space and failures are ignored.
+func (s *ProductImageService) deleteFiles(storageKey string) {
+	for _, variant := range []string{"large", "medium", "thumb"} {
+		_ = s.storage.Delete(storageKey + "/" + variant + ".jpg")
+	}
+}
+
+func (s *ProductImageService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+func (s *ProductImageService) listImages(productID uint) ([]entities.ProductImageResponse, error) {
+	images, err := s.repo.GetProductImages(productID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	responses := []entities.ProductImageResponse{}
+	for _, productImage := range images {
+		responses = append(responses, newProductImageResponse(&productImage))
+	}
+
+	return responses, nil
+}
+
+func newProductImageResponse(image *entities.ProductImage) entities.ProductImageResponse {
+	return entities.ProductImageResponse{
+		ID:           image.ID,
+		URL:          image.URL,
+		MediumURL:    image.MediumURL,
+		ThumbnailURL: image.ThumbnailURL,
+		AltText:      image.AltText,
+		SortOrder:    image.SortOrder,
+		IsPrimary:    image.IsPrimary,
+	}
+}
@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUploadProductImage(t *testing.T) {
+	t.Run("upload first image becomes primary", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}, Name: "Molly Classic"}, nil)
+		mockStorage.On("Put", mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("https://cdn.example.com/molly.jpg", nil)
+		mockRepo.On("GetProductImages", uint(12)).Return([]entities.ProductImage{}, nil)
+		mockRepo.On("InsertProductImage", mock.AnythingOfType("*entities.ProductImage")).Return(&entities.ProductImage{
+			Model: gorm.Model{ID: 3}, ProductID: 12, URL: "https://cdn.example.com/molly.jpg", MediumURL: "https://cdn.example.com/molly.jpg",
+			ThumbnailURL: "https://cdn.example.com/molly.jpg", AltText: "Molly front", SortOrder: 0,
+		}, nil)
+		mockRepo.On("SetPrimaryProductImage", uint(12), uint(3)).Return(nil)
+
+		got, err := imageService.UploadProductImage("12", testPNG(t, 60, 30), "Molly front", false)
+
+		want := &entities.ProductImageResponse{
+			ID: 3, URL: "https://cdn.example.com/molly.jpg", MediumURL: "https://cdn.example.com/molly.jpg",
+			ThumbnailURL: "https://cdn.example.com/molly.jpg", AltText: "Molly front", SortOrder: 0, IsPrimary: true,
+		}
+
+		assert.NoError(t, err)
+		mockStorage.AssertNumberOfCalls(t, "Put", 3)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v but want %v", got, want)
+		}
+
+		inserted := mockRepo.Calls[1].Arguments.Get(0).(*entities.ProductImage)
+		assert.Regexp(t, `^products/12/\d+$`, inserted.StorageKey)
+	})
+
+	t.Run("upload additional image keeps current primary", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockStorage.On("Put", mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("https://cdn.example.com/box.jpg", nil)
+		mockRepo.On("GetProductImages", uint(12)).Return([]entities.ProductImage{{Model: gorm.Model{ID: 3}, IsPrimary: true}}, nil)
+		mockRepo.On("InsertProductImage", mock.MatchedBy(func(image *entities.ProductImage) bool {
+			return image.SortOrder == 1 && image.AltText == "Box art"
+		})).Return(&entities.ProductImage{Model: gorm.Model{ID: 4}, ProductID: 12, AltText: "Box art", SortOrder: 1}, nil)
+
+		got, err := imageService.UploadProductImage("12", testPNG(t, 30, 30), "Box art", false)
+
+		assert.NoError(t, err)
+		assert.False(t, got.IsPrimary)
+		assert.Equal(t, 1, got.SortOrder)
+		mockRepo.AssertNotCalled(t, "SetPrimaryProductImage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("upload image given product not found", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := imageService.UploadProductImage("99", testPNG(t, 30, 30), "", false)
+
+		assert.EqualError(t, err, "product not found")
+	})
+
+	t.Run("upload image given database error removes stored files", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockStorage.On("Put", mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("https://cdn.example.com/box.jpg", nil)
+		mockRepo.On("GetProductImages", uint(12)).Return([]entities.ProductImage{}, nil)
+		mockRepo.On("InsertProductImage", mock.AnythingOfType("*entities.ProductImage")).Return((*entities.ProductImage)(nil), errors.New("connection reset"))
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := imageService.UploadProductImage("12", testPNG(t, 30, 30), "", false)
+
+		assert.EqualError(t, err, "database error")
+		mockStorage.AssertNumberOfCalls(t, "Delete", 3)
+		for _, call := range mockStorage.Calls {
+			assert.Regexp(t, `^products/12/\d+/`, call.Arguments.String(0))
+		}
+	})
+
+	t.Run("upload image given unsupported type", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+
+		_, err := imageService.UploadProductImage("12", []byte("plain text"), "", false)
+
+		assert.EqualError(t, err, "unsupported image type")
+		mockStorage.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateProductImage(t *testing.T) {
+	t.Run("update alt text and make primary", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo}
+
+		altText := "Back view"
+		isPrimary := true
+		productImage := &entities.ProductImage{Model: gorm.Model{ID: 4}, ProductID: 12, AltText: "old", SortOrder: 1}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("GetProductImageById", uint(12), "4").Return(productImage, nil)
+		mockRepo.On("UpdateProductImage", productImage).Return(productImage, nil)
+		mockRepo.On("SetPrimaryProductImage", uint(12), uint(4)).Return(nil)
+
+		got, err := imageService.UpdateProductImage("12", "4", &entities.ProductImageUpdate{AltText: &altText, IsPrimary: &isPrimary})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.ProductImageResponse{ID: 4, AltText: "Back view", SortOrder: 1, IsPrimary: true}, got)
+	})
+
+	t.Run("update image given image not found", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("GetProductImageById", uint(12), "40").Return((*entities.ProductImage)(nil), errors.New("image not found"))
+
+		_, err := imageService.UpdateProductImage("12", "40", &entities.ProductImageUpdate{})
+
+		assert.EqualError(t, err, "image not found")
+	})
+}
+
+func TestReorderProductImages(t *testing.T) {
+	t.Run("reorder images successfully", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("ReorderProductImages", uint(12), []uint{5, 4}).Return(nil)
+		mockRepo.On("GetProductImages", uint(12)).Return([]entities.ProductImage{
+			{Model: gorm.Model{ID: 5}, SortOrder: 0},
+			{Model: gorm.Model{ID: 4}, SortOrder: 1, IsPrimary: true},
+		}, nil)
+
+		got, err := imageService.ReorderProductImages("12", &entities.ProductImageOrder{ImageIDs: []uint{5, 4}})
+
+		want := []entities.ProductImageResponse{
+			{ID: 5, SortOrder: 0},
+			{ID: 4, SortOrder: 1, IsPrimary: true},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("reorder images given foreign image id", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("ReorderProductImages", uint(12), []uint{5, 99}).Return(errors.New("image not found"))
+
+		_, err := imageService.ReorderProductImages("12", &entities.ProductImageOrder{ImageIDs: []uint{5, 99}})
+
+		assert.EqualError(t, err, "image not found")
+	})
+}
+
+func TestDeleteProductImage(t *testing.T) {
+	t.Run("delete image and stored files", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		productImage := &entities.ProductImage{Model: gorm.Model{ID: 4}, ProductID: 12, StorageKey: "products/12/100"}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("GetProductImageById", uint(12), "4").Return(productImage, nil)
+		mockRepo.On("DeleteProductImage", productImage).Return(nil)
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+
+		err := imageService.DeleteProductImage("12", "4")
+
+		assert.NoError(t, err)
+		mockStorage.AssertCalled(t, "Delete", "products/12/100/large.jpg")
+		mockStorage.AssertCalled(t, "Delete", "products/12/100/medium.jpg")
+		mockStorage.AssertCalled(t, "Delete", "products/12/100/thumb.jpg")
+	})
+
+	t.Run("delete image given database error", func(t *testing.T) {
+		mockRepo := new(MockProductImageRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockStorage := new(MockStorage)
+		imageService := ProductImageService{repo: mockRepo, productRepo: mockProductRepo, storage: mockStorage}
+
+		productImage := &entities.ProductImage{Model: gorm.Model{ID: 4}, ProductID: 12, StorageKey: "products/12/100"}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("GetProductImageById", uint(12), "4").Return(productImage, nil)
+		mockRepo.On("DeleteProductImage", productImage).Return(errors.New("connection reset"))
+
+		err := imageService.DeleteProductImage("12", "4")
+
+		assert.EqualError(t, err, "database error")
+		mockStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 30, G: 144, B: 255, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+type MockProductImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductImageRepository) InsertProductImage(image *entities.ProductImage) (*entities.ProductImage, error) {
+	args := m.Called(image)
+	return args.Get(0).(*entities.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageRepository) GetProductImages(productID uint) ([]entities.ProductImage, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageRepository) GetProductImageById(productID uint, imageID string) (*entities.ProductImage, error) {
+	args := m.Called(productID, imageID)
+	return args.Get(0).(*entities.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageRepository) UpdateProductImage(image *entities.ProductImage) (*entities.ProductImage, error) {
+	args := m.Called(image)
+	return args.Get(0).(*entities.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageRepository) SetPrimaryProductImage(productID uint, imageID uint) error {
+	args := m.Called(productID, imageID)
+	return args.Error(0)
+}
+
+func (m *MockProductImageRepository) ReorderProductImages(productID uint, imageIDs []uint) error {
+	args := m.Called(productID, imageIDs)
+	return args.Error(0)
+}
+
+func (m *MockProductImageRepository) DeleteProductImage(image *entities.ProductImage) error {
+	args := m.Called(image)
+	return args.Error(0)
+}
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) Put(key string, contentType string, data []byte) (string, error) {
+	args := m.Called(key, contentType, data)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorage) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
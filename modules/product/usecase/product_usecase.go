@@ -2,17 +2,22 @@ package usecase
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
 )
 
 type ProductUsecase interface {
 	CreateNewProduct(product *entities.Product) (*entities.ProductResponse, error)
 	GetAllProducts() ([]entities.ProductResponse, error)
 	GetProductById(id string) (*entities.ProductResponse, error)
+	GetSellableItem(productID, variantID uint) (*entities.SellableItem, error)
 	UpdateProduct(product *entities.Product, id string) (*entities.ProductResponse, error)
 	DeductStock(id string, count *entities.CountProduct) (*entities.CountProduct, error)
-	SearchProducts(keyword string) ([]entities.ProductResponse, error)
+	ListProducts(filter *entities.ProductFilter) ([]entities.ProductResponse, error)
 }
 
 type ProductService struct {
@@ -24,18 +29,27 @@ func NewProductService(repo ProductRepository) ProductUsecase {
 }
 
 func (s *ProductService) CreateNewProduct(product *entities.Product) (*entities.ProductResponse, error) {
+	if product.Type == "" {
+		product.Type = entities.ProductTypeStandard
+	}
+
+	if product.Type != entities.ProductTypeStandard && product.SeriesID == nil {
+		return nil, errors.New("blind box requires a series")
+	}
+
+	price, err := storePrice(product.Price)
+	if err != nil {
+		return nil, err
+	}
+	product.Price = price
+
 	newProduct, err := s.repo.InsertProduct(product)
 	if err != nil {
 		return nil, errors.New("database error")
 	}
 
-	return &entities.ProductResponse{
-		ID:          newProduct.ID,
-		Name:        newProduct.Name,
-		Description: newProduct.Description,
-		Price:       newProduct.Price,
-		ImageURL:    newProduct.ImageURL,
-	}, nil
+	response := newProductResponse(newProduct)
+	return &response, nil
 
 }
 
@@ -47,13 +61,7 @@ func (s *ProductService) GetAllProducts() ([]entities.ProductResponse, error) {
 
 	var productList []entities.ProductResponse
 	for _, product := range products {
-		productList = append(productList, entities.ProductResponse{
-			ID:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       product.Price,
-			ImageURL:    product.ImageURL,
-		})
+		productList = append(productList, newProductResponse(&product))
 	}
 
 	return productList, nil
@@ -65,66 +73,133 @@ func (s *ProductService) GetProductById(productId string) (*entities.ProductResp
 		return nil, errors.New("database error")
 	}
 
-	return &entities.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		ImageURL:    product.ImageURL,
-	}, nil
+	response := newProductResponse(product)
+	return &response, nil
+}
+
+// GetSellableItem prices one unit of a product, or of one of its variants, for
+// a cart, refusing products that are not on sale.
+func (s *ProductService) GetSellableItem(productID, variantID uint) (*entities.SellableItem, error) {
+	product, err := s.repo.GetProductById(strconv.FormatUint(uint64(productID), 10))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	price, err := unitPrice(product, variantID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.SellableItem{ProductID: product.ID, VariantID: variantID, Type: product.Type, Price: price}, nil
 }
 
 func (s *ProductService) UpdateProduct(product *entities.Product, id string) (*entities.ProductResponse, error) {
+	price, err := storePrice(product.Price)
+	if err != nil {
+		return nil, err
+	}
+	product.Price = price
+
 	productUpdated, err := s.repo.UpdateProduct(product, id)
 	if err != nil {
 		return nil, errors.New("database error")
 	}
 
-	return &entities.ProductResponse{
-		ID:          productUpdated.ID,
-		Name:        productUpdated.Name,
-		Description: productUpdated.Description,
-		Price:       productUpdated.Price,
-		ImageURL:    productUpdated.ImageURL,
-	}, nil
+	response := newProductResponse(productUpdated)
+	return &response, nil
 }
 
 func (s *ProductService) DeductStock(id string, count *entities.CountProduct) (*entities.CountProduct, error) {
 
-	newStock, err := s.repo.UpdateStock(id, count.Count)
+	newStock, err := s.repo.UpdateStock(id, count.VariantID, count.Count, count.ReferenceID)
 	if err != nil {
-		if err.Error() == "product not found" {
-			return nil, errors.New("product not found")
-		} else if err.Error() == "insufficient stock" {
-			return nil, errors.New("insufficient stock")
+		switch err.Error() {
+		case "product not found", "variant not found", "variant required", "insufficient stock", "product is not on sale":
+			return nil, err
 		}
 		return nil, errors.New("database error")
 	}
 
 	return &entities.CountProduct{
-		Count: newStock,
+		VariantID:   count.VariantID,
+		Count:       newStock,
+		ReferenceID: count.ReferenceID,
 	}, nil
 }
 
-func (s *ProductService) SearchProducts(keyword string) ([]entities.ProductResponse, error) {
-	products, err := s.repo.SearchProducts(keyword)
+func (s *ProductService) ListProducts(filter *entities.ProductFilter) ([]entities.ProductResponse, error) {
+	products, err := s.repo.GetProductsByFilter(filter)
 	if err != nil {
-		if err.Error() == "product not found" {
-			return nil, errors.New("product not found")
-		}
-		return nil, err
+		return nil, errors.New("database error")
 	}
 
-	var productList []entities.ProductResponse
+	productList := []entities.ProductResponse{}
 	for _, product := range products {
-		productList = append(productList, entities.ProductResponse{
-			ID:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       product.Price,
-			ImageURL:    product.ImageURL,
-		})
+		productList = append(productList, newProductResponse(&product))
 	}
 
 	return productList, nil
 }
+
+// storePrice checks a price set by an admin. Prices must be positive and in
+// the store currency, which is assumed when the currency is left out.
+func storePrice(price money.Money) (money.Money, error) {
+	price = price.OrCurrency(money.DefaultCurrency)
+
+	if price.Currency != money.DefaultCurrency {
+		return money.Money{}, errors.New("unsupported currency")
+	}
+
+	if !price.IsPositive() {
+		return money.Money{}, errors.New("price must be positive")
+	}
+
+	return price, nil
+}
+
+func newProductResponse(product *entities.Product) entities.ProductResponse {
+	var images []entities.ProductImageResponse
+	for _, image := range product.Images {
+		images = append(images, newProductImageResponse(&image))
+	}
+
+	var categories []entities.CategorySummary
+	for _, category := range product.Categories {
+		categories = append(categories, entities.CategorySummary{ID: category.ID, Name: category.Name, Slug: category.Slug})
+	}
+
+	var variants []entities.ProductVariantResponse
+	for _, variant := range product.Variants {
+		variants = append(variants, newProductVariantResponse(&variant))
+	}
+
+	now := time.Now()
+	response := entities.ProductResponse{
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		ImageURL:    product.ImageURL,
+		Type:        product.Type,
+		SoldOut:     product.ShowWhenSoldOut && product.Stock <= 0,
+		Drop:        newDropSummary(product, now),
+		SeriesID:    product.SeriesID,
+		Artist:      newArtistSummary(product.Artist),
+		Brand:       newBrandSummary(product.Brand),
+		Images:      images,
+		Categories:  categories,
+		Variants:    variants,
+	}
+
+	if onSale(product, now) {
+		listPrice := product.Price
+		response.Price = *product.SalePrice
+		response.CompareAtPrice = &listPrice
+		response.SaleEndsAt = product.SaleEndsAt
+	}
+
+	return response
+}
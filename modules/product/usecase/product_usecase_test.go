@@ -4,8 +4,10 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -18,7 +20,7 @@ func TestCreateNewProduct(t *testing.T) {
 
 		newProduct := &entities.Product{
 			Name: "Molly Classic", Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price: 340.99, Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
+			Price: money.New(34099, "THB"), Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
 		}
 
 		mockRepo.On("InsertProduct", mock.AnythingOfType("*entities.Product")).Return(newProduct, nil)
@@ -29,8 +31,9 @@ func TestCreateNewProduct(t *testing.T) {
 			ID:          uint(0),
 			Name:        "Molly Classic",
 			Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price:       340.99,
+			Price:       money.New(34099, "THB"),
 			ImageURL:    "https://example.com/images/molly-classic.jpg",
+			Type:        "standard",
 		}
 
 		assert.NoError(t, err)
@@ -40,13 +43,58 @@ func TestCreateNewProduct(t *testing.T) {
 		}
 	})
 
+	t.Run("create blind box given no series", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		_, err := productService.CreateNewProduct(&entities.Product{Name: "Labubu Exciting Macaron Box", Price: money.New(39000, "THB"), Type: "blind_box"})
+
+		assert.EqualError(t, err, "blind box requires a series")
+		mockRepo.AssertNotCalled(t, "InsertProduct", mock.Anything)
+	})
+
+	t.Run("create product given no currency", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		mockRepo.On("InsertProduct", mock.MatchedBy(func(p *entities.Product) bool {
+			return p.Price == money.New(34099, "THB")
+		})).Return(&entities.Product{Name: "Molly Classic", Price: money.New(34099, "THB")}, nil)
+
+		got, err := productService.CreateNewProduct(&entities.Product{Name: "Molly Classic", Price: money.New(34099, "")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(34099, "THB"), got.Price)
+	})
+
+	prices := []struct {
+		name    string
+		price   money.Money
+		wantErr string
+	}{
+		{"another currency", money.New(999, "USD"), "unsupported currency"},
+		{"no price", money.Money{}, "price must be positive"},
+	}
+
+	for _, tt := range prices {
+		t.Run("create product given "+tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			productService := ProductService{repo: mockRepo}
+
+			_, err := productService.CreateNewProduct(&entities.Product{Name: "Molly Classic", Price: tt.price})
+
+			assert.EqualError(t, err, tt.wantErr)
+			mockRepo.AssertNotCalled(t, "InsertProduct", mock.Anything)
+		})
+	}
+
 	t.Run("create new product error during query", func(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
 		newProduct := &entities.Product{
 			Name: "Skull Panda Rebel", Description: "A rebellious design from Skull Panda, combining gothic aesthetics with modern art.",
-			Price: 59.99, Stock: 15, ImageURL: "https://example.com/images/skull-panda-rebel.jpg", Active: true,
+			Price: money.New(5999, "THB"), Stock: 15, ImageURL: "https://example.com/images/skull-panda-rebel.jpg", Active: true,
 		}
 
 		mockRepo.On("InsertProduct", mock.AnythingOfType("*entities.Product")).Return((*entities.Product)(nil), errors.New("databse error"))
@@ -64,8 +112,8 @@ func TestGetAllProducts(t *testing.T) {
 		productService := ProductService{repo: mockRepo}
 
 		products := []entities.Product{
-			{Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, Stock: 25, ImageURL: "https://example.com/images/dimoo-starry-night.jpg", Active: true},
-			{Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true},
+			{Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: money.New(4999, "THB"), Stock: 25, ImageURL: "https://example.com/images/dimoo-starry-night.jpg", Active: true},
+			{Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: money.New(4499, "THB"), Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true},
 		}
 
 		mockRepo.On("GetAllProduct").Return(products, nil)
@@ -73,8 +121,8 @@ func TestGetAllProducts(t *testing.T) {
 		got, err := productService.GetAllProducts()
 
 		want := []entities.ProductResponse{
-			{ID: uint(0), Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, ImageURL: "https://example.com/images/dimoo-starry-night.jpg"},
-			{ID: uint(0), Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"},
+			{ID: uint(0), Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: money.New(4999, "THB"), ImageURL: "https://example.com/images/dimoo-starry-night.jpg"},
+			{ID: uint(0), Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: money.New(4499, "THB"), ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"},
 		}
 
 		assert.NoError(t, err)
@@ -105,14 +153,14 @@ func TestGetProductById(t *testing.T) {
 		productService := ProductService{repo: mockRepo}
 
 		product := &entities.Product{Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.",
-			Price: 44.99, Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true}
+			Price: money.New(4499, "THB"), Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true}
 
 		mockRepo.On("GetProductById", "12").Return(product, nil)
 
 		got, err := productService.GetProductById("12")
 
 		want := &entities.ProductResponse{Name: "Pucky Forest Fairy", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.",
-			Price: 44.99, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"}
+			Price: money.New(4499, "THB"), ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"}
 
 		assert.NoError(t, err)
 
@@ -122,6 +170,33 @@ func TestGetProductById(t *testing.T) {
 
 	})
 
+	t.Run("get product by id includes gallery images", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		product := &entities.Product{Model: gorm.Model{ID: 12}, Name: "Pucky Forest Fairy", Price: money.New(4499, "THB"), ImageURL: "https://example.com/front.jpg",
+			Images: []entities.ProductImage{
+				{Model: gorm.Model{ID: 1}, URL: "https://example.com/front.jpg", MediumURL: "https://example.com/front-m.jpg", ThumbnailURL: "https://example.com/front-t.jpg", AltText: "Front", IsPrimary: true},
+				{Model: gorm.Model{ID: 2}, URL: "https://example.com/box.jpg", MediumURL: "https://example.com/box-m.jpg", ThumbnailURL: "https://example.com/box-t.jpg", AltText: "Box art", SortOrder: 1},
+			}}
+
+		mockRepo.On("GetProductById", "12").Return(product, nil)
+
+		got, err := productService.GetProductById("12")
+
+		want := &entities.ProductResponse{ID: 12, Name: "Pucky Forest Fairy", Price: money.New(4499, "THB"), ImageURL: "https://example.com/front.jpg",
+			Images: []entities.ProductImageResponse{
+				{ID: 1, URL: "https://example.com/front.jpg", MediumURL: "https://example.com/front-m.jpg", ThumbnailURL: "https://example.com/front-t.jpg", AltText: "Front", IsPrimary: true},
+				{ID: 2, URL: "https://example.com/box.jpg", MediumURL: "https://example.com/box-m.jpg", ThumbnailURL: "https://example.com/box-t.jpg", AltText: "Box art", SortOrder: 1},
+			}}
+
+		assert.NoError(t, err)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v but want %v", got, want)
+		}
+	})
+
 	t.Run("get product by id given error", func(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
@@ -134,6 +209,56 @@ func TestGetProductById(t *testing.T) {
 		assert.EqualError(t, err, "database error")
 	})
 }
+
+func TestGetSellableItem(t *testing.T) {
+	t.Run("get sellable item prices the variant", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		mockRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}, Price: money.New(59000, "THB"), Active: true, Type: entities.ProductTypeStandard,
+			Variants: []entities.ProductVariant{{Model: gorm.Model{ID: 3}, Price: money.New(62000, "THB")}}}, nil)
+
+		got, err := productService.GetSellableItem(12, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.SellableItem{ProductID: 12, VariantID: 3, Type: entities.ProductTypeStandard, Price: money.New(62000, "THB")}, got)
+	})
+
+	t.Run("get sellable item given product off sale", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		mockRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}, Price: money.New(59000, "THB"), Active: false}, nil)
+
+		_, err := productService.GetSellableItem(12, 0)
+
+		assert.EqualError(t, err, "product is not on sale")
+	})
+
+	t.Run("get sellable item given drop not released yet", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		releaseAt := time.Now().Add(time.Hour)
+		mockRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}, Price: money.New(59000, "THB"), Active: true, ReleaseAt: &releaseAt}, nil)
+
+		_, err := productService.GetSellableItem(12, 0)
+
+		assert.EqualError(t, err, "product is not on sale")
+	})
+
+	t.Run("get sellable item given product not found", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		mockRepo.On("GetProductById", "13").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := productService.GetSellableItem(13, 0)
+
+		assert.EqualError(t, err, "product not found")
+	})
+}
+
 func TestUpdateProduct(t *testing.T) {
 	t.Run("update product successfully", func(t *testing.T) {
 		mockRepo := new(MockProductRepository)
@@ -141,7 +266,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		productUpdate := &entities.Product{
 			Name: "Molly Classic 2", Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price: 440.99, Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
+			Price: money.New(44099, "THB"), Stock: 30, ImageURL: "https://example.com/images/molly-classic.jpg", Active: true,
 		}
 
 		mockRepo.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "12").Return(productUpdate, nil)
@@ -152,7 +277,7 @@ func TestUpdateProduct(t *testing.T) {
 			ID:          uint(0),
 			Name:        "Molly Classic 2",
 			Description: "The iconic Molly figure, loved by art toy collectors worldwide.",
-			Price:       440.99,
+			Price:       money.New(44099, "THB"),
 			ImageURL:    "https://example.com/images/molly-classic.jpg",
 		}
 
@@ -169,7 +294,7 @@ func TestUpdateProduct(t *testing.T) {
 
 		newProduct := &entities.Product{
 			Name: "Skull Panda Rebel", Description: "A rebellious design from Skull Panda, combining gothic aesthetics with modern art.",
-			Price: 59.99, Stock: 15, ImageURL: "https://example.com/images/skull-panda-rebel.jpg", Active: true,
+			Price: money.New(5999, "THB"), Stock: 15, ImageURL: "https://example.com/images/skull-panda-rebel.jpg", Active: true,
 		}
 
 		mockRepo.On("UpdateProduct", mock.AnythingOfType("*entities.Product"), "1").Return((*entities.Product)(nil), errors.New("databse error"))
@@ -186,7 +311,7 @@ func TestDeductStockt(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		mockRepo.On("UpdateStock", "1", 2).Return(18, nil)
+		mockRepo.On("UpdateStock", "1", uint(0), 2, "").Return(18, nil)
 
 		got, err := productService.DeductStock("1", &entities.CountProduct{Count: 2})
 
@@ -205,7 +330,7 @@ func TestDeductStockt(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		mockRepo.On("UpdateStock", "12", 2).Return(0, errors.New("product not found"))
+		mockRepo.On("UpdateStock", "12", uint(0), 2, "").Return(0, errors.New("product not found"))
 
 		_, err := productService.DeductStock("12", &entities.CountProduct{Count: 2})
 
@@ -217,7 +342,7 @@ func TestDeductStockt(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		mockRepo.On("UpdateStock", "12", 2).Return(0, errors.New("insufficient stock"))
+		mockRepo.On("UpdateStock", "12", uint(0), 2, "").Return(0, errors.New("insufficient stock"))
 
 		_, err := productService.DeductStock("12", &entities.CountProduct{Count: 2})
 
@@ -229,7 +354,7 @@ func TestDeductStockt(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		mockRepo.On("UpdateStock", "12", 2).Return(0, errors.New("database error"))
+		mockRepo.On("UpdateStock", "12", uint(0), 2, "").Return(0, errors.New("database error"))
 
 		_, err := productService.DeductStock("12", &entities.CountProduct{Count: 2})
 
@@ -238,55 +363,85 @@ func TestDeductStockt(t *testing.T) {
 	})
 }
 
-func TestSearchProduct(t *testing.T) {
-	t.Run("search product successfull", func(t *testing.T) {
+func TestDeductVariantStock(t *testing.T) {
+	t.Run("reduce variant stock successfully", func(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		products := []entities.Product{
-			{Model: gorm.Model{ID: 1}, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, Stock: 25, ImageURL: "https://example.com/images/dimoo-starry-night.jpg", Active: true},
-			{Model: gorm.Model{ID: 2}, Name: "Pucky Forest Fairy Dimoo", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, Stock: 40, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg", Active: true},
-		}
+		mockRepo.On("UpdateStock", "1", uint(3), 2, "").Return(4, nil)
 
-		mockRepo.On("SearchProducts", "Dimoo").Return(products, nil)
+		got, err := productService.DeductStock("1", &entities.CountProduct{VariantID: 3, Count: 2})
 
-		got, err := productService.SearchProducts("Dimoo")
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.CountProduct{VariantID: 3, Count: 4}, got)
+	})
 
-		want := []entities.ProductResponse{
-			{ID: 1, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night,' featuring a dreamy and artistic design.", Price: 49.99, ImageURL: "https://example.com/images/dimoo-starry-night.jpg"},
-			{ID: 2, Name: "Pucky Forest Fairy Dimoo", Description: "A magical art toy figure from Pucky, with a whimsical forest fairy design.", Price: 44.99, ImageURL: "https://example.com/images/pucky-forest-fairy.jpg"},
-		}
+	t.Run("reduce stock given product with variants and no variant", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
 
-		assert.NoError(t, err)
+		mockRepo.On("UpdateStock", "1", uint(0), 2, "").Return(0, errors.New("variant required"))
 
-		if !reflect.DeepEqual(got, want) {
-			t.Errorf("got %v but want %v", got, want)
-		}
+		_, err := productService.DeductStock("1", &entities.CountProduct{Count: 2})
+
+		assert.EqualError(t, err, "variant required")
 	})
 
-	t.Run("search product given product not found", func(t *testing.T) {
+	t.Run("reduce stock given variant not found", func(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		mockRepo.On("SearchProducts", "Dimon").Return(([]entities.Product)(nil), errors.New("product not found"))
+		mockRepo.On("UpdateStock", "1", uint(9), 2, "").Return(0, errors.New("variant not found"))
 
-		_, err := productService.SearchProducts("Dimon")
+		_, err := productService.DeductStock("1", &entities.CountProduct{VariantID: 9, Count: 2})
 
-		assert.Error(t, err)
-		assert.EqualError(t, err, "product not found")
+		assert.EqualError(t, err, "variant not found")
+	})
+}
 
+func TestListProducts(t *testing.T) {
+	t.Run("list products by category", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		filter := &entities.ProductFilter{CategoryID: 2}
+		mockRepo.On("GetProductsByFilter", filter).Return([]entities.Product{
+			{Model: gorm.Model{ID: 1}, Name: "Labubu Sea Salt", Price: money.New(39000, "THB"), Categories: []entities.Category{{Model: gorm.Model{ID: 3}, Name: "The Monsters", Slug: "the-monsters"}}},
+		}, nil)
+
+		got, err := productService.ListProducts(filter)
+
+		want := []entities.ProductResponse{
+			{ID: 1, Name: "Labubu Sea Salt", Price: money.New(39000, "THB"), Categories: []entities.CategorySummary{{ID: 3, Name: "The Monsters", Slug: "the-monsters"}}},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
 	})
 
-	t.Run("search product given database error", func(t *testing.T) {
+	t.Run("list products given no match returns empty list", func(t *testing.T) {
 		mockRepo := new(MockProductRepository)
 		productService := ProductService{repo: mockRepo}
 
-		mockRepo.On("SearchProducts", "Dimon").Return(([]entities.Product)(nil), errors.New("database error"))
+		filter := &entities.ProductFilter{SeriesID: 8}
+		mockRepo.On("GetProductsByFilter", filter).Return([]entities.Product{}, nil)
 
-		_, err := productService.SearchProducts("Dimon")
+		got, err := productService.ListProducts(filter)
 
-		assert.Error(t, err)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.ProductResponse{}, got)
+	})
 
+	t.Run("list products given database error", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		productService := ProductService{repo: mockRepo}
+
+		filter := &entities.ProductFilter{SeriesID: 8}
+		mockRepo.On("GetProductsByFilter", filter).Return(([]entities.Product)(nil), errors.New("connection refused"))
+
+		_, err := productService.ListProducts(filter)
+
+		assert.EqualError(t, err, "database error")
 	})
 }
 
@@ -314,12 +469,12 @@ func (m *MockProductRepository) UpdateProduct(product *entities.Product, id stri
 	return args.Get(0).(*entities.Product), args.Error(1)
 }
 
-func (m *MockProductRepository) UpdateStock(id string, count int) (int, error) {
-	args := m.Called(id, count)
+func (m *MockProductRepository) UpdateStock(id string, variantID uint, count int, referenceID string) (int, error) {
+	args := m.Called(id, variantID, count, referenceID)
 	return args.Get(0).(int), args.Error(1)
 }
 
-func (m *MockProductRepository) SearchProducts(keyword string) ([]entities.Product, error) {
-	args := m.Called(keyword)
+func (m *MockProductRepository) GetProductsByFilter(filter *entities.ProductFilter) ([]entities.Product, error) {
+	args := m.Called(filter)
 	return args.Get(0).([]entities.Product), args.Error(1)
 }
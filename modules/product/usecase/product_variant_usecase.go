@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+type ProductVariantUsecase interface {
+	CreateVariant(productID string, variant *entities.ProductVariant) (*entities.ProductVariantResponse, error)
+	GetVariants(productID string) ([]entities.ProductVariantResponse, error)
+	UpdateVariant(productID, variantID string, variant *entities.ProductVariant) (*entities.ProductVariantResponse, error)
+	DeleteVariant(productID, variantID string) error
+}
+
+type ProductVariantService struct {
+	repo        ProductVariantRepository
+	productRepo ProductRepository
+}
+
+func NewProductVariantService(repo ProductVariantRepository, productRepo ProductRepository) ProductVariantUsecase {
+	return &ProductVariantService{repo, productRepo}
+}
+
+// CreateVariant adds a variant to a product. A product on sale cannot have
+// variants, as the sale price would not apply to them.
+func (s *ProductVariantService) CreateVariant(productID string, variant *entities.ProductVariant) (*entities.ProductVariantResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.SalePrice != nil {
+		return nil, errors.New("product is on sale")
+	}
+
+	if err := s.checkSKU(variant.SKU, 0); err != nil {
+		return nil, err
+	}
+
+	price, err := storePrice(variant.Price)
+	if err != nil {
+		return nil, err
+	}
+	variant.Price = price
+
+	variant.ProductID = product.ID
+	if variant.Edition == "" {
+		variant.Edition = entities.EditionRegular
+	}
+
+	newVariant, err := s.repo.InsertVariant(variant)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newProductVariantResponse(newVariant)
+	return &response, nil
+}
+
+func (s *ProductVariantService) GetVariants(productID string) ([]entities.ProductVariantResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.repo.GetVariants(product.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	variantList := []entities.ProductVariantResponse{}
+	for _, variant := range variants {
+		variantList = append(variantList, newProductVariantResponse(&variant))
+	}
+
+	return variantList, nil
+}
+
+func (s *ProductVariantService) UpdateVariant(productID, variantID string, variant *entities.ProductVariant) (*entities.ProductVariantResponse, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getVariant(product.ID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSKU(variant.SKU, existing.ID); err != nil {
+		return nil, err
+	}
+
+	price, err := storePrice(variant.Price)
+	if err != nil {
+		return nil, err
+	}
+	variant.Price = price
+
+	variant.ID = existing.ID
+	variant.ProductID = product.ID
+	variant.Stock = existing.Stock
+	if variant.Edition == "" {
+		variant.Edition = entities.EditionRegular
+	}
+
+	variantUpdated, err := s.repo.UpdateVariant(variant)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newProductVariantResponse(variantUpdated)
+	return &response, nil
+}
+
+func (s *ProductVariantService) DeleteVariant(productID, variantID string) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	variant, err := s.getVariant(product.ID, variantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteVariant(variant); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *ProductVariantService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+func (s *ProductVariantService) getVariant(productID uint, variantID string) (*entities.ProductVariant, error) {
+	variant, err := s.repo.GetVariantById(productID, variantID)
+	if err != nil {
+		if err.Error() == "variant not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return variant, nil
+}
+
+// checkSKU rejects a SKU already used by a variant other than ownerID.
+func (s *ProductVariantService) checkSKU(sku string, ownerID uint) error {
+	variant, err := s.repo.GetVariantBySKU(sku)
+	if err != nil {
+		if err.Error() == "variant not found" {
+			return nil
+		}
+		return errors.New("database error")
+	}
+
+	if variant.ID != ownerID {
+		return errors.New("sku already exists")
+	}
+
+	return nil
+}
+
+func newProductVariantResponse(variant *entities.ProductVariant) entities.ProductVariantResponse {
+	return entities.ProductVariantResponse{
+		ID:         variant.ID,
+		SKU:        variant.SKU,
+		Name:       variant.Name,
+		Edition:    variant.Edition,
+		Attributes: variant.Attributes,
+		Price:      variant.Price,
+		InStock:    variant.Stock > 0,
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateVariant(t *testing.T) {
+	t.Run("create variant successfully", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		variant := &entities.ProductVariant{SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Edition: entities.EditionGlow, Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(59000, "THB"), Stock: 4}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetVariantBySKU", "LBB-BIE-GLOW").Return((*entities.ProductVariant)(nil), errors.New("variant not found"))
+		mockRepo.On("InsertVariant", mock.MatchedBy(func(v *entities.ProductVariant) bool {
+			return v.ProductID == 1 && v.Edition == entities.EditionGlow
+		})).Return(&entities.ProductVariant{Model: gorm.Model{ID: 3}, ProductID: 1, SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Edition: entities.EditionGlow, Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(59000, "THB"), Stock: 4}, nil)
+
+		got, err := variantService.CreateVariant("1", variant)
+
+		want := &entities.ProductVariantResponse{ID: 3, SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Edition: entities.EditionGlow, Attributes: entities.VariantAttributes{"finish": "glow"}, Price: money.New(59000, "THB"), InStock: true}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("create variant given duplicate sku", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetVariantBySKU", "LBB-BIE-GLOW").Return(&entities.ProductVariant{Model: gorm.Model{ID: 8}, ProductID: 2}, nil)
+
+		_, err := variantService.CreateVariant("1", &entities.ProductVariant{SKU: "LBB-BIE-GLOW", Name: "Glow", Price: money.New(59000, "THB")})
+
+		assert.EqualError(t, err, "sku already exists")
+		mockRepo.AssertNotCalled(t, "InsertVariant", mock.Anything)
+	})
+
+	t.Run("create variant given product on sale", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		salePrice := money.New(7500, "THB")
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(10000, "THB"), SalePrice: &salePrice}, nil)
+
+		_, err := variantService.CreateVariant("1", &entities.ProductVariant{SKU: "LBB-BIE-GLOW", Name: "Glow", Price: money.New(59000, "THB")})
+
+		assert.EqualError(t, err, "product is on sale")
+		mockRepo.AssertNotCalled(t, "InsertVariant", mock.Anything)
+	})
+
+	t.Run("create variant given product not found", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := variantService.CreateVariant("99", &entities.ProductVariant{SKU: "X-1", Name: "Regular", Price: money.New(39000, "THB")})
+
+		assert.EqualError(t, err, "product not found")
+	})
+}
+
+func TestUpdateVariant(t *testing.T) {
+	t.Run("update variant keeping its own sku and stock, defaulting the edition", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		variant := &entities.ProductVariant{SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Price: money.New(62000, "THB"), Stock: 0}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetVariantById", uint(1), "3").Return(&entities.ProductVariant{Model: gorm.Model{ID: 3}, ProductID: 1, SKU: "LBB-BIE-GLOW", Stock: 4}, nil)
+		mockRepo.On("GetVariantBySKU", "LBB-BIE-GLOW").Return(&entities.ProductVariant{Model: gorm.Model{ID: 3}, ProductID: 1}, nil)
+		mockRepo.On("UpdateVariant", variant).Return(variant, nil)
+
+		got, err := variantService.UpdateVariant("1", "3", variant)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.ProductVariantResponse{ID: 3, SKU: "LBB-BIE-GLOW", Name: "Glow in the dark", Edition: entities.EditionRegular, Price: money.New(62000, "THB"), InStock: true}, got)
+	})
+
+	t.Run("update variant given variant not found", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetVariantById", uint(1), "30").Return((*entities.ProductVariant)(nil), errors.New("variant not found"))
+
+		_, err := variantService.UpdateVariant("1", "30", &entities.ProductVariant{SKU: "X-1"})
+
+		assert.EqualError(t, err, "variant not found")
+	})
+}
+
+func TestDeleteVariant(t *testing.T) {
+	t.Run("delete variant successfully", func(t *testing.T) {
+		mockRepo := new(MockProductVariantRepository)
+		mockProductRepo := new(MockProductRepository)
+		variantService := ProductVariantService{repo: mockRepo, productRepo: mockProductRepo}
+
+		variant := &entities.ProductVariant{Model: gorm.Model{ID: 3}, ProductID: 1}
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetVariantById", uint(1), "3").Return(variant, nil)
+		mockRepo.On("DeleteVariant", variant).Return(nil)
+
+		err := variantService.DeleteVariant("1", "3")
+
+		assert.NoError(t, err)
+	})
+}
+
+type MockProductVariantRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductVariantRepository) InsertVariant(variant *entities.ProductVariant) (*entities.ProductVariant, error) {
+	args := m.Called(variant)
+	return args.Get(0).(*entities.ProductVariant), args.Error(1)
+}
+
+func (m *MockProductVariantRepository) GetVariants(productID uint) ([]entities.ProductVariant, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.ProductVariant), args.Error(1)
+}
+
+func (m *MockProductVariantRepository) GetVariantById(productID uint, variantID string) (*entities.ProductVariant, error) {
+	args := m.Called(productID, variantID)
+	return args.Get(0).(*entities.ProductVariant), args.Error(1)
+}
+
+func (m *MockProductVariantRepository) GetVariantBySKU(sku string) (*entities.ProductVariant, error) {
+	args := m.Called(sku)
+	return args.Get(0).(*entities.ProductVariant), args.Error(1)
+}
+
+func (m *MockProductVariantRepository) UpdateVariant(variant *entities.ProductVariant) (*entities.ProductVariant, error) {
+	args := m.Called(variant)
+	return args.Get(0).(*entities.ProductVariant), args.Error(1)
+}
+
+func (m *MockProductVariantRepository) DeleteVariant(variant *entities.ProductVariant) error {
+	args := m.Called(variant)
+	return args.Error(0)
+}
@@ -0,0 +1,770 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"gorm.io/gorm"
+)
+
+type PromotionUsecase interface {
+	CreateCoupon(request *entities.CouponRequest) (*entities.Coupon, error)
+	GetCoupons() ([]entities.Coupon, error)
+	UpdateCoupon(id string, request *entities.CouponRequest) (*entities.Coupon, error)
+	DeleteCoupon(id string) error
+	CreateCartPromotion(request *entities.CartPromotionRequest) (*entities.CartPromotion, error)
+	GetCartPromotions() ([]entities.CartPromotion, error)
+	DeleteCartPromotion(id string) error
+	PriceCart(request *entities.CartPricingRequest) (*entities.CartPricing, error)
+	RedeemCoupons(request *entities.CouponRedeemRequest) (*entities.CartPricing, error)
+	ReleaseCoupons(referenceID string) error
+}
+
+type PromotionService struct {
+	repo        PromotionRepository
+	productRepo ProductRepository
+}
+
+func NewPromotionService(repo PromotionRepository, productRepo ProductRepository) PromotionUsecase {
+	return &PromotionService{repo, productRepo}
+}
+
+func (s *PromotionService) CreateCoupon(request *entities.CouponRequest) (*entities.Coupon, error) {
+	coupon, err := newCoupon(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(coupon.Code, 0); err != nil {
+		return nil, err
+	}
+
+	newCoupon, err := s.repo.InsertCoupon(coupon)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return newCoupon, nil
+}
+
+func (s *PromotionService) GetCoupons() ([]entities.Coupon, error) {
+	coupons, err := s.repo.GetCoupons()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return coupons, nil
+}
+
+// UpdateCoupon replaces a coupon's terms. Its redemptions so far still count
+// towards the new limits.
+func (s *PromotionService) UpdateCoupon(id string, request *entities.CouponRequest) (*entities.Coupon, error) {
+	current, err := s.repo.GetCouponById(id)
+	if err != nil {
+		if err.Error() == "coupon not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	coupon, err := newCoupon(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(coupon.Code, current.ID); err != nil {
+		return nil, err
+	}
+
+	coupon.Model = current.Model
+	coupon.Redemptions = current.Redemptions
+
+	updated, err := s.repo.UpdateCoupon(coupon)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return updated, nil
+}
+
+func (s *PromotionService) DeleteCoupon(id string) error {
+	if err := s.repo.DeleteCoupon(id); err != nil {
+		if err.Error() == "coupon not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *PromotionService) CreateCartPromotion(request *entities.CartPromotionRequest) (*entities.CartPromotion, error) {
+	promotion, err := newCartPromotion(request)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, productID := range promotion.BundleProductIDs {
+		if _, err := s.getProduct(strconv.FormatUint(uint64(productID), 10)); err != nil {
+			return nil, err
+		}
+	}
+
+	newPromotion, err := s.repo.InsertCartPromotion(promotion)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return newPromotion, nil
+}
+
+func (s *PromotionService) GetCartPromotions() ([]entities.CartPromotion, error) {
+	promotions, err := s.repo.GetCartPromotions(false)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return promotions, nil
+}
+
+func (s *PromotionService) DeleteCartPromotion(id string) error {
+	if err := s.repo.DeleteCartPromotion(id); err != nil {
+		if err.Error() == "promotion not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// PriceCart prices a cart with the promotions it qualifies for and the
+// coupons the customer entered, for the cart and checkout pages. Nothing is
+// used up.
+func (s *PromotionService) PriceCart(request *entities.CartPricingRequest) (*entities.CartPricing, error) {
+	return s.priceCart(request, time.Now(), true)
+}
+
+// RedeemCoupons prices the order being placed again and takes one use of each
+// of its coupons for it. Usage limits are checked as the uses are taken, so
+// two checkouts can't both take a coupon's last use, and a retried checkout
+// does not use a coupon twice.
+func (s *PromotionService) RedeemCoupons(request *entities.CouponRedeemRequest) (*entities.CartPricing, error) {
+	pricing, err := s.priceCart(&request.CartPricingRequest, time.Now(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pricing.Discounts) == 0 {
+		return pricing, nil
+	}
+
+	redemptions := make([]entities.CouponRedemption, len(pricing.Discounts))
+	for i, applied := range pricing.Discounts {
+		redemptions[i] = entities.CouponRedemption{
+			CouponID:    applied.CouponID,
+			ReferenceID: request.ReferenceID,
+			UserID:      optionalID(request.UserID),
+			Discount:    applied.Amount,
+		}
+	}
+
+	if err := s.repo.RedeemCoupons(redemptions); err != nil {
+		switch err.Error() {
+		case "coupon not found", "coupon usage limit reached", "sign in required":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return pricing, nil
+}
+
+// ReleaseCoupons gives back the coupon uses taken for an order that was not
+// placed after all.
+func (s *PromotionService) ReleaseCoupons(referenceID string) error {
+	if err := s.repo.ReleaseCoupons(referenceID); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// priceCart prices the cart's lines and applies the running promotions, then
+// its coupons. checkUsage checks the coupons' usage limits too.
+func (s *PromotionService) priceCart(request *entities.CartPricingRequest, now time.Time, checkUsage bool) (*entities.CartPricing, error) {
+	shipping := request.Shipping.OrCurrency(money.DefaultCurrency)
+	if shipping.Currency != money.DefaultCurrency {
+		return nil, errors.New("unsupported currency")
+	}
+
+	lines := make([]entities.PricedCartLine, len(request.Items))
+	products := make([]*entities.Product, len(request.Items))
+	for i, item := range request.Items {
+		product, err := s.getProduct(strconv.FormatUint(uint64(item.ProductID), 10))
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := unitPrice(product, item.VariantID, now)
+		if err != nil {
+			return nil, err
+		}
+
+		products[i] = product
+		lines[i] = entities.PricedCartLine{
+			ProductID: product.ID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			UnitPrice: price,
+		}
+	}
+
+	promotions, err := s.repo.GetCartPromotions(true)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	applied := applyPromotions(lines, products, runningPromotions(promotions, now))
+
+	coupons, err := s.getCoupons(request.Codes, request.UserID, now, checkUsage)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := applyCoupons(lines, products, shipping, coupons)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing.Promotions = applied
+	return pricing, nil
+}
+
+// getCoupons looks up the coupons behind codes and checks each can be used
+// now, by the user when checkUsage is set, and that they can be used
+// together. A code entered twice counts once.
+func (s *PromotionService) getCoupons(codes []string, userID uint, now time.Time, checkUsage bool) ([]entities.Coupon, error) {
+	var coupons []entities.Coupon
+	seen := map[string]bool{}
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		coupon, err := s.repo.GetCouponByCode(code)
+		if err != nil {
+			if err.Error() == "coupon not found" {
+				return nil, err
+			}
+			return nil, errors.New("database error")
+		}
+
+		if err := checkActive(coupon, now); err != nil {
+			return nil, err
+		}
+
+		if checkUsage {
+			if err := s.checkUsage(coupon, userID); err != nil {
+				return nil, err
+			}
+		}
+
+		coupons = append(coupons, *coupon)
+	}
+
+	if len(coupons) > 1 {
+		for _, coupon := range coupons {
+			if !coupon.Stackable {
+				return nil, errors.New("coupons cannot be combined")
+			}
+		}
+	}
+
+	return coupons, nil
+}
+
+func checkActive(coupon *entities.Coupon, now time.Time) error {
+	if !coupon.Active || (coupon.StartsAt != nil && now.Before(*coupon.StartsAt)) {
+		return errors.New("coupon not active")
+	}
+
+	if coupon.EndsAt != nil && !now.Before(*coupon.EndsAt) {
+		return errors.New("coupon expired")
+	}
+
+	return nil
+}
+
+func (s *PromotionService) checkUsage(coupon *entities.Coupon, userID uint) error {
+	if coupon.UsageLimit > 0 && coupon.Redemptions >= coupon.UsageLimit {
+		return errors.New("coupon usage limit reached")
+	}
+
+	if coupon.PerUserLimit > 0 {
+		if userID == 0 {
+			return errors.New("sign in required")
+		}
+
+		used, err := s.repo.CountRedemptions(coupon.ID, userID)
+		if err != nil {
+			return errors.New("database error")
+		}
+
+		if used >= int64(coupon.PerUserLimit) {
+			return errors.New("coupon usage limit reached")
+		}
+	}
+
+	return nil
+}
+
+// checkCode makes sure no coupon other than the one with id uses code.
+func (s *PromotionService) checkCode(code string, id uint) error {
+	existing, err := s.repo.GetCouponByCode(code)
+	if err != nil {
+		if err.Error() == "coupon not found" {
+			return nil
+		}
+		return errors.New("database error")
+	}
+
+	if existing.ID != id {
+		return errors.New("coupon code already exists")
+	}
+
+	return nil
+}
+
+func (s *PromotionService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+// applyCoupons works out what each coupon takes off the cart, after the
+// promotions' Savings on its lines. Percentage coupons go first, then fixed
+// amounts off what is left, then free shipping. A coupon never takes off more
+// than its eligible lines still cost.
+func applyCoupons(lines []entities.PricedCartLine, products []*entities.Product, shipping money.Money, coupons []entities.Coupon) (*entities.CartPricing, error) {
+	zero := money.New(0, money.DefaultCurrency)
+
+	pricing := &entities.CartPricing{
+		Items:      lines,
+		Subtotal:   zero,
+		Shipping:   shipping,
+		Promotions: []entities.AppliedPromotion{},
+		Savings:    zero,
+		Discounts:  []entities.AppliedCoupon{},
+		Discount:   zero,
+	}
+
+	for i := range lines {
+		full := lines[i].UnitPrice.Mul(lines[i].Quantity)
+		lines[i].Savings = money.New(lines[i].Savings.Amount, money.DefaultCurrency)
+		lines[i].Total = money.New(full.Amount-lines[i].Savings.Amount, money.DefaultCurrency)
+		lines[i].Discount = zero
+		pricing.Subtotal.Amount += full.Amount
+		pricing.Savings.Amount += lines[i].Savings.Amount
+	}
+
+	sort.SliceStable(coupons, func(i, j int) bool {
+		return couponRank(coupons[i].Type) < couponRank(coupons[j].Type)
+	})
+
+	shippingLeft := shipping
+	for _, coupon := range coupons {
+		var eligible []int
+		var spend, left int64
+		for i := range lines {
+			if covers(products[i], coupon.ProductIDs, coupon.CategoryIDs, coupon.ArtistIDs) {
+				eligible = append(eligible, i)
+				spend += lines[i].UnitPrice.Mul(lines[i].Quantity).Amount - lines[i].Savings.Amount
+				left += lines[i].Total.Amount
+			}
+		}
+
+		if len(eligible) == 0 {
+			return nil, errors.New("coupon not applicable")
+		}
+
+		if coupon.MinSpend != nil && spend < coupon.MinSpend.Amount {
+			return nil, errors.New("minimum spend not met")
+		}
+
+		applied := entities.AppliedCoupon{CouponID: coupon.ID, Code: coupon.Code, Type: coupon.Type, Amount: zero}
+		switch coupon.Type {
+		case entities.CouponPercentage:
+			applied.Amount = money.New(left, money.DefaultCurrency).Scale(int64(coupon.PercentOff), 100)
+			shareDiscount(lines, eligible, applied.Amount)
+		case entities.CouponFixed:
+			applied.Amount = money.New(min(coupon.AmountOff.Amount, left), money.DefaultCurrency)
+			shareDiscount(lines, eligible, applied.Amount)
+		case entities.CouponFreeShipping:
+			applied.Amount = shippingLeft
+			shippingLeft = zero
+		}
+
+		pricing.Discounts = append(pricing.Discounts, applied)
+		pricing.Discount.Amount += applied.Amount.Amount
+	}
+
+	pricing.Total = money.New(pricing.Subtotal.Amount+shipping.Amount-pricing.Savings.Amount-pricing.Discount.Amount, money.DefaultCurrency)
+	return pricing, nil
+}
+
+// shareDiscount takes discount off the eligible lines in proportion to what
+// they still cost.
+func shareDiscount(lines []entities.PricedCartLine, eligible []int, discount money.Money) {
+	weights := make([]int64, len(eligible))
+	for j, i := range eligible {
+		weights[j] = lines[i].Total.Amount
+	}
+
+	for j, part := range discount.Allocate(weights...) {
+		lines[eligible[j]].Discount.Amount += part.Amount
+		lines[eligible[j]].Total.Amount -= part.Amount
+	}
+}
+
+func couponRank(couponType string) int {
+	switch couponType {
+	case entities.CouponPercentage:
+		return 0
+	case entities.CouponFixed:
+		return 1
+	}
+
+	return 2
+}
+
+// covers reports whether a product is eligible for a coupon or promotion
+// limited to productIDs, categoryIDs and artistIDs. One that names none of
+// them covers every product.
+func covers(product *entities.Product, productIDs, categoryIDs, artistIDs []uint) bool {
+	if len(productIDs) == 0 && len(categoryIDs) == 0 && len(artistIDs) == 0 {
+		return true
+	}
+
+	if slices.Contains(productIDs, product.ID) {
+		return true
+	}
+
+	if product.ArtistID != nil && slices.Contains(artistIDs, *product.ArtistID) {
+		return true
+	}
+
+	for _, category := range product.Categories {
+		if slices.Contains(categoryIDs, category.ID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runningPromotions returns the promotions whose window is open at now, in
+// the order they are applied.
+func runningPromotions(promotions []entities.CartPromotion, now time.Time) []entities.CartPromotion {
+	var running []entities.CartPromotion
+	for _, promotion := range promotions {
+		if promotion.StartsAt != nil && now.Before(*promotion.StartsAt) {
+			continue
+		}
+		if promotion.EndsAt != nil && !now.Before(*promotion.EndsAt) {
+			continue
+		}
+		running = append(running, promotion)
+	}
+
+	sort.SliceStable(running, func(i, j int) bool {
+		if running[i].Priority != running[j].Priority {
+			return running[i].Priority < running[j].Priority
+		}
+		return running[i].ID < running[j].ID
+	})
+
+	return running
+}
+
+// applyPromotions applies promotions to the cart in order and records what
+// each took off each line in its Savings. used counts the units of each line
+// a promotion has already claimed.
+func applyPromotions(lines []entities.PricedCartLine, products []*entities.Product, promotions []entities.CartPromotion) []entities.AppliedPromotion {
+	applied := []entities.AppliedPromotion{}
+	used := make([]int, len(lines))
+
+	for i := range lines {
+		lines[i].Savings = money.New(0, money.DefaultCurrency)
+		lines[i].Promotions = nil
+	}
+
+	for _, promotion := range promotions {
+		var savings []int64
+		switch promotion.Type {
+		case entities.PromotionBuyXGetY:
+			savings = buyXGetY(lines, products, used, &promotion)
+		case entities.PromotionBundle:
+			savings = bundle(lines, products, used, &promotion)
+		}
+
+		var total int64
+		for i, amount := range savings {
+			if amount == 0 {
+				continue
+			}
+
+			lines[i].Savings.Amount += amount
+			lines[i].Promotions = append(lines[i].Promotions, entities.LinePromotion{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Amount:      money.New(amount, money.DefaultCurrency),
+			})
+			total += amount
+		}
+
+		if total > 0 {
+			applied = append(applied, entities.AppliedPromotion{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Type:        promotion.Type,
+				Amount:      money.New(total, money.DefaultCurrency),
+			})
+		}
+	}
+
+	return applied
+}
+
+// buyXGetY lines the eligible units up from dearest to cheapest and cuts
+// them into groups of BuyQuantity plus FreeQuantity; the last FreeQuantity
+// units of each group are free. Units left over from the last group stay
+// unclaimed for later promotions. It returns the savings per line.
+func buyXGetY(lines []entities.PricedCartLine, products []*entities.Product, used []int, promotion *entities.CartPromotion) []int64 {
+	savings := make([]int64, len(lines))
+
+	var units []int
+	for i := range lines {
+		if promotion.ProductType != "" && products[i].Type != promotion.ProductType {
+			continue
+		}
+		if !covers(products[i], promotion.ProductIDs, promotion.CategoryIDs, promotion.ArtistIDs) {
+			continue
+		}
+		for n := used[i]; n < lines[i].Quantity; n++ {
+			units = append(units, i)
+		}
+	}
+
+	sort.SliceStable(units, func(a, b int) bool {
+		return lines[units[a]].UnitPrice.Amount > lines[units[b]].UnitPrice.Amount
+	})
+
+	group := promotion.BuyQuantity + promotion.FreeQuantity
+	for start := 0; start+group <= len(units); start += group {
+		for k, i := range units[start : start+group] {
+			used[i]++
+			if k >= promotion.BuyQuantity {
+				savings[i] += lines[i].UnitPrice.Amount
+			}
+		}
+	}
+
+	return savings
+}
+
+// bundle sells as many full sets of the bundle's products as the cart holds
+// at the bundle price, taking the dearest unit of each product first. The
+// saving on a set is shared between its units by price. It returns the
+// savings per line.
+func bundle(lines []entities.PricedCartLine, products []*entities.Product, used []int, promotion *entities.CartPromotion) []int64 {
+	savings := make([]int64, len(lines))
+
+	for {
+		set := make([]int, len(promotion.BundleProductIDs))
+		weights := make([]int64, len(promotion.BundleProductIDs))
+		var price int64
+		for j, productID := range promotion.BundleProductIDs {
+			set[j] = -1
+			for i := range lines {
+				if products[i].ID != productID || used[i] >= lines[i].Quantity {
+					continue
+				}
+				if set[j] < 0 || lines[i].UnitPrice.Amount > lines[set[j]].UnitPrice.Amount {
+					set[j] = i
+				}
+			}
+
+			if set[j] < 0 {
+				return savings
+			}
+			weights[j] = lines[set[j]].UnitPrice.Amount
+			price += weights[j]
+		}
+
+		saving := price - promotion.BundlePrice.Amount
+		if saving <= 0 {
+			return savings
+		}
+
+		for j, part := range money.New(saving, money.DefaultCurrency).Allocate(weights...) {
+			used[set[j]]++
+			savings[set[j]] += part.Amount
+		}
+	}
+}
+
+// unitPrice is what one unit of a product, or of one of its variants, sells
+// for right now.
+func unitPrice(product *entities.Product, variantID uint, now time.Time) (money.Money, error) {
+	if drop := newDropSummary(product, now); !product.Active || product.OffSale || product.PreOrder || (drop != nil && drop.Status != DropLive) {
+		return money.Money{}, errors.New("product is not on sale")
+	}
+
+	return listPrice(product, variantID, now)
+}
+
+// listPrice is what one unit of a product, or of one of its variants, is
+// priced at right now, whether or not the product is on sale.
+func listPrice(product *entities.Product, variantID uint, now time.Time) (money.Money, error) {
+	price := effectivePrice(product, now)
+	if len(product.Variants) > 0 {
+		if variantID == 0 {
+			return money.Money{}, errors.New("variant required")
+		}
+
+		index := slices.IndexFunc(product.Variants, func(variant entities.ProductVariant) bool {
+			return variant.ID == variantID
+		})
+		if index < 0 {
+			return money.Money{}, errors.New("variant not found")
+		}
+		price = product.Variants[index].Price
+	} else if variantID != 0 {
+		return money.Money{}, errors.New("variant not found")
+	}
+
+	if price.Currency != money.DefaultCurrency {
+		return money.Money{}, errors.New("unsupported currency")
+	}
+
+	return price, nil
+}
+
+// newCoupon checks a coupon request and turns it into a coupon. Codes are
+// kept in upper case so customers can enter them in any case.
+func newCoupon(request *entities.CouponRequest) (*entities.Coupon, error) {
+	code := strings.ToUpper(strings.TrimSpace(request.Code))
+	if len(code) < 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_") != "" {
+		return nil, errors.New("invalid coupon code")
+	}
+
+	coupon := &entities.Coupon{
+		Code:         code,
+		Description:  request.Description,
+		Type:         request.Type,
+		ProductIDs:   request.ProductIDs,
+		CategoryIDs:  request.CategoryIDs,
+		ArtistIDs:    request.ArtistIDs,
+		UsageLimit:   request.UsageLimit,
+		PerUserLimit: request.PerUserLimit,
+		StartsAt:     request.StartsAt,
+		EndsAt:       request.EndsAt,
+		Stackable:    request.Stackable,
+		Active:       request.Active == nil || *request.Active,
+	}
+
+	switch request.Type {
+	case entities.CouponPercentage:
+		if request.PercentOff <= 0 {
+			return nil, errors.New("invalid discount")
+		}
+		coupon.PercentOff = request.PercentOff
+	case entities.CouponFixed:
+		if request.AmountOff == nil {
+			return nil, errors.New("invalid discount")
+		}
+
+		amountOff := request.AmountOff.OrCurrency(money.DefaultCurrency)
+		if amountOff.Currency != money.DefaultCurrency {
+			return nil, errors.New("unsupported currency")
+		}
+		if !amountOff.IsPositive() {
+			return nil, errors.New("invalid discount")
+		}
+		coupon.AmountOff = &amountOff
+	}
+
+	if request.MinSpend != nil && !request.MinSpend.IsZero() {
+		minSpend := request.MinSpend.OrCurrency(money.DefaultCurrency)
+		if minSpend.Currency != money.DefaultCurrency {
+			return nil, errors.New("unsupported currency")
+		}
+		coupon.MinSpend = &minSpend
+	}
+
+	if request.StartsAt != nil && request.EndsAt != nil && !request.EndsAt.After(*request.StartsAt) {
+		return nil, errors.New("coupon must end after it starts")
+	}
+
+	return coupon, nil
+}
+
+// newCartPromotion checks a cart promotion request and turns it into a
+// promotion. Fields the promotion's type does not use are dropped.
+func newCartPromotion(request *entities.CartPromotionRequest) (*entities.CartPromotion, error) {
+	promotion := &entities.CartPromotion{
+		Name:     request.Name,
+		Type:     request.Type,
+		Priority: request.Priority,
+		StartsAt: request.StartsAt,
+		EndsAt:   request.EndsAt,
+		Active:   request.Active == nil || *request.Active,
+	}
+
+	switch request.Type {
+	case entities.PromotionBuyXGetY:
+		if request.BuyQuantity <= 0 || request.FreeQuantity <= 0 {
+			return nil, errors.New("invalid promotion")
+		}
+		promotion.BuyQuantity = request.BuyQuantity
+		promotion.FreeQuantity = request.FreeQuantity
+		promotion.ProductType = request.ProductType
+		promotion.ProductIDs = request.ProductIDs
+		promotion.CategoryIDs = request.CategoryIDs
+		promotion.ArtistIDs = request.ArtistIDs
+	case entities.PromotionBundle:
+		ids := slices.Clone(request.BundleProductIDs)
+		slices.Sort(ids)
+		if len(ids) < 2 || len(slices.Compact(ids)) != len(request.BundleProductIDs) || request.BundlePrice == nil {
+			return nil, errors.New("invalid promotion")
+		}
+
+		bundlePrice, err := storePrice(*request.BundlePrice)
+		if err != nil {
+			return nil, err
+		}
+		promotion.BundleProductIDs = request.BundleProductIDs
+		promotion.BundlePrice = &bundlePrice
+	}
+
+	if request.StartsAt != nil && request.EndsAt != nil && !request.EndsAt.After(*request.StartsAt) {
+		return nil, errors.New("promotion must end after it starts")
+	}
+
+	return promotion, nil
+}
@@ -0,0 +1,527 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateCoupon(t *testing.T) {
+	t.Run("create coupon successfully", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		promotionService := PromotionService{repo: mockRepo}
+
+		mockRepo.On("GetCouponByCode", "SUMMER-10").Return((*entities.Coupon)(nil), errors.New("coupon not found"))
+		mockRepo.On("InsertCoupon", mock.MatchedBy(func(c *entities.Coupon) bool {
+			return c.Code == "SUMMER-10" && c.PercentOff == 10 && c.AmountOff == nil && c.MinSpend.Amount == 100000 && c.Active
+		})).Return(&entities.Coupon{Model: gorm.Model{ID: 1}, Code: "SUMMER-10"}, nil)
+
+		minSpend := money.New(100000, "")
+		got, err := promotionService.CreateCoupon(&entities.CouponRequest{Code: " summer-10 ", Type: "percentage", PercentOff: 10, MinSpend: &minSpend})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), got.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("create coupon given code in use", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		promotionService := PromotionService{repo: mockRepo}
+
+		mockRepo.On("GetCouponByCode", "SUMMER-10").Return(&entities.Coupon{Model: gorm.Model{ID: 3}}, nil)
+
+		_, err := promotionService.CreateCoupon(&entities.CouponRequest{Code: "SUMMER-10", Type: "percentage", PercentOff: 10})
+
+		assert.EqualError(t, err, "coupon code already exists")
+		mockRepo.AssertNotCalled(t, "InsertCoupon", mock.Anything)
+	})
+
+	startsAt := time.Date(2026, 12, 2, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		request entities.CouponRequest
+		want    string
+	}{
+		{"invalid code", entities.CouponRequest{Code: "10% OFF", Type: "percentage", PercentOff: 10}, "invalid coupon code"},
+		{"percentage without percent off", entities.CouponRequest{Code: "SALE", Type: "percentage"}, "invalid discount"},
+		{"fixed without amount off", entities.CouponRequest{Code: "SALE", Type: "fixed"}, "invalid discount"},
+		{"fixed in another currency", entities.CouponRequest{Code: "SALE", Type: "fixed", AmountOff: &money.Money{Amount: 500, Currency: "USD"}}, "unsupported currency"},
+		{"ends before it starts", entities.CouponRequest{Code: "SALE", Type: "free_shipping", StartsAt: &startsAt, EndsAt: &endsAt}, "coupon must end after it starts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPromotionRepository)
+			promotionService := PromotionService{repo: mockRepo}
+
+			_, err := promotionService.CreateCoupon(&tt.request)
+
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestApplyCoupons(t *testing.T) {
+	artistID := uint(7)
+	products := []*entities.Product{
+		{Model: gorm.Model{ID: 1}, ArtistID: &artistID, Categories: []entities.Category{{Model: gorm.Model{ID: 4}}}},
+		{Model: gorm.Model{ID: 2}},
+	}
+	newLines := func() []entities.PricedCartLine {
+		return []entities.PricedCartLine{
+			{ProductID: 1, Quantity: 2, UnitPrice: money.New(50000, "THB")},
+			{ProductID: 2, Quantity: 1, UnitPrice: money.New(30000, "THB")},
+		}
+	}
+	shipping := money.New(5000, "THB")
+
+	t.Run("percentage off the whole cart", func(t *testing.T) {
+		got, err := applyCoupons(newLines(), products, shipping, []entities.Coupon{{Code: "TEN", Type: "percentage", PercentOff: 10}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(130000, "THB"), got.Subtotal)
+		assert.Equal(t, money.New(13000, "THB"), got.Discount)
+		assert.Equal(t, money.New(122000, "THB"), got.Total)
+		assert.Equal(t, money.New(10000, "THB"), got.Items[0].Discount)
+		assert.Equal(t, money.New(3000, "THB"), got.Items[1].Discount)
+	})
+
+	t.Run("fixed amount off eligible artist", func(t *testing.T) {
+		got, err := applyCoupons(newLines(), products, shipping, []entities.Coupon{
+			{Code: "ARTIST", Type: "fixed", AmountOff: &money.Money{Amount: 200000, Currency: "THB"}, ArtistIDs: []uint{7}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(100000, "THB"), got.Discount)
+		assert.Equal(t, money.New(0, "THB"), got.Items[0].Total)
+		assert.Equal(t, money.New(30000, "THB"), got.Items[1].Total)
+	})
+
+	t.Run("stacked coupons apply percentage first", func(t *testing.T) {
+		got, err := applyCoupons(newLines(), products, shipping, []entities.Coupon{
+			{Code: "SHIP", Type: "free_shipping", Stackable: true},
+			{Code: "FIFTY", Type: "fixed", AmountOff: &money.Money{Amount: 5000, Currency: "THB"}, Stackable: true},
+			{Code: "TEN", Type: "percentage", PercentOff: 10, CategoryIDs: []uint{4}, Stackable: true},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"TEN", "FIFTY", "SHIP"}, []string{got.Discounts[0].Code, got.Discounts[1].Code, got.Discounts[2].Code})
+		assert.Equal(t, money.New(10000, "THB"), got.Discounts[0].Amount)
+		assert.Equal(t, money.New(5000, "THB"), got.Discounts[2].Amount)
+		assert.Equal(t, money.New(20000, "THB"), got.Discount)
+		assert.Equal(t, money.New(115000, "THB"), got.Total)
+	})
+
+	t.Run("minimum spend counts eligible items only", func(t *testing.T) {
+		_, err := applyCoupons(newLines(), products, shipping, []entities.Coupon{
+			{Code: "BIG", Type: "percentage", PercentOff: 10, ProductIDs: []uint{2}, MinSpend: &money.Money{Amount: 50000, Currency: "THB"}},
+		})
+
+		assert.EqualError(t, err, "minimum spend not met")
+	})
+
+	t.Run("no eligible items", func(t *testing.T) {
+		_, err := applyCoupons(newLines(), products, shipping, []entities.Coupon{{Code: "OTHER", Type: "percentage", PercentOff: 10, CategoryIDs: []uint{9}}})
+
+		assert.EqualError(t, err, "coupon not applicable")
+	})
+}
+
+func TestPriceCart(t *testing.T) {
+	request := func(codes ...string) *entities.CartPricingRequest {
+		return &entities.CartPricingRequest{
+			Codes:    codes,
+			Items:    []entities.CartLine{{ProductID: 1, Quantity: 1}},
+			Shipping: money.New(5000, ""),
+		}
+	}
+
+	t.Run("price cart with a coupon", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "SHIP").Return(&entities.Coupon{Model: gorm.Model{ID: 2}, Code: "SHIP", Type: "free_shipping", Active: true}, nil)
+
+		got, err := promotionService.PriceCart(request("ship"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(50000, "THB"), got.Total)
+		assert.Equal(t, uint(2), got.Discounts[0].CouponID)
+	})
+
+	t.Run("price cart given coupon expired", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		endsAt := time.Now().Add(-time.Hour)
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "SHIP").Return(&entities.Coupon{Code: "SHIP", Type: "free_shipping", Active: true, EndsAt: &endsAt}, nil)
+
+		_, err := promotionService.PriceCart(request("SHIP"))
+
+		assert.EqualError(t, err, "coupon expired")
+	})
+
+	t.Run("price cart given usage limit reached", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "SHIP").Return(&entities.Coupon{Code: "SHIP", Type: "free_shipping", Active: true, UsageLimit: 100, Redemptions: 100}, nil)
+
+		_, err := promotionService.PriceCart(request("SHIP"))
+
+		assert.EqualError(t, err, "coupon usage limit reached")
+	})
+
+	t.Run("price cart given per-user limit as a guest", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "WELCOME").Return(&entities.Coupon{Code: "WELCOME", Type: "percentage", PercentOff: 5, Active: true, PerUserLimit: 1}, nil)
+
+		_, err := promotionService.PriceCart(request("WELCOME"))
+
+		assert.EqualError(t, err, "sign in required")
+	})
+
+	t.Run("price cart given coupon that does not stack", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "SHIP").Return(&entities.Coupon{Code: "SHIP", Type: "free_shipping", Active: true, Stackable: true}, nil)
+		mockRepo.On("GetCouponByCode", "TEN").Return(&entities.Coupon{Code: "TEN", Type: "percentage", PercentOff: 10, Active: true}, nil)
+
+		_, err := promotionService.PriceCart(request("SHIP", "TEN"))
+
+		assert.EqualError(t, err, "coupons cannot be combined")
+	})
+
+	t.Run("price cart given variant missing", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{
+			Model:    gorm.Model{ID: 1},
+			Price:    money.New(50000, "THB"),
+			Active:   true,
+			Variants: []entities.ProductVariant{{Model: gorm.Model{ID: 3}, Price: money.New(55000, "THB")}},
+		}, nil)
+
+		_, err := promotionService.PriceCart(request())
+
+		assert.EqualError(t, err, "variant required")
+	})
+}
+
+func TestRedeemCoupons(t *testing.T) {
+	t.Run("redeem coupons successfully", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		userID := uint(12)
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "WELCOME").Return(&entities.Coupon{Model: gorm.Model{ID: 5}, Code: "WELCOME", Type: "percentage", PercentOff: 5, Active: true, PerUserLimit: 1}, nil)
+		mockRepo.On("RedeemCoupons", []entities.CouponRedemption{
+			{CouponID: 5, ReferenceID: "order-42", UserID: &userID, Discount: money.New(2500, "THB")},
+		}).Return(nil)
+
+		got, err := promotionService.RedeemCoupons(&entities.CouponRedeemRequest{
+			CartPricingRequest: entities.CartPricingRequest{UserID: 12, Codes: []string{"WELCOME"}, Items: []entities.CartLine{{ProductID: 1, Quantity: 1}}},
+			ReferenceID:        "order-42",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(47500, "THB"), got.Total)
+		mockRepo.AssertNotCalled(t, "CountRedemptions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("redeem coupons given last use taken", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+		mockRepo.On("GetCouponByCode", "SHIP").Return(&entities.Coupon{Model: gorm.Model{ID: 2}, Code: "SHIP", Type: "free_shipping", Active: true, UsageLimit: 10}, nil)
+		mockRepo.On("RedeemCoupons", mock.Anything).Return(errors.New("coupon usage limit reached"))
+
+		_, err := promotionService.RedeemCoupons(&entities.CouponRedeemRequest{
+			CartPricingRequest: entities.CartPricingRequest{Codes: []string{"SHIP"}, Items: []entities.CartLine{{ProductID: 1, Quantity: 1}}},
+			ReferenceID:        "order-42",
+		})
+
+		assert.EqualError(t, err, "coupon usage limit reached")
+	})
+
+	t.Run("redeem without coupons", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(50000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{}, nil)
+
+		got, err := promotionService.RedeemCoupons(&entities.CouponRedeemRequest{
+			CartPricingRequest: entities.CartPricingRequest{Items: []entities.CartLine{{ProductID: 1, Quantity: 1}}},
+			ReferenceID:        "order-42",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(50000, "THB"), got.Total)
+		mockRepo.AssertNotCalled(t, "RedeemCoupons", mock.Anything)
+	})
+}
+
+func TestReleaseCoupons(t *testing.T) {
+	t.Run("release coupons given database error", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		promotionService := PromotionService{repo: mockRepo}
+
+		mockRepo.On("ReleaseCoupons", "order-42").Return(errors.New("connection refused"))
+
+		err := promotionService.ReleaseCoupons("order-42")
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestApplyPromotions(t *testing.T) {
+	products := []*entities.Product{
+		{Model: gorm.Model{ID: 1}, Type: "blind_box"},
+		{Model: gorm.Model{ID: 2}, Type: "blind_box"},
+		{Model: gorm.Model{ID: 3}, Type: "standard"},
+	}
+	newLines := func() []entities.PricedCartLine {
+		return []entities.PricedCartLine{
+			{ProductID: 1, Quantity: 3, UnitPrice: money.New(39000, "THB")},
+			{ProductID: 2, Quantity: 2, UnitPrice: money.New(35000, "THB")},
+			{ProductID: 3, Quantity: 1, UnitPrice: money.New(50000, "THB")},
+		}
+	}
+	buyThreeGetOne := entities.CartPromotion{Model: gorm.Model{ID: 1}, Name: "Buy 3 get 1", Type: "buy_x_get_y", Priority: 2, BuyQuantity: 3, FreeQuantity: 1, ProductType: "blind_box"}
+	bundle := entities.CartPromotion{Model: gorm.Model{ID: 2}, Name: "Starter set", Type: "bundle", Priority: 1, BundleProductIDs: []uint{1, 3}, BundlePrice: &money.Money{Amount: 80000, Currency: "THB"}}
+
+	t.Run("buy 3 blind boxes get the cheapest free", func(t *testing.T) {
+		lines := newLines()
+
+		got := applyPromotions(lines, products, []entities.CartPromotion{buyThreeGetOne})
+
+		assert.Equal(t, []entities.AppliedPromotion{{PromotionID: 1, Name: "Buy 3 get 1", Type: "buy_x_get_y", Amount: money.New(35000, "THB")}}, got)
+		assert.Equal(t, money.New(0, "THB"), lines[0].Savings)
+		assert.Equal(t, money.New(35000, "THB"), lines[1].Savings)
+		assert.Equal(t, []entities.LinePromotion{{PromotionID: 1, Name: "Buy 3 get 1", Amount: money.New(35000, "THB")}}, lines[1].Promotions)
+		assert.Equal(t, money.New(0, "THB"), lines[2].Savings)
+	})
+
+	t.Run("units claimed by a higher priority promotion", func(t *testing.T) {
+		lines := newLines()
+
+		got := applyPromotions(lines, products, runningPromotions([]entities.CartPromotion{buyThreeGetOne, bundle}, time.Now()))
+
+		assert.Equal(t, []string{"Starter set", "Buy 3 get 1"}, []string{got[0].Name, got[1].Name})
+		assert.Equal(t, money.New(9000, "THB"), got[0].Amount)
+		assert.Equal(t, money.New(35000, "THB"), got[1].Amount)
+		assert.Equal(t, money.New(3944, "THB"), lines[0].Savings)
+		assert.Equal(t, money.New(35000, "THB"), lines[1].Savings)
+		assert.Equal(t, money.New(5056, "THB"), lines[2].Savings)
+	})
+
+	t.Run("bundle that saves nothing", func(t *testing.T) {
+		lines := newLines()
+		dear := bundle
+		dear.BundlePrice = &money.Money{Amount: 100000, Currency: "THB"}
+
+		got := applyPromotions(lines, products, []entities.CartPromotion{dear})
+
+		assert.Empty(t, got)
+		assert.Nil(t, lines[0].Promotions)
+	})
+
+	t.Run("promotion outside its window", func(t *testing.T) {
+		endsAt := time.Now().Add(-time.Hour)
+		ended := buyThreeGetOne
+		ended.EndsAt = &endsAt
+
+		assert.Empty(t, runningPromotions([]entities.CartPromotion{ended}, time.Now()))
+	})
+}
+
+func TestPriceCartWithPromotions(t *testing.T) {
+	t.Run("coupon applies after promotions", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "blind_box", Price: money.New(39000, "THB"), Active: true}, nil)
+		mockRepo.On("GetCartPromotions", true).Return([]entities.CartPromotion{
+			{Model: gorm.Model{ID: 1}, Name: "Buy 3 get 1", Type: "buy_x_get_y", BuyQuantity: 3, FreeQuantity: 1, ProductType: "blind_box"},
+		}, nil)
+		mockRepo.On("GetCouponByCode", "TEN").Return(&entities.Coupon{Model: gorm.Model{ID: 5}, Code: "TEN", Type: "percentage", PercentOff: 10, Active: true}, nil)
+
+		got, err := promotionService.PriceCart(&entities.CartPricingRequest{
+			Codes: []string{"TEN"},
+			Items: []entities.CartLine{{ProductID: 1, Quantity: 4}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, money.New(156000, "THB"), got.Subtotal)
+		assert.Equal(t, money.New(39000, "THB"), got.Savings)
+		assert.Equal(t, money.New(11700, "THB"), got.Discount)
+		assert.Equal(t, money.New(105300, "THB"), got.Total)
+		assert.Equal(t, money.New(105300, "THB"), got.Items[0].Total)
+		assert.Len(t, got.Promotions, 1)
+	})
+}
+
+func TestCreateCartPromotion(t *testing.T) {
+	t.Run("create bundle successfully", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockProductRepo.On("GetProductById", "3").Return(&entities.Product{Model: gorm.Model{ID: 3}}, nil)
+		mockRepo.On("InsertCartPromotion", mock.MatchedBy(func(p *entities.CartPromotion) bool {
+			return p.Type == "bundle" && *p.BundlePrice == money.New(80000, "THB") && p.BuyQuantity == 0 && p.Active
+		})).Return(&entities.CartPromotion{Model: gorm.Model{ID: 2}}, nil)
+
+		got, err := promotionService.CreateCartPromotion(&entities.CartPromotionRequest{
+			Name:             "Starter set",
+			Type:             "bundle",
+			BuyQuantity:      3,
+			BundleProductIDs: []uint{1, 3},
+			BundlePrice:      &money.Money{Amount: 80000},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), got.ID)
+	})
+
+	t.Run("create bundle given product not found", func(t *testing.T) {
+		mockRepo := new(MockPromotionRepository)
+		mockProductRepo := new(MockProductRepository)
+		promotionService := PromotionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := promotionService.CreateCartPromotion(&entities.CartPromotionRequest{
+			Name:             "Starter set",
+			Type:             "bundle",
+			BundleProductIDs: []uint{1, 3},
+			BundlePrice:      &money.Money{Amount: 80000},
+		})
+
+		assert.EqualError(t, err, "product not found")
+		mockRepo.AssertNotCalled(t, "InsertCartPromotion", mock.Anything)
+	})
+
+	tests := []struct {
+		name    string
+		request entities.CartPromotionRequest
+	}{
+		{"buy x get y without free units", entities.CartPromotionRequest{Name: "Buy 3", Type: "buy_x_get_y", BuyQuantity: 3}},
+		{"bundle of one product", entities.CartPromotionRequest{Name: "Solo", Type: "bundle", BundleProductIDs: []uint{1}, BundlePrice: &money.Money{Amount: 100}}},
+		{"bundle with a product twice", entities.CartPromotionRequest{Name: "Pair", Type: "bundle", BundleProductIDs: []uint{1, 1}, BundlePrice: &money.Money{Amount: 100}}},
+		{"bundle without price", entities.CartPromotionRequest{Name: "Pair", Type: "bundle", BundleProductIDs: []uint{1, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPromotionRepository)
+			promotionService := PromotionService{repo: mockRepo}
+
+			_, err := promotionService.CreateCartPromotion(&tt.request)
+
+			assert.EqualError(t, err, "invalid promotion")
+		})
+	}
+}
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) InsertCoupon(coupon *entities.Coupon) (*entities.Coupon, error) {
+	args := m.Called(coupon)
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetCoupons() ([]entities.Coupon, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetCouponById(id string) (*entities.Coupon, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetCouponByCode(code string) (*entities.Coupon, error) {
+	args := m.Called(code)
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionRepository) UpdateCoupon(coupon *entities.Coupon) (*entities.Coupon, error) {
+	args := m.Called(coupon)
+	return args.Get(0).(*entities.Coupon), args.Error(1)
+}
+
+func (m *MockPromotionRepository) DeleteCoupon(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) CountRedemptions(couponID, userID uint) (int64, error) {
+	args := m.Called(couponID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPromotionRepository) RedeemCoupons(redemptions []entities.CouponRedemption) error {
+	args := m.Called(redemptions)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) ReleaseCoupons(referenceID string) error {
+	args := m.Called(referenceID)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) InsertCartPromotion(promotion *entities.CartPromotion) (*entities.CartPromotion, error) {
+	args := m.Called(promotion)
+	return args.Get(0).(*entities.CartPromotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetCartPromotions(activeOnly bool) ([]entities.CartPromotion, error) {
+	args := m.Called(activeOnly)
+	return args.Get(0).([]entities.CartPromotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) DeleteCartPromotion(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
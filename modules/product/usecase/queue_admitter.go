@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// QueueAdmitter lets the next batch of every active waiting room through to
+// checkout once per interval, which sets the rate the drop sells at.
+type QueueAdmitter struct {
+	usecase  QueueUsecase
+	interval time.Duration
+}
+
+func NewQueueAdmitter(usecase QueueUsecase, interval time.Duration) *QueueAdmitter {
+	return &QueueAdmitter{usecase, interval}
+}
+
+// Run admits a batch every interval until ctx is cancelled.
+func (a *QueueAdmitter) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.admit()
+		}
+	}
+}
+
+func (a *QueueAdmitter) admit() {
+	admitted, err := a.usecase.AdmitNext()
+	if err != nil {
+		log.Printf("failed to admit queued shoppers: %v", err)
+	}
+
+	if admitted > 0 {
+		log.Printf("admitted %d queued shoppers", admitted)
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+// queueTicketTTL bounds how long a ticket token can be used to poll; a
+// drop's queue never runs anywhere near this long.
+const queueTicketTTL = 24 * time.Hour
+
+type QueueUsecase interface {
+	ConfigureWaitingRoom(productID string, request *entities.WaitingRoomRequest) (*entities.WaitingRoom, error)
+	JoinQueue(productID string, userID uint) (*entities.QueueStatus, error)
+	GetQueueStatus(productID string, token string) (*entities.QueueStatus, error)
+	AdmitNext() (int64, error)
+	CheckAdmission(productID string, userID uint, token string) error
+}
+
+type QueueService struct {
+	repo        QueueRepository
+	productRepo ProductRepository
+	secret      []byte
+}
+
+func NewQueueService(repo QueueRepository, productRepo ProductRepository, secret string) QueueUsecase {
+	return &QueueService{repo, productRepo, []byte(secret)}
+}
+
+func (s *QueueService) ConfigureWaitingRoom(productID string, request *entities.WaitingRoomRequest) (*entities.WaitingRoom, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.repo.GetWaitingRoom(product.ID)
+	if err != nil {
+		if err.Error() != "waiting room not found" {
+			return nil, errors.New("database error")
+		}
+		room = &entities.WaitingRoom{ProductID: product.ID, Active: true}
+	}
+
+	room.BatchSize = request.BatchSize
+	room.AdmissionMinutes = request.AdmissionMinutes
+	if request.Active != nil {
+		room.Active = *request.Active
+	}
+
+	saved, err := s.repo.SaveWaitingRoom(room)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return saved, nil
+}
+
+// JoinQueue hands the user a signed ticket for the product's waiting room.
+func (s *QueueService) JoinQueue(productID string, userID uint) (*entities.QueueStatus, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.getWaitingRoom(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !room.Active {
+		return nil, errors.New("waiting room is closed")
+	}
+
+	ticket, err := s.repo.JoinQueue(product.ID, userID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return s.queueStatus(ticket)
+}
+
+// GetQueueStatus reports where the holder of a ticket token stands. It is
+// what clients poll, or what the event stream sends, while they wait.
+func (s *QueueService) GetQueueStatus(productID string, token string) (*entities.QueueStatus, error) {
+	claims, err := s.parseToken(token, entities.QueueTokenTicket)
+	if err != nil || strconv.FormatUint(uint64(claims.ProductID), 10) != productID {
+		return nil, errors.New("invalid queue ticket")
+	}
+
+	ticket, err := s.repo.GetTicket(claims.TicketID)
+	if err != nil {
+		if err.Error() == "queue ticket not found" {
+			return nil, errors.New("invalid queue ticket")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return s.queueStatus(ticket)
+}
+
+// AdmitNext admits the next batch of every active waiting room and returns
+// how many tickets were admitted.
+func (s *QueueService) AdmitNext() (int64, error) {
+	rooms, err := s.repo.GetActiveWaitingRooms()
+	if err != nil {
+		return 0, errors.New("database error")
+	}
+
+	now := time.Now()
+
+	var admitted int64
+	for _, room := range rooms {
+		expiresAt := now.Add(time.Duration(room.AdmissionMinutes) * time.Minute)
+
+		count, err := s.repo.AdmitTickets(room.ProductID, room.BatchSize, now, expiresAt)
+		admitted += count
+		if err != nil {
+			return admitted, errors.New("database error")
+		}
+	}
+
+	return admitted, nil
+}
+
+// CheckAdmission lets checkout through for products without an active
+// waiting room, and otherwise only with an unexpired admission token issued
+// to the signed-in user for that product; without a user there is no one to
+// admit. The token is verified by its signature alone so the check stays
+// cheap while the queue is busy.
+func (s *QueueService) CheckAdmission(productID string, userID uint, token string) error {
+	id, err := strconv.ParseUint(productID, 10, 64)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	room, err := s.getWaitingRoom(uint(id))
+	if err != nil {
+		if err.Error() == "waiting room not found" {
+			return nil
+		}
+		return err
+	}
+
+	if !room.Active {
+		return nil
+	}
+
+	claims, err := s.parseToken(token, entities.QueueTokenAdmission)
+	if err != nil || claims.ProductID != room.ProductID || userID == 0 || claims.UserID != userID {
+		return errors.New("admission required")
+	}
+
+	return nil
+}
+
+func (s *QueueService) queueStatus(ticket *entities.QueueTicket) (*entities.QueueStatus, error) {
+	token, err := s.signToken(ticket, entities.QueueTokenTicket, ticket.CreatedAt.Add(queueTicketTTL))
+	if err != nil {
+		return nil, errors.New("failed to sign queue token")
+	}
+
+	status := &entities.QueueStatus{
+		TicketID: ticket.ID,
+		Status:   ticket.Status,
+		Token:    token,
+	}
+
+	if ticket.Status == entities.QueueTicketAdmitted {
+		admissionToken, err := s.signToken(ticket, entities.QueueTokenAdmission, *ticket.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("failed to sign queue token")
+		}
+		status.AdmissionToken = admissionToken
+		status.AdmissionExpiresAt = ticket.ExpiresAt
+
+		return status, nil
+	}
+
+	ahead, err := s.repo.CountAhead(ticket)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	status.Position = ahead + 1
+
+	return status, nil
+}
+
+func (s *QueueService) signToken(ticket *entities.QueueTicket, tokenType string, expiresAt time.Time) (string, error) {
+	claims := &entities.QueueClaims{
+		TicketID:  ticket.ID,
+		ProductID: ticket.ProductID,
+		UserID:    ticket.UserID,
+		Type:      tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+func (s *QueueService) parseToken(tokenString, expectedType string) (*entities.QueueClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &entities.QueueClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*entities.QueueClaims)
+	if !ok || !token.Valid || claims.Type != expectedType {
+		return nil, errors.New("invalid queue token")
+	}
+
+	return claims, nil
+}
+
+func (s *QueueService) getWaitingRoom(productID uint) (*entities.WaitingRoom, error) {
+	room, err := s.repo.GetWaitingRoom(productID)
+	if err != nil {
+		if err.Error() == "waiting room not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return room, nil
+}
+
+func (s *QueueService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const testQueueSecret = "queue-secret"
+
+func TestJoinQueue(t *testing.T) {
+	t.Run("join queue and get a signed ticket", func(t *testing.T) {
+		mockRepo := new(MockQueueRepository)
+		mockProductRepo := new(MockProductRepository)
+		queueService := QueueService{repo: mockRepo, productRepo: mockProductRepo, secret: []byte(testQueueSecret)}
+
+		ticket := &entities.QueueTicket{ID: 40, CreatedAt: time.Now(), ProductID: 1, UserID: 12, Status: "waiting"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetWaitingRoom", uint(1)).Return(&entities.WaitingRoom{ProductID: 1, BatchSize: 50, AdmissionMinutes: 10, Active: true}, nil)
+		mockRepo.On("JoinQueue", uint(1), uint(12)).Return(ticket, nil)
+		mockRepo.On("CountAhead", ticket).Return(int64(39), nil)
+
+		got, err := queueService.JoinQueue("1", 12)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(40), got.Position)
+		assert.Empty(t, got.AdmissionToken)
+
+		claims, err := queueService.parseToken(got.Token, entities.QueueTokenTicket)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(40), claims.TicketID)
+	})
+
+	t.Run("join a closed waiting room", func(t *testing.T) {
+		mockRepo := new(MockQueueRepository)
+		mockProductRepo := new(MockProductRepository)
+		queueService := QueueService{repo: mockRepo, productRepo: mockProductRepo, secret: []byte(testQueueSecret)}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("GetWaitingRoom", uint(1)).Return(&entities.WaitingRoom{ProductID: 1, Active: false}, nil)
+
+		_, err := queueService.JoinQueue("1", 12)
+
+		assert.EqualError(t, err, "waiting room is closed")
+		mockRepo.AssertNotCalled(t, "JoinQueue", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetQueueStatus(t *testing.T) {
+	t.Run("admitted ticket gets an admission token", func(t *testing.T) {
+		mockRepo := new(MockQueueRepository)
+		queueService := QueueService{repo: mockRepo, secret: []byte(testQueueSecret)}
+
+		expiresAt := time.Now().Add(10 * time.Minute)
+		ticket := &entities.QueueTicket{ID: 40, CreatedAt: time.Now(), ProductID: 1, UserID: 12, Status: "admitted", ExpiresAt: &expiresAt}
+		token, _ := queueService.signToken(ticket, entities.QueueTokenTicket, time.Now().Add(time.Hour))
+
+		mockRepo.On("GetTicket", uint(40)).Return(ticket, nil)
+
+		got, err := queueService.GetQueueStatus("1", token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "admitted", got.Status)
+		assert.Zero(t, got.Position)
+
+		claims, err := queueService.parseToken(got.AdmissionToken, entities.QueueTokenAdmission)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(12), claims.UserID)
+	})
+
+	t.Run("status given a ticket for another product", func(t *testing.T) {
+		queueService := QueueService{repo: new(MockQueueRepository), secret: []byte(testQueueSecret)}
+
+		ticket := &entities.QueueTicket{ID: 40, ProductID: 2, UserID: 12}
+		token, _ := queueService.signToken(ticket, entities.QueueTokenTicket, time.Now().Add(time.Hour))
+
+		_, err := queueService.GetQueueStatus("1", token)
+
+		assert.EqualError(t, err, "invalid queue ticket")
+	})
+
+	t.Run("status given a ticket signed with another secret", func(t *testing.T) {
+		forger := QueueService{secret: []byte("not-the-secret")}
+		queueService := QueueService{repo: new(MockQueueRepository), secret: []byte(testQueueSecret)}
+
+		token, _ := forger.signToken(&entities.QueueTicket{ID: 1, ProductID: 1}, entities.QueueTokenTicket, time.Now().Add(time.Hour))
+
+		_, err := queueService.GetQueueStatus("1", token)
+
+		assert.EqualError(t, err, "invalid queue ticket")
+	})
+}
+
+func TestCheckAdmission(t *testing.T) {
+	room := &entities.WaitingRoom{ProductID: 1, BatchSize: 50, AdmissionMinutes: 10, Active: true}
+	ticket := &entities.QueueTicket{ID: 40, ProductID: 1, UserID: 12}
+
+	signer := QueueService{secret: []byte(testQueueSecret)}
+	admission, _ := signer.signToken(ticket, entities.QueueTokenAdmission, time.Now().Add(10*time.Minute))
+	expired, _ := signer.signToken(ticket, entities.QueueTokenAdmission, time.Now().Add(-time.Minute))
+	queueTicket, _ := signer.signToken(ticket, entities.QueueTokenTicket, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name   string
+		room   *entities.WaitingRoom
+		userID uint
+		token  string
+		want   string
+	}{
+		{"valid admission", room, 12, admission, ""},
+		{"no user on the request", room, 0, admission, "admission required"},
+		{"missing token", room, 12, "", "admission required"},
+		{"expired token", room, 12, expired, "admission required"},
+		{"ticket token instead of admission", room, 12, queueTicket, "admission required"},
+		{"another user's admission", room, 77, admission, "admission required"},
+		{"inactive waiting room", &entities.WaitingRoom{ProductID: 1, Active: false}, 12, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockQueueRepository)
+			queueService := QueueService{repo: mockRepo, secret: []byte(testQueueSecret)}
+
+			mockRepo.On("GetWaitingRoom", uint(1)).Return(tt.room, nil)
+
+			err := queueService.CheckAdmission("1", tt.userID, tt.token)
+
+			if tt.want == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("product without a waiting room", func(t *testing.T) {
+		mockRepo := new(MockQueueRepository)
+		queueService := QueueService{repo: mockRepo, secret: []byte(testQueueSecret)}
+
+		mockRepo.On("GetWaitingRoom", uint(3)).Return((*entities.WaitingRoom)(nil), errors.New("waiting room not found"))
+
+		assert.NoError(t, queueService.CheckAdmission("3", 12, ""))
+	})
+}
+
+func TestAdmitNext(t *testing.T) {
+	t.Run("admit a batch from every active room", func(t *testing.T) {
+		mockRepo := new(MockQueueRepository)
+		queueService := QueueService{repo: mockRepo}
+
+		mockRepo.On("GetActiveWaitingRooms").Return([]entities.WaitingRoom{
+			{ProductID: 1, BatchSize: 50, AdmissionMinutes: 10, Active: true},
+			{ProductID: 2, BatchSize: 20, AdmissionMinutes: 5, Active: true},
+		}, nil)
+		mockRepo.On("AdmitTickets", uint(1), 50, mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+			return time.Until(expiresAt) > 9*time.Minute
+		})).Return(int64(50), nil)
+		mockRepo.On("AdmitTickets", uint(2), 20, mock.Anything, mock.Anything).Return(int64(3), nil)
+
+		got, err := queueService.AdmitNext()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(53), got)
+	})
+}
+
+func TestQueueAdmitter(t *testing.T) {
+	t.Run("admit on every tick until cancelled", func(t *testing.T) {
+		mockRepo := new(MockQueueRepository)
+		admitter := NewQueueAdmitter(&QueueService{repo: mockRepo}, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockRepo.On("GetActiveWaitingRooms").Return([]entities.WaitingRoom{}, nil).Run(func(mock.Arguments) {
+			cancel()
+		})
+
+		done := make(chan struct{})
+		go func() {
+			admitter.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("admitter did not stop after cancel")
+		}
+		mockRepo.AssertCalled(t, "GetActiveWaitingRooms")
+	})
+}
+
+type MockQueueRepository struct {
+	mock.Mock
+}
+
+func (m *MockQueueRepository) SaveWaitingRoom(room *entities.WaitingRoom) (*entities.WaitingRoom, error) {
+	args := m.Called(room)
+	return args.Get(0).(*entities.WaitingRoom), args.Error(1)
+}
+
+func (m *MockQueueRepository) GetWaitingRoom(productID uint) (*entities.WaitingRoom, error) {
+	args := m.Called(productID)
+	return args.Get(0).(*entities.WaitingRoom), args.Error(1)
+}
+
+func (m *MockQueueRepository) GetActiveWaitingRooms() ([]entities.WaitingRoom, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.WaitingRoom), args.Error(1)
+}
+
+func (m *MockQueueRepository) JoinQueue(productID, userID uint) (*entities.QueueTicket, error) {
+	args := m.Called(productID, userID)
+	return args.Get(0).(*entities.QueueTicket), args.Error(1)
+}
+
+func (m *MockQueueRepository) GetTicket(id uint) (*entities.QueueTicket, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.QueueTicket), args.Error(1)
+}
+
+func (m *MockQueueRepository) CountAhead(ticket *entities.QueueTicket) (int64, error) {
+	args := m.Called(ticket)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockQueueRepository) AdmitTickets(productID uint, limit int, admittedAt, expiresAt time.Time) (int64, error) {
+	args := m.Called(productID, limit, admittedAt, expiresAt)
+	return args.Get(0).(int64), args.Error(1)
+}
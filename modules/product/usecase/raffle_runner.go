@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RaffleRunner draws raffles as their entry windows close, places the orders
+// their wins are paid through and passes unpaid wins down the waitlist once
+// their payment deadline has gone by.
+type RaffleRunner struct {
+	usecase  RaffleUsecase
+	interval time.Duration
+}
+
+func NewRaffleRunner(usecase RaffleUsecase, interval time.Duration) *RaffleRunner {
+	return &RaffleRunner{usecase, interval}
+}
+
+// Run works through the raffles every interval until ctx is cancelled.
+func (r *RaffleRunner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.run()
+		}
+	}
+}
+
+func (r *RaffleRunner) run() {
+	drawn, err := r.usecase.DrawClosedRaffles()
+	if err != nil {
+		log.Printf("failed to draw raffles: %v", err)
+	}
+
+	if drawn > 0 {
+		log.Printf("drew %d raffles", drawn)
+	}
+
+	passed, err := r.usecase.PassOnLapsedWins()
+	if err != nil {
+		log.Printf("failed to pass on lapsed raffle wins: %v", err)
+	}
+
+	if passed > 0 {
+		log.Printf("passed on %d lapsed raffle wins", passed)
+	}
+
+	placed, err := r.usecase.PlaceWinOrders()
+	if err != nil {
+		log.Printf("failed to place raffle win orders: %v", err)
+	}
+
+	if placed > 0 {
+		log.Printf("placed %d raffle win orders", placed)
+	}
+}
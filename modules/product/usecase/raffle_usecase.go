@@ -0,0 +1,345 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/draw"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"gorm.io/gorm"
+)
+
+const raffleBatch = 100
+
+type RaffleUsecase interface {
+	CreateRaffle(productID string, request *entities.RaffleRequest) (*entities.Raffle, error)
+	GetRaffle(id string) (*entities.Raffle, error)
+	EnterRaffle(id string, userID uint) (*entities.RaffleEntry, error)
+	GetRaffleEntry(id string, userID uint) (*entities.RaffleEntry, error)
+	SettleRafflePayment(confirmation *payment.Confirmation) (*entities.RaffleEntry, error)
+	DrawClosedRaffles() (int, error)
+	PlaceWinOrders() (int, error)
+	PassOnLapsedWins() (int, error)
+}
+
+type RaffleService struct {
+	repo        RaffleRepository
+	productRepo ProductRepository
+	accounts    Accounts
+	orders      Orders
+}
+
+func NewRaffleService(repo RaffleRepository, productRepo ProductRepository, accounts Accounts, orders Orders) RaffleUsecase {
+	return &RaffleService{repo, productRepo, accounts, orders}
+}
+
+// CreateRaffle sets aside units of a product for a raffle at its price now
+// and commits to the seed its draw will use.
+func (s *RaffleService) CreateRaffle(productID string, request *entities.RaffleRequest) (*entities.Raffle, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+		return nil, errors.New("blind box stock is set by its figures")
+	}
+
+	now := time.Now()
+	if !request.EntryClosesAt.After(now) {
+		return nil, errors.New("raffle entry has already closed")
+	}
+
+	// Raffle units are promised to their winners, so they are priced whether
+	// or not the product is otherwise on sale.
+	price, err := listPrice(product, request.VariantID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, err := draw.NewSeed()
+	if err != nil {
+		return nil, errors.New("failed to generate seed")
+	}
+
+	raffle, err := s.repo.InsertRaffle(&entities.Raffle{
+		ProductID:      product.ID,
+		VariantID:      optionalID(request.VariantID),
+		Quantity:       request.Quantity,
+		Price:          price,
+		EntryOpensAt:   request.EntryOpensAt,
+		EntryClosesAt:  request.EntryClosesAt,
+		PaymentMinutes: request.PaymentMinutes,
+		Status:         entities.RaffleOpen,
+		Seed:           seed,
+		SeedHash:       draw.HashSeed(seed),
+	})
+	if err != nil {
+		switch err.Error() {
+		case "product not found", "variant not found", "variant required", "insufficient stock":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return revealRaffleSeed(raffle), nil
+}
+
+// GetRaffle returns a raffle with its seed revealed once it has been drawn.
+func (s *RaffleService) GetRaffle(id string) (*entities.Raffle, error) {
+	raffle, err := s.getRaffle(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return revealRaffleSeed(raffle), nil
+}
+
+// EnterRaffle enters a signed-in user while the raffle's entry window is open.
+// Only users who have verified their email may enter, so one person cannot
+// enter many times over throwaway accounts.
+func (s *RaffleService) EnterRaffle(id string, userID uint) (*entities.RaffleEntry, error) {
+	raffle, err := s.getRaffle(id)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accounts.GetAccount(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	if !account.EmailVerified {
+		return nil, errors.New("email not verified")
+	}
+
+	now := time.Now()
+	if raffle.Status != entities.RaffleOpen || now.Before(raffle.EntryOpensAt) || !now.Before(raffle.EntryClosesAt) {
+		return nil, errors.New("raffle is not open for entries")
+	}
+
+	entry, err := s.repo.InsertEntry(&entities.RaffleEntry{
+		RaffleID: raffle.ID,
+		UserID:   userID,
+		Status:   entities.RaffleEntryEntered,
+	})
+	if err != nil {
+		if err.Error() == "already entered" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return entry, nil
+}
+
+func (s *RaffleService) GetRaffleEntry(id string, userID uint) (*entities.RaffleEntry, error) {
+	raffle, err := s.getRaffle(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.repo.GetEntry(raffle.ID, userID)
+	if err != nil {
+		if err.Error() == "raffle entry not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return entry, nil
+}
+
+// SettleRafflePayment sells a winner their unit once the payment provider
+// confirms that the order placed for the win was paid in full, provided the
+// payment deadline has not passed, and then marks the order paid. Selling the
+// unit is repeatable, so a confirmation retried after the order failed to
+// settle settles it then.
+func (s *RaffleService) SettleRafflePayment(confirmation *payment.Confirmation) (*entities.RaffleEntry, error) {
+	entry, err := s.repo.PayEntry(confirmation.ReferenceID, confirmation.Amount, time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "raffle entry not found", "raffle entry is not a win", "payment deadline has passed", "payment amount does not match":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	if err := s.orders.SettleOrder(confirmation.ReferenceID, confirmation.Amount); err != nil {
+		log.Printf("failed to settle raffle order %s: %v", confirmation.ReferenceID, err)
+		return nil, errors.New("database error")
+	}
+
+	return entry, nil
+}
+
+// DrawClosedRaffles draws every raffle whose entry window has closed and
+// returns how many were drawn. A raffle that fails to draw is logged and
+// left open for the next run, so it does not hold up the others.
+func (s *RaffleService) DrawClosedRaffles() (int, error) {
+	total := 0
+
+	for {
+		now := time.Now()
+
+		raffles, err := s.repo.GetRafflesToDraw(now, raffleBatch)
+		if err != nil {
+			return total, errors.New("database error")
+		}
+
+		failed := 0
+		for _, raffle := range raffles {
+			drawn, err := s.repo.DrawRaffle(raffle.ID, now)
+			if err != nil {
+				log.Printf("failed to draw raffle %d: %v", raffle.ID, err)
+				failed++
+				continue
+			}
+			if drawn {
+				total++
+			}
+		}
+
+		// A full batch of failures would be listed again as it is.
+		if len(raffles) < raffleBatch || failed == len(raffles) {
+			return total, nil
+		}
+	}
+}
+
+// PlaceWinOrders places the order each win is paid through, with the
+// raffle's payment window starting once it is placed, and returns how many
+// were placed. A win that fails is logged and tried again on the next run.
+func (s *RaffleService) PlaceWinOrders() (int, error) {
+	entries, err := s.repo.GetUnorderedWins(raffleBatch)
+	if err != nil {
+		return 0, errors.New("database error")
+	}
+
+	total := 0
+	raffles := map[uint]*entities.Raffle{}
+	for _, entry := range entries {
+		raffle, ok := raffles[entry.RaffleID]
+		if !ok {
+			if raffle, err = s.repo.GetRaffle(entry.RaffleID); err != nil {
+				log.Printf("failed to place order for raffle entry %d: %v", entry.ID, err)
+				continue
+			}
+			raffles[entry.RaffleID] = raffle
+		}
+
+		if s.placeWinOrder(raffle, &entry) {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+func (s *RaffleService) placeWinOrder(raffle *entities.Raffle, entry *entities.RaffleEntry) bool {
+	order, err := s.orders.PlaceOrder(entry.UserID, raffle.ProductID, raffle.VariantID, raffle.Price)
+	if err != nil {
+		log.Printf("failed to place order for raffle entry %d: %v", entry.ID, err)
+		return false
+	}
+
+	dueAt := time.Now().Add(time.Duration(raffle.PaymentMinutes) * time.Minute)
+	attached, err := s.repo.AttachOrder(entry.ID, order, dueAt)
+	if err == nil && attached {
+		return true
+	}
+	if err != nil {
+		log.Printf("failed to attach order %s to raffle entry %d: %v", order.ReferenceID, entry.ID, err)
+	}
+
+	// The win was ordered or passed on meanwhile; the new order is not needed.
+	if err := s.orders.CancelOrder(entry.UserID, order.ReferenceID); err != nil {
+		log.Printf("failed to cancel raffle order %s: %v", order.ReferenceID, err)
+	}
+
+	return false
+}
+
+// PassOnLapsedWins hands every win left unpaid past its deadline to the next
+// waitlisted entry, cancelling the lapsed win's order, and returns how many
+// were passed on. A win that fails to pass on is logged and left for the next
+// run.
+func (s *RaffleService) PassOnLapsedWins() (int, error) {
+	total := 0
+
+	for {
+		now := time.Now()
+
+		entries, err := s.repo.GetLapsedWins(now, raffleBatch)
+		if err != nil {
+			return total, errors.New("database error")
+		}
+
+		failed := 0
+		for _, entry := range entries {
+			lapsed, err := s.repo.PassOnWin(entry.ID, now)
+			if err != nil {
+				log.Printf("failed to pass on raffle entry %d: %v", entry.ID, err)
+				failed++
+				continue
+			}
+			if lapsed == nil {
+				continue
+			}
+			total++
+
+			if lapsed.ReferenceID != "" {
+				if err := s.orders.CancelOrder(lapsed.UserID, lapsed.ReferenceID); err != nil {
+					log.Printf("failed to cancel raffle order %s: %v", lapsed.ReferenceID, err)
+				}
+			}
+		}
+
+		if len(entries) < raffleBatch || failed == len(entries) {
+			return total, nil
+		}
+	}
+}
+
+func (s *RaffleService) getRaffle(id string) (*entities.Raffle, error) {
+	raffleID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.New("raffle not found")
+	}
+
+	raffle, err := s.repo.GetRaffle(uint(raffleID))
+	if err != nil {
+		if err.Error() == "raffle not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return raffle, nil
+}
+
+func (s *RaffleService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+func revealRaffleSeed(raffle *entities.Raffle) *entities.Raffle {
+	if raffle.Status == entities.RaffleDrawn {
+		raffle.RevealedSeed = raffle.Seed
+	}
+
+	return raffle
+}
@@ -0,0 +1,468 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/draw"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/phetployst/art-toys-store/pkg/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateRaffle(t *testing.T) {
+	t.Run("create raffle and commit to its seed", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockProductRepo := new(MockProductRepository)
+		raffleService := RaffleService{repo: mockRepo, productRepo: mockProductRepo}
+
+		opensAt := time.Now()
+		closesAt := opensAt.Add(48 * time.Hour)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Stock: 10, Price: money.New(129000, "THB")}, nil)
+		mockRepo.On("InsertRaffle", mock.MatchedBy(func(r *entities.Raffle) bool {
+			return r.ProductID == 1 && r.Quantity == 3 && r.Status == entities.RaffleOpen && r.Price == money.New(129000, "THB") &&
+				r.Seed != "" && r.SeedHash == draw.HashSeed(r.Seed)
+		})).Return(&entities.Raffle{Model: gorm.Model{ID: 7}, Status: entities.RaffleOpen, Seed: "secret"}, nil)
+
+		got, err := raffleService.CreateRaffle("1", &entities.RaffleRequest{
+			Quantity:       3,
+			EntryOpensAt:   opensAt,
+			EntryClosesAt:  closesAt,
+			PaymentMinutes: 60,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), got.ID)
+		assert.Empty(t, got.RevealedSeed)
+	})
+
+	t.Run("create raffle given more units than in stock", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockProductRepo := new(MockProductRepository)
+		raffleService := RaffleService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Stock: 1, Price: money.New(129000, "THB")}, nil)
+		mockRepo.On("InsertRaffle", mock.Anything).Return((*entities.Raffle)(nil), errors.New("insufficient stock"))
+
+		_, err := raffleService.CreateRaffle("1", &entities.RaffleRequest{
+			Quantity:       3,
+			EntryOpensAt:   time.Now(),
+			EntryClosesAt:  time.Now().Add(time.Hour),
+			PaymentMinutes: 60,
+		})
+
+		assert.EqualError(t, err, "insufficient stock")
+	})
+
+	t.Run("create raffle whose entry has already closed", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockProductRepo := new(MockProductRepository)
+		raffleService := RaffleService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}}, nil)
+
+		_, err := raffleService.CreateRaffle("1", &entities.RaffleRequest{
+			Quantity:       1,
+			EntryOpensAt:   time.Now().Add(-2 * time.Hour),
+			EntryClosesAt:  time.Now().Add(-time.Hour),
+			PaymentMinutes: 60,
+		})
+
+		assert.EqualError(t, err, "raffle entry has already closed")
+		mockRepo.AssertNotCalled(t, "InsertRaffle", mock.Anything)
+	})
+
+	t.Run("create raffle for a product sold in variants without naming one", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockProductRepo := new(MockProductRepository)
+		raffleService := RaffleService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Price: money.New(129000, "THB"),
+			Variants: []entities.ProductVariant{{Model: gorm.Model{ID: 3}, Price: money.New(139000, "THB")}}}, nil)
+
+		_, err := raffleService.CreateRaffle("1", &entities.RaffleRequest{
+			Quantity:       1,
+			EntryOpensAt:   time.Now(),
+			EntryClosesAt:  time.Now().Add(time.Hour),
+			PaymentMinutes: 60,
+		})
+
+		assert.EqualError(t, err, "variant required")
+		mockRepo.AssertNotCalled(t, "InsertRaffle", mock.Anything)
+	})
+}
+
+func TestGetRaffle(t *testing.T) {
+	t.Run("reveal seed once drawn", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		raffleService := RaffleService{repo: mockRepo}
+
+		mockRepo.On("GetRaffle", uint(7)).Return(&entities.Raffle{Model: gorm.Model{ID: 7}, Status: entities.RaffleDrawn, Seed: "secret"}, nil)
+
+		got, err := raffleService.GetRaffle("7")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", got.RevealedSeed)
+	})
+
+	t.Run("keep seed hidden while open", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		raffleService := RaffleService{repo: mockRepo}
+
+		mockRepo.On("GetRaffle", uint(7)).Return(&entities.Raffle{Model: gorm.Model{ID: 7}, Status: entities.RaffleOpen, Seed: "secret"}, nil)
+
+		got, err := raffleService.GetRaffle("7")
+
+		assert.NoError(t, err)
+		assert.Empty(t, got.RevealedSeed)
+	})
+
+	t.Run("get raffle given invalid id", func(t *testing.T) {
+		raffleService := RaffleService{repo: new(MockRaffleRepository)}
+
+		_, err := raffleService.GetRaffle("abc")
+
+		assert.EqualError(t, err, "raffle not found")
+	})
+}
+
+func TestEnterRaffle(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		raffle  *entities.Raffle
+		wantErr string
+	}{
+		{"enter while open", &entities.Raffle{Status: entities.RaffleOpen, EntryOpensAt: now.Add(-time.Hour), EntryClosesAt: now.Add(time.Hour)}, ""},
+		{"enter before opening", &entities.Raffle{Status: entities.RaffleOpen, EntryOpensAt: now.Add(time.Hour), EntryClosesAt: now.Add(2 * time.Hour)}, "raffle is not open for entries"},
+		{"enter after closing", &entities.Raffle{Status: entities.RaffleOpen, EntryOpensAt: now.Add(-2 * time.Hour), EntryClosesAt: now.Add(-time.Hour)}, "raffle is not open for entries"},
+		{"enter once drawn", &entities.Raffle{Status: entities.RaffleDrawn, EntryOpensAt: now.Add(-time.Hour), EntryClosesAt: now.Add(time.Hour)}, "raffle is not open for entries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRaffleRepository)
+			mockAccounts := new(MockAccounts)
+			raffleService := RaffleService{repo: mockRepo, accounts: mockAccounts}
+
+			tt.raffle.ID = 7
+			mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12, EmailVerified: true}, nil)
+			mockRepo.On("GetRaffle", uint(7)).Return(tt.raffle, nil)
+			mockRepo.On("InsertEntry", &entities.RaffleEntry{RaffleID: 7, UserID: 12, Status: entities.RaffleEntryEntered}).
+				Return(&entities.RaffleEntry{ID: 30, RaffleID: 7, UserID: 12, Status: entities.RaffleEntryEntered}, nil)
+
+			got, err := raffleService.EnterRaffle("7", 12)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "InsertEntry", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint(30), got.ID)
+		})
+	}
+
+	t.Run("enter twice", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockAccounts := new(MockAccounts)
+		raffleService := RaffleService{repo: mockRepo, accounts: mockAccounts}
+
+		mockRepo.On("GetRaffle", uint(7)).Return(&entities.Raffle{Model: gorm.Model{ID: 7}, Status: entities.RaffleOpen, EntryOpensAt: now.Add(-time.Hour), EntryClosesAt: now.Add(time.Hour)}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12, EmailVerified: true}, nil)
+		mockRepo.On("InsertEntry", mock.Anything).Return((*entities.RaffleEntry)(nil), errors.New("already entered"))
+
+		_, err := raffleService.EnterRaffle("7", 12)
+
+		assert.EqualError(t, err, "already entered")
+	})
+
+	t.Run("enter without a verified email", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockAccounts := new(MockAccounts)
+		raffleService := RaffleService{repo: mockRepo, accounts: mockAccounts}
+
+		mockRepo.On("GetRaffle", uint(7)).Return(&entities.Raffle{Model: gorm.Model{ID: 7}, Status: entities.RaffleOpen, EntryOpensAt: now.Add(-time.Hour), EntryClosesAt: now.Add(time.Hour)}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12}, nil)
+
+		_, err := raffleService.EnterRaffle("7", 12)
+
+		assert.EqualError(t, err, "email not verified")
+		mockRepo.AssertNotCalled(t, "InsertEntry", mock.Anything)
+	})
+}
+
+func TestSettleRafflePayment(t *testing.T) {
+	t.Run("settle a confirmed payment", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		mockRepo.On("PayEntry", "a1b2", money.New(129000, "THB"), mock.AnythingOfType("time.Time")).
+			Return(&entities.RaffleEntry{ID: 30, Status: entities.RaffleEntryPaid, ReferenceID: "a1b2"}, nil)
+		mockOrders.On("SettleOrder", "a1b2", money.New(129000, "THB")).Return(nil)
+
+		got, err := raffleService.SettleRafflePayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.RaffleEntryPaid, got.Status)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("settle a payment whose order fails to settle", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		mockRepo.On("PayEntry", "a1b2", money.New(129000, "THB"), mock.Anything).
+			Return(&entities.RaffleEntry{ID: 30, Status: entities.RaffleEntryPaid, ReferenceID: "a1b2"}, nil)
+		mockOrders.On("SettleOrder", "a1b2", money.New(129000, "THB")).Return(errors.New("database error"))
+
+		_, err := raffleService.SettleRafflePayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(129000, "THB")})
+
+		assert.EqualError(t, err, "database error")
+	})
+
+	t.Run("settle a payment for the wrong amount", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		raffleService := RaffleService{repo: mockRepo}
+
+		mockRepo.On("PayEntry", "a1b2", money.New(100, "THB"), mock.Anything).Return((*entities.RaffleEntry)(nil), errors.New("payment amount does not match"))
+
+		_, err := raffleService.SettleRafflePayment(&payment.Confirmation{ReferenceID: "a1b2", Amount: money.New(100, "THB")})
+
+		assert.EqualError(t, err, "payment amount does not match")
+	})
+}
+
+func TestDrawClosedRaffles(t *testing.T) {
+	t.Run("draw every closed raffle", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		raffleService := RaffleService{repo: mockRepo}
+
+		mockRepo.On("GetRafflesToDraw", mock.Anything, raffleBatch).Return([]entities.Raffle{{Model: gorm.Model{ID: 7}}, {Model: gorm.Model{ID: 8}}}, nil)
+		mockRepo.On("DrawRaffle", uint(7), mock.Anything).Return(true, nil)
+		mockRepo.On("DrawRaffle", uint(8), mock.Anything).Return(false, nil)
+
+		got, err := raffleService.DrawClosedRaffles()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("keep drawing after a raffle fails", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		raffleService := RaffleService{repo: mockRepo}
+
+		mockRepo.On("GetRafflesToDraw", mock.Anything, raffleBatch).Return([]entities.Raffle{{Model: gorm.Model{ID: 7}}, {Model: gorm.Model{ID: 8}}}, nil)
+		mockRepo.On("DrawRaffle", uint(7), mock.Anything).Return(false, errors.New("connection reset"))
+		mockRepo.On("DrawRaffle", uint(8), mock.Anything).Return(true, nil)
+
+		got, err := raffleService.DrawClosedRaffles()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+		mockRepo.AssertCalled(t, "DrawRaffle", uint(8), mock.Anything)
+	})
+}
+
+func TestPlaceWinOrders(t *testing.T) {
+	raffle := &entities.Raffle{Model: gorm.Model{ID: 7}, ProductID: 1, Price: money.New(129000, "THB"), PaymentMinutes: 60}
+
+	t.Run("place an order for every win", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		order := &entities.PlacedOrder{ID: 40, ReferenceID: "a1b2", Total: money.New(129000, "THB")}
+
+		mockRepo.On("GetUnorderedWins", raffleBatch).Return([]entities.RaffleEntry{{ID: 30, RaffleID: 7, UserID: 12}}, nil)
+		mockRepo.On("GetRaffle", uint(7)).Return(raffle, nil)
+		mockOrders.On("PlaceOrder", uint(12), uint(1), (*uint)(nil), money.New(129000, "THB")).Return(order, nil)
+		mockRepo.On("AttachOrder", uint(30), order, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+		got, err := raffleService.PlaceWinOrders()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("cancel the order of a win passed on meanwhile", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		order := &entities.PlacedOrder{ID: 40, ReferenceID: "a1b2", Total: money.New(129000, "THB")}
+
+		mockRepo.On("GetUnorderedWins", raffleBatch).Return([]entities.RaffleEntry{{ID: 30, RaffleID: 7, UserID: 12}}, nil)
+		mockRepo.On("GetRaffle", uint(7)).Return(raffle, nil)
+		mockOrders.On("PlaceOrder", uint(12), uint(1), (*uint)(nil), money.New(129000, "THB")).Return(order, nil)
+		mockRepo.On("AttachOrder", uint(30), order, mock.Anything).Return(false, nil)
+		mockOrders.On("CancelOrder", uint(12), "a1b2").Return(nil)
+
+		got, err := raffleService.PlaceWinOrders()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, got)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("keep placing after an order fails", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		order := &entities.PlacedOrder{ID: 41, ReferenceID: "c3d4", Total: money.New(129000, "THB")}
+
+		mockRepo.On("GetUnorderedWins", raffleBatch).Return([]entities.RaffleEntry{{ID: 30, RaffleID: 7, UserID: 12}, {ID: 31, RaffleID: 7, UserID: 13}}, nil)
+		mockRepo.On("GetRaffle", uint(7)).Return(raffle, nil).Once()
+		mockOrders.On("PlaceOrder", uint(12), uint(1), (*uint)(nil), money.New(129000, "THB")).Return((*entities.PlacedOrder)(nil), errors.New("database error"))
+		mockOrders.On("PlaceOrder", uint(13), uint(1), (*uint)(nil), money.New(129000, "THB")).Return(order, nil)
+		mockRepo.On("AttachOrder", uint(31), order, mock.Anything).Return(true, nil)
+
+		got, err := raffleService.PlaceWinOrders()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+}
+
+func TestPassOnLapsedWins(t *testing.T) {
+	t.Run("pass on every lapsed win and cancel its order", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		mockRepo.On("GetLapsedWins", mock.Anything, raffleBatch).Return([]entities.RaffleEntry{{ID: 30}, {ID: 31}}, nil)
+		mockRepo.On("PassOnWin", uint(30), mock.Anything).Return(&entities.RaffleEntry{ID: 30, UserID: 12, ReferenceID: "a1b2"}, nil)
+		mockRepo.On("PassOnWin", uint(31), mock.Anything).Return(&entities.RaffleEntry{ID: 31, UserID: 13, ReferenceID: "c3d4"}, nil)
+		mockOrders.On("CancelOrder", uint(12), "a1b2").Return(nil)
+		mockOrders.On("CancelOrder", uint(13), "c3d4").Return(nil)
+
+		got, err := raffleService.PassOnLapsedWins()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got)
+		mockOrders.AssertExpectations(t)
+	})
+
+	t.Run("keep passing on after a win fails", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		mockOrders := new(MockOrders)
+		raffleService := RaffleService{repo: mockRepo, orders: mockOrders}
+
+		mockRepo.On("GetLapsedWins", mock.Anything, raffleBatch).Return([]entities.RaffleEntry{{ID: 30}, {ID: 31}}, nil)
+		mockRepo.On("PassOnWin", uint(30), mock.Anything).Return((*entities.RaffleEntry)(nil), errors.New("connection reset"))
+		mockRepo.On("PassOnWin", uint(31), mock.Anything).Return(&entities.RaffleEntry{ID: 31, UserID: 13, ReferenceID: "c3d4"}, nil)
+		mockOrders.On("CancelOrder", uint(13), "c3d4").Return(nil)
+
+		got, err := raffleService.PassOnLapsedWins()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("pass on given database error", func(t *testing.T) {
+		mockRepo := new(MockRaffleRepository)
+		raffleService := RaffleService{repo: mockRepo}
+
+		mockRepo.On("GetLapsedWins", mock.Anything, raffleBatch).Return(([]entities.RaffleEntry)(nil), errors.New("connection reset"))
+
+		_, err := raffleService.PassOnLapsedWins()
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+type MockRaffleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRaffleRepository) InsertRaffle(raffle *entities.Raffle) (*entities.Raffle, error) {
+	args := m.Called(raffle)
+	return args.Get(0).(*entities.Raffle), args.Error(1)
+}
+
+func (m *MockRaffleRepository) GetRaffle(id uint) (*entities.Raffle, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Raffle), args.Error(1)
+}
+
+func (m *MockRaffleRepository) InsertEntry(entry *entities.RaffleEntry) (*entities.RaffleEntry, error) {
+	args := m.Called(entry)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleRepository) GetEntry(raffleID, userID uint) (*entities.RaffleEntry, error) {
+	args := m.Called(raffleID, userID)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleRepository) GetRafflesToDraw(now time.Time, limit int) ([]entities.Raffle, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]entities.Raffle), args.Error(1)
+}
+
+func (m *MockRaffleRepository) DrawRaffle(id uint, now time.Time) (bool, error) {
+	args := m.Called(id, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRaffleRepository) GetLapsedWins(now time.Time, limit int) ([]entities.RaffleEntry, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleRepository) GetUnorderedWins(limit int) ([]entities.RaffleEntry, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleRepository) AttachOrder(entryID uint, order *entities.PlacedOrder, dueAt time.Time) (bool, error) {
+	args := m.Called(entryID, order, dueAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRaffleRepository) PassOnWin(entryID uint, now time.Time) (*entities.RaffleEntry, error) {
+	args := m.Called(entryID, now)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+func (m *MockRaffleRepository) PayEntry(referenceID string, amount money.Money, now time.Time) (*entities.RaffleEntry, error) {
+	args := m.Called(referenceID, amount, now)
+	return args.Get(0).(*entities.RaffleEntry), args.Error(1)
+}
+
+type MockAccounts struct {
+	mock.Mock
+}
+
+func (m *MockAccounts) GetAccount(userID uint) (*entities.Account, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*entities.Account), args.Error(1)
+}
+
+type MockOrders struct {
+	mock.Mock
+}
+
+func (m *MockOrders) PlaceOrder(userID, productID uint, variantID *uint, price money.Money) (*entities.PlacedOrder, error) {
+	args := m.Called(userID, productID, variantID, price)
+	return args.Get(0).(*entities.PlacedOrder), args.Error(1)
+}
+
+func (m *MockOrders) SettleOrder(referenceID string, amount money.Money) error {
+	args := m.Called(referenceID, amount)
+	return args.Error(0)
+}
+
+func (m *MockOrders) CancelOrder(userID uint, referenceID string) error {
+	args := m.Called(userID, referenceID)
+	return args.Error(0)
+}
@@ -1,12 +1,207 @@
 package usecase
 
-import "github.com/phetployst/art-toys-store/modules/product/entities"
+import (
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
 
 type ProductRepository interface {
 	InsertProduct(product *entities.Product) (*entities.Product, error)
 	GetAllProduct() ([]entities.Product, error)
 	GetProductById(id string) (*entities.Product, error)
 	UpdateProduct(product *entities.Product, id string) (*entities.Product, error)
-	UpdateStock(id string, count int) (int, error)
-	SearchProducts(keyword string) ([]entities.Product, error)
+	UpdateStock(id string, variantID uint, count int, referenceID string) (int, error)
+	GetProductsByFilter(filter *entities.ProductFilter) ([]entities.Product, error)
+}
+
+type InventoryRepository interface {
+	RecordMovement(movement *entities.StockMovement) (*entities.StockMovement, error)
+	GetMovements(productID uint, filter *entities.StockMovementFilter) ([]entities.StockMovement, error)
+	GetStockDrift() ([]entities.StockDrift, error)
+	UpdateStockRules(product *entities.Product) error
+	GetLowStockProducts() ([]entities.LowStockProduct, error)
+	GetPendingStockAlerts(limit int) ([]entities.StockAlert, error)
+	MarkStockAlertsNotified(ids []uint, notifiedAt time.Time) error
+}
+
+type StockSubscriptionRepository interface {
+	InsertSubscription(subscription *entities.StockSubscription) (*entities.StockSubscription, error)
+	GetSubscription(userID, productID uint, variantID *uint) (*entities.StockSubscription, error)
+	GetSubscriptionsByUser(userID uint) ([]entities.StockSubscription, error)
+	DeleteSubscription(userID uint, id string) error
+	GetPendingNotifications(limit int) ([]entities.StockNotification, error)
+	MarkNotificationsSent(ids []uint, sentAt time.Time) error
+}
+
+type DropRepository interface {
+	UpdateDrop(product *entities.Product) error
+	UpdateDropWindows(now time.Time) (int64, error)
+	CheckPurchase(productID, userID uint, quantity int) error
+}
+
+type QueueRepository interface {
+	SaveWaitingRoom(room *entities.WaitingRoom) (*entities.WaitingRoom, error)
+	GetWaitingRoom(productID uint) (*entities.WaitingRoom, error)
+	GetActiveWaitingRooms() ([]entities.WaitingRoom, error)
+	JoinQueue(productID, userID uint) (*entities.QueueTicket, error)
+	GetTicket(id uint) (*entities.QueueTicket, error)
+	CountAhead(ticket *entities.QueueTicket) (int64, error)
+	AdmitTickets(productID uint, limit int, admittedAt, expiresAt time.Time) (int64, error)
+}
+
+type RaffleRepository interface {
+	InsertRaffle(raffle *entities.Raffle) (*entities.Raffle, error)
+	GetRaffle(id uint) (*entities.Raffle, error)
+	InsertEntry(entry *entities.RaffleEntry) (*entities.RaffleEntry, error)
+	GetEntry(raffleID, userID uint) (*entities.RaffleEntry, error)
+	GetRafflesToDraw(now time.Time, limit int) ([]entities.Raffle, error)
+	DrawRaffle(id uint, now time.Time) (bool, error)
+	GetUnorderedWins(limit int) ([]entities.RaffleEntry, error)
+	AttachOrder(entryID uint, order *entities.PlacedOrder, dueAt time.Time) (bool, error)
+	GetLapsedWins(now time.Time, limit int) ([]entities.RaffleEntry, error)
+	PassOnWin(entryID uint, now time.Time) (*entities.RaffleEntry, error)
+	PayEntry(referenceID string, amount money.Money, now time.Time) (*entities.RaffleEntry, error)
+}
+
+type PricingRepository interface {
+	UpdateSale(product *entities.Product, actorID uint) error
+	GetPriceHistory(productID uint) ([]entities.PriceChange, error)
+	InsertScheduledPrice(scheduled *entities.ScheduledPrice) (*entities.ScheduledPrice, error)
+	GetScheduledPrices(productID uint) ([]entities.ScheduledPrice, error)
+	DeleteScheduledPrice(productID uint, id string) error
+	GetDueScheduledPrices(now time.Time, limit int) ([]entities.ScheduledPrice, error)
+	ApplyScheduledPrice(id uint, now time.Time) (bool, error)
+}
+
+type PreOrderRepository interface {
+	SaveOffer(offer *entities.PreOrderOffer) (*entities.PreOrderOffer, error)
+	GetOffer(productID uint) (*entities.PreOrderOffer, error)
+	InsertLine(line *entities.PreOrderLine) (*entities.PreOrderLine, error)
+	GetLinesByUser(userID uint) ([]entities.PreOrderLine, error)
+	ReleaseLines(productID uint, limit int, releasedAt time.Time) ([]entities.PreOrderLine, error)
+	PayLine(referenceID string, amount money.Money, now time.Time) (*entities.PreOrderLine, error)
+}
+
+type ExchangeRateRepository interface {
+	SaveRates(rates []entities.ExchangeRate) error
+	GetRates() ([]entities.ExchangeRate, error)
+	GetRate(currency string) (*entities.ExchangeRate, error)
+	DeleteRate(currency string) error
+}
+
+type WarehouseRepository interface {
+	InsertWarehouse(warehouse *entities.Warehouse) (*entities.Warehouse, error)
+	GetAllWarehouses() ([]entities.Warehouse, error)
+	GetWarehouseById(id string) (*entities.Warehouse, error)
+	GetWarehouseByCode(code string) (*entities.Warehouse, error)
+	UpdateWarehouse(warehouse *entities.Warehouse) (*entities.Warehouse, error)
+	GetStockLevels(productID uint) ([]entities.WarehouseStockLevel, error)
+	TransferStock(transfer *entities.StockTransfer) ([]entities.StockMovement, error)
+}
+
+type ReservationRepository interface {
+	SaveReservation(reservation *entities.StockReservation) (*entities.StockReservation, error)
+	GetReservations(holderID string) ([]entities.StockReservation, error)
+	ExtendReservations(holderID string, expiresAt time.Time) (int64, error)
+	ReleaseReservations(holderID string) error
+	HoldOrder(cartHolderID string, reservations []entities.StockReservation) ([]entities.StockReservation, error)
+	ConvertReservations(holderID string, checkout *entities.Checkout) ([]entities.StockReservation, error)
+	ReleaseExpiredReservations(now time.Time, limit int) (int, error)
+}
+
+type RiskRepository interface {
+	UpdatePurchaseLimits(product *entities.Product) error
+	InsertRiskRule(rule *entities.RiskRule) (*entities.RiskRule, error)
+	GetRiskRules(activeOnly bool) ([]entities.RiskRule, error)
+	DeleteRiskRule(id string) error
+	GetOrderReviews(status string) ([]entities.OrderReview, error)
+	HoldOrderReview(review *entities.OrderReview) (*entities.OrderReview, error)
+	ApproveOrderReview(referenceID string, reviewerID uint, reviewedAt, expiresAt time.Time) (*entities.OrderReview, error)
+	RejectOrderReview(referenceID string, reviewerID uint, reviewedAt time.Time) (*entities.OrderReview, error)
+}
+
+type ProductSearchRepository interface {
+	SearchProducts(query *entities.SearchQuery) ([]entities.SearchHit, int64, error)
+	SearchFacets(query *entities.SearchQuery) (*entities.SearchFacets, error)
+	Autocomplete(query string, limit int) ([]entities.Suggestion, error)
+	ClosestWord(term string) (string, error)
+}
+
+type ProductImageRepository interface {
+	InsertProductImage(image *entities.ProductImage) (*entities.ProductImage, error)
+	GetProductImages(productID uint) ([]entities.ProductImage, error)
+	GetProductImageById(productID uint, imageID string) (*entities.ProductImage, error)
+	UpdateProductImage(image *entities.ProductImage) (*entities.ProductImage, error)
+	SetPrimaryProductImage(productID uint, imageID uint) error
+	ReorderProductImages(productID uint, imageIDs []uint) error
+	DeleteProductImage(image *entities.ProductImage) error
+}
+
+type ProductVariantRepository interface {
+	InsertVariant(variant *entities.ProductVariant) (*entities.ProductVariant, error)
+	GetVariants(productID uint) ([]entities.ProductVariant, error)
+	GetVariantById(productID uint, variantID string) (*entities.ProductVariant, error)
+	GetVariantBySKU(sku string) (*entities.ProductVariant, error)
+	UpdateVariant(variant *entities.ProductVariant) (*entities.ProductVariant, error)
+	DeleteVariant(variant *entities.ProductVariant) error
+}
+
+type BlindBoxRepository interface {
+	InsertFigure(figure *entities.BlindBoxFigure) (*entities.BlindBoxFigure, error)
+	GetFigures(boxID uint) ([]entities.BlindBoxFigure, error)
+	GetFigureById(boxID uint, figureID string) (*entities.BlindBoxFigure, error)
+	UpdateFigure(figure *entities.BlindBoxFigure) (*entities.BlindBoxFigure, error)
+	DeleteFigure(figure *entities.BlindBoxFigure) error
+	InsertSeed(seed *entities.BlindBoxSeed) (*entities.BlindBoxSeed, error)
+	GetSeeds(boxID uint) ([]entities.BlindBoxSeed, error)
+	RotateSeed(boxID uint, next *entities.BlindBoxSeed) (*entities.BlindBoxSeed, error)
+	SaveCase(blindBoxCase *entities.BlindBoxCase) (*entities.BlindBoxCase, error)
+	GetCase(productID uint) (*entities.BlindBoxCase, error)
+	DrawOrder(boxIDs []uint, cases []entities.BlindBoxCase, clientSeed, referenceID string) (*entities.BlindBoxOrderDraw, error)
+	ReturnDraws(referenceID string, now time.Time) error
+}
+
+type TaxonomyRepository interface {
+	InsertCategory(category *entities.Category) (*entities.Category, error)
+	GetAllCategories() ([]entities.Category, error)
+	GetCategoryById(id string) (*entities.Category, error)
+	UpdateCategory(category *entities.Category, id string) (*entities.Category, error)
+	DeleteCategory(id string) error
+	CountChildCategories(id uint) (int64, error)
+	InsertSeries(series *entities.Series) (*entities.Series, error)
+	GetAllSeries() ([]entities.Series, error)
+	GetSeriesById(id string) (*entities.Series, error)
+	UpdateSeries(series *entities.Series, id string) (*entities.Series, error)
+	DeleteSeries(id string) error
+	ReplaceProductCategories(productID uint, categoryIDs []uint) error
+}
+
+type CatalogueRepository interface {
+	InsertArtist(artist *entities.Artist) (*entities.Artist, error)
+	GetAllArtists() ([]entities.Artist, error)
+	GetArtistById(id string) (*entities.Artist, error)
+	UpdateArtist(artist *entities.Artist, id string) (*entities.Artist, error)
+	DeleteArtist(id string) error
+	InsertBrand(brand *entities.Brand) (*entities.Brand, error)
+	GetAllBrands() ([]entities.Brand, error)
+	GetBrandById(id string) (*entities.Brand, error)
+	UpdateBrand(brand *entities.Brand, id string) (*entities.Brand, error)
+	DeleteBrand(id string) error
+}
+
+type PromotionRepository interface {
+	InsertCoupon(coupon *entities.Coupon) (*entities.Coupon, error)
+	GetCoupons() ([]entities.Coupon, error)
+	GetCouponById(id string) (*entities.Coupon, error)
+	GetCouponByCode(code string) (*entities.Coupon, error)
+	UpdateCoupon(coupon *entities.Coupon) (*entities.Coupon, error)
+	DeleteCoupon(id string) error
+	CountRedemptions(couponID, userID uint) (int64, error)
+	RedeemCoupons(redemptions []entities.CouponRedemption) error
+	ReleaseCoupons(referenceID string) error
+	InsertCartPromotion(promotion *entities.CartPromotion) (*entities.CartPromotion, error)
+	GetCartPromotions(activeOnly bool) ([]entities.CartPromotion, error)
+	DeleteCartPromotion(id string) error
 }
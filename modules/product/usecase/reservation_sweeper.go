@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReservationSweeper releases expired reservations in the background so held
+// stock goes back on sale even if the holder never comes back.
+type ReservationSweeper struct {
+	usecase  ReservationUsecase
+	interval time.Duration
+}
+
+func NewReservationSweeper(usecase ReservationUsecase, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{usecase, interval}
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+func (s *ReservationSweeper) sweep() {
+	released, err := s.usecase.ReleaseExpired()
+	if err != nil {
+		log.Printf("failed to release expired reservations: %v", err)
+	}
+
+	if released > 0 {
+		log.Printf("released %d expired reservations", released)
+	}
+}
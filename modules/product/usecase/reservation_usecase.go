@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+const (
+	reservationTTL        = 15 * time.Minute
+	reservationSweepBatch = 100
+)
+
+type ReservationUsecase interface {
+	Reserve(request *entities.ReservationRequest) (*entities.StockReservation, error)
+	GetReservations(holderID string) ([]entities.StockReservation, error)
+	ExtendReservations(holderID string) ([]entities.StockReservation, error)
+	ReleaseReservations(holderID string) error
+	HoldOrder(request *entities.OrderHoldRequest) ([]entities.StockReservation, error)
+	ConvertReservations(request *entities.ReservationConvertRequest) ([]entities.StockReservation, error)
+	ReleaseExpired() (int, error)
+}
+
+type ReservationService struct {
+	repo        ReservationRepository
+	productRepo ProductRepository
+	accounts    Accounts
+}
+
+func NewReservationService(repo ReservationRepository, productRepo ProductRepository, accounts Accounts) ReservationUsecase {
+	return &ReservationService{repo, productRepo, accounts}
+}
+
+// Reserve sets the holder's reservation for an item and restarts its TTL.
+// When the product limits what one address may buy, the user's profile
+// address counts towards it from the cart on.
+func (s *ReservationService) Reserve(request *entities.ReservationRequest) (*entities.StockReservation, error) {
+	product, err := s.getProduct(strconv.FormatUint(uint64(request.ProductID), 10))
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+		return nil, errors.New("blind box stock is set by its figures")
+	}
+
+	addressKey, err := s.profileAddressKey(product, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.SaveReservation(&entities.StockReservation{
+		HolderID:   request.HolderID,
+		UserID:     optionalID(request.UserID),
+		ProductID:  product.ID,
+		VariantID:  optionalID(request.VariantID),
+		Quantity:   request.Quantity,
+		ExpiresAt:  time.Now().Add(reservationTTL),
+		AddressKey: addressKey,
+	})
+	if err != nil {
+		return nil, reserveError(err)
+	}
+
+	return reservation, nil
+}
+
+// HoldOrder moves the user's cart reservations for the order's items onto the
+// order until its payment deadline, checking stock and limits again for each.
+// Blind boxes and cases are drawn rather than reserved, so they are skipped.
+func (s *ReservationService) HoldOrder(request *entities.OrderHoldRequest) ([]entities.StockReservation, error) {
+	var reservations []entities.StockReservation
+	for _, item := range request.Items {
+		product, err := s.getProduct(strconv.FormatUint(uint64(item.ProductID), 10))
+		if err != nil {
+			return nil, err
+		}
+
+		if product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+			continue
+		}
+
+		addressKey, err := s.profileAddressKey(product, request.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, entities.StockReservation{
+			HolderID:   request.ReferenceID,
+			UserID:     optionalID(request.UserID),
+			ProductID:  product.ID,
+			VariantID:  optionalID(item.VariantID),
+			Quantity:   item.Quantity,
+			ExpiresAt:  request.ExpiresAt,
+			AddressKey: addressKey,
+		})
+	}
+
+	if len(reservations) == 0 {
+		return []entities.StockReservation{}, nil
+	}
+
+	held, err := s.repo.HoldOrder(entities.CartHolderID(request.UserID), reservations)
+	if err != nil {
+		return nil, reserveError(err)
+	}
+
+	return held, nil
+}
+
+// profileAddressKey is the key of the user's profile address when the product
+// limits what one address may buy, so the limit applies from the cart on.
+func (s *ReservationService) profileAddressKey(product *entities.Product, userID uint) (string, error) {
+	if product.AddressPurchaseLimit == 0 || userID == 0 {
+		return "", nil
+	}
+
+	account, err := s.accounts.GetAccount(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return "", err
+		}
+		return "", errors.New("database error")
+	}
+
+	return purchaseKey(account.ShippingAddress), nil
+}
+
+func reserveError(err error) error {
+	switch err.Error() {
+	case "product not found", "variant not found", "variant required", "insufficient stock", "reservation not found",
+		"product is not on sale", "purchase limit exceeded", "sign in required":
+		return err
+	}
+
+	return errors.New("database error")
+}
+
+func (s *ReservationService) GetReservations(holderID string) ([]entities.StockReservation, error) {
+	reservations, err := s.repo.GetReservations(holderID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return reservations, nil
+}
+
+// ExtendReservations restarts the TTL of all the holder's reservations, e.g.
+// whenever the cart or checkout sees activity.
+func (s *ReservationService) ExtendReservations(holderID string) ([]entities.StockReservation, error) {
+	extended, err := s.repo.ExtendReservations(holderID, time.Now().Add(reservationTTL))
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	if extended == 0 {
+		return nil, errors.New("reservation not found")
+	}
+
+	return s.GetReservations(holderID)
+}
+
+func (s *ReservationService) ReleaseReservations(holderID string) error {
+	if err := s.repo.ReleaseReservations(holderID); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+// ConvertReservations turns the reservations held for the order referenced by
+// the request into a deduction once it is paid. The order was run past the
+// risk rules when it was checked out.
+func (s *ReservationService) ConvertReservations(request *entities.ReservationConvertRequest) ([]entities.StockReservation, error) {
+	checkout := &entities.Checkout{
+		ReferenceID:   request.ReferenceID,
+		AddressKey:    purchaseKey(request.ShippingAddress),
+		InstrumentKey: purchaseKey(request.PaymentFingerprint),
+	}
+
+	converted, err := s.repo.ConvertReservations(request.ReferenceID, checkout)
+	if err != nil {
+		switch err.Error() {
+		case "reservation not found", "product is not on sale", "purchase limit exceeded",
+			"shipping address required", "payment instrument required":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return converted, nil
+}
+
+// ReleaseExpired releases every reservation that has expired, in batches, and
+// returns how many were released.
+func (s *ReservationService) ReleaseExpired() (int, error) {
+	total := 0
+
+	for {
+		released, err := s.repo.ReleaseExpiredReservations(time.Now(), reservationSweepBatch)
+		total += released
+		if err != nil {
+			return total, errors.New("database error")
+		}
+
+		if released < reservationSweepBatch {
+			return total, nil
+		}
+	}
+}
+
+func (s *ReservationService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestReserve(t *testing.T) {
+	t.Run("reserve item with a fresh expiry", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		variantID := uint(3)
+		before := time.Now()
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("SaveReservation", mock.MatchedBy(func(r *entities.StockReservation) bool {
+			return r.HolderID == "cart-abc" && r.ProductID == 1 && *r.VariantID == 3 && r.Quantity == 2 &&
+				!r.ExpiresAt.Before(before.Add(reservationTTL))
+		})).Return(&entities.StockReservation{Model: gorm.Model{ID: 4}, HolderID: "cart-abc", ProductID: 1, VariantID: &variantID, Quantity: 2, Status: "active"}, nil)
+
+		got, err := reservationService.Reserve(&entities.ReservationRequest{HolderID: "cart-abc", ProductID: 1, VariantID: 3, Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), got.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reserve given blind box", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(&entities.Product{Model: gorm.Model{ID: 20}, Type: "blind_box"}, nil)
+
+		_, err := reservationService.Reserve(&entities.ReservationRequest{HolderID: "cart-abc", ProductID: 20, Quantity: 1})
+
+		assert.EqualError(t, err, "blind box stock is set by its figures")
+		mockRepo.AssertNotCalled(t, "SaveReservation", mock.Anything)
+	})
+
+	t.Run("reserve given product not found", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := reservationService.Reserve(&entities.ReservationRequest{HolderID: "cart-abc", ProductID: 99, Quantity: 1})
+
+		assert.EqualError(t, err, "product not found")
+	})
+
+	t.Run("reserve given insufficient stock", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("SaveReservation", mock.Anything).Return((*entities.StockReservation)(nil), errors.New("insufficient stock"))
+
+		_, err := reservationService.Reserve(&entities.ReservationRequest{HolderID: "cart-abc", ProductID: 1, Quantity: 5})
+
+		assert.EqualError(t, err, "insufficient stock")
+	})
+
+	t.Run("reserve given database error", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("SaveReservation", mock.Anything).Return((*entities.StockReservation)(nil), errors.New("failed to record stock movement"))
+
+		_, err := reservationService.Reserve(&entities.ReservationRequest{HolderID: "cart-abc", ProductID: 1, Quantity: 1})
+
+		assert.EqualError(t, err, "database error")
+	})
+
+	t.Run("reserve with the profile address keyed given a per-address limit", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockAccounts := new(MockAccounts)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo, accounts: mockAccounts}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard", AddressPurchaseLimit: 2}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12, ShippingAddress: "1 Main St, Bangkok"}, nil)
+		mockRepo.On("SaveReservation", mock.MatchedBy(func(r *entities.StockReservation) bool {
+			return r.AddressKey == purchaseKey("1 Main St, Bangkok")
+		})).Return((*entities.StockReservation)(nil), errors.New("purchase limit exceeded"))
+
+		_, err := reservationService.Reserve(&entities.ReservationRequest{UserID: 12, HolderID: "user:12", ProductID: 1, Quantity: 3})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+	})
+}
+
+func TestHoldOrder(t *testing.T) {
+	t.Run("hold the order's reserved items until its deadline", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		userID, variantID := uint(12), uint(3)
+		expiresAt := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+		held := []entities.StockReservation{{Model: gorm.Model{ID: 4}, HolderID: "ORD-1001", ProductID: 1, VariantID: &variantID, Quantity: 2, Status: "active"}}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockProductRepo.On("GetProductById", "20").Return(&entities.Product{Model: gorm.Model{ID: 20}, Type: "blind_box"}, nil)
+		mockRepo.On("HoldOrder", "user:12", []entities.StockReservation{
+			{HolderID: "ORD-1001", UserID: &userID, ProductID: 1, VariantID: &variantID, Quantity: 2, ExpiresAt: expiresAt},
+		}).Return(held, nil)
+
+		got, err := reservationService.HoldOrder(&entities.OrderHoldRequest{
+			UserID:      12,
+			ReferenceID: "ORD-1001",
+			Items:       []entities.OrderHoldItem{{ProductID: 1, VariantID: 3, Quantity: 2}, {ProductID: 20, Quantity: 1}},
+			ExpiresAt:   expiresAt,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, held, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("hold given only blind boxes", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(&entities.Product{Model: gorm.Model{ID: 20}, Type: "blind_box"}, nil)
+
+		got, err := reservationService.HoldOrder(&entities.OrderHoldRequest{UserID: 12, ReferenceID: "ORD-1001", Items: []entities.OrderHoldItem{{ProductID: 20, Quantity: 1}}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+		mockRepo.AssertNotCalled(t, "HoldOrder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("hold given insufficient stock", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		mockProductRepo := new(MockProductRepository)
+		reservationService := ReservationService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("HoldOrder", "user:12", mock.Anything).Return(([]entities.StockReservation)(nil), errors.New("insufficient stock"))
+
+		_, err := reservationService.HoldOrder(&entities.OrderHoldRequest{UserID: 12, ReferenceID: "ORD-1001", Items: []entities.OrderHoldItem{{ProductID: 1, Quantity: 2}}})
+
+		assert.EqualError(t, err, "insufficient stock")
+	})
+}
+
+func TestExtendReservations(t *testing.T) {
+	t.Run("extend reservations successfully", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		reservations := []entities.StockReservation{{Model: gorm.Model{ID: 4}, HolderID: "cart-abc", ProductID: 1, Quantity: 2, Status: "active"}}
+
+		mockRepo.On("ExtendReservations", "cart-abc", mock.Anything).Return(int64(1), nil)
+		mockRepo.On("GetReservations", "cart-abc").Return(reservations, nil)
+
+		got, err := reservationService.ExtendReservations("cart-abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, reservations, got)
+	})
+
+	t.Run("extend given no active reservations", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		mockRepo.On("ExtendReservations", "cart-abc", mock.Anything).Return(int64(0), nil)
+
+		_, err := reservationService.ExtendReservations("cart-abc")
+
+		assert.EqualError(t, err, "reservation not found")
+		mockRepo.AssertNotCalled(t, "GetReservations", mock.Anything)
+	})
+}
+
+func TestConvertReservations(t *testing.T) {
+	t.Run("convert reservations successfully", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		converted := []entities.StockReservation{{Model: gorm.Model{ID: 4}, HolderID: "ORD-1001", ProductID: 1, Quantity: 2, Status: "converted"}}
+		mockRepo.On("ConvertReservations", "ORD-1001", &entities.Checkout{ReferenceID: "ORD-1001"}).Return(converted, nil)
+
+		got, err := reservationService.ConvertReservations(&entities.ReservationConvertRequest{ReferenceID: "ORD-1001"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, converted, got)
+	})
+
+	t.Run("convert with the address and instrument keyed", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		mockRepo.On("ConvertReservations", "ORD-1001", &entities.Checkout{
+			ReferenceID:   "ORD-1001",
+			AddressKey:    purchaseKey("1 Main St, Bangkok"),
+			InstrumentKey: purchaseKey("fp_123"),
+		}).Return(([]entities.StockReservation)(nil), errors.New("purchase limit exceeded"))
+
+		_, err := reservationService.ConvertReservations(&entities.ReservationConvertRequest{
+			ReferenceID:        "ORD-1001",
+			ShippingAddress:    "  1 MAIN St,   Bangkok ",
+			PaymentFingerprint: "fp_123",
+		})
+
+		assert.EqualError(t, err, "purchase limit exceeded")
+	})
+
+	t.Run("convert given expired reservations", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		mockRepo.On("ConvertReservations", "ORD-1001", mock.Anything).Return(([]entities.StockReservation)(nil), errors.New("reservation not found"))
+
+		_, err := reservationService.ConvertReservations(&entities.ReservationConvertRequest{ReferenceID: "ORD-1001"})
+
+		assert.EqualError(t, err, "reservation not found")
+	})
+}
+
+func TestReleaseExpired(t *testing.T) {
+	t.Run("release expired reservations until a short batch", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		mockRepo.On("ReleaseExpiredReservations", mock.Anything, reservationSweepBatch).Return(reservationSweepBatch, nil).Once()
+		mockRepo.On("ReleaseExpiredReservations", mock.Anything, reservationSweepBatch).Return(7, nil).Once()
+
+		got, err := reservationService.ReleaseExpired()
+
+		assert.NoError(t, err)
+		assert.Equal(t, reservationSweepBatch+7, got)
+		mockRepo.AssertNumberOfCalls(t, "ReleaseExpiredReservations", 2)
+	})
+
+	t.Run("release expired given database error", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		reservationService := ReservationService{repo: mockRepo}
+
+		mockRepo.On("ReleaseExpiredReservations", mock.Anything, reservationSweepBatch).Return(3, errors.New("connection reset"))
+
+		got, err := reservationService.ReleaseExpired()
+
+		assert.EqualError(t, err, "database error")
+		assert.Equal(t, 3, got)
+	})
+}
+
+func TestReservationSweeper(t *testing.T) {
+	t.Run("sweep on every tick until cancelled", func(t *testing.T) {
+		mockRepo := new(MockReservationRepository)
+		sweeper := NewReservationSweeper(&ReservationService{repo: mockRepo}, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		mockRepo.On("ReleaseExpiredReservations", mock.Anything, reservationSweepBatch).Return(0, nil).Run(func(mock.Arguments) {
+			cancel()
+		})
+
+		done := make(chan struct{})
+		go func() {
+			sweeper.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("sweeper did not stop after cancel")
+		}
+		mockRepo.AssertCalled(t, "ReleaseExpiredReservations", mock.Anything, reservationSweepBatch)
+	})
+}
+
+type MockReservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReservationRepository) SaveReservation(reservation *entities.StockReservation) (*entities.StockReservation, error) {
+	args := m.Called(reservation)
+	return args.Get(0).(*entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) GetReservations(holderID string) ([]entities.StockReservation, error) {
+	args := m.Called(holderID)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ExtendReservations(holderID string, expiresAt time.Time) (int64, error) {
+	args := m.Called(holderID, expiresAt)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReservationRepository) ReleaseReservations(holderID string) error {
+	args := m.Called(holderID)
+	return args.Error(0)
+}
+
+func (m *MockReservationRepository) HoldOrder(cartHolderID string, reservations []entities.StockReservation) ([]entities.StockReservation, error) {
+	args := m.Called(cartHolderID, reservations)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ConvertReservations(holderID string, checkout *entities.Checkout) ([]entities.StockReservation, error) {
+	args := m.Called(holderID, checkout)
+	return args.Get(0).([]entities.StockReservation), args.Error(1)
+}
+
+func (m *MockReservationRepository) ReleaseExpiredReservations(now time.Time, limit int) (int, error) {
+	args := m.Called(now, limit)
+	return args.Int(0), args.Error(1)
+}
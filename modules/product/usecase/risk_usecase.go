@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+type RiskUsecase interface {
+	SetPurchaseLimits(productID string, request *entities.PurchaseLimitsRequest) (*entities.PurchaseLimits, error)
+	CreateRiskRule(rule *entities.RiskRule) (*entities.RiskRule, error)
+	GetRiskRules() ([]entities.RiskRule, error)
+	DeleteRiskRule(id string) error
+	GetOrderReviews(filter *entities.OrderReviewFilter) ([]entities.OrderReview, error)
+	ScreenOrder(request *entities.OrderScreenRequest) (*entities.OrderReview, error)
+	ApproveOrderReview(referenceID string, reviewerID uint, expiresAt time.Time) (*entities.OrderReview, error)
+	RejectOrderReview(referenceID string, reviewerID uint) (*entities.OrderReview, error)
+}
+
+type RiskService struct {
+	repo        RiskRepository
+	productRepo ProductRepository
+	accounts    Accounts
+}
+
+func NewRiskService(repo RiskRepository, productRepo ProductRepository, accounts Accounts) RiskUsecase {
+	return &RiskService{repo, productRepo, accounts}
+}
+
+func (s *RiskService) SetPurchaseLimits(productID string, request *entities.PurchaseLimitsRequest) (*entities.PurchaseLimits, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.PerUser != nil {
+		product.PurchaseLimit = *request.PerUser
+	}
+	if request.PerAddress != nil {
+		product.AddressPurchaseLimit = *request.PerAddress
+	}
+	if request.PerPaymentInstrument != nil {
+		product.InstrumentPurchaseLimit = *request.PerPaymentInstrument
+	}
+
+	if err := s.repo.UpdatePurchaseLimits(product); err != nil {
+		if err.Error() == "product not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return &entities.PurchaseLimits{
+		ProductID:            product.ID,
+		PerUser:              product.PurchaseLimit,
+		PerAddress:           product.AddressPurchaseLimit,
+		PerPaymentInstrument: product.InstrumentPurchaseLimit,
+	}, nil
+}
+
+func (s *RiskService) CreateRiskRule(rule *entities.RiskRule) (*entities.RiskRule, error) {
+	rule.Active = true
+
+	created, err := s.repo.InsertRiskRule(rule)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return created, nil
+}
+
+func (s *RiskService) GetRiskRules() ([]entities.RiskRule, error) {
+	rules, err := s.repo.GetRiskRules(false)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return rules, nil
+}
+
+func (s *RiskService) DeleteRiskRule(id string) error {
+	if err := s.repo.DeleteRiskRule(id); err != nil {
+		if err.Error() == "risk rule not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *RiskService) GetOrderReviews(filter *entities.OrderReviewFilter) ([]entities.OrderReview, error) {
+	reviews, err := s.repo.GetOrderReviews(filter.Status)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return reviews, nil
+}
+
+// ScreenOrder runs an order being checked out past the active risk rules. An
+// order they reject fails with "order rejected". One they hold has its review
+// filed and the stock reserved for it set aside, and the review is returned;
+// an order they let through has none.
+func (s *RiskService) ScreenOrder(request *entities.OrderScreenRequest) (*entities.OrderReview, error) {
+	rules, err := s.repo.GetRiskRules(true)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	signals, err := s.orderSignals(request)
+	if err != nil {
+		return nil, err
+	}
+
+	action, reasons := evaluateRiskRules(rules, signals)
+	switch action {
+	case entities.RiskActionReject:
+		return nil, errors.New("order rejected")
+	case entities.RiskActionHold:
+		review, err := s.repo.HoldOrderReview(&entities.OrderReview{
+			ReferenceID: request.ReferenceID,
+			UserID:      optionalID(request.UserID),
+			Reasons:     strings.Join(reasons, "; "),
+			Status:      entities.OrderReviewPending,
+		})
+		if err != nil {
+			return nil, errors.New("database error")
+		}
+		return review, nil
+	}
+
+	return nil, nil
+}
+
+// orderSignals gathers the signals the risk rules test. Account signals come
+// from the user's record, so they are missing for a user without one; the
+// order counts come with the request from the order service.
+func (s *RiskService) orderSignals(request *entities.OrderScreenRequest) (map[string]int, error) {
+	signals := map[string]int{
+		entities.RiskSignalQuantity:       request.Quantity,
+		entities.RiskSignalOrdersLastHour: request.OrdersLastHour,
+		entities.RiskSignalOrdersLastDay:  request.OrdersLastDay,
+	}
+
+	account, err := s.accounts.GetAccount(request.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return signals, nil
+		}
+		return nil, errors.New("database error")
+	}
+
+	signals[entities.RiskSignalAccountAgeHours] = int(time.Since(account.CreatedAt).Hours())
+	signals[entities.RiskSignalEmailVerified] = 0
+	if account.EmailVerified {
+		signals[entities.RiskSignalEmailVerified] = 1
+	}
+
+	return signals, nil
+}
+
+// ApproveOrderReview lets an order held for review through. Its stock goes
+// back on hold for it until expiresAt, the order's new payment deadline.
+func (s *RiskService) ApproveOrderReview(referenceID string, reviewerID uint, expiresAt time.Time) (*entities.OrderReview, error) {
+	review, err := s.repo.ApproveOrderReview(referenceID, reviewerID, time.Now(), expiresAt)
+	if err != nil {
+		switch err.Error() {
+		case "order review not found", "order review already settled":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return review, nil
+}
+
+// RejectOrderReview turns an order held for review away and puts its stock
+// back on sale.
+func (s *RiskService) RejectOrderReview(referenceID string, reviewerID uint) (*entities.OrderReview, error) {
+	review, err := s.repo.RejectOrderReview(referenceID, reviewerID, time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "order review not found", "order review already settled":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return review, nil
+}
+
+func (s *RiskService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+// evaluateRiskRules returns the strongest action the rules call for on a
+// checkout and the names of the rules that matched. A checkout without the
+// signal a rule tests fails that rule, so one that cannot be vouched for is
+// never let through unchecked.
+func evaluateRiskRules(rules []entities.RiskRule, signals map[string]int) (string, []string) {
+	action := ""
+	var reasons []string
+
+	for _, rule := range rules {
+		value, ok := signals[rule.Signal]
+		if ok && !compareRiskSignal(value, rule.Operator, rule.Threshold) {
+			continue
+		}
+
+		reasons = append(reasons, rule.Name)
+		if action != entities.RiskActionReject {
+			action = rule.Action
+		}
+	}
+
+	return action, reasons
+}
+
+func compareRiskSignal(value int, operator string, threshold int) bool {
+	switch operator {
+	case "lt":
+		return value < threshold
+	case "lte":
+		return value <= threshold
+	case "gt":
+		return value > threshold
+	case "gte":
+		return value >= threshold
+	case "eq":
+		return value == threshold
+	default:
+		return false
+	}
+}
+
+// purchaseKey hashes a shipping address or payment fingerprint after folding
+// case and whitespace, so the same address typed twice counts as one.
+func purchaseKey(value string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	if normalized == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
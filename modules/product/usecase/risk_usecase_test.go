@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSetPurchaseLimits(t *testing.T) {
+	t.Run("set address limit and keep the rest", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockProductRepo := new(MockProductRepository)
+		riskService := RiskService{repo: mockRepo, productRepo: mockProductRepo}
+
+		perAddress := 2
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, PurchaseLimit: 1}, nil)
+		mockRepo.On("UpdatePurchaseLimits", mock.MatchedBy(func(p *entities.Product) bool {
+			return p.PurchaseLimit == 1 && p.AddressPurchaseLimit == 2 && p.InstrumentPurchaseLimit == 0
+		})).Return(nil)
+
+		got, err := riskService.SetPurchaseLimits("1", &entities.PurchaseLimitsRequest{PerAddress: &perAddress})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.PurchaseLimits{ProductID: 1, PerUser: 1, PerAddress: 2}, got)
+	})
+
+	t.Run("set limits given product not found", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockProductRepo := new(MockProductRepository)
+		riskService := RiskService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := riskService.SetPurchaseLimits("99", &entities.PurchaseLimitsRequest{})
+
+		assert.EqualError(t, err, "product not found")
+	})
+}
+
+func TestEvaluateRiskRules(t *testing.T) {
+	rules := []entities.RiskRule{
+		{Name: "new account", Signal: "account_age_hours", Operator: "lt", Threshold: 24, Action: "hold"},
+		{Name: "unverified", Signal: "email_verified", Operator: "eq", Threshold: 0, Action: "hold"},
+		{Name: "rapid orders", Signal: "orders_last_hour", Operator: "gte", Threshold: 3, Action: "reject"},
+	}
+
+	tests := []struct {
+		name        string
+		signals     map[string]int
+		wantAction  string
+		wantReasons []string
+	}{
+		{"no rule matches", map[string]int{"account_age_hours": 500, "email_verified": 1, "orders_last_hour": 0}, "", nil},
+		{"one hold", map[string]int{"account_age_hours": 2, "email_verified": 1, "orders_last_hour": 0}, "hold", []string{"new account"}},
+		{"reject wins over hold", map[string]int{"account_age_hours": 2, "email_verified": 1, "orders_last_hour": 5}, "reject", []string{"new account", "rapid orders"}},
+		{"missing signals fail their rules", map[string]int{"account_age_hours": 500}, "reject", []string{"unverified", "rapid orders"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, reasons := evaluateRiskRules(rules, tt.signals)
+
+			assert.Equal(t, tt.wantAction, action)
+			assert.Equal(t, tt.wantReasons, reasons)
+		})
+	}
+}
+
+func TestPurchaseKey(t *testing.T) {
+	assert.Equal(t, purchaseKey("1 Main St, Bangkok"), purchaseKey("  1 MAIN st,\tBangkok "))
+	assert.NotEqual(t, purchaseKey("1 Main St, Bangkok"), purchaseKey("2 Main St, Bangkok"))
+	assert.Empty(t, purchaseKey("   "))
+}
+
+func TestScreenOrder(t *testing.T) {
+	t.Run("hold an order from a new account for review", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockAccounts := new(MockAccounts)
+		riskService := RiskService{repo: mockRepo, accounts: mockAccounts}
+
+		mockRepo.On("GetRiskRules", true).Return([]entities.RiskRule{
+			{Name: "new account", Signal: "account_age_hours", Operator: "lt", Threshold: 24, Action: "hold"},
+			{Name: "bulk buyer", Signal: "quantity", Operator: "gt", Threshold: 10, Action: "reject"},
+		}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12, CreatedAt: time.Now().Add(-2 * time.Hour), EmailVerified: true}, nil)
+		mockRepo.On("HoldOrderReview", mock.MatchedBy(func(r *entities.OrderReview) bool {
+			return r.ReferenceID == "ORD-1001" && *r.UserID == 12 && r.Reasons == "new account" && r.Status == "pending"
+		})).Return(&entities.OrderReview{Model: gorm.Model{ID: 3}, ReferenceID: "ORD-1001", Status: "pending"}, nil)
+
+		got, err := riskService.ScreenOrder(&entities.OrderScreenRequest{UserID: 12, ReferenceID: "ORD-1001", Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), got.ID)
+	})
+
+	t.Run("reject an order from an order velocity rule", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockAccounts := new(MockAccounts)
+		riskService := RiskService{repo: mockRepo, accounts: mockAccounts}
+
+		mockRepo.On("GetRiskRules", true).Return([]entities.RiskRule{
+			{Name: "unverified", Signal: "email_verified", Operator: "eq", Threshold: 0, Action: "hold"},
+			{Name: "rapid orders", Signal: "orders_last_hour", Operator: "gte", Threshold: 3, Action: "reject"},
+		}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)}, nil)
+
+		_, err := riskService.ScreenOrder(&entities.OrderScreenRequest{UserID: 12, ReferenceID: "ORD-1001", Quantity: 1, OrdersLastHour: 3, OrdersLastDay: 3})
+
+		assert.EqualError(t, err, "order rejected")
+		mockRepo.AssertNotCalled(t, "HoldOrderReview", mock.Anything)
+	})
+
+	t.Run("hold an order from a user without an account for account rules", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockAccounts := new(MockAccounts)
+		riskService := RiskService{repo: mockRepo, accounts: mockAccounts}
+
+		mockRepo.On("GetRiskRules", true).Return([]entities.RiskRule{
+			{Name: "unverified", Signal: "email_verified", Operator: "eq", Threshold: 0, Action: "hold"},
+		}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return((*entities.Account)(nil), errors.New("user not found"))
+		mockRepo.On("HoldOrderReview", mock.MatchedBy(func(r *entities.OrderReview) bool {
+			return r.Reasons == "unverified"
+		})).Return(&entities.OrderReview{Model: gorm.Model{ID: 3}}, nil)
+
+		got, err := riskService.ScreenOrder(&entities.OrderScreenRequest{UserID: 12, ReferenceID: "ORD-1001", Quantity: 1})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, got)
+	})
+
+	t.Run("let an order through no rule flags", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockAccounts := new(MockAccounts)
+		riskService := RiskService{repo: mockRepo, accounts: mockAccounts}
+
+		mockRepo.On("GetRiskRules", true).Return([]entities.RiskRule{
+			{Name: "bulk buyer", Signal: "quantity", Operator: "gt", Threshold: 10, Action: "reject"},
+		}, nil)
+		mockAccounts.On("GetAccount", uint(12)).Return(&entities.Account{UserID: 12, CreatedAt: time.Now().Add(-30 * 24 * time.Hour), EmailVerified: true}, nil)
+
+		got, err := riskService.ScreenOrder(&entities.OrderScreenRequest{UserID: 12, ReferenceID: "ORD-1001", Quantity: 2})
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+		mockRepo.AssertNotCalled(t, "HoldOrderReview", mock.Anything)
+	})
+}
+
+func TestApproveOrderReview(t *testing.T) {
+	t.Run("approve held order", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		riskService := RiskService{repo: mockRepo}
+
+		expiresAt := time.Date(2026, 11, 1, 10, 30, 0, 0, time.UTC)
+		mockRepo.On("ApproveOrderReview", "ORD-1001", uint(1), mock.AnythingOfType("time.Time"), expiresAt).
+			Return(&entities.OrderReview{Model: gorm.Model{ID: 3}, Status: "approved"}, nil)
+
+		got, err := riskService.ApproveOrderReview("ORD-1001", 1, expiresAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "approved", got.Status)
+	})
+
+	t.Run("approve order that was already settled", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		riskService := RiskService{repo: mockRepo}
+
+		mockRepo.On("ApproveOrderReview", "ORD-1001", uint(1), mock.Anything, mock.Anything).Return((*entities.OrderReview)(nil), errors.New("order review already settled"))
+
+		_, err := riskService.ApproveOrderReview("ORD-1001", 1, time.Now())
+
+		assert.EqualError(t, err, "order review already settled")
+	})
+
+	t.Run("approve given database error", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		riskService := RiskService{repo: mockRepo}
+
+		mockRepo.On("ApproveOrderReview", "ORD-1001", uint(1), mock.Anything, mock.Anything).Return((*entities.OrderReview)(nil), errors.New("connection reset"))
+
+		_, err := riskService.ApproveOrderReview("ORD-1001", 1, time.Now())
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+type MockRiskRepository struct {
+	mock.Mock
+}
+
+func (m *MockRiskRepository) UpdatePurchaseLimits(product *entities.Product) error {
+	args := m.Called(product)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) InsertRiskRule(rule *entities.RiskRule) (*entities.RiskRule, error) {
+	args := m.Called(rule)
+	return args.Get(0).(*entities.RiskRule), args.Error(1)
+}
+
+func (m *MockRiskRepository) GetRiskRules(activeOnly bool) ([]entities.RiskRule, error) {
+	args := m.Called(activeOnly)
+	return args.Get(0).([]entities.RiskRule), args.Error(1)
+}
+
+func (m *MockRiskRepository) DeleteRiskRule(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) GetOrderReviews(status string) ([]entities.OrderReview, error) {
+	args := m.Called(status)
+	return args.Get(0).([]entities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskRepository) HoldOrderReview(review *entities.OrderReview) (*entities.OrderReview, error) {
+	args := m.Called(review)
+	return args.Get(0).(*entities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskRepository) ApproveOrderReview(referenceID string, reviewerID uint, reviewedAt, expiresAt time.Time) (*entities.OrderReview, error) {
+	args := m.Called(referenceID, reviewerID, reviewedAt, expiresAt)
+	return args.Get(0).(*entities.OrderReview), args.Error(1)
+}
+
+func (m *MockRiskRepository) RejectOrderReview(referenceID string, reviewerID uint, reviewedAt time.Time) (*entities.OrderReview, error) {
+	args := m.Called(referenceID, reviewerID, reviewedAt)
+	return args.Get(0).(*entities.OrderReview), args.Error(1)
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+)
+
+// facetLabels names the facet values that are not catalogue rows.
+var facetLabels = map[string]string{
+	entities.AvailabilityInStock: "In stock",
+	entities.AvailabilitySoldOut: "Sold out",
+	entities.EditionRegular:      "Regular",
+	entities.EditionGlow:         "Glow in the dark",
+	entities.EditionChase:        "Chase",
+}
+
+const (
+	defaultSearchPageSize   = 20
+	maxSearchTerms          = 8
+	defaultAutocompleteSize = 8
+)
+
+type SearchUsecase interface {
+	SearchProducts(request *entities.SearchRequest) (*entities.SearchResponse, error)
+	Autocomplete(request *entities.AutocompleteRequest) ([]entities.SuggestionResponse, error)
+}
+
+type SearchService struct {
+	repo ProductSearchRepository
+}
+
+func NewSearchService(repo ProductSearchRepository) SearchUsecase {
+	return &SearchService{repo}
+}
+
+func (s *SearchService) SearchProducts(request *entities.SearchRequest) (*entities.SearchResponse, error) {
+	terms := searchTerms(request.Keyword)
+	if len(terms) == 0 {
+		return nil, errors.New("keyword is required")
+	}
+
+	page, pageSize := request.Page, request.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSearchPageSize
+	}
+
+	for _, key := range request.PriceBuckets {
+		if priceBucketIndex(key) < 0 {
+			return nil, errors.New("invalid price range")
+		}
+	}
+
+	query := &entities.SearchQuery{
+		Terms: terms,
+		Filters: entities.SearchFilters{
+			CategoryIDs:  request.CategoryIDs,
+			ArtistIDs:    request.ArtistIDs,
+			PriceBuckets: request.PriceBuckets,
+			Availability: request.Availability,
+			Editions:     request.Editions,
+		},
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	}
+
+	hits, total, err := s.repo.SearchProducts(query)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	facets, err := s.repo.SearchFacets(query)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	results := []entities.SearchResult{}
+	for _, hit := range hits {
+		results = append(results, entities.SearchResult{
+			ProductResponse: newProductResponse(&hit.Product),
+			Rank:            hit.Rank,
+			Snippet:         hit.Snippet,
+		})
+	}
+
+	response := &entities.SearchResponse{
+		Results:  results,
+		Facets:   newSearchFacets(facets, &query.Filters),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+
+	if total == 0 {
+		didYouMean, err := s.didYouMean(terms)
+		if err != nil {
+			return nil, err
+		}
+		response.DidYouMean = didYouMean
+	}
+
+	return response, nil
+}
+
+func (s *SearchService) Autocomplete(request *entities.AutocompleteRequest) ([]entities.SuggestionResponse, error) {
+	query := strings.TrimSpace(request.Query)
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+
+	limit := request.Limit
+	if limit < 1 {
+		limit = defaultAutocompleteSize
+	}
+
+	suggestions, err := s.repo.Autocomplete(query, limit)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	suggestionList := []entities.SuggestionResponse{}
+	for _, suggestion := range suggestions {
+		suggestionList = append(suggestionList, entities.SuggestionResponse{
+			Kind: suggestion.Kind,
+			ID:   suggestion.ID,
+			Name: suggestion.Name,
+		})
+	}
+
+	return suggestionList, nil
+}
+
+// didYouMean swaps each term for the closest catalogue word. It returns an
+// empty string when no term changes, so nothing is suggested for searches
+// that were spelt right but simply have no results.
+func (s *SearchService) didYouMean(terms []string) (string, error) {
+	corrected := make([]string, 0, len(terms))
+	changed := false
+
+	for _, term := range terms {
+		word, err := s.repo.ClosestWord(term)
+		if err != nil {
+			return "", errors.New("database error")
+		}
+
+		if word == "" || word == term {
+			corrected = append(corrected, term)
+			continue
+		}
+
+		corrected = append(corrected, word)
+		changed = true
+	}
+
+	if !changed {
+		return "", nil
+	}
+
+	return strings.Join(corrected, " "), nil
+}
+
+// searchTerms splits a keyword into lower-case words. Anything that is not a
+// letter, digit or combining mark (Thai vowels and tones are marks) separates
+// words, which also keeps query syntax out of the terms.
+func searchTerms(keyword string) []string {
+	words := strings.FieldsFunc(strings.ToLower(keyword), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	})
+
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		terms = append(terms, word)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// newSearchFacets labels the counts and marks the selected values. Selected
+// values are kept even when nothing matches them so they can be cleared.
+func newSearchFacets(facets *entities.SearchFacets, filters *entities.SearchFilters) entities.SearchFacets {
+	categoryIDs := make([]string, 0, len(filters.CategoryIDs))
+	for _, id := range filters.CategoryIDs {
+		categoryIDs = append(categoryIDs, strconv.FormatUint(uint64(id), 10))
+	}
+
+	artistIDs := make([]string, 0, len(filters.ArtistIDs))
+	for _, id := range filters.ArtistIDs {
+		artistIDs = append(artistIDs, strconv.FormatUint(uint64(id), 10))
+	}
+
+	var availability []string
+	if filters.Availability != "" {
+		availability = []string{filters.Availability}
+	}
+
+	prices := facetValues(facets.Prices, filters.PriceBuckets)
+	sort.SliceStable(prices, func(i, j int) bool {
+		return priceBucketIndex(prices[i].Value) < priceBucketIndex(prices[j].Value)
+	})
+
+	return entities.SearchFacets{
+		Categories:   facetValues(facets.Categories, categoryIDs),
+		Artists:      facetValues(facets.Artists, artistIDs),
+		Prices:       prices,
+		Availability: facetValues(facets.Availability, availability),
+		Editions:     facetValues(facets.Editions, filters.Editions),
+	}
+}
+
+func facetValues(counts []entities.FacetCount, selected []string) []entities.FacetCount {
+	isSelected := map[string]bool{}
+	for _, value := range selected {
+		isSelected[value] = true
+	}
+
+	values := []entities.FacetCount{}
+	seen := map[string]bool{}
+	for _, count := range counts {
+		count.Selected = isSelected[count.Value]
+		if count.Label == "" {
+			count.Label = facetLabel(count.Value)
+		}
+		values = append(values, count)
+		seen[count.Value] = true
+	}
+
+	for _, value := range selected {
+		if !seen[value] {
+			values = append(values, entities.FacetCount{Value: value, Label: facetLabel(value), Selected: true})
+			seen[value] = true
+		}
+	}
+
+	return values
+}
+
+func facetLabel(value string) string {
+	if i := priceBucketIndex(value); i >= 0 {
+		return entities.PriceBuckets[i].Label
+	}
+
+	return facetLabels[value]
+}
+
+func priceBucketIndex(key string) int {
+	for i, bucket := range entities.PriceBuckets {
+		if bucket.Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
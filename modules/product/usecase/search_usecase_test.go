@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSearchProducts(t *testing.T) {
+	t.Run("search products successfully", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		hits := []entities.SearchHit{
+			{
+				Product: entities.Product{Model: gorm.Model{ID: 1}, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night'.", Price: money.New(4999, "THB"), Active: true},
+				Rank:    0.5,
+				Snippet: "<mark>Dimoo</mark> inspired by Van Gogh's '<mark>Starry</mark> Night'.",
+			},
+		}
+
+		mockRepo.On("SearchProducts", &entities.SearchQuery{Terms: []string{"dimoo", "star"}, Limit: 20, Offset: 0}).Return(hits, int64(1), nil)
+		mockRepo.On("SearchFacets", mock.Anything).Return(&entities.SearchFacets{}, nil)
+
+		got, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "Dimoo star"})
+
+		want := &entities.SearchResponse{
+			Results: []entities.SearchResult{
+				{
+					ProductResponse: entities.ProductResponse{ID: 1, Name: "Dimoo Starry Night", Description: "Dimoo inspired by Van Gogh's 'Starry Night'.", Price: money.New(4999, "THB")},
+					Rank:            0.5,
+					Snippet:         "<mark>Dimoo</mark> inspired by Van Gogh's '<mark>Starry</mark> Night'.",
+				},
+			},
+			Facets:   noFacets(),
+			Page:     1,
+			PageSize: 20,
+			Total:    1,
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("search products given page", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("SearchProducts", &entities.SearchQuery{Terms: []string{"labubu"}, Limit: 10, Offset: 20}).Return([]entities.SearchHit{}, int64(21), nil)
+		mockRepo.On("SearchFacets", mock.Anything).Return(&entities.SearchFacets{}, nil)
+
+		got, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "labubu", Page: 3, PageSize: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.SearchResponse{Results: []entities.SearchResult{}, Facets: noFacets(), Page: 3, PageSize: 10, Total: 21}, got)
+	})
+
+	t.Run("search products given keyword without words", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		_, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: " !&| "})
+
+		assert.EqualError(t, err, "keyword is required")
+		mockRepo.AssertNotCalled(t, "SearchProducts", mock.Anything)
+	})
+
+	t.Run("search products given database error", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("SearchProducts", mock.Anything).Return(([]entities.SearchHit)(nil), int64(0), errors.New("connection reset"))
+
+		_, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "dimoo"})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestSearchProductsFacets(t *testing.T) {
+	t.Run("pass filters through and label the facets", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		query := &entities.SearchQuery{
+			Terms: []string{"dimoo"},
+			Filters: entities.SearchFilters{
+				CategoryIDs:  []uint{3},
+				PriceBuckets: []string{"500-plus", "50-100"},
+				Availability: "in_stock",
+				Editions:     []string{"chase"},
+			},
+			Limit: 20,
+		}
+
+		mockRepo.On("SearchProducts", query).Return([]entities.SearchHit{}, int64(4), nil)
+		mockRepo.On("SearchFacets", query).Return(&entities.SearchFacets{
+			Categories:   []entities.FacetCount{{Value: "3", Label: "Figures", Count: 4}, {Value: "4", Label: "Plush", Count: 1}},
+			Prices:       []entities.FacetCount{{Value: "100-250", Count: 2}, {Value: "under-50", Count: 1}, {Value: "50-100", Count: 4}},
+			Availability: []entities.FacetCount{{Value: "in_stock", Count: 4}, {Value: "sold_out", Count: 2}},
+			Editions:     []entities.FacetCount{{Value: "regular", Count: 3}, {Value: "chase", Count: 1}},
+		}, nil)
+
+		got, err := searchService.SearchProducts(&entities.SearchRequest{
+			Keyword:      "dimoo",
+			CategoryIDs:  []uint{3},
+			PriceBuckets: []string{"500-plus", "50-100"},
+			Availability: "in_stock",
+			Editions:     []string{"chase"},
+		})
+
+		want := entities.SearchFacets{
+			Categories: []entities.FacetCount{{Value: "3", Label: "Figures", Count: 4, Selected: true}, {Value: "4", Label: "Plush", Count: 1}},
+			Artists:    []entities.FacetCount{},
+			Prices: []entities.FacetCount{
+				{Value: "under-50", Label: "Under 50", Count: 1},
+				{Value: "50-100", Label: "50 to 100", Count: 4, Selected: true},
+				{Value: "100-250", Label: "100 to 250", Count: 2},
+				{Value: "500-plus", Label: "500 and over", Selected: true},
+			},
+			Availability: []entities.FacetCount{{Value: "in_stock", Label: "In stock", Count: 4, Selected: true}, {Value: "sold_out", Label: "Sold out", Count: 2}},
+			Editions:     []entities.FacetCount{{Value: "regular", Label: "Regular", Count: 3}, {Value: "chase", Label: "Chase", Count: 1, Selected: true}},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got.Facets)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("search products given unknown price range", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		_, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "dimoo", PriceBuckets: []string{"0-10"}})
+
+		assert.EqualError(t, err, "invalid price range")
+		mockRepo.AssertNotCalled(t, "SearchProducts", mock.Anything)
+	})
+
+	t.Run("search facets given database error", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("SearchProducts", mock.Anything).Return([]entities.SearchHit{}, int64(1), nil)
+		mockRepo.On("SearchFacets", mock.Anything).Return((*entities.SearchFacets)(nil), errors.New("connection reset"))
+
+		_, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "dimoo"})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestSearchProductsDidYouMean(t *testing.T) {
+	t.Run("suggest corrected keyword when nothing matches", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("SearchProducts", &entities.SearchQuery{Terms: []string{"labubo", "zimomo"}, Limit: 20}).Return([]entities.SearchHit{}, int64(0), nil)
+		mockRepo.On("SearchFacets", mock.Anything).Return(&entities.SearchFacets{}, nil)
+		mockRepo.On("ClosestWord", "labubo").Return("labubu", nil)
+		mockRepo.On("ClosestWord", "zimomo").Return("zimomo", nil)
+
+		got, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "Labubo Zimomo"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.SearchResponse{Results: []entities.SearchResult{}, Facets: noFacets(), Page: 1, PageSize: 20, DidYouMean: "labubu zimomo"}, got)
+	})
+
+	t.Run("no suggestion when no word is close", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("SearchProducts", mock.Anything).Return([]entities.SearchHit{}, int64(0), nil)
+		mockRepo.On("SearchFacets", mock.Anything).Return(&entities.SearchFacets{}, nil)
+		mockRepo.On("ClosestWord", "xyz").Return("", nil)
+
+		got, err := searchService.SearchProducts(&entities.SearchRequest{Keyword: "xyz"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, got.DidYouMean)
+	})
+}
+
+func TestAutocomplete(t *testing.T) {
+	t.Run("autocomplete successfully", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("Autocomplete", "labubo", 8).Return([]entities.Suggestion{
+			{Kind: entities.SuggestionKindSeries, ID: 5, Name: "Labubu The Monsters", Score: 0.71},
+			{Kind: entities.SuggestionKindProduct, ID: 12, Name: "Labubu Sea Salt Coconut", Score: 0.71},
+		}, nil)
+
+		got, err := searchService.Autocomplete(&entities.AutocompleteRequest{Query: " labubo "})
+
+		want := []entities.SuggestionResponse{
+			{Kind: "series", ID: 5, Name: "Labubu The Monsters"},
+			{Kind: "product", ID: 12, Name: "Labubu Sea Salt Coconut"},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("autocomplete given empty query", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		_, err := searchService.Autocomplete(&entities.AutocompleteRequest{Query: "  "})
+
+		assert.EqualError(t, err, "query is required")
+	})
+
+	t.Run("autocomplete given database error", func(t *testing.T) {
+		mockRepo := new(MockProductSearchRepository)
+		searchService := SearchService{repo: mockRepo}
+
+		mockRepo.On("Autocomplete", "lab", 5).Return(([]entities.Suggestion)(nil), errors.New("connection reset"))
+
+		_, err := searchService.Autocomplete(&entities.AutocompleteRequest{Query: "lab", Limit: 5})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    []string
+	}{
+		{"Dimoo", []string{"dimoo"}},
+		{"  Starry   NIGHT ", []string{"starry", "night"}},
+		{"skull:* & !panda", []string{"skull", "panda"}},
+		{"labubu labubu", []string{"labubu"}},
+		{"ลาบูบู้ 2024", []string{"ลาบูบู้", "2024"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, searchTerms(tt.keyword), tt.keyword)
+	}
+}
+
+func noFacets() entities.SearchFacets {
+	return entities.SearchFacets{
+		Categories:   []entities.FacetCount{},
+		Artists:      []entities.FacetCount{},
+		Prices:       []entities.FacetCount{},
+		Availability: []entities.FacetCount{},
+		Editions:     []entities.FacetCount{},
+	}
+}
+
+type MockProductSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductSearchRepository) SearchProducts(query *entities.SearchQuery) ([]entities.SearchHit, int64, error) {
+	args := m.Called(query)
+	return args.Get(0).([]entities.SearchHit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProductSearchRepository) SearchFacets(query *entities.SearchQuery) (*entities.SearchFacets, error) {
+	args := m.Called(query)
+	return args.Get(0).(*entities.SearchFacets), args.Error(1)
+}
+
+func (m *MockProductSearchRepository) Autocomplete(query string, limit int) ([]entities.Suggestion, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]entities.Suggestion), args.Error(1)
+}
+
+func (m *MockProductSearchRepository) ClosestWord(term string) (string, error) {
+	args := m.Called(term)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+)
+
+const stockAlertBatch = 100
+
+// AdminNotifier tells store admins about products running low.
+type AdminNotifier interface {
+	NotifyLowStock(alerts []entities.StockAlert) error
+}
+
+// StockAlertDispatcher hands pending low-stock alerts to the admin notifier
+// in the background. An alert is only marked notified once the notifier has
+// accepted it, so a failed send is retried on the next run.
+type StockAlertDispatcher struct {
+	repo     InventoryRepository
+	notifier AdminNotifier
+	interval time.Duration
+}
+
+func NewStockAlertDispatcher(repo InventoryRepository, notifier AdminNotifier, interval time.Duration) *StockAlertDispatcher {
+	return &StockAlertDispatcher{repo, notifier, interval}
+}
+
+// Run dispatches every interval until ctx is cancelled.
+func (d *StockAlertDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Dispatch(); err != nil {
+				log.Printf("failed to dispatch stock alerts: %v", err)
+			}
+		}
+	}
+}
+
+// Dispatch sends one batch of pending alerts and returns how many were sent.
+func (d *StockAlertDispatcher) Dispatch() (int, error) {
+	alerts, err := d.repo.GetPendingStockAlerts(stockAlertBatch)
+	if err != nil {
+		return 0, errors.New("database error")
+	}
+
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+
+	if err := d.notifier.NotifyLowStock(alerts); err != nil {
+		return 0, err
+	}
+
+	ids := make([]uint, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+
+	if err := d.repo.MarkStockAlertsNotified(ids, time.Now()); err != nil {
+		return 0, errors.New("database error")
+	}
+
+	return len(alerts), nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+type StockSubscriptionUsecase interface {
+	Subscribe(userID uint, request *entities.StockSubscriptionRequest) (*entities.StockSubscription, error)
+	GetSubscriptions(userID uint) ([]entities.StockSubscription, error)
+	Unsubscribe(userID uint, id string) error
+}
+
+type StockSubscriptionService struct {
+	repo        StockSubscriptionRepository
+	productRepo ProductRepository
+	variantRepo ProductVariantRepository
+}
+
+func NewStockSubscriptionService(repo StockSubscriptionRepository, productRepo ProductRepository, variantRepo ProductVariantRepository) StockSubscriptionUsecase {
+	return &StockSubscriptionService{repo, productRepo, variantRepo}
+}
+
+// Subscribe asks to be told when a sold-out product, or one of its variants,
+// is back in stock. Subscribing twice to the same item returns the existing
+// subscription so the customer keeps their place in the queue.
+func (s *StockSubscriptionService) Subscribe(userID uint, request *entities.StockSubscriptionRequest) (*entities.StockSubscription, error) {
+	product, err := s.getProduct(strconv.FormatUint(uint64(request.ProductID), 10))
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+		return nil, errors.New("blind box stock is set by its figures")
+	}
+
+	stock := product.Stock
+	variantID := optionalID(request.VariantID)
+	if variantID != nil {
+		variant, err := s.variantRepo.GetVariantById(product.ID, strconv.FormatUint(uint64(*variantID), 10))
+		if err != nil {
+			if err.Error() == "variant not found" {
+				return nil, err
+			}
+			return nil, errors.New("database error")
+		}
+		stock = variant.Stock
+	}
+
+	if stock > 0 {
+		return nil, errors.New("product is in stock")
+	}
+
+	existing, err := s.repo.GetSubscription(userID, product.ID, variantID)
+	if err == nil {
+		return existing, nil
+	}
+	if err.Error() != "subscription not found" {
+		return nil, errors.New("database error")
+	}
+
+	subscription, err := s.repo.InsertSubscription(&entities.StockSubscription{
+		ProductID: product.ID,
+		VariantID: variantID,
+		UserID:    userID,
+		Email:     request.Email,
+	})
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return subscription, nil
+}
+
+func (s *StockSubscriptionService) GetSubscriptions(userID uint) ([]entities.StockSubscription, error) {
+	subscriptions, err := s.repo.GetSubscriptionsByUser(userID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return subscriptions, nil
+}
+
+func (s *StockSubscriptionService) Unsubscribe(userID uint, id string) error {
+	if err := s.repo.DeleteSubscription(userID, id); err != nil {
+		if err.Error() == "subscription not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *StockSubscriptionService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Run("subscribe to a sold-out product", func(t *testing.T) {
+		mockRepo := new(MockStockSubscriptionRepository)
+		mockProductRepo := new(MockProductRepository)
+		subscriptionService := StockSubscriptionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		subscription := &entities.StockSubscription{ProductID: 1, UserID: 12, Email: "fan@example.com"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard", Stock: 0}, nil)
+		mockRepo.On("GetSubscription", uint(12), uint(1), (*uint)(nil)).Return((*entities.StockSubscription)(nil), errors.New("subscription not found"))
+		mockRepo.On("InsertSubscription", subscription).Return(&entities.StockSubscription{ID: 5, ProductID: 1, UserID: 12, Email: "fan@example.com"}, nil)
+
+		got, err := subscriptionService.Subscribe(12, &entities.StockSubscriptionRequest{ProductID: 1, Email: "fan@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), got.ID)
+	})
+
+	t.Run("subscribe again keeps the existing subscription", func(t *testing.T) {
+		mockRepo := new(MockStockSubscriptionRepository)
+		mockProductRepo := new(MockProductRepository)
+		subscriptionService := StockSubscriptionService{repo: mockRepo, productRepo: mockProductRepo}
+
+		existing := &entities.StockSubscription{ID: 5, ProductID: 1, UserID: 12, Email: "fan@example.com"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("GetSubscription", uint(12), uint(1), (*uint)(nil)).Return(existing, nil)
+
+		got, err := subscriptionService.Subscribe(12, &entities.StockSubscriptionRequest{ProductID: 1, Email: "fan@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing, got)
+		mockRepo.AssertNotCalled(t, "InsertSubscription", mock.Anything)
+	})
+
+	t.Run("subscribe to a variant in stock", func(t *testing.T) {
+		mockRepo := new(MockStockSubscriptionRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockVariantRepo := new(MockProductVariantRepository)
+		subscriptionService := StockSubscriptionService{repo: mockRepo, productRepo: mockProductRepo, variantRepo: mockVariantRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard", Stock: 4}, nil)
+		mockVariantRepo.On("GetVariantById", uint(1), "3").Return(&entities.ProductVariant{ProductID: 1, Stock: 4}, nil)
+
+		_, err := subscriptionService.Subscribe(12, &entities.StockSubscriptionRequest{ProductID: 1, VariantID: 3, Email: "fan@example.com"})
+
+		assert.EqualError(t, err, "product is in stock")
+		mockRepo.AssertNotCalled(t, "InsertSubscription", mock.Anything)
+	})
+
+	t.Run("subscribe to a sold-out variant of a product still in stock", func(t *testing.T) {
+		mockRepo := new(MockStockSubscriptionRepository)
+		mockProductRepo := new(MockProductRepository)
+		mockVariantRepo := new(MockProductVariantRepository)
+		subscriptionService := StockSubscriptionService{repo: mockRepo, productRepo: mockProductRepo, variantRepo: mockVariantRepo}
+
+		variantID := uint(3)
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard", Stock: 4}, nil)
+		mockVariantRepo.On("GetVariantById", uint(1), "3").Return(&entities.ProductVariant{ProductID: 1, Stock: 0}, nil)
+		mockRepo.On("GetSubscription", uint(12), uint(1), &variantID).Return((*entities.StockSubscription)(nil), errors.New("subscription not found"))
+		mockRepo.On("InsertSubscription", mock.Anything).Return(&entities.StockSubscription{ID: 6, ProductID: 1, VariantID: &variantID, UserID: 12}, nil)
+
+		got, err := subscriptionService.Subscribe(12, &entities.StockSubscriptionRequest{ProductID: 1, VariantID: 3, Email: "fan@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &variantID, got.VariantID)
+	})
+}
+
+func TestBackInStockDispatcher(t *testing.T) {
+	t.Run("send notifications in queue order", func(t *testing.T) {
+		mockRepo := new(MockStockSubscriptionRepository)
+		mockNotifier := new(MockCustomerNotifier)
+		dispatcher := NewBackInStockDispatcher(mockRepo, mockNotifier, time.Minute)
+
+		first := entities.StockNotification{ID: 1, ProductID: 1, UserID: 12, Email: "first@example.com"}
+		second := entities.StockNotification{ID: 2, ProductID: 1, UserID: 40, Email: "second@example.com"}
+
+		mockRepo.On("GetPendingNotifications", stockNotificationBatch).Return([]entities.StockNotification{first, second}, nil)
+		firstSent := mockNotifier.On("NotifyBackInStock", first).Return(nil)
+		mockNotifier.On("NotifyBackInStock", second).Return(nil).NotBefore(firstSent)
+		mockRepo.On("MarkNotificationsSent", []uint{1, 2}, mock.Anything).Return(nil)
+
+		sent, err := dispatcher.Dispatch()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+	})
+
+	t.Run("stop at the first failed send", func(t *testing.T) {
+		mockRepo := new(MockStockSubscriptionRepository)
+		mockNotifier := new(MockCustomerNotifier)
+		dispatcher := NewBackInStockDispatcher(mockRepo, mockNotifier, time.Minute)
+
+		notifications := []entities.StockNotification{
+			{ID: 1, ProductID: 1, Email: "first@example.com"},
+			{ID: 2, ProductID: 1, Email: "second@example.com"},
+			{ID: 3, ProductID: 1, Email: "third@example.com"},
+		}
+
+		mockRepo.On("GetPendingNotifications", stockNotificationBatch).Return(notifications, nil)
+		mockNotifier.On("NotifyBackInStock", notifications[0]).Return(nil)
+		mockNotifier.On("NotifyBackInStock", notifications[1]).Return(errors.New("smtp unavailable"))
+		mockRepo.On("MarkNotificationsSent", []uint{1}, mock.Anything).Return(nil)
+
+		sent, err := dispatcher.Dispatch()
+
+		assert.EqualError(t, err, "smtp unavailable")
+		assert.Equal(t, 1, sent)
+		mockNotifier.AssertNotCalled(t, "NotifyBackInStock", notifications[2])
+	})
+}
+
+type MockCustomerNotifier struct {
+	mock.Mock
+}
+
+func (m *MockCustomerNotifier) NotifyBackInStock(notification entities.StockNotification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+type MockStockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockSubscriptionRepository) InsertSubscription(subscription *entities.StockSubscription) (*entities.StockSubscription, error) {
+	args := m.Called(subscription)
+	return args.Get(0).(*entities.StockSubscription), args.Error(1)
+}
+
+func (m *MockStockSubscriptionRepository) GetSubscription(userID, productID uint, variantID *uint) (*entities.StockSubscription, error) {
+	args := m.Called(userID, productID, variantID)
+	return args.Get(0).(*entities.StockSubscription), args.Error(1)
+}
+
+func (m *MockStockSubscriptionRepository) GetSubscriptionsByUser(userID uint) ([]entities.StockSubscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entities.StockSubscription), args.Error(1)
+}
+
+func (m *MockStockSubscriptionRepository) DeleteSubscription(userID uint, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockStockSubscriptionRepository) GetPendingNotifications(limit int) ([]entities.StockNotification, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]entities.StockNotification), args.Error(1)
+}
+
+func (m *MockStockSubscriptionRepository) MarkNotificationsSent(ids []uint, sentAt time.Time) error {
+	args := m.Called(ids, sentAt)
+	return args.Error(0)
+}
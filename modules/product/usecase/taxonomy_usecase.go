@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+type TaxonomyUsecase interface {
+	CreateCategory(category *entities.Category) (*entities.CategoryResponse, error)
+	GetCategoryTree() ([]entities.CategoryResponse, error)
+	GetCategoryById(id string) (*entities.CategoryResponse, error)
+	UpdateCategory(category *entities.Category, id string) (*entities.CategoryResponse, error)
+	DeleteCategory(id string) error
+	CreateSeries(series *entities.Series) (*entities.SeriesResponse, error)
+	GetAllSeries() ([]entities.SeriesResponse, error)
+	GetSeriesById(id string) (*entities.SeriesResponse, error)
+	UpdateSeries(series *entities.Series, id string) (*entities.SeriesResponse, error)
+	DeleteSeries(id string) error
+	SetProductCategories(productID string, categories *entities.ProductCategories) (*entities.ProductResponse, error)
+}
+
+type TaxonomyService struct {
+	repo        TaxonomyRepository
+	productRepo ProductRepository
+}
+
+func NewTaxonomyService(repo TaxonomyRepository, productRepo ProductRepository) TaxonomyUsecase {
+	return &TaxonomyService{repo, productRepo}
+}
+
+func (s *TaxonomyService) CreateCategory(category *entities.Category) (*entities.CategoryResponse, error) {
+	if category.ParentID != nil {
+		if _, err := s.repo.GetCategoryById(strconv.FormatUint(uint64(*category.ParentID), 10)); err != nil {
+			if err.Error() == "category not found" {
+				return nil, errors.New("parent category not found")
+			}
+			return nil, errors.New("database error")
+		}
+	}
+
+	if category.Slug == "" {
+		category.Slug = slugify(category.Name)
+	}
+
+	newCategory, err := s.repo.InsertCategory(category)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newCategoryResponse(newCategory)
+	return &response, nil
+}
+
+func (s *TaxonomyService) GetCategoryTree() ([]entities.CategoryResponse, error) {
+	categories, err := s.repo.GetAllCategories()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return buildCategoryTree(categories, nil), nil
+}
+
+func (s *TaxonomyService) GetCategoryById(id string) (*entities.CategoryResponse, error) {
+	category, err := s.repo.GetCategoryById(id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	categories, err := s.repo.GetAllCategories()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newCategoryResponse(category)
+	response.Children = buildCategoryTree(categories, &category.ID)
+	return &response, nil
+}
+
+func (s *TaxonomyService) UpdateCategory(category *entities.Category, id string) (*entities.CategoryResponse, error) {
+	existing, err := s.repo.GetCategoryById(id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	if category.ParentID != nil {
+		categories, err := s.repo.GetAllCategories()
+		if err != nil {
+			return nil, errors.New("database error")
+		}
+
+		if !containsCategory(categories, *category.ParentID) {
+			return nil, errors.New("parent category not found")
+		}
+
+		if *category.ParentID == existing.ID || isDescendant(categories, *category.ParentID, existing.ID) {
+			return nil, errors.New("category cannot be moved under itself")
+		}
+	}
+
+	if category.Slug == "" {
+		category.Slug = slugify(category.Name)
+	}
+
+	categoryUpdated, err := s.repo.UpdateCategory(category, id)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	categoryUpdated.ID = existing.ID
+
+	response := newCategoryResponse(categoryUpdated)
+	return &response, nil
+}
+
+func (s *TaxonomyService) DeleteCategory(id string) error {
+	category, err := s.repo.GetCategoryById(id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	children, err := s.repo.CountChildCategories(category.ID)
+	if err != nil {
+		return errors.New("database error")
+	}
+
+	if children > 0 {
+		return errors.New("category has subcategories")
+	}
+
+	if err := s.repo.DeleteCategory(id); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *TaxonomyService) CreateSeries(series *entities.Series) (*entities.SeriesResponse, error) {
+	if series.Slug == "" {
+		series.Slug = slugify(series.Name)
+	}
+
+	newSeries, err := s.repo.InsertSeries(series)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newSeriesResponse(newSeries)
+	return &response, nil
+}
+
+func (s *TaxonomyService) GetAllSeries() ([]entities.SeriesResponse, error) {
+	series, err := s.repo.GetAllSeries()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	seriesList := []entities.SeriesResponse{}
+	for _, item := range series {
+		seriesList = append(seriesList, newSeriesResponse(&item))
+	}
+
+	return seriesList, nil
+}
+
+func (s *TaxonomyService) GetSeriesById(id string) (*entities.SeriesResponse, error) {
+	series, err := s.repo.GetSeriesById(id)
+	if err != nil {
+		if err.Error() == "series not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	response := newSeriesResponse(series)
+	return &response, nil
+}
+
+func (s *TaxonomyService) UpdateSeries(series *entities.Series, id string) (*entities.SeriesResponse, error) {
+	existing, err := s.repo.GetSeriesById(id)
+	if err != nil {
+		if err.Error() == "series not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	if series.Slug == "" {
+		series.Slug = slugify(series.Name)
+	}
+
+	seriesUpdated, err := s.repo.UpdateSeries(series, id)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	seriesUpdated.ID = existing.ID
+
+	response := newSeriesResponse(seriesUpdated)
+	return &response, nil
+}
+
+func (s *TaxonomyService) DeleteSeries(id string) error {
+	if _, err := s.repo.GetSeriesById(id); err != nil {
+		if err.Error() == "series not found" {
+			return err
+		}
+		return errors.New("database error")
+	}
+
+	if err := s.repo.DeleteSeries(id); err != nil {
+		return errors.New("database error")
+	}
+
+	return nil
+}
+
+func (s *TaxonomyService) SetProductCategories(productID string, categories *entities.ProductCategories) (*entities.ProductResponse, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	seen := make(map[uint]bool, len(categories.CategoryIDs))
+	var categoryIDs []uint
+	for _, id := range categories.CategoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			categoryIDs = append(categoryIDs, id)
+		}
+	}
+
+	if err := s.repo.ReplaceProductCategories(product.ID, categoryIDs); err != nil {
+		if err.Error() == "category not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	productUpdated, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newProductResponse(productUpdated)
+	return &response, nil
+}
+
+// buildCategoryTree nests categories under their parents, starting from the
+// children of parentID (or the roots when parentID is nil).
+func buildCategoryTree(categories []entities.Category, parentID *uint) []entities.CategoryResponse {
+	var tree []entities.CategoryResponse
+	for _, category := range categories {
+		if !sameParent(category.ParentID, parentID) {
+			continue
+		}
+
+		node := newCategoryResponse(&category)
+		node.Children = buildCategoryTree(categories, &category.ID)
+		tree = append(tree, node)
+	}
+
+	return tree
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func containsCategory(categories []entities.Category, id uint) bool {
+	for _, category := range categories {
+		if category.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isDescendant reports whether id sits somewhere below ancestorID.
+func isDescendant(categories []entities.Category, id, ancestorID uint) bool {
+	parents := make(map[uint]*uint, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+
+	for depth := 0; depth < len(categories); depth++ {
+		parent := parents[id]
+		if parent == nil {
+			return false
+		}
+		if *parent == ancestorID {
+			return true
+		}
+		id = *parent
+	}
+
+	return false
+}
+
+func slugify(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			dash = false
+		} else if !dash && builder.Len() > 0 {
+			builder.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "-")
+}
+
+func newCategoryResponse(category *entities.Category) entities.CategoryResponse {
+	return entities.CategoryResponse{
+		ID:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ParentID:    category.ParentID,
+	}
+}
+
+func newSeriesResponse(series *entities.Series) entities.SeriesResponse {
+	var products []entities.ProductResponse
+	for _, product := range series.Products {
+		products = append(products, newProductResponse(&product))
+	}
+
+	return entities.SeriesResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Slug:        series.Slug,
+		Description: series.Description,
+		Products:    products,
+	}
+}
@@ -0,0 +1,330 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/phetployst/art-toys-store/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func uintPtr(value uint) *uint {
+	return &value
+}
+
+func TestCreateCategory(t *testing.T) {
+	t.Run("create root category with generated slug", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("InsertCategory", mock.MatchedBy(func(category *entities.Category) bool {
+			return category.Slug == "designer-toys"
+		})).Return(&entities.Category{Model: gorm.Model{ID: 1}, Name: "Designer Toys", Slug: "designer-toys"}, nil)
+
+		got, err := taxonomyService.CreateCategory(&entities.Category{Name: "Designer Toys"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.CategoryResponse{ID: 1, Name: "Designer Toys", Slug: "designer-toys"}, got)
+	})
+
+	t.Run("create category given missing parent", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetCategoryById", "9").Return((*entities.Category)(nil), errors.New("category not found"))
+
+		_, err := taxonomyService.CreateCategory(&entities.Category{Name: "Labubu", ParentID: uintPtr(9)})
+
+		assert.EqualError(t, err, "parent category not found")
+		mockRepo.AssertNotCalled(t, "InsertCategory", mock.Anything)
+	})
+
+	t.Run("create category given database error", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("InsertCategory", mock.Anything).Return((*entities.Category)(nil), errors.New("duplicate key"))
+
+		_, err := taxonomyService.CreateCategory(&entities.Category{Name: "Designer Toys"})
+
+		assert.EqualError(t, err, "database error")
+	})
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	t.Run("build nested category tree", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetAllCategories").Return([]entities.Category{
+			{Model: gorm.Model{ID: 1}, Name: "Brands", Slug: "brands"},
+			{Model: gorm.Model{ID: 2}, Name: "Pop Mart", Slug: "pop-mart", ParentID: uintPtr(1)},
+			{Model: gorm.Model{ID: 3}, Name: "The Monsters", Slug: "the-monsters", ParentID: uintPtr(2)},
+			{Model: gorm.Model{ID: 4}, Name: "Characters", Slug: "characters"},
+		}, nil)
+
+		got, err := taxonomyService.GetCategoryTree()
+
+		want := []entities.CategoryResponse{
+			{ID: 1, Name: "Brands", Slug: "brands", Children: []entities.CategoryResponse{
+				{ID: 2, Name: "Pop Mart", Slug: "pop-mart", ParentID: uintPtr(1), Children: []entities.CategoryResponse{
+					{ID: 3, Name: "The Monsters", Slug: "the-monsters", ParentID: uintPtr(2)},
+				}},
+			}},
+			{ID: 4, Name: "Characters", Slug: "characters"},
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestUpdateCategory(t *testing.T) {
+	categories := []entities.Category{
+		{Model: gorm.Model{ID: 1}, Name: "Brands"},
+		{Model: gorm.Model{ID: 2}, Name: "Pop Mart", ParentID: uintPtr(1)},
+		{Model: gorm.Model{ID: 3}, Name: "The Monsters", ParentID: uintPtr(2)},
+	}
+
+	t.Run("move category under another parent", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		category := &entities.Category{Name: "The Monsters", ParentID: uintPtr(1)}
+
+		mockRepo.On("GetCategoryById", "3").Return(&categories[2], nil)
+		mockRepo.On("GetAllCategories").Return(categories, nil)
+		mockRepo.On("UpdateCategory", category, "3").Return(category, nil)
+
+		got, err := taxonomyService.UpdateCategory(category, "3")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.CategoryResponse{ID: 3, Name: "The Monsters", Slug: "the-monsters", ParentID: uintPtr(1)}, got)
+	})
+
+	t.Run("update category given parent is a descendant", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetCategoryById", "1").Return(&categories[0], nil)
+		mockRepo.On("GetAllCategories").Return(categories, nil)
+
+		_, err := taxonomyService.UpdateCategory(&entities.Category{Name: "Brands", ParentID: uintPtr(3)}, "1")
+
+		assert.EqualError(t, err, "category cannot be moved under itself")
+		mockRepo.AssertNotCalled(t, "UpdateCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update category given itself as parent", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetCategoryById", "2").Return(&categories[1], nil)
+		mockRepo.On("GetAllCategories").Return(categories, nil)
+
+		_, err := taxonomyService.UpdateCategory(&entities.Category{Name: "Pop Mart", ParentID: uintPtr(2)}, "2")
+
+		assert.EqualError(t, err, "category cannot be moved under itself")
+	})
+
+	t.Run("update category given category not found", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetCategoryById", "30").Return((*entities.Category)(nil), errors.New("category not found"))
+
+		_, err := taxonomyService.UpdateCategory(&entities.Category{Name: "Pop Mart"}, "30")
+
+		assert.EqualError(t, err, "category not found")
+	})
+}
+
+func TestDeleteCategory(t *testing.T) {
+	t.Run("delete leaf category", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetCategoryById", "3").Return(&entities.Category{Model: gorm.Model{ID: 3}}, nil)
+		mockRepo.On("CountChildCategories", uint(3)).Return(int64(0), nil)
+		mockRepo.On("DeleteCategory", "3").Return(nil)
+
+		err := taxonomyService.DeleteCategory("3")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete category given subcategories", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetCategoryById", "1").Return(&entities.Category{Model: gorm.Model{ID: 1}}, nil)
+		mockRepo.On("CountChildCategories", uint(1)).Return(int64(2), nil)
+
+		err := taxonomyService.DeleteCategory("1")
+
+		assert.EqualError(t, err, "category has subcategories")
+		mockRepo.AssertNotCalled(t, "DeleteCategory", mock.Anything)
+	})
+}
+
+func TestSeries(t *testing.T) {
+	t.Run("create series successfully", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("InsertSeries", mock.MatchedBy(func(series *entities.Series) bool {
+			return series.Slug == "exciting-macaron"
+		})).Return(&entities.Series{Model: gorm.Model{ID: 5}, Name: "Exciting Macaron", Slug: "exciting-macaron"}, nil)
+
+		got, err := taxonomyService.CreateSeries(&entities.Series{Name: "Exciting Macaron"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.SeriesResponse{ID: 5, Name: "Exciting Macaron", Slug: "exciting-macaron"}, got)
+	})
+
+	t.Run("get series with its figures", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetSeriesById", "5").Return(&entities.Series{Model: gorm.Model{ID: 5}, Name: "Exciting Macaron", Slug: "exciting-macaron",
+			Products: []entities.Product{
+				{Model: gorm.Model{ID: 10}, Name: "Labubu Sea Salt", Price: money.New(39000, "THB"), SeriesID: uintPtr(5)},
+				{Model: gorm.Model{ID: 11}, Name: "Labubu Lychee Berry", Price: money.New(39000, "THB"), SeriesID: uintPtr(5)},
+			}}, nil)
+
+		got, err := taxonomyService.GetSeriesById("5")
+
+		want := &entities.SeriesResponse{ID: 5, Name: "Exciting Macaron", Slug: "exciting-macaron", Products: []entities.ProductResponse{
+			{ID: 10, Name: "Labubu Sea Salt", Price: money.New(39000, "THB"), SeriesID: uintPtr(5)},
+			{ID: 11, Name: "Labubu Lychee Berry", Price: money.New(39000, "THB"), SeriesID: uintPtr(5)},
+		}}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("delete series given series not found", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo}
+
+		mockRepo.On("GetSeriesById", "50").Return((*entities.Series)(nil), errors.New("series not found"))
+
+		err := taxonomyService.DeleteSeries("50")
+
+		assert.EqualError(t, err, "series not found")
+	})
+}
+
+func TestSetProductCategories(t *testing.T) {
+	t.Run("replace product categories without duplicates", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		mockProductRepo := new(MockProductRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}, Name: "Molly Classic",
+			Categories: []entities.Category{{Model: gorm.Model{ID: 2}, Name: "Pop Mart", Slug: "pop-mart"}}}, nil)
+		mockRepo.On("ReplaceProductCategories", uint(12), []uint{2, 3}).Return(nil)
+
+		got, err := taxonomyService.SetProductCategories("12", &entities.ProductCategories{CategoryIDs: []uint{2, 3, 2}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.CategorySummary{{ID: 2, Name: "Pop Mart", Slug: "pop-mart"}}, got.Categories)
+	})
+
+	t.Run("set product categories given product not found", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		mockProductRepo := new(MockProductRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "99").Return((*entities.Product)(nil), gorm.ErrRecordNotFound)
+
+		_, err := taxonomyService.SetProductCategories("99", &entities.ProductCategories{CategoryIDs: []uint{2}})
+
+		assert.EqualError(t, err, "product not found")
+	})
+
+	t.Run("set product categories given unknown category", func(t *testing.T) {
+		mockRepo := new(MockTaxonomyRepository)
+		mockProductRepo := new(MockProductRepository)
+		taxonomyService := TaxonomyService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "12").Return(&entities.Product{Model: gorm.Model{ID: 12}}, nil)
+		mockRepo.On("ReplaceProductCategories", uint(12), []uint{40}).Return(errors.New("category not found"))
+
+		_, err := taxonomyService.SetProductCategories("12", &entities.ProductCategories{CategoryIDs: []uint{40}})
+
+		assert.EqualError(t, err, "category not found")
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "the-monsters-exciting-macaron", slugify("  The Monsters: Exciting Macaron! "))
+	assert.Equal(t, "skullpanda-2024", slugify("SKULLPANDA 2024"))
+}
+
+type MockTaxonomyRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaxonomyRepository) InsertCategory(category *entities.Category) (*entities.Category, error) {
+	args := m.Called(category)
+	return args.Get(0).(*entities.Category), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) GetAllCategories() ([]entities.Category, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Category), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) GetCategoryById(id string) (*entities.Category, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Category), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) UpdateCategory(category *entities.Category, id string) (*entities.Category, error) {
+	args := m.Called(category, id)
+	return args.Get(0).(*entities.Category), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) DeleteCategory(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaxonomyRepository) CountChildCategories(id uint) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) InsertSeries(series *entities.Series) (*entities.Series, error) {
+	args := m.Called(series)
+	return args.Get(0).(*entities.Series), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) GetAllSeries() ([]entities.Series, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Series), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) GetSeriesById(id string) (*entities.Series, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Series), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) UpdateSeries(series *entities.Series, id string) (*entities.Series, error) {
+	args := m.Called(series, id)
+	return args.Get(0).(*entities.Series), args.Error(1)
+}
+
+func (m *MockTaxonomyRepository) DeleteSeries(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaxonomyRepository) ReplaceProductCategories(productID uint, categoryIDs []uint) error {
+	args := m.Called(productID, categoryIDs)
+	return args.Error(0)
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"gorm.io/gorm"
+)
+
+type WarehouseUsecase interface {
+	CreateWarehouse(request *entities.WarehouseRequest) (*entities.WarehouseResponse, error)
+	GetAllWarehouses() ([]entities.WarehouseResponse, error)
+	GetWarehouseById(id string) (*entities.WarehouseResponse, error)
+	UpdateWarehouse(request *entities.WarehouseRequest, id string) (*entities.WarehouseResponse, error)
+	GetStockLevels(productID string) (*entities.ProductStockLevels, error)
+	TransferStock(productID string, actorID uint, request *entities.StockTransferRequest) ([]entities.StockMovement, error)
+}
+
+type WarehouseService struct {
+	repo        WarehouseRepository
+	productRepo ProductRepository
+}
+
+func NewWarehouseService(repo WarehouseRepository, productRepo ProductRepository) WarehouseUsecase {
+	return &WarehouseService{repo, productRepo}
+}
+
+// AllocateStock is the fulfillment rule for an order line: ship it whole from
+// the first warehouse that can, otherwise split it across warehouses in order
+// until it is covered. levels must be in priority order; what they cannot
+// cover is left unallocated.
+func AllocateStock(levels []entities.WarehouseStock, quantity int) []entities.StockAllocation {
+	if quantity <= 0 {
+		return nil
+	}
+
+	for _, level := range levels {
+		if level.Stock >= quantity {
+			return []entities.StockAllocation{{WarehouseID: level.WarehouseID, Quantity: quantity}}
+		}
+	}
+
+	allocations := []entities.StockAllocation{}
+	for _, level := range levels {
+		if quantity == 0 {
+			break
+		}
+		if level.Stock <= 0 {
+			continue
+		}
+
+		take := min(level.Stock, quantity)
+		allocations = append(allocations, entities.StockAllocation{WarehouseID: level.WarehouseID, Quantity: take})
+		quantity -= take
+	}
+
+	return allocations
+}
+
+func (s *WarehouseService) CreateWarehouse(request *entities.WarehouseRequest) (*entities.WarehouseResponse, error) {
+	if err := s.checkCode(request.Code, 0); err != nil {
+		return nil, err
+	}
+
+	warehouse := &entities.Warehouse{
+		Code:     request.Code,
+		Name:     request.Name,
+		Address:  request.Address,
+		Priority: request.Priority,
+		Active:   request.Active == nil || *request.Active,
+	}
+
+	newWarehouse, err := s.repo.InsertWarehouse(warehouse)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newWarehouseResponse(newWarehouse)
+	return &response, nil
+}
+
+func (s *WarehouseService) GetAllWarehouses() ([]entities.WarehouseResponse, error) {
+	warehouses, err := s.repo.GetAllWarehouses()
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	warehouseList := []entities.WarehouseResponse{}
+	for _, warehouse := range warehouses {
+		warehouseList = append(warehouseList, newWarehouseResponse(&warehouse))
+	}
+
+	return warehouseList, nil
+}
+
+func (s *WarehouseService) GetWarehouseById(id string) (*entities.WarehouseResponse, error) {
+	warehouse, err := s.getWarehouse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newWarehouseResponse(warehouse)
+	return &response, nil
+}
+
+func (s *WarehouseService) UpdateWarehouse(request *entities.WarehouseRequest, id string) (*entities.WarehouseResponse, error) {
+	warehouse, err := s.getWarehouse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCode(request.Code, warehouse.ID); err != nil {
+		return nil, err
+	}
+
+	warehouse.Code = request.Code
+	warehouse.Name = request.Name
+	warehouse.Address = request.Address
+	warehouse.Priority = request.Priority
+	if request.Active != nil {
+		warehouse.Active = *request.Active
+	}
+
+	warehouseUpdated, err := s.repo.UpdateWarehouse(warehouse)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	response := newWarehouseResponse(warehouseUpdated)
+	return &response, nil
+}
+
+func (s *WarehouseService) GetStockLevels(productID string) (*entities.ProductStockLevels, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := s.repo.GetStockLevels(product.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	return &entities.ProductStockLevels{
+		ProductID: product.ID,
+		Available: product.Stock,
+		Locations: levels,
+	}, nil
+}
+
+func (s *WarehouseService) TransferStock(productID string, actorID uint, request *entities.StockTransferRequest) ([]entities.StockMovement, error) {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.Type == entities.ProductTypeBlindBox || product.Type == entities.ProductTypeBlindBoxCase {
+		return nil, errors.New("blind box stock is set by its figures")
+	}
+
+	movements, err := s.repo.TransferStock(&entities.StockTransfer{
+		ProductID:       product.ID,
+		VariantID:       optionalID(request.VariantID),
+		FromWarehouseID: request.FromWarehouseID,
+		ToWarehouseID:   request.ToWarehouseID,
+		Quantity:        request.Quantity,
+		ActorID:         &actorID,
+		ReferenceID:     request.ReferenceID,
+		Note:            request.Note,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "product not found", "variant not found", "variant required", "warehouse not found", "insufficient stock":
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return movements, nil
+}
+
+// checkCode makes sure no warehouse other than the one with id uses code.
+func (s *WarehouseService) checkCode(code string, id uint) error {
+	existing, err := s.repo.GetWarehouseByCode(code)
+	if err != nil {
+		if err.Error() == "warehouse not found" {
+			return nil
+		}
+		return errors.New("database error")
+	}
+
+	if existing.ID != id {
+		return errors.New("warehouse code already exists")
+	}
+
+	return nil
+}
+
+func (s *WarehouseService) getWarehouse(id string) (*entities.Warehouse, error) {
+	warehouse, err := s.repo.GetWarehouseById(id)
+	if err != nil {
+		if err.Error() == "warehouse not found" {
+			return nil, err
+		}
+		return nil, errors.New("database error")
+	}
+
+	return warehouse, nil
+}
+
+func (s *WarehouseService) getProduct(productID string) (*entities.Product, error) {
+	product, err := s.productRepo.GetProductById(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("product not found")
+		}
+		return nil, errors.New("database error")
+	}
+
+	return product, nil
+}
+
+func newWarehouseResponse(warehouse *entities.Warehouse) entities.WarehouseResponse {
+	return entities.WarehouseResponse{
+		ID:       warehouse.ID,
+		Code:     warehouse.Code,
+		Name:     warehouse.Name,
+		Address:  warehouse.Address,
+		Priority: warehouse.Priority,
+		Active:   warehouse.Active,
+	}
+}
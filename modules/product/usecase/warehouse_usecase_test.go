@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/phetployst/art-toys-store/modules/product/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestAllocateStock(t *testing.T) {
+	levels := []entities.WarehouseStock{
+		{WarehouseID: 1, Stock: 3},
+		{WarehouseID: 2, Stock: 10},
+		{WarehouseID: 3, Stock: 2},
+	}
+
+	tests := []struct {
+		name     string
+		levels   []entities.WarehouseStock
+		quantity int
+		want     []entities.StockAllocation
+	}{
+		{"first warehouse ships the whole line", levels, 3, []entities.StockAllocation{{WarehouseID: 1, Quantity: 3}}},
+		{"skip to the first warehouse that can ship it whole", levels, 5, []entities.StockAllocation{{WarehouseID: 2, Quantity: 5}}},
+		{"split when no warehouse can ship it whole", levels, 14, []entities.StockAllocation{{WarehouseID: 1, Quantity: 3}, {WarehouseID: 2, Quantity: 10}, {WarehouseID: 3, Quantity: 1}}},
+		{"leave what the warehouses cannot cover", levels, 20, []entities.StockAllocation{{WarehouseID: 1, Quantity: 3}, {WarehouseID: 2, Quantity: 10}, {WarehouseID: 3, Quantity: 2}}},
+		{"no warehouse stock", nil, 2, []entities.StockAllocation{}},
+		{"nothing to allocate", levels, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AllocateStock(tt.levels, tt.quantity))
+		})
+	}
+}
+
+func TestCreateWarehouse(t *testing.T) {
+	t.Run("create warehouse active by default", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		warehouseService := WarehouseService{repo: mockRepo}
+
+		warehouse := &entities.Warehouse{Code: "BKK", Name: "Bangkok warehouse", Priority: 1, Active: true}
+
+		mockRepo.On("GetWarehouseByCode", "BKK").Return((*entities.Warehouse)(nil), errors.New("warehouse not found"))
+		mockRepo.On("InsertWarehouse", warehouse).Return(&entities.Warehouse{Model: gorm.Model{ID: 1}, Code: "BKK", Name: "Bangkok warehouse", Priority: 1, Active: true}, nil)
+
+		got, err := warehouseService.CreateWarehouse(&entities.WarehouseRequest{Code: "BKK", Name: "Bangkok warehouse", Priority: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.WarehouseResponse{ID: 1, Code: "BKK", Name: "Bangkok warehouse", Priority: 1, Active: true}, got)
+	})
+
+	t.Run("create warehouse given duplicate code", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		warehouseService := WarehouseService{repo: mockRepo}
+
+		mockRepo.On("GetWarehouseByCode", "BKK").Return(&entities.Warehouse{Model: gorm.Model{ID: 1}, Code: "BKK"}, nil)
+
+		_, err := warehouseService.CreateWarehouse(&entities.WarehouseRequest{Code: "BKK", Name: "Bangkok 2"})
+
+		assert.EqualError(t, err, "warehouse code already exists")
+		mockRepo.AssertNotCalled(t, "InsertWarehouse", mock.Anything)
+	})
+}
+
+func TestUpdateWarehouse(t *testing.T) {
+	t.Run("deactivate warehouse keeping its own code", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		warehouseService := WarehouseService{repo: mockRepo}
+
+		active := false
+		existing := &entities.Warehouse{Model: gorm.Model{ID: 2}, Code: "POP-SIAM", Name: "Siam pop-up", Priority: 2, Active: true}
+
+		mockRepo.On("GetWarehouseById", "2").Return(existing, nil)
+		mockRepo.On("GetWarehouseByCode", "POP-SIAM").Return(existing, nil)
+		mockRepo.On("UpdateWarehouse", mock.MatchedBy(func(w *entities.Warehouse) bool {
+			return w.ID == 2 && !w.Active
+		})).Return(existing, nil)
+
+		got, err := warehouseService.UpdateWarehouse(&entities.WarehouseRequest{Code: "POP-SIAM", Name: "Siam pop-up", Priority: 2, Active: &active}, "2")
+
+		assert.NoError(t, err)
+		assert.False(t, got.Active)
+	})
+}
+
+func TestGetStockLevels(t *testing.T) {
+	t.Run("get stock levels with aggregate availability", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		mockProductRepo := new(MockProductRepository)
+		warehouseService := WarehouseService{repo: mockRepo, productRepo: mockProductRepo}
+
+		levels := []entities.WarehouseStockLevel{{WarehouseID: 1, Code: "BKK", Name: "Bangkok warehouse", Stock: 12}}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Stock: 10}, nil)
+		mockRepo.On("GetStockLevels", uint(1)).Return(levels, nil)
+
+		got, err := warehouseService.GetStockLevels("1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.ProductStockLevels{ProductID: 1, Available: 10, Locations: levels}, got)
+	})
+}
+
+func TestTransferStock(t *testing.T) {
+	t.Run("transfer stock successfully", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		mockProductRepo := new(MockProductRepository)
+		warehouseService := WarehouseService{repo: mockRepo, productRepo: mockProductRepo}
+
+		actorID := uint(7)
+		transfer := &entities.StockTransfer{ProductID: 1, FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4, ActorID: &actorID, ReferenceID: "TR-1"}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("TransferStock", transfer).Return([]entities.StockMovement{{ID: 50}, {ID: 51}}, nil)
+
+		got, err := warehouseService.TransferStock("1", 7, &entities.StockTransferRequest{FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4, ReferenceID: "TR-1"})
+
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("transfer given blind box", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		mockProductRepo := new(MockProductRepository)
+		warehouseService := WarehouseService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "20").Return(&entities.Product{Model: gorm.Model{ID: 20}, Type: "blind_box"}, nil)
+
+		_, err := warehouseService.TransferStock("20", 7, &entities.StockTransferRequest{FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4})
+
+		assert.EqualError(t, err, "blind box stock is set by its figures")
+		mockRepo.AssertNotCalled(t, "TransferStock", mock.Anything)
+	})
+
+	t.Run("transfer given insufficient stock", func(t *testing.T) {
+		mockRepo := new(MockWarehouseRepository)
+		mockProductRepo := new(MockProductRepository)
+		warehouseService := WarehouseService{repo: mockRepo, productRepo: mockProductRepo}
+
+		mockProductRepo.On("GetProductById", "1").Return(&entities.Product{Model: gorm.Model{ID: 1}, Type: "standard"}, nil)
+		mockRepo.On("TransferStock", mock.Anything).Return(([]entities.StockMovement)(nil), errors.New("insufficient stock"))
+
+		_, err := warehouseService.TransferStock("1", 7, &entities.StockTransferRequest{FromWarehouseID: 2, ToWarehouseID: 1, Quantity: 40})
+
+		assert.EqualError(t, err, "insufficient stock")
+	})
+}
+
+type MockWarehouseRepository struct {
+	mock.Mock
+}
+
+func (m *MockWarehouseRepository) InsertWarehouse(warehouse *entities.Warehouse) (*entities.Warehouse, error) {
+	args := m.Called(warehouse)
+	return args.Get(0).(*entities.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) GetAllWarehouses() ([]entities.Warehouse, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) GetWarehouseById(id string) (*entities.Warehouse, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) GetWarehouseByCode(code string) (*entities.Warehouse, error) {
+	args := m.Called(code)
+	return args.Get(0).(*entities.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) UpdateWarehouse(warehouse *entities.Warehouse) (*entities.Warehouse, error) {
+	args := m.Called(warehouse)
+	return args.Get(0).(*entities.Warehouse), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) GetStockLevels(productID uint) ([]entities.WarehouseStockLevel, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]entities.WarehouseStockLevel), args.Error(1)
+}
+
+func (m *MockWarehouseRepository) TransferStock(transfer *entities.StockTransfer) ([]entities.StockMovement, error) {
+	args := m.Called(transfer)
+	return args.Get(0).([]entities.StockMovement), args.Error(1)
+}
@@ -135,3 +135,73 @@ func (h *httpUserHandler) Refresh(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, userCredential)
 }
+
+// RequestEmailVerification mails the signed-in user a link to verify their
+// email with.
+func (h *httpUserHandler) RequestEmailVerification(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return echo.NewHTTPError(http.StatusUnauthorized, ErrorResponse{
+			Message: "Invalid user ID in token",
+		})
+	}
+
+	if err := h.usecase.RequestEmailVerification(userID, h.config); err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: "User not found",
+			})
+		case "email already verified":
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Message: "Email already verified",
+			})
+		default:
+			log.Printf("unexpected error: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "Internal server error",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{
+		"message": "Verification email sent",
+	})
+}
+
+func (h *httpUserHandler) VerifyEmail(c echo.Context) error {
+	request := new(entities.VerifyEmail)
+
+	validator := validator.New()
+	c.Echo().Validator = &CustomValidator{validator: validator}
+
+	if err := c.Bind(&request); err != nil {
+		log.Printf("failed to bind input: %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request data",
+		})
+	}
+
+	if err := c.Validate(request); err != nil {
+		log.Printf("failed to validate input %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "request data validation failed"})
+	}
+
+	if err := h.usecase.VerifyEmail(request, h.config); err != nil {
+		switch err.Error() {
+		case "invalid token":
+			return c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Message: "invalid token",
+			})
+		default:
+			log.Printf("unexpected error: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "Internal server error",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Email verified",
+	})
+}
@@ -433,6 +433,130 @@ func TestRefreshHandler_auth(t *testing.T) {
 	})
 }
 
+func TestRequestEmailVerificationHandler_auth(t *testing.T) {
+	t.Run("request email verification successfully", func(t *testing.T) {
+		mockService := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("RequestEmailVerification", uint(11), (*config.Config)(nil)).Return(nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(11))
+
+		err := handler.RequestEmailVerification(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, response.Code)
+		assert.JSONEq(t, `{"message":"Verification email sent"}`, response.Body.String())
+	})
+
+	t.Run("request email verification given email already verified", func(t *testing.T) {
+		mockService := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("RequestEmailVerification", uint(11), (*config.Config)(nil)).Return(errors.New("email already verified"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+		c.Set(ContextUserIDKey, uint(11))
+
+		err := handler.RequestEmailVerification(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, response.Code)
+		assert.JSONEq(t, `{"message":"Email already verified"}`, response.Body.String())
+	})
+
+	t.Run("request email verification with missing user ID in token", func(t *testing.T) {
+		mockService := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.RequestEmailVerification(c)
+
+		httpError, _ := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusUnauthorized, httpError.Code)
+	})
+}
+
+func TestVerifyEmailHandler_auth(t *testing.T) {
+	t.Run("verify email successfully", func(t *testing.T) {
+		mockService := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("VerifyEmail", &entities.VerifyEmail{Token: "verify_token"}, (*config.Config)(nil)).Return(nil)
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"token": "verify_token"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.VerifyEmail(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"message":"Email verified"}`, response.Body.String())
+	})
+
+	t.Run("verify email given invalid token", func(t *testing.T) {
+		mockService := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockService.On("VerifyEmail", &entities.VerifyEmail{Token: "verify_token"}, (*config.Config)(nil)).Return(errors.New("invalid token"))
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"token": "verify_token"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.VerifyEmail(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.JSONEq(t, `{"message":"invalid token"}`, response.Body.String())
+	})
+
+	t.Run("verify email given missing token", func(t *testing.T) {
+		mockService := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockService}
+
+		e := echo.New()
+		defer e.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+		c := e.NewContext(request, response)
+
+		err := handler.VerifyEmail(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		mockService.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything)
+	})
+}
+
 type MockUserUsecase struct {
 	mock.Mock
 }
@@ -471,3 +595,23 @@ func (m *MockUserUsecase) GetAllUserProfile() (int64, []entities.UserProfileResp
 	args := m.Called()
 	return args.Get(0).(int64), args.Get(1).([]entities.UserProfileResponse), args.Error(2)
 }
+
+func (m *MockUserUsecase) UploadProfilePicture(userID uint, data []byte) (*entities.ProfilePictureResponse, error) {
+	args := m.Called(userID, data)
+	return args.Get(0).(*entities.ProfilePictureResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) GetAccountStatus(userID uint) (*entities.AccountStatus, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*entities.AccountStatus), args.Error(1)
+}
+
+func (m *MockUserUsecase) RequestEmailVerification(userID uint, config *config.Config) error {
+	args := m.Called(userID, config)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) VerifyEmail(request *entities.VerifyEmail, config *config.Config) error {
+	args := m.Called(request, config)
+	return args.Error(0)
+}
@@ -3,6 +3,7 @@ package adapters
 import (
 	"errors"
 	"log"
+	"time"
 
 	"github.com/phetployst/art-toys-store/modules/user/entities"
 	"github.com/phetployst/art-toys-store/modules/user/usecase"
@@ -151,3 +152,35 @@ func (r *gormUserRepository) InsertUserProfile(userProfile *entities.UserProfile
 	log.Printf("user profile inserted: %+v", userProfile)
 	return nil
 }
+
+func (r *gormUserRepository) UpdateProfilePicture(userID uint, url, key string) error {
+	result := r.db.Model(&entities.UserProfile{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"profile_picture_url": url, "profile_picture_key": key})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SetEmailVerified records when the user verified email, provided it is still
+// the account's email.
+func (r *gormUserRepository) SetEmailVerified(userID uint, email string, verifiedAt time.Time) error {
+	result := r.db.Model(&entities.User{}).
+		Where("id = ? AND email = ?", userID, email).
+		Update("email_verified_at", verifiedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
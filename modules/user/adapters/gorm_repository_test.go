@@ -14,7 +14,7 @@ import (
 )
 
 const (
-	createUserQuery                = `INSERT INTO "users" ("created_at","updated_at","deleted_at","username","email","password_hash","role") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`
+	createUserQuery                = `INSERT INTO "users" ("created_at","updated_at","deleted_at","username","email","password_hash","role","email_verified_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`
 	isUniqueUserQuery              = `SELECT * FROM "users" WHERE (email = $1 OR username = $2) AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT $3`
 	getUserAccountByIdQuery        = `SELECT * FROM "users" WHERE "users"."id" = $1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT $2`
 	getUserAccountByUsernameQuery  = `SELECT * FROM "users" WHERE username = $1 AND "users"."deleted_at" IS NULL ORDER BY "users"."id" LIMIT $2`
@@ -25,7 +25,9 @@ const (
 	getUserProfileByIDQuery        = `SELECT * FROM "user_profiles" WHERE (user_id = $1 AND deleted_at IS NULL) AND "user_profiles"."deleted_at" IS NULL ORDER BY "user_profiles"."id" LIMIT $2`
 	updateUserProfileQuery         = `UPDATE "user_profiles" SET "updated_at"=$1,"user_id"=$2,"username"=$3,"first_name"=$4,"last_name"=$5,"email"=$6,"street"=$7,"city"=$8,"state"=$9,"postal_code"=$10,"country"=$11,"profile_picture_url"=$12 WHERE user_id = $13 AND "user_profiles"."deleted_at" IS NULL`
 	getAllUserProfileQuery         = `SELECT * FROM "user_profiles" WHERE "user_profiles"."deleted_at" IS NULL`
-	insertUserProfileQuery         = `INSERT INTO "user_profiles" ("created_at","updated_at","deleted_at","user_id","username","first_name","last_name","email","street","city","state","postal_code","country","profile_picture_url") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14) RETURNING "id"`
+	setEmailVerifiedQuery          = `UPDATE "users" SET "email_verified_at"=$1,"updated_at"=$2 WHERE (id = $3 AND email = $4) AND "users"."deleted_at" IS NULL`
+	updateProfilePictureQuery      = `UPDATE "user_profiles" SET "profile_picture_key"=$1,"profile_picture_url"=$2,"updated_at"=$3 WHERE user_id = $4 AND "user_profiles"."deleted_at" IS NULL`
+	insertUserProfileQuery         = `INSERT INTO "user_profiles" ("created_at","updated_at","deleted_at","user_id","username","first_name","last_name","email","street","city","state","postal_code","country","profile_picture_url","profile_picture_key") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`
 )
 
 func TestCreateUser_gormRepo(t *testing.T) {
@@ -42,7 +44,7 @@ func TestCreateUser_gormRepo(t *testing.T) {
 		mock.ExpectBegin()
 		row := sqlmock.NewRows([]string{"id"}).AddRow(1)
 		mock.ExpectQuery(createUserQuery).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), user.Username, user.Email, user.PasswordHash, user.Role).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), user.Username, user.Email, user.PasswordHash, user.Role, nil).
 			WillReturnRows(row)
 		mock.ExpectCommit()
 
@@ -67,7 +69,7 @@ func TestCreateUser_gormRepo(t *testing.T) {
 
 		mock.ExpectBegin()
 		mock.ExpectQuery(createUserQuery).
-			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), user.Username, user.Email, user.PasswordHash, user.Role).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), user.Username, user.Email, user.PasswordHash, user.Role, nil).
 			WillReturnError(errors.New("database error"))
 		mock.ExpectRollback()
 
@@ -708,7 +710,7 @@ func TestInsertUserProfile_gormRepo(t *testing.T) {
 		row := sqlmock.NewRows([]string{"id"}).AddRow(1)
 		mock.ExpectQuery(insertUserProfileQuery).
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 31, "phetploy", "Phet", "Ploy", "phetploy@example.com",
-				"123 Green Lane", "Bangkok", "Central", "10110", "Thailand", "https://example.com/profiles/31.jpg").
+				"123 Green Lane", "Bangkok", "Central", "10110", "Thailand", "https://example.com/profiles/31.jpg", "").
 			WillReturnRows(row)
 		mock.ExpectCommit()
 
@@ -739,3 +741,99 @@ func TestInsertUserProfile_gormRepo(t *testing.T) {
 	})
 
 }
+
+func TestUpdateProfilePicture_gormRepo(t *testing.T) {
+	t.Run("update profile picture successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewUserRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateProfilePictureQuery).
+			WithArgs("profiles/31/100", "https://cdn.example.com/profiles/31/512.jpg", sqlmock.AnyArg(), 31).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateProfilePicture(31, "https://cdn.example.com/profiles/31/512.jpg", "profiles/31/100")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("update profile picture given profile not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewUserRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateProfilePictureQuery).
+			WithArgs("profiles/31/100", "https://cdn.example.com/profiles/31/512.jpg", sqlmock.AnyArg(), 31).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.UpdateProfilePicture(31, "https://cdn.example.com/profiles/31/512.jpg", "profiles/31/100")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("update profile picture given database error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewUserRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateProfilePictureQuery).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		err := repo.UpdateProfilePicture(31, "https://cdn.example.com/profiles/31/512.jpg", "profiles/31/100")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSetEmailVerified_gormRepo(t *testing.T) {
+	verifiedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("set email verified successfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewUserRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(setEmailVerifiedQuery).
+			WithArgs(verifiedAt, sqlmock.AnyArg(), 31, "phetploy@example.com").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.SetEmailVerified(31, "phetploy@example.com", verifiedAt)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("set email verified given email changed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		gormDB, _ := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+		repo := NewUserRepository(gormDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(setEmailVerifiedQuery).
+			WithArgs(verifiedAt, sqlmock.AnyArg(), 31, "old@example.com").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.SetEmailVerified(31, "old@example.com", verifiedAt)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
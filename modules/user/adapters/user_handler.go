@@ -1,6 +1,7 @@
 package adapters
 
 import (
+	"io"
 	"log"
 	"net/http"
 
@@ -103,3 +104,56 @@ func (h *httpUserHandler) GetAllUserProfile(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, response)
 }
+
+func (h *httpUserHandler) UploadProfilePicture(c echo.Context) error {
+	userID, ok := c.Get(ContextUserIDKey).(uint)
+	if !ok || userID == 0 {
+		return echo.NewHTTPError(http.StatusUnauthorized, ErrorResponse{
+			Message: "Invalid user ID in token",
+		})
+	}
+
+	file, err := c.FormFile("picture")
+	if err != nil {
+		log.Printf("failed to read form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "picture file is required"})
+	}
+
+	if file.Size > usecase.MaxProfilePictureSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: "image too large"})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Printf("failed to open form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "picture file is required"})
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, usecase.MaxProfilePictureSize+1))
+	if err != nil {
+		log.Printf("failed to read form file %v", err)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Message: "picture file is required"})
+	}
+
+	picture, err := h.usecase.UploadProfilePicture(userID, data)
+	if err != nil {
+		switch err.Error() {
+		case "image too large", "image dimensions too large":
+			return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Message: err.Error()})
+		case "unsupported image type":
+			return c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Message: err.Error()})
+		case "empty image", "invalid image":
+			return c.JSON(http.StatusBadRequest, ErrorResponse{Message: err.Error()})
+		case "user profile not found":
+			return c.JSON(http.StatusNotFound, ErrorResponse{Message: "user profile not found"})
+		default:
+			log.Printf("unexpected error: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Message: "Internal server error",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, picture)
+}
@@ -1,7 +1,9 @@
 package adapters
 
 import (
+	"bytes"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -355,3 +357,99 @@ func TestGetAllUserProfile_user(t *testing.T) {
 		assert.JSONEq(t, `{"message":"no user profiles found"}`, response.Body.String())
 	})
 }
+
+func TestUploadProfilePicture_user(t *testing.T) {
+	newUploadRequest := func(t *testing.T, field string, data []byte) *http.Request {
+		t.Helper()
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile(field, "avatar.png")
+		assert.NoError(t, err)
+		part.Write(data)
+		writer.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/profile/picture", &body)
+		request.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		return request
+	}
+
+	t.Run("upload profile picture successfully", func(t *testing.T) {
+		mockUsecase := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockUsecase}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockUsecase.On("UploadProfilePicture", uint(14), []byte("image-bytes")).Return(&entities.ProfilePictureResponse{
+			ProfilePictureURL: "/uploads/profiles/14/512.jpg",
+			Thumbnails:        []entities.ProfileThumbnail{{Size: 512, URL: "/uploads/profiles/14/512.jpg"}},
+		}, nil)
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newUploadRequest(t, "picture", []byte("image-bytes")), response)
+		c.Set(ContextUserIDKey, uint(14))
+
+		err := handler.UploadProfilePicture(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"profile_picture_url":"/uploads/profiles/14/512.jpg","thumbnails":[{"size":512,"url":"/uploads/profiles/14/512.jpg"}]}`, response.Body.String())
+	})
+
+	t.Run("upload profile picture given missing file", func(t *testing.T) {
+		mockUsecase := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockUsecase}
+
+		e := echo.New()
+		defer e.Close()
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newUploadRequest(t, "avatar", []byte("image-bytes")), response)
+		c.Set(ContextUserIDKey, uint(14))
+
+		err := handler.UploadProfilePicture(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.JSONEq(t, `{"message":"picture file is required"}`, response.Body.String())
+	})
+
+	t.Run("upload profile picture given unsupported type", func(t *testing.T) {
+		mockUsecase := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockUsecase}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockUsecase.On("UploadProfilePicture", uint(14), []byte("not-an-image")).Return((*entities.ProfilePictureResponse)(nil), errors.New("unsupported image type"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newUploadRequest(t, "picture", []byte("not-an-image")), response)
+		c.Set(ContextUserIDKey, uint(14))
+
+		err := handler.UploadProfilePicture(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+	})
+
+	t.Run("upload profile picture given internal server error", func(t *testing.T) {
+		mockUsecase := new(MockUserUsecase)
+		handler := &httpUserHandler{usecase: mockUsecase}
+
+		e := echo.New()
+		defer e.Close()
+
+		mockUsecase.On("UploadProfilePicture", uint(14), []byte("image-bytes")).Return((*entities.ProfilePictureResponse)(nil), errors.New("internal server error"))
+
+		response := httptest.NewRecorder()
+		c := e.NewContext(newUploadRequest(t, "picture", []byte("image-bytes")), response)
+		c.Set(ContextUserIDKey, uint(14))
+
+		err := handler.UploadProfilePicture(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+}
@@ -1,6 +1,10 @@
 package entities
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type (
 	UserAccount struct {
@@ -9,6 +13,16 @@ type (
 		Email    string `json:"email"`
 	}
 
+	// AccountStatus is what other services may know about an account when
+	// deciding whether to trust it. ShippingAddress is the profile address on
+	// one line, empty until the user has a profile.
+	AccountStatus struct {
+		UserID          uint      `json:"user_id"`
+		CreatedAt       time.Time `json:"created_at"`
+		EmailVerified   bool      `json:"email_verified"`
+		ShippingAddress string    `json:"shipping_address"`
+	}
+
 	Login struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
@@ -26,11 +40,18 @@ type (
 		RefreshToken string `json:"refresh_token"`
 	}
 
+	// VerifyEmail carries the token from an email verification message.
+	VerifyEmail struct {
+		Token string `json:"token" validate:"required"`
+	}
+
 	JwtCustomClaims struct {
 		UserID   uint   `json:"user_id"`
 		Username string `json:"username"`
 		Role     string `json:"role"`
 		Type     string `json:"type"`
+		// Email is the address an email verification token was sent to.
+		Email string `json:"email,omitempty"`
 		jwt.RegisteredClaims
 	}
 
@@ -43,4 +64,14 @@ type (
 		Address           Address `gorm:"embedded" json:"address" validate:"required"`
 		ProfilePictureURL string  `gorm:"type:text" json:"profile_picture_url,omitempty"`
 	}
+
+	ProfilePictureResponse struct {
+		ProfilePictureURL string             `json:"profile_picture_url"`
+		Thumbnails        []ProfileThumbnail `json:"thumbnails"`
+	}
+
+	ProfileThumbnail struct {
+		Size int    `json:"size"`
+		URL  string `json:"url"`
+	}
 )
@@ -9,11 +9,13 @@ import (
 type (
 	User struct {
 		gorm.Model
-		Username     string     `gorm:"unique;not null" json:"username" validate:"required"`
-		Email        string     `gorm:"unique;not null" json:"email" validate:"required,email"`
-		PasswordHash string     `json:"password" validate:"required,min=8"`
-		Role         string     `gorm:"default:'user'" json:"role" validate:"required,oneof=user admin"`
-		Credentials  Credential `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"credentials"`
+		Username     string `gorm:"unique;not null" json:"username" validate:"required"`
+		Email        string `gorm:"unique;not null" json:"email" validate:"required,email"`
+		PasswordHash string `json:"password" validate:"required,min=8"`
+		Role         string `gorm:"default:'user'" json:"role" validate:"required,oneof=user admin"`
+		// EmailVerifiedAt is set once the user has confirmed they own Email.
+		EmailVerifiedAt *time.Time `json:"-"`
+		Credentials     Credential `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"credentials"`
 	}
 
 	Credential struct {
@@ -32,6 +34,9 @@ type (
 		Email             string  `gorm:"type:varchar(100);unique;not null" json:"email" validate:"required,email"`
 		Address           Address `gorm:"embedded" json:"address" validate:"required"`
 		ProfilePictureURL string  `gorm:"type:text" json:"profile_picture_url,omitempty"`
+		// ProfilePictureKey is the storage prefix the picture's thumbnails
+		// were written under, so a replaced picture can be removed.
+		ProfilePictureKey string `gorm:"type:text" json:"-"`
 	}
 
 	Address struct {
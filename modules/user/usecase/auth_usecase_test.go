@@ -397,6 +397,124 @@ func TestRefresh_auth(t *testing.T) {
 	})
 }
 
+func TestRequestEmailVerificationUsecase_auth(t *testing.T) {
+	config := &config.Config{Jwt: config.Jwt{AccessTokenSecret: "accessSecret", RefreshTokenSecret: "refreshSecret"}}
+
+	t.Run("request email verification successfully", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockUtil := new(MockUserUtilsService)
+		mockMailer := new(MockMailer)
+		userService := userService{repo: mockRepo, utils: mockUtil, mailer: mockMailer}
+
+		mockRepo.On("GetUserAccountById", uint(1)).Return(&entities.User{Model: gorm.Model{ID: 1}, Email: "phetploy@example.com"}, nil)
+		mockUtil.On("GenerateEmailVerificationToken", uint(1), "phetploy@example.com", config).Return("verify_token", nil)
+		mockMailer.On("SendEmailVerification", "phetploy@example.com", "verify_token").Return(nil)
+
+		err := userService.RequestEmailVerification(1, config)
+
+		assert.NoError(t, err)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("request email verification given email already verified", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := userService{repo: mockRepo}
+
+		verifiedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		mockRepo.On("GetUserAccountById", uint(1)).Return(&entities.User{Model: gorm.Model{ID: 1}, Email: "phetploy@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+		err := userService.RequestEmailVerification(1, config)
+
+		assert.EqualError(t, err, "email already verified")
+	})
+
+	t.Run("request email verification given user not found", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := userService{repo: mockRepo}
+
+		mockRepo.On("GetUserAccountById", uint(1)).Return((*entities.User)(nil), gorm.ErrRecordNotFound)
+
+		err := userService.RequestEmailVerification(1, config)
+
+		assert.EqualError(t, err, "user not found")
+	})
+
+	t.Run("request email verification given mail error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockUtil := new(MockUserUtilsService)
+		mockMailer := new(MockMailer)
+		userService := userService{repo: mockRepo, utils: mockUtil, mailer: mockMailer}
+
+		mockRepo.On("GetUserAccountById", uint(1)).Return(&entities.User{Model: gorm.Model{ID: 1}, Email: "phetploy@example.com"}, nil)
+		mockUtil.On("GenerateEmailVerificationToken", uint(1), "phetploy@example.com", config).Return("verify_token", nil)
+		mockMailer.On("SendEmailVerification", "phetploy@example.com", "verify_token").Return(errors.New("smtp down"))
+
+		err := userService.RequestEmailVerification(1, config)
+
+		assert.EqualError(t, err, "internal server error")
+	})
+}
+
+func TestVerifyEmailUsecase_auth(t *testing.T) {
+	config := &config.Config{Jwt: config.Jwt{AccessTokenSecret: "accessSecret", RefreshTokenSecret: "refreshSecret"}}
+	request := &entities.VerifyEmail{Token: "verify_token"}
+
+	t.Run("verify email successfully", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockUtil := new(MockUserUtilsService)
+		userService := userService{repo: mockRepo, utils: mockUtil}
+
+		mockUtil.On("ParseAndValidateToken", "verify_token", "accessSecret", "verify_email").Return(&entities.JwtCustomClaims{UserID: 1, Email: "phetploy@example.com", Type: "verify_email"}, nil)
+		mockRepo.On("GetUserAccountById", uint(1)).Return(&entities.User{Model: gorm.Model{ID: 1}, Email: "phetploy@example.com"}, nil)
+		mockRepo.On("SetEmailVerified", uint(1), "phetploy@example.com", mock.AnythingOfType("time.Time")).Return(nil)
+
+		err := userService.VerifyEmail(request, config)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("verify email given token for an old address", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockUtil := new(MockUserUtilsService)
+		userService := userService{repo: mockRepo, utils: mockUtil}
+
+		mockUtil.On("ParseAndValidateToken", "verify_token", "accessSecret", "verify_email").Return(&entities.JwtCustomClaims{UserID: 1, Email: "old@example.com", Type: "verify_email"}, nil)
+		mockRepo.On("GetUserAccountById", uint(1)).Return(&entities.User{Model: gorm.Model{ID: 1}, Email: "phetploy@example.com"}, nil)
+
+		err := userService.VerifyEmail(request, config)
+
+		assert.EqualError(t, err, "invalid token")
+		mockRepo.AssertNotCalled(t, "SetEmailVerified", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify email given invalid token", func(t *testing.T) {
+		mockUtil := new(MockUserUtilsService)
+		userService := userService{utils: mockUtil}
+
+		mockUtil.On("ParseAndValidateToken", "verify_token", "accessSecret", "verify_email").Return((*entities.JwtCustomClaims)(nil), errors.New("token is expired"))
+
+		err := userService.VerifyEmail(request, config)
+
+		assert.EqualError(t, err, "invalid token")
+	})
+
+	t.Run("verify email given email already verified", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockUtil := new(MockUserUtilsService)
+		userService := userService{repo: mockRepo, utils: mockUtil}
+
+		verifiedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		mockUtil.On("ParseAndValidateToken", "verify_token", "accessSecret", "verify_email").Return(&entities.JwtCustomClaims{UserID: 1, Email: "phetploy@example.com", Type: "verify_email"}, nil)
+		mockRepo.On("GetUserAccountById", uint(1)).Return(&entities.User{Model: gorm.Model{ID: 1}, Email: "phetploy@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+		err := userService.VerifyEmail(request, config)
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "SetEmailVerified", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 type MockUserRepository struct {
 	mock.Mock
 }
@@ -466,6 +584,16 @@ func (m *MockUserRepository) InsertUserProfile(userProfile *entities.UserProfile
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateProfilePicture(userID uint, url, key string) error {
+	args := m.Called(userID, url, key)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetEmailVerified(userID uint, email string, verifiedAt time.Time) error {
+	args := m.Called(userID, email, verifiedAt)
+	return args.Error(0)
+}
+
 type MockUserUtilsService struct {
 	mock.Mock
 }
@@ -507,3 +635,17 @@ func (m *MockUserUtilsService) ParseAndValidateToken(tokenString, secret, expect
 	args := m.Called(tokenString, secret, expectedType)
 	return args.Get(0).(*entities.JwtCustomClaims), args.Error(1)
 }
+
+func (m *MockUserUtilsService) GenerateEmailVerificationToken(userID uint, email string, config *config.Config) (string, error) {
+	args := m.Called(userID, email, config)
+	return args.String(0), args.Error(1)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) SendEmailVerification(email, token string) error {
+	args := m.Called(email, token)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"time"
+
 	"github.com/phetployst/art-toys-store/modules/user/entities"
 )
 
@@ -17,4 +19,6 @@ type UserRepository interface {
 	UpdateUserProfile(userProfile *entities.UserProfile) (*entities.UserProfile, error)
 	GetAllUserProfile() (int64, []entities.UserProfile, error)
 	InsertUserProfile(profile *entities.UserProfile) error
+	UpdateProfilePicture(userID uint, url, key string) error
+	SetEmailVerified(userID uint, email string, verifiedAt time.Time) error
 }
@@ -2,12 +2,23 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/phetployst/art-toys-store/config"
 	"github.com/phetployst/art-toys-store/modules/user/entities"
+	"github.com/phetployst/art-toys-store/pkg/imaging"
+	"github.com/phetployst/art-toys-store/pkg/storage"
 	"gorm.io/gorm"
 )
 
+const MaxProfilePictureSize = 5 << 20
+
+// ProfilePictureSizes lists the square thumbnail edges generated for every
+// upload. The largest one becomes the profile picture URL.
+var ProfilePictureSizes = []int{64, 128, 256, 512}
+
 type UserUsecase interface {
 	CreateNewUser(user *entities.User) (*entities.UserAccount, error)
 	Login(loginRequest *entities.Login, config *config.Config) (*entities.UserCredential, error)
@@ -16,15 +27,28 @@ type UserUsecase interface {
 	GetUserProfile(userID uint) (*entities.UserProfileResponse, error)
 	UpdateUserProfile(userProfile *entities.UserProfile) (*entities.UserProfileResponse, error)
 	GetAllUserProfile() (int64, []entities.UserProfileResponse, error)
+	UploadProfilePicture(userID uint, data []byte) (*entities.ProfilePictureResponse, error)
+	GetAccountStatus(userID uint) (*entities.AccountStatus, error)
+	RequestEmailVerification(userID uint, config *config.Config) error
+	VerifyEmail(request *entities.VerifyEmail, config *config.Config) error
+}
+
+// Mailer sends the emails the user service reaches users with.
+type Mailer interface {
+	// SendEmailVerification sends email the token that proves it reached
+	// its owner.
+	SendEmailVerification(email, token string) error
 }
 
 type userService struct {
-	repo  UserRepository
-	utils UserUtilsService
+	repo    UserRepository
+	utils   UserUtilsService
+	storage storage.Storage
+	mailer  Mailer
 }
 
-func NewUserService(repo UserRepository, utils UserUtilsService) UserUsecase {
-	return &userService{repo, utils}
+func NewUserService(repo UserRepository, utils UserUtilsService, storage storage.Storage, mailer Mailer) UserUsecase {
+	return &userService{repo, utils, storage, mailer}
 }
 
 func (s *userService) GetUserProfile(userID uint) (*entities.UserProfileResponse, error) {
@@ -48,6 +72,35 @@ func (s *userService) GetUserProfile(userID uint) (*entities.UserProfileResponse
 	}, nil
 }
 
+func (s *userService) GetAccountStatus(userID uint) (*entities.AccountStatus, error) {
+	user, err := s.repo.GetUserAccountById(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, errors.New("internal server error")
+	}
+
+	status := &entities.AccountStatus{
+		UserID:        user.ID,
+		CreatedAt:     user.CreatedAt,
+		EmailVerified: user.EmailVerifiedAt != nil,
+	}
+
+	profile, err := s.repo.GetUserProfileByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status, nil
+		}
+		return nil, errors.New("internal server error")
+	}
+
+	address := profile.Address
+	status.ShippingAddress = strings.Join([]string{address.Street, address.City, address.State, address.PostalCode, address.Country}, ", ")
+
+	return status, nil
+}
+
 func (s *userService) UpdateUserProfile(userProfile *entities.UserProfile) (*entities.UserProfileResponse, error) {
 
 	if !s.repo.IsUniqueUser(userProfile.Email, userProfile.Username) {
@@ -95,3 +148,64 @@ func (s *userService) GetAllUserProfile() (int64, []entities.UserProfileResponse
 
 	return count, userProfileResponses, nil
 }
+
+// UploadProfilePicture stores the thumbnails of a new profile picture and
+// removes the ones it replaces. Thumbnails that never made it onto the
+// profile are removed again.
+func (s *userService) UploadProfilePicture(userID uint, data []byte) (*entities.ProfilePictureResponse, error) {
+	img, err := imaging.Decode(data, MaxProfilePictureSize)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.repo.GetUserProfileByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user profile not found")
+		}
+		return nil, errors.New("internal server error")
+	}
+
+	prefix := fmt.Sprintf("profiles/%d/%d", userID, time.Now().UnixNano())
+	response := &entities.ProfilePictureResponse{}
+
+	for _, size := range ProfilePictureSizes {
+		thumbnail, err := imaging.EncodeJPEG(imaging.SquareThumbnail(img, size))
+		if err != nil {
+			s.deleteProfilePicture(prefix)
+			return nil, errors.New("internal server error")
+		}
+
+		url, err := s.storage.Put(fmt.Sprintf("%s/%d.jpg", prefix, size), "image/jpeg", thumbnail)
+		if err != nil {
+			s.deleteProfilePicture(prefix)
+			return nil, errors.New("internal server error")
+		}
+
+		response.Thumbnails = append(response.Thumbnails, entities.ProfileThumbnail{Size: size, URL: url})
+		response.ProfilePictureURL = url
+	}
+
+	if err := s.repo.UpdateProfilePicture(userID, response.ProfilePictureURL, prefix); err != nil {
+		s.deleteProfilePicture(prefix)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user profile not found")
+		}
+		return nil, errors.New("internal server error")
+	}
+
+	if profile.ProfilePictureKey != "" {
+		s.deleteProfilePicture(profile.ProfilePictureKey)
+	}
+
+	return response, nil
+}
+
+// deleteProfilePicture removes every thumbnail stored under prefix. Missing
+// files are not an error, and a file that can't be removed is only wasted
+// space, so failures are ignored.
+func (s *userService) deleteProfilePicture(prefix string) {
+	for _, size := range ProfilePictureSizes {
+		_ = s.storage.Delete(fmt.Sprintf("%s/%d.jpg", prefix, size))
+	}
+}
@@ -1,9 +1,16 @@
 package usecase
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/phetployst/art-toys-store/modules/user/entities"
 	"github.com/stretchr/testify/assert"
@@ -87,6 +94,59 @@ func TestGetUserProfile_user(t *testing.T) {
 	})
 }
 
+func TestGetAccountStatus_user(t *testing.T) {
+	t.Run("reports a verified account", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := userService{repo: mockRepo}
+
+		createdAt := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+		verifiedAt := createdAt.Add(time.Hour)
+		mockRepo.On("GetUserAccountById", uint(31)).Return(&entities.User{Model: gorm.Model{ID: 31, CreatedAt: createdAt}, EmailVerifiedAt: &verifiedAt}, nil)
+		mockRepo.On("GetUserProfileByID", uint(31)).Return(&entities.UserProfile{UserID: 31, Address: entities.Address{
+			Street:     "123 Green Lane",
+			City:       "Bangkok",
+			State:      "Central",
+			PostalCode: "10110",
+			Country:    "Thailand",
+		}}, nil)
+
+		got, err := service.GetAccountStatus(31)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.AccountStatus{
+			UserID:          31,
+			CreatedAt:       createdAt,
+			EmailVerified:   true,
+			ShippingAddress: "123 Green Lane, Bangkok, Central, 10110, Thailand",
+		}, got)
+	})
+
+	t.Run("reports an account without a profile", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := userService{repo: mockRepo}
+
+		createdAt := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+		mockRepo.On("GetUserAccountById", uint(31)).Return(&entities.User{Model: gorm.Model{ID: 31, CreatedAt: createdAt}}, nil)
+		mockRepo.On("GetUserProfileByID", uint(31)).Return((*entities.UserProfile)(nil), gorm.ErrRecordNotFound)
+
+		got, err := service.GetAccountStatus(31)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.AccountStatus{UserID: 31, CreatedAt: createdAt}, got)
+	})
+
+	t.Run("returns error when user is not found", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		service := userService{repo: mockRepo}
+
+		mockRepo.On("GetUserAccountById", uint(223)).Return((*entities.User)(nil), gorm.ErrRecordNotFound)
+
+		_, err := service.GetAccountStatus(223)
+
+		assert.EqualError(t, err, "user not found")
+	})
+}
+
 func TestUpdateUserProfile_user(t *testing.T) {
 	t.Run("successfully update user profile", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
@@ -234,3 +294,137 @@ func TestGetAllUserProfile_user(t *testing.T) {
 	})
 
 }
+
+func TestUploadProfilePicture_user(t *testing.T) {
+	t.Run("upload profile picture successfully", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockStorage := new(MockStorage)
+		service := userService{repo: mockRepo, storage: mockStorage}
+
+		mockRepo.On("GetUserProfileByID", uint(31)).Return(&entities.UserProfile{UserID: 31, ProfilePictureKey: "profiles/31/100"}, nil)
+		mockStorage.On("Put", mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("https://cdn.example.com/profile.jpg", nil)
+		mockRepo.On("UpdateProfilePicture", uint(31), "https://cdn.example.com/profile.jpg", mock.AnythingOfType("string")).Return(nil)
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+
+		got, err := service.UploadProfilePicture(31, pngImage(t, 40, 20))
+
+		assert.NoError(t, err)
+		for _, size := range ProfilePictureSizes {
+			mockStorage.AssertCalled(t, "Delete", fmt.Sprintf("profiles/31/100/%d.jpg", size))
+		}
+		mockStorage.AssertNumberOfCalls(t, "Delete", len(ProfilePictureSizes))
+		assert.Equal(t, "https://cdn.example.com/profile.jpg", got.ProfilePictureURL)
+		assert.Len(t, got.Thumbnails, len(ProfilePictureSizes))
+		mockStorage.AssertNumberOfCalls(t, "Put", len(ProfilePictureSizes))
+
+		for _, call := range mockStorage.Calls {
+			if call.Method != "Put" {
+				continue
+			}
+			key := call.Arguments.String(0)
+			assert.True(t, strings.HasPrefix(key, "profiles/31/"), "unexpected key %s", key)
+
+			img, _, err := image.Decode(bytes.NewReader(call.Arguments.Get(2).([]byte)))
+			assert.NoError(t, err)
+			assert.Equal(t, img.Bounds().Dx(), img.Bounds().Dy())
+		}
+	})
+
+	t.Run("upload profile picture given unsupported type", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockStorage := new(MockStorage)
+		service := userService{repo: mockRepo, storage: mockStorage}
+
+		_, err := service.UploadProfilePicture(31, []byte("%PDF-1.4 not an image"))
+
+		assert.EqualError(t, err, "unsupported image type")
+		mockStorage.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("upload profile picture given image too large", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockStorage := new(MockStorage)
+		service := userService{repo: mockRepo, storage: mockStorage}
+
+		_, err := service.UploadProfilePicture(31, make([]byte, MaxProfilePictureSize+1))
+
+		assert.EqualError(t, err, "image too large")
+	})
+
+	t.Run("upload profile picture given storage error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockStorage := new(MockStorage)
+		service := userService{repo: mockRepo, storage: mockStorage}
+
+		mockRepo.On("GetUserProfileByID", uint(31)).Return(&entities.UserProfile{UserID: 31}, nil)
+		mockStorage.On("Put", mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("", errors.New("disk full"))
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := service.UploadProfilePicture(31, pngImage(t, 20, 20))
+
+		assert.EqualError(t, err, "internal server error")
+		mockRepo.AssertNotCalled(t, "UpdateProfilePicture", mock.Anything, mock.Anything, mock.Anything)
+		mockStorage.AssertNumberOfCalls(t, "Delete", len(ProfilePictureSizes))
+	})
+
+	t.Run("upload profile picture given profile not found", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockStorage := new(MockStorage)
+		service := userService{repo: mockRepo, storage: mockStorage}
+
+		mockRepo.On("GetUserProfileByID", uint(31)).Return((*entities.UserProfile)(nil), gorm.ErrRecordNotFound)
+
+		_, err := service.UploadProfilePicture(31, pngImage(t, 20, 20))
+
+		assert.EqualError(t, err, "user profile not found")
+		mockStorage.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("upload profile picture given database error on update", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockStorage := new(MockStorage)
+		service := userService{repo: mockRepo, storage: mockStorage}
+
+		mockRepo.On("GetUserProfileByID", uint(31)).Return(&entities.UserProfile{UserID: 31, ProfilePictureKey: "profiles/31/100"}, nil)
+		mockStorage.On("Put", mock.AnythingOfType("string"), "image/jpeg", mock.Anything).Return("https://cdn.example.com/profile.jpg", nil)
+		mockRepo.On("UpdateProfilePicture", uint(31), "https://cdn.example.com/profile.jpg", mock.AnythingOfType("string")).Return(errors.New("connection reset"))
+		mockStorage.On("Delete", mock.AnythingOfType("string")).Return(nil)
+
+		_, err := service.UploadProfilePicture(31, pngImage(t, 20, 20))
+
+		assert.EqualError(t, err, "internal server error")
+		mockStorage.AssertNumberOfCalls(t, "Delete", len(ProfilePictureSizes))
+		mockStorage.AssertNotCalled(t, "Delete", "profiles/31/100/64.jpg")
+	})
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 5), G: uint8(y * 5), B: 120, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+type MockStorage struct {
+	mock.Mock
+}
+
+func (m *MockStorage) Put(key string, contentType string, data []byte) (string, error) {
+	args := m.Called(key, contentType, data)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorage) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
@@ -17,6 +17,7 @@ type UserUtilsService interface {
 	GenerateRefreshToken(userID uint, username, role string, config *config.Config) (string, time.Time, error)
 	SaveUserCredentials(userID uint, refreshToken string, expiresAt time.Time) error
 	ParseAndValidateToken(tokenString, secret, expectedType string) (*entities.JwtCustomClaims, error)
+	GenerateEmailVerificationToken(userID uint, email string, config *config.Config) (string, error)
 }
 
 type userUtils struct {
@@ -100,6 +101,28 @@ func (h *userUtils) GenerateRefreshToken(userID uint, username, role string, con
 	return refreshTokenString, refreshTokenClaims.ExpiresAt.Time, nil
 }
 
+// GenerateEmailVerificationToken signs a token proving that whoever holds it
+// received mail sent to email. It names the address so that it stops working
+// once the account's email changes.
+func (h *userUtils) GenerateEmailVerificationToken(userID uint, email string, config *config.Config) (string, error) {
+	claims := &entities.JwtCustomClaims{
+		UserID: userID,
+		Email:  email,
+		Type:   "verify_email",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(config.Jwt.AccessTokenSecret))
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
 func (h *userUtils) SaveUserCredentials(userID uint, refreshToken string, expiresAt time.Time) error {
 	credential := &entities.Credential{
 		UserID:       userID,
@@ -150,6 +150,27 @@ func TestGenerateRefreshToken_utils(t *testing.T) {
 
 }
 
+func TestGenerateEmailVerificationToken_utils(t *testing.T) {
+	t.Run("generate email verification token successfully", func(t *testing.T) {
+		config := &config.Config{
+			Jwt: config.Jwt{
+				AccessTokenSecret: "secret",
+			},
+		}
+
+		userUtils := &userUtils{}
+
+		token, err := userUtils.GenerateEmailVerificationToken(1, "phetploy@example.com", config)
+		assert.NoError(t, err)
+
+		claims, err := userUtils.ParseAndValidateToken(token, "secret", "verify_email")
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), claims.UserID)
+		assert.Equal(t, "phetploy@example.com", claims.Email)
+	})
+}
+
 func TestSaveUserCredentials_utils(t *testing.T) {
 	t.Run("save user credentials successfully", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
@@ -0,0 +1,184 @@
+// Package draw implements a provably fair weighted draw.
+//
+// The server commits to a secret seed by publishing its SHA-256 hash before
+// any draw is made. Each draw combines that seed with a client supplied seed
+// and an increasing nonce through HMAC-SHA256, so once the seed is revealed
+// anyone can recompute the roll and check which entry it selects.
+package draw
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Entry is a drawable item and its relative weight.
+type Entry struct {
+	ID     uint
+	Weight int
+}
+
+// NewSeed returns a fresh random server seed encoded as hex.
+func NewSeed() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// HashSeed returns the commitment published for a server seed.
+func HashSeed(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Roll derives a number in [0, total) from the seeds and nonce. Values that
+// would bias the modulo are skipped by re-hashing with a round counter.
+func Roll(serverSeed, clientSeed string, nonce uint64, total uint64) uint64 {
+	if total == 0 {
+		return 0
+	}
+
+	limit := ^uint64(0) - (^uint64(0) % total)
+	for round := 0; ; round++ {
+		mac := hmac.New(sha256.New, []byte(serverSeed))
+		mac.Write([]byte(clientSeed + ":" + strconv.FormatUint(nonce, 10) + ":" + strconv.Itoa(round)))
+		value := binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+		if value < limit {
+			return value % total
+		}
+	}
+}
+
+// Pick maps a roll onto the entries by cumulative weight. Entries must be in a
+// stable order (e.g. by ID) for the result to be reproducible.
+func Pick(entries []Entry, roll uint64) (Entry, error) {
+	var cumulative uint64
+	for _, entry := range entries {
+		if entry.Weight <= 0 {
+			continue
+		}
+
+		cumulative += uint64(entry.Weight)
+		if roll < cumulative {
+			return entry, nil
+		}
+	}
+
+	return Entry{}, errors.New("roll out of range")
+}
+
+// TotalWeight sums the positive weights of the entries.
+func TotalWeight(entries []Entry) uint64 {
+	var total uint64
+	for _, entry := range entries {
+		if entry.Weight > 0 {
+			total += uint64(entry.Weight)
+		}
+	}
+
+	return total
+}
+
+// Draw rolls and picks an entry in one step.
+func Draw(entries []Entry, serverSeed, clientSeed string, nonce uint64) (Entry, uint64, error) {
+	total := TotalWeight(entries)
+	if total == 0 {
+		return Entry{}, 0, errors.New("nothing to draw")
+	}
+
+	roll := Roll(serverSeed, clientSeed, nonce, total)
+	entry, err := Pick(entries, roll)
+	if err != nil {
+		return Entry{}, 0, err
+	}
+
+	return entry, roll, nil
+}
+
+// Verify checks a revealed seed against its commitment and reports the entry
+// the draw must have produced.
+func Verify(entries []Entry, serverSeed, seedHash, clientSeed string, nonce uint64) (Entry, error) {
+	if HashSeed(serverSeed) != seedHash {
+		return Entry{}, errors.New("seed does not match commitment")
+	}
+
+	entry, _, err := Draw(entries, serverSeed, clientSeed, nonce)
+	return entry, err
+}
+
+// Assort fills a case of size slots with different regular entries, each
+// chosen by weight from those not yet picked. When secrets are given, one more
+// roll decides whether a secret replaces a slot, with the secrets' share of
+// the combined weight as the chance. Every roll uses the next nonce from
+// nonce on; the number of nonces used is returned alongside the picks.
+func Assort(regulars, secrets []Entry, size int, serverSeed, clientSeed string, nonce uint64) ([]Entry, uint64, error) {
+	remaining := make([]Entry, 0, len(regulars))
+	for _, entry := range regulars {
+		if entry.Weight > 0 {
+			remaining = append(remaining, entry)
+		}
+	}
+
+	if size <= 0 || len(remaining) < size {
+		return nil, 0, errors.New("not enough entries")
+	}
+
+	var used uint64
+	picks := make([]Entry, 0, size)
+	for len(picks) < size {
+		entry, _, err := Draw(remaining, serverSeed, clientSeed, nonce+used)
+		if err != nil {
+			return nil, 0, err
+		}
+		used++
+
+		picks = append(picks, entry)
+		for i := range remaining {
+			if remaining[i].ID == entry.ID {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+
+	secretWeight := TotalWeight(secrets)
+	if secretWeight == 0 {
+		return picks, used, nil
+	}
+
+	chance := Roll(serverSeed, clientSeed, nonce+used, TotalWeight(regulars)+secretWeight)
+	used++
+	if chance >= secretWeight {
+		return picks, used, nil
+	}
+
+	secret, _, err := Draw(secrets, serverSeed, clientSeed, nonce+used)
+	if err != nil {
+		return nil, 0, err
+	}
+	used++
+
+	slot := Roll(serverSeed, clientSeed, nonce+used, uint64(size))
+	used++
+	picks[slot] = secret
+
+	return picks, used, nil
+}
+
+// Shuffle orders n items at random with a Fisher–Yates shuffle, calling swap
+// for each exchange. Walking down from the last position, each one is filled
+// by a roll over the items not yet placed, using the next nonce from nonce on,
+// so every ordering is equally likely and the shuffle takes n-1 rolls.
+func Shuffle(n int, serverSeed, clientSeed string, nonce uint64, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := Roll(serverSeed, clientSeed, nonce+uint64(n-1-i), uint64(i+1))
+		swap(i, int(j))
+	}
+}
@@ -0,0 +1,159 @@
+package draw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoll(t *testing.T) {
+	t.Run("same inputs give the same roll", func(t *testing.T) {
+		first := Roll("server-seed", "client-seed", 7, 100)
+		second := Roll("server-seed", "client-seed", 7, 100)
+
+		assert.Equal(t, first, second)
+		assert.Less(t, first, uint64(100))
+	})
+
+	t.Run("nonce changes the roll", func(t *testing.T) {
+		rolls := map[uint64]bool{}
+		for nonce := uint64(0); nonce < 20; nonce++ {
+			rolls[Roll("server-seed", "client-seed", nonce, 1<<32)] = true
+		}
+
+		assert.Greater(t, len(rolls), 1)
+	})
+}
+
+func TestPick(t *testing.T) {
+	entries := []Entry{{ID: 1, Weight: 60}, {ID: 2, Weight: 0}, {ID: 3, Weight: 39}, {ID: 4, Weight: 1}}
+
+	tests := []struct {
+		roll uint64
+		want uint
+	}{
+		{0, 1},
+		{59, 1},
+		{60, 3},
+		{98, 3},
+		{99, 4},
+	}
+
+	for _, tt := range tests {
+		got, err := Pick(entries, tt.roll)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got.ID)
+	}
+
+	_, err := Pick(entries, 100)
+	assert.EqualError(t, err, "roll out of range")
+}
+
+func TestDraw(t *testing.T) {
+	t.Run("draw follows configured weights", func(t *testing.T) {
+		entries := []Entry{{ID: 1, Weight: 90}, {ID: 2, Weight: 10}}
+
+		counts := map[uint]int{}
+		for nonce := uint64(0); nonce < 5000; nonce++ {
+			entry, _, err := Draw(entries, "server-seed", "client-seed", nonce)
+			assert.NoError(t, err)
+			counts[entry.ID]++
+		}
+
+		assert.InDelta(t, 4500, counts[1], 150)
+		assert.InDelta(t, 500, counts[2], 150)
+	})
+
+	t.Run("draw given no weight", func(t *testing.T) {
+		_, _, err := Draw([]Entry{{ID: 1}}, "server-seed", "client-seed", 0)
+
+		assert.EqualError(t, err, "nothing to draw")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	seed, err := NewSeed()
+	assert.NoError(t, err)
+	assert.Len(t, seed, 64)
+
+	entries := []Entry{{ID: 1, Weight: 5}, {ID: 2, Weight: 5}}
+	drawn, _, err := Draw(entries, seed, "order-42", 3)
+	assert.NoError(t, err)
+
+	t.Run("verify revealed seed", func(t *testing.T) {
+		got, err := Verify(entries, seed, HashSeed(seed), "order-42", 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, drawn, got)
+	})
+
+	t.Run("verify given tampered seed", func(t *testing.T) {
+		_, err := Verify(entries, "other-seed", HashSeed(seed), "order-42", 3)
+
+		assert.EqualError(t, err, "seed does not match commitment")
+	})
+}
+
+func TestAssort(t *testing.T) {
+	regulars := []Entry{{ID: 1, Weight: 10}, {ID: 2, Weight: 10}, {ID: 3, Weight: 10}, {ID: 4, Weight: 10}}
+
+	t.Run("full case holds one of each regular", func(t *testing.T) {
+		for nonce := uint64(0); nonce < 50; nonce++ {
+			picks, used, err := Assort(regulars, nil, 4, "server-seed", "client-seed", nonce)
+
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(4), used)
+			assert.ElementsMatch(t, regulars, picks)
+		}
+	})
+
+	t.Run("secret replaces a single slot", func(t *testing.T) {
+		secrets := []Entry{{ID: 9, Weight: 40}}
+
+		replaced := 0
+		for nonce := uint64(0); nonce < 200; nonce++ {
+			picks, _, err := Assort(regulars, secrets, 4, "server-seed", "client-seed", nonce)
+			assert.NoError(t, err)
+
+			seen := map[uint]bool{}
+			for _, pick := range picks {
+				assert.False(t, seen[pick.ID], "duplicate figure in case")
+				seen[pick.ID] = true
+			}
+			if seen[9] {
+				replaced++
+			}
+		}
+
+		assert.InDelta(t, 100, replaced, 30)
+	})
+
+	t.Run("assort given too few regulars", func(t *testing.T) {
+		_, _, err := Assort(regulars[:2], nil, 3, "server-seed", "client-seed", 0)
+
+		assert.EqualError(t, err, "not enough entries")
+	})
+}
+
+func TestShuffle(t *testing.T) {
+	shuffle := func(serverSeed string) []int {
+		items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		Shuffle(len(items), serverSeed, "client-seed", 0, func(i, j int) {
+			items[i], items[j] = items[j], items[i]
+		})
+		return items
+	}
+
+	t.Run("same seeds give the same order", func(t *testing.T) {
+		assert.Equal(t, shuffle("server-seed"), shuffle("server-seed"))
+	})
+
+	t.Run("keeps every item", func(t *testing.T) {
+		assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, shuffle("server-seed"))
+	})
+
+	t.Run("another seed gives another order", func(t *testing.T) {
+		assert.NotEqual(t, shuffle("server-seed"), shuffle("other-seed"))
+	})
+}
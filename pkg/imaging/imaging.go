@@ -0,0 +1,142 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	_ "image/gif"
+)
+
+const (
+	MaxDimension = 8000
+	JPEGQuality  = 85
+)
+
+var allowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Decode validates the upload and decodes it into an image. Re-encoding the
+// decoded pixels is what strips EXIF and any other embedded metadata.
+func Decode(data []byte, maxBytes int) (image.Image, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty image")
+	}
+
+	if len(data) > maxBytes {
+		return nil, errors.New("image too large")
+	}
+
+	if !allowedContentTypes[http.DetectContentType(data)] {
+		return nil, errors.New("unsupported image type")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, errors.New("image dimensions too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid image")
+	}
+
+	return img, nil
+}
+
+// SquareThumbnail center-crops img to a square and scales it to size x size.
+func SquareThumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	return scale(img, image.Rect(x0, y0, x0+side, y0+side), size, size)
+}
+
+// Fit scales img down so that its longest edge is at most maxEdge, keeping the
+// aspect ratio. Images that already fit are returned unscaled.
+func Fit(img image.Image, maxEdge int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxEdge && height <= maxEdge {
+		return scale(img, bounds, width, height)
+	}
+
+	if width >= height {
+		height = max(1, height*maxEdge/width)
+		width = maxEdge
+	} else {
+		width = max(1, width*maxEdge/height)
+		height = maxEdge
+	}
+
+	return scale(img, bounds, width, height)
+}
+
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// scale resamples the src rectangle of img into a width x height RGBA image
+// using box filtering, which averages every source pixel covered by a
+// destination pixel.
+func scale(img image.Image, src image.Rectangle, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := src.Dx(), src.Dy()
+
+	for y := 0; y < height; y++ {
+		sy0 := src.Min.Y + y*srcHeight/height
+		sy1 := max(sy0+1, src.Min.Y+(y+1)*srcHeight/height)
+
+		for x := 0; x < width; x++ {
+			sx0 := src.Min.X + x*srcWidth/width
+			sx1 := max(sx0+1, src.Min.X+(x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,105 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodedImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 10, B: 10, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("decode jpeg successfully", func(t *testing.T) {
+		img, err := Decode(encodedImage(t, 30, 20), 1<<20)
+
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 30, 20), img.Bounds())
+	})
+
+	t.Run("decode given empty data", func(t *testing.T) {
+		_, err := Decode(nil, 1<<20)
+
+		assert.EqualError(t, err, "empty image")
+	})
+
+	t.Run("decode given data over the limit", func(t *testing.T) {
+		_, err := Decode(encodedImage(t, 30, 20), 10)
+
+		assert.EqualError(t, err, "image too large")
+	})
+
+	t.Run("decode given unsupported type", func(t *testing.T) {
+		_, err := Decode([]byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"), 1<<20)
+
+		assert.EqualError(t, err, "unsupported image type")
+	})
+
+	t.Run("decode given corrupted image", func(t *testing.T) {
+		data := encodedImage(t, 30, 20)
+
+		_, err := Decode(data[:20], 1<<20)
+
+		assert.EqualError(t, err, "invalid image")
+	})
+}
+
+func TestSquareThumbnail(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+
+	thumbnail := SquareThumbnail(img, 64)
+
+	assert.Equal(t, image.Rect(0, 0, 64, 64), thumbnail.Bounds())
+}
+
+func TestFit(t *testing.T) {
+	t.Run("scale down landscape image", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+		assert.Equal(t, image.Rect(0, 0, 100, 50), Fit(img, 100).Bounds())
+	})
+
+	t.Run("scale down portrait image", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 200, 400))
+
+		assert.Equal(t, image.Rect(0, 0, 50, 100), Fit(img, 100).Bounds())
+	})
+
+	t.Run("keep small image size", func(t *testing.T) {
+		img := image.NewRGBA(image.Rect(0, 0, 20, 10))
+
+		assert.Equal(t, image.Rect(0, 0, 20, 10), Fit(img, 100).Bounds())
+	})
+}
+
+func TestEncodeJPEGStripsMetadata(t *testing.T) {
+	data := encodedImage(t, 8, 8)
+	exif := append([]byte{0xFF, 0xE1, 0x00, 0x10}, []byte("Exif\x00\x00GPSDATA!!")...)
+	withExif := append(append(append([]byte{}, data[:2]...), exif...), data[2:]...)
+
+	img, err := Decode(withExif, 1<<20)
+	assert.NoError(t, err)
+
+	encoded, err := EncodeJPEG(img)
+
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(encoded, []byte("GPSDATA")))
+}
@@ -0,0 +1,200 @@
+// Package money represents amounts of money exactly, as an integer count of a
+// currency's minor unit (satang, cents, ...) together with its ISO 4217 code.
+//
+// Amounts are never held as floats. Every operation that can produce a
+// fraction of a minor unit goes through Scale, Convert or Allocate, so the
+// rounding rule lives in this package alone: half away from zero.
+package money
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency the store prices in. Prices that leave the
+// currency out are taken to be in it.
+const DefaultCurrency = "THB"
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an amount in the minor unit of Currency. It is stored as two
+// columns when embedded in a gorm model and encoded as
+// {"amount": 4999, "currency": "THB"} in JSON.
+type Money struct {
+	Amount   int64  `json:"amount" validate:"gte=0"`
+	Currency string `gorm:"type:varchar(3)" json:"currency" validate:"omitempty,iso4217"`
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Exponent is the number of decimal places of the currency's minor unit.
+func Exponent(currency string) int {
+	switch currency {
+	case "JPY", "KRW", "VND", "CLP", "ISK":
+		return 0
+	case "BHD", "KWD", "OMR", "JOD", "TND":
+		return 3
+	}
+
+	return 2
+}
+
+// OrCurrency returns m in currency when m names no currency of its own.
+func (m Money) OrCurrency(currency string) Money {
+	if m.Currency == "" {
+		m.Currency = currency
+	}
+
+	return m
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Less reports whether m is below o. Amounts in different currencies are
+// never less than one another.
+func (m Money) Less(o Money) bool {
+	return m.Currency == o.Currency && m.Amount < o.Amount
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m times n, e.g. a unit price times a quantity.
+func (m Money) Mul(n int) Money {
+	return Money{Amount: m.Amount * int64(n), Currency: m.Currency}
+}
+
+// Scale returns m times num/den rounded to the nearest minor unit, halves
+// away from zero. It is how percentages are applied: 15% off is
+// m.Scale(15, 100).
+func (m Money) Scale(num, den int64) Money {
+	return Money{Amount: mulDivRound(m.Amount, num, den), Currency: m.Currency}
+}
+
+// Convert returns m in currency at rate, the units of currency one unit of
+// m's currency buys. It rounds like Scale.
+func (m Money) Convert(currency string, rate Rate) Money {
+	num := int64(rate) * pow10(Exponent(currency))
+	den := RateScale * pow10(Exponent(m.Currency))
+
+	return Money{Amount: mulDivRound(m.Amount, num, den), Currency: currency}
+}
+
+// Allocate splits m into parts proportional to weights. The parts always add
+// up to m: minor units lost to rounding down go, one each, to the parts
+// listed first. A zero total weight splits nothing and returns zero parts.
+func (m Money) Allocate(weights ...int64) []Money {
+	parts := make([]Money, len(weights))
+
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	for i := range parts {
+		parts[i].Currency = m.Currency
+	}
+
+	if total == 0 {
+		return parts
+	}
+
+	remainder := m.Amount
+	for i, weight := range weights {
+		parts[i].Amount = m.Amount * weight / total
+		remainder -= parts[i].Amount
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if weights[i] == 0 {
+			continue
+		}
+
+		parts[i].Amount += step
+		remainder -= step
+	}
+
+	return parts
+}
+
+// String formats m in major units, e.g. "49.99 THB".
+func (m Money) String() string {
+	exponent := Exponent(m.Currency)
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exponent > 0 {
+		if len(digits) <= exponent {
+			digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+	}
+
+	return strings.TrimSpace(sign + digits + " " + m.Currency)
+}
+
+// mulDivRound returns a*num/den rounded to the nearest integer, halves away
+// from zero. The product is taken in big integers so it cannot overflow.
+func mulDivRound(a, num, den int64) int64 {
+	n := new(big.Int).Mul(big.NewInt(a), big.NewInt(num))
+	d := big.NewInt(den)
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(d) >= 0 {
+		if n.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q.Int64()
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+
+	return p
+}
@@ -0,0 +1,113 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		num, den int64
+		want     int64
+	}{
+		{"exact", 10000, 15, 100, 1500},
+		{"half rounds up", 5, 1, 2, 3},
+		{"below half rounds down", 333, 1, 10, 33},
+		{"negative half rounds away from zero", -5, 1, 2, -3},
+		{"exchange rate", 4999, 2735, 100000, 137},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.amount, "THB").Scale(tt.num, tt.den)
+
+			assert.Equal(t, New(tt.want, "THB"), got)
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	t.Run("split evenly with a remainder", func(t *testing.T) {
+		parts := New(100, "THB").Allocate(1, 1, 1)
+
+		assert.Equal(t, []Money{New(34, "THB"), New(33, "THB"), New(33, "THB")}, parts)
+	})
+
+	t.Run("split by weight", func(t *testing.T) {
+		parts := New(1000, "THB").Allocate(4999, 0, 2500)
+
+		assert.Equal(t, []Money{New(667, "THB"), New(0, "THB"), New(333, "THB")}, parts)
+	})
+
+	t.Run("split with no weight", func(t *testing.T) {
+		parts := New(1000, "THB").Allocate(0, 0)
+
+		assert.Equal(t, []Money{New(0, "THB"), New(0, "THB")}, parts)
+	})
+}
+
+func TestAddSub(t *testing.T) {
+	t.Run("same currency", func(t *testing.T) {
+		sum, err := New(4999, "THB").Add(New(1, "THB"))
+		assert.NoError(t, err)
+		assert.Equal(t, New(5000, "THB"), sum)
+
+		difference, err := sum.Sub(New(2500, "THB"))
+		assert.NoError(t, err)
+		assert.Equal(t, New(2500, "THB"), difference)
+	})
+
+	t.Run("different currencies", func(t *testing.T) {
+		_, err := New(4999, "THB").Add(New(1, "USD"))
+
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+}
+
+func TestLess(t *testing.T) {
+	assert.True(t, New(7500, "THB").Less(New(10000, "THB")))
+	assert.False(t, New(10000, "THB").Less(New(10000, "THB")))
+	assert.False(t, New(7500, "USD").Less(New(10000, "THB")))
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(4999, "THB"), "49.99 THB"},
+		{New(5, "USD"), "0.05 USD"},
+		{New(-150, "THB"), "-1.50 THB"},
+		{New(1200, "JPY"), "1200 JPY"},
+		{New(1500, "KWD"), "1.500 KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.String())
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name  string
+		money Money
+		to    string
+		rate  Rate
+		want  Money
+	}{
+		{"baht to dollars", New(4999, "THB"), "USD", 27350, New(137, "USD")},
+		{"baht to yen", New(4999, "THB"), "JPY", 4215000, New(211, "JPY")},
+		{"baht to dinar", New(100000, "THB"), "KWD", 8400, New(8400, "KWD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.Convert(tt.to, tt.rate))
+		})
+	}
+}
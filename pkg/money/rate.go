@@ -0,0 +1,68 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the precision exchange rates are kept to: six decimal places,
+// so a Rate of 27350 is 0.027350.
+const RateScale = 1_000_000
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exchange rate in millionths. It is stored as an integer and
+// encoded in JSON as a decimal string, e.g. "0.027350", so it survives the
+// round trip exactly.
+type Rate int64
+
+// ParseRate reads a positive decimal with at most six decimal places.
+func ParseRate(s string) (Rate, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if whole == "" && fraction == "" || len(fraction) > 6 || strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return 0, ErrInvalidRate
+	}
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", 6-len(fraction)), 10, 64)
+	if err != nil || units <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	return Rate(units), nil
+}
+
+func (r Rate) String() string {
+	digits := strconv.FormatInt(int64(r), 10)
+	if len(digits) <= 6 {
+		digits = strings.Repeat("0", 7-len(digits)) + digits
+	}
+
+	return digits[:len(digits)-6] + "." + digits[len(digits)-6:]
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON accepts the rate as a string or a bare number. Either way the
+// digits are parsed as written, never through a float.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	rate, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
@@ -0,0 +1,63 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Rate
+		wantErr bool
+	}{
+		{"0.02735", 27350, false},
+		{"4.215", 4215000, false},
+		{"36", 36000000, false},
+		{".5", 500000, false},
+		{"0.0000001", 0, true},
+		{"0", 0, true},
+		{"-1.5", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseRate(tt.text)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRate)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRateJSON(t *testing.T) {
+	t.Run("encode as a decimal string", func(t *testing.T) {
+		data, err := json.Marshal(Rate(27350))
+
+		assert.NoError(t, err)
+		assert.Equal(t, `"0.027350"`, string(data))
+	})
+
+	t.Run("decode a string or a number", func(t *testing.T) {
+		var fromString, fromNumber Rate
+
+		assert.NoError(t, json.Unmarshal([]byte(`"0.02735"`), &fromString))
+		assert.NoError(t, json.Unmarshal([]byte(`0.02735`), &fromNumber))
+		assert.Equal(t, Rate(27350), fromString)
+		assert.Equal(t, Rate(27350), fromNumber)
+	})
+
+	t.Run("decode a negative rate", func(t *testing.T) {
+		var rate Rate
+
+		assert.ErrorIs(t, json.Unmarshal([]byte(`-1`), &rate), ErrInvalidRate)
+	})
+}
@@ -0,0 +1,50 @@
+// Package payment checks payment confirmations sent by the payment provider.
+//
+// The provider signs the raw body of each confirmation with HMAC-SHA256 under
+// a secret shared with the store and sends the hex digest in SignatureHeader.
+// Only confirmations carrying a valid signature may settle anything.
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/phetployst/art-toys-store/pkg/money"
+)
+
+// SignatureHeader carries the signature of a confirmation's body.
+const SignatureHeader = "X-Payment-Signature"
+
+// Confirmation reports that the payment for the order with ReferenceID went
+// through for Amount. PaymentFingerprint identifies the card or account that
+// paid, when the provider reports one.
+type Confirmation struct {
+	ReferenceID        string      `json:"reference_id" validate:"required,max=64"`
+	Amount             money.Money `json:"amount" validate:"required"`
+	PaymentFingerprint string      `json:"payment_fingerprint,omitempty" validate:"max=255"`
+}
+
+// Sign returns the signature of body under secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body under secret. An
+// empty secret verifies nothing.
+func Verify(secret, body []byte, signature string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,33 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"reference_id":"a1b2","amount":{"amount":129000,"currency":"THB"}}`)
+	signature := Sign([]byte("webhook-secret"), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid signature", "webhook-secret", body, signature, true},
+		{"body changed", "webhook-secret", []byte(`{"reference_id":"a1b2","amount":{"amount":1,"currency":"THB"}}`), signature, false},
+		{"another secret", "other-secret", body, signature, false},
+		{"missing signature", "webhook-secret", body, "", false},
+		{"signature not hex", "webhook-secret", body, "not-hex", false},
+		{"no secret configured", "", body, Sign(nil, body), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Verify([]byte(tt.secret), tt.body, tt.signature))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phetployst/art-toys-store/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	t.Run("put and delete file successfully", func(t *testing.T) {
+		dir := t.TempDir()
+		storage := NewLocalStorage(dir, "/uploads/")
+
+		url, err := storage.Put("profiles/14/512.jpg", "image/jpeg", []byte("jpeg"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/uploads/profiles/14/512.jpg", url)
+
+		data, err := os.ReadFile(filepath.Join(dir, "profiles", "14", "512.jpg"))
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", string(data))
+
+		assert.NoError(t, storage.Delete("profiles/14/512.jpg"))
+		_, err = os.Stat(filepath.Join(dir, "profiles", "14", "512.jpg"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("keys cannot escape the base directory", func(t *testing.T) {
+		dir := t.TempDir()
+		storage := NewLocalStorage(filepath.Join(dir, "uploads"), "/uploads")
+
+		_, err := storage.Put("../../secret.txt", "text/plain", []byte("x"))
+
+		assert.NoError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "uploads", "secret.txt"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete missing file is not an error", func(t *testing.T) {
+		storage := NewLocalStorage(t.TempDir(), "/uploads")
+
+		assert.NoError(t, storage.Delete("missing.jpg"))
+	})
+}
+
+func TestS3Storage(t *testing.T) {
+	t.Run("put object with signed request", func(t *testing.T) {
+		var gotPath, gotAuth, gotBody, gotType string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			gotAuth = r.Header.Get("Authorization")
+			gotType = r.Header.Get("Content-Type")
+			body, _ := io.ReadAll(r.Body)
+			gotBody = string(body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		storage, err := NewS3Storage(config.Storage{
+			S3Endpoint: server.URL, S3Region: "ap-southeast-1", S3Bucket: "art-toys",
+			S3AccessKey: "AKID", S3SecretKey: "secret", PublicBaseURL: "https://cdn.example.com",
+		})
+		assert.NoError(t, err)
+		storage.(*s3Storage).now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+		url, err := storage.Put("profiles/14/512 px.jpg", "image/jpeg", []byte("jpeg"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://cdn.example.com/profiles/14/512 px.jpg", url)
+		assert.Equal(t, "/art-toys/profiles/14/512 px.jpg", gotPath)
+		assert.Equal(t, "image/jpeg", gotType)
+		assert.Equal(t, "jpeg", gotBody)
+		assert.True(t, strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/20240102/ap-southeast-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="))
+	})
+
+	t.Run("put object given error response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("AccessDenied"))
+		}))
+		defer server.Close()
+
+		storage, err := NewS3Storage(config.Storage{
+			S3Endpoint: server.URL, S3Bucket: "art-toys", S3AccessKey: "AKID", S3SecretKey: "secret",
+		})
+		assert.NoError(t, err)
+
+		_, err = storage.Put("a.jpg", "image/jpeg", []byte("jpeg"))
+
+		assert.ErrorContains(t, err, "AccessDenied")
+	})
+
+	t.Run("new s3 storage given missing settings", func(t *testing.T) {
+		_, err := New(config.Storage{Driver: "s3"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("new storage given unknown driver", func(t *testing.T) {
+		_, err := New(config.Storage{Driver: "ftp"})
+
+		assert.EqualError(t, err, `unknown storage driver "ftp"`)
+	})
+}
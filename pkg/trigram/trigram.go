@@ -0,0 +1,70 @@
+// Package trigram measures string similarity the way Postgres pg_trgm does,
+// so code that runs without a database can apply the same thresholds.
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// SimilarityThreshold matches pg_trgm.similarity_threshold (the % operator).
+	SimilarityThreshold = 0.3
+	// WordSimilarityThreshold matches pg_trgm.word_similarity_threshold (the <%
+	// operator).
+	WordSimilarityThreshold = 0.6
+)
+
+// Trigrams returns the set of trigrams of text. Like pg_trgm, each word is
+// lower-cased and padded with two spaces in front and one behind.
+func Trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+
+	return set
+}
+
+// Similarity is the share of trigrams two strings have in common, from 0 to 1.
+func Similarity(a, b string) float64 {
+	setA, setB := Trigrams(a), Trigrams(b)
+
+	common := countCommon(setA, setB)
+	union := len(setA) + len(setB) - common
+	if union == 0 {
+		return 0
+	}
+
+	return float64(common) / float64(union)
+}
+
+// WordSimilarity is the share of the query's trigrams found in text. It stays
+// high while the query is a partial or slightly misspelt word of text, which
+// makes it suited to matching input as it is typed.
+func WordSimilarity(query, text string) float64 {
+	setQuery := Trigrams(query)
+	if len(setQuery) == 0 {
+		return 0
+	}
+
+	return float64(countCommon(setQuery, Trigrams(text))) / float64(len(setQuery))
+}
+
+func countCommon(a, b map[string]bool) int {
+	var common int
+	for trigram := range a {
+		if b[trigram] {
+			common++
+		}
+	}
+
+	return common
+}
@@ -0,0 +1,28 @@
+package trigram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrigrams(t *testing.T) {
+	got := Trigrams("Cat")
+
+	assert.Equal(t, map[string]bool{"  c": true, " ca": true, "cat": true, "at ": true}, got)
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("Labubu", "labubu"))
+	assert.InDelta(t, 5.0/9.0, Similarity("labubo", "Labubu"), 0.0001)
+	assert.GreaterOrEqual(t, Similarity("skulpanda", "skullpanda"), SimilarityThreshold)
+	assert.Less(t, Similarity("dimoo", "labubu"), SimilarityThreshold)
+	assert.Equal(t, 0.0, Similarity("", ""))
+}
+
+func TestWordSimilarity(t *testing.T) {
+	assert.InDelta(t, 0.75, WordSimilarity("lab", "Labubu The Monsters"), 0.0001)
+	assert.GreaterOrEqual(t, WordSimilarity("labubo", "Labubu The Monsters"), WordSimilarityThreshold)
+	assert.Less(t, WordSimilarity("hirono", "Labubu The Monsters"), WordSimilarityThreshold)
+	assert.Equal(t, 0.0, WordSimilarity("", "Labubu"))
+}